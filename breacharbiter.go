@@ -1,10 +1,10 @@
 package main
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -14,6 +14,18 @@ import (
 	"github.com/roasbeef/btcutil"
 )
 
+var (
+	// ErrJusticeTxConflict is returned when a breached output has been
+	// spent by a transaction other than one of our justice transactions.
+	ErrJusticeTxConflict = errors.New("breached output spent by foreign " +
+		"transaction")
+
+	// ErrBreachArbiterShuttingDown is returned when the breachArbiter
+	// begins to shut down while it's waiting to claim a breached output.
+	ErrBreachArbiterShuttingDown = errors.New("breach arbiter shutting " +
+		"down")
+)
+
 // breachArbiter is a special subsystem which is responsible for watching and
 // acting on the detection of any attempted uncooperative channel breaches by
 // channel counterparties. This file essentially acts as deterrence code for
@@ -27,6 +39,12 @@ type breachArbiter struct {
 	db         *channeldb.DB
 	notifier   chainntnfs.ChainNotifier
 	htlcSwitch *htlcSwitch
+	sweeper    *sweeper
+
+	// updateChanBackups is called each time a channel has been closed,
+	// signalling that our static channel backups should be updated to
+	// exclude the channel.
+	updateChanBackups func()
 
 	// breachObservers is a map which tracks all the active breach
 	// observers we're currently managing. The key of the map is the
@@ -62,13 +80,16 @@ type breachArbiter struct {
 // newBreachArbiter creates a new instance of a breachArbiter initialized with
 // its dependent objects.
 func newBreachArbiter(wallet *lnwallet.LightningWallet, db *channeldb.DB,
-	notifier chainntnfs.ChainNotifier, h *htlcSwitch) *breachArbiter {
+	notifier chainntnfs.ChainNotifier, h *htlcSwitch, sweeper *sweeper,
+	updateChanBackups func()) *breachArbiter {
 
 	return &breachArbiter{
-		wallet:     wallet,
-		db:         db,
-		notifier:   notifier,
-		htlcSwitch: h,
+		wallet:            wallet,
+		db:                db,
+		notifier:          notifier,
+		htlcSwitch:        h,
+		sweeper:           sweeper,
+		updateChanBackups: updateChanBackups,
 
 		breachObservers:   make(map[wire.OutPoint]chan struct{}),
 		breachedContracts: make(chan *retributionInfo),
@@ -224,7 +245,10 @@ out:
 		case chanPoint := <-b.settledContracts:
 			// A new channel has been closed either unilaterally or
 			// cooperatively, as a result we no longer need a
-			// breachObserver detected to the channel.
+			// breachObserver detected to the channel, nor a
+			// static backup of it.
+			b.updateChanBackups()
+
 			killSignal, ok := b.breachObservers[*chanPoint]
 			if !ok {
 				brarLog.Errorf("Unable to find contract: %v",
@@ -261,8 +285,9 @@ func (b *breachArbiter) exactRetribution(confChan *chainntnfs.ConfirmationEvent,
 
 	// TODO(roasbeef): state needs to be checkpointed here
 
+	var breachHeight uint32
 	select {
-	case _, ok := <-confChan.Confirmed:
+	case breachConf, ok := <-confChan.Confirmed:
 		// If the second value is !ok, then the channel has been closed
 		// signifying a daemon shutdown, so we exit.
 		if !ok {
@@ -271,6 +296,7 @@ func (b *breachArbiter) exactRetribution(confChan *chainntnfs.ConfirmationEvent,
 
 		// Otherwise, if this is a real confirmation notification, then
 		// we fall through to complete our duty.
+		breachHeight = uint32(breachConf.BlockHeight)
 	case <-b.quit:
 		return
 	}
@@ -278,62 +304,189 @@ func (b *breachArbiter) exactRetribution(confChan *chainntnfs.ConfirmationEvent,
 	brarLog.Debugf("Breach transaction %v has been confirmed, sweeping "+
 		"revoked funds", breachInfo.commitHash)
 
-	// With the breach transaction confirmed, we now create the justice tx
-	// which will claim ALL the funds within the channel.
-	justiceTx, err := b.createJusticeTx(breachInfo)
-	if err != nil {
-		brarLog.Errorf("unable to create justice tx: %v", err)
-		return
+	// With the breach transaction confirmed, we hand both outputs to the
+	// sweeper, which will claim ALL the funds within the channel. As the
+	// breaching party may attempt to sweep their output once its CSV delay
+	// expires, we target confirmation within the next block.
+	breachedOutputs := []*breachedOutput{
+		breachInfo.selfOutput, breachInfo.revokedOutput,
 	}
+	resultChans := make([]<-chan *sweepResult, 0, len(breachedOutputs))
+	for _, output := range breachedOutputs {
+		resultChan, err := b.sweeper.sweepInput(
+			output.sweepInput(breachHeight),
+		)
+		if err != nil {
+			brarLog.Errorf("unable to sweep breached output %v: %v",
+				output.outpoint, err)
+			return
+		}
 
-	brarLog.Debugf("Broadcasting justice tx: %v", newLogClosure(func() string {
-		return spew.Sdump(justiceTx)
-	}))
+		resultChans = append(resultChans, resultChan)
+	}
 
-	// Finally, broadcast the transaction, finalizing the channels'
-	// retribution against the cheating counterparty.
-	if err := b.wallet.PublishTransaction(justiceTx); err != nil {
-		brarLog.Errorf("unable to broadcast "+
-			"justice tx: %v", err)
-		return
+	// Once each output has been spent, we collect the set of justice
+	// transactions which swept them.
+	var unclaimedOutputs []*breachedOutput
+	justiceTxids := make(map[chainhash.Hash]struct{})
+	for i, resultChan := range resultChans {
+		select {
+		case result := <-resultChan:
+			output := breachedOutputs[i]
+
+			// If the output can't be swept, such as if it's
+			// unable to pay for its own spend, then it's
+			// abandoned.
+			if result.err != nil {
+				brarLog.Errorf("Unable to sweep breached "+
+					"output %v: %v", output.outpoint,
+					result.err)
+				continue
+			}
+
+			if err := checkJusticeSpend(result); err != nil {
+				brarLog.Errorf("Unable to claim breached output "+
+					"%v: %v (txid=%v)", output.outpoint, err,
+					result.spendingTx.TxHash())
+				unclaimedOutputs = append(unclaimedOutputs, output)
+				continue
+			}
+
+			justiceTxids[result.spendingTx.TxHash()] = struct{}{}
+		case <-b.quit:
+			return
+		}
+	}
+
+	// If any of the outputs were spent by a foreign transaction, then
+	// retribution hasn't yet been served. We keep watching each of them,
+	// as should the foreign spend be re-organized out of the chain, we'll
+	// be able to claim the output once again.
+	for _, output := range unclaimedOutputs {
+		justiceTXID, err := b.reclaimBreachedOutput(output, breachHeight)
+		if err != nil {
+			brarLog.Errorf("Unable to reclaim breached output %v: %v",
+				output.outpoint, err)
+			return
+		}
+
+		justiceTxids[*justiceTXID] = struct{}{}
 	}
 
 	// As a conclusionary step, we register for a notification to be
-	// dispatched once the justice tx is confirmed. After confirmation we
+	// dispatched once each justice tx is confirmed. After confirmation we
 	// notify the caller that initiated the retribution workflow that the
 	// deed has been done.
-	justiceTXID := justiceTx.TxHash()
-	confChan, err = b.notifier.RegisterConfirmationsNtfn(&justiceTXID, 1)
-	if err != nil {
-		brarLog.Errorf("unable to register for conf for txid: %v",
-			justiceTXID)
-		return
-	}
+	for justiceTXID := range justiceTxids {
+		justiceTXID := justiceTXID
+		confChan, err := b.notifier.RegisterConfirmationsNtfn(
+			&justiceTXID, 1)
+		if err != nil {
+			brarLog.Errorf("unable to register for conf for txid: %v",
+				justiceTXID)
+			return
+		}
 
-	select {
-	case _, ok := <-confChan.Confirmed:
-		if !ok {
+		select {
+		case _, ok := <-confChan.Confirmed:
+			if !ok {
+				return
+			}
+		case <-b.quit:
 			return
 		}
+	}
 
-		// TODO(roasbeef): factor in HTLCs
-		revokedFunds := breachInfo.revokedOutput.amt
-		totalFunds := revokedFunds + breachInfo.selfOutput.amt
+	// TODO(roasbeef): factor in HTLCs
+	revokedFunds := breachInfo.revokedOutput.amt
+	totalFunds := revokedFunds + breachInfo.selfOutput.amt
 
-		brarLog.Infof("Justice for ChannelPoint(%v) has "+
-			"been served, %v revoked funds (%v total) "+
-			"have been claimed", breachInfo.chanPoint,
-			revokedFunds, totalFunds)
+	brarLog.Infof("Justice for ChannelPoint(%v) has been served, %v "+
+		"revoked funds (%v total) have been claimed",
+		breachInfo.chanPoint, revokedFunds, totalFunds)
 
-		// TODO(roasbeef): add peer to blacklist?
+	// TODO(roasbeef): add peer to blacklist?
 
-		// TODO(roasbeef): close other active channels with offending peer
+	// TODO(roasbeef): close other active channels with offending peer
 
-		close(breachInfo.doneChan)
+	close(breachInfo.doneChan)
+}
 
-		return
-	case <-b.quit:
-		return
+// checkJusticeSpend returns ErrJusticeTxConflict if the breached output whose
+// spend is described by the passed sweepResult wasn't spent by one of our
+// justice transactions.
+func checkJusticeSpend(result *sweepResult) error {
+	if !result.swept {
+		return ErrJusticeTxConflict
+	}
+
+	return nil
+}
+
+// reclaimBreachedOutput watches a breached output which has been spent by a
+// foreign transaction. If the foreign spend is re-organized out of the chain,
+// then the output is handed to the sweeper once again. This continues until
+// the output has been spent by one of our justice transactions, whose txid is
+// returned.
+func (b *breachArbiter) reclaimBreachedOutput(output *breachedOutput,
+	heightHint uint32) (*chainhash.Hash, error) {
+
+	for {
+		spendEvent, err := b.notifier.RegisterSpendNtfn(
+			&output.outpoint, heightHint,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// We'll first receive the foreign spend, after which we wait
+		// for it to be disconnected from the main chain.
+		select {
+		case _, ok := <-spendEvent.Spend:
+			if !ok {
+				return nil, ErrBreachArbiterShuttingDown
+			}
+		case <-b.quit:
+			spendEvent.Cancel()
+			return nil, ErrBreachArbiterShuttingDown
+		}
+
+		select {
+		case <-spendEvent.Reorg:
+			spendEvent.Cancel()
+		case <-b.quit:
+			spendEvent.Cancel()
+			return nil, ErrBreachArbiterShuttingDown
+		}
+
+		brarLog.Infof("Foreign spend of breached output %v has been "+
+			"re-organized out, sweeping once again", output.outpoint)
+
+		resultChan, err := b.sweeper.sweepInput(
+			output.sweepInput(heightHint),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case result := <-resultChan:
+			if result.err != nil {
+				return nil, result.err
+			}
+
+			if err := checkJusticeSpend(result); err != nil {
+				brarLog.Errorf("Unable to claim breached output "+
+					"%v: %v (txid=%v)", output.outpoint, err,
+					result.spendingTx.TxHash())
+				continue
+			}
+
+			justiceTXID := result.spendingTx.TxHash()
+			return &justiceTXID, nil
+		case <-b.quit:
+			return nil, ErrBreachArbiterShuttingDown
+		}
 	}
 }
 
@@ -377,6 +530,7 @@ func (b *breachArbiter) breachObserver(contract *lnwallet.LightningChannel,
 		if err := contract.DeleteState(); err != nil {
 			brarLog.Errorf("unable to delete channel state: %v", err)
 		}
+		b.updateChanBackups()
 
 		// TODO(roasbeef): need to handle case of remote broadcast
 		// mid-local initiated state-transition, possible false-positive?
@@ -422,12 +576,14 @@ func (b *breachArbiter) breachObserver(contract *lnwallet.LightningChannel,
 				amt:         btcutil.Amount(localSignDesc.Output.Value),
 				outpoint:    breachInfo.LocalOutpoint,
 				witnessFunc: localWitness,
+				witnessSize: lnwallet.P2WKHWitnessSize,
 			},
 
 			revokedOutput: &breachedOutput{
 				amt:         btcutil.Amount(remoteSignDesc.Output.Value),
 				outpoint:    breachInfo.RemoteOutpoint,
 				witnessFunc: remoteWitness,
+				witnessSize: lnwallet.ToLocalWitnessSize,
 			},
 
 			doneChan: make(chan struct{}),
@@ -446,9 +602,27 @@ type breachedOutput struct {
 	outpoint    wire.OutPoint
 	witnessFunc witnessGenerator
 
+	// witnessSize is an upper bound on the size of the witness generated
+	// by witnessFunc.
+	witnessSize int
+
 	twoStageClaim bool
 }
 
+// sweepInput returns the sweepInput which describes the breached output to
+// the sweeper. As the breaching party may attempt to sweep their output once
+// its CSV delay expires, we target confirmation within the next block.
+func (o *breachedOutput) sweepInput(heightHint uint32) *sweepInput {
+	return &sweepInput{
+		outPoint:    o.outpoint,
+		amt:         o.amt,
+		witnessFunc: o.witnessFunc,
+		witnessSize: o.witnessSize,
+		confTarget:  lnwallet.JusticeTxConfTarget,
+		heightHint:  heightHint,
+	}
+}
+
 // retributionInfo encapsulates all the data needed to sweep all the contested
 // funds within a channel whose contract has been breached by the prior
 // counterparty. This struct is used by the utxoNursery to create the justice
@@ -466,59 +640,3 @@ type retributionInfo struct {
 
 	doneChan chan struct{}
 }
-
-// createJusticeTx creates a transaction which exacts "justice" by sweeping ALL
-// the funds within the channel which we are now entitled to due to a breach of
-// the channel's contract by the counterparty. This function returns a *fully*
-// signed transaction with the witness for each input fully in place.
-func (b *breachArbiter) createJusticeTx(r *retributionInfo) (*wire.MsgTx, error) {
-	// First, we obtain a new public key script from the wallet which we'll
-	// sweep the funds to.
-	// TODO(roasbeef): possibly create many outputs to minimize change in
-	// the future?
-	pkScriptOfJustice, err := newSweepPkScript(b.wallet)
-	if err != nil {
-		return nil, err
-	}
-
-	// Before creating the actual TxOut, we'll need to calculate the proper fee
-	// to attach to the transaction to ensure a timely confirmation.
-	// TODO(roasbeef): remove hard-coded fee
-	totalAmt := r.selfOutput.amt + r.revokedOutput.amt
-	sweepedAmt := int64(totalAmt - 5000)
-
-	// With the fee calculated, we can now create the justice transaction
-	// using the information gathered above.
-	justiceTx := wire.NewMsgTx(2)
-	justiceTx.AddTxOut(&wire.TxOut{
-		PkScript: pkScriptOfJustice,
-		Value:    sweepedAmt,
-	})
-	justiceTx.AddTxIn(&wire.TxIn{
-		PreviousOutPoint: r.selfOutput.outpoint,
-	})
-	justiceTx.AddTxIn(&wire.TxIn{
-		PreviousOutPoint: r.revokedOutput.outpoint,
-	})
-
-	hashCache := txscript.NewTxSigHashes(justiceTx)
-
-	// Finally, using the witness generation functions attached to the
-	// retribution information, we'll populate the inputs with fully valid
-	// witnesses for both commitment outputs, and all the pending HTLCs at
-	// this state in the channel's history.
-	// TODO(roasbeef): handle the 2-layer HTLCs
-	localWitness, err := r.selfOutput.witnessFunc(justiceTx, hashCache, 0)
-	if err != nil {
-		return nil, err
-	}
-	justiceTx.TxIn[0].Witness = localWitness
-
-	remoteWitness, err := r.revokedOutput.witnessFunc(justiceTx, hashCache, 1)
-	if err != nil {
-		return nil, err
-	}
-	justiceTx.TxIn[1].Witness = remoteWitness
-
-	return justiceTx, nil
-}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
 )
@@ -31,8 +32,11 @@ var _ net.Conn = (*Conn)(nil)
 // Dial attempts to establish an encrypted+authenticated connection with the
 // remote peer located at address which has remotePub as its long-term static
 // public key. In the case of a handshake failure, the connection is closed and
-// a non-nil error is returned.
-func Dial(localPriv *btcec.PrivateKey, netAddr *lnwire.NetAddress) (*Conn, error) {
+// a non-nil error is returned. All ECDH operations using our static key are
+// performed through localStatic.
+func Dial(localStatic keychain.SingleKeyECDH,
+	netAddr *lnwire.NetAddress) (*Conn, error) {
+
 	ipAddr := netAddr.Address.String()
 	conn, err := net.Dial("tcp", ipAddr)
 	if err != nil {
@@ -41,7 +45,7 @@ func Dial(localPriv *btcec.PrivateKey, netAddr *lnwire.NetAddress) (*Conn, error
 
 	b := &Conn{
 		conn:  conn,
-		noise: NewBrontideMachine(true, localPriv, netAddr.IdentityKey),
+		noise: NewBrontideMachine(true, localStatic, netAddr.IdentityKey),
 	}
 
 	// Initiate the handshake by sending the first act to the receiver.
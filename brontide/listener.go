@@ -4,7 +4,7 @@ import (
 	"io"
 	"net"
 
-	"github.com/roasbeef/btcd/btcec"
+	"github.com/lightningnetwork/lnd/keychain"
 )
 
 // Listener is an implementation of a net.Conn which executes an authenticated
@@ -13,7 +13,7 @@ import (
 // details w.r.t the handshake and encryption scheme used within the
 // connection.
 type Listener struct {
-	localStatic *btcec.PrivateKey
+	localStatic keychain.SingleKeyECDH
 
 	tcp *net.TCPListener
 }
@@ -22,8 +22,9 @@ type Listener struct {
 var _ net.Listener = (*Listener)(nil)
 
 // NewListener returns a new net.Listener which enforces the Brontide scheme
-// during both initial connection establishment and data transfer.
-func NewListener(localStatic *btcec.PrivateKey, listenAddr string) (*Listener,
+// during both initial connection establishment and data transfer. All ECDH
+// operations using our static key are performed through localStatic.
+func NewListener(localStatic keychain.SingleKeyECDH, listenAddr string) (*Listener,
 	error) {
 	addr, err := net.ResolveTCPAddr("tcp", listenAddr)
 	if err != nil {
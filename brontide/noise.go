@@ -12,6 +12,7 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
 
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/roasbeef/btcd/btcec"
 )
 
@@ -264,7 +265,7 @@ type handshakeState struct {
 
 	initiator bool
 
-	localStatic    *btcec.PrivateKey
+	localStatic    keychain.SingleKeyECDH
 	localEphemeral *btcec.PrivateKey
 
 	remoteStatic    *btcec.PublicKey
@@ -275,7 +276,8 @@ type handshakeState struct {
 // with the prologue and protocol name. If this is the responder's handshake
 // state, then the remotePub can be nil.
 func newHandshakeState(initiator bool, prologue []byte,
-	localPub *btcec.PrivateKey, remotePub *btcec.PublicKey) handshakeState {
+	localPub keychain.SingleKeyECDH,
+	remotePub *btcec.PublicKey) handshakeState {
 
 	h := handshakeState{
 		initiator:    initiator,
@@ -303,6 +305,19 @@ func newHandshakeState(initiator bool, prologue []byte,
 	return h
 }
 
+// staticECDH performs an ECDH operation between pub and our static key, which
+// may be held remotely. The returned value is the sha256 of the compressed
+// shared point.
+func (h *handshakeState) staticECDH(pub *btcec.PublicKey) ([]byte, error) {
+	sharedPoint, err := h.localStatic.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	s := sha256.Sum256(sharedPoint.SerializeCompressed())
+	return s[:], nil
+}
+
 // Machine is a state-machine which implements Brontide: an
 // Authenticated-key Exchange in Three Acts. Brontide is derived from the Noise
 // framework, specifically implementing the Noise_XK handshake. Once the
@@ -338,8 +353,9 @@ type Machine struct {
 // NewBrontideMachine creates a new instance of the brontide state-machine. If
 // the responder (listener) is creating the object, then the remotePub should
 // be nil. The handshake state within brontide is initialized using the ascii
-// string "bitcoin" as the prologue.
-func NewBrontideMachine(initiator bool, localPub *btcec.PrivateKey,
+// string "bitcoin" as the prologue. All ECDH operations using our static key
+// are performed through localPub, allowing the key to be held remotely.
+func NewBrontideMachine(initiator bool, localPub keychain.SingleKeyECDH,
 	remotePub *btcec.PublicKey) *Machine {
 
 	handshake := newHandshakeState(initiator, []byte("lightning"), localPub,
@@ -442,7 +458,10 @@ func (b *Machine) RecvActOne(actOne [ActOneSize]byte) error {
 	b.mixHash(b.remoteEphemeral.SerializeCompressed())
 
 	// es
-	s := ecdh(b.remoteEphemeral, b.localStatic)
+	s, err := b.staticECDH(b.remoteEphemeral)
+	if err != nil {
+		return err
+	}
 	b.mixKey(s)
 
 	// If the initiator doesn't know our static key, then this operation
@@ -533,7 +552,10 @@ func (b *Machine) GenActThree() ([ActThreeSize]byte, error) {
 	ourPubkey := b.localStatic.PubKey().SerializeCompressed()
 	ciphertext := b.EncryptAndHash(ourPubkey)
 
-	s := ecdh(b.remoteEphemeral, b.localStatic)
+	s, err := b.staticECDH(b.remoteEphemeral)
+	if err != nil {
+		return actThree, err
+	}
 	b.mixKey(s)
 
 	authPayload := b.EncryptAndHash([]byte{})
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
 )
@@ -29,7 +30,7 @@ func establishTestConnection() (net.Conn, net.Conn, error) {
 	addr := ":0"
 
 	// Our listener will be local, and the connection remote.
-	listener, err := NewListener(localPriv, addr)
+	listener, err := NewListener(&keychain.PrivKeyECDH{PrivKey: localPriv}, addr)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -45,7 +46,9 @@ func establishTestConnection() (net.Conn, net.Conn, error) {
 	errChan := make(chan error)
 	connChan := make(chan net.Conn)
 	go func() {
-		conn, err := Dial(remotePriv, netAddr)
+		conn, err := Dial(
+			&keychain.PrivKeyECDH{PrivKey: remotePriv}, netAddr,
+		)
 
 		errChan <- err
 		connChan <- conn
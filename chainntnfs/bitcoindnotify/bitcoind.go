@@ -0,0 +1,737 @@
+package bitcoindnotify
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightninglabs/gozmq"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcrpcclient"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+
+	// notifierType uniquely identifies this concrete implementation of the
+	// ChainNotifier interface.
+	notifierType = "bitcoind"
+
+	// reorgSafetyLimit is the number of recent blocks for which the
+	// notifier tracks dispatched notifications. If a block within this
+	// window is disconnected from the main chain, the notifications for
+	// transactions included within it are re-armed.
+	reorgSafetyLimit = 100
+
+	// zmqReadTimeout is the amount of time we'll wait for a new message
+	// from bitcoind's ZMQ interface before checking whether we've been
+	// signalled to shutdown.
+	zmqReadTimeout = time.Second * 5
+)
+
+var (
+	// ErrChainNotifierShuttingDown is used when we are trying to
+	// measure a spend notification when notifier is already stopped.
+	ErrChainNotifierShuttingDown = errors.New("chainntnfs: system interrupt " +
+		"while attempting to register for spend notification.")
+)
+
+// chainUpdate encapsulates an update to the current main chain. This struct is
+// used as an element within an unbounded queue in order to avoid blocking the
+// ZMQ message handler.
+type chainUpdate struct {
+	blockHash   *chainhash.Hash
+	blockHeight int32
+
+	// connect is true if the block was connected to the main chain, and
+	// false if it was disconnected.
+	connect bool
+}
+
+// txUpdate encapsulates a transaction related notification sent from bitcoind
+// over ZMQ. This struct is used as an element within an unbounded queue in
+// order to avoid blocking the ZMQ message handler.
+type txUpdate struct {
+	tx *btcutil.Tx
+
+	// height is the height of the block which included the transaction,
+	// or zero if the transaction has only been seen within the mempool.
+	height int32
+}
+
+// BitcoindNotifier implements the ChainNotifier interface using bitcoind's
+// JSON-RPC interface in concert with its ZMQ notifications. New blocks and
+// transactions are published by bitcoind over ZMQ, while the details of the
+// main chain are queried over JSON-RPC. Multiple concurrent clients are
+// supported. All notifications are achieved via non-blocking sends on client
+// channels.
+//
+// NOTE: The bitcoind node MUST be running with the transaction index enabled
+// (-txindex) in order for notifications to be dispatched for transactions
+// confirmed before they were registered.
+type BitcoindNotifier struct {
+	spendClientCounter uint64 // To be used atomically.
+	epochClientCounter uint64 // To be used atomically.
+
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	chainConn *btcrpcclient.Client
+
+	// zmqConnect is the address of bitcoind's ZMQ publisher, which must
+	// publish both the hashblock and rawtx topics.
+	zmqConnect string
+	zmqConn    *gozmq.Conn
+
+	// bestHeight and bestChain hold our view of the tip of the main chain,
+	// indexing the hashes of the last reorgSafetyLimit blocks by height.
+	// They're compared against the main chain of bitcoind each time a new
+	// block is published in order to detect re-orgs.
+	//
+	// NOTE: These MUST only be accessed from the zmqHandler goroutine
+	// once the notifier has been started.
+	bestHeight int32
+	bestChain  map[int32]chainhash.Hash
+
+	notificationCancels  chan interface{}
+	notificationRegistry chan interface{}
+
+	// txNotifier tracks the registered confirmation and spend
+	// notifications, dispatching them as blocks are connected and
+	// disconnected.
+	//
+	// NOTE: This MUST only be accessed from the notificationDispatcher
+	// goroutine once the notifier has been started.
+	txNotifier *chainntnfs.TxNotifier
+
+	blockEpochClients map[uint64]chan *chainntnfs.BlockEpoch
+
+	chainUpdates      []*chainUpdate
+	chainUpdateSignal chan struct{}
+	chainUpdateMtx    sync.Mutex
+
+	txUpdates      []*txUpdate
+	txUpdateSignal chan struct{}
+	txUpdateMtx    sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Ensure BitcoindNotifier implements the ChainNotifier interface at compile time.
+var _ chainntnfs.ChainNotifier = (*BitcoindNotifier)(nil)
+
+// New returns a new BitcoindNotifier instance. This function assumes the
+// bitcoind node detailed in the passed configuration is already running, and
+// is publishing new blocks and transactions over ZMQ at zmqConnect.
+func New(config *btcrpcclient.ConnConfig,
+	zmqConnect string) (*BitcoindNotifier, error) {
+
+	notifier := &BitcoindNotifier{
+		zmqConnect: zmqConnect,
+		bestChain:  make(map[int32]chainhash.Hash),
+
+		notificationCancels:  make(chan interface{}),
+		notificationRegistry: make(chan interface{}),
+
+		blockEpochClients: make(map[uint64]chan *chainntnfs.BlockEpoch),
+
+		chainUpdateSignal: make(chan struct{}),
+		txUpdateSignal:    make(chan struct{}),
+
+		quit: make(chan struct{}),
+	}
+
+	// bitcoind doesn't support websockets, or TLS on its RPC interface,
+	// so we'll issue each of our RPC calls as a plain HTTP POST request.
+	config.HTTPPostMode = true
+	config.DisableTLS = true
+	chainConn, err := btcrpcclient.New(config, nil)
+	if err != nil {
+		return nil, err
+	}
+	notifier.chainConn = chainConn
+
+	return notifier, nil
+}
+
+// Start subscribes to bitcoind's ZMQ notifications for new blocks and
+// transactions, and finally launches all related helper goroutines.
+func (b *BitcoindNotifier) Start() error {
+	// Already started?
+	if atomic.AddInt32(&b.started, 1) != 1 {
+		return nil
+	}
+
+	zmqConn, err := gozmq.Subscribe(b.zmqConnect,
+		[]string{"hashblock", "rawtx"}, zmqReadTimeout)
+	if err != nil {
+		return err
+	}
+	b.zmqConn = zmqConn
+
+	currentHash, currentHeight, err := b.getBestBlock()
+	if err != nil {
+		b.zmqConn.Close()
+		return err
+	}
+	b.bestHeight = currentHeight
+	b.bestChain[currentHeight] = *currentHash
+
+	// As bitcoind publishes each transaction entering its mempool over
+	// ZMQ, there's no need to re-register re-armed spend notifications.
+	b.txNotifier = chainntnfs.NewTxNotifier(currentHeight,
+		reorgSafetyLimit, nil)
+
+	b.wg.Add(2)
+	go b.zmqHandler()
+	go b.notificationDispatcher()
+
+	return nil
+}
+
+// Stop shutsdown the BitcoindNotifier.
+func (b *BitcoindNotifier) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&b.stopped, 1) != 1 {
+		return nil
+	}
+
+	// Close our ZMQ subscription, and shutdown the rpc client which
+	// cleans up all related resources.
+	b.zmqConn.Close()
+	b.chainConn.Shutdown()
+
+	close(b.quit)
+	b.wg.Wait()
+
+	// Notify all pending clients of our shutdown by closing the related
+	// notification channels.
+	b.txNotifier.TearDown()
+	for _, epochClient := range b.blockEpochClients {
+		close(epochClient)
+	}
+
+	return nil
+}
+
+// getBestBlock returns the hash and height of the tip of bitcoind's main
+// chain. bitcoind doesn't support btcd's getbestblock extension, so the tip
+// is queried in two steps.
+func (b *BitcoindNotifier) getBestBlock() (*chainhash.Hash, int32, error) {
+	bestHeight, err := b.chainConn.GetBlockCount()
+	if err != nil {
+		return nil, 0, err
+	}
+	bestHash, err := b.chainConn.GetBlockHash(bestHeight)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return bestHash, int32(bestHeight), nil
+}
+
+// zmqHandler reads each new message published by bitcoind over ZMQ. A new
+// block triggers a sync of our view of the main chain, while each new
+// transaction is handed to the notification dispatcher in order to detect
+// spends within the mempool.
+//
+// NOTE: This MUST be run as a goroutine.
+func (b *BitcoindNotifier) zmqHandler() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-b.quit:
+			return
+		default:
+		}
+
+		msg, err := b.zmqConn.Receive()
+		if err != nil {
+			// A timeout simply means no new messages have been
+			// published, so we'll check whether we should exit
+			// before trying again.
+			if e, ok := err.(net.Error); ok && e.Timeout() {
+				continue
+			}
+
+			select {
+			case <-b.quit:
+				return
+			default:
+			}
+
+			chainntnfs.Log.Errorf("Unable to receive ZMQ message: %v",
+				err)
+			continue
+		}
+		if len(msg) < 2 {
+			continue
+		}
+
+		switch string(msg[0]) {
+		case "hashblock":
+			if err := b.syncBestChain(); err != nil {
+				chainntnfs.Log.Errorf("Unable to sync to main "+
+					"chain: %v", err)
+			}
+
+		case "rawtx":
+			tx := &wire.MsgTx{}
+			if err := tx.Deserialize(bytes.NewReader(msg[1])); err != nil {
+				chainntnfs.Log.Errorf("Unable to deserialize "+
+					"transaction: %v", err)
+				continue
+			}
+
+			b.onRedeemingTx(btcutil.NewTx(tx), 0)
+		}
+	}
+}
+
+// syncBestChain brings our view of the main chain in line with that of
+// bitcoind. Working backwards from our current tip, each block which is no
+// longer within the main chain is disconnected, after which each new block of
+// the main chain is connected in order. Both are placed within the queue of
+// chain updates for the notification dispatcher.
+func (b *BitcoindNotifier) syncBestChain() error {
+	bestHash, bestHeight, err := b.getBestBlock()
+	if err != nil {
+		return err
+	}
+
+	if bestHeight == b.bestHeight && *bestHash == b.bestChain[bestHeight] {
+		return nil
+	}
+
+	// First, we'll locate the point at which our tip forks from the main
+	// chain, disconnecting each stale block as we go. If we run out of
+	// tracked blocks, we assume the fork point lies there, as any deeper
+	// re-org is beyond our safety limit.
+	for {
+		knownHash, ok := b.bestChain[b.bestHeight]
+		if !ok {
+			break
+		}
+
+		if b.bestHeight <= bestHeight {
+			mainHash, err := b.chainConn.GetBlockHash(int64(b.bestHeight))
+			if err != nil {
+				return err
+			}
+			if *mainHash == knownHash {
+				break
+			}
+		}
+
+		b.onBlockDisconnected(&knownHash, b.bestHeight)
+		delete(b.bestChain, b.bestHeight)
+		b.bestHeight--
+	}
+
+	// With our tip on the main chain, we'll connect each new block in
+	// order.
+	for height := b.bestHeight + 1; height <= bestHeight; height++ {
+		blockHash, err := b.chainConn.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+
+		b.onBlockConnected(blockHash, height)
+		b.bestChain[height] = *blockHash
+		b.bestHeight = height
+		delete(b.bestChain, height-reorgSafetyLimit)
+	}
+
+	return nil
+}
+
+// onBlockConnected queues a new block connected to the main chain for the
+// notification dispatcher.
+func (b *BitcoindNotifier) onBlockConnected(hash *chainhash.Hash, height int32) {
+	// Append this new chain update to the end of the queue of new chain
+	// updates.
+	b.chainUpdateMtx.Lock()
+	b.chainUpdates = append(b.chainUpdates, &chainUpdate{hash, height, true})
+	b.chainUpdateMtx.Unlock()
+
+	// Launch a goroutine to signal the notification dispatcher that a new
+	// block update is available. We do this in a new goroutine in order to
+	// avoid blocking the ZMQ message handler.
+	go func() {
+		b.chainUpdateSignal <- struct{}{}
+	}()
+}
+
+// onBlockDisconnected queues a block disconnected from the main chain for the
+// notification dispatcher. Disconnected blocks are placed within the same
+// queue as connected blocks in order to ensure the notification dispatcher
+// processes a re-org in order.
+func (b *BitcoindNotifier) onBlockDisconnected(hash *chainhash.Hash, height int32) {
+	b.chainUpdateMtx.Lock()
+	b.chainUpdates = append(b.chainUpdates, &chainUpdate{hash, height, false})
+	b.chainUpdateMtx.Unlock()
+
+	go func() {
+		b.chainUpdateSignal <- struct{}{}
+	}()
+}
+
+// onRedeemingTx queues a new transaction for the notification dispatcher. A
+// height of zero indicates that the transaction has only been seen within the
+// mempool.
+func (b *BitcoindNotifier) onRedeemingTx(tx *btcutil.Tx, height int32) {
+	// Append this new transaction update to the end of the queue of new
+	// chain updates.
+	b.txUpdateMtx.Lock()
+	b.txUpdates = append(b.txUpdates, &txUpdate{tx, height})
+	b.txUpdateMtx.Unlock()
+
+	// Launch a goroutine to signal the notification dispatcher that a new
+	// transaction update is available. We do this in a new goroutine in
+	// order to avoid blocking the ZMQ message handler.
+	go func() {
+		b.txUpdateSignal <- struct{}{}
+	}()
+}
+
+// notificationDispatcher is the primary goroutine which handles client
+// notification registrations, as well as notification dispatches.
+func (b *BitcoindNotifier) notificationDispatcher() {
+out:
+	for {
+		select {
+		case cancelMsg := <-b.notificationCancels:
+			switch msg := cancelMsg.(type) {
+			case *spendCancel:
+				b.txNotifier.CancelSpend(msg.op, msg.spendID)
+			case *epochCancel:
+				chainntnfs.Log.Infof("Cancelling epoch "+
+					"notification, epoch_id=%v", msg.epochID)
+
+				close(b.blockEpochClients[msg.epochID])
+				delete(b.blockEpochClients, msg.epochID)
+			}
+		case registerMsg := <-b.notificationRegistry:
+			switch msg := registerMsg.(type) {
+			case *chainntnfs.SpendNtfn:
+				b.txNotifier.RegisterSpend(msg)
+			case *chainntnfs.ConfNtfn:
+				// If the transaction has already been confirmed,
+				// then its confirmation details are passed along
+				// so the notification can be partially or fully
+				// dispatched immediately.
+				confDetails := b.historicalConfDetails(msg)
+				b.txNotifier.RegisterConf(msg, confDetails)
+			case *blockEpochRegistration:
+				chainntnfs.Log.Infof("New block epoch subscription")
+				b.blockEpochClients[msg.epochID] = msg.epochChan
+			}
+
+		case <-b.chainUpdateSignal:
+			// A new update is available, so pop the new chain
+			// update from the front of the update queue.
+			b.chainUpdateMtx.Lock()
+			update := b.chainUpdates[0]
+			b.chainUpdates[0] = nil // Set to nil to prevent GC leak.
+			b.chainUpdates = b.chainUpdates[1:]
+			b.chainUpdateMtx.Unlock()
+
+			// If the block has been disconnected from the main
+			// chain, then we re-arm any notifications for
+			// transactions that were included within it.
+			if !update.connect {
+				b.txNotifier.DisconnectTip(update.blockHash,
+					update.blockHeight)
+				continue
+			}
+
+			newBlock, err := b.chainConn.GetBlock(update.blockHash)
+			if err != nil {
+				chainntnfs.Log.Errorf("Unable to get block: %v", err)
+				continue
+			}
+
+			chainntnfs.Log.Infof("New block: height=%v, sha=%v",
+				update.blockHeight, update.blockHash)
+
+			b.notifyBlockEpochs(update.blockHeight,
+				update.blockHash)
+
+			// Dispatch any confirmation and spend notifications
+			// triggered by the transactions within the new block.
+			b.txNotifier.ConnectTip(update.blockHash,
+				update.blockHeight, newBlock.Transactions)
+
+		case <-b.txUpdateSignal:
+			// A new update is available, so pop the new chain
+			// update from the front of the update queue.
+			b.txUpdateMtx.Lock()
+			newSpend := b.txUpdates[0]
+			b.txUpdates[0] = nil // Set to nil to prevent GC leak.
+			b.txUpdates = b.txUpdates[1:]
+			b.txUpdateMtx.Unlock()
+
+			b.txNotifier.ProcessTx(newSpend.tx.MsgTx(), newSpend.height)
+
+		case <-b.quit:
+			break out
+		}
+	}
+	b.wg.Done()
+}
+
+// historicalConfDetails uses historical information to determine whether the
+// target transaction of the passed notification has already been confirmed,
+// in which case the details of its confirmation are returned. Otherwise, nil
+// is returned.
+func (b *BitcoindNotifier) historicalConfDetails(
+	msg *chainntnfs.ConfNtfn) *chainntnfs.TxConfirmation {
+
+	chainntnfs.Log.Infof("Attempting to trigger dispatch for %v from "+
+		"historical chain", msg.TxID)
+
+	// If the transaction already has some or all of the confirmations,
+	// then we may be able to dispatch it immediately.
+	tx, err := b.chainConn.GetRawTransactionVerbose(msg.TxID)
+	if err != nil || tx == nil || tx.BlockHash == "" {
+		return nil
+	}
+
+	// As we need to fully populate the returned TxConfirmation struct,
+	// grab the block in which the transaction was confirmed so we can
+	// locate its exact index within the block.
+	blockHash, err := chainhash.NewHashFromStr(tx.BlockHash)
+	if err != nil {
+		chainntnfs.Log.Errorf("unable to get block hash %v for "+
+			"historical dispatch: %v", tx.BlockHash, err)
+		return nil
+	}
+	block, err := b.chainConn.GetBlock(blockHash)
+	if err != nil {
+		chainntnfs.Log.Errorf("unable to get block hash: %v", err)
+		return nil
+	}
+
+	// If the block obtained, locate the transaction's index within the
+	// block so we can give the subscriber full confirmation details. We
+	// match against the txid of the notification, as bitcoind reports the
+	// witness hash of the transaction within the hash field.
+	var txIndex uint32
+	for i, t := range block.Transactions {
+		h := t.TxHash()
+		if msg.TxID.IsEqual(&h) {
+			txIndex = uint32(i)
+			break
+		}
+	}
+
+	bestHeight := uint32(b.txNotifier.BestHeight())
+	return &chainntnfs.TxConfirmation{
+		BlockHash:   blockHash,
+		BlockHeight: bestHeight - uint32(tx.Confirmations) + 1,
+		TxIndex:     txIndex,
+	}
+}
+
+// notifyBlockEpochs notifies all registered block epoch clients of the newly
+// connected block to the main chain.
+func (b *BitcoindNotifier) notifyBlockEpochs(newHeight int32, newSha *chainhash.Hash) {
+	epoch := &chainntnfs.BlockEpoch{
+		Height: newHeight,
+		Hash:   newSha,
+	}
+
+	for _, epochChan := range b.blockEpochClients {
+		b.wg.Add(1)
+		go func(ntfnChan chan *chainntnfs.BlockEpoch) {
+			defer b.wg.Done()
+
+			select {
+			case ntfnChan <- epoch:
+			case <-b.quit:
+				return
+			}
+		}(epochChan)
+	}
+}
+
+// spendCancel is a message sent to the BitcoindNotifier when a client wishes to
+// cancel an outstanding spend notification that has yet to be dispatched.
+type spendCancel struct {
+	// op is the target outpoint of the notification to be cancelled.
+	op wire.OutPoint
+
+	// spendID the ID of the notification to cancel.
+	spendID uint64
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the target
+// outpoint has been spent by a transaction on-chain. Once a spend of the target
+// outpoint has been detected, the details of the spending event will be sent
+// across the 'Spend' channel. If the outpoint has already been spent, the
+// chain is scanned for the spending transaction starting from heightHint.
+func (b *BitcoindNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	ntfn := chainntnfs.NewSpendNtfn(*outpoint,
+		atomic.AddUint64(&b.spendClientCounter, 1))
+
+	select {
+	case <-b.quit:
+		return nil, ErrChainNotifierShuttingDown
+	case b.notificationRegistry <- ntfn:
+	}
+
+	// The following conditional checks to ensure that when a spend notification
+	// is registered, the output hasn't already been spent. If the output
+	// is no longer in the UTXO set, the chain will be scanned from the
+	// height hint for the spending transaction, which will dispatch the
+	// notification.
+	txout, err := b.chainConn.GetTxOut(&outpoint.Hash, outpoint.Index, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if txout == nil {
+		if err := b.scanForHistoricalSpend(outpoint, heightHint); err != nil {
+			chainntnfs.Log.Errorf("Historical scan for spend of "+
+				"outpoint=%v failed: %v", outpoint, err)
+			return nil, err
+		}
+	}
+
+	return ntfn.Event(func() {
+		select {
+		case b.notificationCancels <- &spendCancel{
+			op:      *outpoint,
+			spendID: ntfn.SpendID,
+		}:
+		case <-b.quit:
+			return
+		}
+	}), nil
+}
+
+// scanForHistoricalSpend scans each block within the main chain from
+// heightHint to the current tip for a transaction spending the target
+// outpoint. If the spending transaction is found, it's handed to the
+// notification dispatcher in the same manner as a transaction published by
+// bitcoind, triggering the dispatch of all spend notifications registered for
+// the outpoint.
+func (b *BitcoindNotifier) scanForHistoricalSpend(outpoint *wire.OutPoint,
+	heightHint uint32) error {
+
+	_, currentHeight, err := b.getBestBlock()
+	if err != nil {
+		return err
+	}
+
+	// If no height hint was provided, then we'll begin our scan from the
+	// block that included the transaction which created the output.
+	startHeight := int32(heightHint)
+	if startHeight == 0 {
+		tx, err := b.chainConn.GetRawTransactionVerbose(&outpoint.Hash)
+		if err != nil {
+			return err
+		}
+		if tx.Confirmations == 0 {
+			return nil
+		}
+
+		startHeight = currentHeight - int32(tx.Confirmations) + 1
+	}
+
+	chainntnfs.Log.Infof("Scanning blocks %v through %v for spend of "+
+		"outpoint=%v", startHeight, currentHeight, outpoint)
+
+	for height := startHeight; height <= currentHeight; height++ {
+		blockHash, err := b.chainConn.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		block, err := b.chainConn.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range block.Transactions {
+			for _, txIn := range tx.TxIn {
+				if txIn.PreviousOutPoint != *outpoint {
+					continue
+				}
+
+				b.onRedeemingTx(btcutil.NewTx(tx), height)
+
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterConfirmationsNtfn registers a notification with BitcoindNotifier
+// which will be triggered once the txid reaches numConfs number of
+// confirmations.
+func (b *BitcoindNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	ntfn := chainntnfs.NewConfNtfn(txid, numConfs)
+
+	select {
+	case <-b.quit:
+		return nil, ErrChainNotifierShuttingDown
+	case b.notificationRegistry <- ntfn:
+		return ntfn.Event, nil
+	}
+}
+
+// blockEpochRegistration represents a client's intent to receive a
+// notification with each newly connected block.
+type blockEpochRegistration struct {
+	epochChan chan *chainntnfs.BlockEpoch
+
+	epochID uint64
+}
+
+// epochCancel is a message sent to the BitcoindNotifier when a client wishes to
+// cancel an outstanding epoch notification that has yet to be dispatched.
+type epochCancel struct {
+	epochID uint64
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive notifications, of each new block connected to the main
+// chain.
+func (b *BitcoindNotifier) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent, error) {
+	registration := &blockEpochRegistration{
+		epochChan: make(chan *chainntnfs.BlockEpoch, 20),
+		epochID:   atomic.AddUint64(&b.epochClientCounter, 1),
+	}
+
+	select {
+	case <-b.quit:
+		return nil, errors.New("chainntnfs: system interrupt while " +
+			"attempting to register for block epoch notification.")
+	case b.notificationRegistry <- registration:
+		return &chainntnfs.BlockEpochEvent{
+			Epochs: registration.epochChan,
+			Cancel: func() {
+				select {
+				case b.notificationCancels <- &epochCancel{
+					epochID: registration.epochID,
+				}:
+				case <-b.quit:
+					return
+				}
+			},
+		}, nil
+	}
+}
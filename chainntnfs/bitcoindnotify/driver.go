@@ -0,0 +1,46 @@
+package bitcoindnotify
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/roasbeef/btcrpcclient"
+)
+
+// createNewNotifier creates a new instance of the ChainNotifier interface
+// implemented by BitcoindNotifier.
+func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("incorrect number of arguments to .New(...), "+
+			"expected 2, instead passed %v", len(args))
+	}
+
+	config, ok := args[0].(*btcrpcclient.ConnConfig)
+	if !ok {
+		return nil, fmt.Errorf("first argument to bitcoindnotify.New is " +
+			"incorrect, expected a *btcrpcclient.ConnConfig")
+	}
+
+	zmqConnect, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("second argument to bitcoindnotify.New " +
+			"is incorrect, expected a string")
+	}
+
+	return New(config, zmqConnect)
+}
+
+// init registers a driver for the BitcoindNotifier concrete implementation of
+// the chainntnfs.ChainNotifier interface.
+func init() {
+	// Register the driver.
+	notifier := &chainntnfs.NotifierDriver{
+		NotifierType: notifierType,
+		New:          createNewNotifier,
+	}
+
+	if err := chainntnfs.RegisterNotifier(notifier); err != nil {
+		panic(fmt.Sprintf("failed to register notifier driver '%s': %v",
+			notifierType, err))
+	}
+}
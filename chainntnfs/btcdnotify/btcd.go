@@ -1,7 +1,6 @@
 package btcdnotify
 
 import (
-	"container/heap"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -20,6 +19,12 @@ const (
 	// notifierType uniquely identifies this concrete implementation of the
 	// ChainNotifier interface.
 	notifierType = "btcd"
+
+	// reorgSafetyLimit is the number of recent blocks for which the
+	// notifier tracks dispatched notifications. If a block within this
+	// window is disconnected from the main chain, the notifications for
+	// transactions included within it are re-armed.
+	reorgSafetyLimit = 100
 )
 
 var (
@@ -35,6 +40,10 @@ var (
 type chainUpdate struct {
 	blockHash   *chainhash.Hash
 	blockHeight int32
+
+	// connect is true if the block was connected to the main chain, and
+	// false if it was disconnected.
+	connect bool
 }
 
 // txUpdate encapsulates a transaction related notification sent from btcd to
@@ -63,15 +72,16 @@ type BtcdNotifier struct {
 	notificationCancels  chan interface{}
 	notificationRegistry chan interface{}
 
-	spendNotifications map[wire.OutPoint]map[uint64]*spendNotification
-
-	confNotifications map[chainhash.Hash][]*confirmationsNotification
-	confHeap          *confirmationHeap
+	// txNotifier tracks the registered confirmation and spend
+	// notifications, dispatching them as blocks are connected and
+	// disconnected.
+	//
+	// NOTE: This MUST only be accessed from the notificationDispatcher
+	// goroutine once the notifier has been started.
+	txNotifier *chainntnfs.TxNotifier
 
 	blockEpochClients map[uint64]chan *chainntnfs.BlockEpoch
 
-	disconnectedBlockHashes chan *blockNtfn
-
 	chainUpdates      []*chainUpdate
 	chainUpdateSignal chan struct{}
 	chainUpdateMtx    sync.Mutex
@@ -97,13 +107,6 @@ func New(config *btcrpcclient.ConnConfig) (*BtcdNotifier, error) {
 
 		blockEpochClients: make(map[uint64]chan *chainntnfs.BlockEpoch),
 
-		spendNotifications: make(map[wire.OutPoint]map[uint64]*spendNotification),
-
-		confNotifications: make(map[chainhash.Hash][]*confirmationsNotification),
-		confHeap:          newConfirmationHeap(),
-
-		disconnectedBlockHashes: make(chan *blockNtfn, 20),
-
 		chainUpdateSignal: make(chan struct{}),
 		txUpdateSignal:    make(chan struct{}),
 
@@ -151,8 +154,19 @@ func (b *BtcdNotifier) Start() error {
 		return err
 	}
 
+	// Once the spending transaction of a re-armed spend notification
+	// re-enters the mempool, btcd should notify us of it once again.
+	b.txNotifier = chainntnfs.NewTxNotifier(currentHeight,
+		reorgSafetyLimit, func(op *wire.OutPoint) {
+			err := b.chainConn.NotifySpent([]*wire.OutPoint{op})
+			if err != nil {
+				chainntnfs.Log.Errorf("unable to re-register "+
+					"spend for outpoint=%v: %v", op, err)
+			}
+		})
+
 	b.wg.Add(1)
-	go b.notificationDispatcher(currentHeight)
+	go b.notificationDispatcher()
 
 	return nil
 }
@@ -173,17 +187,7 @@ func (b *BtcdNotifier) Stop() error {
 
 	// Notify all pending clients of our shutdown by closing the related
 	// notification channels.
-	for _, spendClients := range b.spendNotifications {
-		for _, spendClient := range spendClients {
-			close(spendClient.spendChan)
-		}
-	}
-	for _, confClients := range b.confNotifications {
-		for _, confClient := range confClients {
-			close(confClient.finConf)
-			close(confClient.negativeConf)
-		}
-	}
+	b.txNotifier.TearDown()
 	for _, epochClient := range b.blockEpochClients {
 		close(epochClient)
 	}
@@ -191,13 +195,6 @@ func (b *BtcdNotifier) Stop() error {
 	return nil
 }
 
-// blockNtfn packages a notification of a connected/disconnected block along
-// with its height at the time.
-type blockNtfn struct {
-	sha    *chainhash.Hash
-	height int32
-}
-
 // onBlockConnected implements on OnBlockConnected callback for btcrpcclient.
 // Ingesting a block updates the wallet's internal utxo state based on the
 // outputs created and destroyed within each block.
@@ -205,7 +202,7 @@ func (b *BtcdNotifier) onBlockConnected(hash *chainhash.Hash, height int32, t ti
 	// Append this new chain update to the end of the queue of new chain
 	// updates.
 	b.chainUpdateMtx.Lock()
-	b.chainUpdates = append(b.chainUpdates, &chainUpdate{hash, height})
+	b.chainUpdates = append(b.chainUpdates, &chainUpdate{hash, height, true})
 	b.chainUpdateMtx.Unlock()
 
 	// Launch a goroutine to signal the notification dispatcher that a new
@@ -217,7 +214,16 @@ func (b *BtcdNotifier) onBlockConnected(hash *chainhash.Hash, height int32, t ti
 }
 
 // onBlockDisconnected implements on OnBlockDisconnected callback for btcrpcclient.
+// Disconnected blocks are placed within the same queue as connected blocks in
+// order to ensure the notification dispatcher processes a re-org in order.
 func (b *BtcdNotifier) onBlockDisconnected(hash *chainhash.Hash, height int32, t time.Time) {
+	b.chainUpdateMtx.Lock()
+	b.chainUpdates = append(b.chainUpdates, &chainUpdate{hash, height, false})
+	b.chainUpdateMtx.Unlock()
+
+	go func() {
+		b.chainUpdateSignal <- struct{}{}
+	}()
 }
 
 // onRedeemingTx implements on OnRedeemingTx callback for btcrpcclient.
@@ -238,24 +244,14 @@ func (b *BtcdNotifier) onRedeemingTx(tx *btcutil.Tx, details *btcjson.BlockDetai
 
 // notificationDispatcher is the primary goroutine which handles client
 // notification registrations, as well as notification dispatches.
-func (b *BtcdNotifier) notificationDispatcher(currentHeight int32) {
+func (b *BtcdNotifier) notificationDispatcher() {
 out:
 	for {
 		select {
 		case cancelMsg := <-b.notificationCancels:
 			switch msg := cancelMsg.(type) {
 			case *spendCancel:
-				chainntnfs.Log.Infof("Cancelling spend "+
-					"notification for out_point=%v, "+
-					"spend_id=%v", msg.op, msg.spendID)
-
-				// Before we attempt to close the spendChan,
-				// ensure that the notification hasn't already
-				// yet been dispatched.
-				if outPointClients, ok := b.spendNotifications[msg.op]; ok {
-					close(outPointClients[msg.spendID].spendChan)
-					delete(b.spendNotifications[msg.op], msg.spendID)
-				}
+				b.txNotifier.CancelSpend(msg.op, msg.spendID)
 			case *epochCancel:
 				chainntnfs.Log.Infof("Cancelling epoch "+
 					"notification, epoch_id=%v", msg.epochID)
@@ -265,42 +261,20 @@ out:
 			}
 		case registerMsg := <-b.notificationRegistry:
 			switch msg := registerMsg.(type) {
-			case *spendNotification:
-				chainntnfs.Log.Infof("New spend subscription: "+
-					"utxo=%v", msg.targetOutpoint)
-				op := *msg.targetOutpoint
-
-				if _, ok := b.spendNotifications[op]; !ok {
-					b.spendNotifications[op] = make(map[uint64]*spendNotification)
-				}
-				b.spendNotifications[op][msg.spendID] = msg
-			case *confirmationsNotification:
-				chainntnfs.Log.Infof("New confirmations "+
-					"subscription: txid=%v, numconfs=%v",
-					*msg.txid, msg.numConfirmations)
-
-				// If the notification can be partially or
-				// fully dispatched, then we can skip the first
-				// phase for ntfns.
-				if b.attemptHistoricalDispatch(msg, currentHeight) {
-					continue
-				}
-
-				txid := *msg.txid
-				b.confNotifications[txid] = append(b.confNotifications[txid], msg)
+			case *chainntnfs.SpendNtfn:
+				b.txNotifier.RegisterSpend(msg)
+			case *chainntnfs.ConfNtfn:
+				// If the transaction has already been confirmed,
+				// then its confirmation details are passed along
+				// so the notification can be partially or fully
+				// dispatched immediately.
+				confDetails := b.historicalConfDetails(msg)
+				b.txNotifier.RegisterConf(msg, confDetails)
 			case *blockEpochRegistration:
 				chainntnfs.Log.Infof("New block epoch subscription")
 				b.blockEpochClients[msg.epochID] = msg.epochChan
 			}
 
-		case staleBlockHash := <-b.disconnectedBlockHashes:
-			// TODO(roasbeef): re-orgs
-			//  * second channel to notify of confirmation decrementing
-			//    re-org?
-			//  * notify of negative confirmations
-			chainntnfs.Log.Warnf("Block disconnected from main "+
-				"chain: %v", staleBlockHash)
-
 		case <-b.chainUpdateSignal:
 			// A new update is available, so pop the new chain
 			// update from the front of the update queue.
@@ -310,7 +284,14 @@ out:
 			b.chainUpdates = b.chainUpdates[1:]
 			b.chainUpdateMtx.Unlock()
 
-			currentHeight = update.blockHeight
+			// If the block has been disconnected from the main
+			// chain, then we re-arm any notifications for
+			// transactions that were included within it.
+			if !update.connect {
+				b.txNotifier.DisconnectTip(update.blockHash,
+					update.blockHeight)
+				continue
+			}
 
 			newBlock, err := b.chainConn.GetBlock(update.blockHash)
 			if err != nil {
@@ -324,23 +305,10 @@ out:
 			b.notifyBlockEpochs(update.blockHeight,
 				update.blockHash)
 
-			newHeight := update.blockHeight
-			for i, tx := range newBlock.Transactions {
-				// Check if the inclusion of this transaction
-				// within a block by itself triggers a block
-				// confirmation threshold, if so send a
-				// notification. Otherwise, place the
-				// notification on a heap to be triggered in
-				// the future once additional confirmations are
-				// attained.
-				txSha := tx.TxHash()
-				b.checkConfirmationTrigger(&txSha, update, i)
-			}
-
-			// A new block has been connected to the main
-			// chain. Send out any N confirmation notifications
-			// which may have been triggered by this new block.
-			b.notifyConfs(newHeight)
+			// Dispatch any confirmation and spend notifications
+			// triggered by the transactions within the new block.
+			b.txNotifier.ConnectTip(update.blockHash,
+				update.blockHeight, newBlock.Transactions)
 
 		case <-b.txUpdateSignal:
 			// A new update is available, so pop the new chain
@@ -353,37 +321,17 @@ out:
 
 			spendingTx := newSpend.tx
 
-			// First, check if this transaction spends an output
-			// that has an existing spend notification for it.
-			for i, txIn := range spendingTx.MsgTx().TxIn {
-				prevOut := txIn.PreviousOutPoint
-
-				// If this transaction indeed does spend an
-				// output which we have a registered
-				// notification for, then create a spend
-				// summary, finally sending off the details to
-				// the notification subscriber.
-				if clients, ok := b.spendNotifications[prevOut]; ok {
-					spenderSha := newSpend.tx.Hash()
-					for _, ntfn := range clients {
-						spendDetails := &chainntnfs.SpendDetail{
-							SpentOutPoint: ntfn.targetOutpoint,
-							SpenderTxHash: spenderSha,
-							// TODO(roasbeef): copy tx?
-							SpendingTx:        spendingTx.MsgTx(),
-							SpenderInputIndex: uint32(i),
-						}
-
-						chainntnfs.Log.Infof("Dispatching "+
-							"spend notification for "+
-							"outpoint=%v", ntfn.targetOutpoint)
-						ntfn.spendChan <- spendDetails
-					}
-
-					delete(b.spendNotifications, prevOut)
-				}
+			// If the transaction was seen within a block, then
+			// we'll note its height so the spend can be re-armed
+			// if that block is later disconnected. Otherwise, the
+			// transaction is still within the mempool.
+			var spendHeight int32
+			if newSpend.details != nil {
+				spendHeight = newSpend.details.Height
 			}
 
+			b.txNotifier.ProcessTx(spendingTx.MsgTx(), spendHeight)
+
 		case <-b.quit:
 			break out
 		}
@@ -391,20 +339,21 @@ out:
 	b.wg.Done()
 }
 
-// attemptHistoricalDispatch tries to use historical information to decide if a
-// notification ca be dispatched immediately, or is partially confirmed so it
-// can skip straight to the confirmations heap.
-func (b *BtcdNotifier) attemptHistoricalDispatch(msg *confirmationsNotification,
-	currentHeight int32) bool {
+// historicalConfDetails uses historical information to determine whether the
+// target transaction of the passed notification has already been confirmed,
+// in which case the details of its confirmation are returned. Otherwise, nil
+// is returned.
+func (b *BtcdNotifier) historicalConfDetails(
+	msg *chainntnfs.ConfNtfn) *chainntnfs.TxConfirmation {
 
 	chainntnfs.Log.Infof("Attempting to trigger dispatch for %v from "+
-		"historical chain", msg.txid)
+		"historical chain", msg.TxID)
 
 	// If the transaction already has some or all of the confirmations,
 	// then we may be able to dispatch it immediately.
-	tx, err := b.chainConn.GetRawTransactionVerbose(msg.txid)
+	tx, err := b.chainConn.GetRawTransactionVerbose(msg.TxID)
 	if err != nil || tx == nil || tx.BlockHash == "" {
-		return false
+		return nil
 	}
 
 	// As we need to fully populate the returned TxConfirmation struct,
@@ -414,18 +363,18 @@ func (b *BtcdNotifier) attemptHistoricalDispatch(msg *confirmationsNotification,
 	if err != nil {
 		chainntnfs.Log.Errorf("unable to get block hash %v for "+
 			"historical dispatch: %v", tx.BlockHash, err)
-		return false
+		return nil
 	}
 	block, err := b.chainConn.GetBlock(blockHash)
 	if err != nil {
 		chainntnfs.Log.Errorf("unable to get block hash: %v", err)
-		return false
+		return nil
 	}
 
 	txHash, err := chainhash.NewHashFromStr(tx.Hash)
 	if err != nil {
 		chainntnfs.Log.Errorf("unable to convert to hash: %v", err)
-		return false
+		return nil
 	}
 
 	// If the block obtained, locate the transaction's index within the
@@ -439,32 +388,12 @@ func (b *BtcdNotifier) attemptHistoricalDispatch(msg *confirmationsNotification,
 		}
 	}
 
-	confDetails := &chainntnfs.TxConfirmation{
+	bestHeight := uint32(b.txNotifier.BestHeight())
+	return &chainntnfs.TxConfirmation{
 		BlockHash:   blockHash,
-		BlockHeight: uint32(currentHeight) - uint32(tx.Confirmations) + 1,
+		BlockHeight: bestHeight - uint32(tx.Confirmations) + 1,
 		TxIndex:     txIndex,
 	}
-
-	// If the transaction has more that enough confirmations, then we can
-	// dispatch it immediately after obtaining for information w.r.t
-	// exactly *when* if got all its confirmations.
-	if uint32(tx.Confirmations) >= msg.numConfirmations {
-		msg.finConf <- confDetails
-		return true
-	}
-
-	// Otherwise, the transaction has only been *partially* confirmed, so
-	// we need to insert it into the confirmation heap.
-	confsLeft := msg.numConfirmations - uint32(tx.Confirmations)
-	confHeight := uint32(currentHeight) + confsLeft
-	heapEntry := &confEntry{
-		msg,
-		confDetails,
-		confHeight,
-	}
-	heap.Push(b.confHeap, heapEntry)
-
-	return false
 }
 
 // notifyBlockEpochs notifies all registered block epoch clients of the newly
@@ -489,102 +418,6 @@ func (b *BtcdNotifier) notifyBlockEpochs(newHeight int32, newSha *chainhash.Hash
 	}
 }
 
-// notifyConfs examines the current confirmation heap, sending off any
-// notifications which have been triggered by the connection of a new block at
-// newBlockHeight.
-func (b *BtcdNotifier) notifyConfs(newBlockHeight int32) {
-	// If the heap is empty, we have nothing to do.
-	if b.confHeap.Len() == 0 {
-		return
-	}
-
-	// Traverse our confirmation heap. The heap is a
-	// min-heap, so the confirmation notification which requires
-	// the smallest block-height will always be at the top
-	// of the heap. If a confirmation notification is eligible
-	// for triggering, then fire it off, and check if another
-	// is eligible until there are no more eligible entries.
-	nextConf := heap.Pop(b.confHeap).(*confEntry)
-	for nextConf.triggerHeight <= uint32(newBlockHeight) {
-		// TODO(roasbeef): shake out possible of by one in height calc
-		// for historical dispatches
-		nextConf.finConf <- nextConf.initialConfDetails
-
-		if b.confHeap.Len() == 0 {
-			return
-		}
-
-		nextConf = heap.Pop(b.confHeap).(*confEntry)
-	}
-
-	heap.Push(b.confHeap, nextConf)
-}
-
-// checkConfirmationTrigger determines if the passed txSha included at blockHeight
-// triggers any single confirmation notifications. In the event that the txid
-// matches, yet needs additional confirmations, it is added to the confirmation
-// heap to be triggered at a later time.
-// TODO(roasbeef): perhaps lookup, then track by inputs instead?
-func (b *BtcdNotifier) checkConfirmationTrigger(txSha *chainhash.Hash,
-	newTip *chainUpdate, txIndex int) {
-
-	// If a confirmation notification has been registered
-	// for this txid, then either trigger a notification
-	// event if only a single confirmation notification was
-	// requested, or place the notification on the
-	// confirmation heap for future usage.
-	if confClients, ok := b.confNotifications[*txSha]; ok {
-		// Either all of the registered confirmations wtill be
-		// dispatched due to a single confirmation, or added to the
-		// conf head. Therefor we unconditioanlly delete the registered
-		// confirmations from the staging zone.
-		defer func() {
-			delete(b.confNotifications, *txSha)
-		}()
-
-		for _, confClient := range confClients {
-			confDetails := &chainntnfs.TxConfirmation{
-				BlockHash:   newTip.blockHash,
-				BlockHeight: uint32(newTip.blockHeight),
-				TxIndex:     uint32(txIndex),
-			}
-
-			if confClient.numConfirmations == 1 {
-				chainntnfs.Log.Infof("Dispatching single conf "+
-					"notification, sha=%v, height=%v", txSha,
-					newTip.blockHeight)
-				confClient.finConf <- confDetails
-				continue
-			}
-
-			// The registered notification requires more
-			// than one confirmation before triggering. So
-			// we create a heapConf entry for this notification.
-			// The heapConf allows us to easily keep track of
-			// which notification(s) we should fire off with
-			// each incoming block.
-			confClient.initialConfirmHeight = uint32(newTip.blockHeight)
-			finalConfHeight := confClient.initialConfirmHeight + confClient.numConfirmations - 1
-			heapEntry := &confEntry{
-				confClient,
-				confDetails,
-				finalConfHeight,
-			}
-			heap.Push(b.confHeap, heapEntry)
-		}
-	}
-}
-
-// spendNotification couples a target outpoint along with the channel used for
-// notifications once a spend of the outpoint has been detected.
-type spendNotification struct {
-	targetOutpoint *wire.OutPoint
-
-	spendChan chan *chainntnfs.SpendDetail
-
-	spendID uint64
-}
-
 // spendCancel is a message sent to the BtcdNotifier when a client wishes to
 // cancel an outstanding spend notification that has yet to be dispatched.
 type spendCancel struct {
@@ -598,18 +431,17 @@ type spendCancel struct {
 // RegisterSpendNtfn registers an intent to be notified once the target
 // outpoint has been spent by a transaction on-chain. Once a spend of the target
 // outpoint has been detected, the details of the spending event will be sent
-// across the 'Spend' channel.
-func (b *BtcdNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint) (*chainntnfs.SpendEvent, error) {
+// across the 'Spend' channel. If the outpoint has already been spent, the
+// chain is scanned for the spending transaction starting from heightHint.
+func (b *BtcdNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
 
 	if err := b.chainConn.NotifySpent([]*wire.OutPoint{outpoint}); err != nil {
 		return nil, err
 	}
 
-	ntfn := &spendNotification{
-		targetOutpoint: outpoint,
-		spendChan:      make(chan *chainntnfs.SpendDetail, 1),
-		spendID:        atomic.AddUint64(&b.spendClientCounter, 1),
-	}
+	ntfn := chainntnfs.NewSpendNtfn(*outpoint,
+		atomic.AddUint64(&b.spendClientCounter, 1))
 
 	select {
 	case <-b.quit:
@@ -619,56 +451,96 @@ func (b *BtcdNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint) (*chainntnfs.S
 
 	// The following conditional checks to ensure that when a spend notification
 	// is registered, the output hasn't already been spent. If the output
-	// is no longer in the UTXO set, the chain will be rescanned from the point
-	// where the output was added. The rescan will dispatch the notification.
+	// is no longer in the UTXO set, the chain will be scanned from the
+	// height hint for the spending transaction, which will dispatch the
+	// notification.
 	txout, err := b.chainConn.GetTxOut(&outpoint.Hash, outpoint.Index, true)
 	if err != nil {
 		return nil, err
 	}
 
 	if txout == nil {
-		transaction, err := b.chainConn.GetRawTransactionVerbose(&outpoint.Hash)
-		if err != nil {
+		if err := b.scanForHistoricalSpend(outpoint, heightHint); err != nil {
+			chainntnfs.Log.Errorf("Historical scan for spend of "+
+				"outpoint=%v failed: %v", outpoint, err)
 			return nil, err
 		}
+	}
 
-		blockhash, err := chainhash.NewHashFromStr(transaction.BlockHash)
-		if err != nil {
-			return nil, err
+	return ntfn.Event(func() {
+		select {
+		case b.notificationCancels <- &spendCancel{
+			op:      *outpoint,
+			spendID: ntfn.SpendID,
+		}:
+		case <-b.quit:
+			return
 		}
+	}), nil
+}
 
-		ops := []*wire.OutPoint{outpoint}
-		if err := b.chainConn.Rescan(blockhash, nil, ops); err != nil {
-			chainntnfs.Log.Errorf("Rescan for spend notification txout failed: %v", err)
-			return nil, err
+// scanForHistoricalSpend scans each block within the main chain from
+// heightHint to the current tip for a transaction spending the target
+// outpoint. If the spending transaction is found, it's handed to the
+// notification dispatcher in the same manner as a transaction notified to us
+// by btcd, triggering the dispatch of all spend notifications registered for
+// the outpoint.
+func (b *BtcdNotifier) scanForHistoricalSpend(outpoint *wire.OutPoint,
+	heightHint uint32) error {
+
+	_, currentHeight, err := b.chainConn.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	// If no height hint was provided, then we'll begin our scan from the
+	// block that included the transaction which created the output.
+	startHeight := int32(heightHint)
+	if startHeight == 0 {
+		tx, err := b.chainConn.GetRawTransactionVerbose(&outpoint.Hash)
+		if err != nil {
+			return err
 		}
+		if tx.Confirmations == 0 {
+			return nil
+		}
+
+		startHeight = currentHeight - int32(tx.Confirmations) + 1
 	}
 
-	return &chainntnfs.SpendEvent{
-		Spend: ntfn.spendChan,
-		Cancel: func() {
-			select {
-			case b.notificationCancels <- &spendCancel{
-				op:      *outpoint,
-				spendID: ntfn.spendID,
-			}:
-			case <-b.quit:
-				return
-			}
-		},
-	}, nil
-}
+	chainntnfs.Log.Infof("Scanning blocks %v through %v for spend of "+
+		"outpoint=%v", startHeight, currentHeight, outpoint)
+
+	for height := startHeight; height <= currentHeight; height++ {
+		blockHash, err := b.chainConn.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		block, err := b.chainConn.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
 
-// confirmationNotification represents a client's intent to receive a
-// notification once the target txid reaches numConfirmations confirmations.
-type confirmationsNotification struct {
-	txid *chainhash.Hash
+		for i, tx := range block.Transactions {
+			for _, txIn := range tx.TxIn {
+				if txIn.PreviousOutPoint != *outpoint {
+					continue
+				}
 
-	initialConfirmHeight uint32
-	numConfirmations     uint32
+				details := &btcjson.BlockDetails{
+					Height: height,
+					Hash:   blockHash.String(),
+					Index:  i,
+					Time:   block.Header.Timestamp.Unix(),
+				}
+				b.onRedeemingTx(btcutil.NewTx(tx), details)
 
-	finConf      chan *chainntnfs.TxConfirmation
-	negativeConf chan int32 // TODO(roasbeef): re-org funny business
+				return nil
+			}
+		}
+	}
+
+	return nil
 }
 
 // RegisterConfirmationsNtfn registers a notification with BtcdNotifier
@@ -677,21 +549,13 @@ type confirmationsNotification struct {
 func (b *BtcdNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
 
-	ntfn := &confirmationsNotification{
-		txid:             txid,
-		numConfirmations: numConfs,
-		finConf:          make(chan *chainntnfs.TxConfirmation, 1),
-		negativeConf:     make(chan int32, 1),
-	}
+	ntfn := chainntnfs.NewConfNtfn(txid, numConfs)
 
 	select {
 	case <-b.quit:
 		return nil, ErrChainNotifierShuttingDown
 	case b.notificationRegistry <- ntfn:
-		return &chainntnfs.ConfirmationEvent{
-			Confirmed:    ntfn.finConf,
-			NegativeConf: ntfn.negativeConf,
-		}, nil
+		return ntfn.Event, nil
 	}
 }
 
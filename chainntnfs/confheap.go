@@ -1,12 +1,10 @@
-package btcdnotify
-
-import "github.com/lightningnetwork/lnd/chainntnfs"
+package chainntnfs
 
 // confEntry represents an entry in the min-confirmation heap. .
 type confEntry struct {
-	*confirmationsNotification
+	*ConfNtfn
 
-	initialConfDetails *chainntnfs.TxConfirmation
+	initialConfDetails *TxConfirmation
 
 	triggerHeight uint32
 }
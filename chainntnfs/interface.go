@@ -37,9 +37,15 @@ type ChainNotifier interface {
 	// NOTE: This notifications should be triggered once the transaction is
 	// *seen* on the network, not when it has received a single confirmation.
 	//
+	// The heightHint should represent the earliest height in the chain at
+	// which the outpoint could have been spent. If the outpoint has
+	// already been spent by the time the notification is registered, the
+	// chain is scanned from this height for the spending transaction, and
+	// the notification is dispatched immediately.
+	//
 	// NOTE: Dispatching notifications to multiple clients subscribed to a
 	// spend of the same outpoint MUST be supported.
-	RegisterSpendNtfn(outpoint *wire.OutPoint) (*SpendEvent, error)
+	RegisterSpendNtfn(outpoint *wire.OutPoint, heightHint uint32) (*SpendEvent, error)
 
 	// RegisterBlockEpochNtfn registers an intent to be notified of each
 	// new block connected to the tip of the main chain. The returned
@@ -96,6 +102,13 @@ type ConfirmationEvent struct {
 	// to sync, to request another confirmation event ntfn, then re-open
 	// channel after confs.
 
+	// NegativeConf is a channel that will be sent upon if the transaction
+	// is disconnected from the main chain by a re-org. The value sent is
+	// the number of blocks disconnected at the time the transaction was
+	// removed. Afterwards, the notification is re-armed: once the
+	// transaction is re-included within the main chain and reaches the
+	// targeted number of confirmations, Confirmed will be sent upon once
+	// again.
 	NegativeConf chan int32 // MUST be buffered.
 }
 
@@ -123,6 +136,13 @@ type SpendEvent struct {
 	// target outpoint has been spent.
 	Spend <-chan *SpendDetail // MUST be buffered.
 
+	// Reorg is a receive only channel which will be sent upon if the
+	// spending transaction is disconnected from the main chain by a
+	// re-org after the spend has been dispatched. Afterwards, the
+	// notification is re-armed, so Spend will be sent upon once again
+	// when the output is next spent.
+	Reorg <-chan struct{} // MUST be buffered.
+
 	// Cancel is a closure that should be executed by the caller in the
 	// case that they wish to prematurely abandon their regsitered spend
 	// notification.
@@ -212,6 +232,23 @@ func RegisterNotifier(driver *NotifierDriver) error {
 	return nil
 }
 
+// LookupNotifier returns the registered NotifierDriver of the passed type. An
+// error is returned if no such driver has been registered.
+//
+// NOTE: This function is safe for concurrent access.
+func LookupNotifier(notifierType string) (*NotifierDriver, error) {
+	registerMtx.Lock()
+	defer registerMtx.Unlock()
+
+	driver, ok := notifiers[notifierType]
+	if !ok {
+		return nil, fmt.Errorf("notifier %v not registered",
+			notifierType)
+	}
+
+	return driver, nil
+}
+
 // SupportedNotifiers returns a slice of strings that represent the database
 // drivers that have been registered and are therefore supported.
 //
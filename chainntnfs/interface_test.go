@@ -2,13 +2,23 @@ package chainntnfs_test
 
 import (
 	"bytes"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	_ "github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
 	_ "github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
+	_ "github.com/lightningnetwork/lnd/chainntnfs/neutrinonotify"
+	"github.com/lightningnetwork/lnd/simchain"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 
 	"github.com/roasbeef/btcd/btcec"
@@ -16,7 +26,10 @@ import (
 	"github.com/roasbeef/btcd/rpctest"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcrpcclient"
 	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcwallet/walletdb"
+	_ "github.com/roasbeef/btcwallet/walletdb/bdb"
 )
 
 var (
@@ -27,14 +40,122 @@ var (
 		0x1e, 0xb, 0x4c, 0xfd, 0x9e, 0xc5, 0x8c, 0xe9,
 	}
 
-	netParams       = &chaincfg.SimNetParams
+	// We use the regression test network, as unlike the simulation
+	// network, it's supported by both btcd and bitcoind.
+	netParams       = &chaincfg.RegressionNetParams
 	privKey, pubKey = btcec.PrivKeyFromBytes(btcec.S256(), testPrivKey)
 	addrPk, _       = btcutil.NewAddressPubKey(pubKey.SerializeCompressed(),
 		netParams)
 	testAddr = addrPk.AddressPubKeyHash()
 )
 
-func getTestTxId(miner *rpctest.Harness) (*chainhash.Hash, error) {
+// chainHarness abstracts the chain watched by the notifier under test,
+// allowing the tests to mine blocks, broadcast transactions and cause re-orgs
+// on both a btcd miner, and the in-memory simulated chain.
+type chainHarness interface {
+	// SendOutputs broadcasts a new transaction creating the passed
+	// outputs.
+	SendOutputs(outputs []*wire.TxOut,
+		feeRate btcutil.Amount) (*chainhash.Hash, error)
+
+	// Generate mines numBlocks blocks, including all transactions
+	// broadcast so far.
+	Generate(numBlocks uint32) ([]*chainhash.Hash, error)
+
+	// GetBestBlock returns the hash and height of the tip of the chain.
+	GetBestBlock() (*chainhash.Hash, int32, error)
+
+	// GetRawTransaction returns the transaction identified by txid.
+	GetRawTransaction(txid *chainhash.Hash) (*btcutil.Tx, error)
+
+	// SendRawTransaction broadcasts the passed transaction.
+	SendRawTransaction(tx *wire.MsgTx,
+		allowHighFees bool) (*chainhash.Hash, error)
+
+	// Fork creates a second miner which begins synced with this one, but
+	// mines blocks independently. The returned closure tears it down.
+	Fork() (chainHarness, func(), error)
+
+	// Join syncs the miner with a forked miner, re-org'ing onto its chain
+	// if it's longer.
+	Join(fork chainHarness) error
+}
+
+// btcdHarness is a chainHarness backed by a btcd miner.
+type btcdHarness struct {
+	*rpctest.Harness
+}
+
+func (h *btcdHarness) Generate(numBlocks uint32) ([]*chainhash.Hash, error) {
+	return h.Node.Generate(numBlocks)
+}
+
+func (h *btcdHarness) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return h.Node.GetBestBlock()
+}
+
+func (h *btcdHarness) GetRawTransaction(txid *chainhash.Hash) (*btcutil.Tx, error) {
+	return h.Node.GetRawTransaction(txid)
+}
+
+func (h *btcdHarness) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	return h.Node.SendRawTransaction(tx, allowHighFees)
+}
+
+func (h *btcdHarness) Fork() (chainHarness, func(), error) {
+	miner2, err := rpctest.New(netParams, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := miner2.SetUp(false, 0); err != nil {
+		miner2.TearDown()
+		return nil, nil, err
+	}
+
+	// Sync the new miner with our chain, then disconnect the two so they
+	// mine independently.
+	if err := h.Join(&btcdHarness{miner2}); err != nil {
+		miner2.TearDown()
+		return nil, nil, err
+	}
+	err = miner2.Node.AddNode(h.P2PAddress(), btcrpcclient.ANRemove)
+	if err != nil {
+		miner2.TearDown()
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		miner2.TearDown()
+	}
+	return &btcdHarness{miner2}, cleanUp, nil
+}
+
+func (h *btcdHarness) Join(fork chainHarness) error {
+	miner2 := fork.(*btcdHarness).Harness
+	if err := rpctest.ConnectNode(miner2, h.Harness); err != nil {
+		return err
+	}
+
+	nodeSlice := []*rpctest.Harness{h.Harness, miner2}
+	return rpctest.JoinNodes(nodeSlice, rpctest.Blocks)
+}
+
+// simHarness is a chainHarness backed by the simulated chain.
+type simHarness struct {
+	*simchain.Chain
+}
+
+func (h *simHarness) Fork() (chainHarness, func(), error) {
+	return &simHarness{h.Chain.Fork()}, func() {}, nil
+}
+
+func (h *simHarness) Join(fork chainHarness) error {
+	return h.Chain.Join(fork.(*simHarness).Chain)
+}
+
+func getTestTxId(miner chainHarness) (*chainhash.Hash, error) {
 	script, err := txscript.PayToAddrScript(testAddr)
 	if err != nil {
 		return nil, err
@@ -49,7 +170,7 @@ func getTestTxId(miner *rpctest.Harness) (*chainhash.Hash, error) {
 	return miner.SendOutputs(outputs, 10)
 }
 
-func testSingleConfirmationNotification(miner *rpctest.Harness,
+func testSingleConfirmationNotification(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing single conf notification")
@@ -76,7 +197,7 @@ func testSingleConfirmationNotification(miner *rpctest.Harness,
 
 	// Now generate a single block, the transaction should be included which
 	// should trigger a notification event.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
 
@@ -93,7 +214,7 @@ func testSingleConfirmationNotification(miner *rpctest.Harness,
 	}
 }
 
-func testMultiConfirmationNotification(miner *rpctest.Harness,
+func testMultiConfirmationNotification(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing mulit-conf notification")
@@ -115,7 +236,7 @@ func testMultiConfirmationNotification(miner *rpctest.Harness,
 
 	// Now generate a six blocks. The transaction should be included in the
 	// first block, which will be built upon by the other 5 blocks.
-	if _, err := miner.Node.Generate(6); err != nil {
+	if _, err := miner.Generate(6); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
 
@@ -132,7 +253,7 @@ func testMultiConfirmationNotification(miner *rpctest.Harness,
 	}
 }
 
-func testBatchConfirmationNotification(miner *rpctest.Harness,
+func testBatchConfirmationNotification(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing batch mulit-conf notification")
@@ -175,7 +296,7 @@ func testBatchConfirmationNotification(miner *rpctest.Harness,
 
 		// Generate the number of blocks necessary to trigger this
 		// current confirmation notification.
-		if _, err := miner.Node.Generate(blocksToGen); err != nil {
+		if _, err := miner.Generate(blocksToGen); err != nil {
 			t.Fatalf("unable to generate single block: %v", err)
 		}
 
@@ -193,7 +314,7 @@ func testBatchConfirmationNotification(miner *rpctest.Harness,
 	}
 }
 
-func createSpendableOutput(miner *rpctest.Harness,
+func createSpendableOutput(miner chainHarness,
 	t *testing.T) (*wire.OutPoint, []byte) {
 
 	txid, err := getTestTxId(miner)
@@ -202,12 +323,12 @@ func createSpendableOutput(miner *rpctest.Harness,
 	}
 
 	// Mine a single block which should include that txid above.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
 
 	// Now that we have the txid, fetch the transaction itself.
-	wrappedTx, err := miner.Node.GetRawTransaction(txid)
+	wrappedTx, err := miner.GetRawTransaction(txid)
 	if err != nil {
 		t.Fatalf("unable to get new tx: %v", err)
 	}
@@ -252,7 +373,7 @@ func createSpendTx(outpoint *wire.OutPoint, pkScript []byte,
 	return spendingTx
 }
 
-func testSpendNotification(miner *rpctest.Harness,
+func testSpendNotification(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing multi-client spend notification")
@@ -267,10 +388,16 @@ func testSpendNotification(miner *rpctest.Harness,
 	// spentness notification for the newly created output with multiple
 	// clients in order to ensure the implementation can support
 	// multi-client spend notifications.
+	_, currentHeight, err := miner.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get current height: %v", err)
+	}
+
 	const numClients = 5
 	spendClients := make([]*chainntnfs.SpendEvent, numClients)
 	for i := 0; i < numClients; i++ {
-		spentIntent, err := notifier.RegisterSpendNtfn(outpoint)
+		spentIntent, err := notifier.RegisterSpendNtfn(outpoint,
+			uint32(currentHeight))
 		if err != nil {
 			t.Fatalf("unable to register for spend ntfn: %v", err)
 		}
@@ -282,14 +409,14 @@ func testSpendNotification(miner *rpctest.Harness,
 	spendingTx := createSpendTx(outpoint, pkScript, t)
 
 	// Broadcast our spending transaction.
-	spenderSha, err := miner.Node.SendRawTransaction(spendingTx, true)
+	spenderSha, err := miner.SendRawTransaction(spendingTx, true)
 	if err != nil {
 		t.Fatalf("unable to brodacst tx: %v", err)
 	}
 
 	// Now we mine a single block, which should include our spend. The
 	// notification should also be sent off.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
 
@@ -329,7 +456,7 @@ func testSpendNotification(miner *rpctest.Harness,
 	}
 }
 
-func testBlockEpochNotification(miner *rpctest.Harness,
+func testBlockEpochNotification(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing block epoch notification")
@@ -368,7 +495,7 @@ func testBlockEpochNotification(miner *rpctest.Harness,
 
 	// Now generate 10 blocks, the clients above should each receive 10
 	// notifications, thereby unblocking the goroutine above.
-	if _, err := miner.Node.Generate(numBlocks); err != nil {
+	if _, err := miner.Generate(numBlocks); err != nil {
 		t.Fatalf("unable to generate blocks: %v", err)
 	}
 
@@ -379,7 +506,7 @@ func testBlockEpochNotification(miner *rpctest.Harness,
 	}
 }
 
-func testMultiClientConfirmationNotification(miner *rpctest.Harness,
+func testMultiClientConfirmationNotification(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing multi-client multi-conf notification")
@@ -421,7 +548,7 @@ func testMultiClientConfirmationNotification(miner *rpctest.Harness,
 
 	// Finally, generate a single block which should trigger the unblocking
 	// of all numConfsClients blocked on the channel read above.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate block: %v", err)
 	}
 
@@ -435,7 +562,7 @@ func testMultiClientConfirmationNotification(miner *rpctest.Harness,
 // Tests the case in which a confirmation notification is requested for a
 // transaction that has already been included in a block. In this case, the
 // confirmation notification should be dispatched immediately.
-func testTxConfirmedBeforeNtfnRegistration(miner *rpctest.Harness,
+func testTxConfirmedBeforeNtfnRegistration(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing transaction confirmed before notification registration")
@@ -453,7 +580,7 @@ func testTxConfirmedBeforeNtfnRegistration(miner *rpctest.Harness,
 	// the confirmation event is registered below to ensure that the TXID
 	// hasn't already been included in the chain, otherwise the
 	// notification will never be sent.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate two blocks: %v", err)
 	}
 
@@ -490,7 +617,7 @@ func testTxConfirmedBeforeNtfnRegistration(miner *rpctest.Harness,
 	numConfs = 6
 
 	// First, generate 2 confirmations.
-	if _, err := miner.Node.Generate(2); err != nil {
+	if _, err := miner.Generate(2); err != nil {
 		t.Fatalf("unable to generate blocks: %v", err)
 	}
 
@@ -503,7 +630,7 @@ func testTxConfirmedBeforeNtfnRegistration(miner *rpctest.Harness,
 
 	// With the notification registered, generate another 4 blocks, this
 	// should dispatch the notification.
-	if _, err := miner.Node.Generate(4); err != nil {
+	if _, err := miner.Generate(4); err != nil {
 		t.Fatalf("unable to generate blocks: %v", err)
 	}
 
@@ -523,7 +650,7 @@ func testTxConfirmedBeforeNtfnRegistration(miner *rpctest.Harness,
 // Tests the case in which a spend notification is requested for a spend that
 // has already been included in a block. In this case, the spend notification
 // should be dispatched immediately.
-func testSpendBeforeNtfnRegistration(miner *rpctest.Harness,
+func testSpendBeforeNtfnRegistration(miner chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	t.Logf("testing spend broadcast before notification registration")
@@ -538,12 +665,12 @@ func testSpendBeforeNtfnRegistration(miner *rpctest.Harness,
 	}
 
 	// Mine a single block which should include that txid above.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
 
 	// Now that we have the txid, fetch the transaction itself.
-	wrappedTx, err := miner.Node.GetRawTransaction(txid)
+	wrappedTx, err := miner.GetRawTransaction(txid)
 	if err != nil {
 		t.Fatalf("unable to get new tx: %v", err)
 	}
@@ -584,21 +711,34 @@ func testSpendBeforeNtfnRegistration(miner *rpctest.Harness,
 	}
 	spendingTx.TxIn[0].SignatureScript = sigScript
 
+	// Before broadcasting the spending transaction, we'll note the
+	// current height, as the output can't have been spent before it.
+	_, heightHint, err := miner.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get current height: %v", err)
+	}
+
 	// Broadcast our spending transaction.
-	spenderSha, err := miner.Node.SendRawTransaction(spendingTx, true)
+	spenderSha, err := miner.SendRawTransaction(spendingTx, true)
 	if err != nil {
 		t.Fatalf("unable to brodacst tx: %v", err)
 	}
 
-	// Now we mine an additional block, which should include our spend.
-	if _, err := miner.Node.Generate(1); err != nil {
+	// Now we mine an additional block, which should include our spend,
+	// followed by a few more blocks in order to bury the spend within the
+	// chain.
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
+	if _, err := miner.Generate(5); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
 
 	// Now, we register to be notified of a spend that has already
-	// happened.  The notifier should dispatch a spend notification
-	// immediately.
-	spentIntent, err := notifier.RegisterSpendNtfn(outpoint)
+	// happened, scanning the chain from our height hint.  The notifier
+	// should dispatch a spend notification immediately.
+	spentIntent, err := notifier.RegisterSpendNtfn(outpoint,
+		uint32(heightHint))
 	if err != nil {
 		t.Fatalf("unable to register for spend ntfn: %v", err)
 	}
@@ -624,12 +764,16 @@ func testSpendBeforeNtfnRegistration(miner *rpctest.Harness,
 			t.Fatalf("ntfn includes wrong spending input index, reports %v, should be %v",
 				ntfn.SpenderInputIndex, 0)
 		}
+		if ntfn.SpendingHeight != heightHint+1 {
+			t.Fatalf("ntfn includes wrong spending height, reports %v, should be %v",
+				ntfn.SpendingHeight, heightHint+1)
+		}
 	case <-time.After(2 * time.Second):
 		t.Fatalf("spend ntfn never received")
 	}
 }
 
-func testCancelSpendNtfn(node *rpctest.Harness,
+func testCancelSpendNtfn(node chainHarness,
 	notifier chainntnfs.ChainNotifier, t *testing.T) {
 
 	// We'd like to test that once a spend notification is registered, it
@@ -642,10 +786,16 @@ func testCancelSpendNtfn(node *rpctest.Harness,
 	// Create two clients that each registered to the spend notification.
 	// We'll cancel the notification for the first client and leave the
 	// notification for the second client enabled.
+	_, currentHeight, err := node.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get current height: %v", err)
+	}
+
 	const numClients = 2
 	spendClients := make([]*chainntnfs.SpendEvent, numClients)
 	for i := 0; i < numClients; i++ {
-		spentIntent, err := notifier.RegisterSpendNtfn(outpoint)
+		spentIntent, err := notifier.RegisterSpendNtfn(outpoint,
+			uint32(currentHeight))
 		if err != nil {
 			t.Fatalf("unable to register for spend ntfn: %v", err)
 		}
@@ -661,14 +811,14 @@ func testCancelSpendNtfn(node *rpctest.Harness,
 	spendClients[1].Cancel()
 
 	// Broadcast our spending transaction.
-	spenderSha, err := node.Node.SendRawTransaction(spendingTx, true)
+	spenderSha, err := node.SendRawTransaction(spendingTx, true)
 	if err != nil {
 		t.Fatalf("unable to brodacst tx: %v", err)
 	}
 
 	// Now we mine a single block, which should include our spend. The
 	// notification should also be sent off.
-	if _, err := node.Node.Generate(1); err != nil {
+	if _, err := node.Generate(1); err != nil {
 		t.Fatalf("unable to generate single block: %v", err)
 	}
 
@@ -709,7 +859,7 @@ func testCancelSpendNtfn(node *rpctest.Harness,
 	}
 }
 
-func testCancelEpochNtfn(node *rpctest.Harness, notifier chainntnfs.ChainNotifier,
+func testCancelEpochNtfn(node chainHarness, notifier chainntnfs.ChainNotifier,
 	t *testing.T) {
 
 	// We'd like to ensure that once a client cancels their block epoch
@@ -732,7 +882,7 @@ func testCancelEpochNtfn(node *rpctest.Harness, notifier chainntnfs.ChainNotifie
 
 	// Now mine a single block, this should trigger the logic to dispatch
 	// epoch notifications.
-	if _, err := node.Node.Generate(1); err != nil {
+	if _, err := node.Generate(1); err != nil {
 		t.Fatalf("unable to generate blocks: %v", err)
 	}
 
@@ -759,7 +909,220 @@ func testCancelEpochNtfn(node *rpctest.Harness, notifier chainntnfs.ChainNotifie
 	}
 }
 
-var ntfnTests = []func(node *rpctest.Harness, notifier chainntnfs.ChainNotifier, t *testing.T){
+func testReorgConfNotification(miner chainHarness,
+	notifier chainntnfs.ChainNotifier, t *testing.T) {
+
+	t.Logf("testing re-org of confirmed transaction")
+
+	// In order to cause a re-org, we'll fork the chain, creating a second
+	// miner which begins synced with the main miner, but mines blocks
+	// independently of it.
+	miner2, cleanUp, err := miner.Fork()
+	if err != nil {
+		t.Fatalf("unable to fork chain: %v", err)
+	}
+	defer cleanUp()
+
+	// With the miners disconnected, we'll create a transaction which is
+	// only known to the main miner, and register for its confirmation.
+	txid, err := getTestTxId(miner)
+	if err != nil {
+		t.Fatalf("unable to create test tx: %v", err)
+	}
+	confIntent, err := notifier.RegisterConfirmationsNtfn(txid, 1)
+	if err != nil {
+		t.Fatalf("unable to register ntfn: %v", err)
+	}
+
+	// Mining a single block should confirm the transaction.
+	if _, err := miner.Generate(1); err != nil {
+		t.Fatalf("unable to generate single block: %v", err)
+	}
+	select {
+	case <-confIntent.Confirmed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("confirmation notification never received")
+	}
+
+	// Next, the second miner will mine a longer chain which doesn't
+	// include the transaction. Once the second miner's chain is joined
+	// back, the main miner will re-org onto it, disconnecting the block
+	// which confirmed the transaction.
+	if _, err := miner2.Generate(2); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	if err := miner.Join(miner2); err != nil {
+		t.Fatalf("unable to join miners: %v", err)
+	}
+
+	// The notifier should signal the re-org with a depth of one, as only
+	// the block confirming the transaction was disconnected.
+	select {
+	case depth := <-confIntent.NegativeConf:
+		if depth != 1 {
+			t.Fatalf("incorrect re-org depth: expected 1, got %v",
+				depth)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("negative confirmation notification never received")
+	}
+
+	// The transaction should have been returned to the main miner's
+	// mempool, so mining another block should re-confirm it, triggering
+	// the re-armed notification.
+	if _, err := miner.Generate(1); err != nil {
+		t.Fatalf("unable to generate single block: %v", err)
+	}
+	select {
+	case <-confIntent.Confirmed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("re-armed confirmation notification never received")
+	}
+}
+
+// launchBitcoind starts a bitcoind node on the regression test network which
+// syncs the chain of the passed miner, and publishes new blocks and
+// transactions over ZMQ. The RPC config and ZMQ address of the node are
+// returned, along with a function which stops the node and removes its data.
+func launchBitcoind(miner *rpctest.Harness,
+	t *testing.T) (*btcrpcclient.ConnConfig, string, func()) {
+
+	tempBitcoindDir, err := ioutil.TempDir("", "bitcoind")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
+	}
+
+	rpcPort := rand.Intn(65000-1024) + 1024
+	zmqPath := fmt.Sprintf("tcp://127.0.0.1:%d", rpcPort+1)
+	bitcoind := exec.Command(
+		"bitcoind",
+		"-datadir="+tempBitcoindDir,
+		"-regtest",
+		"-txindex",
+		"-disablewallet",
+		"-whitelist=127.0.0.1",
+		"-connect="+miner.P2PAddress(),
+		"-rpcuser=weks",
+		"-rpcpassword=weks",
+		fmt.Sprintf("-rpcport=%d", rpcPort),
+		"-zmqpubhashblock="+zmqPath,
+		"-zmqpubrawtx="+zmqPath,
+	)
+	if err := bitcoind.Start(); err != nil {
+		os.RemoveAll(tempBitcoindDir)
+		t.Fatalf("unable to start bitcoind: %v", err)
+	}
+	cleanUp := func() {
+		bitcoind.Process.Kill()
+		bitcoind.Wait()
+		os.RemoveAll(tempBitcoindDir)
+	}
+
+	rpcConfig := &btcrpcclient.ConnConfig{
+		Host:         fmt.Sprintf("127.0.0.1:%d", rpcPort),
+		User:         "weks",
+		Pass:         "weks",
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}
+	rpcClient, err := btcrpcclient.New(rpcConfig, nil)
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create rpc client: %v", err)
+	}
+	defer rpcClient.Shutdown()
+
+	// Before handing the node off, we'll wait for it to sync up to the
+	// tip of the miner's chain.
+	_, minerHeight, err := miner.Node.GetBestBlock()
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to get miner's height: %v", err)
+	}
+	timeout := time.After(30 * time.Second)
+	for {
+		height, err := rpcClient.GetBlockCount()
+		if err == nil && height >= int64(minerHeight) {
+			break
+		}
+
+		select {
+		case <-timeout:
+			cleanUp()
+			t.Fatalf("bitcoind never synced to height %v",
+				minerHeight)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return rpcConfig, zmqPath, cleanUp
+}
+
+// launchNeutrino starts a neutrino light client which syncs the headers and
+// compact filters of the passed miner's chain. The light client is returned
+// along with a function which stops it and removes its data.
+func launchNeutrino(miner *rpctest.Harness,
+	t *testing.T) (*neutrino.ChainService, func()) {
+
+	spvDir, err := ioutil.TempDir("", "neutrino")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
+	}
+
+	dbName := filepath.Join(spvDir, "neutrino.db")
+	spvDatabase, err := walletdb.Create("bdb", dbName)
+	if err != nil {
+		os.RemoveAll(spvDir)
+		t.Fatalf("unable to create walletdb: %v", err)
+	}
+
+	spvConfig := neutrino.Config{
+		DataDir:      spvDir,
+		Database:     spvDatabase,
+		ChainParams:  *netParams,
+		ConnectPeers: []string{miner.P2PAddress()},
+	}
+	spvNode, err := neutrino.NewChainService(spvConfig)
+	if err != nil {
+		spvDatabase.Close()
+		os.RemoveAll(spvDir)
+		t.Fatalf("unable to create neutrino: %v", err)
+	}
+	spvNode.Start()
+
+	cleanUp := func() {
+		spvNode.Stop()
+		spvDatabase.Close()
+		os.RemoveAll(spvDir)
+	}
+
+	// Before handing the light client off, we'll wait for it to sync up
+	// to the tip of the miner's chain.
+	_, minerHeight, err := miner.Node.GetBestBlock()
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to get miner's height: %v", err)
+	}
+	timeout := time.After(30 * time.Second)
+	for {
+		bestBlock, err := spvNode.BestSnapshot()
+		if err == nil && bestBlock.Height >= minerHeight {
+			break
+		}
+
+		select {
+		case <-timeout:
+			cleanUp()
+			t.Fatalf("neutrino never synced to height %v",
+				minerHeight)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return spvNode, cleanUp
+}
+
+var ntfnTests = []func(node chainHarness, notifier chainntnfs.ChainNotifier, t *testing.T){
 	testSingleConfirmationNotification,
 	testMultiConfirmationNotification,
 	testBatchConfirmationNotification,
@@ -770,6 +1133,24 @@ var ntfnTests = []func(node *rpctest.Harness, notifier chainntnfs.ChainNotifier,
 	testSpendBeforeNtfnRegistration,
 	testCancelSpendNtfn,
 	testCancelEpochNtfn,
+	testReorgConfNotification,
+}
+
+// setUpMiner initializes the harness around a btcd node which will serve as
+// our dedicated miner to generate blocks, cause re-orgs, etc. We'll set up
+// this node with a chain length of 125, so we have plentyyy of BTC to play
+// around with.
+func setUpMiner(t *testing.T) *rpctest.Harness {
+	miner, err := rpctest.New(netParams, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create mining node: %v", err)
+	}
+	if err := miner.SetUp(true, 25); err != nil {
+		miner.TearDown()
+		t.Fatalf("unable to set up mining node: %v", err)
+	}
+
+	return miner
 }
 
 // TestInterfaces tests all registered interfaces with a unified set of tests
@@ -784,33 +1165,84 @@ var ntfnTests = []func(node *rpctest.Harness, notifier chainntnfs.ChainNotifier,
 // the interface. Second, an additional case in the switch within the main loop
 // below needs to be added which properly initializes the interface.
 func TestInterfaces(t *testing.T) {
-	// Initialize the harness around a btcd node which will serve as our
-	// dedicated miner to generate blocks, cause re-orgs, etc. We'll set up
-	// this node with a chain length of 125, so we have plentyyy of BTC to
-	// play around with.
-	miner, err := rpctest.New(netParams, nil, nil)
-	if err != nil {
-		t.Fatalf("unable to create mining node: %v", err)
-	}
-	defer miner.TearDown()
-	if err := miner.SetUp(true, 25); err != nil {
-		t.Fatalf("unable to set up mining node: %v", err)
+	// The btcd node which serves as the dedicated miner of the drivers
+	// watching a real chain is only set up once the first such driver is
+	// tested, so the drivers watching a chain of their own can be tested
+	// without btcd.
+	var miner *rpctest.Harness
+	getMiner := func() *rpctest.Harness {
+		if miner == nil {
+			miner = setUpMiner(t)
+		}
+		return miner
 	}
-
-	rpcConfig := miner.RPCConfig()
+	defer func() {
+		if miner != nil {
+			miner.TearDown()
+		}
+	}()
 
 	log.Printf("Running %v ChainNotifier interface tests\n", len(ntfnTests))
-	var notifier chainntnfs.ChainNotifier
+	var (
+		notifier chainntnfs.ChainNotifier
+		harness  chainHarness
+		err      error
+	)
 	for _, notifierDriver := range chainntnfs.RegisteredNotifiers() {
 		notifierType := notifierDriver.NotifierType
 
 		switch notifierType {
 		case "btcd":
+			harness = &btcdHarness{getMiner()}
+
+			rpcConfig := getMiner().RPCConfig()
 			notifier, err = notifierDriver.New(&rpcConfig)
 			if err != nil {
 				t.Fatalf("unable to create %v notifier: %v",
 					notifierType, err)
 			}
+		case "bitcoind":
+			if _, err := exec.LookPath("bitcoind"); err != nil {
+				t.Logf("bitcoind not found, skipping %v notifier",
+					notifierType)
+				continue
+			}
+
+			harness = &btcdHarness{getMiner()}
+
+			bitcoindConfig, zmqPath, cleanUp := launchBitcoind(
+				getMiner(), t)
+			defer cleanUp()
+
+			notifier, err = notifierDriver.New(bitcoindConfig, zmqPath)
+			if err != nil {
+				t.Fatalf("unable to create %v notifier: %v",
+					notifierType, err)
+			}
+		case "neutrino":
+			harness = &btcdHarness{getMiner()}
+
+			spvNode, cleanUp := launchNeutrino(getMiner(), t)
+			defer cleanUp()
+
+			notifier, err = notifierDriver.New(spvNode)
+			if err != nil {
+				t.Fatalf("unable to create %v notifier: %v",
+					notifierType, err)
+			}
+		case "simchain":
+			// The simulated chain is both the notifier, and the
+			// chain the tests drive.
+			notifier, err = notifierDriver.New(netParams)
+			if err != nil {
+				t.Fatalf("unable to create %v notifier: %v",
+					notifierType, err)
+			}
+			harness = &simHarness{notifier.(*simchain.Chain)}
+		default:
+			t.Logf("no harness known for %v notifier, skipping",
+				notifierType)
+			continue
 		}
 
 		if err := notifier.Start(); err != nil {
@@ -818,8 +1250,10 @@ func TestInterfaces(t *testing.T) {
 				notifierType, err)
 		}
 
+		t.Logf("Running ChainNotifier interface tests for %v",
+			notifierType)
 		for _, ntfnTest := range ntfnTests {
-			ntfnTest(miner, notifier, t)
+			ntfnTest(harness, notifier, t)
 		}
 
 		notifier.Stop()
@@ -0,0 +1,617 @@
+package neutrinonotify
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightninglabs/neutrino"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcrpcclient"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/gcs/builder"
+	"github.com/roasbeef/btcwallet/waddrmgr"
+)
+
+const (
+
+	// notifierType uniquely identifies this concrete implementation of the
+	// ChainNotifier interface.
+	notifierType = "neutrino"
+
+	// reorgSafetyLimit is the number of recent blocks for which the
+	// notifier tracks dispatched notifications. If a block within this
+	// window is disconnected from the main chain, the notifications for
+	// transactions included within it are re-armed.
+	reorgSafetyLimit = 100
+
+	// historicalConfDepth is the number of recent blocks which are
+	// searched for a transaction that may have been confirmed before a
+	// confirmation notification for it was registered. As light clients
+	// lack a transaction index, a transaction confirmed deeper than this
+	// within the chain won't be found.
+	historicalConfDepth = reorgSafetyLimit
+)
+
+var (
+	// ErrChainNotifierShuttingDown is used when we are trying to
+	// measure a spend notification when notifier is already stopped.
+	ErrChainNotifierShuttingDown = errors.New("chainntnfs: system interrupt " +
+		"while attempting to register for spend notification.")
+)
+
+// chainUpdate encapsulates an update to the current main chain. This struct is
+// used as an element within an unbounded queue in order to avoid blocking the
+// rescan's notification handlers.
+type chainUpdate struct {
+	blockHash   *chainhash.Hash
+	blockHeight int32
+
+	// connect is true if the block was connected to the main chain, and
+	// false if it was disconnected.
+	connect bool
+
+	// matched is true if the block's filter matched one of the
+	// transactions or outpoints we're watching. Only then is the full
+	// block fetched from the network.
+	matched bool
+}
+
+// txUpdate encapsulates a spending transaction found while scanning the
+// historical chain. This struct is used as an element within an unbounded
+// queue in order to avoid blocking the scan.
+type txUpdate struct {
+	tx *btcutil.Tx
+
+	// height is the height of the block which included the transaction.
+	height int32
+}
+
+// NeutrinoNotifier implements the ChainNotifier interface using a neutrino
+// light client. Rather than relying on a full node, the compact filter of
+// each new block is fetched from the client's peers and matched against the
+// transactions and outpoints we're watching, with the full block only being
+// downloaded on a match. Multiple concurrent clients are supported. All
+// notifications are achieved via non-blocking sends on client channels.
+type NeutrinoNotifier struct {
+	spendClientCounter uint64 // To be used atomically.
+	epochClientCounter uint64 // To be used atomically.
+
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	p2pNode   *neutrino.ChainService
+	chainView neutrino.Rescan
+
+	notificationCancels  chan interface{}
+	notificationRegistry chan interface{}
+
+	// txNotifier tracks the registered confirmation and spend
+	// notifications, dispatching them as blocks are connected and
+	// disconnected.
+	//
+	// NOTE: This MUST only be accessed from the notificationDispatcher
+	// goroutine once the notifier has been started.
+	txNotifier *chainntnfs.TxNotifier
+
+	blockEpochClients map[uint64]chan *chainntnfs.BlockEpoch
+
+	chainUpdates      []*chainUpdate
+	chainUpdateSignal chan struct{}
+	chainUpdateMtx    sync.Mutex
+
+	txUpdates      []*txUpdate
+	txUpdateSignal chan struct{}
+	txUpdateMtx    sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Ensure NeutrinoNotifier implements the ChainNotifier interface at compile time.
+var _ chainntnfs.ChainNotifier = (*NeutrinoNotifier)(nil)
+
+// New returns a new NeutrinoNotifier instance. This function assumes the
+// passed neutrino ChainService has already been started, and is connected to
+// peers which serve compact filters.
+func New(node *neutrino.ChainService) (*NeutrinoNotifier, error) {
+	notifier := &NeutrinoNotifier{
+		p2pNode: node,
+
+		notificationCancels:  make(chan interface{}),
+		notificationRegistry: make(chan interface{}),
+
+		blockEpochClients: make(map[uint64]chan *chainntnfs.BlockEpoch),
+
+		chainUpdateSignal: make(chan struct{}),
+		txUpdateSignal:    make(chan struct{}),
+
+		quit: make(chan struct{}),
+	}
+
+	return notifier, nil
+}
+
+// Start launches a rescan from the current tip of the light client's chain,
+// which notifies us of each new block along with the transactions within it
+// matching our watch list, and finally launches all related helper
+// goroutines.
+func (n *NeutrinoNotifier) Start() error {
+	// Already started?
+	if atomic.AddInt32(&n.started, 1) != 1 {
+		return nil
+	}
+
+	bestBlock, err := n.p2pNode.BestSnapshot()
+	if err != nil {
+		return err
+	}
+
+	rescanOptions := []neutrino.RescanOption{
+		neutrino.StartBlock(bestBlock),
+		neutrino.QuitChan(n.quit),
+		neutrino.NotificationHandlers(btcrpcclient.NotificationHandlers{
+			OnFilteredBlockConnected:    n.onFilteredBlockConnected,
+			OnFilteredBlockDisconnected: n.onFilteredBlockDisconnected,
+		}),
+	}
+	n.chainView = n.p2pNode.NewRescan(rescanOptions...)
+	n.chainView.Start()
+
+	// The outpoints of re-armed spend notifications remain within the
+	// rescan's watch list, so there's no need to re-register them.
+	n.txNotifier = chainntnfs.NewTxNotifier(bestBlock.Height,
+		reorgSafetyLimit, nil)
+
+	n.wg.Add(1)
+	go n.notificationDispatcher()
+
+	return nil
+}
+
+// Stop shutsdown the NeutrinoNotifier.
+func (n *NeutrinoNotifier) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&n.stopped, 1) != 1 {
+		return nil
+	}
+
+	// Closing the quit channel also terminates the rescan, which we'll
+	// wait to exit along with our own goroutines.
+	close(n.quit)
+	n.chainView.WaitForShutdown()
+	n.wg.Wait()
+
+	// Notify all pending clients of our shutdown by closing the related
+	// notification channels.
+	n.txNotifier.TearDown()
+	for _, epochClient := range n.blockEpochClients {
+		close(epochClient)
+	}
+
+	return nil
+}
+
+// onFilteredBlockConnected implements the OnFilteredBlockConnected callback
+// of the rescan. The passed transactions are those within the block matching
+// our watch list.
+func (n *NeutrinoNotifier) onFilteredBlockConnected(height int32,
+	header *wire.BlockHeader, txns []*btcutil.Tx) {
+
+	blockHash := header.BlockHash()
+
+	// Append this new chain update to the end of the queue of new chain
+	// updates.
+	n.chainUpdateMtx.Lock()
+	n.chainUpdates = append(n.chainUpdates, &chainUpdate{
+		blockHash:   &blockHash,
+		blockHeight: height,
+		connect:     true,
+		matched:     len(txns) != 0,
+	})
+	n.chainUpdateMtx.Unlock()
+
+	// Launch a goroutine to signal the notification dispatcher that a new
+	// block update is available. We do this in a new goroutine in order to
+	// avoid blocking the rescan.
+	go func() {
+		n.chainUpdateSignal <- struct{}{}
+	}()
+}
+
+// onFilteredBlockDisconnected implements the OnFilteredBlockDisconnected
+// callback of the rescan. Disconnected blocks are placed within the same
+// queue as connected blocks in order to ensure the notification dispatcher
+// processes a re-org in order.
+func (n *NeutrinoNotifier) onFilteredBlockDisconnected(height int32,
+	header *wire.BlockHeader) {
+
+	blockHash := header.BlockHash()
+
+	n.chainUpdateMtx.Lock()
+	n.chainUpdates = append(n.chainUpdates, &chainUpdate{
+		blockHash:   &blockHash,
+		blockHeight: height,
+	})
+	n.chainUpdateMtx.Unlock()
+
+	go func() {
+		n.chainUpdateSignal <- struct{}{}
+	}()
+}
+
+// onRelevantTx queues a spending transaction found within the historical
+// chain for the notification dispatcher.
+func (n *NeutrinoNotifier) onRelevantTx(tx *btcutil.Tx, height int32) {
+	n.txUpdateMtx.Lock()
+	n.txUpdates = append(n.txUpdates, &txUpdate{tx, height})
+	n.txUpdateMtx.Unlock()
+
+	go func() {
+		n.txUpdateSignal <- struct{}{}
+	}()
+}
+
+// notificationDispatcher is the primary goroutine which handles client
+// notification registrations, as well as notification dispatches.
+func (n *NeutrinoNotifier) notificationDispatcher() {
+out:
+	for {
+		select {
+		case cancelMsg := <-n.notificationCancels:
+			switch msg := cancelMsg.(type) {
+			case *spendCancel:
+				n.txNotifier.CancelSpend(msg.op, msg.spendID)
+			case *epochCancel:
+				chainntnfs.Log.Infof("Cancelling epoch "+
+					"notification, epoch_id=%v", msg.epochID)
+
+				close(n.blockEpochClients[msg.epochID])
+				delete(n.blockEpochClients, msg.epochID)
+			}
+		case registerMsg := <-n.notificationRegistry:
+			switch msg := registerMsg.(type) {
+			case *chainntnfs.SpendNtfn:
+				n.txNotifier.RegisterSpend(msg)
+			case *chainntnfs.ConfNtfn:
+				// If the transaction has already been confirmed,
+				// then its confirmation details are passed along
+				// so the notification can be partially or fully
+				// dispatched immediately.
+				confDetails := n.historicalConfDetails(msg)
+				n.txNotifier.RegisterConf(msg, confDetails)
+			case *blockEpochRegistration:
+				chainntnfs.Log.Infof("New block epoch subscription")
+				n.blockEpochClients[msg.epochID] = msg.epochChan
+			}
+
+		case <-n.chainUpdateSignal:
+			// A new update is available, so pop the new chain
+			// update from the front of the update queue.
+			n.chainUpdateMtx.Lock()
+			update := n.chainUpdates[0]
+			n.chainUpdates[0] = nil // Set to nil to prevent GC leak.
+			n.chainUpdates = n.chainUpdates[1:]
+			n.chainUpdateMtx.Unlock()
+
+			// If the block has been disconnected from the main
+			// chain, then we re-arm any notifications for
+			// transactions that were included within it.
+			if !update.connect {
+				n.txNotifier.DisconnectTip(update.blockHash,
+					update.blockHeight)
+				continue
+			}
+
+			// We'll only download the full block if its filter
+			// matched one of the transactions or outpoints we're
+			// watching.
+			var newBlock *btcutil.Block
+			if update.matched {
+				var err error
+				newBlock, err = n.p2pNode.GetBlockFromNetwork(
+					*update.blockHash)
+				if err != nil || newBlock == nil {
+					chainntnfs.Log.Errorf("Unable to get "+
+						"block: %v", err)
+					continue
+				}
+			}
+
+			chainntnfs.Log.Infof("New block: height=%v, sha=%v",
+				update.blockHeight, update.blockHash)
+
+			n.notifyBlockEpochs(update.blockHeight,
+				update.blockHash)
+
+			// Dispatch any confirmation and spend notifications
+			// triggered by the transactions within the new block.
+			var txns []*wire.MsgTx
+			if newBlock != nil {
+				txns = newBlock.MsgBlock().Transactions
+			}
+			n.txNotifier.ConnectTip(update.blockHash,
+				update.blockHeight, txns)
+
+		case <-n.txUpdateSignal:
+			// A new update is available, so pop the new chain
+			// update from the front of the update queue.
+			n.txUpdateMtx.Lock()
+			newSpend := n.txUpdates[0]
+			n.txUpdates[0] = nil // Set to nil to prevent GC leak.
+			n.txUpdates = n.txUpdates[1:]
+			n.txUpdateMtx.Unlock()
+
+			n.txNotifier.ProcessTx(newSpend.tx.MsgTx(), newSpend.height)
+
+		case <-n.quit:
+			break out
+		}
+	}
+	n.wg.Done()
+}
+
+// historicalConfDetails uses historical information to determine whether the
+// target transaction of the passed notification has already been confirmed,
+// in which case the details of its confirmation are returned. Otherwise, nil
+// is returned.
+func (n *NeutrinoNotifier) historicalConfDetails(
+	msg *chainntnfs.ConfNtfn) *chainntnfs.TxConfirmation {
+
+	chainntnfs.Log.Infof("Attempting to trigger dispatch for %v from "+
+		"historical chain", msg.TxID)
+
+	// If the transaction already has some or all of the confirmations,
+	// then we may be able to dispatch it immediately. Lacking a
+	// transaction index, we'll search the filters of the most recent
+	// blocks for the transaction.
+	blockHash, blockHeight, txIndex, err := n.findHistoricalTx(msg.TxID,
+		n.txNotifier.BestHeight())
+	if err != nil {
+		chainntnfs.Log.Errorf("unable to search historical chain for "+
+			"txid=%v: %v", msg.TxID, err)
+		return nil
+	}
+	if blockHash == nil {
+		return nil
+	}
+
+	return &chainntnfs.TxConfirmation{
+		BlockHash:   blockHash,
+		BlockHeight: uint32(blockHeight),
+		TxIndex:     txIndex,
+	}
+}
+
+// findHistoricalTx searches the historicalConfDepth most recent blocks of the
+// main chain for the target transaction, matching the txid against each
+// block's extended compact filter. The full block is only downloaded if its
+// filter matches. If found, the hash and height of the block which included
+// the transaction are returned, along with its index within the block.
+// Otherwise, a nil block hash is returned.
+func (n *NeutrinoNotifier) findHistoricalTx(txid *chainhash.Hash,
+	currentHeight int32) (*chainhash.Hash, int32, uint32, error) {
+
+	for height := currentHeight; height > 0 &&
+		height > currentHeight-historicalConfDepth; height-- {
+
+		header, err := n.p2pNode.GetBlockByHeight(uint32(height))
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		blockHash := header.BlockHash()
+
+		filter, err := n.p2pNode.GetCFilter(blockHash, true)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if filter == nil {
+			continue
+		}
+
+		key := builder.DeriveKey(&blockHash)
+		match, err := filter.Match(key, txid[:])
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if !match {
+			continue
+		}
+
+		// As filters may produce false positives, we'll fetch the
+		// block itself to confirm it includes the transaction, and
+		// to locate the transaction's index within the block.
+		block, err := n.p2pNode.GetBlockFromNetwork(blockHash)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for i, tx := range block.MsgBlock().Transactions {
+			h := tx.TxHash()
+			if txid.IsEqual(&h) {
+				return &blockHash, height, uint32(i), nil
+			}
+		}
+	}
+
+	return nil, 0, 0, nil
+}
+
+// notifyBlockEpochs notifies all registered block epoch clients of the newly
+// connected block to the main chain.
+func (n *NeutrinoNotifier) notifyBlockEpochs(newHeight int32, newSha *chainhash.Hash) {
+	epoch := &chainntnfs.BlockEpoch{
+		Height: newHeight,
+		Hash:   newSha,
+	}
+
+	for _, epochChan := range n.blockEpochClients {
+		n.wg.Add(1)
+		go func(ntfnChan chan *chainntnfs.BlockEpoch) {
+			defer n.wg.Done()
+
+			select {
+			case ntfnChan <- epoch:
+			case <-n.quit:
+				return
+			}
+		}(epochChan)
+	}
+}
+
+// spendCancel is a message sent to the NeutrinoNotifier when a client wishes to
+// cancel an outstanding spend notification that has yet to be dispatched.
+type spendCancel struct {
+	// op is the target outpoint of the notification to be cancelled.
+	op wire.OutPoint
+
+	// spendID the ID of the notification to cancel.
+	spendID uint64
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the target
+// outpoint has been spent by a transaction on-chain. Once a spend of the target
+// outpoint has been detected, the details of the spending event will be sent
+// across the 'Spend' channel. If the outpoint has already been spent, the
+// chain is scanned for the spending transaction starting from heightHint.
+func (n *NeutrinoNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	ntfn := chainntnfs.NewSpendNtfn(*outpoint,
+		atomic.AddUint64(&n.spendClientCounter, 1))
+
+	select {
+	case <-n.quit:
+		return nil, ErrChainNotifierShuttingDown
+	case n.notificationRegistry <- ntfn:
+	}
+
+	// With the notification registered, we'll add the outpoint to the
+	// watch list of the rescan, so that we're notified of any blocks which
+	// spend it.
+	err := n.chainView.Update(neutrino.AddOutPoints(*outpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	// As a light client has no view of the UTXO set, we'll always scan
+	// the chain from the height hint for a spend of the outpoint that
+	// may have occurred before the notification was registered. This may
+	// take some time, so it's done in the background.
+	n.wg.Add(1)
+	go n.scanForHistoricalSpend(outpoint, heightHint)
+
+	return ntfn.Event(func() {
+		select {
+		case n.notificationCancels <- &spendCancel{
+			op:      *outpoint,
+			spendID: ntfn.SpendID,
+		}:
+		case <-n.quit:
+			return
+		}
+	}), nil
+}
+
+// scanForHistoricalSpend scans the main chain from heightHint to the current
+// tip for a transaction spending the target outpoint. Only the blocks whose
+// filters match the outpoint are downloaded. If the spending transaction is
+// found, it's handed to the notification dispatcher, triggering the dispatch
+// of all spend notifications registered for the outpoint.
+//
+// NOTE: This MUST be run as a goroutine.
+func (n *NeutrinoNotifier) scanForHistoricalSpend(outpoint *wire.OutPoint,
+	heightHint uint32) {
+
+	defer n.wg.Done()
+
+	spendReport, err := n.p2pNode.GetUtxo(
+		neutrino.WatchOutPoints(*outpoint),
+		neutrino.StartBlock(&waddrmgr.BlockStamp{
+			Height: int32(heightHint),
+		}),
+		neutrino.QuitChan(n.quit),
+	)
+	if err != nil {
+		chainntnfs.Log.Errorf("Historical scan for spend of "+
+			"outpoint=%v failed: %v", outpoint, err)
+		return
+	}
+
+	// If the output is still unspent, then the rescan will notify us once
+	// it's spent.
+	if spendReport == nil || spendReport.SpendingTx == nil {
+		return
+	}
+
+	n.onRelevantTx(btcutil.NewTx(spendReport.SpendingTx),
+		int32(spendReport.SpendingTxHeight))
+}
+
+// RegisterConfirmationsNtfn registers a notification with NeutrinoNotifier
+// which will be triggered once the txid reaches numConfs number of
+// confirmations.
+func (n *NeutrinoNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	ntfn := chainntnfs.NewConfNtfn(txid, numConfs)
+
+	select {
+	case <-n.quit:
+		return nil, ErrChainNotifierShuttingDown
+	case n.notificationRegistry <- ntfn:
+	}
+
+	// With the notification registered, we'll add the txid to the watch
+	// list of the rescan, so that we're notified of the block which
+	// includes it.
+	if err := n.chainView.Update(neutrino.AddTxIDs(*txid)); err != nil {
+		return nil, err
+	}
+
+	return ntfn.Event, nil
+}
+
+// blockEpochRegistration represents a client's intent to receive a
+// notification with each newly connected block.
+type blockEpochRegistration struct {
+	epochChan chan *chainntnfs.BlockEpoch
+
+	epochID uint64
+}
+
+// epochCancel is a message sent to the NeutrinoNotifier when a client wishes to
+// cancel an outstanding epoch notification that has yet to be dispatched.
+type epochCancel struct {
+	epochID uint64
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive notifications, of each new block connected to the main
+// chain.
+func (n *NeutrinoNotifier) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent, error) {
+	registration := &blockEpochRegistration{
+		epochChan: make(chan *chainntnfs.BlockEpoch, 20),
+		epochID:   atomic.AddUint64(&n.epochClientCounter, 1),
+	}
+
+	select {
+	case <-n.quit:
+		return nil, errors.New("chainntnfs: system interrupt while " +
+			"attempting to register for block epoch notification.")
+	case n.notificationRegistry <- registration:
+		return &chainntnfs.BlockEpochEvent{
+			Epochs: registration.epochChan,
+			Cancel: func() {
+				select {
+				case n.notificationCancels <- &epochCancel{
+					epochID: registration.epochID,
+				}:
+				case <-n.quit:
+					return
+				}
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,643 @@
+package chainntnfs
+
+import (
+	"container/heap"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ConfNtfn represents a client's intent to receive a notification once the
+// target txid reaches NumConfirmations confirmations.
+type ConfNtfn struct {
+	// TxID is the hash of the transaction for which confirmation
+	// notifications are requested.
+	TxID *chainhash.Hash
+
+	// NumConfirmations is the number of confirmations after which the
+	// notification is to be sent.
+	NumConfirmations uint32
+
+	// Event contains the channels over which the client is notified of the
+	// transaction's confirmation, or its removal from the main chain.
+	Event *ConfirmationEvent
+}
+
+// NewConfNtfn returns a new confirmation notification for the passed txid,
+// with buffered event channels.
+func NewConfNtfn(txid *chainhash.Hash, numConfs uint32) *ConfNtfn {
+	return &ConfNtfn{
+		TxID:             txid,
+		NumConfirmations: numConfs,
+		Event: &ConfirmationEvent{
+			Confirmed:    make(chan *TxConfirmation, 1),
+			NegativeConf: make(chan int32, 1),
+		},
+	}
+}
+
+// SpendNtfn couples a target outpoint along with the channels used for
+// notifications once a spend of the outpoint has been detected.
+type SpendNtfn struct {
+	// OutPoint is the outpoint for which spend notifications are
+	// requested.
+	OutPoint wire.OutPoint
+
+	// SpendID uniquely identifies the notification among those registered
+	// for the same outpoint.
+	SpendID uint64
+
+	spendChan chan *SpendDetail
+
+	// reorgChan is sent upon if the spending transaction is disconnected
+	// from the main chain after the spend has been dispatched.
+	reorgChan chan struct{}
+}
+
+// NewSpendNtfn returns a new spend notification for the passed outpoint, with
+// buffered event channels.
+func NewSpendNtfn(op wire.OutPoint, spendID uint64) *SpendNtfn {
+	return &SpendNtfn{
+		OutPoint:  op,
+		SpendID:   spendID,
+		spendChan: make(chan *SpendDetail, 1),
+		reorgChan: make(chan struct{}, 1),
+	}
+}
+
+// Event returns the SpendEvent handed to the client, which cancels the
+// notification by executing the passed closure.
+func (s *SpendNtfn) Event(cancel func()) *SpendEvent {
+	return &SpendEvent{
+		Spend:  s.spendChan,
+		Reorg:  s.reorgChan,
+		Cancel: cancel,
+	}
+}
+
+// TxNotifier houses the confirmation and spend bookkeeping shared by each of
+// the ChainNotifier implementations. The notifier feeds it each block that's
+// connected to or disconnected from the main chain, along with any relevant
+// transactions seen outside of a block, and the TxNotifier dispatches the
+// notifications they trigger. Notifications for transactions included within
+// the last reorgSafetyLimit blocks are tracked, so they can be re-armed if
+// those blocks are disconnected by a re-org.
+//
+// NOTE: The TxNotifier isn't safe for concurrent use. It's intended to be
+// driven solely from the notification dispatcher of a ChainNotifier.
+type TxNotifier struct {
+	bestHeight       int32
+	reorgSafetyLimit int32
+
+	// rearmSpend, if non-nil, is called with the outpoint of each spend
+	// notification which is re-armed, allowing the ChainNotifier to once
+	// again request notifications for spends of the outpoint from its
+	// backend.
+	rearmSpend func(op *wire.OutPoint)
+
+	spendNotifications map[wire.OutPoint]map[uint64]*SpendNtfn
+
+	confNotifications map[chainhash.Hash][]*ConfNtfn
+	confHeap          *confirmationHeap
+
+	// confsByHeight indexes the confirmation notifications whose
+	// transaction was included within one of the last reorgSafetyLimit
+	// blocks by the height of that block.
+	confsByHeight map[int32][]*ConfNtfn
+
+	// spendsByHeight indexes the dispatched spend notifications whose
+	// spending transaction was included within one of the last
+	// reorgSafetyLimit blocks by the height of that block.
+	spendsByHeight map[int32][]*SpendNtfn
+
+	// unconfirmedSpends holds the spend notifications which were
+	// dispatched after the spending transaction was seen within the
+	// mempool, keyed by the spending txid. Once the spending transaction
+	// is included within a block, they're moved to spendsByHeight.
+	unconfirmedSpends map[chainhash.Hash][]*SpendNtfn
+
+	// unconfirmedSpendHeights records the best height at the time each of
+	// the transactions within unconfirmedSpends was seen within the
+	// mempool. If a transaction isn't included within a block before it
+	// falls out of the re-org window, then it's no longer tracked.
+	unconfirmedSpendHeights map[chainhash.Hash]int32
+
+	// reorgDepth is the number of blocks that have been disconnected from
+	// the main chain since a block was last connected.
+	reorgDepth int32
+}
+
+// NewTxNotifier returns a new TxNotifier with the passed best height of the
+// main chain. Notifications are re-armed for re-orgs up to reorgSafetyLimit
+// blocks deep. The rearmSpend closure may be nil.
+func NewTxNotifier(bestHeight, reorgSafetyLimit int32,
+	rearmSpend func(op *wire.OutPoint)) *TxNotifier {
+
+	return &TxNotifier{
+		bestHeight:              bestHeight,
+		reorgSafetyLimit:        reorgSafetyLimit,
+		rearmSpend:              rearmSpend,
+		spendNotifications:      make(map[wire.OutPoint]map[uint64]*SpendNtfn),
+		confNotifications:       make(map[chainhash.Hash][]*ConfNtfn),
+		confHeap:                newConfirmationHeap(),
+		confsByHeight:           make(map[int32][]*ConfNtfn),
+		spendsByHeight:          make(map[int32][]*SpendNtfn),
+		unconfirmedSpends:       make(map[chainhash.Hash][]*SpendNtfn),
+		unconfirmedSpendHeights: make(map[chainhash.Hash]int32),
+	}
+}
+
+// BestHeight returns the height of the last block connected to the main
+// chain.
+func (t *TxNotifier) BestHeight() int32 {
+	return t.bestHeight
+}
+
+// RegisterConf registers the passed confirmation notification. If the
+// ChainNotifier found the transaction within the historical chain, then its
+// confirmation details are passed, and the notification is dispatched
+// immediately if the transaction already has enough confirmations. Otherwise,
+// details should be nil, and the notification is dispatched once the
+// transaction is included within a newly connected block.
+func (t *TxNotifier) RegisterConf(ntfn *ConfNtfn, details *TxConfirmation) {
+	Log.Infof("New confirmations subscription: txid=%v, numconfs=%v",
+		ntfn.TxID, ntfn.NumConfirmations)
+
+	if details == nil {
+		txid := *ntfn.TxID
+		t.confNotifications[txid] = append(t.confNotifications[txid], ntfn)
+		return
+	}
+
+	numConfs := uint32(t.bestHeight-int32(details.BlockHeight)) + 1
+
+	// If the transaction was confirmed within the re-org window, then
+	// we'll track the notification so it can be re-armed if the block
+	// confirming the transaction is disconnected.
+	if int32(numConfs) < t.reorgSafetyLimit {
+		confHeight := int32(details.BlockHeight)
+		t.confsByHeight[confHeight] = append(t.confsByHeight[confHeight],
+			ntfn)
+	}
+
+	// If the transaction has more that enough confirmations, then we can
+	// dispatch it immediately after obtaining for information w.r.t
+	// exactly *when* if got all its confirmations.
+	if numConfs >= ntfn.NumConfirmations {
+		ntfn.Event.Confirmed <- details
+		return
+	}
+
+	// Otherwise, the transaction has only been *partially* confirmed, so
+	// we need to insert it into the confirmation heap.
+	confsLeft := ntfn.NumConfirmations - numConfs
+	heap.Push(t.confHeap, &confEntry{
+		ntfn,
+		details,
+		uint32(t.bestHeight) + confsLeft,
+	})
+}
+
+// RegisterSpend registers the passed spend notification, which is dispatched
+// once a transaction spending its outpoint is processed.
+func (t *TxNotifier) RegisterSpend(ntfn *SpendNtfn) {
+	Log.Infof("New spend subscription: utxo=%v", ntfn.OutPoint)
+
+	t.addSpend(ntfn)
+}
+
+// addSpend adds the passed spend notification to the set awaiting a spend of
+// its outpoint.
+func (t *TxNotifier) addSpend(ntfn *SpendNtfn) {
+	op := ntfn.OutPoint
+	if _, ok := t.spendNotifications[op]; !ok {
+		t.spendNotifications[op] = make(map[uint64]*SpendNtfn)
+	}
+	t.spendNotifications[op][ntfn.SpendID] = ntfn
+}
+
+// CancelSpend cancels the spend notification identified by the passed
+// outpoint and spend ID. If the notification has yet to be dispatched, its
+// spend channel is closed.
+func (t *TxNotifier) CancelSpend(op wire.OutPoint, spendID uint64) {
+	Log.Infof("Cancelling spend notification for out_point=%v, "+
+		"spend_id=%v", op, spendID)
+
+	// Before we attempt to close the spendChan, ensure that the
+	// notification hasn't already yet been dispatched.
+	if outPointClients, ok := t.spendNotifications[op]; ok {
+		if ntfn, ok := outPointClients[spendID]; ok {
+			close(ntfn.spendChan)
+			delete(outPointClients, spendID)
+		}
+		if len(outPointClients) == 0 {
+			delete(t.spendNotifications, op)
+		}
+	}
+
+	// If the notification has already been dispatched, we also stop
+	// tracking it so it isn't re-armed in the case of a re-org.
+	removeNtfn := func(ntfns []*SpendNtfn) []*SpendNtfn {
+		for i, ntfn := range ntfns {
+			if ntfn.SpendID == spendID && ntfn.OutPoint == op {
+				return append(ntfns[:i], ntfns[i+1:]...)
+			}
+		}
+		return ntfns
+	}
+
+	for height, ntfns := range t.spendsByHeight {
+		t.spendsByHeight[height] = removeNtfn(ntfns)
+	}
+	for txid, ntfns := range t.unconfirmedSpends {
+		ntfns = removeNtfn(ntfns)
+		if len(ntfns) == 0 {
+			t.removeUnconfirmedSpend(txid)
+			continue
+		}
+		t.unconfirmedSpends[txid] = ntfns
+	}
+}
+
+// ProcessTx dispatches any spend notifications registered for the outputs
+// spent by the passed transaction, which was seen outside of a newly
+// connected block. A height of zero indicates that the transaction has only
+// been seen within the mempool, otherwise it's the height of the block which
+// included the transaction.
+func (t *TxNotifier) ProcessTx(tx *wire.MsgTx, height int32) {
+	txid := tx.TxHash()
+	t.checkSpendTrigger(tx, &txid, height)
+
+	// If any spends were dispatched from the mempool, then we note the
+	// current height so we can stop tracking them if the transaction never
+	// confirms.
+	_, dispatched := t.unconfirmedSpends[txid]
+	_, seen := t.unconfirmedSpendHeights[txid]
+	if height == 0 && dispatched && !seen {
+		t.unconfirmedSpendHeights[txid] = t.bestHeight
+	}
+}
+
+// ConnectTip processes the transactions of a newly connected block at the
+// passed height, dispatching any confirmation and spend notifications they
+// trigger. Any transactions not relevant to the registered notifications may
+// be omitted.
+func (t *TxNotifier) ConnectTip(blockHash *chainhash.Hash, height int32,
+	txns []*wire.MsgTx) {
+
+	t.reorgDepth = 0
+	t.bestHeight = height
+
+	for i, tx := range txns {
+		// Check if the inclusion of this transaction within a block
+		// by itself triggers a block confirmation threshold, if so
+		// send a notification. Otherwise, place the notification on a
+		// heap to be triggered in the future once additional
+		// confirmations are attained.
+		txSha := tx.TxHash()
+		t.checkConfirmationTrigger(&txSha, blockHash, height, i)
+
+		// If this transaction double spends an output whose spend we
+		// dispatched from the mempool, then that spend is re-armed so
+		// it can be dispatched once again below.
+		t.checkSpendConflicts(tx, &txSha)
+
+		// We'll also check whether this transaction spends any
+		// outputs we're watching.
+		t.checkSpendTrigger(tx, &txSha, height)
+	}
+
+	// A new block has been connected to the main chain. Send out any N
+	// confirmation notifications which may have been triggered by this
+	// new block.
+	t.notifyConfs(height)
+
+	// Finally, stop tracking the notifications for any blocks which have
+	// now fallen out of the re-org window.
+	t.pruneReorgWindow(height)
+}
+
+// DisconnectTip re-arms all confirmation and spend notifications for
+// transactions which were included within the disconnected block at the
+// passed height. Each confirmation client is sent the current depth of the
+// re-org over the NegativeConf channel, and each spend client is signalled
+// over the Reorg channel. The notifications will be dispatched once again
+// when the transactions are re-included within the main chain.
+func (t *TxNotifier) DisconnectTip(blockHash *chainhash.Hash, height int32) {
+	t.reorgDepth++
+	t.bestHeight = height - 1
+
+	Log.Warnf("Block disconnected from main chain: height=%v, sha=%v, "+
+		"reorg_depth=%v", height, blockHash, t.reorgDepth)
+
+	for _, ntfn := range t.confsByHeight[height] {
+		Log.Infof("Re-arming confirmation notification for txid=%v",
+			ntfn.TxID)
+
+		// The notification may still be waiting on additional
+		// confirmations within the heap, in which case it must be
+		// removed so it isn't triggered.
+		t.removeConfEntry(ntfn)
+
+		// If the client hasn't yet read a confirmation which has now
+		// become stale, then we'll drain it.
+		select {
+		case <-ntfn.Event.Confirmed:
+		default:
+		}
+
+		// If the client has yet to read a prior re-org depth, then
+		// it'll learn of the re-org regardless, so the send is
+		// allowed to fail.
+		select {
+		case ntfn.Event.NegativeConf <- t.reorgDepth:
+		default:
+		}
+
+		txid := *ntfn.TxID
+		t.confNotifications[txid] = append(t.confNotifications[txid], ntfn)
+	}
+	delete(t.confsByHeight, height)
+
+	for _, ntfn := range t.spendsByHeight[height] {
+		t.rearm(ntfn)
+	}
+	delete(t.spendsByHeight, height)
+}
+
+// TearDown notifies all pending clients of the ChainNotifier's shutdown by
+// closing the related notification channels.
+func (t *TxNotifier) TearDown() {
+	for _, spendClients := range t.spendNotifications {
+		for _, spendClient := range spendClients {
+			close(spendClient.spendChan)
+		}
+	}
+	for _, confClients := range t.confNotifications {
+		for _, confClient := range confClients {
+			close(confClient.Event.Confirmed)
+			close(confClient.Event.NegativeConf)
+		}
+	}
+}
+
+// checkSpendTrigger dispatches any spend notifications registered for the
+// outputs spent by the passed transaction. A spendHeight of zero indicates
+// that the transaction has only been seen within the mempool.
+func (t *TxNotifier) checkSpendTrigger(spendingTx *wire.MsgTx,
+	spenderSha *chainhash.Hash, spendHeight int32) {
+
+	// If we previously dispatched spend notifications after seeing this
+	// transaction within the mempool, then as it's now been included
+	// within a block, we'll track them by height.
+	if spendHeight != 0 {
+		if ntfns, ok := t.unconfirmedSpends[*spenderSha]; ok {
+			t.spendsByHeight[spendHeight] = append(
+				t.spendsByHeight[spendHeight], ntfns...)
+			t.removeUnconfirmedSpend(*spenderSha)
+		}
+	}
+
+	// Next, check if this transaction spends an output that has an
+	// existing spend notification for it.
+	for i, txIn := range spendingTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+
+		// If this transaction indeed does spend an output which we
+		// have a registered notification for, then create a spend
+		// summary, finally sending off the details to the
+		// notification subscriber.
+		clients, ok := t.spendNotifications[prevOut]
+		if !ok {
+			continue
+		}
+
+		for _, ntfn := range clients {
+			spentOutPoint := ntfn.OutPoint
+			spendDetails := &SpendDetail{
+				SpentOutPoint: &spentOutPoint,
+				SpenderTxHash: spenderSha,
+				// TODO(roasbeef): copy tx?
+				SpendingTx:        spendingTx,
+				SpenderInputIndex: uint32(i),
+				SpendingHeight:    spendHeight,
+			}
+
+			// If the notification was re-armed after a re-org,
+			// then a stale spend may still be buffered, so we'll
+			// drain it before dispatching the new spend.
+			select {
+			case <-ntfn.spendChan:
+			default:
+			}
+
+			Log.Infof("Dispatching spend notification for "+
+				"outpoint=%v", ntfn.OutPoint)
+			ntfn.spendChan <- spendDetails
+
+			if spendHeight == 0 {
+				t.unconfirmedSpends[*spenderSha] = append(
+					t.unconfirmedSpends[*spenderSha], ntfn)
+			} else {
+				t.spendsByHeight[spendHeight] = append(
+					t.spendsByHeight[spendHeight], ntfn)
+			}
+		}
+
+		delete(t.spendNotifications, prevOut)
+	}
+}
+
+// checkSpendConflicts re-arms each spend notification which was dispatched
+// after seeing a spending transaction within the mempool, if the passed
+// transaction included within a block spends the same output. As the
+// mempool transaction can no longer confirm, each client is signalled over
+// the Reorg channel.
+func (t *TxNotifier) checkSpendConflicts(tx *wire.MsgTx,
+	txSha *chainhash.Hash) {
+
+	spent := make(map[wire.OutPoint]struct{}, len(tx.TxIn))
+	for _, txIn := range tx.TxIn {
+		spent[txIn.PreviousOutPoint] = struct{}{}
+	}
+
+	for txid, ntfns := range t.unconfirmedSpends {
+		if txid == *txSha {
+			continue
+		}
+
+		var remaining []*SpendNtfn
+		for _, ntfn := range ntfns {
+			if _, ok := spent[ntfn.OutPoint]; !ok {
+				remaining = append(remaining, ntfn)
+				continue
+			}
+
+			Log.Infof("Spend of outpoint=%v by txid=%v was double "+
+				"spent by txid=%v", ntfn.OutPoint, txid, txSha)
+
+			t.rearm(ntfn)
+		}
+
+		if len(remaining) == 0 {
+			t.removeUnconfirmedSpend(txid)
+			continue
+		}
+		t.unconfirmedSpends[txid] = remaining
+	}
+}
+
+// rearm re-arms a dispatched spend notification whose spending transaction
+// will no longer confirm, signalling the client over the Reorg channel.
+func (t *TxNotifier) rearm(ntfn *SpendNtfn) {
+	Log.Infof("Re-arming spend notification for outpoint=%v",
+		ntfn.OutPoint)
+
+	select {
+	case <-ntfn.spendChan:
+	default:
+	}
+
+	select {
+	case ntfn.reorgChan <- struct{}{}:
+	default:
+	}
+
+	t.addSpend(ntfn)
+
+	if t.rearmSpend != nil {
+		op := ntfn.OutPoint
+		t.rearmSpend(&op)
+	}
+}
+
+// removeUnconfirmedSpend stops tracking the spend notifications dispatched
+// for the passed mempool transaction.
+func (t *TxNotifier) removeUnconfirmedSpend(txid chainhash.Hash) {
+	delete(t.unconfirmedSpends, txid)
+	delete(t.unconfirmedSpendHeights, txid)
+}
+
+// pruneReorgWindow stops tracking the notifications for transactions included
+// within blocks that have fallen out of the re-org window as of the passed
+// height.
+func (t *TxNotifier) pruneReorgWindow(bestHeight int32) {
+	for height := range t.confsByHeight {
+		if height <= bestHeight-t.reorgSafetyLimit {
+			delete(t.confsByHeight, height)
+		}
+	}
+	for height := range t.spendsByHeight {
+		if height <= bestHeight-t.reorgSafetyLimit {
+			delete(t.spendsByHeight, height)
+		}
+	}
+
+	// Spends dispatched from the mempool are only tracked for as long as
+	// their spending transaction may still confirm.
+	for txid, height := range t.unconfirmedSpendHeights {
+		if height <= bestHeight-t.reorgSafetyLimit {
+			t.removeUnconfirmedSpend(txid)
+		}
+	}
+}
+
+// removeConfEntry removes the heap entry for the passed confirmation
+// notification from the confirmation heap, if one exists.
+func (t *TxNotifier) removeConfEntry(ntfn *ConfNtfn) {
+	for i, entry := range t.confHeap.items {
+		if entry.ConfNtfn == ntfn {
+			heap.Remove(t.confHeap, i)
+			return
+		}
+	}
+}
+
+// notifyConfs examines the current confirmation heap, sending off any
+// notifications which have been triggered by the connection of a new block at
+// newBlockHeight.
+func (t *TxNotifier) notifyConfs(newBlockHeight int32) {
+	// If the heap is empty, we have nothing to do.
+	if t.confHeap.Len() == 0 {
+		return
+	}
+
+	// Traverse our confirmation heap. The heap is a
+	// min-heap, so the confirmation notification which requires
+	// the smallest block-height will always be at the top
+	// of the heap. If a confirmation notification is eligible
+	// for triggering, then fire it off, and check if another
+	// is eligible until there are no more eligible entries.
+	nextConf := heap.Pop(t.confHeap).(*confEntry)
+	for nextConf.triggerHeight <= uint32(newBlockHeight) {
+		// TODO(roasbeef): shake out possible of by one in height calc
+		// for historical dispatches
+		nextConf.Event.Confirmed <- nextConf.initialConfDetails
+
+		if t.confHeap.Len() == 0 {
+			return
+		}
+
+		nextConf = heap.Pop(t.confHeap).(*confEntry)
+	}
+
+	heap.Push(t.confHeap, nextConf)
+}
+
+// checkConfirmationTrigger determines if the passed txSha included at
+// blockHeight triggers any single confirmation notifications. In the event
+// that the txid matches, yet needs additional confirmations, it is added to
+// the confirmation heap to be triggered at a later time.
+// TODO(roasbeef): perhaps lookup, then track by inputs instead?
+func (t *TxNotifier) checkConfirmationTrigger(txSha *chainhash.Hash,
+	blockHash *chainhash.Hash, blockHeight int32, txIndex int) {
+
+	// If a confirmation notification has been registered
+	// for this txid, then either trigger a notification
+	// event if only a single confirmation notification was
+	// requested, or place the notification on the
+	// confirmation heap for future usage.
+	confClients, ok := t.confNotifications[*txSha]
+	if !ok {
+		return
+	}
+
+	// Either all of the registered confirmations will be dispatched due
+	// to a single confirmation, or added to the conf heap. Therefore we
+	// unconditionally delete the registered confirmations from the
+	// staging zone.
+	delete(t.confNotifications, *txSha)
+
+	for _, confClient := range confClients {
+		confDetails := &TxConfirmation{
+			BlockHash:   blockHash,
+			BlockHeight: uint32(blockHeight),
+			TxIndex:     uint32(txIndex),
+		}
+
+		// Track the notification so it can be re-armed if this block
+		// is disconnected from the main chain.
+		t.confsByHeight[blockHeight] = append(
+			t.confsByHeight[blockHeight], confClient)
+
+		if confClient.NumConfirmations == 1 {
+			Log.Infof("Dispatching single conf notification, "+
+				"sha=%v, height=%v", txSha, blockHeight)
+			confClient.Event.Confirmed <- confDetails
+			continue
+		}
+
+		// The registered notification requires more than one
+		// confirmation before triggering. So we create a heapConf
+		// entry for this notification. The heapConf allows us to
+		// easily keep track of which notification(s) we should fire
+		// off with each incoming block.
+		finalConfHeight := uint32(blockHeight) +
+			confClient.NumConfirmations - 1
+		heap.Push(t.confHeap, &confEntry{
+			confClient,
+			confDetails,
+			finalConfHeight,
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package chainntnfs
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// testBestHeight is the height of the main chain at which each test's
+	// TxNotifier is created.
+	testBestHeight = 10
+
+	// testReorgSafetyLimit is the re-org window of each test's
+	// TxNotifier.
+	testReorgSafetyLimit = 6
+)
+
+// spendTx returns a transaction spending the passed outpoint. The lock time is
+// used to produce distinct transactions spending the same outpoint.
+func spendTx(op wire.OutPoint, lockTime uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+	tx.AddTxOut(&wire.TxOut{Value: 1e8})
+	tx.LockTime = lockTime
+
+	return tx
+}
+
+// connectBlock connects a block including the passed transactions at the next
+// height of the TxNotifier's main chain.
+func connectBlock(n *TxNotifier, txns ...*wire.MsgTx) *chainhash.Hash {
+	height := n.BestHeight() + 1
+	blockHash := chainhash.Hash{byte(height)}
+	n.ConnectTip(&blockHash, height, txns)
+
+	return &blockHash
+}
+
+// disconnectBlock disconnects the tip of the TxNotifier's main chain.
+func disconnectBlock(n *TxNotifier) {
+	height := n.BestHeight()
+	n.DisconnectTip(&chainhash.Hash{byte(height)}, height)
+}
+
+// assertConf asserts that a confirmation at the passed height has been
+// dispatched to the notification.
+func assertConf(t *testing.T, ntfn *ConfNtfn, height uint32) {
+	select {
+	case conf := <-ntfn.Event.Confirmed:
+		if conf.BlockHeight != height {
+			t.Fatalf("expected confirmation at height %v, got %v",
+				height, conf.BlockHeight)
+		}
+	default:
+		t.Fatalf("confirmation for txid=%v not dispatched", ntfn.TxID)
+	}
+}
+
+// assertNoConf asserts that no confirmation has been dispatched to the
+// notification.
+func assertNoConf(t *testing.T, ntfn *ConfNtfn) {
+	select {
+	case <-ntfn.Event.Confirmed:
+		t.Fatalf("unexpected confirmation for txid=%v", ntfn.TxID)
+	default:
+	}
+}
+
+// assertSpend asserts that a spend by the passed transaction at the passed
+// height has been dispatched to the notification.
+func assertSpend(t *testing.T, ntfn *SpendNtfn, tx *wire.MsgTx,
+	height int32) {
+
+	select {
+	case spend := <-ntfn.spendChan:
+		txid := tx.TxHash()
+		if *spend.SpenderTxHash != txid {
+			t.Fatalf("expected spend by txid=%v, got %v", txid,
+				spend.SpenderTxHash)
+		}
+		if spend.SpendingHeight != height {
+			t.Fatalf("expected spend at height %v, got %v",
+				height, spend.SpendingHeight)
+		}
+		if *spend.SpentOutPoint != ntfn.OutPoint {
+			t.Fatalf("expected spend of outpoint=%v, got %v",
+				ntfn.OutPoint, spend.SpentOutPoint)
+		}
+	default:
+		t.Fatalf("spend of outpoint=%v not dispatched", ntfn.OutPoint)
+	}
+}
+
+// assertNoSpend asserts that no spend has been dispatched to the
+// notification.
+func assertNoSpend(t *testing.T, ntfn *SpendNtfn) {
+	select {
+	case <-ntfn.spendChan:
+		t.Fatalf("unexpected spend of outpoint=%v", ntfn.OutPoint)
+	default:
+	}
+}
+
+// assertReorg asserts whether the notification's client has been signalled
+// over the Reorg channel.
+func assertReorg(t *testing.T, ntfn *SpendNtfn, expected bool) {
+	select {
+	case <-ntfn.reorgChan:
+		if !expected {
+			t.Fatalf("unexpected re-org of spend of outpoint=%v",
+				ntfn.OutPoint)
+		}
+	default:
+		if expected {
+			t.Fatalf("re-org of spend of outpoint=%v not "+
+				"signalled", ntfn.OutPoint)
+		}
+	}
+}
+
+// TestTxNotifierConfDispatch ensures that confirmation notifications are
+// dispatched once their transaction reaches the requested number of
+// confirmations, including transactions confirmed before registration.
+func TestTxNotifierConfDispatch(t *testing.T) {
+	n := NewTxNotifier(testBestHeight, testReorgSafetyLimit, nil)
+
+	tx := spendTx(wire.OutPoint{Index: 1}, 0)
+	txid := tx.TxHash()
+
+	oneConf := NewConfNtfn(&txid, 1)
+	threeConfs := NewConfNtfn(&txid, 3)
+	n.RegisterConf(oneConf, nil)
+	n.RegisterConf(threeConfs, nil)
+
+	// Including the transaction within a block should only dispatch the
+	// single confirmation notification.
+	connectBlock(n, tx)
+	assertConf(t, oneConf, testBestHeight+1)
+	assertNoConf(t, threeConfs)
+
+	connectBlock(n)
+	assertNoConf(t, threeConfs)
+
+	connectBlock(n)
+	assertConf(t, threeConfs, testBestHeight+1)
+
+	// A notification registered for the transaction now that it has
+	// three confirmations should be dispatched immediately if it requires
+	// no more, otherwise once the remaining confirmations are reached.
+	details := &TxConfirmation{BlockHeight: testBestHeight + 1}
+	historical := NewConfNtfn(&txid, 3)
+	n.RegisterConf(historical, details)
+	assertConf(t, historical, testBestHeight+1)
+
+	partial := NewConfNtfn(&txid, 5)
+	n.RegisterConf(partial, details)
+	assertNoConf(t, partial)
+
+	connectBlock(n)
+	assertNoConf(t, partial)
+
+	connectBlock(n)
+	assertConf(t, partial, testBestHeight+1)
+}
+
+// TestTxNotifierConfReorg ensures that a confirmation notification is
+// re-armed if the block confirming its transaction is disconnected, and is
+// dispatched once again when the transaction is re-included.
+func TestTxNotifierConfReorg(t *testing.T) {
+	n := NewTxNotifier(testBestHeight, testReorgSafetyLimit, nil)
+
+	tx := spendTx(wire.OutPoint{Index: 1}, 0)
+	txid := tx.TxHash()
+
+	ntfn := NewConfNtfn(&txid, 2)
+	n.RegisterConf(ntfn, nil)
+
+	// Disconnecting the block before the notification is triggered should
+	// remove it from the confirmation heap.
+	connectBlock(n, tx)
+	disconnectBlock(n)
+
+	select {
+	case depth := <-ntfn.Event.NegativeConf:
+		if depth != 1 {
+			t.Fatalf("expected re-org depth of 1, got %v", depth)
+		}
+	default:
+		t.Fatalf("re-org not signalled")
+	}
+
+	connectBlock(n)
+	connectBlock(n)
+	assertNoConf(t, ntfn)
+
+	// Once re-included, the notification should be dispatched after the
+	// requested number of confirmations.
+	connectBlock(n, tx)
+	assertNoConf(t, ntfn)
+
+	connectBlock(n)
+	assertConf(t, ntfn, testBestHeight+3)
+
+	// Once the confirming block falls out of the re-org window, the
+	// notification should no longer be tracked.
+	for i := 0; i < testReorgSafetyLimit; i++ {
+		connectBlock(n)
+	}
+	if len(n.confsByHeight) != 0 {
+		t.Fatalf("expected no tracked confirmations, found %v",
+			len(n.confsByHeight))
+	}
+}
+
+// TestTxNotifierSpendDispatch ensures that spend notifications are dispatched
+// for spends seen within the mempool and within blocks, re-armed by re-orgs,
+// and that cancelled notifications aren't dispatched.
+func TestTxNotifierSpendDispatch(t *testing.T) {
+	var rearmed []wire.OutPoint
+	n := NewTxNotifier(testBestHeight, testReorgSafetyLimit,
+		func(op *wire.OutPoint) {
+			rearmed = append(rearmed, *op)
+		},
+	)
+
+	op := wire.OutPoint{Index: 1}
+	tx := spendTx(op, 0)
+
+	ntfn := NewSpendNtfn(op, 1)
+	n.RegisterSpend(ntfn)
+
+	cancelled := NewSpendNtfn(op, 2)
+	n.RegisterSpend(cancelled)
+	n.CancelSpend(op, cancelled.SpendID)
+	if _, ok := <-cancelled.spendChan; ok {
+		t.Fatalf("cancelled spend notification dispatched")
+	}
+
+	// The spend should be dispatched once seen within the mempool, and
+	// not once again when the transaction is included within a block.
+	n.ProcessTx(tx, 0)
+	assertSpend(t, ntfn, tx, 0)
+
+	connectBlock(n, tx)
+	assertNoSpend(t, ntfn)
+
+	// Disconnecting the block should re-arm the notification, signalling
+	// the client and requesting spend notifications for the outpoint.
+	disconnectBlock(n)
+	assertReorg(t, ntfn, true)
+	if len(rearmed) != 1 || rearmed[0] != op {
+		t.Fatalf("expected outpoint=%v to be re-armed, got %v", op,
+			rearmed)
+	}
+
+	connectBlock(n, tx)
+	assertSpend(t, ntfn, tx, testBestHeight+1)
+}
+
+// TestTxNotifierSpendConflict ensures that a spend dispatched from the
+// mempool is re-armed if a conflicting transaction is included within a
+// block, and that spends which never confirm are pruned.
+func TestTxNotifierSpendConflict(t *testing.T) {
+	n := NewTxNotifier(testBestHeight, testReorgSafetyLimit, nil)
+
+	op := wire.OutPoint{Index: 1}
+	tx := spendTx(op, 0)
+	doubleSpend := spendTx(op, 1)
+
+	ntfn := NewSpendNtfn(op, 1)
+	n.RegisterSpend(ntfn)
+
+	n.ProcessTx(tx, 0)
+	assertSpend(t, ntfn, tx, 0)
+
+	// Including the double spend within a block should signal the client
+	// and dispatch the spend by the confirmed transaction.
+	connectBlock(n, doubleSpend)
+	assertReorg(t, ntfn, true)
+	assertSpend(t, ntfn, doubleSpend, testBestHeight+1)
+	if len(n.unconfirmedSpends) != 0 {
+		t.Fatalf("conflicted spend still tracked")
+	}
+
+	// A mempool spend which never confirms should no longer be tracked
+	// once it's been unconfirmed for the re-org window.
+	otherOp := wire.OutPoint{Index: 2}
+	otherNtfn := NewSpendNtfn(otherOp, 2)
+	n.RegisterSpend(otherNtfn)
+
+	otherTx := spendTx(otherOp, 0)
+	n.ProcessTx(otherTx, 0)
+	assertSpend(t, otherNtfn, otherTx, 0)
+
+	for i := 0; i < testReorgSafetyLimit-1; i++ {
+		connectBlock(n)
+	}
+	if len(n.unconfirmedSpends) != 1 {
+		t.Fatalf("unconfirmed spend pruned before leaving the " +
+			"re-org window")
+	}
+
+	connectBlock(n)
+	if len(n.unconfirmedSpends) != 0 ||
+		len(n.unconfirmedSpendHeights) != 0 {
+
+		t.Fatalf("unconfirmed spend not pruned")
+	}
+	assertReorg(t, otherNtfn, false)
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lightninglabs/neutrino"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcrpcclient"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcwallet/chain"
+	"github.com/roasbeef/btcwallet/walletdb"
+
+	// Import the ChainNotifier drivers so they register themselves with
+	// the chainntnfs registry.
+	_ "github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
+	_ "github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
+	_ "github.com/lightningnetwork/lnd/chainntnfs/neutrinonotify"
+)
+
+// chainBackend houses the connection to the bitcoin node selected by the
+// bitcoin.node option. The connection is shared by the ChainNotifier and
+// WalletController drivers, which draw their typed configuration from it.
+type chainBackend struct {
+	// node is the bitcoin node implementation lnd is connected to.
+	node string
+
+	// rpcConfig is the RPC configuration of a btcd or bitcoind node.
+	rpcConfig *btcrpcclient.ConnConfig
+
+	// rpcCert is btcd's TLS certificate, used to authenticate its RPC
+	// connection.
+	rpcCert []byte
+
+	// chainService is the neutrino light client.
+	chainService *neutrino.ChainService
+
+	// chainSource is the chain.Interface the wallet uses to sync with
+	// the chain. If nil, the wallet will connect to btcd itself.
+	chainSource chain.Interface
+
+	// notifier is the ChainNotifier created on top of the backend. Wallet
+	// drivers which share the notifier's view of the chain, such as the
+	// simulated chain's, are created on top of it.
+	notifier chainntnfs.ChainNotifier
+
+	// cleanUp is run once lnd shuts down to release the resources held
+	// by the backend.
+	cleanUp func()
+}
+
+// notifierArgs maps the name of each ChainNotifier driver to a function which
+// builds the arguments expected by the driver's New method from the chain
+// options within the passed config, and the backend connected to the node
+// they select. Adding a new ChainNotifier to lnd requires registering its
+// driver, and adding an entry here.
+var notifierArgs = map[string]func(*config, *chainBackend) ([]interface{}, error){
+	"btcd": func(cfg *config, b *chainBackend) ([]interface{}, error) {
+		if cfg.Bitcoin.Node != "btcd" {
+			return nil, fmt.Errorf("btcd notifier requires a btcd "+
+				"node, instead have %v", cfg.Bitcoin.Node)
+		}
+		return []interface{}{b.rpcConfig}, nil
+	},
+	"bitcoind": func(cfg *config, b *chainBackend) ([]interface{}, error) {
+		if cfg.Bitcoin.Node != "bitcoind" {
+			return nil, fmt.Errorf("bitcoind notifier requires a "+
+				"bitcoind node, instead have %v",
+				cfg.Bitcoin.Node)
+		}
+		return []interface{}{b.rpcConfig, cfg.Bitcoin.ZMQPath}, nil
+	},
+	"neutrino": func(cfg *config, b *chainBackend) ([]interface{}, error) {
+		if cfg.Bitcoin.Node != "neutrino" {
+			return nil, fmt.Errorf("neutrino notifier requires a "+
+				"neutrino node, instead have %v",
+				cfg.Bitcoin.Node)
+		}
+		return []interface{}{b.chainService}, nil
+	},
+
+	// The simulated chain is held entirely in memory, and is only
+	// intended for tests, so it doesn't depend on the bitcoin node.
+	"simchain": func(cfg *config, b *chainBackend) ([]interface{}, error) {
+		return []interface{}{activeNetParams.Params}, nil
+	},
+}
+
+// walletArgs maps the name of each WalletController driver to a function
+// which builds the arguments expected by the driver's New method from the
+// passed config, and the backend connected to the bitcoin node it selects.
+// Adding a new WalletController to lnd requires registering its driver, and
+// adding an entry here.
+var walletArgs = map[string]func(*config, *chainBackend) ([]interface{}, error){
+	"btcwallet": func(cfg *config, b *chainBackend) ([]interface{}, error) {
+		walletConfig := &btcwallet.Config{
+			PrivatePass: []byte(cfg.Btcwallet.PrivatePass),
+			DataDir:     filepath.Join(cfg.DataDir, "lnwallet"),
+			RPCUser:     cfg.RPCUser,
+			RPCPass:     cfg.RPCPass,
+			CACert:      b.rpcCert,
+			ChainSource: b.chainSource,
+			NetParams:   activeNetParams.Params,
+			WatchOnly:   cfg.RemoteSigner != "",
+		}
+		if b.rpcConfig != nil {
+			walletConfig.RPCHost = b.rpcConfig.Host
+		}
+
+		// If the wallet doesn't yet exist, then it'll be created from
+		// either a fresh seed, or one restored by the user.
+		if err := initWalletSeed(cfg, walletConfig); err != nil {
+			return nil, err
+		}
+
+		return []interface{}{walletConfig}, nil
+	},
+
+	// The simulated chain's wallet is held in memory alongside the chain
+	// itself, so it requires the simulated chain's notifier, and is
+	// created from a fresh seed each time lnd starts. As it's unable to
+	// share the seed of a remote signer, it can't be used with one.
+	"simchain": func(cfg *config, b *chainBackend) ([]interface{}, error) {
+		if cfg.RemoteSigner != "" {
+			return nil, fmt.Errorf("simchain wallet can't be used " +
+				"with a remote signer")
+		}
+
+		simChain, ok := b.notifier.(*simchain.Chain)
+		if !ok {
+			return nil, fmt.Errorf("simchain wallet requires the "+
+				"simchain notifier, instead have %v",
+				cfg.ChainNotifier)
+		}
+
+		var seed [32]byte
+		if _, err := rand.Read(seed[:]); err != nil {
+			return nil, err
+		}
+
+		return []interface{}{simChain, seed[:]}, nil
+	},
+}
+
+// newChainBackend connects to the bitcoin node selected within the passed
+// config.
+func newChainBackend(cfg *config) (*chainBackend, error) {
+	backend := &chainBackend{
+		node:    cfg.Bitcoin.Node,
+		cleanUp: func() {},
+	}
+
+	// If the specified host for the node's RPC server already has a port
+	// specified, then we use that directly. Otherwise, we assume the
+	// default port according to the selected chain parameters and node.
+	rpcPort := activeNetParams.rpcPort
+	if cfg.Bitcoin.Node == "bitcoind" {
+		rpcPort = activeNetParams.bitcoindRPCPort
+	}
+	var rpcHost string
+	if strings.Contains(cfg.RPCHost, ":") {
+		rpcHost = cfg.RPCHost
+	} else {
+		rpcHost = fmt.Sprintf("%v:%v", cfg.RPCHost, rpcPort)
+	}
+
+	switch cfg.Bitcoin.Node {
+	case "bitcoind":
+		// bitcoind's RPC interface is reached over plain HTTP, while
+		// new blocks and transactions are published over ZMQ.
+		backend.rpcConfig = &btcrpcclient.ConnConfig{
+			Host:         rpcHost,
+			User:         cfg.RPCUser,
+			Pass:         cfg.RPCPass,
+			DisableTLS:   true,
+			HTTPPostMode: true,
+		}
+
+		chainConn, err := chain.NewBitcoindClient(activeNetParams.Params,
+			rpcHost, cfg.RPCUser, cfg.RPCPass, cfg.Bitcoin.ZMQPath,
+			time.Millisecond*100)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create bitcoind "+
+				"client: %v", err)
+		}
+		backend.chainSource = chainConn
+
+	case "neutrino":
+		// The neutrino light client stores the block headers and
+		// filters it syncs within its own database, which we'll
+		// create if needed.
+		dbName := filepath.Join(cfg.DataDir, "neutrino.db")
+		nodeDatabase, err := walletdb.Create("bdb", dbName)
+		if err != nil {
+			return nil, err
+		}
+
+		neutrinoConfig := neutrino.Config{
+			DataDir:      cfg.DataDir,
+			Database:     nodeDatabase,
+			ChainParams:  *activeNetParams.Params,
+			AddPeers:     cfg.NeutrinoMode.AddPeers,
+			ConnectPeers: cfg.NeutrinoMode.ConnectPeers,
+		}
+		chainService, err := neutrino.NewChainService(neutrinoConfig)
+		if err != nil {
+			nodeDatabase.Close()
+			return nil, fmt.Errorf("unable to create neutrino: %v",
+				err)
+		}
+		chainService.Start()
+		backend.chainService = chainService
+		backend.cleanUp = func() {
+			chainService.Stop()
+			nodeDatabase.Close()
+		}
+
+		chainConn, err := chain.NewNeutrinoClient(chainService)
+		if err != nil {
+			backend.cleanUp()
+			return nil, fmt.Errorf("unable to create neutrino "+
+				"client: %v", err)
+		}
+		backend.chainSource = chainConn
+
+	default:
+		// Next load btcd's TLS cert for the RPC connection. If a raw
+		// cert was specified in the config, then we'll set that
+		// directly. Otherwise, we attempt to read the cert from the
+		// path specified in the config.
+		var rpcCert []byte
+		if cfg.RawRPCCert != "" {
+			var err error
+			rpcCert, err = hex.DecodeString(cfg.RawRPCCert)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			certFile, err := os.Open(cfg.RPCCert)
+			if err != nil {
+				return nil, err
+			}
+			rpcCert, err = ioutil.ReadAll(certFile)
+			if err != nil {
+				return nil, err
+			}
+			if err := certFile.Close(); err != nil {
+				return nil, err
+			}
+		}
+
+		backend.rpcCert = rpcCert
+		backend.rpcConfig = &btcrpcclient.ConnConfig{
+			Host:                 rpcHost,
+			Endpoint:             "ws",
+			User:                 cfg.RPCUser,
+			Pass:                 cfg.RPCPass,
+			Certificates:         rpcCert,
+			DisableTLS:           false,
+			DisableConnectOnNew:  true,
+			DisableAutoReconnect: false,
+		}
+	}
+
+	return backend, nil
+}
+
+// newChainNotifier looks up the ChainNotifier driver selected within the
+// passed config, and creates a new instance of it on top of the backend.
+func newChainNotifier(cfg *config,
+	backend *chainBackend) (chainntnfs.ChainNotifier, error) {
+
+	driver, err := chainntnfs.LookupNotifier(cfg.ChainNotifier)
+	if err != nil {
+		return nil, err
+	}
+
+	argsFunc, ok := notifierArgs[driver.NotifierType]
+	if !ok {
+		return nil, fmt.Errorf("no config known for notifier %v",
+			driver.NotifierType)
+	}
+	args, err := argsFunc(cfg, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier, err := driver.New(args...)
+	if err != nil {
+		return nil, err
+	}
+	backend.notifier = notifier
+
+	return notifier, nil
+}
+
+// newWalletController looks up the WalletController driver selected within
+// the passed config, and creates a new instance of it on top of the backend.
+func newWalletController(cfg *config,
+	backend *chainBackend) (lnwallet.WalletController, error) {
+
+	driver, err := lnwallet.LookupWallet(cfg.WalletController)
+	if err != nil {
+		return nil, err
+	}
+
+	argsFunc, ok := walletArgs[driver.WalletType]
+	if !ok {
+		return nil, fmt.Errorf("no config known for wallet %v",
+			driver.WalletType)
+	}
+	args, err := argsFunc(cfg, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.New(args...)
+}
+
+// newFeeEstimator creates the FeeEstimator lnd uses to price its on-chain
+// transactions. The estimates of a btcd or bitcoind node are preferred, with
+// the fee rate within the passed config used as a fallback if the node is
+// unable to produce one. As the neutrino light client doesn't track the
+// mempool, it always uses the configured fee rate.
+func newFeeEstimator(cfg *config,
+	backend *chainBackend) (lnwallet.FeeEstimator, error) {
+
+	staticEstimator := lnwallet.StaticFeeEstimator{
+		FeeRate: btcutil.Amount(cfg.FeeRate),
+	}
+
+	var (
+		nodeEstimator lnwallet.FeeEstimator
+		err           error
+	)
+	switch backend.node {
+	case "btcd":
+		nodeEstimator, err = lnwallet.NewBtcdFeeEstimator(
+			*backend.rpcConfig)
+	case "bitcoind":
+		nodeEstimator, err = lnwallet.NewBitcoindFeeEstimator(
+			*backend.rpcConfig)
+	default:
+		return staticEstimator, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return lnwallet.NewFallbackFeeEstimator(nodeEstimator,
+		staticEstimator), nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// chanAcceptRequest is an inbound channel request awaiting the decision of a
+// single ChannelAcceptor client.
+type chanAcceptRequest struct {
+	// id uniquely identifies the request, as the pending channel ID of
+	// msg is chosen by the peer, and so may collide with that of another
+	// request.
+	id uint64
+
+	peerKey *btcec.PublicKey
+	msg     *lnwire.SingleFundingRequest
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	resp chan *chanAcceptResponse
+}
+
+// chanAcceptResponse is a ChannelAcceptor client's decision on an inbound
+// channel request.
+type chanAcceptResponse struct {
+	accept bool
+
+	// reason is the reason the channel was rejected, which is relayed to
+	// the requesting peer.
+	reason string
+}
+
+// chanAcceptorClient is a single subscriber to the ChannelAcceptor RPC, to
+// which inbound channel requests are dispatched.
+type chanAcceptorClient struct {
+	id uint64
+
+	// requests is the channel over which inbound channel requests are
+	// dispatched to the client.
+	requests chan *chanAcceptRequest
+
+	// expired is the channel over which requests dispatched to the client
+	// are sent once they're no longer awaiting its decision, as the
+	// timeout has elapsed.
+	expired chan *chanAcceptRequest
+
+	// quit is closed once the client has unsubscribed.
+	quit chan struct{}
+}
+
+// chanAcceptor dispatches each inbound channel request to the clients
+// subscribed via the ChannelAcceptor RPC, collecting their decisions. A
+// request is only accepted if every client accepts it. If a client fails to
+// decide within the timeout, then the default decision is used in its place.
+// If there are no clients, then every request is accepted.
+type chanAcceptor struct {
+	// timeout is the amount of time clients are given to decide on a
+	// request.
+	timeout time.Duration
+
+	// rejectDefault indicates that requests should be rejected, rather
+	// than accepted, if a client fails to decide within the timeout.
+	rejectDefault bool
+
+	mtx     sync.Mutex
+	nextID  uint64
+	clients map[uint64]*chanAcceptorClient
+
+	// nextReqID is the ID assigned to the next request.
+	nextReqID uint64
+
+	// inFlight is the set of requests currently being decided upon, keyed
+	// by the requesting peer and their pending channel ID.
+	inFlight map[chanAcceptKey]struct{}
+}
+
+// chanAcceptKey identifies an inbound channel request by the peer which made
+// it, and the pending channel ID they chose for it.
+type chanAcceptKey struct {
+	peer          [33]byte
+	pendingChanID [32]byte
+}
+
+// newChanAcceptor creates a new chanAcceptor, without any clients, which uses
+// the passed timeout and default decision.
+func newChanAcceptor(timeout time.Duration, rejectDefault bool) *chanAcceptor {
+	return &chanAcceptor{
+		timeout:       timeout,
+		rejectDefault: rejectDefault,
+		clients:       make(map[uint64]*chanAcceptorClient),
+		inFlight:      make(map[chanAcceptKey]struct{}),
+	}
+}
+
+// Subscribe registers a new client, to which all subsequent inbound channel
+// requests are dispatched until it unsubscribes.
+func (c *chanAcceptor) Subscribe() *chanAcceptorClient {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	client := &chanAcceptorClient{
+		id:       c.nextID,
+		requests: make(chan *chanAcceptRequest),
+		expired:  make(chan *chanAcceptRequest),
+		quit:     make(chan struct{}),
+	}
+	c.clients[client.id] = client
+	c.nextID++
+
+	return client
+}
+
+// Unsubscribe removes a client previously registered via Subscribe. Any
+// requests it has yet to decide on fall back to the default decision.
+func (c *chanAcceptor) Unsubscribe(client *chanAcceptorClient) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.clients[client.id]; !ok {
+		return
+	}
+
+	delete(c.clients, client.id)
+	close(client.quit)
+}
+
+// Accept dispatches an inbound channel request from the passed peer to every
+// client, returning whether the channel should be accepted, and if not, the
+// reason why. This method blocks until every client has decided, or the
+// timeout has elapsed. A request reusing the pending channel ID of another
+// request from the same peer which is still being decided upon is rejected.
+func (c *chanAcceptor) Accept(peerKey *btcec.PublicKey,
+	msg *lnwire.SingleFundingRequest) (bool, string) {
+
+	key := chanAcceptKey{pendingChanID: msg.PendingChannelID}
+	copy(key.peer[:], peerKey.SerializeCompressed())
+
+	c.mtx.Lock()
+	if _, ok := c.inFlight[key]; ok {
+		c.mtx.Unlock()
+		return false, fmt.Sprintf("request with pending channel ID "+
+			"%x already in flight", msg.PendingChannelID)
+	}
+	c.inFlight[key] = struct{}{}
+
+	reqID := c.nextReqID
+	c.nextReqID++
+
+	clients := make([]*chanAcceptorClient, 0, len(c.clients))
+	for _, client := range c.clients {
+		clients = append(clients, client)
+	}
+	c.mtx.Unlock()
+
+	defer func() {
+		c.mtx.Lock()
+		delete(c.inFlight, key)
+		c.mtx.Unlock()
+	}()
+
+	// Each client is queried concurrently, with all of them sharing the
+	// same deadline. The timer is left to fire even if we return early, so
+	// that no query is left waiting on an unresponsive client.
+	timeout := make(chan struct{})
+	time.AfterFunc(c.timeout, func() {
+		close(timeout)
+	})
+	responses := make(chan *chanAcceptResponse, len(clients))
+	for _, client := range clients {
+		go func(client *chanAcceptorClient) {
+			req := &chanAcceptRequest{
+				id:      reqID,
+				peerKey: peerKey,
+				msg:     msg,
+				resp:    make(chan *chanAcceptResponse, 1),
+			}
+
+			select {
+			case client.requests <- req:
+			case <-client.quit:
+				responses <- c.defaultResponse()
+				return
+			case <-timeout:
+				responses <- c.defaultResponse()
+				return
+			}
+
+			select {
+			case resp := <-req.resp:
+				responses <- resp
+			case <-client.quit:
+				responses <- c.defaultResponse()
+			case <-timeout:
+				responses <- c.defaultResponse()
+
+				// The client may still decide on the request,
+				// so we'll let it know it has expired.
+				select {
+				case client.expired <- req:
+				case <-client.quit:
+				}
+			}
+		}(client)
+	}
+
+	for range clients {
+		resp := <-responses
+		if !resp.accept {
+			return false, resp.reason
+		}
+	}
+
+	return true, ""
+}
+
+// defaultResponse returns the decision used in place of a client which fails
+// to decide on a request in time.
+func (c *chanAcceptor) defaultResponse() *chanAcceptResponse {
+	if !c.rejectDefault {
+		return &chanAcceptResponse{accept: true}
+	}
+
+	return &chanAcceptResponse{
+		reason: fmt.Sprintf("no decision made within %v", c.timeout),
+	}
+}
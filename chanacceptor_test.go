@@ -0,0 +1,225 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// acceptTestTimeout is the duration a test waits for the channel acceptor to
+// decide on a request before failing.
+const acceptTestTimeout = 5 * time.Second
+
+// acceptResult is the decision of the channel acceptor on a request.
+type acceptResult struct {
+	accept bool
+	reason string
+}
+
+// newAcceptRequest creates a funding request from a new peer.
+func newAcceptRequest(t *testing.T,
+	pendingChanID byte) (*btcec.PublicKey, *lnwire.SingleFundingRequest) {
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	return priv.PubKey(), &lnwire.SingleFundingRequest{
+		PendingChannelID: [32]byte{pendingChanID},
+		FundingAmount:    1000000,
+	}
+}
+
+// accept asks the channel acceptor to decide on the passed request within a
+// new goroutine, returning the channel its decision is delivered over.
+func accept(acceptor *chanAcceptor, peerKey *btcec.PublicKey,
+	msg *lnwire.SingleFundingRequest) <-chan *acceptResult {
+
+	results := make(chan *acceptResult, 1)
+	go func() {
+		accept, reason := acceptor.Accept(peerKey, msg)
+		results <- &acceptResult{accept, reason}
+	}()
+
+	return results
+}
+
+// assertAcceptResult asserts that the channel acceptor decided on a request
+// as expected.
+func assertAcceptResult(t *testing.T, results <-chan *acceptResult,
+	accept bool, reason string) {
+
+	select {
+	case result := <-results:
+		if result.accept != accept || result.reason != reason {
+			t.Fatalf("expected accept=%v reason=%q, got "+
+				"accept=%v reason=%q", accept, reason,
+				result.accept, result.reason)
+		}
+
+	case <-time.After(acceptTestTimeout):
+		t.Fatalf("request not decided upon")
+	}
+}
+
+// receiveRequest asserts that the passed client is dispatched a request.
+func receiveRequest(t *testing.T,
+	client *chanAcceptorClient) *chanAcceptRequest {
+
+	select {
+	case req := <-client.requests:
+		return req
+	case <-time.After(acceptTestTimeout):
+		t.Fatalf("request not dispatched to client")
+	}
+
+	return nil
+}
+
+// TestChanAcceptorNoClients tests that requests are accepted if there are no
+// clients to decide on them, regardless of the default decision.
+func TestChanAcceptorNoClients(t *testing.T) {
+	acceptor := newChanAcceptor(time.Hour, true)
+
+	peerKey, msg := newAcceptRequest(t, 1)
+	assertAcceptResult(t, accept(acceptor, peerKey, msg), true, "")
+}
+
+// TestChanAcceptorAllClientsDecide tests that a request is only accepted if
+// every client accepts it, and that the reason given by a client rejecting it
+// is returned.
+func TestChanAcceptorAllClientsDecide(t *testing.T) {
+	acceptor := newChanAcceptor(time.Hour, true)
+	first := acceptor.Subscribe()
+	second := acceptor.Subscribe()
+
+	// A request accepted by both clients should be accepted.
+	peerKey, msg := newAcceptRequest(t, 1)
+	results := accept(acceptor, peerKey, msg)
+	for _, client := range []*chanAcceptorClient{first, second} {
+		req := receiveRequest(t, client)
+		if req.msg != msg || !req.peerKey.IsEqual(peerKey) {
+			t.Fatalf("unexpected request dispatched to client")
+		}
+		req.resp <- &chanAcceptResponse{accept: true}
+	}
+	assertAcceptResult(t, results, true, "")
+
+	// If either client rejects a request, then it should be rejected.
+	const reason = "channel too small"
+	peerKey, msg = newAcceptRequest(t, 2)
+	results = accept(acceptor, peerKey, msg)
+	receiveRequest(t, first).resp <- &chanAcceptResponse{accept: true}
+	receiveRequest(t, second).resp <- &chanAcceptResponse{reason: reason}
+	assertAcceptResult(t, results, false, reason)
+}
+
+// TestChanAcceptorTimeout tests that the default decision is used in place of
+// a client which fails to decide within the timeout, and that the client is
+// notified the request has expired.
+func TestChanAcceptorTimeout(t *testing.T) {
+	for _, rejectDefault := range []bool{false, true} {
+		acceptor := newChanAcceptor(50*time.Millisecond, rejectDefault)
+		client := acceptor.Subscribe()
+
+		peerKey, msg := newAcceptRequest(t, 1)
+		results := accept(acceptor, peerKey, msg)
+		req := receiveRequest(t, client)
+
+		expected := acceptor.defaultResponse()
+		assertAcceptResult(t, results, expected.accept,
+			expected.reason)
+
+		select {
+		case expired := <-client.expired:
+			if expired != req {
+				t.Fatalf("unexpected request expired")
+			}
+		case <-time.After(acceptTestTimeout):
+			t.Fatalf("request not expired")
+		}
+
+		acceptor.Unsubscribe(client)
+	}
+}
+
+// TestChanAcceptorUnsubscribe tests that the default decision is used in
+// place of a client which unsubscribes before deciding on a request, and that
+// requests are no longer dispatched to it.
+func TestChanAcceptorUnsubscribe(t *testing.T) {
+	acceptor := newChanAcceptor(time.Hour, true)
+	client := acceptor.Subscribe()
+
+	peerKey, msg := newAcceptRequest(t, 1)
+	results := accept(acceptor, peerKey, msg)
+	receiveRequest(t, client)
+
+	acceptor.Unsubscribe(client)
+	expected := acceptor.defaultResponse()
+	assertAcceptResult(t, results, expected.accept, expected.reason)
+
+	// With the client gone, there's no one left to decide on requests, so
+	// they should be accepted.
+	peerKey, msg = newAcceptRequest(t, 2)
+	assertAcceptResult(t, accept(acceptor, peerKey, msg), true, "")
+}
+
+// TestChanAcceptorDuplicateRequest tests that a request reusing the pending
+// channel ID of another request from the same peer is rejected while the
+// first is still being decided upon, but not once it has been, and that
+// requests from distinct peers sharing a pending channel ID are dispatched
+// under distinct request IDs.
+func TestChanAcceptorDuplicateRequest(t *testing.T) {
+	acceptor := newChanAcceptor(time.Hour, true)
+	client := acceptor.Subscribe()
+
+	peerKey, msg := newAcceptRequest(t, 1)
+	results := accept(acceptor, peerKey, msg)
+	req := receiveRequest(t, client)
+
+	// While the first request is in flight, the same peer can't reuse its
+	// pending channel ID.
+	dupMsg := &lnwire.SingleFundingRequest{
+		PendingChannelID: msg.PendingChannelID,
+		FundingAmount:    2000000,
+	}
+	dupResults := accept(acceptor, peerKey, dupMsg)
+	select {
+	case result := <-dupResults:
+		if result.accept {
+			t.Fatalf("duplicate request accepted")
+		}
+	case <-client.requests:
+		t.Fatalf("duplicate request dispatched to client")
+	case <-time.After(acceptTestTimeout):
+		t.Fatalf("duplicate request not decided upon")
+	}
+
+	// Another peer may use the same pending channel ID, and its request
+	// should be told apart from the first.
+	otherKey, otherMsg := newAcceptRequest(t, 1)
+	otherResults := accept(acceptor, otherKey, otherMsg)
+	otherReq := receiveRequest(t, client)
+	if otherReq.id == req.id {
+		t.Fatalf("requests from distinct peers share ID %v", req.id)
+	}
+
+	const reason = "channel too small"
+	otherReq.resp <- &chanAcceptResponse{reason: reason}
+	req.resp <- &chanAcceptResponse{accept: true}
+	assertAcceptResult(t, results, true, "")
+	assertAcceptResult(t, otherResults, false, reason)
+
+	// Now that the first request has been decided upon, its pending
+	// channel ID may be reused.
+	results = accept(acceptor, peerKey, dupMsg)
+	req = receiveRequest(t, client)
+	if req.msg != dupMsg {
+		t.Fatalf("unexpected request dispatched to client")
+	}
+	req.resp <- &chanAcceptResponse{accept: true}
+	assertAcceptResult(t, results, true, "")
+}
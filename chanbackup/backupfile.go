@@ -0,0 +1,80 @@
+package chanbackup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+const (
+	// DefaultBackupFileName is the default name of the file holding our
+	// multi-channel static backup.
+	DefaultBackupFileName = "channel.backup"
+
+	// tempBackupFileSuffix is the suffix appended to the name of the
+	// backup file to obtain the name of the temporary file a new backup
+	// is written to before it replaces the existing backup.
+	tempBackupFileSuffix = ".tmp"
+)
+
+// MultiFile is a file holding a multi-channel static backup. The file is
+// updated atomically: each new backup is written in full to a temporary file,
+// which is then renamed over the existing backup. At any point, the file
+// therefore holds either the prior or the new backup in its entirety.
+type MultiFile struct {
+	fileName     string
+	tempFileName string
+}
+
+// NewMultiFile creates a new MultiFile backed by the file at the passed path.
+func NewMultiFile(fileName string) *MultiFile {
+	return &MultiFile{
+		fileName:     fileName,
+		tempFileName: fileName + tempBackupFileSuffix,
+	}
+}
+
+// FileName returns the path of the backup file.
+func (b *MultiFile) FileName() string {
+	return b.fileName
+}
+
+// UpdateAndSwap atomically replaces the backup held by the file with the
+// passed packed backup.
+func (b *MultiFile) UpdateAndSwap(newBackup PackedMulti) error {
+	tempFile, err := os.OpenFile(b.tempFileName,
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create temp backup file: %v", err)
+	}
+
+	if _, err := tempFile.Write(newBackup); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("unable to write temp backup file: %v", err)
+	}
+
+	// Before swapping the files, we'll ensure the new backup has been
+	// flushed to disk.
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("unable to sync temp backup file: %v", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("unable to close temp backup file: %v", err)
+	}
+
+	return os.Rename(b.tempFileName, b.fileName)
+}
+
+// ExtractMulti reads the backup held by the file, then decrypts and
+// deserializes it using the passed key ring.
+func (b *MultiFile) ExtractMulti(keyRing keychain.ECDHRing) (*Multi, error) {
+	packedMulti, err := ioutil.ReadFile(b.fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return PackedMulti(packedMulti).Unpack(keyRing)
+}
@@ -0,0 +1,54 @@
+package chanbackup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestMultiFileUpdateAndSwap tests that each backup written to a MultiFile
+// replaces the prior backup, and leaves no temporary file behind.
+func TestMultiFileUpdateAndSwap(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "chanbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	keyRing := newTestKeyRing(t, 0x01)
+	backupFile := NewMultiFile(filepath.Join(tempDir,
+		DefaultBackupFileName))
+
+	multi := Multi{Version: DefaultMultiVersion}
+	for i := uint32(0); i < 3; i++ {
+		multi.StaticBackups = append(multi.StaticBackups,
+			newTestSingle(t, i))
+
+		var b bytes.Buffer
+		if err := multi.PackToWriter(&b, keyRing); err != nil {
+			t.Fatalf("unable to pack backup: %v", err)
+		}
+		if err := backupFile.UpdateAndSwap(b.Bytes()); err != nil {
+			t.Fatalf("unable to update backup file: %v", err)
+		}
+
+		extracted, err := backupFile.ExtractMulti(keyRing)
+		if err != nil {
+			t.Fatalf("unable to extract backup: %v", err)
+		}
+		if !reflect.DeepEqual(&multi, extracted) {
+			t.Fatalf("backups don't match: expected %v, got %v",
+				multi, extracted)
+		}
+
+		_, err = os.Stat(backupFile.FileName() + tempBackupFileSuffix)
+		if !os.IsNotExist(err) {
+			t.Fatalf("temp backup file left behind: %v", err)
+		}
+	}
+}
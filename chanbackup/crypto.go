@@ -0,0 +1,100 @@
+package chanbackup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// backupKeyLoc is the locator of the key from which the encryption key of our
+// backups is derived. As the key is derived from the wallet's seed, the
+// backups can be decrypted after restoring the wallet from its seed alone.
+var backupKeyLoc = keychain.KeyLocator{
+	Family: keychain.KeyFamilyStaticBackup,
+	Index:  0,
+}
+
+// genEncryptionKey derives the key used to encrypt and decrypt our backups:
+// the sha256 of the point shared between the key at the backupKeyLoc and its
+// own public key. As the point is computed using the key ring's ECDH, the
+// encryption key may be derived without access to the private key.
+func genEncryptionKey(keyRing keychain.ECDHRing) ([]byte, error) {
+	keyDesc, err := keyRing.DeriveKey(backupKeyLoc)
+	if err != nil {
+		return nil, err
+	}
+	sharedPoint, err := keyRing.ECDH(backupKeyLoc, keyDesc.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionKey := sha256.Sum256(sharedPoint.SerializeCompressed())
+	return encryptionKey[:], nil
+}
+
+// encryptPayloadToWriter encrypts the passed payload using the backup key
+// derived from the key ring, writing the result to the passed io.Writer. The
+// payload is encrypted using chacha20poly1305 under a random nonce, which is
+// prepended to the ciphertext.
+func encryptPayloadToWriter(payload bytes.Buffer, w io.Writer,
+	keyRing keychain.ECDHRing) error {
+
+	encryptionKey, err := genEncryptionKey(keyRing)
+	if err != nil {
+		return err
+	}
+
+	cipher, err := chacha20poly1305.New(encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+
+	cipherText := cipher.Seal(nil, nonce[:], payload.Bytes(), nonce[:])
+
+	if _, err := w.Write(nonce[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(cipherText)
+	return err
+}
+
+// decryptPayloadFromReader decrypts a payload encrypted by
+// encryptPayloadToWriter, read in full from the passed io.Reader.
+func decryptPayloadFromReader(r io.Reader,
+	keyRing keychain.ECDHRing) ([]byte, error) {
+
+	encryptionKey, err := genEncryptionKey(keyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	packed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(packed) < chacha20poly1305.NonceSize {
+		return nil, fmt.Errorf("payload size too small, must be at "+
+			"least %v bytes", chacha20poly1305.NonceSize)
+	}
+
+	cipher, err := chacha20poly1305.New(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := packed[:chacha20poly1305.NonceSize]
+	cipherText := packed[chacha20poly1305.NonceSize:]
+
+	return cipher.Open(nil, nonce, cipherText, nonce)
+}
@@ -0,0 +1,101 @@
+package chanbackup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// MultiBackupVersion denotes the version of the serialization format of a
+// multi-channel static backup.
+type MultiBackupVersion byte
+
+const (
+	// DefaultMultiVersion is the default version of a multi-channel
+	// static backup.
+	DefaultMultiVersion MultiBackupVersion = 0
+)
+
+// Multi is a static backup of all our channels. Rather than encrypting each
+// Single individually, the set of backups is serialized in its entirety, then
+// encrypted as a whole.
+type Multi struct {
+	// Version is the version of the serialization format of the backup.
+	Version MultiBackupVersion
+
+	// StaticBackups is the set of backups of each of our channels.
+	StaticBackups []Single
+}
+
+// PackToWriter serializes the backup, then encrypts it using the backup key
+// derived from the passed key ring, writing the result to the passed
+// io.Writer.
+func (m *Multi) PackToWriter(w io.Writer, keyRing keychain.ECDHRing) error {
+	if m.Version != DefaultMultiVersion {
+		return fmt.Errorf("unable to serialize multi backup version %v",
+			m.Version)
+	}
+
+	var plainText bytes.Buffer
+	err := writeElements(&plainText, m.Version,
+		uint32(len(m.StaticBackups)))
+	if err != nil {
+		return err
+	}
+	for _, single := range m.StaticBackups {
+		if err := single.Serialize(&plainText); err != nil {
+			return err
+		}
+	}
+
+	return encryptPayloadToWriter(plainText, w, keyRing)
+}
+
+// UnpackFromReader decrypts a backup packed by PackToWriter, read in full from
+// the passed io.Reader, then deserializes it.
+func (m *Multi) UnpackFromReader(r io.Reader,
+	keyRing keychain.ECDHRing) error {
+
+	plainText, err := decryptPayloadFromReader(r, keyRing)
+	if err != nil {
+		return err
+	}
+	backupReader := bytes.NewReader(plainText)
+
+	var numBackups uint32
+	if err := readElements(backupReader, &m.Version); err != nil {
+		return err
+	}
+	if m.Version != DefaultMultiVersion {
+		return fmt.Errorf("unable to deserialize multi backup "+
+			"version %v", m.Version)
+	}
+	if err := readElements(backupReader, &numBackups); err != nil {
+		return err
+	}
+
+	m.StaticBackups = make([]Single, numBackups)
+	for i := range m.StaticBackups {
+		if err := m.StaticBackups[i].Deserialize(backupReader); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PackedMulti is a packed multi-channel static backup.
+type PackedMulti []byte
+
+// Unpack decrypts and deserializes the packed backup using the passed key
+// ring.
+func (p PackedMulti) Unpack(keyRing keychain.ECDHRing) (*Multi, error) {
+	var multi Multi
+	if err := multi.UnpackFromReader(bytes.NewReader(p), keyRing); err != nil {
+		return nil, err
+	}
+
+	return &multi, nil
+}
@@ -0,0 +1,268 @@
+package chanbackup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// SingleBackupVersion denotes the version of the serialization format of a
+// single static channel backup.
+type SingleBackupVersion byte
+
+const (
+	// DefaultSingleVersion is the default version of a single static
+	// channel backup.
+	DefaultSingleVersion SingleBackupVersion = 0
+)
+
+var (
+	// byteOrder is the byte order used to serialize all integers within
+	// our backups.
+	byteOrder = binary.BigEndian
+)
+
+// Single is a static backup of a single channel. It contains all the
+// information we need to recover the funds of the channel should our channel
+// database be lost: the channel's funding outpoint, how to reach the remote
+// peer, and the locators of the keys used within the channel. As the backup
+// doesn't change as the channel is updated, it only needs to be written once
+// when the channel is opened.
+//
+// A Single is unable to restore the channel itself. Instead, we reconnect to
+// the remote peer, and request that it force close the channel, allowing us to
+// sweep our balance from its commitment transaction.
+type Single struct {
+	// Version is the version of the serialization format of the backup.
+	Version SingleBackupVersion
+
+	// ChainHash is the genesis hash of the chain the channel was opened
+	// within.
+	ChainHash chainhash.Hash
+
+	// FundingOutpoint is the outpoint of the channel's funding output.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel.
+	Capacity btcutil.Amount
+
+	// FundingBroadcastHeight is the height at which the funding
+	// transaction was broadcast, before which the funding output can't
+	// have been spent.
+	FundingBroadcastHeight uint32
+
+	// RemoteNodePub is the identity public key of the remote peer.
+	RemoteNodePub *btcec.PublicKey
+
+	// Addresses is the set of addresses the remote peer was last known to
+	// be reachable at.
+	Addresses []*net.TCPAddr
+
+	// MultiSigKeyLoc, CommitKeyLoc and RevocationRootKeyLoc locate the
+	// keys we used within the channel, allowing them to be re-derived
+	// from the wallet's seed.
+	MultiSigKeyLoc       keychain.KeyLocator
+	CommitKeyLoc         keychain.KeyLocator
+	RevocationRootKeyLoc keychain.KeyLocator
+}
+
+// NewSingle creates a new static backup of the passed channel, opened within
+// the chain identified by the passed genesis hash. The remote peer is known to
+// be reachable at the passed addresses.
+func NewSingle(channel *channeldb.OpenChannel, chainHash chainhash.Hash,
+	addrs []*net.TCPAddr) Single {
+
+	return Single{
+		Version:                DefaultSingleVersion,
+		ChainHash:              chainHash,
+		FundingOutpoint:        *channel.ChanID,
+		Capacity:               channel.Capacity,
+		FundingBroadcastHeight: channel.FundingBroadcastHeight,
+		RemoteNodePub:          channel.IdentityPub,
+		Addresses:              addrs,
+		MultiSigKeyLoc:         channel.OurMultiSigKeyLoc,
+		CommitKeyLoc:           channel.OurCommitKeyLoc,
+		RevocationRootKeyLoc:   channel.RevocationRootKeyLoc,
+	}
+}
+
+// Serialize writes the plaintext serialization of the backup to the passed
+// io.Writer.
+func (s *Single) Serialize(w io.Writer) error {
+	if s.Version != DefaultSingleVersion {
+		return fmt.Errorf("unable to serialize backup version %v",
+			s.Version)
+	}
+
+	if len(s.Addresses) > math.MaxUint16 {
+		return fmt.Errorf("too many addresses: %v", len(s.Addresses))
+	}
+
+	err := writeElements(w,
+		s.Version, s.ChainHash, s.FundingOutpoint.Hash,
+		s.FundingOutpoint.Index, uint64(s.Capacity),
+		s.FundingBroadcastHeight,
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.RemoteNodePub.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	if err := writeElements(w, uint16(len(s.Addresses))); err != nil {
+		return err
+	}
+	for _, addr := range s.Addresses {
+		addrStr := []byte(addr.String())
+		if err := writeElements(w, uint16(len(addrStr))); err != nil {
+			return err
+		}
+		if _, err := w.Write(addrStr); err != nil {
+			return err
+		}
+	}
+
+	return writeElements(w,
+		s.MultiSigKeyLoc.Family, s.MultiSigKeyLoc.Index,
+		s.CommitKeyLoc.Family, s.CommitKeyLoc.Index,
+		s.RevocationRootKeyLoc.Family, s.RevocationRootKeyLoc.Index,
+	)
+}
+
+// Deserialize reads the plaintext serialization of a backup from the passed
+// io.Reader.
+func (s *Single) Deserialize(r io.Reader) error {
+	if err := readElements(r, &s.Version); err != nil {
+		return err
+	}
+	if s.Version != DefaultSingleVersion {
+		return fmt.Errorf("unable to deserialize backup version %v",
+			s.Version)
+	}
+
+	var capacity uint64
+	err := readElements(r,
+		&s.ChainHash, &s.FundingOutpoint.Hash,
+		&s.FundingOutpoint.Index, &capacity,
+		&s.FundingBroadcastHeight,
+	)
+	if err != nil {
+		return err
+	}
+	s.Capacity = btcutil.Amount(capacity)
+
+	var pubBytes [33]byte
+	if _, err := io.ReadFull(r, pubBytes[:]); err != nil {
+		return err
+	}
+	s.RemoteNodePub, err = btcec.ParsePubKey(pubBytes[:], btcec.S256())
+	if err != nil {
+		return err
+	}
+
+	var numAddrs uint16
+	if err := readElements(r, &numAddrs); err != nil {
+		return err
+	}
+	s.Addresses = make([]*net.TCPAddr, 0, numAddrs)
+	for i := uint16(0); i < numAddrs; i++ {
+		var addrLen uint16
+		if err := readElements(r, &addrLen); err != nil {
+			return err
+		}
+		addrStr := make([]byte, addrLen)
+		if _, err := io.ReadFull(r, addrStr); err != nil {
+			return err
+		}
+
+		addr, err := net.ResolveTCPAddr("tcp", string(addrStr))
+		if err != nil {
+			return err
+		}
+		s.Addresses = append(s.Addresses, addr)
+	}
+
+	return readElements(r,
+		&s.MultiSigKeyLoc.Family, &s.MultiSigKeyLoc.Index,
+		&s.CommitKeyLoc.Family, &s.CommitKeyLoc.Index,
+		&s.RevocationRootKeyLoc.Family, &s.RevocationRootKeyLoc.Index,
+	)
+}
+
+// writeElements writes each of the passed fixed size elements to the passed
+// io.Writer.
+func writeElements(w io.Writer, elements ...interface{}) error {
+	for _, element := range elements {
+		if err := binary.Write(w, byteOrder, element); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readElements reads each of the passed fixed size elements from the passed
+// io.Reader.
+func readElements(r io.Reader, elements ...interface{}) error {
+	for _, element := range elements {
+		if err := binary.Read(r, byteOrder, element); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PackToWriter serializes the backup, then encrypts it using the backup key
+// derived from the passed key ring, writing the result to the passed
+// io.Writer.
+func (s *Single) PackToWriter(w io.Writer, keyRing keychain.ECDHRing) error {
+	var plainText bytes.Buffer
+	if err := s.Serialize(&plainText); err != nil {
+		return err
+	}
+
+	return encryptPayloadToWriter(plainText, w, keyRing)
+}
+
+// UnpackFromReader decrypts a backup packed by PackToWriter, read in full from
+// the passed io.Reader, then deserializes it.
+func (s *Single) UnpackFromReader(r io.Reader,
+	keyRing keychain.ECDHRing) error {
+
+	plainText, err := decryptPayloadFromReader(r, keyRing)
+	if err != nil {
+		return err
+	}
+
+	return s.Deserialize(bytes.NewReader(plainText))
+}
+
+// PackedSingles is a set of packed static channel backups.
+type PackedSingles [][]byte
+
+// Unpack decrypts and deserializes each of the packed backups using the
+// passed key ring.
+func (p PackedSingles) Unpack(keyRing keychain.ECDHRing) ([]Single, error) {
+	singles := make([]Single, len(p))
+	for i, packed := range p {
+		err := singles[i].UnpackFromReader(bytes.NewReader(packed),
+			keyRing)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return singles, nil
+}
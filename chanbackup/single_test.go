@@ -0,0 +1,135 @@
+package chanbackup
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+// newTestKeyRing creates a new key ring from a seed filled with the passed
+// byte.
+func newTestKeyRing(t *testing.T, seedByte byte) keychain.SecretKeyRing {
+	seed := bytes.Repeat([]byte{seedByte}, 32)
+	root, err := hdkeychain.NewMaster(seed, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create root key: %v", err)
+	}
+
+	store := keychain.NewMemIndexStore()
+	keyRing, err := keychain.NewHDKeyRing(root, store)
+	if err != nil {
+		t.Fatalf("unable to create key ring: %v", err)
+	}
+
+	return keyRing
+}
+
+// newTestSingle creates a static channel backup populated with test data.
+func newTestSingle(t *testing.T, index uint32) Single {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", "10.0.0.2:9000")
+	if err != nil {
+		t.Fatalf("unable to resolve addr: %v", err)
+	}
+
+	return Single{
+		Version:   DefaultSingleVersion,
+		ChainHash: *chaincfg.RegressionNetParams.GenesisHash,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  [32]byte{0x01, byte(index)},
+			Index: index,
+		},
+		Capacity:               5000000,
+		FundingBroadcastHeight: 100 + index,
+		RemoteNodePub:          priv.PubKey(),
+		Addresses:              []*net.TCPAddr{addr},
+		MultiSigKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyMultiSig,
+			Index:  index,
+		},
+		CommitKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyCommitKey,
+			Index:  index,
+		},
+		RevocationRootKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyRevocationRoot,
+			Index:  index,
+		},
+	}
+}
+
+// TestSinglePackUnpack tests that a packed static channel backup can be
+// unpacked using the key ring it was packed with, but not any other.
+func TestSinglePackUnpack(t *testing.T) {
+	t.Parallel()
+
+	keyRing := newTestKeyRing(t, 0x01)
+	single := newTestSingle(t, 1)
+
+	var b bytes.Buffer
+	if err := single.PackToWriter(&b, keyRing); err != nil {
+		t.Fatalf("unable to pack backup: %v", err)
+	}
+	packed := PackedSingles{b.Bytes()}
+
+	unpacked, err := packed.Unpack(keyRing)
+	if err != nil {
+		t.Fatalf("unable to unpack backup: %v", err)
+	}
+	if !reflect.DeepEqual(single, unpacked[0]) {
+		t.Fatalf("backups don't match: expected %v, got %v", single,
+			unpacked[0])
+	}
+
+	// A key ring derived from another seed shouldn't be able to decrypt
+	// the backup.
+	if _, err := packed.Unpack(newTestKeyRing(t, 0x02)); err == nil {
+		t.Fatalf("backup unpacked with the wrong key ring")
+	}
+
+	// Tampering with the packed backup should also be detected.
+	tampered := append([]byte(nil), b.Bytes()...)
+	tampered[len(tampered)-1] ^= 0x01
+	if _, err := (PackedSingles{tampered}).Unpack(keyRing); err == nil {
+		t.Fatalf("tampered backup unpacked")
+	}
+}
+
+// TestMultiPackUnpack tests that a multi-channel static backup survives being
+// packed and unpacked.
+func TestMultiPackUnpack(t *testing.T) {
+	t.Parallel()
+
+	keyRing := newTestKeyRing(t, 0x01)
+
+	multi := Multi{Version: DefaultMultiVersion}
+	for i := uint32(0); i < 3; i++ {
+		multi.StaticBackups = append(multi.StaticBackups,
+			newTestSingle(t, i))
+	}
+
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, keyRing); err != nil {
+		t.Fatalf("unable to pack backup: %v", err)
+	}
+
+	unpacked, err := PackedMulti(b.Bytes()).Unpack(keyRing)
+	if err != nil {
+		t.Fatalf("unable to unpack backup: %v", err)
+	}
+	if !reflect.DeepEqual(&multi, unpacked) {
+		t.Fatalf("backups don't match: expected %v, got %v", multi,
+			unpacked)
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// chanBackupSnapshot is a snapshot of our static channel backups, sent to
+// subscribers each time the backup file has been updated.
+type chanBackupSnapshot struct {
+	// chanPoints is the set of channels included within the backup.
+	chanPoints []wire.OutPoint
+
+	// packedMulti is the encrypted multi-channel backup of the channels.
+	packedMulti chanbackup.PackedMulti
+}
+
+// chanBackupManager maintains the static backups of our channels. Each time a
+// channel is opened or closed, the multi-channel backup of all our channels
+// is regenerated and atomically swapped into the backup file, and the new
+// backup is sent to all subscribed clients. Channels which are still being
+// restored from a prior backup remain within the backup until they've been
+// closed, ensuring a restore can be repeated.
+type chanBackupManager struct {
+	started uint32
+	stopped uint32
+
+	chanDB     *channeldb.DB
+	keyRing    keychain.ECDHRing
+	chainHash  chainhash.Hash
+	backupFile *chanbackup.MultiFile
+
+	// pendingRestores returns the backups of the channels which are being
+	// restored, and so are no longer present within our database.
+	pendingRestores func() []chanbackup.Single
+
+	// updates is signalled each time our set of channels has changed. As
+	// each update regenerates the backup in its entirety, multiple
+	// signals received while an update is in progress are coalesced.
+	updates chan struct{}
+
+	clientMtx           sync.Mutex
+	nextClientID        uint32
+	notificationClients map[uint32]*chanBackupSubscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChanBackupManager creates a new chanBackupManager which writes the
+// backups of all channels within the passed database to the passed backup
+// file, along with the backups returned by pendingRestores. The backups are
+// encrypted using a key derived from the passed key ring.
+func newChanBackupManager(chanDB *channeldb.DB, keyRing keychain.ECDHRing,
+	chainHash chainhash.Hash, backupFile *chanbackup.MultiFile,
+	pendingRestores func() []chanbackup.Single) *chanBackupManager {
+
+	return &chanBackupManager{
+		chanDB:              chanDB,
+		keyRing:             keyRing,
+		chainHash:           chainHash,
+		backupFile:          backupFile,
+		pendingRestores:     pendingRestores,
+		updates:             make(chan struct{}, 1),
+		notificationClients: make(map[uint32]*chanBackupSubscription),
+		quit:                make(chan struct{}),
+	}
+}
+
+// Start writes a fresh backup of our current set of channels, then launches
+// the goroutine which updates the backup as channels are opened and closed.
+// An existing backup is left untouched if we don't yet have any channels, nor
+// any channels being restored.
+func (c *chanBackupManager) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	chbuLog.Tracef("Starting channel backup manager")
+
+	// If we have no channels, but a backup file already exists, then our
+	// channel database may have been lost, so we'll refrain from
+	// overwriting the backup until a channel is opened, ensuring it
+	// remains available to be restored from.
+	singles, err := c.fetchSingles()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(c.backupFile.FileName())
+	switch {
+	case len(singles) == 0 && err == nil:
+		chbuLog.Infof("No channels found, leaving existing channel "+
+			"backup %v untouched", c.backupFile.FileName())
+
+	default:
+		if err := c.updateBackup(); err != nil {
+			return err
+		}
+	}
+
+	c.wg.Add(1)
+	go c.backupUpdater()
+
+	return nil
+}
+
+// Stop gracefully shuts down the chanBackupManager.
+func (c *chanBackupManager) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	chbuLog.Infof("Channel backup manager shutting down")
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// channelsChanged signals that a channel has been opened or closed, and so
+// the backup should be updated. This method never blocks.
+func (c *chanBackupManager) channelsChanged() {
+	select {
+	case c.updates <- struct{}{}:
+	default:
+	}
+}
+
+// backupUpdater updates the backup each time our set of channels changes.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chanBackupManager) backupUpdater() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.updates:
+			if err := c.updateBackup(); err != nil {
+				chbuLog.Errorf("unable to update channel "+
+					"backup: %v", err)
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// fetchSingles creates a static backup of each of our channels, including
+// those which are still pending, merged with the backups of the channels
+// we're restoring.
+func (c *chanBackupManager) fetchSingles() ([]chanbackup.Single, error) {
+	dbChannels, err := c.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	singles := make([]chanbackup.Single, 0, len(dbChannels))
+	knownChans := make(map[wire.OutPoint]struct{})
+	for _, dbChannel := range dbChannels {
+		knownChans[*dbChannel.ChanID] = struct{}{}
+
+		// If we don't know the addresses of the remote peer, then the
+		// backup is still useful, as the peer may reconnect to us, so
+		// we'll include it regardless.
+		var addrs []*net.TCPAddr
+		linkNode, err := c.chanDB.FetchLinkNode(dbChannel.IdentityPub)
+		if err == nil {
+			addrs = linkNode.Addresses
+		} else {
+			chbuLog.Warnf("unable to fetch addresses of peer %x "+
+				"for ChannelPoint(%v): %v",
+				dbChannel.IdentityPub.SerializeCompressed(),
+				dbChannel.ChanID, err)
+		}
+
+		singles = append(singles, chanbackup.NewSingle(dbChannel,
+			c.chainHash, addrs))
+	}
+
+	// A channel being restored is no longer within our database, so
+	// its backup is carried over as is until the channel has been
+	// closed.
+	if c.pendingRestores == nil {
+		return singles, nil
+	}
+	for _, single := range c.pendingRestores() {
+		if _, ok := knownChans[single.FundingOutpoint]; ok {
+			continue
+		}
+		knownChans[single.FundingOutpoint] = struct{}{}
+
+		singles = append(singles, single)
+	}
+
+	return singles, nil
+}
+
+// updateBackup regenerates the backup of all our channels, atomically swaps
+// it into the backup file, then notifies all subscribed clients.
+func (c *chanBackupManager) updateBackup() error {
+	singles, err := c.fetchSingles()
+	if err != nil {
+		return err
+	}
+
+	multi := chanbackup.Multi{
+		Version:       chanbackup.DefaultMultiVersion,
+		StaticBackups: singles,
+	}
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, c.keyRing); err != nil {
+		return err
+	}
+
+	if err := c.backupFile.UpdateAndSwap(b.Bytes()); err != nil {
+		return err
+	}
+
+	chbuLog.Infof("Updated channel backup %v with %v channels",
+		c.backupFile.FileName(), len(singles))
+
+	snapshot := &chanBackupSnapshot{
+		chanPoints:  make([]wire.OutPoint, len(singles)),
+		packedMulti: b.Bytes(),
+	}
+	for i, single := range singles {
+		snapshot.chanPoints[i] = single.FundingOutpoint
+	}
+	c.notifyClients(snapshot)
+
+	return nil
+}
+
+// exportSingle returns the packed static backup of the channel identified by
+// the passed channel point.
+func (c *chanBackupManager) exportSingle(chanPoint wire.OutPoint) ([]byte, error) {
+	singles, err := c.fetchSingles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, single := range singles {
+		if single.FundingOutpoint != chanPoint {
+			continue
+		}
+
+		var b bytes.Buffer
+		if err := single.PackToWriter(&b, c.keyRing); err != nil {
+			return nil, err
+		}
+
+		return b.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unable to find ChannelPoint(%v)", chanPoint)
+}
+
+// notifyClients sends the passed snapshot to all currently registered
+// subscription clients.
+func (c *chanBackupManager) notifyClients(snapshot *chanBackupSnapshot) {
+	c.clientMtx.Lock()
+	defer c.clientMtx.Unlock()
+
+	for _, client := range c.notificationClients {
+		go func(client *chanBackupSubscription) {
+			select {
+			case client.Snapshots <- snapshot:
+			case <-client.cancel:
+			case <-c.quit:
+			}
+		}(client)
+	}
+}
+
+// chanBackupSubscription represents an intent to receive a snapshot of our
+// static channel backups each time they're updated.
+type chanBackupSubscription struct {
+	Snapshots chan *chanBackupSnapshot
+
+	cancel chan struct{}
+
+	mgr *chanBackupManager
+	id  uint32
+}
+
+// Cancel unregisters the chanBackupSubscription, freeing any previously
+// allocated resources.
+func (s *chanBackupSubscription) Cancel() {
+	s.mgr.clientMtx.Lock()
+	delete(s.mgr.notificationClients, s.id)
+	s.mgr.clientMtx.Unlock()
+
+	close(s.cancel)
+}
+
+// subscribe returns a chanBackupSubscription which allows the caller to
+// receive async notifications each time the backup is updated.
+func (c *chanBackupManager) subscribe() *chanBackupSubscription {
+	client := &chanBackupSubscription{
+		Snapshots: make(chan *chanBackupSnapshot),
+		cancel:    make(chan struct{}),
+		mgr:       c,
+	}
+
+	c.clientMtx.Lock()
+	c.notificationClients[c.nextClientID] = client
+	client.id = c.nextClientID
+	c.nextClientID++
+	c.clientMtx.Unlock()
+
+	return client
+}
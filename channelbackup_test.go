@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+// backupTestTimeout is the duration a test waits for a backup to be updated
+// before failing.
+const backupTestTimeout = 5 * time.Second
+
+// newTestKeyRing creates a new key ring from a seed filled with the passed
+// byte.
+func newTestKeyRing(t *testing.T, seedByte byte) keychain.SecretKeyRing {
+	seed := bytes.Repeat([]byte{seedByte}, 32)
+	root, err := hdkeychain.NewMaster(seed, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create root key: %v", err)
+	}
+
+	store := keychain.NewMemIndexStore()
+	keyRing, err := keychain.NewHDKeyRing(root, store)
+	if err != nil {
+		t.Fatalf("unable to create key ring: %v", err)
+	}
+
+	return keyRing
+}
+
+// newTestChannelDB opens a channel database within a new temporary
+// directory, returning the directory along with a closure which closes and
+// removes the database.
+func newTestChannelDB(t *testing.T) (*channeldb.DB, string, func()) {
+	tempDir, err := ioutil.TempDir("", "chanbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+
+	return cdb, tempDir, func() {
+		cdb.Close()
+		os.RemoveAll(tempDir)
+	}
+}
+
+// newTestChannel writes a pending channel with the passed channel point to
+// the database, shared with a new remote peer reachable at the passed
+// address.
+func newTestChannel(t *testing.T, cdb *channeldb.DB, chanPoint wire.OutPoint,
+	addr *net.TCPAddr) *channeldb.OpenChannel {
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	pub := priv.PubKey()
+
+	producer := shachain.NewRevocationProducer(chainhash.Hash{0x01})
+
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&wire.TxIn{PreviousOutPoint: chanPoint})
+	commitTx.AddTxOut(&wire.TxOut{Value: 1000000})
+
+	channel := &channeldb.OpenChannel{
+		IsInitiator:            true,
+		IsPending:              true,
+		ChanType:               channeldb.SingleFunder,
+		IdentityPub:            pub,
+		ChanID:                 &chanPoint,
+		FundingOutpoint:        &chanPoint,
+		Capacity:               btcutil.Amount(1000000),
+		OurBalance:             btcutil.Amount(1000000),
+		OurCommitKey:           pub,
+		TheirCommitKey:         pub,
+		OurMultiSigKey:         pub,
+		TheirMultiSigKey:       pub,
+		TheirCurrentRevocation: pub,
+		OurCommitTx:            commitTx,
+		OurCommitSig:           bytes.Repeat([]byte{1}, 71),
+		RevocationProducer:     producer,
+		RevocationStore:        shachain.NewRevocationStore(),
+		FundingWitnessScript:   []byte{0x00},
+		OurDeliveryScript:      []byte{0x00},
+		TheirDeliveryScript:    []byte{0x00},
+		FundingBroadcastHeight: 100,
+		NumConfsRequired:       1,
+		LocalCsvDelay:          144,
+		RemoteCsvDelay:         144,
+		OurMultiSigKeyLoc:      keychain.KeyLocator{Family: keychain.KeyFamilyMultiSig},
+		OurCommitKeyLoc:        keychain.KeyLocator{Family: keychain.KeyFamilyCommitKey},
+		RevocationRootKeyLoc:   keychain.KeyLocator{Family: keychain.KeyFamilyRevocationRoot},
+		CreationTime:           time.Unix(0, 0),
+		Db:                     cdb,
+	}
+	if err := channel.SyncPending(addr); err != nil {
+		t.Fatalf("unable to write channel: %v", err)
+	}
+
+	return channel
+}
+
+// newTestRestoreSingle creates the static backup of a channel which isn't
+// present within our database, as if it's being restored.
+func newTestRestoreSingle(t *testing.T, index uint32) chanbackup.Single {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	return chanbackup.Single{
+		Version:   chanbackup.DefaultSingleVersion,
+		ChainHash: *activeNetParams.GenesisHash,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  chainhash.Hash{0x02, byte(index)},
+			Index: index,
+		},
+		Capacity:               btcutil.Amount(500000),
+		FundingBroadcastHeight: 100,
+		RemoteNodePub:          priv.PubKey(),
+		MultiSigKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyMultiSig,
+			Index:  index,
+		},
+		CommitKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyCommitKey,
+			Index:  index,
+		},
+		RevocationRootKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyRevocationRoot,
+			Index:  index,
+		},
+	}
+}
+
+// assertBackupChans asserts that the backup file holds the backups of
+// exactly the passed channels.
+func assertBackupChans(t *testing.T, backupFile *chanbackup.MultiFile,
+	keyRing keychain.SecretKeyRing, chanPoints ...wire.OutPoint) {
+
+	multi, err := backupFile.ExtractMulti(keyRing)
+	if err != nil {
+		t.Fatalf("unable to extract backup: %v", err)
+	}
+
+	backedUp := make(map[wire.OutPoint]struct{})
+	for _, single := range multi.StaticBackups {
+		backedUp[single.FundingOutpoint] = struct{}{}
+	}
+	if len(backedUp) != len(multi.StaticBackups) {
+		t.Fatalf("backup contains duplicate channels: %v",
+			multi.StaticBackups)
+	}
+	if len(backedUp) != len(chanPoints) {
+		t.Fatalf("expected %v channels within backup, found %v",
+			len(chanPoints), len(backedUp))
+	}
+	for _, chanPoint := range chanPoints {
+		if _, ok := backedUp[chanPoint]; !ok {
+			t.Fatalf("ChannelPoint(%v) missing from backup",
+				chanPoint)
+		}
+	}
+}
+
+// TestChanBackupMergesPendingRestores tests that the backups of channels
+// being restored are included within the backup alongside the channels
+// within our database, without duplicating any channel present in both.
+func TestChanBackupMergesPendingRestores(t *testing.T) {
+	cdb, tempDir, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9735}
+	dbChannel := newTestChannel(t, cdb, wire.OutPoint{Index: 1}, addr)
+
+	// One of the channels being restored has since been re-added to our
+	// database, so only the backup created from the database should be
+	// included.
+	restored := newTestRestoreSingle(t, 2)
+	stale := newTestRestoreSingle(t, 3)
+	stale.FundingOutpoint = *dbChannel.ChanID
+
+	backupFile := chanbackup.NewMultiFile(
+		filepath.Join(tempDir, "channel.backup"),
+	)
+	keyRing := newTestKeyRing(t, 0x01)
+	mgr := newChanBackupManager(cdb, keyRing,
+		*activeNetParams.GenesisHash, backupFile,
+		func() []chanbackup.Single {
+			return []chanbackup.Single{restored, stale}
+		},
+	)
+
+	singles, err := mgr.fetchSingles()
+	if err != nil {
+		t.Fatalf("unable to fetch backups: %v", err)
+	}
+	if len(singles) != 2 {
+		t.Fatalf("expected 2 backups, found %v", len(singles))
+	}
+	if !singles[0].RemoteNodePub.IsEqual(dbChannel.IdentityPub) {
+		t.Fatalf("backup of ChannelPoint(%v) not created from the "+
+			"database", dbChannel.ChanID)
+	}
+	if len(singles[0].Addresses) != 1 ||
+		singles[0].Addresses[0].String() != addr.String() {
+
+		t.Fatalf("expected peer address %v, found %v", addr,
+			singles[0].Addresses)
+	}
+
+	if err := mgr.updateBackup(); err != nil {
+		t.Fatalf("unable to update backup: %v", err)
+	}
+	assertBackupChans(t, backupFile, keyRing, *dbChannel.ChanID,
+		restored.FundingOutpoint)
+}
+
+// TestChanBackupStartKeepsExistingBackup tests that starting with an empty
+// database leaves an existing backup untouched, unless channels are being
+// restored, in which case the backup is rewritten to include them.
+func TestChanBackupStartKeepsExistingBackup(t *testing.T) {
+	cdb, tempDir, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	keyRing := newTestKeyRing(t, 0x01)
+	backupFile := chanbackup.NewMultiFile(
+		filepath.Join(tempDir, "channel.backup"),
+	)
+
+	// Write a backup of a channel which has been lost from our database.
+	lost := newTestRestoreSingle(t, 1)
+	multi := chanbackup.Multi{
+		Version:       chanbackup.DefaultMultiVersion,
+		StaticBackups: []chanbackup.Single{lost},
+	}
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, keyRing); err != nil {
+		t.Fatalf("unable to pack backup: %v", err)
+	}
+	if err := backupFile.UpdateAndSwap(b.Bytes()); err != nil {
+		t.Fatalf("unable to write backup: %v", err)
+	}
+
+	var pending []chanbackup.Single
+	mgr := newChanBackupManager(cdb, keyRing,
+		*activeNetParams.GenesisHash, backupFile,
+		func() []chanbackup.Single {
+			return pending
+		},
+	)
+	if err := mgr.Start(); err != nil {
+		t.Fatalf("unable to start backup manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	packed, err := ioutil.ReadFile(backupFile.FileName())
+	if err != nil {
+		t.Fatalf("unable to read backup: %v", err)
+	}
+	if !bytes.Equal(packed, b.Bytes()) {
+		t.Fatalf("existing backup overwritten")
+	}
+
+	// Once the lost channel is being restored, along with another, the
+	// backup should be updated to include both.
+	other := newTestRestoreSingle(t, 2)
+	mgr.Stop()
+	pending = []chanbackup.Single{lost, other}
+	mgr = newChanBackupManager(cdb, keyRing,
+		*activeNetParams.GenesisHash, backupFile,
+		func() []chanbackup.Single {
+			return pending
+		},
+	)
+	if err := mgr.Start(); err != nil {
+		t.Fatalf("unable to start backup manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	assertBackupChans(t, backupFile, keyRing, lost.FundingOutpoint,
+		other.FundingOutpoint)
+}
+
+// TestChanBackupSubscription tests that subscribers are sent a snapshot of
+// the backup each time our set of channels changes, and that the backup of a
+// single channel can be exported.
+func TestChanBackupSubscription(t *testing.T) {
+	cdb, tempDir, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	keyRing := newTestKeyRing(t, 0x01)
+	backupFile := chanbackup.NewMultiFile(
+		filepath.Join(tempDir, "channel.backup"),
+	)
+	mgr := newChanBackupManager(cdb, keyRing,
+		*activeNetParams.GenesisHash, backupFile, nil)
+	if err := mgr.Start(); err != nil {
+		t.Fatalf("unable to start backup manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	client := mgr.subscribe()
+	defer client.Cancel()
+
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9735}
+	dbChannel := newTestChannel(t, cdb, wire.OutPoint{Index: 1}, addr)
+	mgr.channelsChanged()
+
+	select {
+	case snapshot := <-client.Snapshots:
+		if len(snapshot.chanPoints) != 1 ||
+			snapshot.chanPoints[0] != *dbChannel.ChanID {
+
+			t.Fatalf("expected snapshot of ChannelPoint(%v), "+
+				"found %v", dbChannel.ChanID,
+				snapshot.chanPoints)
+		}
+
+		multi, err := snapshot.packedMulti.Unpack(keyRing)
+		if err != nil {
+			t.Fatalf("unable to unpack snapshot: %v", err)
+		}
+		if len(multi.StaticBackups) != 1 {
+			t.Fatalf("expected 1 backup within snapshot, "+
+				"found %v", len(multi.StaticBackups))
+		}
+
+	case <-time.After(backupTestTimeout):
+		t.Fatalf("snapshot not sent")
+	}
+	assertBackupChans(t, backupFile, keyRing, *dbChannel.ChanID)
+
+	packed, err := mgr.exportSingle(*dbChannel.ChanID)
+	if err != nil {
+		t.Fatalf("unable to export backup: %v", err)
+	}
+	singles, err := chanbackup.PackedSingles{packed}.Unpack(keyRing)
+	if err != nil {
+		t.Fatalf("unable to unpack backup: %v", err)
+	}
+	if singles[0].FundingOutpoint != *dbChannel.ChanID {
+		t.Fatalf("expected backup of ChannelPoint(%v), found %v",
+			dbChannel.ChanID, singles[0].FundingOutpoint)
+	}
+
+	if _, err := mgr.exportSingle(wire.OutPoint{Index: 2}); err == nil {
+		t.Fatalf("exported backup of unknown channel")
+	}
+}
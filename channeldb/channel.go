@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
@@ -57,17 +59,23 @@ var (
 	// sequential prefix scans, and second to eliminate write amplification
 	// caused by serializing/deserializing the *entire* struct with each
 	// update.
-	chanCapacityPrefix   = []byte("ccp")
-	selfBalancePrefix    = []byte("sbp")
-	theirBalancePrefix   = []byte("tbp")
-	minFeePerKbPrefix    = []byte("mfp")
-	theirDustLimitPrefix = []byte("tdlp")
-	ourDustLimitPrefix   = []byte("odlp")
-	updatePrefix         = []byte("uup")
-	satSentPrefix        = []byte("ssp")
-	satReceivedPrefix    = []byte("srp")
-	netFeesPrefix        = []byte("ntp")
-	isPendingPrefix      = []byte("pdg")
+	chanCapacityPrefix     = []byte("ccp")
+	selfBalancePrefix      = []byte("sbp")
+	theirBalancePrefix     = []byte("tbp")
+	minFeePerKbPrefix      = []byte("mfp")
+	theirDustLimitPrefix   = []byte("tdlp")
+	ourDustLimitPrefix     = []byte("odlp")
+	updatePrefix           = []byte("uup")
+	satSentPrefix          = []byte("ssp")
+	satReceivedPrefix      = []byte("srp")
+	netFeesPrefix          = []byte("ntp")
+	isPendingPrefix        = []byte("pdg")
+	ourConstraintsPrefix   = []byte("occ")
+	theirConstraintsPrefix = []byte("tcc")
+	broadcastHeightPrefix  = []byte("bhp")
+	isPrivatePrefix        = []byte("prv")
+	fundingStatePrefix     = []byte("fsp")
+	commitBroadcastPrefix  = []byte("cbp")
 
 	// chanIDKey stores the node, and channelID for an active channel.
 	chanIDKey = []byte("cik")
@@ -96,6 +104,10 @@ var (
 	// deliveryScriptsKey stores the scripts for the final delivery in the
 	// case of a cooperative closure.
 	deliveryScriptsKey = []byte("dsk")
+
+	// keyLocatorsKey stores the locators of the keys we derived from the
+	// wallet's key ring for the channel.
+	keyLocatorsKey = []byte("klk")
 )
 
 // ChannelType is an enum-like type that describes one of several possible
@@ -119,6 +131,72 @@ const (
 	DualFunder = 1
 )
 
+// FundingState describes how far a channel has progressed through the final
+// stages of the funding flow, allowing the flow to be resumed from where it
+// left off after a restart.
+type FundingState uint8
+
+const (
+	// NOTE: iota isn't used here for this enum needs to be stable
+	// long-term as it will be persisted to the database.
+
+	// FundingBroadcast indicates that the funding transaction has been
+	// broadcast, and the channel is awaiting its confirmation.
+	FundingBroadcast FundingState = 0
+
+	// FundingConfirmed indicates that the funding transaction has reached
+	// the required number of confirmations, and the channel has been
+	// marked open, but FundingLocked has yet to be sent to the remote
+	// party.
+	FundingConfirmed FundingState = 1
+
+	// FundingLockedSent indicates that FundingLocked has been sent to the
+	// remote party, but the channel has yet to be announced.
+	FundingLockedSent FundingState = 2
+
+	// FundingAnnounced indicates that the channel has been announced, and
+	// the funding flow is complete.
+	FundingAnnounced FundingState = 3
+)
+
+// String returns a human readable version of the FundingState.
+func (f FundingState) String() string {
+	switch f {
+	case FundingBroadcast:
+		return "FundingBroadcast"
+	case FundingConfirmed:
+		return "FundingConfirmed"
+	case FundingLockedSent:
+		return "FundingLockedSent"
+	case FundingAnnounced:
+		return "FundingAnnounced"
+	default:
+		return fmt.Sprintf("FundingState(%d)", uint8(f))
+	}
+}
+
+// ChannelConstraints is a set of flow control constraints one party of a
+// channel imposes upon the other for the lifetime of the channel. The
+// constraints allow a party to bound its exposure to HTLCs offered by its
+// counterparty, and to ensure the counterparty always has funds at stake
+// within the channel. A zero value for any of the constraints indicates that
+// it wasn't negotiated.
+type ChannelConstraints struct {
+	// MaxPendingAmount is the maximum total value of outstanding HTLCs
+	// that may be offered by the constrained party at any given time.
+	MaxPendingAmount btcutil.Amount
+
+	// ChanReserve is the minimum balance the constrained party must
+	// retain within their output of the commitment transaction. HTLCs
+	// which would cause the balance to dip below the reserve are
+	// rejected.
+	ChanReserve btcutil.Amount
+
+	// MaxAcceptedHtlcs is the maximum number of outstanding HTLCs that may
+	// be offered by the constrained party at any given time.
+	MaxAcceptedHtlcs uint16
+}
+
 // OpenChannel encapsulates the persistent and dynamic state of an open channel
 // with a remote node. An open channel supports several options for on-disk
 // serialization depending on the exact context. Full (upon channel creation)
@@ -151,6 +229,16 @@ type OpenChannel struct {
 	// this amount are not enforceable onchain from out point of view.
 	OurDustLimit btcutil.Amount
 
+	// OurConstraints are the constraints we've imposed upon the remote
+	// party. They bound the HTLCs the remote party may offer to us, along
+	// with the balance they must retain within the channel.
+	OurConstraints ChannelConstraints
+
+	// TheirConstraints are the constraints the remote party has imposed
+	// upon us. They bound the HTLCs we may offer to the remote party,
+	// along with the balance we must retain within the channel.
+	TheirConstraints ChannelConstraints
+
 	// OurCommitKey is the key to be used within our commitment transaction
 	// to generate the scripts for outputs paying to ourself, and
 	// revocation clauses.
@@ -199,6 +287,31 @@ type OpenChannel struct {
 	// confirmed.
 	IsPending bool
 
+	// FundingState is the stage of the funding flow the channel has
+	// reached.
+	FundingState FundingState
+
+	// ShortChanID is the short channel ID of the channel, which encodes the
+	// location of the funding output within the chain. It's only known
+	// once the funding transaction has confirmed.
+	ShortChanID lnwire.ShortChannelID
+
+	// IsPrivate indicates that the channel is private, meaning it isn't
+	// announced to the rest of the network. As such, it's only known to
+	// the two parties of the channel.
+	IsPrivate bool
+
+	// CommitmentBroadcast indicates that we've broadcast our commitment
+	// transaction in order to force close the channel, and are now
+	// waiting for the channel to be resolved on-chain.
+	CommitmentBroadcast bool
+
+	// FundingBroadcastHeight is the height of the best known block at the
+	// time the funding transaction was broadcast. As the funding output
+	// can't have been spent before this height, it serves as a hint for
+	// where to begin scanning the chain for spends of the funding output.
+	FundingBroadcastHeight uint32
+
 	// FundingOutpoint is the outpoint of the final funding transaction.
 	FundingOutpoint *wire.OutPoint
 
@@ -210,6 +323,15 @@ type OpenChannel struct {
 	// transaction for the remote party.
 	TheirMultiSigKey *btcec.PublicKey
 
+	// OurMultiSigKeyLoc, OurCommitKeyLoc and RevocationRootKeyLoc locate
+	// the keys derived from the wallet's key ring for the channel,
+	// allowing them to be re-derived from the wallet's seed alone.
+	// Channels created before the introduction of the key ring leave
+	// these as zero values.
+	OurMultiSigKeyLoc    keychain.KeyLocator
+	OurCommitKeyLoc      keychain.KeyLocator
+	RevocationRootKeyLoc keychain.KeyLocator
+
 	// FundingWitnessScript is the full witness script used within the
 	// funding transaction.
 	FundingWitnessScript []byte
@@ -344,31 +466,38 @@ func (c *OpenChannel) SyncPending(addr *net.TCPAddr) error {
 	defer c.Unlock()
 
 	return c.Db.Update(func(tx *bolt.Tx) error {
-		// First, sync all the persistent channel state to disk.
-		if err := c.fullSync(tx); err != nil {
-			return err
-		}
+		return c.syncPending(tx, addr)
+	})
+}
 
-		nodeInfoBucket, err := tx.CreateBucketIfNotExists(nodeInfoBucket)
-		if err != nil {
-			return err
-		}
+// syncPending is an internal version of the SyncPending method which allows
+// callers to sync a pending channel while re-using an existing database
+// transaction.
+func (c *OpenChannel) syncPending(tx *bolt.Tx, addr *net.TCPAddr) error {
+	// First, sync all the persistent channel state to disk.
+	if err := c.fullSync(tx); err != nil {
+		return err
+	}
 
-		// If a LinkNode for this identity public key already exsits, then
-		// we can exit early.
-		nodePub := c.IdentityPub.SerializeCompressed()
-		if nodeInfoBucket.Get(nodePub) != nil {
-			return nil
-		}
+	nodeInfoBucket, err := tx.CreateBucketIfNotExists(nodeInfoBucket)
+	if err != nil {
+		return err
+	}
 
-		// Next, we need to establish a (possibly) new LinkNode
-		// relationship for this channel. The LinkNode metadata contains
-		// reachability, up-time, and service bits related information.
-		// TODO(roasbeef): net info shuld be in lnwire.NetAddress
-		linkNode := c.Db.NewLinkNode(wire.MainNet, c.IdentityPub, addr)
+	// If a LinkNode for this identity public key already exsits, then
+	// we can exit early.
+	nodePub := c.IdentityPub.SerializeCompressed()
+	if nodeInfoBucket.Get(nodePub) != nil {
+		return nil
+	}
 
-		return putLinkNode(nodeInfoBucket, linkNode)
-	})
+	// Next, we need to establish a (possibly) new LinkNode
+	// relationship for this channel. The LinkNode metadata contains
+	// reachability, up-time, and service bits related information.
+	// TODO(roasbeef): net info shuld be in lnwire.NetAddress
+	linkNode := c.Db.NewLinkNode(wire.MainNet, c.IdentityPub, addr)
+
+	return putLinkNode(nodeInfoBucket, linkNode)
 }
 
 // UpdateCommitment updates the on-disk state of our currently broadcastable
@@ -802,6 +931,9 @@ func putOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := putChanOurDustLimit(openChanBucket, channel); err != nil {
 		return err
 	}
+	if err := putChanConstraints(openChanBucket, channel); err != nil {
+		return err
+	}
 	if err := putChanNumUpdates(openChanBucket, channel); err != nil {
 		return err
 	}
@@ -811,6 +943,15 @@ func putOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := putChanIsPending(openChanBucket, channel); err != nil {
 		return err
 	}
+	if err := putChanBroadcastHeight(openChanBucket, channel); err != nil {
+		return err
+	}
+	if err := putChanIsPrivate(openChanBucket, channel); err != nil {
+		return err
+	}
+	if err := putChanFundingState(openChanBucket, channel); err != nil {
+		return err
+	}
 
 	// Next, write out the fields of the channel update less frequently.
 	if err := putChannelIDs(nodeChanBucket, channel); err != nil {
@@ -831,6 +972,9 @@ func putOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := putChanDeliveryScripts(nodeChanBucket, channel); err != nil {
 		return err
 	}
+	if err := putChanKeyLocators(nodeChanBucket, channel); err != nil {
+		return err
+	}
 	if err := putCurrentHtlcs(nodeChanBucket, channel.Htlcs,
 		channel.ChanID); err != nil {
 		return err
@@ -868,6 +1012,9 @@ func fetchOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err = fetchChanDeliveryScripts(nodeChanBucket, channel); err != nil {
 		return nil, fmt.Errorf("unable to read delivery scripts: %v", err)
 	}
+	if err = fetchChanKeyLocators(nodeChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read key locators: %v", err)
+	}
 	channel.Htlcs, err = fetchCurrentHtlcs(nodeChanBucket, chanID)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read current htlc's: %v", err)
@@ -889,6 +1036,9 @@ func fetchOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err = fetchChanOurDustLimit(openChanBucket, channel); err != nil {
 		return nil, fmt.Errorf("unable to read their dust limit: %v", err)
 	}
+	if err = fetchChanConstraints(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read constraints: %v", err)
+	}
 	if err = fetchChanNumUpdates(openChanBucket, channel); err != nil {
 		return nil, fmt.Errorf("unable to read num updates: %v", err)
 	}
@@ -898,6 +1048,19 @@ func fetchOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err = fetchChanIsPending(openChanBucket, channel); err != nil {
 		return nil, err
 	}
+	if err = fetchChanBroadcastHeight(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read broadcast height: %v", err)
+	}
+	if err = fetchChanIsPrivate(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read private flag: %v", err)
+	}
+	if err = fetchChanFundingState(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read funding state: %v", err)
+	}
+	if err = fetchChanCommitBroadcast(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read commitment broadcast "+
+			"flag: %v", err)
+	}
 
 	return channel, nil
 }
@@ -925,9 +1088,24 @@ func deleteOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := deleteChanOurDustLimit(openChanBucket, channelID); err != nil {
 		return err
 	}
+	if err := deleteChanConstraints(openChanBucket, channelID); err != nil {
+		return err
+	}
 	if err := deleteChanIsPending(openChanBucket, channelID); err != nil {
 		return err
 	}
+	if err := deleteChanBroadcastHeight(openChanBucket, channelID); err != nil {
+		return err
+	}
+	if err := deleteChanIsPrivate(openChanBucket, channelID); err != nil {
+		return err
+	}
+	if err := deleteChanFundingState(openChanBucket, channelID); err != nil {
+		return err
+	}
+	if err := deleteChanCommitBroadcast(openChanBucket, channelID); err != nil {
+		return err
+	}
 
 	// Finally, delete all the fields directly within the node's channel
 	// bucket.
@@ -949,6 +1127,9 @@ func deleteOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := deleteChanDeliveryScripts(nodeChanBucket, channelID); err != nil {
 		return err
 	}
+	if err := deleteChanKeyLocators(nodeChanBucket, channelID); err != nil {
+		return err
+	}
 	if err := deleteCurrentHtlcs(nodeChanBucket, o); err != nil {
 		return err
 	}
@@ -1149,6 +1330,80 @@ func deleteChanOurDustLimit(openChanBucket *bolt.Bucket, chanID []byte) error {
 	return openChanBucket.Delete(ourDustKey)
 }
 
+func putChanConstraints(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+
+	copy(keyPrefix, ourConstraintsPrefix)
+	ourConstraints := serializeChanConstraints(&channel.OurConstraints)
+	if err := openChanBucket.Put(keyPrefix, ourConstraints); err != nil {
+		return err
+	}
+
+	copy(keyPrefix, theirConstraintsPrefix)
+	theirConstraints := serializeChanConstraints(&channel.TheirConstraints)
+	return openChanBucket.Put(keyPrefix, theirConstraints)
+}
+
+func fetchChanConstraints(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+
+	// Channels created before constraints were negotiated won't have
+	// either key present, in which case they're left unconstrained.
+	copy(keyPrefix, ourConstraintsPrefix)
+	if constraintBytes := openChanBucket.Get(keyPrefix); constraintBytes != nil {
+		channel.OurConstraints = deserializeChanConstraints(constraintBytes)
+	}
+
+	copy(keyPrefix, theirConstraintsPrefix)
+	if constraintBytes := openChanBucket.Get(keyPrefix); constraintBytes != nil {
+		channel.TheirConstraints = deserializeChanConstraints(constraintBytes)
+	}
+
+	return nil
+}
+
+func deleteChanConstraints(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix[3:], chanID)
+
+	copy(keyPrefix, ourConstraintsPrefix)
+	if err := openChanBucket.Delete(keyPrefix); err != nil {
+		return err
+	}
+
+	copy(keyPrefix, theirConstraintsPrefix)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func serializeChanConstraints(c *ChannelConstraints) []byte {
+	var scratch [18]byte
+	byteOrder.PutUint64(scratch[:8], uint64(c.MaxPendingAmount))
+	byteOrder.PutUint64(scratch[8:16], uint64(c.ChanReserve))
+	byteOrder.PutUint16(scratch[16:], c.MaxAcceptedHtlcs)
+
+	return scratch[:]
+}
+
+func deserializeChanConstraints(b []byte) ChannelConstraints {
+	return ChannelConstraints{
+		MaxPendingAmount: btcutil.Amount(byteOrder.Uint64(b[:8])),
+		ChanReserve:      btcutil.Amount(byteOrder.Uint64(b[8:16])),
+		MaxAcceptedHtlcs: byteOrder.Uint16(b[16:18]),
+	}
+}
+
 func putChanNumUpdates(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
 	scratch := make([]byte, 8)
 	byteOrder.PutUint64(scratch, channel.NumUpdates)
@@ -1292,6 +1547,188 @@ func fetchChanIsPending(openChanBucket *bolt.Bucket, channel *OpenChannel) error
 	return nil
 }
 
+func putChanBroadcastHeight(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	scratch := make([]byte, 4)
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], broadcastHeightPrefix)
+
+	byteOrder.PutUint32(scratch, channel.FundingBroadcastHeight)
+	return openChanBucket.Put(keyPrefix, scratch)
+}
+
+func deleteChanBroadcastHeight(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix[3:], chanID)
+	copy(keyPrefix[:3], broadcastHeightPrefix)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func fetchChanBroadcastHeight(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], broadcastHeightPrefix)
+
+	// Channels created before the broadcast height was recorded won't
+	// have the key present, in which case the height is left as zero.
+	heightBytes := openChanBucket.Get(keyPrefix)
+	if heightBytes == nil {
+		return nil
+	}
+	channel.FundingBroadcastHeight = byteOrder.Uint32(heightBytes)
+
+	return nil
+}
+
+func putChanIsPrivate(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	scratch := make([]byte, 2)
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], isPrivatePrefix)
+
+	if channel.IsPrivate {
+		byteOrder.PutUint16(scratch, uint16(1))
+		return openChanBucket.Put(keyPrefix, scratch)
+	}
+
+	byteOrder.PutUint16(scratch, uint16(0))
+	return openChanBucket.Put(keyPrefix, scratch)
+}
+
+func deleteChanIsPrivate(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix[3:], chanID)
+	copy(keyPrefix[:3], isPrivatePrefix)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func fetchChanIsPrivate(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], isPrivatePrefix)
+
+	// Channels created before private channels were introduced won't have
+	// the key present, in which case they're public.
+	privateBytes := openChanBucket.Get(keyPrefix)
+	if privateBytes == nil {
+		return nil
+	}
+	channel.IsPrivate = byteOrder.Uint16(privateBytes) == 1
+
+	return nil
+}
+
+func deleteChanCommitBroadcast(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix[3:], chanID)
+	copy(keyPrefix[:3], commitBroadcastPrefix)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func fetchChanCommitBroadcast(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], commitBroadcastPrefix)
+
+	// The key is only written once our commitment transaction has been
+	// broadcast, so its absence means the channel isn't being closed.
+	channel.CommitmentBroadcast = openChanBucket.Get(keyPrefix) != nil
+
+	return nil
+}
+
+func putChanFundingState(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], fundingStatePrefix)
+
+	return openChanBucket.Put(keyPrefix, serializeFundingState(
+		channel.FundingState, channel.ShortChanID))
+}
+
+func deleteChanFundingState(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix[3:], chanID)
+	copy(keyPrefix[:3], fundingStatePrefix)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func fetchChanFundingState(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix[3:], b.Bytes())
+	copy(keyPrefix[:3], fundingStatePrefix)
+
+	// Channels created before the funding state was recorded won't have
+	// the key present. Those which are still pending will resume their
+	// funding flow as usual once confirmed, while the funding flow of
+	// those already open is considered complete.
+	stateBytes := openChanBucket.Get(keyPrefix)
+	if stateBytes == nil {
+		if channel.IsPending {
+			channel.FundingState = FundingBroadcast
+		} else {
+			channel.FundingState = FundingAnnounced
+		}
+		return nil
+	}
+	channel.FundingState, channel.ShortChanID = deserializeFundingState(
+		stateBytes)
+
+	return nil
+}
+
+func serializeFundingState(state FundingState,
+	shortChanID lnwire.ShortChannelID) []byte {
+
+	b := make([]byte, 9)
+	b[0] = uint8(state)
+	byteOrder.PutUint64(b[1:], shortChanID.ToUint64())
+	return b
+}
+
+func deserializeFundingState(b []byte) (FundingState, lnwire.ShortChannelID) {
+	state := FundingState(b[0])
+	shortChanID := lnwire.NewShortChanIDFromInt(byteOrder.Uint64(b[1:]))
+	return state, shortChanID
+}
+
 func putChannelIDs(nodeChanBucket *bolt.Bucket, channel *OpenChannel) error {
 	// TODO(roasbeef): just pass in chanID everywhere for puts
 	var b bytes.Buffer
@@ -1738,6 +2175,66 @@ func fetchChanDeliveryScripts(nodeChanBucket *bolt.Bucket, channel *OpenChannel)
 	return err
 }
 
+func putChanKeyLocators(nodeChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var bc bytes.Buffer
+	if err := writeOutpoint(&bc, channel.ChanID); err != nil {
+		return err
+	}
+	locatorsKey := make([]byte, len(keyLocatorsKey)+bc.Len())
+	copy(locatorsKey[:3], keyLocatorsKey)
+	copy(locatorsKey[3:], bc.Bytes())
+
+	var b [24]byte
+	locators := []keychain.KeyLocator{
+		channel.OurMultiSigKeyLoc,
+		channel.OurCommitKeyLoc,
+		channel.RevocationRootKeyLoc,
+	}
+	for i, locator := range locators {
+		byteOrder.PutUint32(b[i*8:], uint32(locator.Family))
+		byteOrder.PutUint32(b[i*8+4:], locator.Index)
+	}
+
+	return nodeChanBucket.Put(locatorsKey, b[:])
+}
+
+func deleteChanKeyLocators(nodeChanBucket *bolt.Bucket, chanID []byte) error {
+	locatorsKey := make([]byte, len(keyLocatorsKey)+len(chanID))
+	copy(locatorsKey[:3], keyLocatorsKey)
+	copy(locatorsKey[3:], chanID)
+	return nodeChanBucket.Delete(locatorsKey)
+}
+
+func fetchChanKeyLocators(nodeChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+	locatorsKey := make([]byte, len(keyLocatorsKey)+b.Len())
+	copy(locatorsKey[:3], keyLocatorsKey)
+	copy(locatorsKey[3:], b.Bytes())
+
+	// Channels created before the key locators were recorded won't have
+	// the key present, in which case the locators are left empty.
+	locatorBytes := nodeChanBucket.Get(locatorsKey)
+	if locatorBytes == nil {
+		return nil
+	}
+
+	locators := []*keychain.KeyLocator{
+		&channel.OurMultiSigKeyLoc,
+		&channel.OurCommitKeyLoc,
+		&channel.RevocationRootKeyLoc,
+	}
+	for i, locator := range locators {
+		locator.Family = keychain.KeyFamily(
+			byteOrder.Uint32(locatorBytes[i*8:]))
+		locator.Index = byteOrder.Uint32(locatorBytes[i*8+4:])
+	}
+
+	return nil
+}
+
 // htlcDiskSize represents the number of btyes a serialized HTLC takes up on
 // disk. The size of an HTLC on disk is 49 bytes total: incoming (1) + amt (8)
 // + rhash (32) + timeouts (8) + output index (2)
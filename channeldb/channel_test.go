@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg"
@@ -139,14 +141,24 @@ func createTestChannelState(cdb *DB) (*OpenChannel, error) {
 	copy(obsfucator[:], key[:])
 
 	return &OpenChannel{
-		IsInitiator:                true,
-		IsPending:                  true,
-		ChanType:                   SingleFunder,
-		IdentityPub:                pubKey,
-		ChanID:                     id,
-		MinFeePerKb:                btcutil.Amount(5000),
-		TheirDustLimit:             btcutil.Amount(200),
-		OurDustLimit:               btcutil.Amount(200),
+		IsInitiator:    true,
+		IsPending:      true,
+		ChanType:       SingleFunder,
+		IdentityPub:    pubKey,
+		ChanID:         id,
+		MinFeePerKb:    btcutil.Amount(5000),
+		TheirDustLimit: btcutil.Amount(200),
+		OurDustLimit:   btcutil.Amount(200),
+		OurConstraints: ChannelConstraints{
+			MaxPendingAmount: btcutil.Amount(9000),
+			ChanReserve:      btcutil.Amount(100),
+			MaxAcceptedHtlcs: 30,
+		},
+		TheirConstraints: ChannelConstraints{
+			MaxPendingAmount: btcutil.Amount(8000),
+			ChanReserve:      btcutil.Amount(200),
+			MaxAcceptedHtlcs: 20,
+		},
 		OurCommitKey:               privKey.PubKey(),
 		TheirCommitKey:             pubKey,
 		Capacity:                   btcutil.Amount(10000),
@@ -160,8 +172,13 @@ func createTestChannelState(cdb *DB) (*OpenChannel, error) {
 		FundingOutpoint:            testOutpoint,
 		OurMultiSigKey:             privKey.PubKey(),
 		TheirMultiSigKey:           privKey.PubKey(),
+		OurMultiSigKeyLoc:          keychain.KeyLocator{Family: keychain.KeyFamilyMultiSig, Index: 1},
+		OurCommitKeyLoc:            keychain.KeyLocator{Family: keychain.KeyFamilyCommitKey, Index: 2},
+		RevocationRootKeyLoc:       keychain.KeyLocator{Family: keychain.KeyFamilyRevocationRoot, Index: 3},
 		FundingWitnessScript:       script,
 		NumConfsRequired:           4,
+		FundingBroadcastHeight:     1337,
+		IsPrivate:                  true,
 		TheirCurrentRevocation:     privKey.PubKey(),
 		TheirCurrentRevocationHash: key,
 		OurDeliveryScript:          script,
@@ -226,6 +243,16 @@ func TestOpenChannelPutGetDelete(t *testing.T) {
 	if state.OurDustLimit != newState.OurDustLimit {
 		t.Fatal("our dust limit doesn't match")
 	}
+	if state.OurConstraints != newState.OurConstraints {
+		t.Fatalf("our constraints don't match: expected %v, got %v",
+			spew.Sdump(state.OurConstraints),
+			spew.Sdump(newState.OurConstraints))
+	}
+	if state.TheirConstraints != newState.TheirConstraints {
+		t.Fatalf("their constraints don't match: expected %v, got %v",
+			spew.Sdump(state.TheirConstraints),
+			spew.Sdump(newState.TheirConstraints))
+	}
 	if state.IsInitiator != newState.IsInitiator {
 		t.Fatal("initiator status doesn't match")
 	}
@@ -284,6 +311,20 @@ func TestOpenChannelPutGetDelete(t *testing.T) {
 		t.Fatal("redeem script doesn't match")
 	}
 
+	// The locators of our keys should be identical.
+	if state.OurMultiSigKeyLoc != newState.OurMultiSigKeyLoc {
+		t.Fatalf("multisig key locators don't match: %v vs %v",
+			state.OurMultiSigKeyLoc, newState.OurMultiSigKeyLoc)
+	}
+	if state.OurCommitKeyLoc != newState.OurCommitKeyLoc {
+		t.Fatalf("commit key locators don't match: %v vs %v",
+			state.OurCommitKeyLoc, newState.OurCommitKeyLoc)
+	}
+	if state.RevocationRootKeyLoc != newState.RevocationRootKeyLoc {
+		t.Fatalf("revocation root key locators don't match: %v vs %v",
+			state.RevocationRootKeyLoc, newState.RevocationRootKeyLoc)
+	}
+
 	// The local and remote delivery scripts should be identical.
 	if !bytes.Equal(state.OurDeliveryScript, newState.OurDeliveryScript) {
 		t.Fatal("our delivery address doesn't match")
@@ -315,6 +356,15 @@ func TestOpenChannelPutGetDelete(t *testing.T) {
 		t.Fatalf("num confs required doesn't match: %v, vs. %v",
 			state.NumConfsRequired, newState.NumConfsRequired)
 	}
+	if state.FundingBroadcastHeight != newState.FundingBroadcastHeight {
+		t.Fatalf("broadcast height doesn't match: %v, vs. %v",
+			state.FundingBroadcastHeight,
+			newState.FundingBroadcastHeight)
+	}
+	if state.IsPrivate != newState.IsPrivate {
+		t.Fatalf("private flag doesn't match: %v, vs. %v",
+			state.IsPrivate, newState.IsPrivate)
+	}
 
 	if state.CreationTime.Unix() != newState.CreationTime.Unix() {
 		t.Fatal("creation time doesn't match")
@@ -652,7 +702,25 @@ func TestFetchPendingChannels(t *testing.T) {
 			"got %v", 1, len(pendingChannels))
 	}
 
-	if err := cdb.MarkChannelAsOpen(pendingChannels[0].ChanID); err != nil {
+	if pendingChannels[0].FundingState != FundingBroadcast {
+		t.Fatalf("expected funding state %v, got %v", FundingBroadcast,
+			pendingChannels[0].FundingState)
+	}
+
+	// Updating the funding state of a channel that has yet to be marked
+	// open should fail.
+	err = cdb.UpdateFundingState(pendingChannels[0].ChanID, FundingLockedSent)
+	if err != ErrNoFundingState {
+		t.Fatalf("expected ErrNoFundingState, got %v", err)
+	}
+
+	shortChanID := lnwire.ShortChannelID{
+		BlockHeight: 1337,
+		TxIndex:     10,
+		TxPosition:  1,
+	}
+	err = cdb.MarkChannelAsOpen(pendingChannels[0].ChanID, shortChanID)
+	if err != nil {
 		t.Fatalf("unable to mark channel as open: %v", err)
 	}
 
@@ -665,4 +733,137 @@ func TestFetchPendingChannels(t *testing.T) {
 		t.Fatalf("incorrect number of pending channels: expecting %v,"+
 			"got %v", 0, len(pendingChannels))
 	}
+
+	// The channel should now be in the confirmed state, with its short
+	// channel ID recorded.
+	openChannels, err := cdb.FetchAllChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if openChannels[0].FundingState != FundingConfirmed {
+		t.Fatalf("expected funding state %v, got %v", FundingConfirmed,
+			openChannels[0].FundingState)
+	}
+	if openChannels[0].ShortChanID != shortChanID {
+		t.Fatalf("short chan id mismatch: expected %v, got %v",
+			shortChanID, openChannels[0].ShortChanID)
+	}
+
+	// Advancing the funding state should preserve the short channel ID.
+	err = cdb.UpdateFundingState(openChannels[0].ChanID, FundingLockedSent)
+	if err != nil {
+		t.Fatalf("unable to update funding state: %v", err)
+	}
+	openChannels, err = cdb.FetchAllChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if openChannels[0].FundingState != FundingLockedSent {
+		t.Fatalf("expected funding state %v, got %v", FundingLockedSent,
+			openChannels[0].FundingState)
+	}
+	if openChannels[0].ShortChanID != shortChanID {
+		t.Fatalf("short chan id mismatch: expected %v, got %v",
+			shortChanID, openChannels[0].ShortChanID)
+	}
+
+	// Finally, once our commitment transaction has been broadcast, the
+	// channel should be marked as such.
+	if openChannels[0].CommitmentBroadcast {
+		t.Fatalf("channel marked as broadcast before force close")
+	}
+	if err := cdb.MarkCommitmentBroadcast(openChannels[0].ChanID); err != nil {
+		t.Fatalf("unable to mark commitment broadcast: %v", err)
+	}
+	openChannels, err = cdb.FetchAllChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if !openChannels[0].CommitmentBroadcast {
+		t.Fatalf("channel not marked as broadcast after force close")
+	}
+
+	// If the funding transaction is re-org'd out of the chain, then the
+	// channel should be pending once again, without a short channel ID.
+	if err := cdb.MarkChannelAsPending(openChannels[0].ChanID); err != nil {
+		t.Fatalf("unable to mark channel as pending: %v", err)
+	}
+	pendingChannels, err = cdb.FetchPendingChannels()
+	if err != nil {
+		t.Fatalf("unable to list pending channels: %v", err)
+	}
+	if len(pendingChannels) != 1 {
+		t.Fatalf("incorrect number of pending channels: expecting %v,"+
+			"got %v", 1, len(pendingChannels))
+	}
+	if pendingChannels[0].FundingState != FundingBroadcast {
+		t.Fatalf("expected funding state %v, got %v", FundingBroadcast,
+			pendingChannels[0].FundingState)
+	}
+	if pendingChannels[0].ShortChanID != (lnwire.ShortChannelID{}) {
+		t.Fatalf("short chan id not cleared: %v",
+			pendingChannels[0].ShortChanID)
+	}
+}
+
+func TestSyncPendingChannels(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	// Create two channels, each with a distinct funding outpoint.
+	chans := make([]*OpenChannel, 2)
+	addrs := make([]*net.TCPAddr, 2)
+	for i := range chans {
+		state, err := createTestChannelState(cdb)
+		if err != nil {
+			t.Fatalf("unable to create channel state: %v", err)
+		}
+		state.ChanID = &wire.OutPoint{Hash: id.Hash, Index: uint32(i)}
+		state.FundingOutpoint = state.ChanID
+		chans[i] = state
+
+		addrs[i] = &net.TCPAddr{
+			IP:   net.ParseIP("127.0.0.1"),
+			Port: 18555 + i,
+		}
+	}
+
+	// Each channel requires the address of its remote node, so nothing
+	// should be written if one is missing.
+	if err := cdb.SyncPendingChannels(chans, addrs[:1]); err == nil {
+		t.Fatalf("channels synced without addresses")
+	}
+	pendingChannels, err := cdb.FetchPendingChannels()
+	if err != nil {
+		t.Fatalf("unable to list pending channels: %v", err)
+	}
+	if len(pendingChannels) != 0 {
+		t.Fatalf("incorrect number of pending channels: expecting %v, "+
+			"got %v", 0, len(pendingChannels))
+	}
+
+	if err := cdb.SyncPendingChannels(chans, addrs); err != nil {
+		t.Fatalf("unable to sync channels: %v", err)
+	}
+	pendingChannels, err = cdb.FetchPendingChannels()
+	if err != nil {
+		t.Fatalf("unable to list pending channels: %v", err)
+	}
+	if len(pendingChannels) != len(chans) {
+		t.Fatalf("incorrect number of pending channels: expecting %v, "+
+			"got %v", len(chans), len(pendingChannels))
+	}
+
+	synced := make(map[wire.OutPoint]struct{})
+	for _, channel := range pendingChannels {
+		synced[*channel.ChanID] = struct{}{}
+	}
+	for _, channel := range chans {
+		if _, ok := synced[*channel.ChanID]; !ok {
+			t.Fatalf("ChannelPoint(%v) not synced", channel.ChanID)
+		}
+	}
 }
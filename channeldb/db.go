@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
 )
@@ -133,6 +135,18 @@ func (d *DB) Wipe() error {
 		if err != nil && err != bolt.ErrBucketNotFound {
 			return err
 		}
+		err = tx.DeleteBucket(sharedHashBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		err = tx.DeleteBucket(sharedHashExpiryBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		err = tx.DeleteBucket(onionKeyBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
 
 		return nil
 	})
@@ -360,9 +374,42 @@ func fetchChannels(d *DB, pendingOnly bool) ([]*OpenChannel, error) {
 	return channels, err
 }
 
+// SyncPendingChannels writes the contents of each of the passed channels to
+// the database within a single transaction, as SyncPending does for a single
+// channel. Either all of the channels are written, or none of them are. The
+// address of each channel's remote node is found at the same index within
+// addrs.
+func (d *DB) SyncPendingChannels(chans []*OpenChannel,
+	addrs []*net.TCPAddr) error {
+
+	if len(chans) != len(addrs) {
+		return fmt.Errorf("%v channels given, but %v addresses",
+			len(chans), len(addrs))
+	}
+
+	for _, c := range chans {
+		c.Lock()
+		defer c.Unlock()
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		for i, c := range chans {
+			if err := c.syncPending(tx, addrs[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // MarkChannelAsOpen records the finalization of the funding process and marks
-// a channel as available for use.
-func (d *DB) MarkChannelAsOpen(outpoint *wire.OutPoint) error {
+// a channel as available for use. The short channel ID of the now confirmed
+// channel is recorded along with it, and the channel's funding state advanced
+// to FundingConfirmed.
+func (d *DB) MarkChannelAsOpen(outpoint *wire.OutPoint,
+	shortChanID lnwire.ShortChannelID) error {
+
 	return d.Update(func(tx *bolt.Tx) error {
 		openChanBucket := tx.Bucket(openChannelBucket)
 		if openChanBucket == nil {
@@ -383,7 +430,102 @@ func (d *DB) MarkChannelAsOpen(outpoint *wire.OutPoint) error {
 		// longer pending.
 		scratch := make([]byte, 2)
 		byteOrder.PutUint16(scratch, uint16(0))
-		return openChanBucket.Put(keyPrefix, scratch)
+		if err := openChanBucket.Put(keyPrefix, scratch); err != nil {
+			return err
+		}
+
+		// Finally, we'll record the channel's new funding state along
+		// with its short channel ID.
+		copy(keyPrefix[:3], fundingStatePrefix)
+		return openChanBucket.Put(keyPrefix, serializeFundingState(
+			FundingConfirmed, shortChanID))
+	})
+}
+
+// MarkChannelAsPending reverts a channel previously marked open back to the
+// pending state, after its funding transaction was re-org'd out of the main
+// chain. The channel's short channel ID is discarded, as the funding
+// transaction's location within the chain is no longer known.
+func (d *DB) MarkChannelAsPending(outpoint *wire.OutPoint) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		openChanBucket := tx.Bucket(openChannelBucket)
+		if openChanBucket == nil {
+			return ErrNoActiveChannels
+		}
+
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, outpoint); err != nil {
+			return err
+		}
+		keyPrefix := make([]byte, 3+b.Len())
+		copy(keyPrefix[3:], b.Bytes())
+		copy(keyPrefix[:3], isPendingPrefix)
+
+		scratch := make([]byte, 2)
+		byteOrder.PutUint16(scratch, uint16(1))
+		if err := openChanBucket.Put(keyPrefix, scratch); err != nil {
+			return err
+		}
+
+		copy(keyPrefix[:3], fundingStatePrefix)
+		return openChanBucket.Put(keyPrefix, serializeFundingState(
+			FundingBroadcast, lnwire.ShortChannelID{}))
+	})
+}
+
+// UpdateFundingState records that the channel identified by the passed
+// outpoint has advanced to the given stage of the funding flow. The channel
+// must have already been marked open.
+func (d *DB) UpdateFundingState(outpoint *wire.OutPoint,
+	state FundingState) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		openChanBucket := tx.Bucket(openChannelBucket)
+		if openChanBucket == nil {
+			return ErrNoActiveChannels
+		}
+
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, outpoint); err != nil {
+			return err
+		}
+		keyPrefix := make([]byte, 3+b.Len())
+		copy(keyPrefix[3:], b.Bytes())
+		copy(keyPrefix[:3], fundingStatePrefix)
+
+		// The short channel ID recorded once the channel was marked
+		// open is carried over, with only the state itself updated.
+		stateBytes := openChanBucket.Get(keyPrefix)
+		if stateBytes == nil {
+			return ErrNoFundingState
+		}
+		_, shortChanID := deserializeFundingState(stateBytes)
+
+		return openChanBucket.Put(keyPrefix, serializeFundingState(
+			state, shortChanID))
+	})
+}
+
+// MarkCommitmentBroadcast records that our commitment transaction for the
+// channel identified by the passed outpoint has been broadcast, in order to
+// force close the channel. The record is removed along with the rest of the
+// channel's state once the channel has been closed.
+func (d *DB) MarkCommitmentBroadcast(outpoint *wire.OutPoint) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		openChanBucket := tx.Bucket(openChannelBucket)
+		if openChanBucket == nil {
+			return ErrNoActiveChannels
+		}
+
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, outpoint); err != nil {
+			return err
+		}
+		keyPrefix := make([]byte, 3+b.Len())
+		copy(keyPrefix[3:], b.Bytes())
+		copy(keyPrefix[:3], commitBroadcastPrefix)
+
+		return openChanBucket.Put(keyPrefix, []byte{1})
 	})
 }
 
@@ -81,4 +81,20 @@ var (
 	// ErrUnknownAddressType is returned when a node's addressType is not
 	// an expected value.
 	ErrUnknownAddressType = fmt.Errorf("address type cannot be resolved")
+
+	// ErrSharedHashNotFound is returned when a targeted shared secret hash
+	// can't be found within the decaying shared secret log.
+	ErrSharedHashNotFound = fmt.Errorf("shared secret hash not found")
+
+	// ErrNoOnionKeyState is returned when the state of the node's onion
+	// key rotation hasn't yet been written to the database.
+	ErrNoOnionKeyState = fmt.Errorf("no onion key state found")
+
+	// ErrNoOnionKeySig is returned when attempting to write a node which
+	// has an onion key, but no signature authenticating it.
+	ErrNoOnionKeySig = fmt.Errorf("onion key has no signature")
+
+	// ErrNoFundingState is returned when attempting to update the funding
+	// state of a channel which has yet to be marked open.
+	ErrNoFundingState = fmt.Errorf("no funding state found for channel")
 )
@@ -877,6 +877,17 @@ type LightningNode struct {
 	// Features is the list of protocol features supported by this node.
 	Features *lnwire.FeatureVector
 
+	// OnionKey is the public key that should be used when constructing
+	// onion packets destined to this node. The key is rotated by the node
+	// over time, with the latest key being advertised within its node
+	// announcement. If the node hasn't advertised an onion key, then this
+	// will be nil and the identity public key should be used instead.
+	OnionKey *btcec.PublicKey
+
+	// OnionKeySig is a signature under the advertised public key which
+	// authenticates the OnionKey. It's only set if the OnionKey is.
+	OnionKeySig *btcec.Signature
+
 	db *DB
 
 	// TODO(roasbeef): discovery will need storage to keep it's last IP
@@ -1415,6 +1426,23 @@ func putLightningNode(nodeBucket *bolt.Bucket, aliasBucket *bolt.Bucket, node *L
 		return err
 	}
 
+	// The onion key is written as an optional trailing field, so it's only
+	// included if the node has advertised one.
+	if node.OnionKey != nil {
+		_, err := b.Write(node.OnionKey.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		if node.OnionKeySig == nil {
+			return ErrNoOnionKeySig
+		}
+		err = wire.WriteVarBytes(&b, 0, node.OnionKeySig.Serialize())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nodeBucket.Put(nodePub, b.Bytes())
 }
 
@@ -1526,6 +1554,31 @@ func deserializeLightningNode(r io.Reader) (*LightningNode, error) {
 		return nil, err
 	}
 
+	// Finally, we'll attempt to read the optional onion key. If we've
+	// reached the end of the serialized node, then the node hasn't
+	// advertised an onion key.
+	var onionKey [33]byte
+	_, err = io.ReadFull(r, onionKey[:])
+	switch {
+	case err == io.EOF:
+		return node, nil
+	case err != nil:
+		return nil, err
+	}
+	node.OnionKey, err = btcec.ParsePubKey(onionKey[:], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	sigBytes, err = wire.ReadVarBytes(r, 0, 80, "sig")
+	if err != nil {
+		return nil, err
+	}
+	node.OnionKeySig, err = btcec.ParseSignature(sigBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
 	return node, nil
 }
 
@@ -75,14 +75,16 @@ func TestNodeInsertionAndDeletion(t *testing.T) {
 	// graph, so we'll create a test vertex to start with.
 	_, testPub := btcec.PrivKeyFromBytes(btcec.S256(), key[:])
 	node := &LightningNode{
-		AuthSig:    testSig,
-		LastUpdate: time.Unix(1232342, 0),
-		PubKey:     testPub,
-		Color:      color.RGBA{1, 2, 3, 0},
-		Alias:      "kek",
-		Features:   testFeatures,
-		Addresses:  testAddrs,
-		db:         db,
+		AuthSig:     testSig,
+		LastUpdate:  time.Unix(1232342, 0),
+		PubKey:      testPub,
+		Color:       color.RGBA{1, 2, 3, 0},
+		Alias:       "kek",
+		Features:    testFeatures,
+		Addresses:   testAddrs,
+		OnionKey:    testPub,
+		OnionKeySig: testSig,
+		db:          db,
 	}
 
 	// First, insert the node into the graph DB. This should succeed
@@ -411,8 +413,8 @@ func TestEdgeInfoUpdates(t *testing.T) {
 		MinHTLC:                   2342135,
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 3452352,
-		Node: secondNode,
-		db:   db,
+		Node:                      secondNode,
+		db:                        db,
 	}
 	edge2 := &ChannelEdgePolicy{
 		Signature:                 testSig,
@@ -423,8 +425,8 @@ func TestEdgeInfoUpdates(t *testing.T) {
 		MinHTLC:                   2342135,
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 90392423,
-		Node: firstNode,
-		db:   db,
+		Node:                      firstNode,
+		db:                        db,
 	}
 
 	// Next, insert both nodes into the database, they should both be
@@ -495,7 +497,7 @@ func randEdgePolicy(chanID uint64, op wire.OutPoint, db *DB) *ChannelEdgePolicy
 		MinHTLC:                   btcutil.Amount(prand.Int63()),
 		FeeBaseMSat:               btcutil.Amount(prand.Int63()),
 		FeeProportionalMillionths: btcutil.Amount(prand.Int63()),
-		db: db,
+		db:                        db,
 	}
 }
 
@@ -865,6 +867,17 @@ func compareNodes(a, b *LightningNode) error {
 		return fmt.Errorf("Alias doesn't match: expected %#v, \n "+
 			"got %#v", a.Alias, b.Alias)
 	}
+	if !reflect.DeepEqual(a.OnionKey, b.OnionKey) {
+		return fmt.Errorf("OnionKey doesn't match: expected %#v, \n "+
+			"got %#v", a.OnionKey, b.OnionKey)
+	}
+	if (a.OnionKeySig == nil) != (b.OnionKeySig == nil) ||
+		(a.OnionKeySig != nil && !bytes.Equal(a.OnionKeySig.Serialize(),
+			b.OnionKeySig.Serialize())) {
+
+		return fmt.Errorf("OnionKeySig doesn't match: expected %#v, \n "+
+			"got %#v", a.OnionKeySig, b.OnionKeySig)
+	}
 	if !reflect.DeepEqual(a.db, b.db) {
 		return fmt.Errorf("db doesn't match: expected %#v, \n "+
 			"got %#v", a.db, b.db)
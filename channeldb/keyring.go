@@ -0,0 +1,72 @@
+package channeldb
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+var (
+	// keyIndexBucket is the name of the bucket within the database that
+	// stores the number of keys derived within each family of the
+	// wallet's key ring. Each key is a big-endian family, and each value
+	// the next unused index within the family.
+	keyIndexBucket = []byte("key-index")
+)
+
+// NextKeyIndex reserves and returns the next unused index within the passed
+// key family.
+//
+// NOTE: This is part of the keychain.IndexStore interface.
+func (d *DB) NextKeyIndex(keyFam keychain.KeyFamily) (uint32, error) {
+	var index uint32
+	err := d.Update(func(tx *bolt.Tx) error {
+		indexes, err := tx.CreateBucketIfNotExists(keyIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		var famKey [4]byte
+		byteOrder.PutUint32(famKey[:], uint32(keyFam))
+
+		if indexBytes := indexes.Get(famKey[:]); indexBytes != nil {
+			index = byteOrder.Uint32(indexBytes)
+		}
+
+		var nextIndex [4]byte
+		byteOrder.PutUint32(nextIndex[:], index+1)
+		return indexes.Put(famKey[:], nextIndex[:])
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return index, nil
+}
+
+// FetchKeyIndex returns the number of indexes reserved within the passed key
+// family.
+//
+// NOTE: This is part of the keychain.IndexStore interface.
+func (d *DB) FetchKeyIndex(keyFam keychain.KeyFamily) (uint32, error) {
+	var index uint32
+	err := d.View(func(tx *bolt.Tx) error {
+		indexes := tx.Bucket(keyIndexBucket)
+		if indexes == nil {
+			return nil
+		}
+
+		var famKey [4]byte
+		byteOrder.PutUint32(famKey[:], uint32(keyFam))
+
+		if indexBytes := indexes.Get(famKey[:]); indexBytes != nil {
+			index = byteOrder.Uint32(indexBytes)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return index, nil
+}
@@ -0,0 +1,55 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// TestKeyIndexes tests that indexes are reserved sequentially within each key
+// family, independently of all other families.
+func TestKeyIndexes(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Before any indexes have been reserved, each family should report
+	// zero reserved indexes.
+	index, err := cdb.FetchKeyIndex(keychain.KeyFamilyMultiSig)
+	if err != nil {
+		t.Fatalf("unable to fetch key index: %v", err)
+	}
+	if index != 0 {
+		t.Fatalf("expected index 0, got %v", index)
+	}
+
+	families := []keychain.KeyFamily{
+		keychain.KeyFamilyMultiSig,
+		keychain.KeyFamilyRevocationRoot,
+	}
+	for i := uint32(0); i < 3; i++ {
+		for _, keyFam := range families {
+			index, err := cdb.NextKeyIndex(keyFam)
+			if err != nil {
+				t.Fatalf("unable to reserve key index: %v", err)
+			}
+			if index != i {
+				t.Fatalf("expected index %v for family %v, got %v",
+					i, keyFam, index)
+			}
+		}
+	}
+
+	for _, keyFam := range families {
+		index, err := cdb.FetchKeyIndex(keyFam)
+		if err != nil {
+			t.Fatalf("unable to fetch key index: %v", err)
+		}
+		if index != 3 {
+			t.Fatalf("expected 3 indexes reserved for family %v, "+
+				"got %v", keyFam, index)
+		}
+	}
+}
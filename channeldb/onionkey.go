@@ -0,0 +1,78 @@
+package channeldb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// onionKeyBucket is the name of the bucket within the database that
+	// stores the state of the node's onion key rotation.
+	onionKeyBucket = []byte("onion-key")
+
+	// onionKeyStateKey is the key within the onionKeyBucket which stores
+	// the serialized OnionKeyState.
+	onionKeyStateKey = []byte("onion-key-state")
+)
+
+// OnionKeyState describes the onion key currently in use by the node. Onion
+// keys are derived from the wallet's key hierarchy, so only the index of the
+// current key needs to be stored in order to re-derive it, along with the
+// index of the prior key which may still be used within its grace period.
+type OnionKeyState struct {
+	// Index is the derivation index of the current onion key.
+	Index uint32
+
+	// RotatedAt is the time at which the current onion key replaced the
+	// prior key. Onion packets constructed using the prior key (Index-1)
+	// are accepted until the grace period following this time elapses.
+	RotatedAt time.Time
+}
+
+// PutOnionKeyState writes the passed onion key state to disk, overwriting any
+// prior state.
+func (d *DB) PutOnionKeyState(state *OnionKeyState) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		keys, err := tx.CreateBucketIfNotExists(onionKeyBucket)
+		if err != nil {
+			return err
+		}
+
+		var b [12]byte
+		byteOrder.PutUint32(b[:4], state.Index)
+		byteOrder.PutUint64(b[4:], uint64(state.RotatedAt.Unix()))
+
+		return keys.Put(onionKeyStateKey, b[:])
+	})
+}
+
+// FetchOnionKeyState returns the current onion key state of the node. If the
+// state hasn't yet been written, then ErrNoOnionKeyState is returned.
+func (d *DB) FetchOnionKeyState() (*OnionKeyState, error) {
+	var state *OnionKeyState
+	err := d.View(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(onionKeyBucket)
+		if keys == nil {
+			return ErrNoOnionKeyState
+		}
+
+		stateBytes := keys.Get(onionKeyStateKey)
+		if stateBytes == nil {
+			return ErrNoOnionKeyState
+		}
+
+		rotatedAt := int64(byteOrder.Uint64(stateBytes[4:12]))
+		state = &OnionKeyState{
+			Index:     byteOrder.Uint32(stateBytes[:4]),
+			RotatedAt: time.Unix(rotatedAt, 0),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
@@ -0,0 +1,43 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestOnionKeyState tests that the onion key state can be written to and
+// read back from the database, with later writes replacing prior state.
+func TestOnionKeyState(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Before any state has been written, we should get the proper error.
+	if _, err := cdb.FetchOnionKeyState(); err != ErrNoOnionKeyState {
+		t.Fatalf("expected ErrNoOnionKeyState, got %v", err)
+	}
+
+	for i := uint32(0); i < 3; i++ {
+		state := &OnionKeyState{
+			Index:     i,
+			RotatedAt: time.Unix(int64(1000000+i), 0),
+		}
+		if err := cdb.PutOnionKeyState(state); err != nil {
+			t.Fatalf("unable to write onion key state: %v", err)
+		}
+
+		dbState, err := cdb.FetchOnionKeyState()
+		if err != nil {
+			t.Fatalf("unable to fetch onion key state: %v", err)
+		}
+		if !reflect.DeepEqual(state, dbState) {
+			t.Fatalf("onion key state mismatch: expected %v, got %v",
+				spew.Sdump(state), spew.Sdump(dbState))
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// sharedHashBucket is the name of the bucket within the database that
+	// stores the hashes of all Sphinx shared secrets we've processed
+	// within incoming HTLCs. Each hash is mapped to a serialized
+	// SharedHashEntry which records the expiry of the HTLC that carried
+	// the onion packet, along with the location of the HTLC within its
+	// channel. The bucket serves as a decaying log: once the expiry height
+	// of an entry has passed, the entry can be safely removed as an HTLC
+	// carrying a replayed packet would no longer be accepted.
+	sharedHashBucket = []byte("shared-hash")
+
+	// sharedHashExpiryBucket is the name of the bucket within the
+	// database that indexes the entries of the decaying shared secret log
+	// by their expiry. Each key is the big-endian expiry height of an
+	// entry, followed by its shared secret hash, and maps to an empty
+	// value. As bolt orders keys lexicographically, the expired entries
+	// can be found without scanning the entire log.
+	sharedHashExpiryBucket = []byte("shared-hash-expiry")
+)
+
+// sharedHashExpiryKey returns the key of the passed shared secret hash within
+// the expiry index.
+func sharedHashExpiryKey(expiry uint32, hash []byte) []byte {
+	var key [4 + 32]byte
+	byteOrder.PutUint32(key[:4], expiry)
+	copy(key[4:], hash)
+
+	return key[:]
+}
+
+// SharedHashEntry is the value stored for each shared secret hash within the
+// decaying shared secret log. Along with the CLTV expiry of the HTLC which
+// carried the onion packet, the channel and log index of the HTLC are also
+// recorded. This allows callers to distinguish a genuine replay of an onion
+// packet from a circuit which is re-processed after a restart.
+type SharedHashEntry struct {
+	// Expiry is the absolute CLTV expiry of the HTLC which carried the
+	// onion packet. Once the chain has advanced beyond this height, the
+	// entry may be garbage collected.
+	Expiry uint32
+
+	// ChanID is the channel over which the HTLC carrying the onion packet
+	// was received.
+	ChanID lnwire.ChannelID
+
+	// HtlcIndex is the index of the HTLC within the remote party's update
+	// log for the channel.
+	HtlcIndex uint64
+}
+
+// LogSharedHash attempts to add the passed shared secret hash to the decaying
+// shared secret log. If the hash isn't yet known, then it's written to disk
+// along with the passed entry and a nil entry is returned. Otherwise, the
+// stored entry is left untouched and returned to the caller so it can
+// determine if the onion packet has been replayed.
+func (d *DB) LogSharedHash(hash [32]byte,
+	entry *SharedHashEntry) (*SharedHashEntry, error) {
+
+	var existingEntry *SharedHashEntry
+	err := d.Update(func(tx *bolt.Tx) error {
+		hashes, err := tx.CreateBucketIfNotExists(sharedHashBucket)
+		if err != nil {
+			return err
+		}
+		expiryIndex, err := tx.CreateBucketIfNotExists(
+			sharedHashExpiryBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// If we already have an entry for this shared secret hash,
+		// then we'll return it to the caller without modifying it.
+		if entryBytes := hashes.Get(hash[:]); entryBytes != nil {
+			existingEntry, err = deserializeSharedHashEntry(
+				bytes.NewReader(entryBytes),
+			)
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := serializeSharedHashEntry(&b, entry); err != nil {
+			return err
+		}
+
+		if err := hashes.Put(hash[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		return expiryIndex.Put(
+			sharedHashExpiryKey(entry.Expiry, hash[:]), nil,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existingEntry, nil
+}
+
+// LookupSharedHash attempts to locate the entry for the target shared secret
+// hash within the decaying log. If the hash is unknown, then
+// ErrSharedHashNotFound is returned.
+func (d *DB) LookupSharedHash(hash [32]byte) (*SharedHashEntry, error) {
+	var entry *SharedHashEntry
+	err := d.View(func(tx *bolt.Tx) error {
+		hashes := tx.Bucket(sharedHashBucket)
+		if hashes == nil {
+			return ErrSharedHashNotFound
+		}
+
+		entryBytes := hashes.Get(hash[:])
+		if entryBytes == nil {
+			return ErrSharedHashNotFound
+		}
+
+		var err error
+		entry, err = deserializeSharedHashEntry(bytes.NewReader(entryBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// PruneSharedHashes removes all entries within the decaying shared secret log
+// whose expiry is below the passed block height. The number of entries
+// removed is returned. Only the expired entries are visited, as they're
+// located via the expiry index.
+func (d *DB) PruneSharedHashes(height uint32) (uint32, error) {
+	var numPruned uint32
+	err := d.Update(func(tx *bolt.Tx) error {
+		hashes := tx.Bucket(sharedHashBucket)
+		expiryIndex := tx.Bucket(sharedHashExpiryBucket)
+		if hashes == nil || expiryIndex == nil {
+			return nil
+		}
+
+		// First, we'll collect the index keys of all expired entries,
+		// which precede those of all other entries. Bolt doesn't allow
+		// deletion while iterating over a bucket, so we'll delete them
+		// in a second pass.
+		var expiredKeys [][]byte
+		cursor := expiryIndex.Cursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			if byteOrder.Uint32(k[:4]) >= height {
+				break
+			}
+
+			indexKey := make([]byte, len(k))
+			copy(indexKey, k)
+			expiredKeys = append(expiredKeys, indexKey)
+		}
+
+		for _, indexKey := range expiredKeys {
+			if err := hashes.Delete(indexKey[4:]); err != nil {
+				return err
+			}
+			if err := expiryIndex.Delete(indexKey); err != nil {
+				return err
+			}
+		}
+
+		numPruned = uint32(len(expiredKeys))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numPruned, nil
+}
+
+func serializeSharedHashEntry(w io.Writer, e *SharedHashEntry) error {
+	var scratch [8]byte
+
+	byteOrder.PutUint32(scratch[:4], e.Expiry)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(e.ChanID[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], e.HtlcIndex)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deserializeSharedHashEntry(r io.Reader) (*SharedHashEntry, error) {
+	var scratch [8]byte
+
+	e := &SharedHashEntry{}
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	e.Expiry = byteOrder.Uint32(scratch[:4])
+
+	if _, err := io.ReadFull(r, e.ChanID[:]); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	e.HtlcIndex = byteOrder.Uint64(scratch[:])
+
+	return e, nil
+}
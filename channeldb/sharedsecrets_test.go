@@ -0,0 +1,156 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestSharedHashLogAndPrune tests that shared secret hashes can be added to
+// the decaying log, that duplicate insertions return the original entry, and
+// that entries are pruned once their expiry has passed.
+func TestSharedHashLogAndPrune(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	hash1 := sha256.Sum256([]byte("secret one"))
+	hash2 := sha256.Sum256([]byte("secret two"))
+
+	entry1 := &SharedHashEntry{
+		Expiry:    100,
+		HtlcIndex: 5,
+	}
+	copy(entry1.ChanID[:], rev[:])
+	entry2 := &SharedHashEntry{
+		Expiry:    200,
+		HtlcIndex: 6,
+	}
+
+	// Initially, neither hash should be found within the log.
+	if _, err := cdb.LookupSharedHash(hash1); err != ErrSharedHashNotFound {
+		t.Fatalf("expected ErrSharedHashNotFound, got %v", err)
+	}
+
+	// Adding both hashes for the first time should report no prior entry.
+	for _, e := range []struct {
+		hash  [32]byte
+		entry *SharedHashEntry
+	}{{hash1, entry1}, {hash2, entry2}} {
+		existing, err := cdb.LogSharedHash(e.hash, e.entry)
+		if err != nil {
+			t.Fatalf("unable to log shared hash: %v", err)
+		}
+		if existing != nil {
+			t.Fatalf("expected no existing entry, got %v",
+				spew.Sdump(existing))
+		}
+	}
+
+	// Logging the first hash again with a different entry should return
+	// the original entry, leaving it unmodified on disk.
+	replayEntry := &SharedHashEntry{
+		Expiry:    300,
+		HtlcIndex: 9,
+	}
+	existing, err := cdb.LogSharedHash(hash1, replayEntry)
+	if err != nil {
+		t.Fatalf("unable to log shared hash: %v", err)
+	}
+	if !reflect.DeepEqual(existing, entry1) {
+		t.Fatalf("existing entry mismatch: expected %v, got %v",
+			spew.Sdump(entry1), spew.Sdump(existing))
+	}
+	dbEntry, err := cdb.LookupSharedHash(hash1)
+	if err != nil {
+		t.Fatalf("unable to lookup shared hash: %v", err)
+	}
+	if !reflect.DeepEqual(dbEntry, entry1) {
+		t.Fatalf("stored entry mismatch: expected %v, got %v",
+			spew.Sdump(entry1), spew.Sdump(dbEntry))
+	}
+
+	// Pruning at a height equal to the first entry's expiry shouldn't
+	// remove anything.
+	numPruned, err := cdb.PruneSharedHashes(100)
+	if err != nil {
+		t.Fatalf("unable to prune shared hashes: %v", err)
+	}
+	if numPruned != 0 {
+		t.Fatalf("expected no entries pruned, instead %v were",
+			numPruned)
+	}
+
+	// Once the chain moves past the first entry's expiry, it should be
+	// removed while the second entry remains.
+	numPruned, err = cdb.PruneSharedHashes(101)
+	if err != nil {
+		t.Fatalf("unable to prune shared hashes: %v", err)
+	}
+	if numPruned != 1 {
+		t.Fatalf("expected 1 entry pruned, instead %v were", numPruned)
+	}
+	if _, err := cdb.LookupSharedHash(hash1); err != ErrSharedHashNotFound {
+		t.Fatalf("expected ErrSharedHashNotFound, got %v", err)
+	}
+	if _, err := cdb.LookupSharedHash(hash2); err != nil {
+		t.Fatalf("unable to lookup shared hash: %v", err)
+	}
+}
+
+// TestSharedHashPruneIndex tests that pruning removes every expired entry,
+// including entries sharing an expiry, and that pruned entries are removed
+// from the expiry index so they're never pruned again.
+func TestSharedHashPruneIndex(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	expiries := []uint32{150, 100, 100, 200}
+	hashes := make([][32]byte, len(expiries))
+	for i, expiry := range expiries {
+		hashes[i] = sha256.Sum256([]byte{byte(i)})
+		_, err := cdb.LogSharedHash(hashes[i], &SharedHashEntry{
+			Expiry:    expiry,
+			HtlcIndex: uint64(i),
+		})
+		if err != nil {
+			t.Fatalf("unable to log shared hash: %v", err)
+		}
+	}
+
+	numPruned, err := cdb.PruneSharedHashes(151)
+	if err != nil {
+		t.Fatalf("unable to prune shared hashes: %v", err)
+	}
+	if numPruned != 3 {
+		t.Fatalf("expected 3 entries pruned, instead %v were", numPruned)
+	}
+	for i, expiry := range expiries {
+		_, err := cdb.LookupSharedHash(hashes[i])
+		switch {
+		case expiry < 151 && err != ErrSharedHashNotFound:
+			t.Fatalf("expected entry %v to be pruned, got %v", i,
+				err)
+		case expiry >= 151 && err != nil:
+			t.Fatalf("unable to lookup shared hash: %v", err)
+		}
+	}
+
+	// Pruning at the same height once more should find nothing left to
+	// prune.
+	numPruned, err = cdb.PruneSharedHashes(151)
+	if err != nil {
+		t.Fatalf("unable to prune shared hashes: %v", err)
+	}
+	if numPruned != 0 {
+		t.Fatalf("expected no entries pruned, instead %v were",
+			numPruned)
+	}
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// chanRestorer recovers the funds of channels whose state has been lost, using
+// their static backups. As a static backup doesn't contain the channel's
+// state, we're unable to close the channel ourselves. Instead, we reconnect to
+// the remote peer of each restored channel and request that it force close
+// the channel. Once the remote peer's commitment transaction has confirmed, we
+// sweep the output paying to us, which isn't encumbered by any delay.
+//
+// NOTE: Pending restores aren't persisted, so a restore should be repeated if
+// the daemon is restarted before the restored channels have been closed.
+type chanRestorer struct {
+	started uint32
+	stopped uint32
+
+	server *server
+
+	// channelsChanged is called each time a channel is added to, or
+	// removed from the set of channels being restored, so the static
+	// backups of our channels may be updated.
+	channelsChanged func()
+
+	// pending maps the serialized public key of a remote peer to the
+	// backups of the channels we share with it whose funding outputs are
+	// yet to be spent.
+	pending    map[[33]byte][]chanbackup.Single
+	pendingMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChanRestorer creates a new chanRestorer which recovers channels on behalf
+// of the passed server.
+func newChanRestorer(s *server, channelsChanged func()) *chanRestorer {
+	return &chanRestorer{
+		server:          s,
+		channelsChanged: channelsChanged,
+		pending:         make(map[[33]byte][]chanbackup.Single),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Start starts the chanRestorer.
+func (c *chanRestorer) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	chbuLog.Tracef("Starting channel restorer")
+
+	return nil
+}
+
+// Stop gracefully shuts down the chanRestorer, abandoning any pending
+// restores.
+func (c *chanRestorer) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	chbuLog.Infof("Channel restorer shutting down")
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// restore begins the recovery of each of the passed channels. Channels which
+// are still present within our database are skipped, as they can be closed
+// as usual.
+func (c *chanRestorer) restore(singles []chanbackup.Single) error {
+	dbChannels, err := c.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+	knownChans := make(map[wire.OutPoint]struct{})
+	for _, dbChannel := range dbChannels {
+		knownChans[*dbChannel.ChanID] = struct{}{}
+	}
+
+	for _, single := range singles {
+		if single.ChainHash != *activeNetParams.GenesisHash {
+			return fmt.Errorf("ChannelPoint(%v) was opened within "+
+				"chain %v, not %v", single.FundingOutpoint,
+				single.ChainHash, activeNetParams.GenesisHash)
+		}
+
+		if _, ok := knownChans[single.FundingOutpoint]; ok {
+			chbuLog.Infof("ChannelPoint(%v) is still open, "+
+				"skipping restore", single.FundingOutpoint)
+			continue
+		}
+
+		if err := c.restoreChannel(single); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreChannel begins the recovery of a single channel. We'll watch for the
+// channel's funding output to be spent, then connect to the remote peer in
+// order to request that it force close the channel.
+func (c *chanRestorer) restoreChannel(single chanbackup.Single) error {
+	keyRing := c.server.lnwallet.KeyRing
+
+	// As our key ring may have been recreated from our seed, it may be
+	// unaware of the keys used within the channel. We'll reserve each of
+	// them to ensure they're never used again within another channel.
+	keyLocs := []keychain.KeyLocator{
+		single.MultiSigKeyLoc, single.CommitKeyLoc,
+		single.RevocationRootKeyLoc,
+	}
+	for _, keyLoc := range keyLocs {
+		for {
+			keyDesc, err := keyRing.DeriveNextKey(keyLoc.Family)
+			if err != nil {
+				return err
+			}
+			if keyDesc.Index >= keyLoc.Index {
+				break
+			}
+		}
+	}
+
+	// The remote peer's commitment transaction pays our balance to a
+	// p2wkh output of our commitment key, which we'll derive in order to
+	// identify, then sweep the output.
+	commitKey, err := keyRing.DeriveKey(single.CommitKeyLoc)
+	if err != nil {
+		return err
+	}
+
+	chbuLog.Infof("Restoring ChannelPoint(%v) with peer %x",
+		single.FundingOutpoint,
+		single.RemoteNodePub.SerializeCompressed())
+
+	var peerKey [33]byte
+	copy(peerKey[:], single.RemoteNodePub.SerializeCompressed())
+
+	c.pendingMtx.Lock()
+	c.pending[peerKey] = append(c.pending[peerKey], single)
+	c.pendingMtx.Unlock()
+
+	c.channelsChanged()
+
+	c.wg.Add(1)
+	go c.sweepRemoteCommit(single, commitKey.PubKey)
+
+	// If we're already connected to the peer, then we can request the
+	// force close immediately. Otherwise, the request is sent once the
+	// connection has been established.
+	if _, err := c.server.findPeer(single.RemoteNodePub); err == nil {
+		go c.peerConnected(single.RemoteNodePub)
+		return nil
+	}
+
+	for _, addr := range single.Addresses {
+		netAddr := &lnwire.NetAddress{
+			IdentityKey: single.RemoteNodePub,
+			Address:     addr,
+			ChainNet:    activeNetParams.Net,
+		}
+
+		go func() {
+			if err := c.server.ConnectToPeer(netAddr, true); err != nil {
+				chbuLog.Errorf("unable to connect to peer "+
+					"%v: %v", netAddr, err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// peerConnected requests that the passed peer force close each of the
+// channels we're restoring with it.
+func (c *chanRestorer) peerConnected(peerPub *btcec.PublicKey) {
+	var peerKey [33]byte
+	copy(peerKey[:], peerPub.SerializeCompressed())
+
+	c.pendingMtx.Lock()
+	singles := c.pending[peerKey]
+	c.pendingMtx.Unlock()
+
+	if len(singles) == 0 {
+		return
+	}
+
+	msgs := make([]lnwire.Message, 0, len(singles))
+	for _, single := range singles {
+		chbuLog.Infof("Requesting peer %x force close "+
+			"ChannelPoint(%v)", peerKey, single.FundingOutpoint)
+
+		msgs = append(msgs, &lnwire.Error{
+			ChanID: lnwire.NewChanIDFromOutPoint(
+				&single.FundingOutpoint),
+			Code: lnwire.ErrForceCloseRequested,
+			Data: []byte("channel state lost, please force close"),
+		})
+	}
+
+	if err := c.server.sendToPeer(peerPub, msgs...); err != nil {
+		chbuLog.Errorf("unable to request force close from peer "+
+			"%x: %v", peerKey, err)
+	}
+}
+
+// sweepRemoteCommit waits for the funding output of the passed channel to be
+// spent. If the spending transaction pays to our commitment key, then the
+// output is handed to the sweeper, which sweeps it back into the wallet.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chanRestorer) sweepRemoteCommit(single chanbackup.Single,
+	commitKey *btcec.PublicKey) {
+
+	defer c.wg.Done()
+
+	chanPoint := single.FundingOutpoint
+
+	spendNtfn, err := c.server.chainNotifier.RegisterSpendNtfn(
+		&chanPoint, single.FundingBroadcastHeight)
+	if err != nil {
+		chbuLog.Errorf("unable to register for spend of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+	defer spendNtfn.Cancel()
+
+	var spendDetail *chainntnfs.SpendDetail
+	select {
+	case detail, ok := <-spendNtfn.Spend:
+		if !ok {
+			return
+		}
+		spendDetail = detail
+
+	case <-c.quit:
+		return
+	}
+
+	// Now that the channel has been closed, the peer no longer needs to
+	// be asked to close it.
+	c.removePending(single)
+
+	ourScript, err := p2wkhScript(commitKey)
+	if err != nil {
+		chbuLog.Errorf("unable to create commitment script for "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+
+	closeTx := spendDetail.SpendingTx
+	closeTxid := closeTx.TxHash()
+
+	ourIndex := -1
+	for i, txOut := range closeTx.TxOut {
+		if bytes.Equal(txOut.PkScript, ourScript) {
+			ourIndex = i
+			break
+		}
+	}
+	if ourIndex == -1 {
+		chbuLog.Infof("ChannelPoint(%v) closed by %v without an "+
+			"output paying to us", chanPoint, closeTxid)
+		return
+	}
+
+	ourOutput := closeTx.TxOut[ourIndex]
+	signDesc := &lnwallet.SignDescriptor{
+		PubKey:   commitKey,
+		Output:   ourOutput,
+		HashType: txscript.SigHashAll,
+	}
+	witnessFunc := func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
+		inputIndex int) ([][]byte, error) {
+
+		desc := *signDesc
+		desc.SigHashes = hc
+		desc.InputIndex = inputIndex
+
+		return lnwallet.CommitSpendNoDelay(c.server.lnwallet.Signer,
+			&desc, tx)
+	}
+
+	resultChan, err := c.server.sweeper.sweepInput(&sweepInput{
+		outPoint: wire.OutPoint{
+			Hash:  closeTxid,
+			Index: uint32(ourIndex),
+		},
+		amt:         btcutil.Amount(ourOutput.Value),
+		witnessFunc: witnessFunc,
+		witnessSize: lnwallet.P2WKHWitnessSize,
+		confTarget:  lnwallet.SweepTxConfTarget,
+		heightHint:  uint32(spendDetail.SpendingHeight),
+	})
+	if err != nil {
+		chbuLog.Errorf("unable to sweep output of ChannelPoint(%v): "+
+			"%v", chanPoint, err)
+		return
+	}
+
+	chbuLog.Infof("Sweeping %v from ChannelPoint(%v) closed by %v",
+		btcutil.Amount(ourOutput.Value), chanPoint, closeTxid)
+
+	select {
+	case result, ok := <-resultChan:
+		if !ok {
+			return
+		}
+		if result.err != nil {
+			chbuLog.Errorf("unable to sweep output of "+
+				"ChannelPoint(%v): %v", chanPoint, result.err)
+			return
+		}
+
+		chbuLog.Infof("ChannelPoint(%v) restored, output swept by %v",
+			chanPoint, result.spendingTx.TxHash())
+
+	case <-c.quit:
+	}
+}
+
+// removePending removes the passed channel from the set of channels we're
+// waiting to be closed.
+func (c *chanRestorer) removePending(single chanbackup.Single) {
+	var peerKey [33]byte
+	copy(peerKey[:], single.RemoteNodePub.SerializeCompressed())
+
+	c.pendingMtx.Lock()
+	defer c.pendingMtx.Unlock()
+	defer c.channelsChanged()
+
+	singles := c.pending[peerKey]
+	for i, s := range singles {
+		if s.FundingOutpoint != single.FundingOutpoint {
+			continue
+		}
+
+		singles = append(singles[:i], singles[i+1:]...)
+		break
+	}
+
+	if len(singles) == 0 {
+		delete(c.pending, peerKey)
+		return
+	}
+	c.pending[peerKey] = singles
+}
+
+// pendingSingles returns the backups of all channels we're waiting to be
+// closed.
+func (c *chanRestorer) pendingSingles() []chanbackup.Single {
+	c.pendingMtx.Lock()
+	defer c.pendingMtx.Unlock()
+
+	var singles []chanbackup.Single
+	for _, peerSingles := range c.pending {
+		singles = append(singles, peerSingles...)
+	}
+
+	return singles
+}
+
+// p2wkhScript returns the p2wkh output script paying to the passed key.
+func p2wkhScript(pubKey *btcec.PublicKey) ([]byte, error) {
+	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash,
+		activeNetParams.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	return txscript.PayToAddrScript(addr)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// newChannelsChangedSignal returns a closure which, like the backup
+// manager's, signals that the backups should be updated without blocking,
+// along with the channel the signal is delivered over.
+func newChannelsChangedSignal() (chan struct{}, func()) {
+	changed := make(chan struct{}, 1)
+	return changed, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// assertChannelsChanged asserts whether the backups have been signalled to
+// be updated.
+func assertChannelsChanged(t *testing.T, changed chan struct{}, expected bool) {
+	select {
+	case <-changed:
+		if !expected {
+			t.Fatalf("unexpected backup update")
+		}
+	default:
+		if expected {
+			t.Fatalf("backup update not signalled")
+		}
+	}
+}
+
+// TestChanRestorerSkipsUnrestorable tests that channels still present within
+// our database aren't restored, and that backups of channels opened within
+// another chain are rejected.
+func TestChanRestorerSkipsUnrestorable(t *testing.T) {
+	cdb, _, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9735}
+	dbChannel := newTestChannel(t, cdb, wire.OutPoint{Index: 1}, addr)
+
+	changed, channelsChanged := newChannelsChangedSignal()
+	restorer := newChanRestorer(&server{chanDB: cdb}, channelsChanged)
+
+	known := newTestRestoreSingle(t, 1)
+	known.FundingOutpoint = *dbChannel.ChanID
+	if err := restorer.restore([]chanbackup.Single{known}); err != nil {
+		t.Fatalf("unable to restore channel: %v", err)
+	}
+	if len(restorer.pendingSingles()) != 0 {
+		t.Fatalf("known channel restored")
+	}
+	assertChannelsChanged(t, changed, false)
+
+	otherChain := newTestRestoreSingle(t, 2)
+	otherChain.ChainHash = *chaincfg.MainNetParams.GenesisHash
+	if err := restorer.restore([]chanbackup.Single{otherChain}); err == nil {
+		t.Fatalf("channel of another chain restored")
+	}
+	if len(restorer.pendingSingles()) != 0 {
+		t.Fatalf("channel of another chain restored")
+	}
+	assertChannelsChanged(t, changed, false)
+}
+
+// TestChanRestorerRemovePending tests that channels are removed from the set
+// of pending restores individually, and that the backups are signalled to
+// be updated each time.
+func TestChanRestorerRemovePending(t *testing.T) {
+	changed, channelsChanged := newChannelsChangedSignal()
+	restorer := newChanRestorer(&server{}, channelsChanged)
+
+	first := newTestRestoreSingle(t, 1)
+	second := newTestRestoreSingle(t, 2)
+	second.RemoteNodePub = first.RemoteNodePub
+
+	var peerKey [33]byte
+	copy(peerKey[:], first.RemoteNodePub.SerializeCompressed())
+	restorer.pending[peerKey] = []chanbackup.Single{first, second}
+
+	restorer.removePending(first)
+	assertChannelsChanged(t, changed, true)
+
+	pending := restorer.pendingSingles()
+	if len(pending) != 1 ||
+		pending[0].FundingOutpoint != second.FundingOutpoint {
+
+		t.Fatalf("expected ChannelPoint(%v) to remain pending, "+
+			"found %v", second.FundingOutpoint, pending)
+	}
+
+	restorer.removePending(second)
+	assertChannelsChanged(t, changed, true)
+
+	if _, ok := restorer.pending[peerKey]; ok {
+		t.Fatalf("peer without pending restores still tracked")
+	}
+}
+
+// TestChanRestorerSweepsRemoteCommit tests that once the remote peer of a
+// restored channel has broadcast its commitment transaction, the output
+// paying to our commitment key is swept back into the wallet.
+func TestChanRestorerSweepsRemoteCommit(t *testing.T) {
+	chain := simchain.New(&chaincfg.RegressionNetParams)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+	defer chain.Stop()
+
+	cdb, _, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	simWallet, err := simchain.NewWallet(chain, bytes.Repeat([]byte{4}, 32))
+	if err != nil {
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+	wallet, err := lnwallet.NewLightningWallet(cdb, chain, simWallet,
+		simWallet, simWallet, simWallet,
+		lnwallet.StaticFeeEstimator{FeeRate: sweepTestFeeRate},
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+
+	sweeper := newSweeper(wallet, chain,
+		lnwallet.StaticFeeEstimator{FeeRate: sweepTestFeeRate},
+		10*time.Millisecond)
+	if err := sweeper.Start(); err != nil {
+		t.Fatalf("unable to start sweeper: %v", err)
+	}
+	defer sweeper.Stop()
+
+	changed, channelsChanged := newChannelsChangedSignal()
+	restorer := newChanRestorer(&server{
+		chanDB:        cdb,
+		chainNotifier: chain,
+		lnwallet:      wallet,
+		sweeper:       sweeper,
+		peersByPub:    make(map[string]*peer),
+	}, channelsChanged)
+	if err := restorer.Start(); err != nil {
+		t.Fatalf("unable to start restorer: %v", err)
+	}
+	defer restorer.Stop()
+
+	// Confirm the funding output of the channel we'll restore.
+	const capacity = btcutil.Amount(1000000)
+	fundingTxid, err := chain.SendOutputs([]*wire.TxOut{{
+		Value:    int64(capacity),
+		PkScript: []byte{0x00},
+	}}, 0)
+	if err != nil {
+		t.Fatalf("unable to publish funding tx: %v", err)
+	}
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	single := newTestRestoreSingle(t, 3)
+	single.FundingOutpoint = wire.OutPoint{Hash: *fundingTxid}
+	single.Capacity = capacity
+	single.FundingBroadcastHeight = 1
+
+	if err := restorer.restore([]chanbackup.Single{single}); err != nil {
+		t.Fatalf("unable to restore channel: %v", err)
+	}
+	if len(restorer.pendingSingles()) != 1 {
+		t.Fatalf("channel not pending restore")
+	}
+	assertChannelsChanged(t, changed, true)
+
+	// The keys used within the channel should have been reserved, so
+	// they're never used within a new channel.
+	keyDesc, err := wallet.KeyRing.DeriveNextKey(keychain.KeyFamilyCommitKey)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if keyDesc.Index <= single.CommitKeyLoc.Index {
+		t.Fatalf("commitment key index %v not reserved, derived %v",
+			single.CommitKeyLoc.Index, keyDesc.Index)
+	}
+
+	// The remote peer now broadcasts its commitment transaction, paying
+	// our balance to our commitment key.
+	commitKey, err := wallet.KeyRing.DeriveKey(single.CommitKeyLoc)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	ourScript, err := p2wkhScript(commitKey.PubKey)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	ourOutput := &wire.TxOut{Value: 400000, PkScript: ourScript}
+
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&wire.TxIn{PreviousOutPoint: single.FundingOutpoint})
+	commitTx.AddTxOut(&wire.TxOut{Value: 590000, PkScript: []byte{0x00}})
+	commitTx.AddTxOut(ourOutput)
+	if err := chain.PublishTransaction(commitTx); err != nil {
+		t.Fatalf("unable to publish commitment tx: %v", err)
+	}
+
+	ourOutPoint := wire.OutPoint{Hash: commitTx.TxHash(), Index: 1}
+	spendNtfn, err := chain.RegisterSpendNtfn(&ourOutPoint, 1)
+	if err != nil {
+		t.Fatalf("unable to register for spend: %v", err)
+	}
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	select {
+	case spend := <-spendNtfn.Spend:
+		assertValidSpend(t, spend.SpendingTx,
+			int(spend.SpenderInputIndex), ourOutput)
+
+	case <-time.After(sweepTestTimeout):
+		t.Fatalf("output paying to us not swept")
+	}
+
+	// As the channel has been closed, it should no longer be pending
+	// restore, and the backups should have been updated to exclude it.
+	if len(restorer.pendingSingles()) != 0 {
+		t.Fatalf("closed channel still pending restore")
+	}
+	assertChannelsChanged(t, changed, true)
+}
@@ -0,0 +1,285 @@
+package cipherseed
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/salsa20"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// CipherSeedVersion is the current version of the enciphering scheme
+	// used to protect a CipherSeed. It's the first byte of every
+	// enciphered seed, allowing the scheme to be changed in the future.
+	CipherSeedVersion uint8 = 0
+
+	// InternalVersion is the current version of the scheme used to derive
+	// the keys of a wallet from the entropy of its CipherSeed.
+	InternalVersion uint8 = 0
+
+	// EntropySize is the number of bytes of entropy within a CipherSeed.
+	// The entropy is used directly as the HD seed of the wallet.
+	EntropySize = 16
+
+	// DecipheredCipherSeedSize is the size of a serialized CipherSeed:
+	// its internal version (1 byte), birthday (2 bytes) and entropy (16
+	// bytes).
+	DecipheredCipherSeedSize = 19
+
+	// EncipheredCipherSeedSize is the size of an enciphered CipherSeed:
+	// the external version (1 byte), the ciphertext of the serialized seed
+	// (19 bytes), an authentication tag (4 bytes), the salt used to derive
+	// the enciphering keys (5 bytes) and a checksum of all prior bytes (4
+	// bytes).
+	EncipheredCipherSeedSize = 33
+
+	// NumMnemonicWords is the number of words within the mnemonic encoding
+	// of an enciphered CipherSeed. Each word encodes 11 bits, so the
+	// mnemonic encodes all 264 bits of the enciphered seed.
+	NumMnemonicWords = 24
+
+	// bitsPerWord is the number of bits encoded by each word of a
+	// mnemonic.
+	bitsPerWord = 11
+
+	saltSize = 5
+	tagSize  = 4
+
+	// The offsets of each field within an enciphered CipherSeed.
+	cipherTextOffset = 1
+	tagOffset        = cipherTextOffset + DecipheredCipherSeedSize
+	saltOffset       = tagOffset + tagSize
+	checksumOffset   = saltOffset + saltSize
+
+	// The scrypt parameters used to derive the enciphering keys from the
+	// passphrase. These are deliberately expensive in order to slow down
+	// brute force attacks against the passphrase.
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+
+	keySize = 32
+)
+
+var (
+	// BitcoinGenesisDate is the timestamp of the Bitcoin genesis block. A
+	// CipherSeed's birthday is expressed as the number of days elapsed
+	// since this date.
+	BitcoinGenesisDate = time.Unix(1231006505, 0)
+
+	// defaultPassphrase is the passphrase used to encipher a seed if the
+	// user doesn't specify one.
+	defaultPassphrase = []byte("lnd")
+
+	// checksumTable is the CRC-32 table used to compute the checksum of an
+	// enciphered seed.
+	checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// ErrIncorrectVersion is returned when deciphering a seed with an
+	// unknown version.
+	ErrIncorrectVersion = errors.New("unknown cipher seed version")
+
+	// ErrIncorrectMnemonic is returned when the checksum of a mnemonic
+	// doesn't match, indicating that the mnemonic was mistyped.
+	ErrIncorrectMnemonic = errors.New("mnemonic checksum mismatch, " +
+		"the mnemonic may be mistyped")
+
+	// ErrInvalidPass is returned when a seed is deciphered using a
+	// passphrase other than the one it was enciphered with.
+	ErrInvalidPass = errors.New("invalid cipher seed passphrase")
+)
+
+// CipherSeed is a versioned seed from which a wallet can be deterministically
+// re-derived. Along with its entropy, the seed records its birthday, the day
+// at which it was created, so a restored wallet needs only to scan the chain
+// from that day onwards in order to recover its funds. A CipherSeed is
+// enciphered under an optional passphrase before being encoded as a mnemonic
+// for the user to back up.
+type CipherSeed struct {
+	// InternalVersion is the version of the scheme used to derive the
+	// keys of a wallet from the seed.
+	InternalVersion uint8
+
+	// Birthday is the number of days elapsed between the Bitcoin genesis
+	// block and the creation of the seed.
+	Birthday uint16
+
+	// Entropy is the entropy of the seed, from which all keys of the
+	// wallet are derived.
+	Entropy [EntropySize]byte
+
+	// salt is the salt used to derive the keys that encipher the seed.
+	salt [saltSize]byte
+}
+
+// New creates a new CipherSeed with the passed internal version and a
+// birthday of now. If entropy is nil, then fresh entropy is read from the
+// system's CSPRNG.
+func New(internalVersion uint8, entropy *[EntropySize]byte,
+	now time.Time) (*CipherSeed, error) {
+
+	seed := &CipherSeed{
+		InternalVersion: internalVersion,
+		Birthday:        uint16(now.Sub(BitcoinGenesisDate) / (24 * time.Hour)),
+	}
+
+	if entropy != nil {
+		seed.Entropy = *entropy
+	} else if _, err := rand.Read(seed.Entropy[:]); err != nil {
+		return nil, err
+	}
+
+	if _, err := rand.Read(seed.salt[:]); err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+// BirthdayTime returns the day at which the seed was created.
+func (c *CipherSeed) BirthdayTime() time.Time {
+	return BitcoinGenesisDate.Add(time.Duration(c.Birthday) * 24 * time.Hour)
+}
+
+// encode serializes the seed's internal version, birthday and entropy to the
+// passed io.Writer.
+func (c *CipherSeed) encode(w io.Writer) error {
+	if err := binary.Write(w, binary.BigEndian, c.InternalVersion); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, c.Birthday); err != nil {
+		return err
+	}
+	_, err := w.Write(c.Entropy[:])
+	return err
+}
+
+// decode deserializes the seed's internal version, birthday and entropy from
+// the passed io.Reader.
+func (c *CipherSeed) decode(r io.Reader) error {
+	if err := binary.Read(r, binary.BigEndian, &c.InternalVersion); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.BigEndian, &c.Birthday); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(r, c.Entropy[:])
+	return err
+}
+
+// Encipher enciphers the seed under the passed passphrase. If the passphrase
+// is empty, then a default passphrase is used instead.
+func (c *CipherSeed) Encipher(pass []byte) ([EncipheredCipherSeedSize]byte, error) {
+	var cipherSeedBytes [EncipheredCipherSeedSize]byte
+
+	encKey, macKey, err := deriveKeys(pass, c.salt[:])
+	if err != nil {
+		return cipherSeedBytes, err
+	}
+
+	var plainText bytes.Buffer
+	if err := c.encode(&plainText); err != nil {
+		return cipherSeedBytes, err
+	}
+
+	cipherSeedBytes[0] = CipherSeedVersion
+	copy(cipherSeedBytes[saltOffset:checksumOffset], c.salt[:])
+
+	// As the enciphering key is unique to the salt, we're able to use a
+	// zero nonce.
+	var nonce [8]byte
+	salsa20.XORKeyStream(cipherSeedBytes[cipherTextOffset:tagOffset],
+		plainText.Bytes(), nonce[:], encKey)
+
+	tag := seedTag(macKey, cipherSeedBytes[:])
+	copy(cipherSeedBytes[tagOffset:saltOffset], tag)
+
+	checksum := crc32.Checksum(cipherSeedBytes[:checksumOffset],
+		checksumTable)
+	binary.BigEndian.PutUint32(cipherSeedBytes[checksumOffset:], checksum)
+
+	return cipherSeedBytes, nil
+}
+
+// Decipher deciphers a seed enciphered under the passed passphrase. An error
+// is returned if the enciphered seed is corrupted, or if the passphrase is
+// incorrect.
+func Decipher(cipherSeedBytes [EncipheredCipherSeedSize]byte,
+	pass []byte) (*CipherSeed, error) {
+
+	checksum := binary.BigEndian.Uint32(cipherSeedBytes[checksumOffset:])
+	if checksum != crc32.Checksum(cipherSeedBytes[:checksumOffset],
+		checksumTable) {
+
+		return nil, ErrIncorrectMnemonic
+	}
+
+	if cipherSeedBytes[0] != CipherSeedVersion {
+		return nil, ErrIncorrectVersion
+	}
+
+	var seed CipherSeed
+	copy(seed.salt[:], cipherSeedBytes[saltOffset:checksumOffset])
+
+	encKey, macKey, err := deriveKeys(pass, seed.salt[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Before deciphering the seed, we'll ensure that the passphrase is
+	// correct by checking the authentication tag.
+	tag := seedTag(macKey, cipherSeedBytes[:])
+	if !hmac.Equal(tag, cipherSeedBytes[tagOffset:saltOffset]) {
+		return nil, ErrInvalidPass
+	}
+
+	var nonce [8]byte
+	plainText := make([]byte, DecipheredCipherSeedSize)
+	salsa20.XORKeyStream(plainText,
+		cipherSeedBytes[cipherTextOffset:tagOffset], nonce[:], encKey)
+
+	if err := seed.decode(bytes.NewReader(plainText)); err != nil {
+		return nil, err
+	}
+	if seed.InternalVersion != InternalVersion {
+		return nil, ErrIncorrectVersion
+	}
+
+	return &seed, nil
+}
+
+// deriveKeys derives the key used to encipher a seed, and the key used to
+// authenticate it, from the passed passphrase and salt.
+func deriveKeys(pass, salt []byte) (*[keySize]byte, []byte, error) {
+	if len(pass) == 0 {
+		pass = defaultPassphrase
+	}
+
+	keys, err := scrypt.Key(pass, salt, scryptN, scryptR, scryptP,
+		keySize*2)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var encKey [keySize]byte
+	copy(encKey[:], keys[:keySize])
+
+	return &encKey, keys[keySize:], nil
+}
+
+// seedTag computes the authentication tag of an enciphered seed, which
+// commits to its version, ciphertext and salt.
+func seedTag(macKey []byte, cipherSeedBytes []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(cipherSeedBytes[:tagOffset])
+	mac.Write(cipherSeedBytes[saltOffset:checksumOffset])
+	return mac.Sum(nil)[:tagSize]
+}
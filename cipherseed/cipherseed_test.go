@@ -0,0 +1,123 @@
+package cipherseed
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+var (
+	testEntropy = [EntropySize]byte{
+		0x81, 0xb6, 0x37, 0xd8, 0x63, 0x59, 0xe6, 0x96,
+		0x0d, 0xe7, 0x95, 0xe4, 0x1e, 0x0b, 0x4c, 0xfd,
+	}
+
+	testPass = []byte("test")
+)
+
+// TestCipherSeedRoundTrip tests that a seed encoded as a mnemonic can be
+// decoded and deciphered back into the original seed.
+func TestCipherSeedRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1500000000, 0)
+	seed, err := New(InternalVersion, &testEntropy, now)
+	if err != nil {
+		t.Fatalf("unable to create seed: %v", err)
+	}
+
+	for _, pass := range [][]byte{testPass, nil} {
+		mnemonic, err := seed.ToMnemonic(pass)
+		if err != nil {
+			t.Fatalf("unable to create mnemonic: %v", err)
+		}
+
+		// The mnemonic should survive being written out and parsed
+		// back in.
+		var buf bytes.Buffer
+		for _, word := range mnemonic {
+			buf.WriteString(word + " ")
+		}
+		parsed, err := ParseMnemonic(buf.String())
+		if err != nil {
+			t.Fatalf("unable to parse mnemonic: %v", err)
+		}
+
+		newSeed, err := parsed.ToCipherSeed(pass)
+		if err != nil {
+			t.Fatalf("unable to decipher seed: %v", err)
+		}
+		if *newSeed != *seed {
+			t.Fatalf("seeds don't match: expected %v, got %v",
+				seed, newSeed)
+		}
+	}
+
+	// The seed's birthday should be the day it was created.
+	birthday := seed.BirthdayTime()
+	if now.Before(birthday) || now.Sub(birthday) >= 24*time.Hour {
+		t.Fatalf("birthday %v isn't the day of %v", birthday, now)
+	}
+}
+
+// TestCipherSeedInvalidPass tests that deciphering a seed with the wrong
+// passphrase fails.
+func TestCipherSeedInvalidPass(t *testing.T) {
+	t.Parallel()
+
+	seed, err := New(InternalVersion, &testEntropy, time.Now())
+	if err != nil {
+		t.Fatalf("unable to create seed: %v", err)
+	}
+	mnemonic, err := seed.ToMnemonic(testPass)
+	if err != nil {
+		t.Fatalf("unable to create mnemonic: %v", err)
+	}
+
+	_, err = mnemonic.ToCipherSeed([]byte("wrong"))
+	if err != ErrInvalidPass {
+		t.Fatalf("expected ErrInvalidPass, got %v", err)
+	}
+}
+
+// TestMnemonicIncorrect tests that a mistyped mnemonic is rejected.
+func TestMnemonicIncorrect(t *testing.T) {
+	t.Parallel()
+
+	seed, err := New(InternalVersion, &testEntropy, time.Now())
+	if err != nil {
+		t.Fatalf("unable to create seed: %v", err)
+	}
+	mnemonic, err := seed.ToMnemonic(testPass)
+	if err != nil {
+		t.Fatalf("unable to create mnemonic: %v", err)
+	}
+
+	// Swapping a word for another within the word list should be caught
+	// by the checksum.
+	swapped := mnemonic
+	if swapped[3] == englishWordList[0] {
+		swapped[3] = englishWordList[1]
+	} else {
+		swapped[3] = englishWordList[0]
+	}
+	if _, err := swapped.ToCipherSeed(testPass); err != ErrIncorrectMnemonic {
+		t.Fatalf("expected ErrIncorrectMnemonic, got %v", err)
+	}
+
+	// A word outside the word list should be reported.
+	unknown := mnemonic
+	unknown[5] = "lightning"
+	_, err = unknown.ToCipherSeed(testPass)
+	wordErr, ok := err.(ErrUnknownMnemonicWord)
+	if !ok {
+		t.Fatalf("expected ErrUnknownMnemonicWord, got %v", err)
+	}
+	if wordErr.Index != 5 {
+		t.Fatalf("expected word 5 to be unknown, got %v", wordErr.Index)
+	}
+
+	if _, err := ParseMnemonic("abandon ability"); err == nil {
+		t.Fatalf("short mnemonic shouldn't be parsed")
+	}
+}
@@ -0,0 +1,105 @@
+package cipherseed
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownMnemonicWord is returned when a mnemonic contains a word which
+// isn't within the word list.
+type ErrUnknownMnemonicWord struct {
+	// Word is the unknown word.
+	Word string
+
+	// Index is the position of the word within the mnemonic.
+	Index int
+}
+
+// Error returns a human readable string describing the error.
+func (e ErrUnknownMnemonicWord) Error() string {
+	return fmt.Sprintf("word %v (%q) of the mnemonic isn't within the "+
+		"word list", e.Index+1, e.Word)
+}
+
+// Mnemonic is the human readable encoding of an enciphered CipherSeed, which
+// the user writes down in order to back up their wallet.
+type Mnemonic [NumMnemonicWords]string
+
+// ToMnemonic enciphers the seed under the passed passphrase, then encodes it
+// as a mnemonic.
+func (c *CipherSeed) ToMnemonic(pass []byte) (Mnemonic, error) {
+	cipherSeedBytes, err := c.Encipher(pass)
+	if err != nil {
+		return Mnemonic{}, err
+	}
+
+	return cipherTextToMnemonic(cipherSeedBytes), nil
+}
+
+// ToCipherSeed decodes the mnemonic, then deciphers the seed it encodes using
+// the passed passphrase.
+func (m *Mnemonic) ToCipherSeed(pass []byte) (*CipherSeed, error) {
+	cipherSeedBytes, err := mnemonicToCipherText(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decipher(cipherSeedBytes, pass)
+}
+
+// ParseMnemonic parses a mnemonic from a string of words separated by
+// whitespace, as entered by the user.
+func ParseMnemonic(s string) (Mnemonic, error) {
+	var mnemonic Mnemonic
+
+	words := strings.Fields(strings.ToLower(s))
+	if len(words) != NumMnemonicWords {
+		return mnemonic, fmt.Errorf("mnemonic must be %v words, "+
+			"instead got %v", NumMnemonicWords, len(words))
+	}
+	copy(mnemonic[:], words)
+
+	return mnemonic, nil
+}
+
+// cipherTextToMnemonic encodes an enciphered seed as a mnemonic, mapping each
+// 11 bits of the seed to a word, most significant bit first.
+func cipherTextToMnemonic(cipherText [EncipheredCipherSeedSize]byte) Mnemonic {
+	var mnemonic Mnemonic
+	for i := 0; i < NumMnemonicWords; i++ {
+		var index uint16
+		for j := 0; j < bitsPerWord; j++ {
+			bit := uint(i*bitsPerWord + j)
+			index <<= 1
+			index |= uint16(cipherText[bit/8]>>(7-bit%8)) & 1
+		}
+
+		mnemonic[i] = englishWordList[index]
+	}
+
+	return mnemonic
+}
+
+// mnemonicToCipherText decodes the enciphered seed encoded by the passed
+// mnemonic.
+func mnemonicToCipherText(mnemonic *Mnemonic) ([EncipheredCipherSeedSize]byte, error) {
+	var cipherText [EncipheredCipherSeedSize]byte
+	for i, word := range mnemonic {
+		index, ok := englishWordIndex[word]
+		if !ok {
+			return cipherText, ErrUnknownMnemonicWord{
+				Word:  word,
+				Index: i,
+			}
+		}
+
+		for j := 0; j < bitsPerWord; j++ {
+			bit := uint(i*bitsPerWord + j)
+			if index&(1<<uint(bitsPerWord-1-j)) != 0 {
+				cipherText[bit/8] |= 1 << (7 - bit%8)
+			}
+		}
+	}
+
+	return cipherText, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -97,13 +98,52 @@ func newAddress(ctx *cli.Context) error {
 	return nil
 }
 
+// coinControlFlags are the flags shared by the commands which fund a
+// transaction from the wallet, allowing the caller to control the coins
+// selected and the fee rate paid.
+var coinControlFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name: "utxo",
+		Usage: "an output of the form txid:index which may be spent, " +
+			"may be repeated to restrict coin selection to the " +
+			"specified outputs",
+	},
+	cli.Int64Flag{
+		Name:  "sat_per_vbyte",
+		Usage: "the fee rate in satoshis per virtual byte to pay",
+	},
+	cli.IntFlag{
+		Name: "target_conf",
+		Usage: "the number of blocks within which the transaction " +
+			"should confirm, used to estimate the fee rate",
+	},
+	cli.IntFlag{
+		Name: "min_confs",
+		Usage: "the number of confirmations an output requires in " +
+			"order to be spent (default: 1)",
+	},
+	cli.BoolFlag{
+		Name: "spend_unconfirmed",
+		Usage: "allow unconfirmed outputs to be spent, in which case " +
+			"min_confs must not be set",
+	},
+}
+
+// coinSelectFlags extends coinControlFlags with the ability to spend every
+// eligible output.
+var coinSelectFlags = append(coinControlFlags, cli.BoolFlag{
+	Name: "send_all",
+	Usage: "spend every eligible output, sending their entire " +
+		"value less fees, in which case the amount is ignored",
+})
+
 var sendCoinsCommand = cli.Command{
 	Name:      "sendcoins",
 	Usage:     "send bitcoin on-chain to an address",
 	ArgsUsage: "addr amt",
 	Description: "Send amt coins in satoshis to the BASE58 encoded bitcoin address addr.\n\n" +
 		"   Positional arguments and flags can be used interchangeably but not at the same time!",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		cli.StringFlag{
 			Name:  "addr",
 			Usage: "the BASE58 encoded bitcoin address to send coins to on-chain",
@@ -113,7 +153,7 @@ var sendCoinsCommand = cli.Command{
 			Name:  "amt",
 			Usage: "the number of bitcoin denominated in satoshis to send",
 		},
-	},
+	}, coinSelectFlags...),
 	Action: sendCoins,
 }
 
@@ -145,6 +185,8 @@ func sendCoins(ctx *cli.Context) error {
 		amt = ctx.Int64("amt")
 	case args.Present():
 		amt, err = strconv.ParseInt(args.First(), 10, 64)
+	case ctx.Bool("send_all"):
+		// The amount is ignored when sending all coins.
 	default:
 		return fmt.Errorf("Amount argument missing")
 	}
@@ -158,8 +200,14 @@ func sendCoins(ctx *cli.Context) error {
 	defer cleanUp()
 
 	req := &lnrpc.SendCoinsRequest{
-		Addr:   addr,
-		Amount: amt,
+		Addr:             addr,
+		Amount:           amt,
+		Outpoints:        ctx.StringSlice("utxo"),
+		SatPerVbyte:      uint64(ctx.Int64("sat_per_vbyte")),
+		TargetConf:       uint32(ctx.Int("target_conf")),
+		MinConfs:         int32(ctx.Int("min_confs")),
+		SpendUnconfirmed: ctx.Bool("spend_unconfirmed"),
+		SendAll:          ctx.Bool("send_all"),
 	}
 	txid, err := client.SendCoins(ctxb, req)
 	if err != nil {
@@ -179,6 +227,7 @@ var sendManyCommand = cli.Command{
 		"   'send-json-string' decodes addresses and the amount to send " +
 		"respectively in the following format.\n" +
 		`   '{"ExampleAddr": NumCoinsInSatoshis, "SecondAddr": NumCoins}'`,
+	Flags:  coinSelectFlags,
 	Action: sendMany,
 }
 
@@ -195,7 +244,13 @@ func sendMany(ctx *cli.Context) error {
 	defer cleanUp()
 
 	txid, err := client.SendMany(ctxb, &lnrpc.SendManyRequest{
-		AddrToAmount: amountToAddr,
+		AddrToAmount:     amountToAddr,
+		Outpoints:        ctx.StringSlice("utxo"),
+		SatPerVbyte:      uint64(ctx.Int64("sat_per_vbyte")),
+		TargetConf:       uint32(ctx.Int("target_conf")),
+		MinConfs:         int32(ctx.Int("min_confs")),
+		SpendUnconfirmed: ctx.Bool("spend_unconfirmed"),
+		SendAll:          ctx.Bool("send_all"),
 	})
 	if err != nil {
 		return err
@@ -205,6 +260,44 @@ func sendMany(ctx *cli.Context) error {
 	return nil
 }
 
+var listUnspentCommand = cli.Command{
+	Name:  "listunspent",
+	Usage: "List the wallet's unspent witness outputs.",
+	Description: "List the unspent witness outputs controlled by the " +
+		"wallet whose number of confirmations lies between min_confs " +
+		"and max_confs.",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "min_confs",
+			Usage: "the minimum number of confirmations of an output",
+			Value: 1,
+		},
+		cli.IntFlag{
+			Name: "max_confs",
+			Usage: "the maximum number of confirmations of an output, " +
+				"if zero there's no maximum",
+		},
+	},
+	Action: listUnspent,
+}
+
+func listUnspent(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListUnspent(ctxb, &lnrpc.ListUnspentRequest{
+		MinConfs: int32(ctx.Int("min_confs")),
+		MaxConfs: int32(ctx.Int("max_confs")),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var connectCommand = cli.Command{
 	Name:      "connect",
 	Usage:     "connect to a remote lnd peer",
@@ -262,7 +355,7 @@ var openChannelCommand = cli.Command{
 		"output is returned. NOTE: peer_id and node_key are " +
 		"mutually exclusive, only one should be used, not both.",
 	ArgsUsage: "node-key local-amt push-amt [num-confs]",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		cli.IntFlag{
 			Name:  "peer_id",
 			Usage: "the relative id of the peer to open a channel with",
@@ -291,7 +384,19 @@ var openChannelCommand = cli.Command{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
 		},
-	},
+		cli.BoolFlag{
+			Name: "psbt",
+			Usage: "fund the channel from an external wallet, " +
+				"presenting the funding transaction as a PSBT " +
+				"via fundingstatestep",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "make the channel private, such that it won't " +
+				"be announced to the rest of the network; the " +
+				"peer must support extended funding",
+		},
+	}, coinSelectFlags...),
 	Action: openChannel,
 }
 
@@ -316,7 +421,15 @@ func openChannel(ctx *cli.Context) error {
 	}
 
 	req := &lnrpc.OpenChannelRequest{
-		NumConfs: uint32(ctx.Int("num_confs")),
+		NumConfs:         uint32(ctx.Int("num_confs")),
+		Outpoints:        ctx.StringSlice("utxo"),
+		SatPerVbyte:      uint64(ctx.Int64("sat_per_vbyte")),
+		TargetConf:       uint32(ctx.Int("target_conf")),
+		MinConfs:         int32(ctx.Int("min_confs")),
+		SpendUnconfirmed: ctx.Bool("spend_unconfirmed"),
+		SendAll:          ctx.Bool("send_all"),
+		PsbtFunding:      ctx.Bool("psbt"),
+		Private:          ctx.Bool("private"),
 	}
 
 	switch {
@@ -348,6 +461,8 @@ func openChannel(ctx *cli.Context) error {
 			return fmt.Errorf("unable to decode local amt: %v", err)
 		}
 		args = args.Tail()
+	case ctx.Bool("send_all"):
+		// The local amount is ignored when committing all coins.
 	default:
 		return fmt.Errorf("local amt argument missing")
 	}
@@ -375,6 +490,25 @@ func openChannel(ctx *cli.Context) error {
 		}
 
 		switch update := resp.Update.(type) {
+		case *lnrpc.OpenStatusUpdate_PsbtFund:
+			psbtFund := update.PsbtFund
+			printJSON(struct {
+				PendingChanID  string `json:"pending_chan_id"`
+				FundingAddress string `json:"funding_address"`
+				FundingAmount  int64  `json:"funding_amount"`
+			}{
+				PendingChanID:  hex.EncodeToString(psbtFund.PendingChanId),
+				FundingAddress: psbtFund.FundingAddress,
+				FundingAmount:  psbtFund.FundingAmount,
+			},
+			)
+
+			fmt.Fprintln(os.Stderr, "Craft a PSBT paying "+
+				"funding_amount to funding_address, then present "+
+				"it via fundingstatestep --psbt_verify. Once "+
+				"verified, sign it and present it via "+
+				"fundingstatestep --psbt_finalize.")
+
 		case *lnrpc.OpenStatusUpdate_ChanPending:
 			txid, err := chainhash.NewHash(update.ChanPending.Txid)
 			if err != nil {
@@ -410,6 +544,199 @@ func openChannel(ctx *cli.Context) error {
 	}
 }
 
+var batchOpenChannelCommand = cli.Command{
+	Name:  "batchopenchannel",
+	Usage: "Open channels to several existing peers at once.",
+	Description: "Open a channel to each of the specified peers, all " +
+		"funded by a single funding transaction, which is only " +
+		"broadcast once every peer has agreed to their channel. If " +
+		"any channel fails, then none are opened.\n" +
+		"   'channels-json-string' describes the channels in the " +
+		"following format.\n" +
+		`   '[{"node_key": "PubKeyHex", "local_amt": Sats, "push_amt": Sats}]'`,
+	ArgsUsage: "channels-json-string",
+	Flags: append([]cli.Flag{
+		cli.IntFlag{
+			Name: "num_confs",
+			Usage: "the number of confirmations required before the " +
+				"channels are considered 'open'",
+			Value: 1,
+		},
+	}, coinControlFlags...),
+	Action: batchOpenChannel,
+}
+
+func batchOpenChannel(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() == 0 {
+		cli.ShowCommandHelp(ctx, "batchopenchannel")
+		return nil
+	}
+
+	var chans []struct {
+		NodeKey  string `json:"node_key"`
+		LocalAmt int64  `json:"local_amt"`
+		PushAmt  int64  `json:"push_amt"`
+	}
+	if err := json.Unmarshal([]byte(ctx.Args().First()), &chans); err != nil {
+		return fmt.Errorf("unable to decode channels: %v", err)
+	}
+
+	req := &lnrpc.BatchOpenChannelRequest{
+		NumConfs:         uint32(ctx.Int("num_confs")),
+		Outpoints:        ctx.StringSlice("utxo"),
+		SatPerVbyte:      uint64(ctx.Int64("sat_per_vbyte")),
+		TargetConf:       uint32(ctx.Int("target_conf")),
+		MinConfs:         int32(ctx.Int("min_confs")),
+		SpendUnconfirmed: ctx.Bool("spend_unconfirmed"),
+	}
+	for _, channel := range chans {
+		nodePubHex, err := hex.DecodeString(channel.NodeKey)
+		if err != nil {
+			return fmt.Errorf("unable to decode node public key: %v",
+				err)
+		}
+
+		req.Channels = append(req.Channels, &lnrpc.BatchOpenChannel{
+			NodePubkey:         nodePubHex,
+			LocalFundingAmount: channel.LocalAmt,
+			PushSat:            channel.PushAmt,
+		})
+	}
+
+	resp, err := client.BatchOpenChannel(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	channelPoints := make([]string, len(resp.PendingChannels))
+	for i, pendingChan := range resp.PendingChannels {
+		txid, err := chainhash.NewHash(pendingChan.Txid)
+		if err != nil {
+			return err
+		}
+		channelPoints[i] = fmt.Sprintf("%v:%v", txid,
+			pendingChan.OutputIndex)
+	}
+
+	printJSON(struct {
+		ChannelPoints []string `json:"channel_points"`
+	}{
+		ChannelPoints: channelPoints,
+	},
+	)
+	return nil
+}
+
+var fundingStateStepCommand = cli.Command{
+	Name:  "fundingstatestep",
+	Usage: "Advance the funding of a PSBT funded channel.",
+	Description: "Advance the funding workflow of a channel opened via " +
+		"openchannel --psbt. First, present the unsigned PSBT funding " +
+		"the channel via --psbt_verify. Once it has been verified, " +
+		"sign it, then present the signed PSBT via --psbt_finalize, " +
+		"or the signed transaction via --final_raw_tx, after which " +
+		"the funding transaction is broadcast. All PSBTs are base64 " +
+		"encoded.",
+	ArgsUsage: "pending_chan_id",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pending_chan_id",
+			Usage: "the hex encoded pending channel ID",
+		},
+		cli.StringFlag{
+			Name:  "psbt_verify",
+			Usage: "the unsigned PSBT funding the channel",
+		},
+		cli.StringFlag{
+			Name:  "psbt_finalize",
+			Usage: "the signed PSBT funding the channel",
+		},
+		cli.StringFlag{
+			Name:  "final_raw_tx",
+			Usage: "the hex encoded signed funding transaction",
+		},
+	},
+	Action: fundingStateStep,
+}
+
+func fundingStateStep(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var chanIDStr string
+	switch {
+	case ctx.IsSet("pending_chan_id"):
+		chanIDStr = ctx.String("pending_chan_id")
+	case ctx.Args().Present():
+		chanIDStr = ctx.Args().First()
+	default:
+		return fmt.Errorf("pending_chan_id argument missing")
+	}
+	pendingChanID, err := hex.DecodeString(chanIDStr)
+	if err != nil {
+		return fmt.Errorf("unable to decode pending_chan_id: %v", err)
+	}
+
+	req := &lnrpc.FundingTransitionMsg{}
+	switch {
+	case ctx.IsSet("psbt_verify"):
+		packet, err := base64.StdEncoding.DecodeString(
+			ctx.String("psbt_verify"))
+		if err != nil {
+			return fmt.Errorf("unable to decode psbt: %v", err)
+		}
+		req.Trigger = &lnrpc.FundingTransitionMsg_PsbtVerify{
+			PsbtVerify: &lnrpc.FundingPsbtVerify{
+				PendingChanId: pendingChanID,
+				FundedPsbt:    packet,
+			},
+		}
+
+	case ctx.IsSet("psbt_finalize"):
+		packet, err := base64.StdEncoding.DecodeString(
+			ctx.String("psbt_finalize"))
+		if err != nil {
+			return fmt.Errorf("unable to decode psbt: %v", err)
+		}
+		req.Trigger = &lnrpc.FundingTransitionMsg_PsbtFinalize{
+			PsbtFinalize: &lnrpc.FundingPsbtFinalize{
+				PendingChanId: pendingChanID,
+				SignedPsbt:    packet,
+			},
+		}
+
+	case ctx.IsSet("final_raw_tx"):
+		rawTx, err := hex.DecodeString(ctx.String("final_raw_tx"))
+		if err != nil {
+			return fmt.Errorf("unable to decode final_raw_tx: %v",
+				err)
+		}
+		req.Trigger = &lnrpc.FundingTransitionMsg_PsbtFinalize{
+			PsbtFinalize: &lnrpc.FundingPsbtFinalize{
+				PendingChanId: pendingChanID,
+				FinalRawTx:    rawTx,
+			},
+		}
+
+	default:
+		return fmt.Errorf("one of psbt_verify, psbt_finalize or " +
+			"final_raw_tx must be set")
+	}
+
+	resp, err := client.FundingStateStep(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // TODO(roasbeef): also allow short relative channel ID.
 
 var closeChannelCommand = cli.Command{
@@ -1520,3 +1847,253 @@ func listChainTxns(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var rotateOnionKeyCommand = cli.Command{
+	Name:        "rotateonionkey",
+	Usage:       "Rotate the node's onion key.",
+	Description: "Derive a new onion key to be used to process incoming onion packets, and announce it to the network. Onion packets constructed using the prior key will continue to be accepted until the configured grace period has elapsed.",
+	Action:      rotateOnionKey,
+}
+
+func rotateOnionKey(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RotateOnionKey(ctxb, &lnrpc.RotateOnionKeyRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var estimateFeeCommand = cli.Command{
+	Name:        "estimatefee",
+	Usage:       "Estimate the fee rate of an on-chain transaction.",
+	Description: "Estimate the fee rate a transaction must pay in order to confirm within the target number of blocks, using the same fee estimator lnd uses to price its own transactions.",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "conf_target",
+			Value: 6,
+			Usage: "the number of blocks within which the transaction should confirm",
+		},
+	},
+	Action: estimateFee,
+}
+
+func estimateFee(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.EstimateFeeRequest{
+		TargetConf: int32(ctx.Int("conf_target")),
+	}
+	resp, err := client.EstimateFee(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var bumpFeeCommand = cli.Command{
+	Name:  "bumpfee",
+	Usage: "Bump the fee of an unconfirmed transaction.",
+	Description: "Bump the fee of an unconfirmed funding, close or sweep transaction. " +
+		"If all of its inputs belong to the wallet, and it signals replaceability, the " +
+		"transaction is replaced by one paying a higher fee. Otherwise, a child transaction " +
+		"spending one of its outputs is broadcast, paying for its parent. Passing an " +
+		"outpoint rather than a txid forces the output to be spent by a child.",
+	ArgsUsage: "txid",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "txid",
+			Usage: "the txid of the transaction to bump",
+		},
+		cli.StringFlag{
+			Name:  "outpoint",
+			Usage: "an output of the transaction to spend with a child, of the form txid:index",
+		},
+		cli.IntFlag{
+			Name:  "conf_target",
+			Value: 6,
+			Usage: "the number of blocks within which the transaction should confirm",
+		},
+		cli.Int64Flag{
+			Name:  "fee_per_weight",
+			Usage: "the fee rate to target in satoshis per weight unit, taking precedence over conf_target",
+		},
+	},
+	Action: bumpFee,
+}
+
+func bumpFee(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	txid := ctx.String("txid")
+	if txid == "" && ctx.NArg() > 0 {
+		txid = ctx.Args().First()
+	}
+
+	req := &lnrpc.BumpFeeRequest{
+		Txid:         txid,
+		Outpoint:     ctx.String("outpoint"),
+		TargetConf:   int32(ctx.Int("conf_target")),
+		FeePerWeight: ctx.Int64("fee_per_weight"),
+	}
+	resp, err := client.BumpFee(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportChanBackupCommand = cli.Command{
+	Name:  "exportchanbackup",
+	Usage: "Export the static backup of a channel.",
+	Description: "Export the encrypted static backup of a channel, allowing its " +
+		"funds to be recovered using restorechanbackup should the channel " +
+		"database be lost. The backup is printed as hex, unless an output " +
+		"file is specified.",
+	ArgsUsage: "chan_point",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "chan_point",
+			Usage: "the channel point of the channel to back up, of the form txid:index",
+		},
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the backup to",
+		},
+	},
+	Action: exportChanBackup,
+}
+
+func exportChanBackup(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	chanPointStr := ctx.String("chan_point")
+	if chanPointStr == "" && ctx.NArg() > 0 {
+		chanPointStr = ctx.Args().First()
+	}
+
+	parts := strings.Split(chanPointStr, ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("chan_point %v not of the form txid:index",
+			chanPointStr)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to decode output index: %v", err)
+	}
+
+	req := &lnrpc.ExportChannelBackupRequest{
+		ChanPoint: &lnrpc.ChannelPoint{
+			FundingTxidStr: parts[0],
+			OutputIndex:    uint32(index),
+		},
+	}
+	resp, err := client.ExportChannelBackup(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	if outputFile := ctx.String("output_file"); outputFile != "" {
+		return ioutil.WriteFile(outputFile, resp.ChanBackup, 0600)
+	}
+
+	printJSON(struct {
+		ChanPoint  string `json:"chan_point"`
+		ChanBackup string `json:"chan_backup"`
+	}{
+		ChanPoint:  chanPointStr,
+		ChanBackup: hex.EncodeToString(resp.ChanBackup),
+	})
+	return nil
+}
+
+var restoreChanBackupCommand = cli.Command{
+	Name:  "restorechanbackup",
+	Usage: "Restore channels from their static backups.",
+	Description: "Restore the channels within a set of static backups. As the " +
+		"state of the channels has been lost, the remote peer of each channel " +
+		"is asked to force close it, after which our balance is swept back " +
+		"into the wallet. Backups may be passed as hex, or as the backup file " +
+		"maintained by lnd.",
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "single_backup",
+			Usage: "the hex encoded static backup of a single channel, may be repeated",
+		},
+		cli.StringFlag{
+			Name:  "multi_backup",
+			Usage: "the hex encoded static backup of multiple channels",
+		},
+		cli.StringFlag{
+			Name:  "multi_file",
+			Usage: "the path of a file holding the static backup of multiple channels",
+		},
+	},
+	Action: restoreChanBackup,
+}
+
+func restoreChanBackup(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.RestoreChanBackupRequest{}
+	for _, singleHex := range ctx.StringSlice("single_backup") {
+		single, err := hex.DecodeString(singleHex)
+		if err != nil {
+			return fmt.Errorf("unable to decode single backup: %v",
+				err)
+		}
+		req.ChanBackups = append(req.ChanBackups, single)
+	}
+
+	var err error
+	switch {
+	case ctx.IsSet("multi_backup") && ctx.IsSet("multi_file"):
+		return fmt.Errorf("only one of multi_backup and multi_file " +
+			"may be set")
+
+	case ctx.IsSet("multi_backup"):
+		req.MultiChanBackup, err = hex.DecodeString(
+			ctx.String("multi_backup"))
+		if err != nil {
+			return fmt.Errorf("unable to decode multi backup: %v",
+				err)
+		}
+
+	case ctx.IsSet("multi_file"):
+		req.MultiChanBackup, err = ioutil.ReadFile(
+			ctx.String("multi_file"))
+		if err != nil {
+			return fmt.Errorf("unable to read multi backup file: "+
+				"%v", err)
+		}
+	}
+
+	if len(req.ChanBackups) == 0 && len(req.MultiChanBackup) == 0 {
+		return fmt.Errorf("no channel backups specified")
+	}
+
+	resp, err := client.RestoreChannelBackups(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
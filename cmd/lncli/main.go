@@ -55,9 +55,12 @@ func main() {
 	app.Commands = []cli.Command{
 		newAddressCommand,
 		sendManyCommand,
+		listUnspentCommand,
 		sendCoinsCommand,
 		connectCommand,
 		openChannelCommand,
+		fundingStateStepCommand,
+		batchOpenChannelCommand,
 		closeChannelCommand,
 		listPeersCommand,
 		walletBalanceCommand,
@@ -78,6 +81,11 @@ func main() {
 		debugLevelCommand,
 		decodePayReqComamnd,
 		listChainTxnsCommand,
+		rotateOnionKeyCommand,
+		estimateFeeCommand,
+		bumpFeeCommand,
+		exportChanBackupCommand,
+		restoreChanBackupCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -10,11 +10,15 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	flags "github.com/btcsuite/go-flags"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
-	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
 )
 
@@ -31,6 +35,21 @@ const (
 	defaultRPCUser            = ""
 	defaultRPCPass            = ""
 	defaultMaxPendingChannels = 1
+	defaultOnionKeyRotation   = time.Hour * 24 * 7
+	defaultOnionKeyGrace      = time.Hour * 24
+	defaultHtlcExpiryDelta    = 10
+	defaultCommitBatchSize    = 10
+	defaultCommitBatchDelay   = time.Millisecond * 50
+	defaultBitcoinNode        = "btcd"
+	defaultWalletController   = "btcwallet"
+	defaultWalletPrivatePass  = "hello"
+	defaultRecoveryWindow     = 250
+	defaultFeeRate            = 25
+	defaultSweepBatchWindow   = time.Second
+	defaultAcceptorTimeout    = time.Second * 15
+	defaultReservationTimeout = time.Minute * 10
+	defaultPsbtTimeout        = time.Hour * 24
+	defaultMaxPendingBlocks   = 2016
 )
 
 var (
@@ -41,8 +60,32 @@ var (
 
 	btcdHomeDir        = btcutil.AppDataDir("btcd", false)
 	defaultRPCCertFile = filepath.Join(btcdHomeDir, "rpc.cert")
+
+	bitcoindHomeDir = btcutil.AppDataDir("bitcoin", false)
 )
 
+// chainConfig houses the options which select the bitcoin node backing lnd.
+type chainConfig struct {
+	Node    string `long:"node" description:"The bitcoin node implementation lnd should use as its chain backend" choice:"btcd" choice:"bitcoind" choice:"neutrino"`
+	ZMQPath string `long:"zmqpath" description:"The address at which the bitcoind node publishes the hashblock, rawblock and rawtx ZMQ topics, e.g. tcp://127.0.0.1:28332. Only used if the node is bitcoind."`
+}
+
+// neutrinoConfig houses the options of the neutrino light client, which is
+// used in place of a full node if the bitcoin node is neutrino.
+type neutrinoConfig struct {
+	AddPeers     []string `long:"addpeer" description:"Add a peer serving compact filters to connect with at startup"`
+	ConnectPeers []string `long:"connect" description:"Connect only to the specified peers serving compact filters at startup"`
+}
+
+// btcwalletConfig houses the options of the btcwallet WalletController
+// driver.
+type btcwalletConfig struct {
+	PrivatePass    string `long:"privatepass" default-mask:"-" description:"The passphrase used to encrypt the private keys of the wallet"`
+	SeedPass       string `long:"seedpass" default-mask:"-" description:"The optional passphrase used to encipher the mnemonic seed of the wallet"`
+	Restore        bool   `long:"restore" description:"If the wallet doesn't yet exist, restore it from an existing mnemonic seed read from stdin, then scan the chain for its funds"`
+	RecoveryWindow uint32 `long:"recoverywindow" description:"The number of unused addresses beyond the last used address the wallet watches while recovering its funds"`
+}
+
 // config defines the configuration options for lnd.
 //
 // See loadConfig for further details regarding the configuration
@@ -72,6 +115,44 @@ type config struct {
 	SimNet             bool   `long:"simnet" description:"Use the simulation test network"`
 	DebugHTLC          bool   `long:"debughtlc" description:"Activate the debug htlc mode. With the debug HTLC mode, all payments sent use a pre-determined R-Hash. Additionally, all HTLCs sent to a node with the debug HTLC R-Hash are immediately settled in the next available state transition."`
 	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
+
+	AcceptorTimeout       time.Duration `long:"acceptortimeout" description:"The amount of time to wait for a client of the ChannelAcceptor RPC to decide on an inbound channel request, after which the default decision is used."`
+	AcceptorRejectDefault bool          `long:"acceptorrejectdefault" description:"Reject inbound channel requests which a client of the ChannelAcceptor RPC fails to decide on in time, rather than accepting them."`
+
+	ReservationTimeout time.Duration `long:"reservationtimeout" description:"The amount of time a channel reservation may go without progressing through the funding flow before it's cancelled, releasing any coins it had reserved."`
+	PsbtTimeout        time.Duration `long:"psbttimeout" description:"The amount of time a PSBT funded channel reservation may await a PSBT from the caller before it's cancelled. As the PSBT may need to be signed by hand, this is typically longer than reservationtimeout."`
+	MaxPendingBlocks   uint32        `long:"maxpendingblocks" description:"The number of blocks to wait for the funding transaction of an inbound pending channel to confirm before forgetting the channel. A value of 0 waits indefinitely."`
+
+	OnionKeyRotation    time.Duration `long:"onionkeyrotation" description:"The interval at which the onion key used to process Sphinx packets is rotated. A value of 0 disables scheduled rotation."`
+	OnionKeyGracePeriod time.Duration `long:"onionkeygraceperiod" description:"The period following an onion key rotation during which onion packets constructed using the prior key are still accepted."`
+
+	HtlcExpiryDelta uint32 `long:"htlcexpirydelta" description:"The number of blocks before the expiry of an outgoing HTLC at which its channel will be force closed if the HTLC hasn't yet been resolved off-chain."`
+
+	CommitBatchSize     uint32        `long:"commitbatchsize" description:"The number of HTLCs added to a channel before a new commitment is signed. Settles and fails are always signed immediately. A value of 1 disables batching, signing a new commitment after each update."`
+	CommitBatchInterval time.Duration `long:"commitbatchinterval" description:"The maximum amount of time channel updates are collected before a new commitment is signed."`
+
+	ChainNotifier    string `long:"chainnotifier" description:"The ChainNotifier driver used to receive notifications from the chain. If unset, the driver matching bitcoin.node is used."`
+	WalletController string `long:"walletcontroller" description:"The WalletController driver which manages lnd's on-chain funds"`
+
+	FeeRate int64 `long:"feerate" description:"The fee rate, in satoshis per weight unit, used if the chain backend is unable to estimate fees. The neutrino backend always uses this rate."`
+
+	SweepBatchWindow time.Duration `long:"sweepbatchwindow" description:"The amount of time outputs ready to be swept are collected before they're batched into a single sweep transaction."`
+
+	BackupFilePath string `long:"backupfilepath" description:"The path of the file holding the static backups of all channels. If unset, the file is placed within the data directory."`
+
+	SignRPCListen   string `long:"signrpclisten" description:"The host:port on which to expose the Signer service, allowing a node without private keys to sign using this node's wallet. The service is served separately from the RPC server, and only to clients authenticating with a TLS certificate signed by signrpcclientca."`
+	SignRPCTLSCert  string `long:"signrpctlscert" description:"The TLS certificate presented by the Signer service."`
+	SignRPCTLSKey   string `long:"signrpctlskey" description:"The private key of the Signer service's TLS certificate."`
+	SignRPCClientCA string `long:"signrpcclientca" description:"The PEM encoded certificates trusted to sign the TLS certificates of Signer service clients."`
+
+	RemoteSigner        string `long:"remotesigner" description:"The host:port of a remote Signer service which holds all of the node's private keys. The local wallet is watch-only, so it must be restored from the signer's seed, and all signing and key derivation is delegated to the signer."`
+	RemoteSignerTLSCert string `long:"remotesignertlscert" description:"The TLS certificate used to authenticate with the remote signer."`
+	RemoteSignerTLSKey  string `long:"remotesignertlskey" description:"The private key of the TLS certificate used to authenticate with the remote signer."`
+	RemoteSignerCA      string `long:"remotesignerca" description:"The PEM encoded certificates trusted to sign the remote signer's TLS certificate."`
+
+	Bitcoin      *chainConfig     `group:"Bitcoin" namespace:"bitcoin"`
+	NeutrinoMode *neutrinoConfig  `group:"neutrino" namespace:"neutrino"`
+	Btcwallet    *btcwalletConfig `group:"btcwallet" namespace:"btcwallet"`
 }
 
 // loadConfig initializes and parses the config using a config file and command
@@ -84,17 +165,37 @@ type config struct {
 // 	4) Parse CLI options and overwrite/add any specified options
 func loadConfig() (*config, error) {
 	defaultCfg := config{
-		ConfigFile:         defaultConfigFile,
-		DataDir:            defaultDataDir,
-		DebugLevel:         defaultLogLevel,
-		LogDir:             defaultLogDir,
-		PeerPort:           defaultPeerPort,
-		RPCPort:            defaultRPCPort,
-		RPCHost:            defaultRPCHost,
-		RPCUser:            defaultRPCUser,
-		RPCPass:            defaultRPCPass,
-		RPCCert:            defaultRPCCertFile,
-		MaxPendingChannels: defaultMaxPendingChannels,
+		ConfigFile:          defaultConfigFile,
+		DataDir:             defaultDataDir,
+		DebugLevel:          defaultLogLevel,
+		LogDir:              defaultLogDir,
+		PeerPort:            defaultPeerPort,
+		RPCPort:             defaultRPCPort,
+		RPCHost:             defaultRPCHost,
+		RPCUser:             defaultRPCUser,
+		RPCPass:             defaultRPCPass,
+		RPCCert:             defaultRPCCertFile,
+		MaxPendingChannels:  defaultMaxPendingChannels,
+		OnionKeyRotation:    defaultOnionKeyRotation,
+		OnionKeyGracePeriod: defaultOnionKeyGrace,
+		HtlcExpiryDelta:     defaultHtlcExpiryDelta,
+		CommitBatchSize:     defaultCommitBatchSize,
+		CommitBatchInterval: defaultCommitBatchDelay,
+		FeeRate:             defaultFeeRate,
+		SweepBatchWindow:    defaultSweepBatchWindow,
+		AcceptorTimeout:     defaultAcceptorTimeout,
+		ReservationTimeout:  defaultReservationTimeout,
+		PsbtTimeout:         defaultPsbtTimeout,
+		MaxPendingBlocks:    defaultMaxPendingBlocks,
+		Bitcoin: &chainConfig{
+			Node: defaultBitcoinNode,
+		},
+		NeutrinoMode:     &neutrinoConfig{},
+		WalletController: defaultWalletController,
+		Btcwallet: &btcwalletConfig{
+			PrivatePass:    defaultWalletPrivatePass,
+			RecoveryWindow: defaultRecoveryWindow,
+		},
 	}
 
 	// Pre-parse the command line options to pick up an alternative config
@@ -175,10 +276,130 @@ func loadConfig() (*config, error) {
 		}
 	}
 
+	// Ensure the commitment batching parameters are sane.
+	if cfg.CommitBatchSize == 0 {
+		str := "%s: The commitbatchsize must be at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.CommitBatchInterval <= 0 {
+		str := "%s: The commitbatchinterval must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// bitcoind doesn't support the simulation test network, and must
+	// publish new blocks and transactions over ZMQ in order for lnd to
+	// receive notifications.
+	if cfg.Bitcoin.Node == "bitcoind" {
+		if cfg.SimNet {
+			str := "%s: The simnet params can't be used with a " +
+				"bitcoind node"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		if cfg.Bitcoin.ZMQPath == "" {
+			str := "%s: The bitcoin.zmqpath must be set when using " +
+				"a bitcoind node"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
+	// Unless a ChainNotifier driver was explicitly selected, we'll use the
+	// one which speaks to the bitcoin node backing lnd. Both the selected
+	// ChainNotifier and WalletController must have a registered driver.
+	if cfg.ChainNotifier == "" {
+		cfg.ChainNotifier = cfg.Bitcoin.Node
+	}
+	if _, err := chainntnfs.LookupNotifier(cfg.ChainNotifier); err != nil {
+		str := "%s: Invalid chainnotifier %v, supported drivers: %v"
+		err := fmt.Errorf(str, funcName, cfg.ChainNotifier,
+			chainntnfs.SupportedNotifiers())
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if _, err := lnwallet.LookupWallet(cfg.WalletController); err != nil {
+		str := "%s: Invalid walletcontroller %v, supported drivers: %v"
+		err := fmt.Errorf(str, funcName, cfg.WalletController,
+			lnwallet.SupportedWallets())
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// The fallback fee rate must be positive, otherwise we'd broadcast
+	// transactions unable to propagate through the network.
+	if cfg.FeeRate <= 0 {
+		str := "%s: The feerate must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// A node exposing the Signer service can't itself delegate signing to
+	// a remote signer, as the two would then sign for each other.
+	if cfg.SignRPCListen != "" && cfg.RemoteSigner != "" {
+		str := "%s: The signrpclisten and remotesigner options are " +
+			"mutually exclusive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// As the Signer service signs arbitrary data, both ends of the
+	// connection must authenticate each other.
+	if cfg.SignRPCListen != "" && (cfg.SignRPCTLSCert == "" ||
+		cfg.SignRPCTLSKey == "" || cfg.SignRPCClientCA == "") {
+
+		str := "%s: The signrpctlscert, signrpctlskey and " +
+			"signrpcclientca options must be set along with " +
+			"signrpclisten"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.RemoteSigner != "" && (cfg.RemoteSignerTLSCert == "" ||
+		cfg.RemoteSignerTLSKey == "" || cfg.RemoteSignerCA == "") {
+
+		str := "%s: The remotesignertlscert, remotesignertlskey and " +
+			"remotesignerca options must be set along with " +
+			"remotesigner"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// A zero batch window is permitted, sweeping each output as soon as
+	// it's ready, but a negative window is invalid.
+	if cfg.SweepBatchWindow < 0 {
+		str := "%s: The sweepbatchwindow must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// If the rpcuser and rpcpass paramters aren't set, then we'll attempt
-	// to automatically obtain the properm mcredentials for btcd and set
-	// them within the configuration.
-	if cfg.RPCUser == "" || cfg.RPCPass == "" {
+	// to automatically obtain the properm mcredentials for the bitcoin
+	// node and set them within the configuration. The neutrino light
+	// client connects directly to the p2p network, so doesn't need them.
+	if cfg.Bitcoin.Node != "neutrino" &&
+		(cfg.RPCUser == "" || cfg.RPCPass == "") {
+
 		// If we're in simnet mode, then the running btcd instance
 		// won't read the RPC credentials from the configuration. So if
 		// lnd wasn't specified the paramters, then we won't be able to
@@ -189,9 +410,13 @@ func loadConfig() (*config, error) {
 			return nil, fmt.Errorf(str, funcName)
 		}
 
-		fmt.Println("Attempting automatic RPC configuration to btcd")
+		node := cfg.Bitcoin.Node
+		fmt.Printf("Attempting automatic RPC configuration to %v\n", node)
 
 		confFile := filepath.Join(btcdHomeDir, "btcd.conf")
+		if node == "bitcoind" {
+			confFile = filepath.Join(bitcoindHomeDir, "bitcoin.conf")
+		}
 		rpcUser, rpcPass, err := extractRPCParams(confFile)
 		if err != nil {
 			return nil, fmt.Errorf("unable to extract RPC "+
@@ -199,7 +424,7 @@ func loadConfig() (*config, error) {
 				err)
 		}
 
-		fmt.Println("Automatically obtained btcd's RPC credentials")
+		fmt.Printf("Automatically obtained %v's RPC credentials\n", node)
 		cfg.RPCUser, cfg.RPCPass = rpcUser, rpcPass
 	}
 
@@ -212,6 +437,28 @@ func loadConfig() (*config, error) {
 	cfg.DataDir = cleanAndExpandPath(cfg.DataDir)
 	cfg.DataDir = filepath.Join(cfg.DataDir, activeNetParams.Name)
 
+	// If no path was specified for the static channel backup file, then
+	// it's placed within the namespaced data directory.
+	if cfg.BackupFilePath == "" {
+		cfg.BackupFilePath = filepath.Join(cfg.DataDir,
+			chanbackup.DefaultBackupFileName)
+	} else {
+		cfg.BackupFilePath = cleanAndExpandPath(cfg.BackupFilePath)
+	}
+
+	// Expand the paths of the certificates and keys used to authenticate
+	// connections to and from the Signer service.
+	tlsPaths := []*string{
+		&cfg.SignRPCTLSCert, &cfg.SignRPCTLSKey, &cfg.SignRPCClientCA,
+		&cfg.RemoteSignerTLSCert, &cfg.RemoteSignerTLSKey,
+		&cfg.RemoteSignerCA,
+	}
+	for _, path := range tlsPaths {
+		if *path != "" {
+			*path = cleanAndExpandPath(*path)
+		}
+	}
+
 	// Append the network type to the log directory so it is "namespaced"
 	// per network in the same fashion as the data directory.
 	cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
@@ -332,16 +579,16 @@ func supportedSubsystems() []string {
 
 // noiseDial is a factory function which creates a connmgr compliant dialing
 // function by returning a closure which includes the server's identity key.
-func noiseDial(idPriv *btcec.PrivateKey) func(net.Addr) (net.Conn, error) {
+func noiseDial(idKey keychain.SingleKeyECDH) func(net.Addr) (net.Conn, error) {
 	return func(a net.Addr) (net.Conn, error) {
 		lnAddr := a.(*lnwire.NetAddress)
-		return brontide.Dial(idPriv, lnAddr)
+		return brontide.Dial(idKey, lnAddr)
 	}
 }
 
 // extractRPCParams attempts to extract the RPC credentials for an existing
-// btcd instance. The passed path is expected to be the location of btcd's
-// application data directory on the target system.
+// btcd or bitcoind instance. The passed path is expected to be the location of
+// the node's configuration file on the target system.
 func extractRPCParams(btcdConfigPath string) (string, string, error) {
 	// First, we'll open up the btcd configuration file found at the target
 	// destination.
@@ -371,9 +618,9 @@ func extractRPCParams(btcdConfigPath string) (string, string, error) {
 	}
 
 	// Similarly, we'll use another regular expression to find the set
-	// rpcpass (if any). If we can't find the pass, then we'll exit with an
-	// error.
-	rpcPassRegexp, err := regexp.Compile(`(?m)^\s*rpcpass=([^\s]+)`)
+	// rpcpass (if any), which bitcoind names rpcpassword. If we can't find
+	// the pass, then we'll exit with an error.
+	rpcPassRegexp, err := regexp.Compile(`(?m)^\s*rpcpass(?:word)?=([^\s]+)`)
 	if err != nil {
 		return "", "", err
 	}
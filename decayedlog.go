@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// decayedLog is a persistent log of the hashes of all the Sphinx shared
+// secrets we've derived while processing incoming onion packets. Each entry
+// is stored along side the CLTV expiry of the HTLC which carried the packet.
+// The log is consulted for each newly received onion packet in order to
+// detect replays: a peer re-transmitting a captured onion packet would
+// otherwise cause us to forward it once again. As an HTLC can't be redeemed
+// once its expiry has passed, entries "decay" and are garbage collected as
+// new blocks are connected to the main chain.
+type decayedLog struct {
+	started uint32
+	stopped uint32
+
+	db       *channeldb.DB
+	notifier chainntnfs.ChainNotifier
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newDecayedLog creates a new instance of the decayedLog backed by the passed
+// channeldb. The notifier is used to receive block epochs which drive the
+// garbage collection of expired entries.
+func newDecayedLog(db *channeldb.DB,
+	notifier chainntnfs.ChainNotifier) *decayedLog {
+
+	return &decayedLog{
+		db:       db,
+		notifier: notifier,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the garbage collector of the decayedLog.
+func (d *decayedLog) Start() error {
+	if !atomic.CompareAndSwapUint32(&d.started, 0, 1) {
+		return nil
+	}
+
+	hswcLog.Tracef("Starting decayed shared secret log")
+
+	epochClient, err := d.notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	d.wg.Add(1)
+	go d.garbageCollector(epochClient)
+
+	return nil
+}
+
+// Stop gracefully shuts down the decayedLog's garbage collector.
+func (d *decayedLog) Stop() error {
+	if !atomic.CompareAndSwapUint32(&d.stopped, 0, 1) {
+		return nil
+	}
+
+	hswcLog.Infof("Decayed shared secret log shutting down")
+
+	close(d.quit)
+	d.wg.Wait()
+
+	return nil
+}
+
+// garbageCollector removes all entries whose expiry has passed each time a
+// new block is connected to the main chain.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *decayedLog) garbageCollector(epochClient *chainntnfs.BlockEpochEvent) {
+	defer d.wg.Done()
+	defer epochClient.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-epochClient.Epochs:
+			// If the epoch channel has been closed, then the
+			// ChainNotifier is exiting, so we'll do the same.
+			if !ok {
+				return
+			}
+
+			numPruned, err := d.db.PruneSharedHashes(uint32(epoch.Height))
+			if err != nil {
+				hswcLog.Errorf("unable to prune decayed log: %v",
+					err)
+				continue
+			}
+
+			if numPruned != 0 {
+				hswcLog.Debugf("Pruned %v expired shared secret "+
+					"hashes at height=%v", numPruned,
+					epoch.Height)
+			}
+
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// checkAndLog records the passed Sphinx shared secret of an onion packet
+// within the log, returning true if the packet has already been processed as
+// part of a different HTLC. The channel ID and HTLC index identify the HTLC
+// which carried the packet, allowing the same HTLC to be re-processed after a
+// restart without being flagged as a replay. The sha256 of the shared secret
+// is stored in the log instead of the secret itself, as the shared secret may
+// be used to decrypt the packet.
+func (d *decayedLog) checkAndLog(sharedSecret [32]byte, expiry uint32,
+	chanID lnwire.ChannelID, htlcIndex uint64) (bool, error) {
+
+	sharedHash := sha256.Sum256(sharedSecret[:])
+
+	existingEntry, err := d.db.LogSharedHash(sharedHash,
+		&channeldb.SharedHashEntry{
+			Expiry:    expiry,
+			ChanID:    chanID,
+			HtlcIndex: htlcIndex,
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// If this is the first time we've seen this shared secret, then the
+	// packet is fresh.
+	if existingEntry == nil {
+		return false, nil
+	}
+
+	// Otherwise, the packet is only a replay if it was previously
+	// received within a distinct HTLC.
+	return existingEntry.ChanID != chanID ||
+		existingEntry.HtlcIndex != htlcIndex, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcd/chaincfg"
+)
+
+// newTestSharedSecret returns a random Sphinx shared secret, standing in for
+// the secret derived while processing an onion packet.
+func newTestSharedSecret(t *testing.T) [32]byte {
+	var sharedSecret [32]byte
+	if _, err := rand.Read(sharedSecret[:]); err != nil {
+		t.Fatalf("unable to create shared secret: %v", err)
+	}
+
+	return sharedSecret
+}
+
+// assertReplay asserts whether the decayed log considers the onion packet
+// with the passed shared secret, carried by the HTLC at the passed location,
+// to be a replay.
+func assertReplay(t *testing.T, log *decayedLog, sharedSecret [32]byte,
+	expiry uint32, chanID lnwire.ChannelID, htlcIndex uint64,
+	expected bool) {
+
+	isReplay, err := log.checkAndLog(sharedSecret, expiry, chanID,
+		htlcIndex)
+	if err != nil {
+		t.Fatalf("unable to check onion packet: %v", err)
+	}
+	if isReplay != expected {
+		t.Fatalf("expected replay=%v for HTLC(chan_id=%v, index=%v), "+
+			"got %v", expected, chanID, htlcIndex, isReplay)
+	}
+}
+
+// TestDecayedLogReplay tests that an onion packet is only considered a replay
+// if it has already been received within a different HTLC, so that HTLCs
+// re-processed after a restart aren't rejected.
+func TestDecayedLogReplay(t *testing.T) {
+	cdb, _, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	log := newDecayedLog(cdb, nil)
+
+	sharedSecret := newTestSharedSecret(t)
+	chanID := lnwire.ChannelID{1}
+
+	// The first time the packet is received, it's fresh.
+	assertReplay(t, log, sharedSecret, 100, chanID, 0, false)
+
+	// Processing it once more within the same HTLC isn't a replay.
+	assertReplay(t, log, sharedSecret, 100, chanID, 0, false)
+
+	// Receiving it within any other HTLC is.
+	assertReplay(t, log, sharedSecret, 100, chanID, 1, true)
+	assertReplay(t, log, sharedSecret, 100, lnwire.ChannelID{2}, 0,
+		true)
+
+	// A distinct packet within another HTLC is fresh.
+	otherSecret := newTestSharedSecret(t)
+	assertReplay(t, log, otherSecret, 100, chanID, 1, false)
+}
+
+// TestDecayedLogPersistence tests that replays are still detected after the
+// database backing the decayed log has been re-opened.
+func TestDecayedLogPersistence(t *testing.T) {
+	cdb, dbPath, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	sharedSecret := newTestSharedSecret(t)
+	chanID := lnwire.ChannelID{1}
+
+	log := newDecayedLog(cdb, nil)
+	assertReplay(t, log, sharedSecret, 100, chanID, 0, false)
+
+	// Restart by re-opening the database.
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close database: %v", err)
+	}
+	cdb, err := channeldb.Open(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer cdb.Close()
+
+	log = newDecayedLog(cdb, nil)
+	assertReplay(t, log, sharedSecret, 100, chanID, 0, false)
+	assertReplay(t, log, sharedSecret, 100, chanID, 1, true)
+}
+
+// TestDecayedLogGarbageCollection tests that entries are garbage collected
+// once the chain advances beyond their expiry, but not before.
+func TestDecayedLogGarbageCollection(t *testing.T) {
+	chain := simchain.New(&chaincfg.RegressionNetParams)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+	defer chain.Stop()
+
+	cdb, _, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	log := newDecayedLog(cdb, chain)
+	if err := log.Start(); err != nil {
+		t.Fatalf("unable to start decayed log: %v", err)
+	}
+	defer log.Stop()
+
+	_, height, err := chain.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get best block: %v", err)
+	}
+	chanID := lnwire.ChannelID{1}
+
+	// We'll log one packet expiring in the next block, and another which
+	// expires later.
+	expiry := uint32(height) + 1
+	expiringSecret := newTestSharedSecret(t)
+	assertReplay(t, log, expiringSecret, expiry, chanID, 0, false)
+	laterSecret := newTestSharedSecret(t)
+	assertReplay(t, log, laterSecret, expiry+10, chanID, 1, false)
+
+	expiringHash := sha256.Sum256(expiringSecret[:])
+	laterHash := sha256.Sum256(laterSecret[:])
+
+	// Reaching the expiry height shouldn't collect the entry, as an HTLC
+	// expiring at this height may still be redeemed.
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := cdb.LookupSharedHash(expiringHash); err != nil {
+		t.Fatalf("entry collected at its expiry height: %v", err)
+	}
+
+	// Once the chain moves beyond it, the entry should be collected.
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := cdb.LookupSharedHash(expiringHash)
+		if err == channeldb.ErrSharedHashNotFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expired entry not collected: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := cdb.LookupSharedHash(laterHash); err != nil {
+		t.Fatalf("unexpired entry collected: %v", err)
+	}
+
+	// As the expired entry has been collected, the packet is no longer
+	// recognized.
+	assertReplay(t, log, expiringSecret, expiry+10, chanID, 2, false)
+}
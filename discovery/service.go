@@ -451,12 +451,14 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(nMsg *networkMsg) []l
 		}
 
 		node := &channeldb.LightningNode{
-			LastUpdate: time.Unix(int64(msg.Timestamp), 0),
-			Addresses:  msg.Addresses,
-			PubKey:     msg.NodeID,
-			Alias:      msg.Alias.String(),
-			AuthSig:    msg.Signature,
-			Features:   msg.Features,
+			LastUpdate:  time.Unix(int64(msg.Timestamp), 0),
+			Addresses:   msg.Addresses,
+			PubKey:      msg.NodeID,
+			Alias:       msg.Alias.String(),
+			AuthSig:     msg.Signature,
+			Features:    msg.Features,
+			OnionKey:    msg.OnionKey,
+			OnionKeySig: msg.OnionKeySig,
 		}
 
 		if err := d.cfg.Router.AddNode(node); err != nil {
@@ -869,12 +871,14 @@ func (d *AuthenticatedGossiper) synchronizeWithNode(syncReq *syncRequest) error
 		}
 
 		ann := &lnwire.NodeAnnouncement{
-			Signature: node.AuthSig,
-			Timestamp: uint32(node.LastUpdate.Unix()),
-			Addresses: node.Addresses,
-			NodeID:    node.PubKey,
-			Alias:     alias,
-			Features:  node.Features,
+			Signature:   node.AuthSig,
+			Timestamp:   uint32(node.LastUpdate.Unix()),
+			Addresses:   node.Addresses,
+			NodeID:      node.PubKey,
+			Alias:       alias,
+			Features:    node.Features,
+			OnionKey:    node.OnionKey,
+			OnionKeySig: node.OnionKeySig,
 		}
 		announceMessages = append(announceMessages, ann)
 
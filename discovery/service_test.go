@@ -180,7 +180,9 @@ func (m *mockNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	return nil, nil
 }
 
-func (m *mockNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint) (*chainntnfs.SpendEvent, error) {
+func (m *mockNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
 	return nil, nil
 }
 
@@ -293,6 +295,7 @@ func createNodeAnnouncement(priv *btcec.PrivateKey) (*lnwire.NodeAnnouncement,
 		Timestamp: uint32(prand.Int31()),
 		Addresses: testAddrs,
 		NodeID:    priv.PubKey(),
+		OnionKey:  priv.PubKey(),
 		Alias:     alias,
 		Features:  testFeatures,
 	}
@@ -301,6 +304,9 @@ func createNodeAnnouncement(priv *btcec.PrivateKey) (*lnwire.NodeAnnouncement,
 	if a.Signature, err = SignAnnouncement(&signer, priv.PubKey(), a); err != nil {
 		return nil, err
 	}
+	if a.OnionKeySig, err = SignOnionKey(&signer, priv.PubKey(), a); err != nil {
+		return nil, err
+	}
 
 	return a, nil
 }
@@ -502,6 +508,45 @@ func TestProcessAnnouncement(t *testing.T) {
 	}
 }
 
+// TestOnionKeyValidation checks that node announcements carrying an onion
+// key which isn't authenticated by the node are rejected.
+func TestOnionKeyValidation(t *testing.T) {
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup()
+
+	// An announcement whose onion key has been swapped out after signing
+	// should be rejected.
+	na, err := createNodeAnnouncement(nodeKeyPriv1)
+	if err != nil {
+		t.Fatalf("can't create node announcement: %v", err)
+	}
+	na.OnionKey = nodeKeyPub2
+
+	err = <-ctx.discovery.ProcessRemoteAnnouncement(na, na.NodeID)
+	if err == nil {
+		t.Fatalf("announcement with forged onion key was accepted")
+	}
+
+	// As should one whose onion key lacks a signature entirely.
+	na, err = createNodeAnnouncement(nodeKeyPriv1)
+	if err != nil {
+		t.Fatalf("can't create node announcement: %v", err)
+	}
+	na.OnionKeySig = nil
+
+	err = <-ctx.discovery.ProcessRemoteAnnouncement(na, na.NodeID)
+	if err == nil {
+		t.Fatalf("announcement with unsigned onion key was accepted")
+	}
+
+	if len(ctx.router.nodes) != 0 {
+		t.Fatalf("invalid node announcement was added to router")
+	}
+}
+
 // TestPrematureAnnouncement checks that premature announcements are
 // not propagated to the router subsystem until block with according
 // block height received.
@@ -134,3 +134,17 @@ func SignAnnouncement(signer lnwallet.MessageSigner, pubKey *btcec.PublicKey,
 
 	return signer.SignMessage(pubKey, data)
 }
+
+// SignOnionKey is a helper function which is used to sign the onion key
+// advertised within an outgoing node announcement. The announcement's
+// Timestamp, NodeID and OnionKey must be set before calling this function.
+func SignOnionKey(signer lnwallet.MessageSigner, pubKey *btcec.PublicKey,
+	msg *lnwire.NodeAnnouncement) (*btcec.Signature, error) {
+
+	data, err := msg.OnionKeyDataToSign()
+	if err != nil {
+		return nil, errors.Errorf("unable to get data to sign: %v", err)
+	}
+
+	return signer.SignMessage(pubKey, data)
+}
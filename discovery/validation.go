@@ -65,6 +65,23 @@ func (d *AuthenticatedGossiper) validateNodeAnn(a *lnwire.NodeAnnouncement) erro
 		return errors.New("signature on node announcement is invalid")
 	}
 
+	// The onion key isn't covered by the signature above, so if one has
+	// been advertised, then it must carry a signature of its own.
+	if a.OnionKey == nil {
+		return nil
+	}
+	if a.OnionKeySig == nil {
+		return errors.New("onion key on node announcement is unsigned")
+	}
+	data, err = a.OnionKeyDataToSign()
+	if err != nil {
+		return err
+	}
+	dataHash = chainhash.DoubleHashB(data)
+	if !a.OnionKeySig.Verify(dataHash, copyPubKey(a.NodeID)) {
+		return errors.New("signature on onion key is invalid")
+	}
+
 	return nil
 }
 
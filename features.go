@@ -2,6 +2,13 @@ package main
 
 import "github.com/lightningnetwork/lnd/lnwire"
 
+// extendedFundingFeature is the local feature signalling support for the
+// optional trailing fields of the SingleFundingRequest and
+// SingleFundingResponse messages, which carry the constraints each side
+// imposes upon the channel. Peers lacking this feature would reject the
+// longer messages, so the fields are only sent to peers which signal it.
+const extendedFundingFeature = "extended-funding"
+
 // globalFeatures feature vector which affects HTLCs and thus are also
 // advertised to other nodes.
 var globalFeatures = lnwire.NewFeatureVector([]lnwire.Feature{})
@@ -13,4 +20,8 @@ var localFeatures = lnwire.NewFeatureVector([]lnwire.Feature{
 		Name: "new-ping-and-funding",
 		Flag: lnwire.RequiredFlag,
 	},
+	{
+		Name: extendedFundingFeature,
+		Flag: lnwire.OptionalFlag,
+	},
 })
@@ -0,0 +1,519 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// minFeeIncrementPerWeight is the amount, in satoshis per weight unit,
+	// by which a replacement transaction must raise the fee paid over the
+	// transaction it replaces in order to be accepted by nodes enforcing
+	// BIP 125.
+	minFeeIncrementPerWeight = btcutil.Amount(1)
+)
+
+// bumpResult describes the transaction broadcast in order to bump the fee of
+// a stuck transaction.
+type bumpResult struct {
+	// tx is either the replacement of the stuck transaction, or a child
+	// transaction spending one of its outputs.
+	tx *wire.MsgTx
+
+	// replaced is true if tx replaces the stuck transaction, and false if
+	// tx is a child paying for its parent.
+	replaced bool
+
+	// fee is the absolute fee paid by tx.
+	fee btcutil.Amount
+}
+
+// feeBumper is a subsystem which raises the fee of transactions which are
+// stuck within the mempool, such as funding, close and sweep transactions
+// broadcast with too low a fee rate. If all the inputs of a transaction
+// belong to the wallet, and the transaction signals replaceability, it's
+// replaced by a transaction paying a higher fee (RBF). Otherwise, a child
+// transaction spending one of its wallet outputs is broadcast, paying a fee
+// high enough that the pair of transactions reaches the target fee rate
+// (CPFP). Each transaction broadcast is tracked until it has confirmed.
+type feeBumper struct {
+	started uint32
+	stopped uint32
+
+	wallet   *lnwallet.LightningWallet
+	notifier chainntnfs.ChainNotifier
+	chanDB   *channeldb.DB
+
+	// bumpMtx serializes fee bumps, ensuring that concurrent bumps of the
+	// same transaction don't double spend one another.
+	bumpMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFeeBumper creates a new instance of the feeBumper.
+func newFeeBumper(wallet *lnwallet.LightningWallet,
+	notifier chainntnfs.ChainNotifier, chanDB *channeldb.DB) *feeBumper {
+
+	return &feeBumper{
+		wallet:   wallet,
+		notifier: notifier,
+		chanDB:   chanDB,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the feeBumper.
+func (f *feeBumper) Start() error {
+	if !atomic.CompareAndSwapUint32(&f.started, 0, 1) {
+		return nil
+	}
+
+	bmprLog.Tracef("Starting fee bumper")
+
+	return nil
+}
+
+// Stop gracefully shuts down the feeBumper, abandoning the tracking of any
+// transactions yet to confirm.
+func (f *feeBumper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&f.stopped, 0, 1) {
+		return nil
+	}
+
+	bmprLog.Infof("Fee bumper shutting down")
+
+	close(f.quit)
+	f.wg.Wait()
+
+	return nil
+}
+
+// bumpFee raises the fee of the unconfirmed transaction identified by txid,
+// targeting the passed fee rate. If outPoint is non-nil, the fee is always
+// bumped by a child spending the referenced output of the transaction.
+// Otherwise, the transaction is replaced if possible, falling back to a child
+// spending the largest wallet output of the transaction.
+func (f *feeBumper) bumpFee(txid *chainhash.Hash, outPoint *wire.OutPoint,
+	feePerWeight btcutil.Amount) (*bumpResult, error) {
+
+	f.bumpMtx.Lock()
+	defer f.bumpMtx.Unlock()
+
+	if err := f.checkUnconfirmed(txid); err != nil {
+		return nil, err
+	}
+
+	tx, err := f.wallet.ChainIO.GetTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+	txFee, err := f.txFee(tx)
+	if err != nil {
+		return nil, err
+	}
+	txWeight := btcutil.Amount(
+		blockchain.GetTransactionWeight(btcutil.NewTx(tx)))
+
+	bmprLog.Debugf("Bumping fee of tx %v (weight=%v, fee=%v) to %v "+
+		"sat/weight", txid, int64(txWeight), txFee, int64(feePerWeight))
+
+	var result *bumpResult
+	if outPoint == nil {
+		replaceable, err := f.canReplace(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		if replaceable {
+			result, err = f.replaceTx(tx, txFee, feePerWeight)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if result == nil {
+		result, err = f.createChildTx(tx, txWeight, txFee, outPoint,
+			feePerWeight)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Before broadcasting the transaction, we'll register for its
+	// confirmation so we're able to track it until it's been mined. We
+	// also register for the spend of its first input, which tells us if
+	// the transaction is double spent, such as by the original
+	// transaction it replaces confirming instead.
+	bumpTxid := result.tx.TxHash()
+	confEvent, err := f.notifier.RegisterConfirmationsNtfn(&bumpTxid, 1)
+	if err != nil {
+		return nil, err
+	}
+	_, bestHeight, err := f.wallet.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	spendEvent, err := f.notifier.RegisterSpendNtfn(
+		&result.tx.TxIn[0].PreviousOutPoint, uint32(bestHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	bmprLog.Infof("Bumping fee of tx %v with tx %v (replaced=%v, "+
+		"fee=%v): %v", txid, bumpTxid, result.replaced,
+		result.fee, newLogClosure(func() string {
+			return spew.Sdump(result.tx)
+		}))
+
+	if err := f.wallet.PublishTransaction(result.tx); err != nil {
+		spendEvent.Cancel()
+		return nil, err
+	}
+
+	f.wg.Add(1)
+	go f.waitForConfirmation(*txid, bumpTxid, confEvent, spendEvent)
+
+	return result, nil
+}
+
+// checkUnconfirmed returns an error if the transaction identified by txid
+// isn't an unconfirmed transaction relevant to the wallet.
+func (f *feeBumper) checkUnconfirmed(txid *chainhash.Hash) error {
+	txDetails, err := f.wallet.ListTransactionDetails()
+	if err != nil {
+		return err
+	}
+
+	for _, txDetail := range txDetails {
+		if txDetail.Hash != *txid {
+			continue
+		}
+
+		if txDetail.NumConfirmations > 0 {
+			return fmt.Errorf("transaction %v has already "+
+				"confirmed", txid)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("transaction %v not found within wallet", txid)
+}
+
+// txFee returns the absolute fee paid by the passed transaction, looking up
+// the value of each output it spends.
+func (f *feeBumper) txFee(tx *wire.MsgTx) (btcutil.Amount, error) {
+	var inputSum btcutil.Amount
+	for _, txIn := range tx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		prevTx, err := f.wallet.ChainIO.GetTransaction(&prevOut.Hash)
+		if err != nil {
+			return 0, err
+		}
+		if prevOut.Index >= uint32(len(prevTx.TxOut)) {
+			return 0, fmt.Errorf("input %v spends non-existent "+
+				"output", prevOut)
+		}
+
+		inputSum += btcutil.Amount(prevTx.TxOut[prevOut.Index].Value)
+	}
+
+	var outputSum btcutil.Amount
+	for _, txOut := range tx.TxOut {
+		outputSum += btcutil.Amount(txOut.Value)
+	}
+
+	return inputSum - outputSum, nil
+}
+
+// canReplace returns true if the passed transaction can be replaced by one
+// paying a higher fee: it must signal replaceability, and all of its inputs
+// must belong to the wallet so we're able to sign the replacement.
+func (f *feeBumper) canReplace(tx *wire.MsgTx) (bool, error) {
+	// Replacing a funding transaction would alter the outpoint of the
+	// channel it opens, invalidating the commitment transactions which
+	// spend it, so funding transactions are only ever bumped by a child.
+	pendingChannels, err := f.chanDB.FetchPendingChannels()
+	if err != nil {
+		return false, err
+	}
+	txid := tx.TxHash()
+	for _, pendingChan := range pendingChannels {
+		if pendingChan.ChanID.Hash == txid {
+			return false, nil
+		}
+	}
+
+	// As defined by BIP 125, a transaction signals replaceability if any
+	// of its inputs has a sequence number below MaxTxInSequenceNum-1.
+	signalsReplacement := false
+	for _, txIn := range tx.TxIn {
+		if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+			signalsReplacement = true
+		}
+
+		_, err := f.wallet.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err == lnwallet.ErrNotMine {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+
+	return signalsReplacement, nil
+}
+
+// replaceTx creates a transaction replacing the passed transaction, paying
+// the target fee rate. The additional fee is deducted from the largest output
+// of the transaction belonging to the wallet.
+func (f *feeBumper) replaceTx(tx *wire.MsgTx, txFee,
+	feePerWeight btcutil.Amount) (*bumpResult, error) {
+
+	txid := tx.TxHash()
+	changeOutput, err := f.largestWalletOutput(&txid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The replacement must pay the target fee rate, and must also raise
+	// the fee paid by at least the minimum increment required by BIP 125.
+	txWeight := btcutil.Amount(
+		blockchain.GetTransactionWeight(btcutil.NewTx(tx)))
+	newFee := feePerWeight * txWeight
+	if minFee := txFee + minFeeIncrementPerWeight*txWeight; newFee < minFee {
+		newFee = minFee
+	}
+
+	changeValue := changeOutput.Value - (newFee - txFee)
+	if changeValue < lnwallet.DefaultDustLimit() {
+		return nil, fmt.Errorf("change output %v of %v is unable to "+
+			"pay replacement fee of %v", changeOutput.OutPoint,
+			changeOutput.Value, newFee)
+	}
+
+	replacementTx := tx.Copy()
+	replacementTx.TxOut[changeOutput.Index].Value = int64(changeValue)
+	if err := f.signInputs(replacementTx); err != nil {
+		return nil, err
+	}
+
+	return &bumpResult{
+		tx:       replacementTx,
+		replaced: true,
+		fee:      newFee,
+	}, nil
+}
+
+// createChildTx creates a transaction spending an output of the passed
+// transaction back into the wallet, paying a fee such that the pair of
+// transactions reaches the target fee rate. If outPoint is nil, the largest
+// wallet output of the transaction is spent.
+func (f *feeBumper) createChildTx(tx *wire.MsgTx, txWeight, txFee btcutil.Amount,
+	outPoint *wire.OutPoint, feePerWeight btcutil.Amount) (*bumpResult, error) {
+
+	txid := tx.TxHash()
+
+	var (
+		parentOutput *lnwallet.Utxo
+		err          error
+	)
+	if outPoint == nil {
+		parentOutput, err = f.largestWalletOutput(&txid)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		utxos, err := f.walletOutputs(&txid)
+		if err != nil {
+			return nil, err
+		}
+		for _, utxo := range utxos {
+			if utxo.OutPoint == *outPoint {
+				parentOutput = utxo
+				break
+			}
+		}
+		if parentOutput == nil {
+			return nil, fmt.Errorf("output %v isn't an unspent "+
+				"output of the wallet", outPoint)
+		}
+	}
+
+	pkScript, err := newSweepPkScript(f.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	childTx := wire.NewMsgTx(2)
+	childTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: parentOutput.OutPoint,
+	})
+	childTx.AddTxOut(&wire.TxOut{
+		PkScript: pkScript,
+		Value:    int64(parentOutput.Value),
+	})
+
+	// We'll sign the child transaction once in order to determine its
+	// weight, which is unaffected by the value of its output.
+	if err := f.signInputs(childTx); err != nil {
+		return nil, err
+	}
+	childWeight := btcutil.Amount(
+		blockchain.GetTransactionWeight(btcutil.NewTx(childTx)))
+
+	// The child pays the fee required for the pair of transactions to
+	// reach the target fee rate, though no less than the fee required for
+	// the child on its own.
+	childFee := feePerWeight*(txWeight+childWeight) - txFee
+	if minFee := feePerWeight * childWeight; childFee < minFee {
+		childFee = minFee
+	}
+
+	childValue := parentOutput.Value - childFee
+	if childValue < lnwallet.DefaultDustLimit() {
+		return nil, fmt.Errorf("output %v of %v is unable to pay "+
+			"child tx fee of %v", parentOutput.OutPoint,
+			parentOutput.Value, childFee)
+	}
+
+	childTx.TxOut[0].Value = int64(childValue)
+	if err := f.signInputs(childTx); err != nil {
+		return nil, err
+	}
+
+	return &bumpResult{
+		tx:  childTx,
+		fee: childFee,
+	}, nil
+}
+
+// walletOutputs returns the unspent outputs of the transaction identified by
+// txid which belong to the wallet.
+func (f *feeBumper) walletOutputs(txid *chainhash.Hash) ([]*lnwallet.Utxo, error) {
+	// Passing -1 includes the outputs of unconfirmed transactions.
+	utxos, err := f.wallet.ListUnspentWitness(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []*lnwallet.Utxo
+	for _, utxo := range utxos {
+		if utxo.Hash == *txid {
+			outputs = append(outputs, utxo)
+		}
+	}
+
+	return outputs, nil
+}
+
+// largestWalletOutput returns the largest unspent output of the transaction
+// identified by txid which belongs to the wallet.
+func (f *feeBumper) largestWalletOutput(txid *chainhash.Hash) (*lnwallet.Utxo, error) {
+	utxos, err := f.walletOutputs(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	var largest *lnwallet.Utxo
+	for _, utxo := range utxos {
+		if largest == nil || utxo.Value > largest.Value {
+			largest = utxo
+		}
+	}
+	if largest == nil {
+		return nil, fmt.Errorf("transaction %v has no unspent wallet "+
+			"outputs", txid)
+	}
+
+	return largest, nil
+}
+
+// signInputs populates the passed transaction with a valid input script for
+// each of its inputs, all of which must belong to the wallet.
+func (f *feeBumper) signInputs(tx *wire.MsgTx) error {
+	signDesc := lnwallet.SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: txscript.NewTxSigHashes(tx),
+	}
+	for i, txIn := range tx.TxIn {
+		info, err := f.wallet.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			return err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := f.wallet.Signer.ComputeInputScript(tx,
+			&signDesc)
+		if err != nil {
+			return err
+		}
+
+		txIn.SignatureScript = inputScript.ScriptSig
+		txIn.Witness = inputScript.Witness
+	}
+
+	return nil
+}
+
+// waitForConfirmation tracks the transaction broadcast to bump the fee of a
+// stuck transaction until it has confirmed, or until its first input has been
+// spent by a conflicting transaction.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *feeBumper) waitForConfirmation(txid, bumpTxid chainhash.Hash,
+	confEvent *chainntnfs.ConfirmationEvent,
+	spendEvent *chainntnfs.SpendEvent) {
+
+	defer f.wg.Done()
+	defer spendEvent.Cancel()
+
+	for {
+		select {
+		case conf, ok := <-confEvent.Confirmed:
+			// If the confirmation channel has been closed, then
+			// the notifier is shutting down so we exit.
+			if !ok {
+				return
+			}
+
+			bmprLog.Infof("Fee bump tx %v of tx %v confirmed at "+
+				"height %v", bumpTxid, txid, conf.BlockHeight)
+			return
+
+		case spend, ok := <-spendEvent.Spend:
+			if !ok {
+				return
+			}
+
+			// The spend of the input by the fee bump transaction
+			// itself is dispatched once it enters the mempool, so
+			// we continue to wait for its confirmation.
+			if *spend.SpenderTxHash == bumpTxid {
+				continue
+			}
+
+			bmprLog.Warnf("Fee bump tx %v of tx %v was double "+
+				"spent by tx %v", bumpTxid, txid,
+				spend.SpenderTxHash)
+			return
+
+		case <-f.quit:
+			return
+		}
+	}
+}
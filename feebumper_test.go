@@ -0,0 +1,324 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// bumpTestFeeRate is the fee rate, in satoshis per weight unit, targeted by
+// the fee bumps within each test.
+const bumpTestFeeRate = btcutil.Amount(100)
+
+// confRecordingNotifier wraps the simulated chain, forwarding the txid of
+// each confirmation notification registered.
+type confRecordingNotifier struct {
+	*simchain.Chain
+
+	confTxids chan chainhash.Hash
+}
+
+// RegisterConfirmationsNtfn records the txid before registering the
+// notification with the simulated chain.
+func (n *confRecordingNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	n.confTxids <- *txid
+
+	return n.Chain.RegisterConfirmationsNtfn(txid, numConfs)
+}
+
+// feeBumperTestContext bundles a feeBumper with the simulated chain and
+// wallet backing it.
+type feeBumperTestContext struct {
+	*simWalletHarness
+
+	notifier  *confRecordingNotifier
+	feeBumper *feeBumper
+	cleanUp   func()
+}
+
+// newFeeBumperTestContext creates a feeBumper backed by a simulated chain,
+// along with a wallet holding a single confirmed output.
+func newFeeBumperTestContext(t *testing.T) *feeBumperTestContext {
+	harness := newSimWalletHarness(t, 3)
+
+	tempDir, err := ioutil.TempDir("", "feebumper")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+
+	notifier := &confRecordingNotifier{
+		Chain:     harness.chain,
+		confTxids: make(chan chainhash.Hash, 1),
+	}
+	feeBumper := newFeeBumper(harness.lnWallet, notifier, cdb)
+	if err := feeBumper.Start(); err != nil {
+		t.Fatalf("unable to start fee bumper: %v", err)
+	}
+
+	// Fund the wallet with a single confirmed output.
+	harness.fundWallet(btcutil.SatoshiPerBitcoin)
+	harness.generate(1)
+
+	return &feeBumperTestContext{
+		simWalletHarness: harness,
+		notifier:         notifier,
+		feeBumper:        feeBumper,
+		cleanUp: func() {
+			feeBumper.Stop()
+			harness.stop()
+			cdb.Close()
+			os.RemoveAll(tempDir)
+		},
+	}
+}
+
+// sendStuckTx publishes a transaction from the wallet paying to an external
+// script, with change returned to the wallet. The transaction remains within
+// the mempool until a block is generated.
+func (ctx *feeBumperTestContext) sendStuckTx() *wire.MsgTx {
+	txid, err := ctx.wallet.SendOutputs([]*wire.TxOut{{
+		Value:    btcutil.SatoshiPerBitcoin / 2,
+		PkScript: []byte{txscript.OP_TRUE},
+	}})
+	if err != nil {
+		ctx.t.Fatalf("unable to send outputs: %v", err)
+	}
+
+	tx, err := ctx.chain.GetTransaction(txid)
+	if err != nil {
+		ctx.t.Fatalf("unable to fetch tx: %v", err)
+	}
+
+	return tx
+}
+
+// assertFee asserts that the passed transaction pays the expected fee.
+func (ctx *feeBumperTestContext) assertFee(tx *wire.MsgTx,
+	expected btcutil.Amount) {
+
+	fee, err := ctx.feeBumper.txFee(tx)
+	if err != nil {
+		ctx.t.Fatalf("unable to compute fee: %v", err)
+	}
+	if fee != expected {
+		ctx.t.Fatalf("expected fee of %v, got %v", expected, fee)
+	}
+}
+
+// assertValidInputs asserts that each input of the passed transaction validly
+// spends the output it references.
+func (ctx *feeBumperTestContext) assertValidInputs(tx *wire.MsgTx) {
+	hashCache := txscript.NewTxSigHashes(tx)
+	for i, txIn := range tx.TxIn {
+		prevOut, err := ctx.wallet.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			ctx.t.Fatalf("unable to fetch input: %v", err)
+		}
+
+		vm, err := txscript.NewEngine(prevOut.PkScript, tx, i,
+			txscript.StandardVerifyFlags, nil, hashCache,
+			prevOut.Value)
+		if err != nil {
+			ctx.t.Fatalf("unable to create engine: %v", err)
+		}
+		if err := vm.Execute(); err != nil {
+			ctx.t.Fatalf("input %v invalid: %v", i, err)
+		}
+	}
+}
+
+// txWeight returns the weight of the passed transaction.
+func txWeight(tx *wire.MsgTx) btcutil.Amount {
+	return btcutil.Amount(blockchain.GetTransactionWeight(btcutil.NewTx(tx)))
+}
+
+// TestFeeBumperChildPaysForParent tests that a stuck transaction which can't
+// be replaced has its fee bumped by a child spending its change output, that
+// the pair of transactions reaches the target fee rate, and that the child is
+// tracked until it confirms.
+func TestFeeBumperChildPaysForParent(t *testing.T) {
+	ctx := newFeeBumperTestContext(t)
+	defer ctx.cleanUp()
+
+	parentTx := ctx.sendStuckTx()
+	parentTxid := parentTx.TxHash()
+	parentFee, err := ctx.feeBumper.txFee(parentTx)
+	if err != nil {
+		t.Fatalf("unable to compute fee: %v", err)
+	}
+
+	// As the transaction doesn't signal replaceability, the fee should be
+	// bumped by a child transaction.
+	result, err := ctx.feeBumper.bumpFee(&parentTxid, nil, bumpTestFeeRate)
+	if err != nil {
+		t.Fatalf("unable to bump fee: %v", err)
+	}
+	if result.replaced {
+		t.Fatalf("expected child tx, got replacement")
+	}
+
+	childTx := result.tx
+	if len(childTx.TxIn) != 1 ||
+		childTx.TxIn[0].PreviousOutPoint.Hash != parentTxid {
+
+		t.Fatalf("child tx doesn't spend parent tx %v", parentTxid)
+	}
+	ctx.assertValidInputs(childTx)
+
+	expectedFee := bumpTestFeeRate*(txWeight(parentTx)+txWeight(childTx)) -
+		parentFee
+	if result.fee != expectedFee {
+		t.Fatalf("expected child fee of %v, got %v", expectedFee,
+			result.fee)
+	}
+	ctx.assertFee(childTx, expectedFee)
+
+	// The child should be tracked by its confirmation.
+	select {
+	case txid := <-ctx.notifier.confTxids:
+		if txid != childTx.TxHash() {
+			t.Fatalf("expected confirmation of %v to be tracked, "+
+				"got %v", childTx.TxHash(), txid)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("child tx confirmation not tracked")
+	}
+
+	if _, err := ctx.chain.GetRawTransaction(&parentTxid); err != nil {
+		t.Fatalf("parent tx not found: %v", err)
+	}
+	childTxid := childTx.TxHash()
+	if _, err := ctx.chain.GetRawTransaction(&childTxid); err != nil {
+		t.Fatalf("child tx not broadcast: %v", err)
+	}
+
+	// Once confirmed, the transaction can no longer be bumped.
+	ctx.generate(1)
+	_, err = ctx.feeBumper.bumpFee(&parentTxid, nil, bumpTestFeeRate)
+	if err == nil {
+		t.Fatalf("expected bump of confirmed tx to fail")
+	}
+}
+
+// TestFeeBumperChildSpendsOutPoint tests that a child transaction spends the
+// output of the stuck transaction requested, and that outputs not belonging
+// to the wallet are rejected.
+func TestFeeBumperChildSpendsOutPoint(t *testing.T) {
+	ctx := newFeeBumperTestContext(t)
+	defer ctx.cleanUp()
+
+	parentTx := ctx.sendStuckTx()
+	parentTxid := parentTx.TxHash()
+
+	// The first output pays to an external script, so it can't be spent
+	// by the child.
+	externalOutPoint := &wire.OutPoint{Hash: parentTxid, Index: 0}
+	_, err := ctx.feeBumper.bumpFee(&parentTxid, externalOutPoint,
+		bumpTestFeeRate)
+	if err == nil {
+		t.Fatalf("expected bump spending external output to fail")
+	}
+
+	changeOutPoint := &wire.OutPoint{Hash: parentTxid, Index: 1}
+	result, err := ctx.feeBumper.bumpFee(&parentTxid, changeOutPoint,
+		bumpTestFeeRate)
+	if err != nil {
+		t.Fatalf("unable to bump fee: %v", err)
+	}
+	if result.tx.TxIn[0].PreviousOutPoint != *changeOutPoint {
+		t.Fatalf("expected child to spend %v, got %v", changeOutPoint,
+			result.tx.TxIn[0].PreviousOutPoint)
+	}
+}
+
+// TestFeeBumperReplaceTx tests that only transactions signalling
+// replaceability whose inputs all belong to the wallet can be replaced, and
+// that the replacement pays the target fee rate from the wallet's change.
+func TestFeeBumperReplaceTx(t *testing.T) {
+	ctx := newFeeBumperTestContext(t)
+	defer ctx.cleanUp()
+
+	stuckTx := ctx.sendStuckTx()
+	replaceable, err := ctx.feeBumper.canReplace(stuckTx)
+	if err != nil {
+		t.Fatalf("unable to check replaceability: %v", err)
+	}
+	if replaceable {
+		t.Fatalf("tx without replaceable sequence deemed replaceable")
+	}
+
+	// Signalling replaceability within any of its inputs makes the
+	// transaction replaceable.
+	rbfTx := stuckTx.Copy()
+	rbfTx.TxIn[0].Sequence = 0
+	replaceable, err = ctx.feeBumper.canReplace(rbfTx)
+	if err != nil {
+		t.Fatalf("unable to check replaceability: %v", err)
+	}
+	if !replaceable {
+		t.Fatalf("tx signalling replaceability deemed irreplaceable")
+	}
+
+	// Though a transaction spending an input outside of the wallet can't
+	// be replaced, as we're unable to sign it.
+	foreignTx := rbfTx.Copy()
+	foreignTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+	})
+	replaceable, err = ctx.feeBumper.canReplace(foreignTx)
+	if err != nil {
+		t.Fatalf("unable to check replaceability: %v", err)
+	}
+	if replaceable {
+		t.Fatalf("tx with foreign input deemed replaceable")
+	}
+
+	txFee, err := ctx.feeBumper.txFee(rbfTx)
+	if err != nil {
+		t.Fatalf("unable to compute fee: %v", err)
+	}
+	result, err := ctx.feeBumper.replaceTx(rbfTx, txFee, bumpTestFeeRate)
+	if err != nil {
+		t.Fatalf("unable to replace tx: %v", err)
+	}
+	if !result.replaced {
+		t.Fatalf("expected replacement tx")
+	}
+
+	replacementTx := result.tx
+	ctx.assertValidInputs(replacementTx)
+
+	expectedFee := bumpTestFeeRate * txWeight(rbfTx)
+	if result.fee != expectedFee {
+		t.Fatalf("expected replacement fee of %v, got %v", expectedFee,
+			result.fee)
+	}
+	ctx.assertFee(replacementTx, expectedFee)
+
+	// Only the wallet's change output should pay for the increased fee.
+	if replacementTx.TxOut[0].Value != rbfTx.TxOut[0].Value {
+		t.Fatalf("external output value changed from %v to %v",
+			rbfTx.TxOut[0].Value, replacementTx.TxOut[0].Value)
+	}
+	expectedChange := rbfTx.TxOut[1].Value - int64(expectedFee-txFee)
+	if replacementTx.TxOut[1].Value != expectedChange {
+		t.Fatalf("expected change of %v, got %v", expectedChange,
+			replacementTx.TxOut[1].Value)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/binary"
+	"net"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,6 +17,8 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/psbt"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
@@ -26,6 +29,24 @@ import (
 const (
 	// TODO(roasbeef): tune
 	msgBufferSize = 50
+
+	// zombieSweepInterval is the interval at which the funding manager
+	// checks for reservations which have been idle for longer than the
+	// reservation timeout.
+	zombieSweepInterval = time.Minute
+
+	// fundingReorgSafetyDepth is the number of blocks that are required
+	// to be built on top of the block confirming a funding transaction
+	// before we stop watching for it to be re-org'd out of the chain.
+	fundingReorgSafetyDepth = 6
+
+	// minChannelSize is the smallest channel we'll open. Atm, we require
+	// the amount to be above 6k satoshis as we currently hard-coded a 5k
+	// satoshi fee in several areas. As a result 6k sat is the min channel
+	// size that allows us to safely sit above the dust threshold after
+	// fees are applied.
+	// TODO(roasbeef): remove after dynamic fees are in
+	minChannelSize = btcutil.Amount(6000)
 )
 
 // reservationWithCtx encapsulates a pending channel reservation. This wrapper
@@ -40,10 +61,112 @@ type reservationWithCtx struct {
 	reservation *lnwallet.ChannelReservation
 	peerAddress *lnwire.NetAddress
 
+	// psbtFunding indicates that the funding transaction is to be crafted
+	// and signed by an external wallet, and presented to us as a PSBT.
+	psbtFunding bool
+
+	// theirContribution is the responder's contribution to a PSBT funded
+	// channel, held until the PSBT funding it has been verified.
+	theirContribution *lnwallet.ChannelContribution
+
+	// psbtVerified is true once the unsigned PSBT funding the channel has
+	// been verified, and the funding outpoint sent to the responder.
+	psbtVerified bool
+
+	// theirCommitSig is the responder's signature for our version of the
+	// commitment transaction of a PSBT funded channel, held until the
+	// signed PSBT has been presented.
+	theirCommitSig []byte
+
+	// fundingInputScripts are the input scripts of the signed PSBT
+	// funding the channel, held until the responder's signature for our
+	// commitment transaction has been received.
+	fundingInputScripts []*lnwallet.InputScript
+
+	// batch is the batch of channels this channel is being opened as part
+	// of, sharing a single funding transaction. It's nil if the channel
+	// isn't part of a batch.
+	batch *fundingBatch
+
+	// lastUpdated is the time at which the reservation last progressed
+	// through the funding workflow. Reservations which fail to progress
+	// within their timeout are considered zombies, and are cancelled by
+	// the zombie sweeper.
+	lastUpdated time.Time
+
+	// committed is true once the channel has been committed to disk, at
+	// which point the reservation is only kept around until the funding
+	// transaction confirms, and is no longer subject to the zombie
+	// sweeper.
+	committed bool
+
 	updates chan *lnrpc.OpenStatusUpdate
 	err     chan error
 }
 
+// timeout returns the amount of time the reservation may go without
+// progressing through the funding workflow before it's considered a zombie.
+// A PSBT funded reservation awaiting a PSBT from the caller, either to verify
+// or to finalize, is given the longer PSBT timeout, as the PSBT may need to be
+// signed by hand.
+func (r *reservationWithCtx) timeout() time.Duration {
+	if r.psbtFunding && r.batch == nil && r.theirContribution != nil &&
+		r.fundingInputScripts == nil {
+
+		return cfg.PsbtTimeout
+	}
+
+	return cfg.ReservationTimeout
+}
+
+// fundingBatch tracks a set of channels, opened with one or more peers at
+// once, which share a single funding transaction. The funding transaction is
+// only created once every responder has accepted their channel, and only
+// broadcast once every responder has signed our version of their channel's
+// commitment transaction. If any channel of the batch fails, then they all
+// do.
+type fundingBatch struct {
+	// numChans is the number of channels within the batch.
+	numChans int
+
+	// coinSelectOpts controls the coin selection performed in order to
+	// fund the batch's funding transaction. If nil, the wallet's defaults
+	// are used.
+	coinSelectOpts *lnwallet.CoinSelectOpts
+
+	// chans holds the reservations of the batch's channels, indexed by
+	// their pending channel ID.
+	chans map[[32]byte]*reservationWithCtx
+
+	// fundingOutputs holds the funding output of each channel whose
+	// responder has accepted it, indexed by its pending channel ID.
+	fundingOutputs map[[32]byte]*wire.TxOut
+
+	// fundingTx is the signed funding transaction shared by the batch's
+	// channels. It's nil until every responder has accepted their channel.
+	fundingTx *wire.MsgTx
+
+	// numSigned is the number of channels whose responder has signed our
+	// version of the commitment transaction.
+	numSigned int
+
+	// failed is set once any channel of the batch has failed.
+	failed bool
+}
+
+// newFundingBatch creates a new batch of numChans channels, whose funding
+// transaction is funded according to the passed coin selection options.
+func newFundingBatch(numChans int,
+	coinSelectOpts *lnwallet.CoinSelectOpts) *fundingBatch {
+
+	return &fundingBatch{
+		numChans:       numChans,
+		coinSelectOpts: coinSelectOpts,
+		chans:          make(map[[32]byte]*reservationWithCtx),
+		fundingOutputs: make(map[[32]byte]*wire.TxOut),
+	}
+}
+
 // initFundingMsg is sent by an outside subsystem to the funding manager in
 // order to kick off a funding workflow with a specified target peer. The
 // original request which defines the parameters of the funding workflow are
@@ -60,6 +183,10 @@ type initFundingMsg struct {
 type fundingRequestMsg struct {
 	msg         *lnwire.SingleFundingRequest
 	peerAddress *lnwire.NetAddress
+
+	// accepted indicates that the request has already been accepted by
+	// the channel acceptor.
+	accepted bool
 }
 
 // fundingResponseMsg couples an lnwire.SingleFundingResponse message with the
@@ -101,6 +228,24 @@ type fundingErrorMsg struct {
 	peerAddress *lnwire.NetAddress
 }
 
+// resumeFundingMsg requests that the funding flow of a confirmed channel be
+// resumed from the stage recorded within the database.
+type resumeFundingMsg struct {
+	peerKey   *btcec.PublicKey
+	chanPoint wire.OutPoint
+
+	// resendLocked indicates that FundingLocked should be sent to the
+	// remote party even if it has been sent before, as long as the channel
+	// is yet to be used. The remote party may have gone down before
+	// processing it, in which case it's still awaiting the message.
+	resendLocked bool
+}
+
+// resumeDoneMsg signals that a resumed funding flow has exited.
+type resumeDoneMsg struct {
+	chanPoint wire.OutPoint
+}
+
 // pendingChannels is a map instantiated per-peer which tracks all active
 // pending single funded channels indexed by their pending channel identifier.
 type pendingChannels map[[32]byte]*reservationWithCtx
@@ -130,12 +275,29 @@ type fundingConfig struct {
 	// funds from on-chain transaction outputs into Lightning channels.
 	Wallet *lnwallet.LightningWallet
 
+	// FeeEstimator calculates appropriate fee rates based on historical
+	// transaction information. The fee rate it returns is proposed to the
+	// remote peer as the fee rate of the channel's commitment transaction.
+	FeeEstimator lnwallet.FeeEstimator
+
 	// ArbiterChan allows the FundingManager to notify the BreachArbiter
 	// that a new channel has been created that should be observed to
 	// ensure that the channel counterparty hasn't broadcasted an invalid
 	// commitment transaction.
 	ArbiterChan chan<- *lnwallet.LightningChannel
 
+	// UpdateChannelBackups is called each time a new channel has been
+	// committed to disk, signalling that our static channel backups
+	// should be updated to include the channel.
+	UpdateChannelBackups func()
+
+	// AcceptChannel is consulted for each inbound funding request which
+	// satisfies our own constraints, returning whether the channel should
+	// be accepted, and if not, the reason why. This function may block
+	// for some time, so it MUST NOT be called from the main event loop.
+	AcceptChannel func(peerKey *btcec.PublicKey,
+		msg *lnwire.SingleFundingRequest) (bool, string)
+
 	// Notifier is used by the FundingManager to determine when the
 	// channel's funding transaction has been confirmed on the blockchain
 	// so that the channel creation process can be completed.
@@ -148,8 +310,10 @@ type fundingConfig struct {
 	SignMessage func(pubKey *btcec.PublicKey, msg []byte) (*btcec.Signature, error)
 
 	// SendAnnouncement is used by the FundingManager to announce newly
-	// created channels to the rest of the Lightning Network.
-	SendAnnouncement func(msg lnwire.Message) error
+	// created channels to the rest of the Lightning Network. The returned
+	// channel is sent an error, or nil, once the announcement has been
+	// processed.
+	SendAnnouncement func(msg lnwire.Message) chan error
 
 	// SendToPeer allows the FundingManager to send messages to the peer
 	// node during the multiple steps involved in the creation of the
@@ -221,6 +385,13 @@ type fundingManager struct {
 	barrierMtx      sync.RWMutex
 	newChanBarriers map[lnwire.ChannelID]chan struct{}
 
+	// resumingChans tracks the channels whose funding flow is currently
+	// being resumed, mapping each to a request to resume it once more
+	// which arrived in the meantime, if any. It's only accessed by the
+	// reservationCoordinator, ensuring the funding flow of a channel is
+	// never resumed twice at once.
+	resumingChans map[wire.OutPoint]*resumeFundingMsg
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -233,6 +404,7 @@ func newFundingManager(cfg fundingConfig) (*fundingManager, error) {
 		chanIDKey:          cfg.TempChanIDSeed,
 		activeReservations: make(map[serializedPubKey]pendingChannels),
 		newChanBarriers:    make(map[lnwire.ChannelID]chan struct{}),
+		resumingChans:      make(map[wire.OutPoint]*resumeFundingMsg),
 		fundingMsgs:        make(chan interface{}, msgBufferSize),
 		fundingRequests:    make(chan *initFundingMsg, msgBufferSize),
 		queries:            make(chan interface{}, 1),
@@ -274,6 +446,28 @@ func (f *fundingManager) Start() error {
 		go f.waitForFundingConfirmation(channel, doneChan)
 	}
 
+	// Channels whose funding transactions have already confirmed may not
+	// have completed the remainder of the funding flow before the daemon
+	// went down, so we'll resume the flow for each of them from the stage
+	// they reached. As the reservationCoordinator has yet to be started,
+	// we're free to handle the requests directly.
+	allChannels, err := f.cfg.Wallet.ChannelDB.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+	for _, channel := range allChannels {
+		if channel.IsPending ||
+			channel.FundingState == channeldb.FundingAnnounced {
+
+			continue
+		}
+
+		f.handleResumeFunding(&resumeFundingMsg{
+			peerKey:   channel.IdentityPub,
+			chanPoint: *channel.FundingOutpoint,
+		})
+	}
+
 	f.wg.Add(1) // TODO(roasbeef): tune
 	go f.reservationCoordinator()
 
@@ -343,6 +537,12 @@ type pendingChannel struct {
 	capacity      btcutil.Amount
 	localBalance  btcutil.Amount
 	remoteBalance btcutil.Amount
+
+	// forgetHeight is the height at which the channel will be forgotten
+	// if its funding transaction has yet to confirm. It's zero if the
+	// channel won't be forgotten, as is the case for channels we
+	// initiated.
+	forgetHeight uint32
 }
 
 type pendingChansReq struct {
@@ -365,6 +565,98 @@ func (f *fundingManager) PendingChannels() ([]*pendingChannel, error) {
 	return <-respChan, <-errChan
 }
 
+// pendingReservation describes a channel reservation which is still
+// progressing through the funding workflow.
+type pendingReservation struct {
+	identityPub   *btcec.PublicKey
+	pendingChanID [32]byte
+	capacity      btcutil.Amount
+	initiator     bool
+
+	// expiry is the time at which the reservation will be cancelled if it
+	// fails to progress further.
+	expiry time.Time
+}
+
+type pendingReservationsReq struct {
+	resp chan []*pendingReservation
+}
+
+// PendingReservations returns a slice describing all the channel reservations
+// which have yet to be committed to disk, along with when each will be
+// cancelled if it fails to progress through the funding workflow.
+func (f *fundingManager) PendingReservations() []*pendingReservation {
+	respChan := make(chan []*pendingReservation, 1)
+
+	select {
+	case f.queries <- &pendingReservationsReq{respChan}:
+	case <-f.quit:
+		return nil
+	}
+
+	select {
+	case resp := <-respChan:
+		return resp
+	case <-f.quit:
+		return nil
+	}
+}
+
+// psbtVerifyMsg is sent by an outside subsystem to the funding manager in
+// order to present the unsigned PSBT funding a pending channel.
+type psbtVerifyMsg struct {
+	pendingChanID [32]byte
+	packet        *psbt.Packet
+
+	err chan error
+}
+
+// VerifyPsbtFunding verifies that the passed unsigned PSBT funds the pending
+// channel identified by pendingChanID. If so, then the resulting funding
+// outpoint is sent to the responder, in order to obtain their signature for
+// our version of the commitment transaction. The PSBT must only be signed
+// once it has been successfully verified.
+func (f *fundingManager) VerifyPsbtFunding(pendingChanID [32]byte,
+	packet *psbt.Packet) error {
+
+	errChan := make(chan error, 1)
+
+	f.queries <- &psbtVerifyMsg{
+		pendingChanID: pendingChanID,
+		packet:        packet,
+		err:           errChan,
+	}
+
+	return <-errChan
+}
+
+// psbtFinalizeMsg is sent by an outside subsystem to the funding manager in
+// order to present the signed funding transaction of a pending channel.
+type psbtFinalizeMsg struct {
+	pendingChanID [32]byte
+	fundingTx     *wire.MsgTx
+
+	err chan error
+}
+
+// FinalizePsbtFunding presents the signed funding transaction of the pending
+// channel identified by pendingChanID, whose unsigned PSBT has already been
+// verified. The transaction will be broadcast as soon as we hold the
+// responder's signature for our version of the commitment transaction.
+func (f *fundingManager) FinalizePsbtFunding(pendingChanID [32]byte,
+	fundingTx *wire.MsgTx) error {
+
+	errChan := make(chan error, 1)
+
+	f.queries <- &psbtFinalizeMsg{
+		pendingChanID: pendingChanID,
+		fundingTx:     fundingTx,
+		err:           errChan,
+	}
+
+	return <-errChan
+}
+
 // reservationCoordinator is the primary goroutine tasked with progressing the
 // funding workflow between the wallet, and any outside peers or local callers.
 //
@@ -372,6 +664,9 @@ func (f *fundingManager) PendingChannels() ([]*pendingChannel, error) {
 func (f *fundingManager) reservationCoordinator() {
 	defer f.wg.Done()
 
+	zombieSweepTicker := time.NewTicker(zombieSweepInterval)
+	defer zombieSweepTicker.Stop()
+
 	for {
 		select {
 		case msg := <-f.fundingMsgs:
@@ -388,6 +683,10 @@ func (f *fundingManager) reservationCoordinator() {
 				f.handleFundingLocked(fmsg)
 			case *fundingErrorMsg:
 				f.handleErrorMsg(fmsg)
+			case *resumeFundingMsg:
+				f.handleResumeFunding(fmsg)
+			case *resumeDoneMsg:
+				f.handleResumeDone(fmsg)
 			}
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
@@ -397,7 +696,15 @@ func (f *fundingManager) reservationCoordinator() {
 				f.handleNumPending(msg)
 			case *pendingChansReq:
 				f.handlePendingChannels(msg)
+			case *psbtVerifyMsg:
+				f.handlePsbtVerify(msg)
+			case *psbtFinalizeMsg:
+				f.handlePsbtFinalize(msg)
+			case *pendingReservationsReq:
+				f.handlePendingReservations(msg)
 			}
+		case <-zombieSweepTicker.C:
+			f.pruneZombieReservations()
 		case <-f.quit:
 			return
 		}
@@ -444,6 +751,7 @@ func (f *fundingManager) handlePendingChannels(msg *pendingChansReq) {
 			capacity:      dbPendingChan.Capacity,
 			localBalance:  dbPendingChan.OurBalance,
 			remoteBalance: dbPendingChan.TheirBalance,
+			forgetHeight:  forgetHeight(dbPendingChan),
 		}
 
 		pendingChannels = append(pendingChannels, pendingChan)
@@ -453,11 +761,147 @@ func (f *fundingManager) handlePendingChannels(msg *pendingChansReq) {
 	msg.err <- nil
 }
 
+// handlePendingReservations responds to a request for details concerning all
+// channel reservations which have yet to be committed to disk.
+func (f *fundingManager) handlePendingReservations(msg *pendingReservationsReq) {
+	var reservations []*pendingReservation
+
+	f.resMtx.RLock()
+	for _, peerReservations := range f.activeReservations {
+		for pendingChanID, resCtx := range peerReservations {
+			if resCtx.committed {
+				continue
+			}
+
+			reservations = append(reservations, &pendingReservation{
+				identityPub:   resCtx.peerAddress.IdentityKey,
+				pendingChanID: pendingChanID,
+				capacity:      resCtx.reservation.Capacity(),
+				initiator:     resCtx.updates != nil,
+				expiry: resCtx.lastUpdated.Add(
+					resCtx.timeout()),
+			})
+		}
+	}
+	f.resMtx.RUnlock()
+
+	msg.resp <- reservations
+}
+
+// pruneZombieReservations cancels every reservation which has failed to
+// progress through the funding workflow within its timeout,
+// releasing the coins it had reserved. Such reservations are typically left
+// behind by a peer which began a funding workflow, then disappeared.
+func (f *fundingManager) pruneZombieReservations() {
+	type zombie struct {
+		peerKey       *btcec.PublicKey
+		pendingChanID [32]byte
+		timeout       time.Duration
+	}
+
+	var zombies []zombie
+	f.resMtx.RLock()
+	for _, peerReservations := range f.activeReservations {
+		for pendingChanID, resCtx := range peerReservations {
+			timeout := resCtx.timeout()
+			if resCtx.committed ||
+				time.Since(resCtx.lastUpdated) < timeout {
+
+				continue
+			}
+
+			zombies = append(zombies, zombie{
+				peerKey:       resCtx.peerAddress.IdentityKey,
+				pendingChanID: pendingChanID,
+				timeout:       timeout,
+			})
+		}
+	}
+	f.resMtx.RUnlock()
+
+	for _, z := range zombies {
+		// The reservation may have already been cancelled if it was
+		// part of a batch containing another zombie.
+		_, err := f.getReservationCtx(z.peerKey, z.pendingChanID)
+		if err != nil {
+			continue
+		}
+
+		fndgLog.Infof("Cancelling zombie reservation for peer(%x), "+
+			"pendingId(%x), idle for over %v",
+			z.peerKey.SerializeCompressed(), z.pendingChanID,
+			z.timeout)
+
+		resCtx, err := f.cancelReservationCtx(z.peerKey,
+			z.pendingChanID)
+		if err != nil {
+			fndgLog.Errorf("unable to cancel zombie reservation: %v",
+				err)
+			continue
+		}
+
+		timeoutErr := errors.Errorf("funding flow timed out after %v",
+			z.timeout)
+		select {
+		case resCtx.err <- timeoutErr:
+		default:
+		}
+
+		errMsg := &lnwire.Error{
+			ChanID: z.pendingChanID,
+			Code:   lnwire.ErrFundingTimeout,
+			Data:   []byte(timeoutErr.Error()),
+		}
+		if err := f.cfg.SendToPeer(z.peerKey, errMsg); err != nil {
+			fndgLog.Debugf("unable to send funding timeout to "+
+				"peer(%x): %v", z.peerKey.SerializeCompressed(),
+				err)
+		}
+	}
+}
+
 // processFundingRequest sends a message to the fundingManager allowing it to
 // initiate the new funding workflow with the source peer.
 func (f *fundingManager) processFundingRequest(msg *lnwire.SingleFundingRequest,
 	peerAddress *lnwire.NetAddress) {
-	f.fundingMsgs <- &fundingRequestMsg{msg, peerAddress}
+	f.fundingMsgs <- &fundingRequestMsg{msg, peerAddress, false}
+}
+
+// consultChanAcceptor asks the channel acceptor to decide upon the passed
+// funding request. If the request is rejected, then an error carrying the
+// reason is sent to the remote peer. Otherwise, the request is sent back to
+// the main event loop to be processed in full.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *fundingManager) consultChanAcceptor(fmsg *fundingRequestMsg) {
+	defer f.wg.Done()
+
+	peerKey := fmsg.peerAddress.IdentityKey
+	accept, reason := f.cfg.AcceptChannel(peerKey, fmsg.msg)
+	if !accept {
+		fndgLog.Infof("Channel acceptor rejected fundingRequest("+
+			"pendingId=%x) from peer(%x): %v",
+			fmsg.msg.PendingChannelID,
+			peerKey.SerializeCompressed(), reason)
+
+		if reason == "" {
+			reason = "channel rejected"
+		}
+		errMsg := &lnwire.Error{
+			ChanID: fmsg.msg.PendingChannelID,
+			Code:   lnwire.ErrChannelRejected,
+			Data:   []byte(reason),
+		}
+		if err := f.cfg.SendToPeer(peerKey, errMsg); err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
+		return
+	}
+
+	select {
+	case f.fundingMsgs <- &fundingRequestMsg{fmsg.msg, fmsg.peerAddress, true}:
+	case <-f.quit:
+	}
 }
 
 // handleFundingRequest creates an initial 'ChannelReservation' within
@@ -510,6 +954,44 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 	amt := msg.FundingAmount
 	delay := msg.CsvDelay
 
+	// Before committing any resources to this channel, ensure that the
+	// constraints the initiator wishes to impose upon us are reasonable.
+	// If the initiator lacks the extended-funding feature, then it won't
+	// have sent any, and the channel will be without constraints on both
+	// sides.
+	theirConstraints := legacyChanConstraints(amt)
+	if msg.Extended {
+		theirConstraints = channeldb.ChannelConstraints{
+			MaxPendingAmount: msg.MaxValueInFlight,
+			ChanReserve:      msg.ChannelReserve,
+			MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		}
+	}
+	if err := validateChanConstraints(amt, &theirConstraints); err != nil {
+		fndgLog.Errorf("Rejecting funding request from peer(%x): %v",
+			fmsg.peerAddress.IdentityKey.SerializeCompressed(), err)
+
+		errMsg := &lnwire.Error{
+			ChanID: msg.PendingChannelID,
+			Code:   lnwire.ErrUnacceptableConstraints,
+			Data:   []byte(err.Error()),
+		}
+		if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg); err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
+		return
+	}
+
+	// If the request has yet to be vetted by the channel acceptor, then
+	// we'll consult it within a new goroutine as it may block for some
+	// time. Once accepted, the request will be re-queued to be processed
+	// in full.
+	if !fmsg.accepted {
+		f.wg.Add(1)
+		go f.consultChanAcceptor(fmsg)
+		return
+	}
+
 	// TODO(roasbeef): error if funding flow already ongoing
 	fndgLog.Infof("Recv'd fundingRequest(amt=%v, delay=%v, pendingId=%x) "+
 		"from peer(%x)", amt, msg.PushSatoshis, delay, msg.PendingChannelID,
@@ -528,7 +1010,7 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 	reservation, err := f.cfg.Wallet.InitChannelReservation(amt, 0,
 		fmsg.peerAddress.IdentityKey, fmsg.peerAddress.Address,
 		uint16(fmsg.msg.ConfirmationDepth), delay, ourDustLimit,
-		msg.PushSatoshis)
+		msg.PushSatoshis, nil)
 	if err != nil {
 		// TODO(roasbeef): push ErrorGeneric message
 		fndgLog.Errorf("Unable to initialize reservation: %v", err)
@@ -537,6 +1019,21 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 
 	reservation.SetTheirDustLimit(theirDustlimit)
 
+	// With the reservation created, we'll record the constraints the
+	// initiator imposes upon us, along with the constraints we'll impose
+	// upon the initiator in return.
+	ourConstraints := legacyChanConstraints(amt)
+	if msg.Extended {
+		ourConstraints = defaultChanConstraints(amt)
+	}
+	reservation.SetTheirConstraints(theirConstraints)
+	reservation.SetOurConstraints(ourConstraints)
+
+	// The initiator decides whether the channel is to be announced, so
+	// we'll mark it as private if they haven't asked to announce it.
+	private := msg.ChannelFlags&lnwire.FFAnnounceChannel == 0
+	reservation.SetPrivate(private)
+
 	// Once the reservation has been created successfully, we add it to
 	// this peers map of pending reservations to track this particular
 	// reservation until either abort or completion.
@@ -548,6 +1045,7 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 		reservation: reservation,
 		err:         make(chan error, 1),
 		peerAddress: fmsg.peerAddress,
+		lastUpdated: time.Now(),
 	}
 	f.resMtx.Unlock()
 
@@ -597,6 +1095,12 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 		ourContribution.RevocationKey, ourContribution.CommitKey,
 		ourContribution.MultiSigKey, ourContribution.CsvDelay,
 		deliveryScript, ourDustLimit, msg.ConfirmationDepth)
+	if msg.Extended {
+		fundingResp.Extended = true
+		fundingResp.MaxValueInFlight = ourConstraints.MaxPendingAmount
+		fundingResp.ChannelReserve = ourConstraints.ChanReserve
+		fundingResp.MaxAcceptedHTLCs = ourConstraints.MaxAcceptedHtlcs
+	}
 
 	if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, fundingResp); err != nil {
 		fndgLog.Errorf("unable to send funding response to peer: %v", err)
@@ -626,6 +1130,7 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 			peerKey, pendingChanID)
 		return
 	}
+	resCtx.lastUpdated = time.Now()
 
 	cancelReservation := func() {
 		_, err := f.cancelReservationCtx(peerKey, pendingChanID)
@@ -638,6 +1143,36 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 
 	resCtx.reservation.SetTheirDustLimit(msg.DustLimit)
 
+	// Ensure that the constraints the responder wishes to impose upon us
+	// are reasonable. If not, we'll abort the funding workflow.
+	capacity := resCtx.reservation.Capacity()
+	theirConstraints := legacyChanConstraints(capacity)
+	if msg.Extended {
+		theirConstraints = channeldb.ChannelConstraints{
+			MaxPendingAmount: msg.MaxValueInFlight,
+			ChanReserve:      msg.ChannelReserve,
+			MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		}
+	}
+	if err := validateChanConstraints(capacity, &theirConstraints); err != nil {
+		fndgLog.Errorf("Rejecting funding response from peer(%x): %v",
+			peerKey.SerializeCompressed(), err)
+
+		errMsg := &lnwire.Error{
+			ChanID: pendingChanID,
+			Code:   lnwire.ErrUnacceptableConstraints,
+			Data:   []byte(err.Error()),
+		}
+		if err := f.cfg.SendToPeer(peerKey, errMsg); err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
+
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+	resCtx.reservation.SetTheirConstraints(theirConstraints)
+
 	// The remote node has responded with their portion of the channel
 	// contribution. At this point, we can process their contribution which
 	// allows us to construct and sign both the commitment transaction, and
@@ -658,6 +1193,57 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 		RevocationKey:   copyPubKey(msg.RevocationKey),
 		CsvDelay:        msg.CsvDelay,
 	}
+	// If the funding transaction is to be crafted by an external wallet,
+	// then we'll hand the details of the funding output over to the
+	// caller, and wait for them to present a PSBT paying to it.
+	if resCtx.psbtFunding {
+		fundingOut, err := resCtx.reservation.ExternalFundingOutput(
+			contribution.MultiSigKey)
+		if err != nil {
+			fndgLog.Errorf("Unable to generate funding output: %v",
+				err)
+			cancelReservation()
+			resCtx.err <- err
+			return
+		}
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			fundingOut.PkScript, activeNetParams.Params)
+		if err != nil {
+			fndgLog.Errorf("Unable to extract funding address: %v",
+				err)
+			cancelReservation()
+			resCtx.err <- err
+			return
+		}
+
+		resCtx.theirContribution = contribution
+
+		// If the channel is part of a batch, then the batch's funding
+		// transaction will be created by our wallet once every channel
+		// has been accepted.
+		if resCtx.batch != nil {
+			f.batchChannelAccepted(resCtx.batch, pendingChanID,
+				fundingOut)
+			return
+		}
+
+		fndgLog.Infof("Waiting for PSBT funding pendingID(%x) with "+
+			"%v to %v", pendingChanID, btcutil.Amount(fundingOut.Value),
+			addrs[0])
+
+		resCtx.updates <- &lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_PsbtFund{
+				PsbtFund: &lnrpc.ReadyForPsbtFunding{
+					PendingChanId:  pendingChanID[:],
+					FundingAddress: addrs[0].String(),
+					FundingAmount:  fundingOut.Value,
+					FundingScript:  fundingOut.PkScript,
+				},
+			},
+		}
+		return
+	}
+
 	if err := resCtx.reservation.ProcessContribution(contribution); err != nil {
 		fndgLog.Errorf("Unable to process contribution from %v: %v",
 			fmsg.peerAddress.IdentityKey, err)
@@ -666,6 +1252,19 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 		return
 	}
 
+	if err := f.sendFundingComplete(resCtx, pendingChanID); err != nil {
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+}
+
+// sendFundingComplete sends the funding outpoint along with our signature for
+// their version of the commitment transaction to the responder of the passed
+// reservation, once their contribution has been processed.
+func (f *fundingManager) sendFundingComplete(resCtx *reservationWithCtx,
+	pendingChanID [32]byte) error {
+
 	// Now that we have their contribution, we can extract, then send over
 	// both the funding out point and our signature for their version of
 	// the commitment transaction to the remote peer.
@@ -674,9 +1273,7 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 	commitSig, err := btcec.ParseSignature(sig, btcec.S256())
 	if err != nil {
 		fndgLog.Errorf("Unable to parse signature: %v", err)
-		cancelReservation()
-		resCtx.err <- err
-		return
+		return err
 	}
 
 	// A new channel has almost finished the funding process. In order to
@@ -698,89 +1295,256 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 	fundingComplete := lnwire.NewSingleFundingComplete(pendingChanID, *outPoint,
 		commitSig, revocationKey, obsfucator)
 
-	err = f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, fundingComplete)
+	err = f.cfg.SendToPeer(resCtx.peerAddress.IdentityKey, fundingComplete)
 	if err != nil {
 		fndgLog.Errorf("Unable to send funding complete message: %v", err)
-		cancelReservation()
-		resCtx.err <- err
-		return
+		return err
 	}
-}
 
-// processFundingComplete queues a funding complete message coupled with the
-// source peer to the fundingManager.
-func (f *fundingManager) processFundingComplete(msg *lnwire.SingleFundingComplete,
-	peerAddress *lnwire.NetAddress) {
-	f.fundingMsgs <- &fundingCompleteMsg{msg, peerAddress}
+	return nil
 }
 
-// handleFundingComplete progresses the funding workflow when the daemon is on
-// the responding side of a single funder workflow. Once this message has been
-// processed, a signature is sent to the remote peer allowing it to broadcast
-// the funding transaction, progressing the workflow into the final stage.
-func (f *fundingManager) handleFundingComplete(fmsg *fundingCompleteMsg) {
-	peerKey := fmsg.peerAddress.IdentityKey
-	pendingChanID := fmsg.msg.PendingChannelID
-
-	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+// handlePsbtVerify verifies the unsigned PSBT funding a pending channel. If
+// it correctly funds the channel, then the resulting funding outpoint is sent
+// to the responder. Otherwise, an error is returned, leaving the reservation
+// intact so the caller may present a corrected PSBT.
+func (f *fundingManager) handlePsbtVerify(msg *psbtVerifyMsg) {
+	resCtx, err := f.getReservationCtxByPendingID(msg.pendingChanID)
 	if err != nil {
-		fndgLog.Warnf("can't find reservation (peerID:%v, chanID:%v)",
-			peerKey, pendingChanID)
+		msg.err <- err
 		return
 	}
+	resCtx.lastUpdated = time.Now()
 
-	cancelReservation := func() {
-		_, err := f.cancelReservationCtx(peerKey, pendingChanID)
-		if err != nil {
-			fndgLog.Errorf("unable to cancel reservation: %v", err)
-		}
-	}
+	switch {
+	case !resCtx.psbtFunding || resCtx.batch != nil:
+		msg.err <- errors.Errorf("pending channel %x isn't PSBT funded",
+			msg.pendingChanID)
+		return
 
-	// The channel initiator has responded with the funding outpoint of the
-	// final funding transaction, as well as a signature for our version of
-	// the commitment transaction. So at this point, we can validate the
-	// inititator's commitment transaction, then send our own if it's valid.
-	// TODO(roasbeef): make case (p vs P) consistent throughout
-	fundingOut := fmsg.msg.FundingOutPoint
-	fndgLog.Infof("completing pendingID(%x) with ChannelPoint(%v)",
-		pendingChanID, fundingOut)
+	case resCtx.theirContribution == nil:
+		msg.err <- errors.Errorf("pending channel %x isn't yet ready "+
+			"for funding", msg.pendingChanID)
+		return
 
-	revokeKey := copyPubKey(fmsg.msg.RevocationKey)
-	obsfucator := fmsg.msg.StateHintObsfucator
-	commitSig := fmsg.msg.CommitSignature.Serialize()
+	case resCtx.psbtVerified:
+		msg.err <- errors.Errorf("PSBT funding pending channel %x has "+
+			"already been verified", msg.pendingChanID)
+		return
+	}
 
-	// With all the necessary data available, attempt to advance the
-	// funding workflow to the next stage. If this succeeds then the
-	// funding transaction will broadcast after our next message.
-	completeChan, err := resCtx.reservation.CompleteReservationSingle(
-		revokeKey, &fundingOut, commitSig, obsfucator)
-	if err != nil {
-		// TODO(roasbeef): better error logging: peerID, channelID, etc.
-		fndgLog.Errorf("unable to complete single reservation: %v", err)
-		cancelReservation()
+	if err := verifyPsbtInputs(msg.packet); err != nil {
+		msg.err <- err
 		return
 	}
 
-	// With their signature for our version of the commitment transaction
-	// verified, we can now send over our signature to the remote peer.
-	// TODO(roasbeef): just have raw bytes in wire msg? avoids decoding
-	// then decoding shortly afterwards.
-	_, sig := resCtx.reservation.OurSignatures()
-	ourCommitSig, err := btcec.ParseSignature(sig, btcec.S256())
+	// With the inputs deemed sane, we'll have the wallet ensure the
+	// transaction pays to the funding output, then create both commitment
+	// transactions.
+	err = resCtx.reservation.ProcessExternalContribution(
+		resCtx.theirContribution, msg.packet.UnsignedTx)
 	if err != nil {
-		fndgLog.Errorf("unable to parse signature: %v", err)
-		cancelReservation()
+		fndgLog.Errorf("Unable to process PSBT funding pendingID(%x): %v",
+			msg.pendingChanID, err)
+		msg.err <- err
 		return
 	}
+	resCtx.psbtVerified = true
 
-	// A new channel has almost finished the funding process. In order to
-	// properly synchronize with the writeHandler goroutine, we add a new
-	// channel to the barriers map which will be closed once the channel is
-	// fully open.
-	f.barrierMtx.Lock()
-	channelID := lnwire.NewChanIDFromOutPoint(&fundingOut)
-	fndgLog.Debugf("Creating chan barrier for ChanID(%v)", channelID)
-	f.newChanBarriers[channelID] = make(chan struct{})
+	if err := f.sendFundingComplete(resCtx, msg.pendingChanID); err != nil {
+		_, cancelErr := f.cancelReservationCtx(
+			resCtx.peerAddress.IdentityKey, msg.pendingChanID)
+		if cancelErr != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v",
+				cancelErr)
+		}
+		resCtx.err <- err
+		msg.err <- err
+		return
+	}
+
+	msg.err <- nil
+}
+
+// verifyPsbtInputs ensures that each input of the passed PSBT spends a native
+// witness output, as otherwise the txid of the funding transaction could
+// change once signed. It also ensures that the inputs cover the outputs.
+func verifyPsbtInputs(packet *psbt.Packet) error {
+	if len(packet.UnsignedTx.TxIn) == 0 {
+		return errors.Errorf("PSBT has no inputs")
+	}
+
+	var inputTotal, outputTotal int64
+	for i := range packet.Inputs {
+		utxo, err := packet.InputUtxo(i)
+		if err != nil {
+			return err
+		}
+
+		if !txscript.IsPayToWitnessPubKeyHash(utxo.PkScript) &&
+			!txscript.IsPayToWitnessScriptHash(utxo.PkScript) {
+
+			return errors.Errorf("input %v of PSBT doesn't spend a "+
+				"native witness output", i)
+		}
+
+		inputTotal += utxo.Value
+	}
+	for _, txOut := range packet.UnsignedTx.TxOut {
+		outputTotal += txOut.Value
+	}
+
+	if inputTotal < outputTotal {
+		return errors.Errorf("PSBT inputs of %v don't cover its "+
+			"outputs of %v", btcutil.Amount(inputTotal),
+			btcutil.Amount(outputTotal))
+	}
+
+	return nil
+}
+
+// handlePsbtFinalize records the signed funding transaction of a pending PSBT
+// funded channel. If the responder's signature for our version of the
+// commitment transaction has already been received, then the channel funding
+// is completed, broadcasting the funding transaction.
+func (f *fundingManager) handlePsbtFinalize(msg *psbtFinalizeMsg) {
+	resCtx, err := f.getReservationCtxByPendingID(msg.pendingChanID)
+	if err != nil {
+		msg.err <- err
+		return
+	}
+	resCtx.lastUpdated = time.Now()
+
+	switch {
+	case !resCtx.psbtFunding || !resCtx.psbtVerified || resCtx.batch != nil:
+		msg.err <- errors.Errorf("pending channel %x has no verified "+
+			"PSBT", msg.pendingChanID)
+		return
+
+	case resCtx.fundingInputScripts != nil:
+		msg.err <- errors.Errorf("PSBT funding pending channel %x has "+
+			"already been finalized", msg.pendingChanID)
+		return
+	}
+
+	// The signed transaction must be the one we verified, otherwise the
+	// funding outpoint we sent to the responder would be invalid.
+	fundingPoint := resCtx.reservation.FundingOutpoint()
+	if msg.fundingTx.TxHash() != fundingPoint.Hash {
+		msg.err <- errors.Errorf("signed funding transaction %v "+
+			"doesn't match verified funding transaction %v",
+			msg.fundingTx.TxHash(), fundingPoint.Hash)
+		return
+	}
+
+	inputScripts := make([]*lnwallet.InputScript, len(msg.fundingTx.TxIn))
+	for i, txIn := range msg.fundingTx.TxIn {
+		if len(txIn.Witness) == 0 && len(txIn.SignatureScript) == 0 {
+			msg.err <- errors.Errorf("input %v of funding "+
+				"transaction isn't signed", i)
+			return
+		}
+
+		inputScripts[i] = &lnwallet.InputScript{
+			Witness:   txIn.Witness,
+			ScriptSig: txIn.SignatureScript,
+		}
+	}
+	resCtx.fundingInputScripts = inputScripts
+
+	fndgLog.Infof("Recv'd signed funding transaction for pendingID(%x)",
+		msg.pendingChanID)
+
+	// If we're still waiting for the responder's signature, then the
+	// channel funding will be completed once it arrives.
+	if resCtx.theirCommitSig == nil {
+		msg.err <- nil
+		return
+	}
+
+	msg.err <- f.completeChannelFunding(resCtx, msg.pendingChanID,
+		resCtx.theirCommitSig)
+}
+
+// processFundingComplete queues a funding complete message coupled with the
+// source peer to the fundingManager.
+func (f *fundingManager) processFundingComplete(msg *lnwire.SingleFundingComplete,
+	peerAddress *lnwire.NetAddress) {
+	f.fundingMsgs <- &fundingCompleteMsg{msg, peerAddress}
+}
+
+// handleFundingComplete progresses the funding workflow when the daemon is on
+// the responding side of a single funder workflow. Once this message has been
+// processed, a signature is sent to the remote peer allowing it to broadcast
+// the funding transaction, progressing the workflow into the final stage.
+func (f *fundingManager) handleFundingComplete(fmsg *fundingCompleteMsg) {
+	peerKey := fmsg.peerAddress.IdentityKey
+	pendingChanID := fmsg.msg.PendingChannelID
+
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil {
+		fndgLog.Warnf("can't find reservation (peerID:%v, chanID:%v)",
+			peerKey, pendingChanID)
+		return
+	}
+	resCtx.lastUpdated = time.Now()
+
+	cancelReservation := func() {
+		_, err := f.cancelReservationCtx(peerKey, pendingChanID)
+		if err != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v", err)
+		}
+	}
+
+	// The channel initiator has responded with the funding outpoint of the
+	// final funding transaction, as well as a signature for our version of
+	// the commitment transaction. So at this point, we can validate the
+	// inititator's commitment transaction, then send our own if it's valid.
+	// TODO(roasbeef): make case (p vs P) consistent throughout
+	fundingOut := fmsg.msg.FundingOutPoint
+	fndgLog.Infof("completing pendingID(%x) with ChannelPoint(%v)",
+		pendingChanID, fundingOut)
+
+	revokeKey := copyPubKey(fmsg.msg.RevocationKey)
+	obsfucator := fmsg.msg.StateHintObsfucator
+	commitSig := fmsg.msg.CommitSignature.Serialize()
+
+	// With all the necessary data available, attempt to advance the
+	// funding workflow to the next stage. If this succeeds then the
+	// funding transaction will broadcast after our next message.
+	completeChan, err := resCtx.reservation.CompleteReservationSingle(
+		revokeKey, &fundingOut, commitSig, obsfucator)
+	if err != nil {
+		// TODO(roasbeef): better error logging: peerID, channelID, etc.
+		fndgLog.Errorf("unable to complete single reservation: %v", err)
+		cancelReservation()
+		return
+	}
+
+	// Now that the channel has been committed to disk, we'll ensure it's
+	// included within our static channel backups.
+	f.cfg.UpdateChannelBackups()
+
+	// With their signature for our version of the commitment transaction
+	// verified, we can now send over our signature to the remote peer.
+	// TODO(roasbeef): just have raw bytes in wire msg? avoids decoding
+	// then decoding shortly afterwards.
+	_, sig := resCtx.reservation.OurSignatures()
+	ourCommitSig, err := btcec.ParseSignature(sig, btcec.S256())
+	if err != nil {
+		fndgLog.Errorf("unable to parse signature: %v", err)
+		cancelReservation()
+		return
+	}
+
+	// A new channel has almost finished the funding process. In order to
+	// properly synchronize with the writeHandler goroutine, we add a new
+	// channel to the barriers map which will be closed once the channel is
+	// fully open.
+	f.barrierMtx.Lock()
+	channelID := lnwire.NewChanIDFromOutPoint(&fundingOut)
+	fndgLog.Debugf("Creating chan barrier for ChanID(%v)", channelID)
+	f.newChanBarriers[channelID] = make(chan struct{})
 	f.barrierMtx.Unlock()
 
 	fndgLog.Infof("sending signComplete for pendingID(%x) over ChannelPoint(%v)",
@@ -793,6 +1557,10 @@ func (f *fundingManager) handleFundingComplete(fmsg *fundingCompleteMsg) {
 		return
 	}
 
+	// With the channel committed to disk, the reservation is now only
+	// awaiting the confirmation of the funding transaction.
+	resCtx.committed = true
+
 	go func() {
 		doneChan := make(chan struct{})
 		go f.waitForFundingConfirmation(completeChan, doneChan)
@@ -824,155 +1592,852 @@ func (f *fundingManager) handleFundingSignComplete(fmsg *fundingSignCompleteMsg)
 			peerKey, chanID)
 		return
 	}
+	resCtx.lastUpdated = time.Now()
+
+	// The remote peer has responded with a signature for our commitment
+	// transaction. If the channel is PSBT funded, and the signed funding
+	// transaction is yet to be presented, then we'll hold onto it until
+	// then.
+	commitSig := fmsg.msg.CommitSignature.Serialize()
+	if resCtx.psbtFunding && resCtx.fundingInputScripts == nil {
+		fndgLog.Infof("Waiting for signed PSBT for pendingID(%x)",
+			chanID)
+
+		resCtx.theirCommitSig = commitSig
+
+		// Once every responder of a batch has signed, the batch's
+		// funding transaction can be broadcast.
+		if resCtx.batch != nil {
+			resCtx.batch.numSigned++
+			if resCtx.batch.numSigned == resCtx.batch.numChans {
+				f.completeBatch(resCtx.batch)
+			}
+		}
+		return
+	}
+
+	f.completeChannelFunding(resCtx, chanID, commitSig)
+}
+
+// batchChannelAccepted records the funding output of a channel within a
+// batch, once its responder has accepted it. When every channel of the batch
+// has been accepted, the wallet funds the batch's funding transaction, and
+// the resulting funding outpoint of each channel is sent to its responder.
+func (f *fundingManager) batchChannelAccepted(batch *fundingBatch,
+	pendingChanID [32]byte, fundingOut *wire.TxOut) {
+
+	if batch.failed {
+		return
+	}
+
+	batch.fundingOutputs[pendingChanID] = fundingOut
+	if len(batch.fundingOutputs) < batch.numChans {
+		return
+	}
+
+	outputs := make([]*wire.TxOut, 0, len(batch.fundingOutputs))
+	for _, fundingOut := range batch.fundingOutputs {
+		outputs = append(outputs, fundingOut)
+	}
+
+	fundingTx, err := f.cfg.Wallet.FundBatchTx(outputs,
+		batch.coinSelectOpts)
+	if err != nil {
+		fndgLog.Errorf("Unable to fund batch of %v channels: %v",
+			batch.numChans, err)
+		f.failBatch(batch, nil, err)
+		return
+	}
+	batch.fundingTx = fundingTx
+
+	fndgLog.Infof("Funded batch of %v channels with txid %v",
+		batch.numChans, fundingTx.TxHash())
+
+	// Each reservation is handed the unsigned funding transaction. As all
+	// of its inputs are our own witness inputs, its txid won't change
+	// once the signatures are attached.
+	unsignedTx := fundingTx.Copy()
+	for _, txIn := range unsignedTx.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+
+	for pendingChanID, resCtx := range batch.chans {
+		err := resCtx.reservation.ProcessExternalContribution(
+			resCtx.theirContribution, unsignedTx)
+		if err != nil {
+			fndgLog.Errorf("Unable to process batch funding for "+
+				"pendingID(%x): %v", pendingChanID, err)
+			f.failBatch(batch, nil, err)
+			return
+		}
+		resCtx.psbtVerified = true
+
+		if err := f.sendFundingComplete(resCtx, pendingChanID); err != nil {
+			f.failBatch(batch, nil, err)
+			return
+		}
+	}
+}
+
+// completeBatch completes the funding of each channel within a batch once
+// every responder has signed our version of their channel's commitment
+// transaction. Every responder's signature is verified before any channel is
+// committed to disk, and all the channels are then committed together,
+// before the batch's funding transaction is broadcast. If the funding
+// transaction can't be broadcast, then the channels are removed from disk,
+// and the batch fails.
+func (f *fundingManager) completeBatch(batch *fundingBatch) {
+	if batch.failed {
+		return
+	}
+
+	inputScripts := make([]*lnwallet.InputScript, len(batch.fundingTx.TxIn))
+	for i, txIn := range batch.fundingTx.TxIn {
+		inputScripts[i] = &lnwallet.InputScript{
+			Witness:   txIn.Witness,
+			ScriptSig: txIn.SignatureScript,
+		}
+	}
+
+	// forgetCompleted removes the reservations which have been completed
+	// by the wallet, and so can no longer be cancelled, before the batch
+	// is failed.
+	completeChans := make(map[[32]byte]*channeldb.OpenChannel)
+	forgetCompleted := func() {
+		for pendingChanID := range completeChans {
+			resCtx := batch.chans[pendingChanID]
+			f.deleteReservationCtx(resCtx.peerAddress.IdentityKey,
+				pendingChanID)
+		}
+	}
+
+	// First, we'll verify each responder's signature for our version of
+	// their channel's commitment transaction.
+	for pendingChanID, resCtx := range batch.chans {
+		resCtx.fundingInputScripts = inputScripts
+
+		completeChan, err := resCtx.reservation.CompleteReservation(
+			inputScripts, resCtx.theirCommitSig)
+		if err != nil {
+			fndgLog.Errorf("Unable to complete pendingID(%x) of "+
+				"batch: %v", pendingChanID, err)
+			resCtx.err <- err
+
+			forgetCompleted()
+			f.failBatch(batch, resCtx, err)
+			return
+		}
+		completeChans[pendingChanID] = completeChan
+	}
+
+	// With every signature verified, we'll commit all the channels to
+	// disk at once, so we never end up with only part of the batch.
+	chans := make([]*channeldb.OpenChannel, 0, len(completeChans))
+	addrs := make([]*net.TCPAddr, 0, len(completeChans))
+	for pendingChanID, completeChan := range completeChans {
+		resCtx := batch.chans[pendingChanID]
+
+		chans = append(chans, completeChan)
+		addrs = append(addrs, resCtx.peerAddress.Address)
+	}
+	err := f.cfg.Wallet.ChannelDB.SyncPendingChannels(chans, addrs)
+	if err != nil {
+		fndgLog.Errorf("Unable to commit batch of %v channels: %v",
+			batch.numChans, err)
+		forgetCompleted()
+		f.failBatch(batch, nil, err)
+		return
+	}
+
+	fndgLog.Infof("Broadcasting batch funding tx %v: %v",
+		batch.fundingTx.TxHash(), newLogClosure(func() string {
+			return spew.Sdump(batch.fundingTx)
+		}),
+	)
+
+	// If the funding transaction can't be broadcast, then it may still
+	// have reached the network, for example if it was already within the
+	// mempool. As the transaction is fully signed, the channels can't
+	// safely be forgotten, so they're left pending, and the transaction is
+	// rebroadcast with each new block until it's accepted. Its inputs
+	// remain locked in the meantime, so they aren't spent elsewhere.
+	err = f.cfg.Wallet.PublishTransaction(batch.fundingTx)
+	if err != nil {
+		fndgLog.Errorf("Unable to broadcast batch funding tx %v, "+
+			"retrying with each new block: %v",
+			batch.fundingTx.TxHash(), err)
+
+		f.wg.Add(1)
+		go f.rebroadcastFundingTx(batch.fundingTx)
+	} else {
+		// Now that the transaction has been broadcast, the wallet
+		// considers its inputs spent, so we no longer need to keep
+		// them locked.
+		f.cfg.Wallet.ReleaseTxInputs(batch.fundingTx)
+	}
+
+	for pendingChanID, completeChan := range completeChans {
+		f.channelCommitted(batch.chans[pendingChanID], pendingChanID,
+			completeChan)
+	}
+}
+
+// rebroadcastFundingTx broadcasts the passed funding transaction, which we
+// were previously unable to broadcast, with each new block until it's
+// accepted, after which its inputs are released. If the transaction confirms
+// in the meantime, then it must have reached the network after all, so we stop
+// rebroadcasting it.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *fundingManager) rebroadcastFundingTx(fundingTx *wire.MsgTx) {
+	defer f.wg.Done()
+
+	txid := fundingTx.TxHash()
+
+	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(&txid, 1)
+	if err != nil {
+		fndgLog.Errorf("Unable to register for confirmation of "+
+			"funding tx %v: %v", txid, err)
+		return
+	}
+
+	epochClient, err := f.cfg.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		fndgLog.Errorf("Unable to register for new blocks to "+
+			"rebroadcast funding tx %v: %v", txid, err)
+		return
+	}
+	defer epochClient.Cancel()
+
+	for {
+		select {
+		case _, ok := <-epochClient.Epochs:
+			if !ok {
+				return
+			}
+
+		case <-confNtfn.Confirmed:
+			fndgLog.Infof("Funding tx %v confirmed", txid)
+			f.cfg.Wallet.ReleaseTxInputs(fundingTx)
+			return
+
+		case <-f.quit:
+			return
+		}
+
+		err := f.cfg.Wallet.PublishTransaction(fundingTx)
+		if err != nil {
+			fndgLog.Debugf("Unable to rebroadcast funding tx %v: %v",
+				txid, err)
+			continue
+		}
+
+		fndgLog.Infof("Rebroadcast funding tx %v", txid)
+		f.cfg.Wallet.ReleaseTxInputs(fundingTx)
+
+		return
+	}
+}
+
+// failBatch fails every channel of a batch once any of them has failed,
+// cancelling their reservations and releasing the inputs of the batch's
+// funding transaction, if it has been created. The error is sent to the
+// caller of each channel, bar the one which caused the failure, if any, as it
+// will have already been notified.
+func (f *fundingManager) failBatch(batch *fundingBatch,
+	failedChan *reservationWithCtx, err error) {
+
+	if batch.failed {
+		return
+	}
+	batch.failed = true
+
+	fndgLog.Errorf("Failing batch of %v channels: %v", batch.numChans, err)
+
+	for pendingChanID, resCtx := range batch.chans {
+		// The reservation which caused the failure may have already
+		// been cancelled.
+		peerKey := resCtx.peerAddress.IdentityKey
+		if _, err := f.getReservationCtx(peerKey, pendingChanID); err == nil {
+			_, cancelErr := f.cancelReservationCtx(peerKey,
+				pendingChanID)
+			if cancelErr != nil {
+				fndgLog.Errorf("unable to cancel reservation: %v",
+					cancelErr)
+			}
+		}
+
+		if resCtx == failedChan {
+			continue
+		}
+
+		select {
+		case resCtx.err <- err:
+		default:
+		}
+	}
+
+	if batch.fundingTx != nil {
+		f.cfg.Wallet.ReleaseTxInputs(batch.fundingTx)
+	}
+}
+
+// completeChannelFunding verifies the responder's signature for our version
+// of the commitment transaction, then commits the channel state to disk and
+// broadcasts the funding transaction. The channel is then watched until the
+// funding transaction is sufficiently confirmed. If the reservation can't be
+// completed, then it's cancelled, and the error returned.
+func (f *fundingManager) completeChannelFunding(resCtx *reservationWithCtx,
+	chanID [32]byte, commitSig []byte) error {
+
+	peerKey := resCtx.peerAddress.IdentityKey
+
+	// We'll verify the signature for validity, then commit the state to
+	// disk as we can now open the channel.
+	completeChan, err := resCtx.reservation.CompleteReservation(
+		resCtx.fundingInputScripts, commitSig)
+	if err != nil {
+		fndgLog.Errorf("unable to complete reservation sign complete: %v", err)
+		resCtx.err <- err
+
+		if _, err := f.cancelReservationCtx(peerKey, chanID); err != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v", err)
+		}
+		return err
+	}
+
+	f.channelCommitted(resCtx, chanID, completeChan)
+
+	return nil
+}
+
+// channelCommitted notifies the caller that the channel of the passed
+// reservation has been committed to disk, and its funding transaction
+// broadcast. The channel is then watched until the funding transaction is
+// sufficiently confirmed.
+func (f *fundingManager) channelCommitted(resCtx *reservationWithCtx,
+	chanID [32]byte, completeChan *channeldb.OpenChannel) {
+
+	peerKey := resCtx.peerAddress.IdentityKey
+
+	// Now that the channel has been committed to disk, we'll ensure it's
+	// included within our static channel backups.
+	f.cfg.UpdateChannelBackups()
+
+	fundingPoint := resCtx.reservation.FundingOutpoint()
+	fndgLog.Infof("Finalizing pendingID(%x) over ChannelPoint(%v), "+
+		"waiting for channel open on-chain", chanID, fundingPoint)
+
+	// Send an update to the upstream client that the negotiation process
+	// is over.
+	// TODO(roasbeef): add abstraction over updates to accommodate
+	// long-polling, or SSE, etc.
+	resCtx.updates <- &lnrpc.OpenStatusUpdate{
+		Update: &lnrpc.OpenStatusUpdate_ChanPending{
+			ChanPending: &lnrpc.PendingUpdate{
+				Txid:        fundingPoint.Hash[:],
+				OutputIndex: fundingPoint.Index,
+			},
+		},
+	}
+
+	// With the channel committed to disk, the reservation is now only
+	// awaiting the confirmation of the funding transaction.
+	resCtx.committed = true
+
+	go func() {
+		doneChan := make(chan struct{})
+		go f.waitForFundingConfirmation(completeChan, doneChan)
+
+		select {
+		case <-f.quit:
+			return
+		case <-doneChan:
+		}
+
+		// Finally give the caller a final update notifying them that
+		// the channel is now open.
+		// TODO(roasbeef): only notify after recv of funding locked?
+		resCtx.updates <- &lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_ChanOpen{
+				ChanOpen: &lnrpc.ChannelOpenUpdate{
+					ChannelPoint: &lnrpc.ChannelPoint{
+						FundingTxid: fundingPoint.Hash[:],
+						OutputIndex: fundingPoint.Index,
+					},
+				},
+			},
+		}
+
+		f.deleteReservationCtx(peerKey, chanID)
+	}()
+}
+
+// waitForFundingConfirmation handles the final stages of the channel funding
+// process once the funding transaction has been broadcast. The primary
+// function of waitForFundingConfirmation is to wait for blockchain
+// confirmation, and then to notify the other systems that must be notified
+// when a channel has become active for lightning transactions. The passed
+// doneChan is closed once the channel has first been marked open. If the
+// funding transaction is then re-org'd out of the chain before it's buried
+// fundingReorgSafetyDepth blocks deep, the channel is reverted to pending
+// until the funding transaction confirms once again.
+func (f *fundingManager) waitForFundingConfirmation(completeChan *channeldb.OpenChannel,
+	doneChan chan struct{}) {
+
+	var doneClosed bool
+	defer func() {
+		if !doneClosed {
+			close(doneChan)
+		}
+	}()
+
+	// Register with the ChainNotifier for a notification once the funding
+	// transaction reaches `numConfs` confirmations.
+	txid := completeChan.FundingOutpoint.Hash
+	numConfs := uint32(completeChan.NumConfsRequired)
+	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(&txid, numConfs)
+	if err != nil {
+		fndgLog.Errorf("Unable to register for confirmation of "+
+			"ChannelPoint(%v)", completeChan.FundingOutpoint)
+		return
+	}
+
+	// We'll also watch for new blocks, both so the channel can be
+	// forgotten if the remote peer's funding transaction has gone
+	// unconfirmed for too long, and so we know once the confirmed funding
+	// transaction is buried deep enough to no longer be re-org'd out.
+	epochClient, err := f.cfg.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		fndgLog.Errorf("Unable to register for new blocks: %v", err)
+		return
+	}
+	defer epochClient.Cancel()
+
+	for {
+		confDetails := f.waitForConf(completeChan, confNtfn,
+			epochClient.Epochs)
+		if confDetails == nil {
+			return
+		}
+
+		if !f.markChannelOpen(completeChan, confDetails) {
+			return
+		}
+
+		if !doneClosed {
+			close(doneChan)
+			doneClosed = true
+		}
+
+		f.resumeFunding(&resumeFundingMsg{
+			peerKey:   completeChan.IdentityPub,
+			chanPoint: *completeChan.FundingOutpoint,
+		})
+
+		// Now that the channel is open, we'll watch for the funding
+		// transaction to be re-org'd out until it's safely buried. If
+		// it is, then the channel is reverted to pending, and we wait
+		// for the funding transaction to confirm once again.
+		safeHeight := confDetails.BlockHeight + fundingReorgSafetyDepth
+		if !f.waitForFundingReorg(completeChan, confNtfn,
+			epochClient.Epochs, safeHeight) {
+
+			return
+		}
+	}
+}
+
+// waitForConf waits for the funding transaction of the passed channel to
+// reach its required number of confirmations, returning the confirmation
+// details. If the remote peer initiated the channel, then it's solely
+// responsible for the funding transaction confirming, so the channel is
+// forgotten if it remains unconfirmed for too long. Nil is returned if the
+// channel has been forgotten, or the ChainNotifier is shutting down.
+func (f *fundingManager) waitForConf(completeChan *channeldb.OpenChannel,
+	confNtfn *chainntnfs.ConfirmationEvent,
+	epochs <-chan *chainntnfs.BlockEpoch) *chainntnfs.TxConfirmation {
+
+	txid := completeChan.FundingOutpoint.Hash
+	numConfs := uint32(completeChan.NumConfsRequired)
+
+	fndgLog.Infof("Waiting for funding tx (%v) to reach %v confirmations",
+		txid, numConfs)
+
+	// If we initiated the channel, then forgetAt is zero, and we'll wait
+	// for as long as it takes.
+	forgetAt := forgetHeight(completeChan)
+
+	// Wait until the specified number of confirmations has been reached,
+	// or the wallet signals a shutdown. If the funding transaction is
+	// re-org'd out before then, the notification is re-armed by the
+	// notifier, so we continue to wait for it to be re-confirmed.
+	for {
+		select {
+		case epoch, ok := <-epochs:
+			if !ok {
+				fndgLog.Warnf("ChainNotifier shutting down, cannot "+
+					"complete funding flow for ChannelPoint(%v)",
+					completeChan.FundingOutpoint)
+				return nil
+			}
+
+			if forgetAt == 0 || uint32(epoch.Height) < forgetAt {
+				continue
+			}
+
+			fndgLog.Warnf("Funding tx (%v) remains unconfirmed at "+
+				"height %v, forgetting ChannelPoint(%v)", txid,
+				epoch.Height, completeChan.FundingOutpoint)
+
+			f.forgetPendingChannel(completeChan)
+			return nil
+
+		case details, ok := <-confNtfn.Confirmed:
+			if !ok {
+				fndgLog.Warnf("ChainNotifier shutting down, cannot "+
+					"complete funding flow for ChannelPoint(%v)",
+					completeChan.FundingOutpoint)
+				return nil
+			}
+			return details
+
+		case depth, ok := <-confNtfn.NegativeConf:
+			if !ok {
+				fndgLog.Warnf("ChainNotifier shutting down, cannot "+
+					"complete funding flow for ChannelPoint(%v)",
+					completeChan.FundingOutpoint)
+				return nil
+			}
+
+			fndgLog.Warnf("Funding tx (%v) re-org'd out of the main "+
+				"chain (depth=%v), waiting for it to reach %v "+
+				"confirmations once again", txid, depth, numConfs)
+
+		case <-f.quit:
+			return nil
+		}
+	}
+}
+
+// markChannelOpen marks the passed channel as open within the database once
+// its funding transaction has confirmed, recording the channel's short
+// channel ID. False is returned if the channel couldn't be marked open.
+func (f *fundingManager) markChannelOpen(completeChan *channeldb.OpenChannel,
+	confDetails *chainntnfs.TxConfirmation) bool {
+
+	fundingPoint := *completeChan.FundingOutpoint
+	chanID := lnwire.NewChanIDFromOutPoint(&fundingPoint)
+
+	fndgLog.Infof("ChannelPoint(%v) is now active: ChannelID(%x)",
+		fundingPoint, chanID)
+
+	// With the block height and the transaction index known, we can
+	// construct the compact chanID which is used on the network to unique
+	// identify channels.
+	shortChanID := lnwire.ShortChannelID{
+		BlockHeight: confDetails.BlockHeight,
+		TxIndex:     confDetails.TxIndex,
+		TxPosition:  uint16(fundingPoint.Index),
+	}
+
+	// Now that the channel has been fully confirmed, we'll mark it as open
+	// within the database, recording its short channel ID so the rest of
+	// the funding flow can be resumed should we restart before it
+	// completes.
+	err := f.cfg.Wallet.ChannelDB.MarkChannelAsOpen(&fundingPoint,
+		shortChanID)
+	if err != nil {
+		fndgLog.Errorf("error setting channel pending flag to false: "+
+			"%v", err)
+		return false
+	}
+	completeChan.IsPending = false
+	completeChan.FundingState = channeldb.FundingConfirmed
+	completeChan.ShortChanID = shortChanID
+
+	return true
+}
+
+// waitForFundingReorg watches for the funding transaction of the passed open
+// channel to be re-org'd out of the main chain, until the best height reaches
+// safeHeight. If the funding transaction is re-org'd out, then the channel is
+// rolled back to pending and true is returned, signalling that we must wait
+// for the funding transaction to confirm once again.
+func (f *fundingManager) waitForFundingReorg(completeChan *channeldb.OpenChannel,
+	confNtfn *chainntnfs.ConfirmationEvent,
+	epochs <-chan *chainntnfs.BlockEpoch, safeHeight uint32) bool {
+
+	for {
+		select {
+		case epoch, ok := <-epochs:
+			if !ok {
+				return false
+			}
+
+			if uint32(epoch.Height) >= safeHeight {
+				return false
+			}
+
+		case depth, ok := <-confNtfn.NegativeConf:
+			if !ok {
+				return false
+			}
+
+			fndgLog.Warnf("Funding tx (%v) of open ChannelPoint(%v) "+
+				"re-org'd out of the main chain (depth=%v), "+
+				"reverting channel to pending",
+				completeChan.FundingOutpoint.Hash,
+				completeChan.FundingOutpoint, depth)
+
+			return f.rollbackChannelOpen(completeChan)
+
+		case <-f.quit:
+			return false
+		}
+	}
+}
+
+// rollbackChannelOpen reverts an open channel whose funding transaction has
+// been re-org'd out of the main chain back to pending. The channel's link is
+// torn down and its edge removed from the channel graph, as its short channel
+// ID is no longer valid. A new barrier is created for the channel, which is
+// closed once the channel is re-opened. False is returned if the channel
+// couldn't be reverted.
+func (f *fundingManager) rollbackChannelOpen(completeChan *channeldb.OpenChannel) bool {
+	fundingPoint := completeChan.FundingOutpoint
+	chanID := lnwire.NewChanIDFromOutPoint(fundingPoint)
+
+	err := f.cfg.Wallet.ChannelDB.MarkChannelAsPending(fundingPoint)
+	if err != nil {
+		fndgLog.Errorf("unable to revert ChannelPoint(%v) to "+
+			"pending: %v", fundingPoint, err)
+		return false
+	}
+	completeChan.IsPending = true
+	completeChan.FundingState = channeldb.FundingBroadcast
+	completeChan.ShortChanID = lnwire.ShortChannelID{}
+
+	f.barrierMtx.Lock()
+	if _, ok := f.newChanBarriers[chanID]; !ok {
+		f.newChanBarriers[chanID] = make(chan struct{})
+	}
+	f.barrierMtx.Unlock()
+
+	if peer, err := f.cfg.FindPeer(completeChan.IdentityPub); err == nil {
+		peer.removeLink(chanID)
+	}
+
+	graph := f.cfg.Wallet.ChannelDB.ChannelGraph()
+	err = graph.DeleteChannelEdge(fundingPoint)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		fndgLog.Errorf("unable to remove edge of ChannelPoint(%v) "+
+			"from graph: %v", fundingPoint, err)
+	}
 
-	// The remote peer has responded with a signature for our commitment
-	// transaction. We'll verify the signature for validity, then commit
-	// the state to disk as we can now open the channel.
-	commitSig := fmsg.msg.CommitSignature.Serialize()
-	completeChan, err := resCtx.reservation.CompleteReservation(nil, commitSig)
-	if err != nil {
-		fndgLog.Errorf("unable to complete reservation sign complete: %v", err)
-		resCtx.err <- err
+	return true
+}
 
-		if _, err := f.cancelReservationCtx(peerKey, chanID); err != nil {
-			fndgLog.Errorf("unable to cancel reservation: %v", err)
+// resumeFunding requests that the reservationCoordinator resume the funding
+// flow of a confirmed channel.
+func (f *fundingManager) resumeFunding(msg *resumeFundingMsg) {
+	select {
+	case f.fundingMsgs <- msg:
+	case <-f.quit:
+	}
+}
+
+// handleResumeFunding launches the resumed funding flow of a channel, unless
+// it's already being resumed. In that case, the flow is resumed once more
+// after the current attempt exits, as the attempt may have halted before the
+// state which prompted the request was reached.
+func (f *fundingManager) handleResumeFunding(msg *resumeFundingMsg) {
+	if queued, ok := f.resumingChans[msg.chanPoint]; ok {
+		if queued != nil && queued.resendLocked {
+			msg.resendLocked = true
 		}
+		f.resumingChans[msg.chanPoint] = msg
 		return
 	}
 
-	fundingPoint := resCtx.reservation.FundingOutpoint()
-	fndgLog.Infof("Finalizing pendingID(%x) over ChannelPoint(%v), "+
-		"waiting for channel open on-chain", chanID, fundingPoint)
+	f.resumingChans[msg.chanPoint] = nil
 
-	// Send an update to the upstream client that the negotiation process
-	// is over.
-	// TODO(roasbeef): add abstraction over updates to accommodate
-	// long-polling, or SSE, etc.
-	resCtx.updates <- &lnrpc.OpenStatusUpdate{
-		Update: &lnrpc.OpenStatusUpdate_ChanPending{
-			ChanPending: &lnrpc.PendingUpdate{
-				Txid:        fundingPoint.Hash[:],
-				OutputIndex: fundingPoint.Index,
-			},
-		},
-	}
+	f.wg.Add(1)
+	go f.resumeFundingFlow(msg)
+}
 
-	go func() {
-		doneChan := make(chan struct{})
-		go f.waitForFundingConfirmation(completeChan, doneChan)
+// handleResumeDone handles the exit of a resumed funding flow, resuming it
+// once more if requested in the meantime.
+func (f *fundingManager) handleResumeDone(msg *resumeDoneMsg) {
+	queued := f.resumingChans[msg.chanPoint]
+	delete(f.resumingChans, msg.chanPoint)
+
+	if queued != nil {
+		f.handleResumeFunding(queued)
+	}
+}
 
+// resumeFundingFlow carries a confirmed channel through the remaining stages
+// of the funding flow, starting from the stage recorded within the database:
+// first sending FundingLocked to the remote party, then announcing the
+// channel. Each stage is recorded once complete, so that after a restart the
+// flow picks up from where it left off. If FundingLocked can't be sent, then
+// the flow is halted until the remote party next connects.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *fundingManager) resumeFundingFlow(msg *resumeFundingMsg) {
+	defer f.wg.Done()
+	defer func() {
 		select {
+		case f.fundingMsgs <- &resumeDoneMsg{msg.chanPoint}:
 		case <-f.quit:
+		}
+	}()
+
+	fundingPoint := &msg.chanPoint
+	db := f.cfg.Wallet.ChannelDB
+
+	// The channel is read from the database each time the flow is resumed,
+	// as a prior attempt may have progressed it since the request was
+	// made.
+	channel, err := f.fetchOpenChannel(msg.peerKey, fundingPoint)
+	if err != nil {
+		fndgLog.Errorf("unable to resume funding flow for "+
+			"ChannelPoint(%v): %v", fundingPoint, err)
+		return
+	}
+
+	fndgLog.Debugf("Resuming funding flow for ChannelPoint(%v) from "+
+		"state %v", fundingPoint, channel.FundingState)
+
+	switch {
+	case channel.FundingState == channeldb.FundingConfirmed:
+		if err := f.sendFundingLocked(channel); err != nil {
+			fndgLog.Errorf("unable to send FundingLocked for "+
+				"ChannelPoint(%v), will retry once peer "+
+				"reconnects: %v", fundingPoint, err)
 			return
-		case <-doneChan:
 		}
 
-		// Finally give the caller a final update notifying them that
-		// the channel is now open.
-		// TODO(roasbeef): only notify after recv of funding locked?
-		resCtx.updates <- &lnrpc.OpenStatusUpdate{
-			Update: &lnrpc.OpenStatusUpdate_ChanOpen{
-				ChanOpen: &lnrpc.ChannelOpenUpdate{
-					ChannelPoint: &lnrpc.ChannelPoint{
-						FundingTxid: fundingPoint.Hash[:],
-						OutputIndex: fundingPoint.Index,
-					},
-				},
-			},
+		err := db.UpdateFundingState(fundingPoint,
+			channeldb.FundingLockedSent)
+		if err != nil {
+			fndgLog.Errorf("unable to update funding state: %v", err)
+			return
 		}
+		channel.FundingState = channeldb.FundingLockedSent
 
-		f.deleteReservationCtx(peerKey, fmsg.msg.PendingChannelID)
-	}()
-}
+	// Once the channel has been used, the remote party must have received
+	// FundingLocked, so there's no need to send it again.
+	case msg.resendLocked && channel.NumUpdates == 0:
+		fndgLog.Debugf("Re-sending FundingLocked for ChannelPoint(%v)",
+			fundingPoint)
 
-// waitForFundingConfirmation handles the final stages of the channel funding
-// process once the funding transaction has been broadcast. The primary
-// function of waitForFundingConfirmation is to wait for blockchain
-// confirmation, and then to notify the other systems that must be notified
-// when a channel has become active for lightning transactions.
-func (f *fundingManager) waitForFundingConfirmation(completeChan *channeldb.OpenChannel,
-	doneChan chan struct{}) {
+		if err := f.sendFundingLocked(channel); err != nil {
+			fndgLog.Errorf("unable to re-send FundingLocked for "+
+				"ChannelPoint(%v): %v", fundingPoint, err)
+		}
+	}
 
-	defer close(doneChan)
+	if channel.FundingState == channeldb.FundingLockedSent {
+		fndgLog.Infof("Announcing ChannelPoint(%v), short_chan_id=%v, "+
+			"private=%v", fundingPoint, spew.Sdump(channel.ShortChanID),
+			channel.IsPrivate)
 
-	// Register with the ChainNotifier for a notification once the funding
-	// transaction reaches `numConfs` confirmations.
-	txid := completeChan.FundingOutpoint.Hash
-	numConfs := uint32(completeChan.NumConfsRequired)
-	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(&txid, numConfs)
-	if err != nil {
-		fndgLog.Errorf("Unable to register for confirmation of "+
-			"ChannelPoint(%v)", completeChan.FundingOutpoint)
-		return
+		// Register the new link with the L3 routing manager so this
+		// new channel can be utilized during path finding. The
+		// announcement is only recorded once the gossiper has
+		// processed it, so it's retried after a restart otherwise.
+		chanID := lnwire.NewChanIDFromOutPoint(fundingPoint)
+		err := f.announceChannel(f.cfg.IDKey, channel.IdentityPub,
+			channel.OurMultiSigKey, channel.TheirMultiSigKey,
+			channel.ShortChanID, chanID, channel.IsPrivate)
+		if err != nil {
+			fndgLog.Errorf("unable to announce ChannelPoint(%v): %v",
+				fundingPoint, err)
+			return
+		}
+
+		err = db.UpdateFundingState(fundingPoint,
+			channeldb.FundingAnnounced)
+		if err != nil {
+			fndgLog.Errorf("unable to update funding state: %v", err)
+			return
+		}
 	}
+}
 
-	fndgLog.Infof("Waiting for funding tx (%v) to reach %v confirmations",
-		txid, numConfs)
+// fetchOpenChannel retrieves the channel with the passed peer, funded by the
+// passed outpoint, from the database.
+func (f *fundingManager) fetchOpenChannel(peerKey *btcec.PublicKey,
+	chanPoint *wire.OutPoint) (*channeldb.OpenChannel, error) {
 
-	// Wait until the specified number of confirmations has been reached,
-	// or the wallet signals a shutdown.
-	confDetails, ok := <-confNtfn.Confirmed
-	if !ok {
-		fndgLog.Warnf("ChainNotifier shutting down, cannot complete "+
-			"funding flow for ChannelPoint(%v)",
-			completeChan.FundingOutpoint)
-		return
+	channels, err := f.cfg.Wallet.ChannelDB.FetchOpenChannels(peerKey)
+	if err != nil {
+		return nil, err
 	}
 
-	fundingPoint := *completeChan.FundingOutpoint
-	chanID := lnwire.NewChanIDFromOutPoint(&fundingPoint)
+	for _, channel := range channels {
+		if *channel.FundingOutpoint == *chanPoint {
+			return channel, nil
+		}
+	}
 
-	fndgLog.Infof("ChannelPoint(%v) is now active: ChannelID(%x)",
-		fundingPoint, chanID)
+	return nil, errors.Errorf("unable to find ChannelPoint(%v)", chanPoint)
+}
 
-	// Now that the channel has been fully confirmed, we'll mark it as open
-	// within the database.
-	completeChan.IsPending = false
-	err = f.cfg.Wallet.ChannelDB.MarkChannelAsOpen(&fundingPoint)
+// sendFundingLocked sends the FundingLocked message for the passed channel to
+// the remote party. The message marks that we consider the channel open by
+// presenting the remote party with our next revocation key. Without the
+// revocation key, the remote party will be unable to propose state
+// transitions.
+func (f *fundingManager) sendFundingLocked(channel *channeldb.OpenChannel) error {
+	// We'll create the state-machine object which wraps the database
+	// state in order to derive our next revocation key.
+	lnChannel, err := lnwallet.NewLightningChannel(nil, nil, channel)
 	if err != nil {
-		fndgLog.Errorf("error setting channel pending flag to false: "+
-			"%v", err)
-		return
+		return err
 	}
-
-	// With the channel marked open, we'll create the state-machine object
-	// which wraps the database state.
-	channel, err := lnwallet.NewLightningChannel(nil, nil, completeChan)
+	nextRevocation, err := lnChannel.NextRevocationkey()
 	if err != nil {
-		fndgLog.Errorf("error creating new lightning channel: %v", err)
-		return
+		return err
 	}
 
-	// Next, we'll send over the funding locked message which marks that we
-	// consider the channel open by presenting the remote party with our
-	// next revocation key. Without the revocation key, the remote party
-	// will be unable to propose state transitions.
-	nextRevocation, err := channel.NextRevocationkey()
+	chanID := lnwire.NewChanIDFromOutPoint(channel.FundingOutpoint)
+	fundingLockedMsg := lnwire.NewFundingLocked(chanID, nextRevocation)
+	return f.cfg.SendToPeer(channel.IdentityPub, fundingLockedMsg)
+}
+
+// peerConnected resumes the funding flow of each channel with the newly
+// connected peer which has yet to complete it, as we may have been unable to
+// reach the peer when the channel was confirmed. FundingLocked is also sent
+// once more for each channel which is yet to be used, as the peer may not
+// have processed it before going down.
+func (f *fundingManager) peerConnected(peerKey *btcec.PublicKey) {
+	channels, err := f.cfg.Wallet.ChannelDB.FetchOpenChannels(peerKey)
 	if err != nil {
-		fndgLog.Errorf("unable to create next revocation: %v", err)
+		fndgLog.Errorf("unable to fetch channels for peer(%x): %v",
+			peerKey.SerializeCompressed(), err)
 		return
 	}
-	fundingLockedMsg := lnwire.NewFundingLocked(chanID, nextRevocation)
-	f.cfg.SendToPeer(completeChan.IdentityPub, fundingLockedMsg)
 
-	// With the block height and the transaction index known, we can
-	// construct the compact chanID which is used on the network to unique
-	// identify channels.
-	shortChanID := lnwire.ShortChannelID{
-		BlockHeight: confDetails.BlockHeight,
-		TxIndex:     confDetails.TxIndex,
-		TxPosition:  uint16(fundingPoint.Index),
-	}
+	for _, channel := range channels {
+		if channel.IsPending {
+			continue
+		}
+		if channel.FundingState == channeldb.FundingAnnounced &&
+			channel.NumUpdates != 0 {
 
-	fndgLog.Infof("Announcing ChannelPoint(%v), short_chan_id=%v", fundingPoint,
-		spew.Sdump(shortChanID))
+			continue
+		}
 
-	// Register the new link with the L3 routing manager so this new
-	// channel can be utilized during path finding.
-	go f.announceChannel(f.cfg.IDKey, completeChan.IdentityPub,
-		channel.LocalFundingKey, channel.RemoteFundingKey,
-		shortChanID, chanID)
-	return
+		f.resumeFunding(&resumeFundingMsg{
+			peerKey:      peerKey,
+			chanPoint:    *channel.FundingOutpoint,
+			resendLocked: true,
+		})
+	}
 }
 
 // processFundingLocked sends a message to the fundingManager allowing it to finish
@@ -983,6 +2448,42 @@ func (f *fundingManager) processFundingLocked(msg *lnwire.FundingLocked,
 	f.fundingMsgs <- &fundingLockedMsg{msg, peerAddress}
 }
 
+// forgetHeight returns the height at which the passed pending channel will be
+// forgotten if its funding transaction has yet to confirm. Only channels
+// initiated by the remote peer are ever forgotten, as we aren't in control of
+// their funding transaction. Zero is returned for all other channels, or if
+// forgetting channels has been disabled.
+func forgetHeight(channel *channeldb.OpenChannel) uint32 {
+	if channel.IsInitiator || cfg.MaxPendingBlocks == 0 {
+		return 0
+	}
+
+	return channel.FundingBroadcastHeight + cfg.MaxPendingBlocks
+}
+
+// forgetPendingChannel removes all trace of a pending channel whose funding
+// transaction has failed to confirm in time. As the remote peer initiated the
+// channel, none of our funds are at risk.
+func (f *fundingManager) forgetPendingChannel(channel *channeldb.OpenChannel) {
+	if err := channel.CloseChannel(); err != nil {
+		fndgLog.Errorf("Unable to forget ChannelPoint(%v): %v",
+			channel.FundingOutpoint, err)
+		return
+	}
+
+	// Close the channel's barrier so nothing is left waiting on a channel
+	// which will now never open.
+	chanID := lnwire.NewChanIDFromOutPoint(channel.FundingOutpoint)
+	f.barrierMtx.Lock()
+	if barrier, ok := f.newChanBarriers[chanID]; ok {
+		close(barrier)
+		delete(f.newChanBarriers, chanID)
+	}
+	f.barrierMtx.Unlock()
+
+	f.cfg.UpdateChannelBackups()
+}
+
 // handleFundingLocked finalizes the channel funding process and enables the channel
 // to enter normal operating mode.
 func (f *fundingManager) handleFundingLocked(fmsg *fundingLockedMsg) {
@@ -997,32 +2498,51 @@ func (f *fundingManager) handleFundingLocked(fmsg *fundingLockedMsg) {
 		return
 	}
 
-	// With the channel retrieved, we'll send the breach arbiter the new
-	// channel so it can watch for attempts to breach the channel's
-	// contract by the remote
-	// party.
-	f.cfg.ArbiterChan <- channel
-
 	// Launch a defer so we _ensure_ that the channel barrier is properly
 	// closed even if the target peer is not longer online at this point.
 	defer func() {
 		// Close the active channel barrier signalling the readHandler
 		// that commitment related modifications to this channel can
-		// now proceed.
+		// now proceed. The barrier may have already been closed if
+		// this is a retransmission of a FundingLocked we've already
+		// processed.
 		f.barrierMtx.Lock()
-		fndgLog.Tracef("Closing chan barrier for ChanID(%v)", chanID)
-		close(f.newChanBarriers[chanID])
-		delete(f.newChanBarriers, chanID)
+		if barrier, ok := f.newChanBarriers[chanID]; ok {
+			fndgLog.Tracef("Closing chan barrier for ChanID(%v)",
+				chanID)
+			close(barrier)
+			delete(f.newChanBarriers, chanID)
+		}
 		f.barrierMtx.Unlock()
 	}()
 
-	// Finally, we'll find the peer that sent us this message so we can
+	// Next, we'll find the peer that sent us this message so we can
 	// provide it with the fully initialized channel state.
 	peer, err := f.cfg.FindPeer(fmsg.peerAddress.IdentityKey)
 	if err != nil {
 		fndgLog.Errorf("Unable to find peer: %v", err)
 		return
 	}
+
+	// As FundingLocked is retransmitted by the remote party if it wasn't
+	// able to confirm it was sent, the channel may already be active
+	// within the peer, having been loaded from the database when the
+	// peer connected. In that case, there's nothing left to do.
+	peer.activeChanMtx.RLock()
+	_, ok := peer.activeChannels[chanID]
+	peer.activeChanMtx.RUnlock()
+	if ok {
+		fndgLog.Debugf("ChannelID(%v) already active, ignoring "+
+			"FundingLocked", chanID)
+		return
+	}
+
+	// With the channel retrieved, we'll send the breach arbiter the new
+	// channel so it can watch for attempts to breach the channel's
+	// contract by the remote
+	// party.
+	f.cfg.ArbiterChan <- channel
+
 	newChanDone := make(chan struct{})
 	newChanMsg := &newChannelMsg{
 		channel: channel,
@@ -1175,20 +2695,50 @@ func (f *fundingManager) newChanAnnouncement(localPubKey, remotePubKey *btcec.Pu
 // the network to recognize the legitimacy of the channel. The crafted
 // announcements are then sent to the channel router to handle broadcasting to
 // the network during its next trickle.
+//
+// If the channel is private, then the announcement proof isn't exchanged with
+// the remote party. Without the proof, the channel is only added to our own
+// view of the network, allowing it to be used during path finding, but is
+// never broadcast.
 func (f *fundingManager) announceChannel(localIDKey, remoteIDKey, localFundingKey,
 	remoteFundingKey *btcec.PublicKey, shortChanID lnwire.ShortChannelID,
-	chanID lnwire.ChannelID) {
+	chanID lnwire.ChannelID, private bool) error {
 
 	ann, err := f.newChanAnnouncement(localIDKey, remoteIDKey, localFundingKey,
 		remoteFundingKey, shortChanID, chanID)
 	if err != nil {
-		fndgLog.Errorf("can't generate channel announcement: %v", err)
-		return
+		return errors.Errorf("can't generate channel announcement: %v",
+			err)
+	}
+
+	if err := f.sendAnnouncement(ann.chanAnn); err != nil {
+		return err
+	}
+	if err := f.sendAnnouncement(ann.chanUpdateAnn); err != nil {
+		return err
+	}
+	if private {
+		return nil
 	}
+	return f.sendAnnouncement(ann.chanProof)
+}
+
+// sendAnnouncement hands the passed announcement to the gossiper, blocking
+// until it's been processed. As a channel may be announced once more when
+// its funding flow is resumed, announcements the gossiper already knows of
+// aren't treated as an error.
+func (f *fundingManager) sendAnnouncement(msg lnwire.Message) error {
+	select {
+	case err := <-f.cfg.SendAnnouncement(msg):
+		if routing.IsError(err, routing.ErrOutdated, routing.ErrIgnored) {
+			fndgLog.Debugf("Announcement already known: %v", err)
+			return nil
+		}
+		return err
 
-	f.cfg.SendAnnouncement(ann.chanAnn)
-	f.cfg.SendAnnouncement(ann.chanUpdateAnn)
-	f.cfg.SendAnnouncement(ann.chanProof)
+	case <-f.quit:
+		return errors.New("funding manager shutting down")
+	}
 }
 
 // initFundingWorkflow sends a message to the funding manager instructing it
@@ -1221,17 +2771,103 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		msg.pushAmt, capacity, numConfs, msg.peerAddress.Address,
 		ourDustLimit)
 
+	// If another channel of this channel's batch has already failed, then
+	// there's no point in proceeding.
+	if msg.batch != nil && msg.batch.failed {
+		msg.err <- errors.Errorf("another channel of the batch failed")
+		return
+	}
+
+	// A private channel can only be opened with a peer that has signalled
+	// the extended-funding feature, as otherwise it's unable to receive
+	// the channel flags, and would announce the channel regardless.
+	extended := f.peerSupportsExtendedFunding(peerKey)
+	if msg.private && !extended {
+		err := errors.Errorf("unable to open private channel: peer %x "+
+			"doesn't support extended funding",
+			peerKey.SerializeCompressed())
+		msg.err <- err
+		if msg.batch != nil {
+			f.failBatch(msg.batch, nil, err)
+		}
+		return
+	}
+
 	// Initialize a funding reservation with the local wallet. If the
 	// wallet doesn't have enough funds to commit to this channel, then
-	// the request will fail, and be aborted.
-	reservation, err := f.cfg.Wallet.InitChannelReservation(capacity,
-		localAmt, peerKey, msg.peerAddress.Address, uint16(numConfs), 4,
-		ourDustLimit, msg.pushAmt)
+	// the request will fail, and be aborted. If the channel is to be
+	// funded via a PSBT, then no coins of our own will be selected.
+	var (
+		reservation *lnwallet.ChannelReservation
+		err         error
+	)
+	if msg.psbtFunding {
+		reservation, err = f.cfg.Wallet.InitExternalFundingReservation(
+			capacity, localAmt, peerKey, msg.peerAddress.Address,
+			uint16(numConfs), 4, ourDustLimit, msg.pushAmt)
+	} else {
+		reservation, err = f.cfg.Wallet.InitChannelReservation(capacity,
+			localAmt, peerKey, msg.peerAddress.Address,
+			uint16(numConfs), 4, ourDustLimit, msg.pushAmt,
+			msg.coinSelectOpts)
+	}
 	if err != nil {
 		msg.err <- err
+		if msg.batch != nil {
+			f.failBatch(msg.batch, nil, err)
+		}
 		return
 	}
 
+	// The funding transaction of a batch is broadcast once every channel
+	// has been completed, rather than by each reservation.
+	if msg.batch != nil {
+		reservation.DeferFundingBroadcast()
+	}
+
+	// If all of our eligible coins are being committed to the channel,
+	// then the amount we're funding is only known now that coin selection
+	// has taken place.
+	if msg.coinSelectOpts != nil && msg.coinSelectOpts.SpendAll {
+		localAmt = reservation.OurFundingAmt()
+		capacity = localAmt + remoteAmt
+
+		fndgLog.Infof("Committing all eligible coins to fundingRequest, "+
+			"localAmt=%v, capacity=%v", localAmt, capacity)
+
+		var err error
+		switch {
+		case localAmt < minChannelSize:
+			err = errors.Errorf("channel of %v is too small, the "+
+				"minimum channel size is: %v (6k sat)", localAmt,
+				minChannelSize)
+
+		case msg.pushAmt >= localAmt:
+			err = errors.Errorf("amount pushed to remote peer "+
+				"for initial state must be below the local "+
+				"funding amount of %v", localAmt)
+		}
+		if err != nil {
+			if err := reservation.Cancel(); err != nil {
+				fndgLog.Errorf("unable to cancel reservation: %v",
+					err)
+			}
+			msg.err <- err
+			return
+		}
+	}
+
+	// We'll impose the default set of constraints upon the responder,
+	// sending them over within the funding request. If the responder
+	// lacks the extended-funding feature, then it's unable to receive
+	// them, so the channel will be without constraints on both sides.
+	ourConstraints := legacyChanConstraints(capacity)
+	if extended {
+		ourConstraints = defaultChanConstraints(capacity)
+	}
+	reservation.SetOurConstraints(ourConstraints)
+	reservation.SetPrivate(msg.private)
+
 	// Obtain a new pending channel ID which is used to track this
 	// reservation throughout its lifetime.
 	chanID := f.nextPendingChanID()
@@ -1245,14 +2881,22 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		f.activeReservations[peerIDKey] = make(pendingChannels)
 	}
 
-	f.activeReservations[peerIDKey][chanID] = &reservationWithCtx{
+	resCtx := &reservationWithCtx{
 		reservation: reservation,
 		peerAddress: msg.peerAddress,
+		psbtFunding: msg.psbtFunding,
+		batch:       msg.batch,
+		lastUpdated: time.Now(),
 		updates:     msg.updates,
 		err:         msg.err,
 	}
+	f.activeReservations[peerIDKey][chanID] = resCtx
 	f.resMtx.Unlock()
 
+	if msg.batch != nil {
+		msg.batch.chans[chanID] = resCtx
+	}
+
 	// Once the reservation has been created, and indexed, queue a funding
 	// request to the remote peer, kicking off the funding workflow.
 	contribution := reservation.OurContribution()
@@ -1260,19 +2904,36 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	if err != nil {
 		fndgLog.Errorf("Unable to convert address to pkscript: %v", err)
 		msg.err <- err
+		if msg.batch != nil {
+			f.failBatch(msg.batch, resCtx, err)
+		}
 		return
 	}
 
 	fndgLog.Infof("Starting funding workflow with %v for pendingID(%x)",
 		msg.peerAddress.Address, chanID)
 
+	// Consult the fee estimator for the fee rate we'll propose for the
+	// commitment transaction. The funding request expresses the rate in
+	// satoshis per kilo-vbyte, so we'll scale the estimate accordingly.
+	feePerWeight, err := f.cfg.FeeEstimator.EstimateFeePerWeight(
+		lnwallet.FundingTxConfTarget)
+	if err != nil {
+		fndgLog.Errorf("Unable to estimate fee rate: %v", err)
+		msg.err <- err
+		if msg.batch != nil {
+			f.failBatch(msg.batch, resCtx, err)
+		}
+		return
+	}
+	feePerKb := feePerWeight * 4000
+
 	// TODO(roasbeef): add FundingRequestFromContribution func
-	// TODO(roasbeef): need to set fee/kb
 	fundingReq := lnwire.NewSingleFundingRequest(
 		chanID,
 		msg.channelType,
 		msg.coinType,
-		0, // TODO(roasbeef): grab from fee estimation model
+		feePerKb,
 		capacity,
 		contribution.CsvDelay,
 		contribution.CommitKey,
@@ -1282,9 +2943,25 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		msg.pushAmt,
 		numConfs,
 	)
+	if extended {
+		fundingReq.Extended = true
+		fundingReq.MaxValueInFlight = ourConstraints.MaxPendingAmount
+		fundingReq.ChannelReserve = ourConstraints.ChanReserve
+		fundingReq.MaxAcceptedHTLCs = ourConstraints.MaxAcceptedHtlcs
+	}
+
+	// Unless the channel is to be private, we'll signal to the responder
+	// that we wish to announce it to the rest of the network.
+	if !msg.private {
+		fundingReq.ChannelFlags |= lnwire.FFAnnounceChannel
+	}
+
 	if err := f.cfg.SendToPeer(peerKey, fundingReq); err != nil {
 		fndgLog.Errorf("Unable to send funding request message: %v", err)
 		msg.err <- err
+		if msg.batch != nil {
+			f.failBatch(msg.batch, resCtx, err)
+		}
 		return
 	}
 }
@@ -1328,12 +3005,20 @@ func (f *fundingManager) handleErrorMsg(fmsg *fundingErrorMsg) {
 	case lnwire.ErrMaxPendingChannels:
 		fallthrough
 	case lnwire.ErrSynchronizingChain:
+		fallthrough
+	case lnwire.ErrUnacceptableConstraints:
+		fallthrough
+	case lnwire.ErrChannelRejected:
+		fallthrough
+	case lnwire.ErrFundingTimeout:
 		peerKey := fmsg.peerAddress.IdentityKey
 		chanID := fmsg.err.ChanID
+		// The reservation may have already been cancelled on our
+		// end, for example if both sides timed out the funding flow
+		// at the same time, in which case there's nothing left to do.
 		ctx, err := f.cancelReservationCtx(peerKey, chanID)
 		if err != nil {
 			fndgLog.Warnf("unable to delete reservation: %v", err)
-			ctx.err <- err
 			return
 		}
 
@@ -1371,6 +3056,14 @@ func (f *fundingManager) cancelReservationCtx(peerKey *btcec.PublicKey,
 	}
 
 	f.deleteReservationCtx(peerKey, chanID)
+
+	// If the channel is part of a batch, then the remaining channels of
+	// the batch can no longer be funded.
+	if ctx.batch != nil {
+		f.failBatch(ctx.batch, ctx, errors.Errorf("channel %x of "+
+			"batch failed", chanID))
+	}
+
 	return ctx, nil
 }
 
@@ -1386,6 +3079,23 @@ func (f *fundingManager) deleteReservationCtx(peerKey *btcec.PublicKey,
 	f.resMtx.Unlock()
 }
 
+// getReservationCtxByPendingID returns the reservation context with the passed
+// pending channel id, regardless of the peer it's with.
+func (f *fundingManager) getReservationCtxByPendingID(
+	chanID [32]byte) (*reservationWithCtx, error) {
+
+	f.resMtx.RLock()
+	defer f.resMtx.RUnlock()
+
+	for _, pendingChans := range f.activeReservations {
+		if resCtx, ok := pendingChans[chanID]; ok {
+			return resCtx, nil
+		}
+	}
+
+	return nil, errors.Errorf("unknown pending channel (id: %x)", chanID)
+}
+
 // getReservationCtx returns the reservation context by peer id and channel id.
 func (f *fundingManager) getReservationCtx(peerKey *btcec.PublicKey,
 	chanID [32]byte) (*reservationWithCtx, error) {
@@ -1409,3 +3119,62 @@ func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
 		Y:     pub.Y,
 	}
 }
+
+// defaultChanConstraints returns the default set of constraints we'll impose
+// upon the remote party of a channel with the target capacity.
+func defaultChanConstraints(capacity btcutil.Amount) channeldb.ChannelConstraints {
+	return channeldb.ChannelConstraints{
+		MaxPendingAmount: lnwallet.DefaultMaxPendingAmount(capacity),
+		ChanReserve:      lnwallet.DefaultChanReserve(capacity),
+		MaxAcceptedHtlcs: lnwallet.DefaultMaxAcceptedHTLCs,
+	}
+}
+
+// legacyChanConstraints returns the constraints in effect for a channel of the
+// target capacity with a peer lacking the extended-funding feature. As such a
+// peer neither sends nor observes constraints, the channel is only limited by
+// the protocol itself.
+func legacyChanConstraints(capacity btcutil.Amount) channeldb.ChannelConstraints {
+	return channeldb.ChannelConstraints{
+		MaxPendingAmount: capacity,
+		ChanReserve:      0,
+		MaxAcceptedHtlcs: lnwallet.MaxHTLCNumber / 2,
+	}
+}
+
+// peerSupportsExtendedFunding returns true if the passed peer has signalled
+// the extended-funding feature, and is therefore able to receive the optional
+// trailing fields of the funding messages.
+func (f *fundingManager) peerSupportsExtendedFunding(peerKey *btcec.PublicKey) bool {
+	peer, err := f.cfg.FindPeer(peerKey)
+	if err != nil || peer.localSharedFeatures == nil {
+		return false
+	}
+
+	return peer.localSharedFeatures.IsActive(extendedFundingFeature)
+}
+
+// validateChanConstraints ensures the constraints the remote party wishes to
+// impose upon us within a channel of the target capacity are reasonable. We
+// reject any reserve above a fifth of the channel's capacity, as well as any
+// limits which would leave us unable to offer HTLCs at all.
+func validateChanConstraints(capacity btcutil.Amount,
+	constraints *channeldb.ChannelConstraints) error {
+
+	if constraints.ChanReserve > capacity/5 {
+		return errors.Errorf("channel reserve of %v is too large for "+
+			"capacity of %v", constraints.ChanReserve, capacity)
+	}
+
+	if constraints.MaxAcceptedHtlcs == 0 ||
+		constraints.MaxAcceptedHtlcs > lnwallet.MaxHTLCNumber/2 {
+		return errors.Errorf("max accepted htlcs of %v is invalid",
+			constraints.MaxAcceptedHtlcs)
+	}
+
+	if constraints.MaxPendingAmount == 0 {
+		return errors.Errorf("max pending amount of zero is invalid")
+	}
+
+	return nil
+}
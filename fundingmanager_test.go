@@ -0,0 +1,1085 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// fundingTestFeeRate is the fee rate, in sat/weight, returned by the
+	// fee estimator of each funding test node.
+	fundingTestFeeRate = 50
+
+	// fundingTestTimeout is the duration a test waits for the funding
+	// workflow to progress before failing.
+	fundingTestTimeout = 5 * time.Second
+)
+
+// publishController is a WalletController which can be instructed to fail to
+// publish transactions.
+type publishController struct {
+	*simchain.Wallet
+
+	// failPublish is non-zero if transactions should fail to be published.
+	failPublish int32
+}
+
+// PublishTransaction publishes the passed transaction, unless the controller
+// has been instructed to fail.
+func (p *publishController) PublishTransaction(tx *wire.MsgTx) error {
+	if atomic.LoadInt32(&p.failPublish) != 0 {
+		return fmt.Errorf("unable to publish transaction")
+	}
+
+	return p.Wallet.PublishTransaction(tx)
+}
+
+// fundingTestNode is a funding manager, backed by its own wallet and channel
+// database, whose messages are delivered directly to the funding managers of
+// its peers.
+type fundingTestNode struct {
+	t *testing.T
+
+	privKey    *btcec.PrivateKey
+	addr       *lnwire.NetAddress
+	controller *publishController
+	wallet     *lnwallet.LightningWallet
+	fundingCfg fundingConfig
+	fundingMgr *fundingManager
+
+	// offline is non-zero if messages sent to the node should fail to be
+	// delivered.
+	offline int32
+
+	// msgHook, if set, is handed each message the node sends, returning
+	// the message to be delivered in its place. It must be set before the
+	// node starts sending messages.
+	msgHook func(msg lnwire.Message) lnwire.Message
+
+	// announceHook, if set, is handed each announcement the node sends,
+	// returning the channel the result of processing it is delivered
+	// over. Otherwise, announcements are processed successfully at once.
+	// It must be set before the node starts announcing channels.
+	announceHook func(msg lnwire.Message) chan error
+
+	cleanUp func()
+}
+
+// fundingTestNet is a set of funding test nodes on a shared simulated chain.
+type fundingTestNet struct {
+	t     *testing.T
+	chain *simchain.Chain
+	nodes map[string]*fundingTestNode
+}
+
+// newFundingTestNet creates a new simulated chain, without any funding test
+// nodes.
+func newFundingTestNet(t *testing.T) *fundingTestNet {
+	cfg = &config{
+		MaxPendingChannels: defaultMaxPendingChannels,
+		ReservationTimeout: defaultReservationTimeout,
+		PsbtTimeout:        defaultPsbtTimeout,
+		MaxPendingBlocks:   defaultMaxPendingBlocks,
+	}
+
+	chain := simchain.New(&chaincfg.RegressionNetParams)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+
+	return &fundingTestNet{
+		t:     t,
+		chain: chain,
+		nodes: make(map[string]*fundingTestNode),
+	}
+}
+
+// addNode creates a new funding test node, whose wallet is derived from the
+// passed seed byte, and starts its funding manager. All nodes must be added
+// before any funding workflow is started.
+func (n *fundingTestNet) addNode(seedByte byte) *fundingTestNode {
+	t := n.t
+
+	cdb, _, cleanUpDB := newTestChannelDB(t)
+
+	simWallet, err := simchain.NewWallet(n.chain,
+		bytes.Repeat([]byte{seedByte}, 32))
+	if err != nil {
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+	controller := &publishController{Wallet: simWallet}
+	feeEstimator := lnwallet.StaticFeeEstimator{FeeRate: fundingTestFeeRate}
+	wallet, err := lnwallet.NewLightningWallet(cdb, n.chain, controller,
+		simWallet, simWallet, simWallet, feeEstimator,
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+	if err := wallet.Startup(); err != nil {
+		t.Fatalf("unable to start wallet: %v", err)
+	}
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create identity key: %v", err)
+	}
+
+	node := &fundingTestNode{
+		t:       t,
+		privKey: privKey,
+		addr: &lnwire.NetAddress{
+			IdentityKey: privKey.PubKey(),
+			Address: &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 9735 + len(n.nodes),
+			},
+			ChainNet: activeNetParams.Net,
+		},
+		controller: controller,
+		wallet:     wallet,
+	}
+
+	node.fundingCfg = fundingConfig{
+		IDKey:        privKey.PubKey(),
+		Wallet:       wallet,
+		FeeEstimator: feeEstimator,
+		Notifier:     n.chain,
+		SignMessage: func(pubKey *btcec.PublicKey,
+			msg []byte) (*btcec.Signature, error) {
+
+			if pubKey.IsEqual(privKey.PubKey()) {
+				return privKey.Sign(chainhash.DoubleHashB(msg))
+			}
+
+			return wallet.MessageSigner.SignMessage(pubKey, msg)
+		},
+		SendAnnouncement: func(msg lnwire.Message) chan error {
+			return node.announce(msg)
+		},
+		ArbiterChan:          make(chan *lnwallet.LightningChannel, 10),
+		UpdateChannelBackups: func() {},
+		AcceptChannel: func(*btcec.PublicKey,
+			*lnwire.SingleFundingRequest) (bool, string) {
+
+			return true, ""
+		},
+		SendToPeer: func(target *btcec.PublicKey,
+			msgs ...lnwire.Message) error {
+
+			return n.deliver(node, target, msgs...)
+		},
+		FindPeer: func(peerKey *btcec.PublicKey) (*peer, error) {
+			return nil, fmt.Errorf("unable to find peer")
+		},
+		FindChannel: func(chanID lnwire.ChannelID) (*lnwallet.LightningChannel, error) {
+			return nil, fmt.Errorf("unable to find channel")
+		},
+		TempChanIDSeed: [32]byte{seedByte},
+	}
+	node.startFundingMgr()
+
+	node.cleanUp = func() {
+		node.fundingMgr.Stop()
+		wallet.Shutdown()
+		cleanUpDB()
+	}
+
+	n.nodes[string(privKey.PubKey().SerializeCompressed())] = node
+
+	return node
+}
+
+// startFundingMgr creates and starts a new funding manager for the node.
+func (n *fundingTestNode) startFundingMgr() {
+	fundingMgr, err := newFundingManager(n.fundingCfg)
+	if err != nil {
+		n.t.Fatalf("unable to create funding manager: %v", err)
+	}
+	if err := fundingMgr.Start(); err != nil {
+		n.t.Fatalf("unable to start funding manager: %v", err)
+	}
+	n.fundingMgr = fundingMgr
+}
+
+// restart stops the node's funding manager, then starts a new one in its
+// place, as if the node had restarted.
+func (n *fundingTestNode) restart() {
+	if err := n.fundingMgr.Stop(); err != nil {
+		n.t.Fatalf("unable to stop funding manager: %v", err)
+	}
+	n.startFundingMgr()
+}
+
+// announce hands the passed announcement to the node's announceHook, if set,
+// returning the channel the result of processing it is delivered over.
+func (n *fundingTestNode) announce(msg lnwire.Message) chan error {
+	if n.announceHook != nil {
+		return n.announceHook(msg)
+	}
+
+	errChan := make(chan error, 1)
+	errChan <- nil
+	return errChan
+}
+
+// deliver hands the passed messages, sent by the passed node, to the funding
+// manager of the target node.
+func (n *fundingTestNet) deliver(from *fundingTestNode,
+	target *btcec.PublicKey, msgs ...lnwire.Message) error {
+
+	to, ok := n.nodes[string(target.SerializeCompressed())]
+	if !ok {
+		return fmt.Errorf("unable to find peer %x",
+			target.SerializeCompressed())
+	}
+
+	// The message hook is handed each message even if the target is
+	// offline, allowing tests to observe failed attempts to send them.
+	for _, msg := range msgs {
+		offline := atomic.LoadInt32(&to.offline) != 0
+		if from.msgHook != nil {
+			msg = from.msgHook(msg)
+		}
+		if offline {
+			return fmt.Errorf("peer %x is offline",
+				target.SerializeCompressed())
+		}
+
+		switch msg := msg.(type) {
+		case *lnwire.SingleFundingRequest:
+			to.fundingMgr.processFundingRequest(msg, from.addr)
+		case *lnwire.SingleFundingResponse:
+			to.fundingMgr.processFundingResponse(msg, from.addr)
+		case *lnwire.SingleFundingComplete:
+			to.fundingMgr.processFundingComplete(msg, from.addr)
+		case *lnwire.SingleFundingSignComplete:
+			to.fundingMgr.processFundingSignComplete(msg, from.addr)
+		case *lnwire.FundingLocked:
+			to.fundingMgr.processFundingLocked(msg, from.addr)
+		case *lnwire.Error:
+			to.fundingMgr.processFundingError(msg, from.addr)
+		default:
+			return fmt.Errorf("unexpected message %T", msg)
+		}
+	}
+
+	return nil
+}
+
+// stop shuts down every node, along with the simulated chain.
+func (n *fundingTestNet) stop() {
+	for _, node := range n.nodes {
+		node.cleanUp()
+	}
+	n.chain.Stop()
+}
+
+// fund sends a confirmed output of the passed amount to the node's wallet.
+func (n *fundingTestNet) fund(node *fundingTestNode, amt btcutil.Amount) {
+	addr, err := node.wallet.NewAddress(lnwallet.WitnessPubKey, false)
+	if err != nil {
+		n.t.Fatalf("unable to create address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		n.t.Fatalf("unable to create pkScript: %v", err)
+	}
+
+	output := &wire.TxOut{Value: int64(amt), PkScript: pkScript}
+	if _, err := n.chain.SendOutputs([]*wire.TxOut{output}, 0); err != nil {
+		n.t.Fatalf("unable to send outputs: %v", err)
+	}
+	if _, err := n.chain.Generate(1); err != nil {
+		n.t.Fatalf("unable to generate block: %v", err)
+	}
+}
+
+// openBatch starts the funding workflow of a batch of channels, funded by a
+// single funding transaction, from the node to each of the passed peers. The
+// update and error channels of each channel are returned in the order of
+// the peers.
+func (n *fundingTestNode) openBatch(peers []*fundingTestNode,
+	amt btcutil.Amount) ([]chan *lnrpc.OpenStatusUpdate, []chan error) {
+
+	batch := newFundingBatch(len(peers), nil)
+
+	updates := make([]chan *lnrpc.OpenStatusUpdate, len(peers))
+	errChans := make([]chan error, len(peers))
+	for i, peer := range peers {
+		updates[i] = make(chan *lnrpc.OpenStatusUpdate, 1)
+		errChans[i] = make(chan error, 1)
+
+		n.fundingMgr.initFundingWorkflow(peer.addr, &openChanReq{
+			targetPubkey:    peer.addr.IdentityKey,
+			localFundingAmt: amt,
+			numConfs:        1,
+			psbtFunding:     true,
+			batch:           batch,
+			updates:         updates[i],
+			err:             errChans[i],
+		})
+	}
+
+	return updates, errChans
+}
+
+// openChannel starts the funding workflow of a channel from the node to the
+// passed peer, returning the channel's funding outpoint once it's pending.
+func (n *fundingTestNode) openChannel(peer *fundingTestNode,
+	amt btcutil.Amount) *wire.OutPoint {
+
+	updates := make(chan *lnrpc.OpenStatusUpdate, 2)
+	errChan := make(chan error, 1)
+	n.fundingMgr.initFundingWorkflow(peer.addr, &openChanReq{
+		targetPubkey:    peer.addr.IdentityKey,
+		localFundingAmt: amt,
+		numConfs:        1,
+		updates:         updates,
+		err:             errChan,
+	})
+
+	select {
+	case update := <-updates:
+		pending := update.GetChanPending()
+		if pending == nil {
+			n.t.Fatalf("expected pending update, got %v", update)
+		}
+		txid, err := chainhash.NewHash(pending.Txid)
+		if err != nil {
+			n.t.Fatalf("unable to parse txid: %v", err)
+		}
+		return &wire.OutPoint{Hash: *txid, Index: pending.OutputIndex}
+
+	case err := <-errChan:
+		n.t.Fatalf("unable to open channel: %v", err)
+
+	case <-time.After(fundingTestTimeout):
+		n.t.Fatalf("channel not pending")
+	}
+
+	return nil
+}
+
+// captureFundingLocked returns the channel over which each FundingLocked
+// message the node attempts to send is delivered.
+func (n *fundingTestNode) captureFundingLocked() chan *lnwire.FundingLocked {
+	locked := make(chan *lnwire.FundingLocked, 10)
+	n.msgHook = func(msg lnwire.Message) lnwire.Message {
+		if fundingLocked, ok := msg.(*lnwire.FundingLocked); ok {
+			locked <- fundingLocked
+		}
+		return msg
+	}
+
+	return locked
+}
+
+// assertFundingState asserts that the node's channel with the passed peer,
+// funded by the passed outpoint, reaches the expected funding state.
+func (n *fundingTestNode) assertFundingState(peer *fundingTestNode,
+	chanPoint *wire.OutPoint, expected channeldb.FundingState) {
+
+	var state channeldb.FundingState
+	deadline := time.Now().Add(fundingTestTimeout)
+	for time.Now().Before(deadline) {
+		channel, err := n.fundingMgr.fetchOpenChannel(
+			peer.addr.IdentityKey, chanPoint)
+		if err != nil {
+			n.t.Fatalf("unable to fetch channel: %v", err)
+		}
+		state = channel.FundingState
+		if !channel.IsPending && state == expected {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	n.t.Fatalf("expected funding state %v, found %v", expected, state)
+}
+
+// assertFundingLocked asserts whether a FundingLocked message is delivered
+// over the passed channel. If none is expected, then we wait for a short
+// while to ensure none is sent.
+func assertFundingLocked(t *testing.T, locked chan *lnwire.FundingLocked,
+	expected bool) {
+
+	timeout := fundingTestTimeout
+	if !expected {
+		timeout = 100 * time.Millisecond
+	}
+
+	select {
+	case <-locked:
+		if !expected {
+			t.Fatalf("unexpected FundingLocked sent")
+		}
+	case <-time.After(timeout):
+		if expected {
+			t.Fatalf("FundingLocked not sent")
+		}
+	}
+}
+
+// pendingAnnouncement is an announcement awaiting the result of being
+// processed.
+type pendingAnnouncement struct {
+	msg lnwire.Message
+	err chan error
+}
+
+// captureAnnouncements sets the node's announceHook, returning the channel
+// over which each announcement the node sends is delivered. The
+// announcements are left to be processed by the test.
+func (n *fundingTestNode) captureAnnouncements() chan *pendingAnnouncement {
+	anns := make(chan *pendingAnnouncement, 10)
+	n.announceHook = func(msg lnwire.Message) chan error {
+		ann := &pendingAnnouncement{msg: msg, err: make(chan error, 1)}
+		anns <- ann
+		return ann.err
+	}
+
+	return anns
+}
+
+// assertAnnouncement asserts whether an announcement is delivered over the
+// passed channel, returning it if so. If none is expected, then we wait for
+// a short while to ensure none is sent.
+func assertAnnouncement(t *testing.T, anns chan *pendingAnnouncement,
+	expected bool) *pendingAnnouncement {
+
+	timeout := fundingTestTimeout
+	if !expected {
+		timeout = 100 * time.Millisecond
+	}
+
+	select {
+	case ann := <-anns:
+		if !expected {
+			t.Fatalf("unexpected announcement %T sent", ann.msg)
+		}
+		return ann
+
+	case <-time.After(timeout):
+		if expected {
+			t.Fatalf("announcement not sent")
+		}
+	}
+
+	return nil
+}
+
+// processAnnouncements successfully processes the three announcements of a
+// public channel.
+func processAnnouncements(t *testing.T, anns chan *pendingAnnouncement) {
+	for i := 0; i < 3; i++ {
+		assertAnnouncement(t, anns, true).err <- nil
+	}
+}
+
+// ageReservations moves the last update of each of the node's reservations
+// back by the passed duration, as if they had been idle for that long.
+func (n *fundingTestNode) ageReservations(age time.Duration) {
+	n.fundingMgr.resMtx.Lock()
+	defer n.fundingMgr.resMtx.Unlock()
+
+	for _, peerReservations := range n.fundingMgr.activeReservations {
+		for _, resCtx := range peerReservations {
+			resCtx.lastUpdated = resCtx.lastUpdated.Add(-age)
+		}
+	}
+}
+
+// numReservations returns the number of the node's active reservations.
+func (n *fundingTestNode) numReservations() int {
+	n.fundingMgr.resMtx.RLock()
+	defer n.fundingMgr.resMtx.RUnlock()
+
+	var numReservations int
+	for _, peerReservations := range n.fundingMgr.activeReservations {
+		numReservations += len(peerReservations)
+	}
+
+	return numReservations
+}
+
+// assertPendingChans asserts that the node's database holds the expected
+// number of pending channels.
+func (n *fundingTestNode) assertPendingChans(expected int) {
+	pendingChans, err := n.wallet.ChannelDB.FetchPendingChannels()
+	if err != nil {
+		n.t.Fatalf("unable to fetch pending channels: %v", err)
+	}
+	if len(pendingChans) != expected {
+		n.t.Fatalf("expected %v pending channels, found %v", expected,
+			len(pendingChans))
+	}
+}
+
+// assertBatchFailed asserts that every channel of a batch has failed, that
+// none of them have been committed to disk, and that the coins selected to
+// fund the batch have been unlocked.
+func assertBatchFailed(t *testing.T, initiator *fundingTestNode,
+	updates []chan *lnrpc.OpenStatusUpdate, errChans []chan error) {
+
+	for i, errChan := range errChans {
+		select {
+		case <-errChan:
+		case update := <-updates[i]:
+			t.Fatalf("channel %v of failed batch updated: %v", i,
+				update)
+		case <-time.After(fundingTestTimeout):
+			t.Fatalf("channel %v of batch didn't fail", i)
+		}
+	}
+
+	initiator.assertPendingChans(0)
+	if len(initiator.wallet.LockedOutpoints()) != 0 {
+		t.Fatalf("coins of failed batch still locked")
+	}
+}
+
+// assertBatchPending asserts that every channel of a batch is reported as
+// pending, and that they all share the same funding transaction, whose txid
+// is returned.
+func assertBatchPending(t *testing.T, updates []chan *lnrpc.OpenStatusUpdate,
+	errChans []chan error) *chainhash.Hash {
+
+	var fundingTxid *chainhash.Hash
+	for i, updateChan := range updates {
+		select {
+		case update := <-updateChan:
+			pending := update.GetChanPending()
+			if pending == nil {
+				t.Fatalf("expected pending update, got %v",
+					update)
+			}
+			txid, err := chainhash.NewHash(pending.Txid)
+			if err != nil {
+				t.Fatalf("unable to parse txid: %v", err)
+			}
+			if fundingTxid != nil && *txid != *fundingTxid {
+				t.Fatalf("channels of batch funded by %v and "+
+					"%v", fundingTxid, txid)
+			}
+			fundingTxid = txid
+
+		case err := <-errChans[i]:
+			t.Fatalf("channel %v of batch failed: %v", i, err)
+
+		case <-time.After(fundingTestTimeout):
+			t.Fatalf("channel %v of batch not pending", i)
+		}
+	}
+
+	return fundingTxid
+}
+
+// TestFundingBatch tests that once every responder of a batch has signed, the
+// channels of the batch are committed to disk, and their shared funding
+// transaction is broadcast.
+func TestFundingBatch(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	carol := fundingNet.addNode(3)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	const chanAmt = btcutil.Amount(1000000)
+	updates, errChans := alice.openBatch(
+		[]*fundingTestNode{bob, carol}, chanAmt)
+
+	fundingTxid := assertBatchPending(t, updates, errChans)
+
+	// Both channels should now be pending, and their funding transaction
+	// broadcast.
+	alice.assertPendingChans(2)
+	if _, err := fundingNet.chain.GetRawTransaction(fundingTxid); err != nil {
+		t.Fatalf("batch funding tx not broadcast: %v", err)
+	}
+	if len(alice.wallet.LockedOutpoints()) != 0 {
+		t.Fatalf("coins of broadcast batch still locked")
+	}
+}
+
+// TestFundingBatchPublishFailure tests that if the funding transaction of a
+// batch can't be broadcast, then its channels remain pending and the coins
+// selected to fund it remain locked, as the transaction may still have
+// reached the network, and that it's rebroadcast with the next block.
+func TestFundingBatchPublishFailure(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	carol := fundingNet.addNode(3)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	atomic.StoreInt32(&alice.controller.failPublish, 1)
+
+	updates, errChans := alice.openBatch(
+		[]*fundingTestNode{bob, carol}, 1000000)
+	fundingTxid := assertBatchPending(t, updates, errChans)
+
+	alice.assertPendingChans(2)
+	if _, err := fundingNet.chain.GetRawTransaction(fundingTxid); err == nil {
+		t.Fatalf("batch funding tx broadcast despite failure")
+	}
+	if len(alice.wallet.LockedOutpoints()) == 0 {
+		t.Fatalf("coins of unbroadcast batch unlocked")
+	}
+
+	// Once the funding transaction can be published again, it should be
+	// rebroadcast with the next block, after which its coins are
+	// unlocked.
+	atomic.StoreInt32(&alice.controller.failPublish, 0)
+	if _, err := fundingNet.chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	deadline := time.Now().Add(fundingTestTimeout)
+	for {
+		_, err := fundingNet.chain.GetRawTransaction(fundingTxid)
+		if err == nil && len(alice.wallet.LockedOutpoints()) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch funding tx not rebroadcast: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	alice.assertPendingChans(2)
+}
+
+// TestFundingBatchInvalidSig tests that if any responder of a batch provides
+// an invalid signature for our version of their commitment transaction, then
+// none of the channels of the batch are committed to disk.
+func TestFundingBatchInvalidSig(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	carol := fundingNet.addNode(3)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	// Carol will sign Alice's commitment transaction with an unrelated
+	// key.
+	invalidKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	invalidSig, err := invalidKey.Sign(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	carol.msgHook = func(msg lnwire.Message) lnwire.Message {
+		if signComplete, ok := msg.(*lnwire.SingleFundingSignComplete); ok {
+			signComplete.CommitSignature = invalidSig
+		}
+		return msg
+	}
+
+	updates, errChans := alice.openBatch(
+		[]*fundingTestNode{bob, carol}, 1000000)
+	assertBatchFailed(t, alice, updates, errChans)
+}
+
+// TestFundingPrivateRequiresExtendedFunding tests that a private channel
+// can't be opened with a peer lacking the extended-funding feature, as it'd
+// be unable to learn that the channel isn't to be announced.
+func TestFundingPrivateRequiresExtendedFunding(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	updates := make(chan *lnrpc.OpenStatusUpdate, 1)
+	errChan := make(chan error, 1)
+	alice.fundingMgr.initFundingWorkflow(bob.addr, &openChanReq{
+		targetPubkey:    bob.addr.IdentityKey,
+		localFundingAmt: 1000000,
+		numConfs:        1,
+		private:         true,
+		updates:         updates,
+		err:             errChan,
+	})
+
+	select {
+	case <-errChan:
+	case update := <-updates:
+		t.Fatalf("private channel opened with legacy peer: %v", update)
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("private channel with legacy peer not rejected")
+	}
+
+	alice.assertPendingChans(0)
+	if len(alice.wallet.LockedOutpoints()) != 0 {
+		t.Fatalf("coins of rejected channel locked")
+	}
+}
+
+// TestFundingResumeAwaitsAnnouncement tests that a channel is only recorded
+// as announced once its announcements have been processed, and that requests
+// to resume its funding flow made in the meantime are deferred until the
+// current attempt exits.
+func TestFundingResumeAwaitsAnnouncement(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	locked := alice.captureFundingLocked()
+	anns := alice.captureAnnouncements()
+
+	chanPoint := alice.openChannel(bob, 1000000)
+	if _, err := fundingNet.chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	// Once the funding transaction confirms, FundingLocked should be sent
+	// to Bob, then the channel announced. Until the announcement has been
+	// processed, the channel shouldn't be recorded as announced.
+	assertFundingLocked(t, locked, true)
+	chanAnn := assertAnnouncement(t, anns, true)
+	if _, ok := chanAnn.msg.(*lnwire.ChannelAnnouncement); !ok {
+		t.Fatalf("expected channel announcement, got %T", chanAnn.msg)
+	}
+	alice.assertFundingState(bob, chanPoint, channeldb.FundingLockedSent)
+
+	// Bob now reconnects twice while the announcement is being processed.
+	// Neither reconnection should resume the funding flow while the
+	// current attempt is still running.
+	alice.fundingMgr.peerConnected(bob.addr.IdentityKey)
+	alice.fundingMgr.peerConnected(bob.addr.IdentityKey)
+	assertFundingLocked(t, locked, false)
+	assertAnnouncement(t, anns, false)
+
+	chanAnn.err <- nil
+	assertAnnouncement(t, anns, true).err <- nil
+	assertAnnouncement(t, anns, true).err <- nil
+	alice.assertFundingState(bob, chanPoint, channeldb.FundingAnnounced)
+
+	// Once the attempt exits, the flow should be resumed just once more on
+	// behalf of both reconnections. As the channel is yet to be used,
+	// FundingLocked is re-sent, but the channel isn't announced again.
+	assertFundingLocked(t, locked, true)
+	assertFundingLocked(t, locked, false)
+	assertAnnouncement(t, anns, false)
+}
+
+// TestFundingResumeOnReconnect tests that if FundingLocked can't be sent once
+// the funding transaction confirms, then it's sent once the peer reconnects,
+// and that it's re-sent on each reconnection until the channel is used.
+func TestFundingResumeOnReconnect(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	locked := alice.captureFundingLocked()
+	anns := alice.captureAnnouncements()
+
+	chanPoint := alice.openChannel(bob, 1000000)
+
+	// Bob goes offline before the funding transaction confirms, so Alice
+	// should fail to send FundingLocked, leaving the funding flow halted.
+	atomic.StoreInt32(&bob.offline, 1)
+	if _, err := fundingNet.chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	assertFundingLocked(t, locked, true)
+	alice.assertFundingState(bob, chanPoint, channeldb.FundingConfirmed)
+	assertAnnouncement(t, anns, false)
+
+	// Once Bob reconnects, the funding flow should complete.
+	atomic.StoreInt32(&bob.offline, 0)
+	alice.fundingMgr.peerConnected(bob.addr.IdentityKey)
+	assertFundingLocked(t, locked, true)
+	processAnnouncements(t, anns)
+	alice.assertFundingState(bob, chanPoint, channeldb.FundingAnnounced)
+
+	// As the channel is yet to be used, Bob may not have processed
+	// FundingLocked, so it should be re-sent when he next reconnects.
+	alice.fundingMgr.peerConnected(bob.addr.IdentityKey)
+	assertFundingLocked(t, locked, true)
+	assertAnnouncement(t, anns, false)
+}
+
+// TestFundingResumeAfterRestart tests that if the announcement of a channel
+// fails, then the channel is announced once the funding manager restarts,
+// without FundingLocked being sent once more.
+func TestFundingResumeAfterRestart(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	locked := alice.captureFundingLocked()
+	anns := alice.captureAnnouncements()
+
+	chanPoint := alice.openChannel(bob, 1000000)
+	if _, err := fundingNet.chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	// The channel announcement fails to be processed, so the channel
+	// shouldn't be recorded as announced.
+	assertFundingLocked(t, locked, true)
+	assertAnnouncement(t, anns, true).err <- fmt.Errorf("gossiper down")
+	alice.assertFundingState(bob, chanPoint, channeldb.FundingLockedSent)
+	assertAnnouncement(t, anns, false)
+
+	// After restarting, the channel should be announced, and as Alice has
+	// already sent FundingLocked, she shouldn't send it again.
+	alice.restart()
+	processAnnouncements(t, anns)
+	alice.assertFundingState(bob, chanPoint, channeldb.FundingAnnounced)
+	assertFundingLocked(t, locked, false)
+}
+
+// TestFundingPruneZombieReservation tests that a reservation which has been
+// idle for longer than the reservation timeout is cancelled, unlocking the
+// coins it had selected, and that both the caller and the peer are told the
+// funding flow timed out.
+func TestFundingPruneZombieReservation(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	// Bob's response will reference an unknown pending channel, so Alice
+	// will ignore it, leaving her reservation idle.
+	responded := make(chan struct{}, 1)
+	bob.msgHook = func(msg lnwire.Message) lnwire.Message {
+		if resp, ok := msg.(*lnwire.SingleFundingResponse); ok {
+			resp.PendingChannelID = [32]byte{0xff}
+			responded <- struct{}{}
+		}
+		return msg
+	}
+	timeoutErrs := make(chan *lnwire.Error, 1)
+	alice.msgHook = func(msg lnwire.Message) lnwire.Message {
+		if errMsg, ok := msg.(*lnwire.Error); ok {
+			timeoutErrs <- errMsg
+		}
+		return msg
+	}
+
+	updates := make(chan *lnrpc.OpenStatusUpdate, 1)
+	errChan := make(chan error, 1)
+	alice.fundingMgr.initFundingWorkflow(bob.addr, &openChanReq{
+		targetPubkey:    bob.addr.IdentityKey,
+		localFundingAmt: 1000000,
+		numConfs:        1,
+		updates:         updates,
+		err:             errChan,
+	})
+
+	select {
+	case <-responded:
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("funding request not responded to")
+	}
+	if len(alice.wallet.LockedOutpoints()) == 0 {
+		t.Fatalf("coins of reservation not locked")
+	}
+
+	// A reservation idle for less than the timeout should be left alone.
+	alice.ageReservations(cfg.ReservationTimeout - time.Minute)
+	alice.fundingMgr.pruneZombieReservations()
+	if alice.numReservations() != 1 {
+		t.Fatalf("reservation pruned before timing out")
+	}
+
+	// Once it's been idle for longer than the timeout, it should be
+	// cancelled, unlocking its coins.
+	alice.ageReservations(2 * time.Minute)
+	alice.fundingMgr.pruneZombieReservations()
+	if alice.numReservations() != 0 {
+		t.Fatalf("zombie reservation not pruned")
+	}
+	if len(alice.wallet.LockedOutpoints()) != 0 {
+		t.Fatalf("coins of zombie reservation still locked")
+	}
+
+	select {
+	case <-errChan:
+	case update := <-updates:
+		t.Fatalf("zombie reservation updated: %v", update)
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("caller not told funding flow timed out")
+	}
+
+	select {
+	case errMsg := <-timeoutErrs:
+		if errMsg.Code != lnwire.ErrFundingTimeout {
+			t.Fatalf("expected funding timeout error, got %v",
+				errMsg.Code)
+		}
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("peer not told funding flow timed out")
+	}
+}
+
+// TestFundingPruneAwaitingPsbt tests that a PSBT funded reservation awaiting
+// a PSBT from the caller is only pruned once the PSBT timeout has elapsed,
+// rather than the reservation timeout.
+func TestFundingPruneAwaitingPsbt(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+
+	updates := make(chan *lnrpc.OpenStatusUpdate, 1)
+	errChan := make(chan error, 1)
+	alice.fundingMgr.initFundingWorkflow(bob.addr, &openChanReq{
+		targetPubkey:    bob.addr.IdentityKey,
+		localFundingAmt: 1000000,
+		numConfs:        1,
+		psbtFunding:     true,
+		updates:         updates,
+		err:             errChan,
+	})
+
+	select {
+	case update := <-updates:
+		if update.GetPsbtFund() == nil {
+			t.Fatalf("expected PSBT funding update, got %v", update)
+		}
+	case err := <-errChan:
+		t.Fatalf("unable to open channel: %v", err)
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("channel not ready for PSBT funding")
+	}
+
+	// While awaiting the PSBT, the reservation timeout shouldn't apply.
+	alice.ageReservations(cfg.ReservationTimeout + time.Minute)
+	alice.fundingMgr.pruneZombieReservations()
+	if alice.numReservations() != 1 {
+		t.Fatalf("reservation awaiting PSBT pruned")
+	}
+
+	alice.ageReservations(cfg.PsbtTimeout)
+	alice.fundingMgr.pruneZombieReservations()
+	if alice.numReservations() != 0 {
+		t.Fatalf("zombie reservation awaiting PSBT not pruned")
+	}
+
+	select {
+	case <-errChan:
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("caller not told funding flow timed out")
+	}
+}
+
+// TestFundingForgetPendingChannel tests that an inbound pending channel whose
+// funding transaction fails to confirm is forgotten once the chain reaches
+// its forget height, but not before.
+func TestFundingForgetPendingChannel(t *testing.T) {
+	fundingNet := newFundingTestNet(t)
+	defer fundingNet.stop()
+	cfg.MaxPendingBlocks = 3
+
+	alice := fundingNet.addNode(1)
+	bob := fundingNet.addNode(2)
+	fundingNet.fund(alice, 10*btcutil.SatoshiPerBitcoin)
+
+	// Alice fails to broadcast the funding transaction, leaving Bob's
+	// channel pending without its funding transaction ever confirming.
+	atomic.StoreInt32(&alice.controller.failPublish, 1)
+
+	updates := make(chan *lnrpc.OpenStatusUpdate, 1)
+	errChan := make(chan error, 1)
+	alice.fundingMgr.initFundingWorkflow(bob.addr, &openChanReq{
+		targetPubkey:    bob.addr.IdentityKey,
+		localFundingAmt: 1000000,
+		numConfs:        1,
+		updates:         updates,
+		err:             errChan,
+	})
+
+	select {
+	case <-errChan:
+	case update := <-updates:
+		t.Fatalf("channel with unpublished funding tx updated: %v",
+			update)
+	case <-time.After(fundingTestTimeout):
+		t.Fatalf("funding tx publish failure not reported")
+	}
+
+	pendingChans, err := bob.wallet.ChannelDB.FetchPendingChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch pending channels: %v", err)
+	}
+	if len(pendingChans) != 1 {
+		t.Fatalf("expected 1 pending channel, found %v",
+			len(pendingChans))
+	}
+	forgetAt := forgetHeight(pendingChans[0])
+	if forgetAt != pendingChans[0].FundingBroadcastHeight+3 {
+		t.Fatalf("expected forget height %v, got %v",
+			pendingChans[0].FundingBroadcastHeight+3, forgetAt)
+	}
+
+	// Up until the forget height, the channel should remain pending.
+	_, height, err := fundingNet.chain.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get best block: %v", err)
+	}
+	_, err = fundingNet.chain.Generate(forgetAt - uint32(height) - 1)
+	if err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	bob.assertPendingChans(1)
+
+	// Once the forget height is reached, the channel should be forgotten.
+	if _, err := fundingNet.chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	deadline := time.Now().Add(fundingTestTimeout)
+	for {
+		pendingChans, err := bob.wallet.ChannelDB.FetchPendingChannels()
+		if err != nil {
+			t.Fatalf("unable to fetch pending channels: %v", err)
+		}
+		if len(pendingChans) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pending channel not forgotten at height %v",
+				forgetAt)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,528 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// htlcExpiryWatcher watches over all open channels for outgoing HTLCs which
+// are nearing their expiry. If the remote party hasn't settled or failed an
+// outgoing HTLC off-chain by the time its expiry is within the configured
+// delta of the current block height, then we're forced to go to chain: the
+// HTLC we've accepted on the incoming link can't be timed out before we're
+// able to either reclaim the outgoing HTLC, or learn its preimage. Once the
+// channel has been force closed, the watcher waits for the HTLC output to be
+// spent in order to resolve the upstream HTLC within the switch.
+type htlcExpiryWatcher struct {
+	started uint32
+	stopped uint32
+
+	server *server
+
+	// delta is the number of blocks before an outgoing HTLC's expiry at
+	// which the channel it's located in will be force closed.
+	delta uint32
+
+	// closing is the set of channels currently being force closed. As
+	// channels may be force closed by both the watcher, and at the
+	// request of the remote peer, a channel is added to the set before
+	// checking whether its commitment has been broadcast, ensuring that
+	// it's never broadcast twice.
+	closing    map[wire.OutPoint]struct{}
+	closingMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newHtlcExpiryWatcher creates a new instance of the htlcExpiryWatcher which
+// will force close channels containing outgoing HTLCs expiring within delta
+// blocks.
+func newHtlcExpiryWatcher(s *server, delta uint32) *htlcExpiryWatcher {
+	return &htlcExpiryWatcher{
+		server:  s,
+		delta:   delta,
+		closing: make(map[wire.OutPoint]struct{}),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine which checks the set of open channels for
+// expiring HTLCs each time a new block is connected. Any channels which were
+// force closed before a restart, but haven't yet been resolved, are resumed.
+func (h *htlcExpiryWatcher) Start() error {
+	if !atomic.CompareAndSwapUint32(&h.started, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Tracef("Starting htlc expiry watcher")
+
+	if err := h.resumeClosing(); err != nil {
+		return err
+	}
+
+	epochClient, err := h.server.chainNotifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	h.wg.Add(1)
+	go h.expiryWatcher(epochClient)
+
+	return nil
+}
+
+// Stop gracefully shuts down the htlcExpiryWatcher.
+func (h *htlcExpiryWatcher) Stop() error {
+	if !atomic.CompareAndSwapUint32(&h.stopped, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Infof("Htlc expiry watcher shutting down")
+
+	close(h.quit)
+	h.wg.Wait()
+
+	return nil
+}
+
+// expiryWatcher checks all open channels for expiring outgoing HTLCs each
+// time a new block is connected to the main chain.
+//
+// NOTE: This MUST be run as a goroutine.
+func (h *htlcExpiryWatcher) expiryWatcher(epochClient *chainntnfs.BlockEpochEvent) {
+	defer h.wg.Done()
+	defer epochClient.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-epochClient.Epochs:
+			// If the epoch channel has been closed, then the
+			// notifier is shutting down so we exit.
+			if !ok {
+				return
+			}
+
+			if err := h.checkChannels(uint32(epoch.Height)); err != nil {
+				srvrLog.Errorf("unable to check channels for "+
+					"expiring htlcs at height %v: %v",
+					epoch.Height, err)
+			}
+
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// checkChannels force closes each open channel which has an outgoing HTLC
+// expiring within delta blocks of the passed height.
+func (h *htlcExpiryWatcher) checkChannels(height uint32) error {
+	dbChannels, err := h.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+
+	for _, dbChannel := range dbChannels {
+		chanPoint := *dbChannel.ChanID
+
+		// If our commitment transaction has already been broadcast,
+		// then the channel is being resolved on-chain.
+		if dbChannel.CommitmentBroadcast {
+			continue
+		}
+
+		expiring := false
+		for _, htlc := range dbChannel.Htlcs {
+			if !htlc.Incoming && htlc.RefundTimeout <= height+h.delta {
+				expiring = true
+				break
+			}
+		}
+		if !expiring {
+			continue
+		}
+
+		srvrLog.Warnf("ChannelPoint(%v) has an outgoing htlc expiring "+
+			"within %v blocks of height %v, force closing",
+			chanPoint, h.delta, height)
+
+		if err := h.forceClose(dbChannel, height); err != nil {
+			srvrLog.Errorf("unable to force close "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// forceClose tears down the link of the passed channel if the remote peer is
+// currently connected, then broadcasts our latest commitment transaction.
+// The outputs of the commitment transaction are handed to the utxoNursery,
+// and a goroutine is launched for each of our outgoing HTLCs in order to
+// resolve them once they've been spent on-chain. The passed height is the
+// current best height, before which none of our HTLC outputs can be spent.
+// If the channel is already being force closed, then this is a noop.
+func (h *htlcExpiryWatcher) forceClose(dbChannel *channeldb.OpenChannel,
+	height uint32) error {
+
+	chanPoint := *dbChannel.ChanID
+
+	return h.guardClose(chanPoint, func() error {
+		// If the remote peer is online, then we first remove the
+		// channel's link, ensuring no further updates are applied to
+		// the channel while we're closing it.
+		if p, err := h.server.findPeer(dbChannel.IdentityPub); err == nil {
+			chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+			p.removeLink(chanID)
+		}
+
+		// With the link removed, we re-read the channel's state from
+		// disk as it may have been updated before the link was torn
+		// down.
+		dbChannel, err := h.fetchChannel(chanPoint)
+		if err != nil {
+			return err
+		}
+		channel, err := lnwallet.NewLightningChannel(
+			h.server.lnwallet.Signer, nil, dbChannel)
+		if err != nil {
+			return err
+		}
+
+		closeSummary, err := channel.ForceClose()
+		if err != nil {
+			return err
+		}
+
+		// Before broadcasting, we record that the channel is being
+		// force closed, ensuring we won't attempt to close it again,
+		// and that its resolution is resumed after a restart.
+		err = h.server.chanDB.MarkCommitmentBroadcast(&chanPoint)
+		if err != nil {
+			return err
+		}
+
+		closeTx := closeSummary.CloseTx
+
+		srvrLog.Infof("Broadcasting force close transaction, "+
+			"ChannelPoint(%v): %v", chanPoint,
+			newLogClosure(func() string {
+				return spew.Sdump(closeTx)
+			}))
+		if err := h.server.lnwallet.PublishTransaction(closeTx); err != nil {
+			return err
+		}
+
+		// Send the closed channel summary over to the utxoNursery in
+		// order to have its outputs, including our outgoing HTLCs,
+		// swept back into the wallet once they're mature.
+		h.server.utxoNursery.incubateOutputs(closeSummary)
+
+		h.watchClose(channel, closeSummary, height)
+
+		return nil
+	})
+}
+
+// guardClose executes the passed function, which force closes the channel
+// with the passed channel point, unless the channel is already being force
+// closed. Concurrent attempts to force close the same channel are serialised,
+// with only the first of them executing the function. As the caller's view of
+// the channel may be stale, whether our commitment transaction has already
+// been broadcast is re-read from disk once the channel has been claimed.
+func (h *htlcExpiryWatcher) guardClose(chanPoint wire.OutPoint,
+	closeChan func() error) error {
+
+	h.closingMtx.Lock()
+	if _, ok := h.closing[chanPoint]; ok {
+		h.closingMtx.Unlock()
+		return nil
+	}
+	h.closing[chanPoint] = struct{}{}
+	h.closingMtx.Unlock()
+
+	defer func() {
+		h.closingMtx.Lock()
+		delete(h.closing, chanPoint)
+		h.closingMtx.Unlock()
+	}()
+
+	dbChannel, err := h.fetchChannel(chanPoint)
+	if err != nil {
+		return err
+	}
+	if dbChannel.CommitmentBroadcast {
+		return nil
+	}
+
+	return closeChan()
+}
+
+// resumeClosing resumes the resolution of each channel which we force closed
+// before the daemon was last shut down. As the commitment transaction is
+// deterministic, the close summary is re-derived from the channel's on-disk
+// state, and the commitment transaction re-broadcast in case it never made it
+// into the mempool. The nursery persists its own state, so the outputs aren't
+// incubated a second time.
+func (h *htlcExpiryWatcher) resumeClosing() error {
+	dbChannels, err := h.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+
+	_, height, err := h.server.bio.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	for _, dbChannel := range dbChannels {
+		if !dbChannel.CommitmentBroadcast {
+			continue
+		}
+
+		chanPoint := *dbChannel.ChanID
+
+		channel, err := lnwallet.NewLightningChannel(
+			h.server.lnwallet.Signer, nil, dbChannel)
+		if err != nil {
+			return err
+		}
+		closeSummary, err := channel.ForceClose()
+		if err != nil {
+			return err
+		}
+
+		srvrLog.Infof("Resuming force close of ChannelPoint(%v)",
+			chanPoint)
+
+		err = h.server.lnwallet.PublishTransaction(closeSummary.CloseTx)
+		if err != nil {
+			srvrLog.Debugf("unable to re-broadcast force close "+
+				"transaction for ChannelPoint(%v): %v",
+				chanPoint, err)
+		}
+
+		h.watchClose(channel, closeSummary, uint32(height))
+	}
+
+	return nil
+}
+
+// watchClose launches the goroutines which resolve each of our outgoing HTLCs
+// once spent, and which clean up the channel's state once the passed force
+// close has confirmed.
+func (h *htlcExpiryWatcher) watchClose(channel *lnwallet.LightningChannel,
+	closeSummary *lnwallet.ForceCloseSummary, height uint32) {
+
+	for _, htlcRes := range closeSummary.HtlcResolutions {
+		h.wg.Add(1)
+		go h.resolveHtlc(htlcRes, height)
+	}
+
+	closeTxid := closeSummary.CloseTx.TxHash()
+
+	h.wg.Add(1)
+	go h.waitForClose(channel, &closeTxid)
+}
+
+// forceCloseRequested force closes the channel identified by the passed
+// channel ID at the request of the remote peer. A peer which has lost its
+// channel state, and restored it from a static backup, is unable to close the
+// channel itself, so it relies on us to broadcast our commitment transaction.
+// The request is ignored if the channel isn't shared with the requesting peer.
+//
+// NOTE: The peer rejects requests for channels the remote peer has updated
+// over its current connection, as it then can't have lost their state.
+func (h *htlcExpiryWatcher) forceCloseRequested(chanID lnwire.ChannelID,
+	peerPub *btcec.PublicKey) error {
+
+	dbChannels, err := h.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+
+	for _, dbChannel := range dbChannels {
+		chanPoint := *dbChannel.ChanID
+		if lnwire.NewChanIDFromOutPoint(&chanPoint) != chanID {
+			continue
+		}
+
+		if !dbChannel.IdentityPub.IsEqual(peerPub) {
+			return fmt.Errorf("ChannelPoint(%v) isn't shared with "+
+				"peer %x", chanPoint, peerPub.SerializeCompressed())
+		}
+
+		if dbChannel.CommitmentBroadcast {
+			return nil
+		}
+
+		_, height, err := h.server.bio.GetBestBlock()
+		if err != nil {
+			return err
+		}
+
+		srvrLog.Warnf("Peer %x requested force close of "+
+			"ChannelPoint(%v), force closing",
+			peerPub.SerializeCompressed(), chanPoint)
+
+		return h.forceClose(dbChannel, uint32(height))
+	}
+
+	return fmt.Errorf("unable to find ChannelID(%v)", chanID)
+}
+
+// fetchChannel attempts to locate an open channel identified by its channel
+// point, returning the channel's current on-disk state.
+func (h *htlcExpiryWatcher) fetchChannel(
+	chanPoint wire.OutPoint) (*channeldb.OpenChannel, error) {
+
+	dbChannels, err := h.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dbChannel := range dbChannels {
+		if *dbChannel.ChanID == chanPoint {
+			return dbChannel, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find ChannelPoint(%v)", chanPoint)
+}
+
+// waitForClose waits for the passed force close transaction to confirm, then
+// deletes the channel's state and signals the breachArbiter that it no longer
+// needs to watch the channel.
+//
+// NOTE: This MUST be run as a goroutine.
+func (h *htlcExpiryWatcher) waitForClose(channel *lnwallet.LightningChannel,
+	closeTxid *chainhash.Hash) {
+
+	defer h.wg.Done()
+
+	chanPoint := *channel.ChannelPoint()
+
+	confNtfn, err := h.server.chainNotifier.RegisterConfirmationsNtfn(
+		closeTxid, 1)
+	if err != nil {
+		srvrLog.Errorf("unable to register for confirmation of "+
+			"force close of ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+
+	select {
+	case txConf, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return
+		}
+
+		srvrLog.Infof("ChannelPoint(%v) is now closed at height %v",
+			chanPoint, txConf.BlockHeight)
+		if err := channel.DeleteState(); err != nil {
+			srvrLog.Errorf("unable to delete ChannelPoint(%v) "+
+				"from db: %v", chanPoint, err)
+			return
+		}
+
+	case <-h.quit:
+		return
+	}
+
+	select {
+	case h.server.breachArbiter.settledContracts <- &chanPoint:
+	case <-h.quit:
+	}
+}
+
+// resolveHtlc waits for the output of an outgoing HTLC on our commitment
+// transaction to be spent. If the remote party swept the output by revealing
+// the HTLC's preimage, then the HTLC is settled within the switch. If we've
+// timed out the HTLC ourselves, or the remote party swept it via the
+// revocation clause, then the HTLC is failed backwards.
+//
+// NOTE: This MUST be run as a goroutine.
+func (h *htlcExpiryWatcher) resolveHtlc(htlcRes lnwallet.OutgoingHtlcResolution,
+	heightHint uint32) {
+
+	defer h.wg.Done()
+
+	spendNtfn, err := h.server.chainNotifier.RegisterSpendNtfn(
+		&htlcRes.ClaimOutpoint, heightHint)
+	if err != nil {
+		srvrLog.Errorf("unable to register for spend of htlc "+
+			"output %v: %v", htlcRes.ClaimOutpoint, err)
+		return
+	}
+	defer spendNtfn.Cancel()
+
+	var spendDetail *chainntnfs.SpendDetail
+	select {
+	case detail, ok := <-spendNtfn.Spend:
+		if !ok {
+			return
+		}
+		spendDetail = detail
+
+	case <-h.quit:
+		return
+	}
+
+	amt := btcutil.Amount(htlcRes.SweepSignDesc.Output.Value)
+	spenderInput := spendDetail.SpendingTx.TxIn[spendDetail.SpenderInputIndex]
+
+	spendType, preimage := lnwallet.ClassifyHtlcSpend(
+		spenderInput.Witness, htlcRes.PaymentHash)
+	switch spendType {
+	case lnwallet.HtlcSpendRedeemed:
+		srvrLog.Infof("Htlc output %v claimed on-chain with preimage "+
+			"%x, settling htlc %x", htlcRes.ClaimOutpoint,
+			preimage[:], htlcRes.PaymentHash[:])
+
+		h.server.htlcSwitch.resolveOnChain(&htlcPacket{
+			msg: &lnwire.UpdateFufillHTLC{
+				PaymentPreimage: preimage,
+			},
+			amt: amt,
+		})
+		return
+
+	case lnwallet.HtlcSpendTimedOut:
+		srvrLog.Infof("Htlc output %v timed out on-chain, failing "+
+			"htlc %x", htlcRes.ClaimOutpoint, htlcRes.PaymentHash[:])
+
+	// If the remote party swept the output using the revocation clause,
+	// then the commitment we broadcast had been revoked. The preimage
+	// hasn't been revealed, so we'll still fail the HTLC backwards.
+	case lnwallet.HtlcSpendRevoked:
+		srvrLog.Errorf("Htlc output %v swept on-chain via the "+
+			"revocation clause, failing htlc %x",
+			htlcRes.ClaimOutpoint, htlcRes.PaymentHash[:])
+
+	default:
+		srvrLog.Errorf("Htlc output %v spent by unknown witness "+
+			"in txid %v, unable to resolve htlc %x",
+			htlcRes.ClaimOutpoint, spendDetail.SpenderTxHash,
+			htlcRes.PaymentHash[:])
+		return
+	}
+
+	h.server.htlcSwitch.resolveOnChain(&htlcPacket{
+		msg: &lnwire.UpdateFailHTLC{
+			Reason: []byte{uint8(lnwire.UpstreamTimeout)},
+		},
+		payHash: htlcRes.PaymentHash,
+		amt:     amt,
+	})
+}
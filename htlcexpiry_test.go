@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestHtlcExpiryWatcherGuardClose tests that concurrent attempts to force
+// close the same channel, as made by the watcher and the channel's peer,
+// result in its commitment transaction being broadcast only once, and that
+// the channel isn't closed again once its commitment has been broadcast.
+func TestHtlcExpiryWatcherGuardClose(t *testing.T) {
+	cdb, _, cleanUp := newTestChannelDB(t)
+	defer cleanUp()
+
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9735}
+	chanPoint := wire.OutPoint{Index: 1}
+	newTestChannel(t, cdb, chanPoint, addr)
+
+	watcher := newHtlcExpiryWatcher(&server{chanDB: cdb}, 10)
+
+	// Each attempt which gets to close the channel records the broadcast
+	// of its commitment, after giving the others a chance to race it.
+	var numCloses uint32
+	closeChan := func() error {
+		atomic.AddUint32(&numCloses, 1)
+		time.Sleep(50 * time.Millisecond)
+		return cdb.MarkCommitmentBroadcast(&chanPoint)
+	}
+
+	const numAttempts = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, numAttempts)
+	for i := 0; i < numAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- watcher.guardClose(chanPoint, closeChan)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unable to close channel: %v", err)
+		}
+	}
+	if n := atomic.LoadUint32(&numCloses); n != 1 {
+		t.Fatalf("channel closed %v times, expected once", n)
+	}
+
+	// Now that the commitment has been broadcast, any further attempt to
+	// close the channel should be ignored.
+	if err := watcher.guardClose(chanPoint, closeChan); err != nil {
+		t.Fatalf("unable to close channel: %v", err)
+	}
+	if n := atomic.LoadUint32(&numCloses); n != 1 {
+		t.Fatalf("channel closed %v times, expected once", n)
+	}
+}
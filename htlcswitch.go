@@ -295,6 +295,19 @@ func (h *htlcSwitch) SendHTLC(htlcPkt *htlcPacket) ([32]byte, error) {
 	return <-htlcPkt.preImage, <-htlcPkt.err
 }
 
+// resolveOnChain hands the switch a settle or fail packet for an outgoing HTLC
+// which has been resolved on-chain after its link was force closed. The
+// packet is processed as if it had arrived over the link, propagating the
+// resolution backwards over the payment circuit.
+func (h *htlcSwitch) resolveOnChain(htlcPkt *htlcPacket) {
+	htlcPkt.err = make(chan error, 1)
+
+	select {
+	case h.htlcPlex <- htlcPkt:
+	case <-h.quit:
+	}
+}
+
 // htlcForwarder is responsible for optimally forwarding (and possibly
 // fragmenting) incoming/outgoing HTLCs amongst all active interfaces and
 // their links. The duties of the forwarder are similar to that of a network
@@ -0,0 +1,158 @@
+package keychain
+
+import (
+	"errors"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// KeyFamily represents a "family" of keys that will be used within various
+// contracts created by lnd. Each family is a distinct branch of the key
+// ring's HD hierarchy, allowing the keys of a particular purpose to be
+// derived independently of all others.
+type KeyFamily uint32
+
+const (
+	// KeyFamilyMultiSig are keys to be used within multi-sig scripts, such
+	// as the 2-of-2 output of a channel's funding transaction.
+	KeyFamilyMultiSig KeyFamily = 0
+
+	// KeyFamilyCommitKey are the keys used within the outputs paying to us
+	// within the commitment transactions of a channel. As the channel
+	// protocol uses a single commitment key for both the time-locked
+	// output of our commitment transaction and the output paying to us
+	// within theirs, both are derived from this family.
+	//
+	// NOTE: Families 1, 2 and 4 are reserved for revocation, HTLC and
+	// delay basepoints, should the channel protocol introduce distinct
+	// keys for each. No keys are derived from them.
+	KeyFamilyCommitKey KeyFamily = 3
+
+	// KeyFamilyRevocationRoot are the keys from which the revocation
+	// preimages of a channel are derived.
+	KeyFamilyRevocationRoot KeyFamily = 5
+
+	// KeyFamilyNodeKey is the family of the node's identity key.
+	KeyFamilyNodeKey KeyFamily = 6
+
+	// KeyFamilyStaticBackup is the family of the key used to encrypt our
+	// static channel backups.
+	KeyFamilyStaticBackup KeyFamily = 7
+
+	// KeyFamilyOnionKey is the family of the rotating onion keys used to
+	// process incoming Sphinx packets. The index of each onion key is
+	// that of its rotation.
+	KeyFamilyOnionKey KeyFamily = 8
+
+	// LastKeyFamily is the last key family known to the key ring. Key rings
+	// iterate up to it to re-derive the keys of all families at startup.
+	LastKeyFamily = KeyFamilyOnionKey
+)
+
+// String returns a human readable description of the key family.
+func (k KeyFamily) String() string {
+	switch k {
+	case KeyFamilyMultiSig:
+		return "multisig"
+	case KeyFamilyCommitKey:
+		return "commit key"
+	case KeyFamilyRevocationRoot:
+		return "revocation root"
+	case KeyFamilyNodeKey:
+		return "node key"
+	case KeyFamilyStaticBackup:
+		return "static backup"
+	case KeyFamilyOnionKey:
+		return "onion key"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// ErrUnknownKey is returned when the private key of a public key
+	// which wasn't derived by the key ring is requested.
+	ErrUnknownKey = errors.New("key not derived by key ring")
+)
+
+// KeyLocator is a two-tuple that can be used to derive *any* key that has
+// ever been used under the key ring. Given the seed of the wallet, a
+// KeyLocator is all that's needed to re-derive a key.
+type KeyLocator struct {
+	// Family is the family of key being identified.
+	Family KeyFamily
+
+	// Index is the precise index of the key being identified.
+	Index uint32
+}
+
+// KeyDescriptor wraps a KeyLocator and also optionally includes a public key.
+// Either the KeyLocator must be non-empty, or the public key pointer be
+// non-nil.
+type KeyDescriptor struct {
+	KeyLocator
+
+	// PubKey is the public key of the key identified by the KeyLocator.
+	PubKey *btcec.PublicKey
+}
+
+// KeyRing is the primary interface that will be used to derive the various
+// keys used within the peer-to-peer network, and also within any created
+// contracts. Each key is identified by a KeyLocator, allowing it to be
+// re-derived from the wallet's seed alone.
+type KeyRing interface {
+	// DeriveNextKey attempts to derive the *next* key within the key
+	// family specified, never returning the same key twice.
+	DeriveNextKey(keyFam KeyFamily) (KeyDescriptor, error)
+
+	// DeriveKey attempts to derive an arbitrary key specified by the
+	// passed KeyLocator. This may be used in several recovery scenarios,
+	// or when manually rotating something like our current default node
+	// key.
+	DeriveKey(keyLoc KeyLocator) (KeyDescriptor, error)
+
+	// LocateKey returns the KeyLocator of the passed public key. If the
+	// public key wasn't derived by the key ring, then ErrUnknownKey is
+	// returned.
+	LocateKey(pubKey *btcec.PublicKey) (KeyLocator, error)
+}
+
+// ECDHRing is a KeyRing which is also able to perform ECDH using the private
+// keys it derives, without exposing them. This allows the secrets a node
+// derives from its keys to be computed by a remote signer holding the keys.
+type ECDHRing interface {
+	KeyRing
+
+	// ECDH returns the point shared between the private key identified by
+	// the passed KeyLocator and the passed public key. Callers are
+	// expected to hash the point as required by their protocol.
+	ECDH(keyLoc KeyLocator, pubKey *btcec.PublicKey) (*btcec.PublicKey, error)
+}
+
+// SecretKeyRing is similar to the regular KeyRing interface, but it is also
+// able to derive *private keys*. As this is a super-set of the ECDHRing, we
+// also expect the SecretKeyRing to implement the full ECDHRing interface.
+type SecretKeyRing interface {
+	ECDHRing
+
+	// DerivePrivKey attempts to derive the private key that corresponds to
+	// the passed KeyLocator.
+	DerivePrivKey(keyLoc KeyLocator) (*btcec.PrivateKey, error)
+
+	// FetchPrivKey returns the private key corresponding to the passed
+	// public key. If the public key wasn't derived by the key ring, then
+	// ErrUnknownKey is returned.
+	FetchPrivKey(pubKey *btcec.PublicKey) (*btcec.PrivateKey, error)
+}
+
+// IndexStore persists the number of keys derived within each key family, so
+// that each call to DeriveNextKey yields a fresh key across restarts.
+type IndexStore interface {
+	// NextKeyIndex reserves and returns the next unused index within the
+	// passed key family.
+	NextKeyIndex(keyFam KeyFamily) (uint32, error)
+
+	// FetchKeyIndex returns the number of indexes reserved within the
+	// passed key family.
+	FetchKeyIndex(keyFam KeyFamily) (uint32, error)
+}
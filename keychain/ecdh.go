@@ -0,0 +1,86 @@
+package keychain
+
+import "github.com/roasbeef/btcd/btcec"
+
+// SingleKeyECDH is able to perform ECDH using a single private key, which may
+// be held remotely, such as the node's identity key when authenticating
+// connections.
+type SingleKeyECDH interface {
+	// PubKey returns the public key of the private key.
+	PubKey() *btcec.PublicKey
+
+	// ECDH returns the point shared between the private key and the passed
+	// public key.
+	ECDH(pubKey *btcec.PublicKey) (*btcec.PublicKey, error)
+}
+
+// PubKeyECDH is an implementation of the SingleKeyECDH interface which
+// performs ECDH using a key of an ECDHRing, identified by its descriptor.
+type PubKeyECDH struct {
+	keyDesc KeyDescriptor
+	keyRing ECDHRing
+}
+
+// A compile time check to ensure that PubKeyECDH implements the SingleKeyECDH
+// interface.
+var _ SingleKeyECDH = (*PubKeyECDH)(nil)
+
+// NewPubKeyECDH creates a new PubKeyECDH which performs ECDH using the key of
+// the passed descriptor, derived by the passed key ring.
+func NewPubKeyECDH(keyDesc KeyDescriptor, keyRing ECDHRing) *PubKeyECDH {
+	return &PubKeyECDH{
+		keyDesc: keyDesc,
+		keyRing: keyRing,
+	}
+}
+
+// PubKey returns the public key of the private key.
+//
+// NOTE: This is part of the keychain.SingleKeyECDH interface.
+func (p *PubKeyECDH) PubKey() *btcec.PublicKey {
+	return p.keyDesc.PubKey
+}
+
+// ECDH returns the point shared between the private key and the passed public
+// key.
+//
+// NOTE: This is part of the keychain.SingleKeyECDH interface.
+func (p *PubKeyECDH) ECDH(pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+	return p.keyRing.ECDH(p.keyDesc.KeyLocator, pubKey)
+}
+
+// PrivKeyECDH is an implementation of the SingleKeyECDH interface backed by a
+// private key held in memory.
+type PrivKeyECDH struct {
+	PrivKey *btcec.PrivateKey
+}
+
+// A compile time check to ensure that PrivKeyECDH implements the
+// SingleKeyECDH interface.
+var _ SingleKeyECDH = (*PrivKeyECDH)(nil)
+
+// PubKey returns the public key of the private key.
+//
+// NOTE: This is part of the keychain.SingleKeyECDH interface.
+func (p *PrivKeyECDH) PubKey() *btcec.PublicKey {
+	return p.PrivKey.PubKey()
+}
+
+// ECDH returns the point shared between the private key and the passed public
+// key.
+//
+// NOTE: This is part of the keychain.SingleKeyECDH interface.
+func (p *PrivKeyECDH) ECDH(pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+	return ecdh(p.PrivKey, pubKey), nil
+}
+
+// ecdh returns the point shared between the passed private and public keys.
+func ecdh(privKey *btcec.PrivateKey, pubKey *btcec.PublicKey) *btcec.PublicKey {
+	x, y := btcec.S256().ScalarMult(pubKey.X, pubKey.Y, privKey.D.Bytes())
+
+	return &btcec.PublicKey{
+		Curve: btcec.S256(),
+		X:     x,
+		Y:     y,
+	}
+}
@@ -0,0 +1,203 @@
+package keychain
+
+import (
+	"sync"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+const (
+	// keyRingIndex is the top level HD key index beneath the root key
+	// under which the key ring's families are derived.
+	keyRingIndex = hdkeychain.HardenedKeyStart + 4
+
+	// legacyNodeKeyIndex is the top level HD key index from which the
+	// node's identity key was derived before the introduction of the key
+	// ring. The first key of the KeyFamilyNodeKey family is mapped onto
+	// it in order to preserve the identity of existing nodes.
+	legacyNodeKeyIndex = hdkeychain.HardenedKeyStart + 2
+
+	// legacyOnionKeyIndex is the top level HD key index beneath which the
+	// onion keys were derived before they joined the key ring. Keys of
+	// the KeyFamilyOnionKey family remain derived beneath it, so the onion
+	// key in use isn't changed.
+	legacyOnionKeyIndex = hdkeychain.HardenedKeyStart + 3
+)
+
+// HDKeyRing is an implementation of the SecretKeyRing interface backed by a
+// BIP32 root key. Keys are derived at the path root/4'/family'/index', with
+// the exception of the first node key, which is derived at its legacy path
+// root/2'. All keys are derived using hardened derivation, so the key ring
+// requires the root private key.
+type HDKeyRing struct {
+	root *hdkeychain.ExtendedKey
+
+	store IndexStore
+
+	// pubKeys maps the serialized public key of each key derived by the
+	// key ring to its locator, allowing the private key of a public key
+	// to be retrieved for signing.
+	pubKeys map[[33]byte]KeyLocator
+	sync.RWMutex
+}
+
+// A compile time check to ensure that HDKeyRing implements the SecretKeyRing
+// interface.
+var _ SecretKeyRing = (*HDKeyRing)(nil)
+
+// NewHDKeyRing creates a new HDKeyRing from the passed root key, persisting
+// the next index of each key family to the passed store. All keys previously
+// derived by the key ring are re-derived so their private keys are available
+// to sign with.
+func NewHDKeyRing(root *hdkeychain.ExtendedKey,
+	store IndexStore) (*HDKeyRing, error) {
+
+	k := &HDKeyRing{
+		root:    root,
+		store:   store,
+		pubKeys: make(map[[33]byte]KeyLocator),
+	}
+
+	for keyFam := KeyFamilyMultiSig; keyFam <= LastKeyFamily; keyFam++ {
+		numKeys, err := store.FetchKeyIndex(keyFam)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint32(0); i < numKeys; i++ {
+			_, err := k.DeriveKey(KeyLocator{Family: keyFam, Index: i})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return k, nil
+}
+
+// DeriveNextKey attempts to derive the *next* key within the key family
+// specified.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (k *HDKeyRing) DeriveNextKey(keyFam KeyFamily) (KeyDescriptor, error) {
+	index, err := k.store.NextKeyIndex(keyFam)
+	if err != nil {
+		return KeyDescriptor{}, err
+	}
+
+	return k.DeriveKey(KeyLocator{Family: keyFam, Index: index})
+}
+
+// DeriveKey attempts to derive an arbitrary key specified by the passed
+// KeyLocator.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (k *HDKeyRing) DeriveKey(keyLoc KeyLocator) (KeyDescriptor, error) {
+	privKey, err := k.DerivePrivKey(keyLoc)
+	if err != nil {
+		return KeyDescriptor{}, err
+	}
+	pubKey := privKey.PubKey()
+
+	var serializedKey [33]byte
+	copy(serializedKey[:], pubKey.SerializeCompressed())
+
+	k.Lock()
+	k.pubKeys[serializedKey] = keyLoc
+	k.Unlock()
+
+	return KeyDescriptor{
+		KeyLocator: keyLoc,
+		PubKey:     pubKey,
+	}, nil
+}
+
+// DerivePrivKey attempts to derive the private key that corresponds to the
+// passed KeyLocator.
+//
+// NOTE: This is part of the keychain.SecretKeyRing interface.
+func (k *HDKeyRing) DerivePrivKey(keyLoc KeyLocator) (*btcec.PrivateKey, error) {
+	var (
+		key *hdkeychain.ExtendedKey
+		err error
+	)
+	switch {
+	case keyLoc.Family == KeyFamilyNodeKey && keyLoc.Index == 0:
+		key, err = k.root.Child(legacyNodeKeyIndex)
+
+	case keyLoc.Family == KeyFamilyOnionKey:
+		key, err = k.deriveChild(legacyOnionKeyIndex,
+			hdkeychain.HardenedKeyStart+keyLoc.Index)
+
+	default:
+		key, err = k.deriveChild(keyRingIndex,
+			hdkeychain.HardenedKeyStart+uint32(keyLoc.Family),
+			hdkeychain.HardenedKeyStart+keyLoc.Index)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key.ECPrivKey()
+}
+
+// ECDH returns the point shared between the private key identified by the
+// passed KeyLocator and the passed public key.
+//
+// NOTE: This is part of the keychain.ECDHRing interface.
+func (k *HDKeyRing) ECDH(keyLoc KeyLocator,
+	pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	privKey, err := k.DerivePrivKey(keyLoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecdh(privKey, pubKey), nil
+}
+
+// LocateKey returns the KeyLocator of the passed public key, which must have
+// been derived by the key ring.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (k *HDKeyRing) LocateKey(pubKey *btcec.PublicKey) (KeyLocator, error) {
+	var serializedKey [33]byte
+	copy(serializedKey[:], pubKey.SerializeCompressed())
+
+	k.RLock()
+	keyLoc, ok := k.pubKeys[serializedKey]
+	k.RUnlock()
+	if !ok {
+		return KeyLocator{}, ErrUnknownKey
+	}
+
+	return keyLoc, nil
+}
+
+// FetchPrivKey returns the private key corresponding to the passed public
+// key, which must have been derived by the key ring.
+//
+// NOTE: This is part of the keychain.SecretKeyRing interface.
+func (k *HDKeyRing) FetchPrivKey(pubKey *btcec.PublicKey) (*btcec.PrivateKey, error) {
+	keyLoc, err := k.LocateKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.DerivePrivKey(keyLoc)
+}
+
+// deriveChild derives the descendant of the root key at the passed path.
+func (k *HDKeyRing) deriveChild(path ...uint32) (*hdkeychain.ExtendedKey, error) {
+	key := k.root
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
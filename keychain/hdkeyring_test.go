@@ -0,0 +1,234 @@
+package keychain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+func newTestKeyRing(t *testing.T, store IndexStore) *HDKeyRing {
+	seed := bytes.Repeat([]byte{0x01}, 32)
+	root, err := hdkeychain.NewMaster(seed, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create root key: %v", err)
+	}
+
+	keyRing, err := NewHDKeyRing(root, store)
+	if err != nil {
+		t.Fatalf("unable to create key ring: %v", err)
+	}
+
+	return keyRing
+}
+
+// TestHDKeyRingDerivation tests that keys derived by the key ring are unique
+// within and across families, can be re-derived from their locators alone,
+// and that their private keys can be retrieved after a restart.
+func TestHDKeyRingDerivation(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemIndexStore()
+	keyRing := newTestKeyRing(t, store)
+
+	const numKeys = 3
+	seen := make(map[string]struct{})
+	var keyDescs []KeyDescriptor
+	for keyFam := KeyFamilyMultiSig; keyFam <= LastKeyFamily; keyFam++ {
+		for i := uint32(0); i < numKeys; i++ {
+			keyDesc, err := keyRing.DeriveNextKey(keyFam)
+			if err != nil {
+				t.Fatalf("unable to derive key: %v", err)
+			}
+			if keyDesc.Family != keyFam || keyDesc.Index != i {
+				t.Fatalf("expected locator (%v, %v), got (%v, %v)",
+					keyFam, i, keyDesc.Family, keyDesc.Index)
+			}
+
+			serializedKey := string(keyDesc.PubKey.SerializeCompressed())
+			if _, ok := seen[serializedKey]; ok {
+				t.Fatalf("key (%v, %v) derived twice", keyFam, i)
+			}
+			seen[serializedKey] = struct{}{}
+
+			keyDescs = append(keyDescs, keyDesc)
+		}
+	}
+
+	// A fresh key ring backed by the same store should be able to produce
+	// the private key of each public key derived above.
+	keyRing = newTestKeyRing(t, store)
+	for _, keyDesc := range keyDescs {
+		reDerived, err := keyRing.DeriveKey(keyDesc.KeyLocator)
+		if err != nil {
+			t.Fatalf("unable to derive key: %v", err)
+		}
+		if !reDerived.PubKey.IsEqual(keyDesc.PubKey) {
+			t.Fatalf("key (%v, %v) not re-derived from its locator",
+				keyDesc.Family, keyDesc.Index)
+		}
+
+		privKey, err := keyRing.FetchPrivKey(keyDesc.PubKey)
+		if err != nil {
+			t.Fatalf("unable to fetch private key: %v", err)
+		}
+		if !privKey.PubKey().IsEqual(keyDesc.PubKey) {
+			t.Fatalf("private key of (%v, %v) doesn't match public "+
+				"key", keyDesc.Family, keyDesc.Index)
+		}
+
+		keyLoc, err := keyRing.LocateKey(keyDesc.PubKey)
+		if err != nil {
+			t.Fatalf("unable to locate key: %v", err)
+		}
+		if keyLoc != keyDesc.KeyLocator {
+			t.Fatalf("expected locator (%v, %v), got (%v, %v)",
+				keyDesc.Family, keyDesc.Index, keyLoc.Family,
+				keyLoc.Index)
+		}
+	}
+
+	// The next key derived should pick up where we left off.
+	keyDesc, err := keyRing.DeriveNextKey(KeyFamilyMultiSig)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if keyDesc.Index != numKeys {
+		t.Fatalf("expected index %v, got %v", numKeys, keyDesc.Index)
+	}
+}
+
+// TestHDKeyRingLegacyNodeKey tests that the first node key is derived from the
+// legacy identity key path, preserving the identity of existing nodes.
+func TestHDKeyRingLegacyNodeKey(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemIndexStore()
+	keyRing := newTestKeyRing(t, store)
+
+	legacyKey, err := keyRing.root.Child(hdkeychain.HardenedKeyStart + 2)
+	if err != nil {
+		t.Fatalf("unable to derive legacy key: %v", err)
+	}
+	legacyPub, err := legacyKey.ECPubKey()
+	if err != nil {
+		t.Fatalf("unable to derive legacy key: %v", err)
+	}
+
+	nodeKey, err := keyRing.DeriveKey(KeyLocator{Family: KeyFamilyNodeKey})
+	if err != nil {
+		t.Fatalf("unable to derive node key: %v", err)
+	}
+	if !nodeKey.PubKey.IsEqual(legacyPub) {
+		t.Fatalf("node key not derived from legacy path")
+	}
+
+	if _, err := keyRing.FetchPrivKey(legacyPub); err != nil {
+		t.Fatalf("unable to fetch node key: %v", err)
+	}
+
+	// A key which wasn't derived by the key ring should be rejected.
+	unknownKey, err := keyRing.root.ECPubKey()
+	if err != nil {
+		t.Fatalf("unable to derive root key: %v", err)
+	}
+	if _, err := keyRing.FetchPrivKey(unknownKey); err != ErrUnknownKey {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+// TestHDKeyRingLegacyOnionKey tests that onion keys are derived from the path
+// they were derived from before joining the key ring, so the onion key in use
+// isn't changed.
+func TestHDKeyRingLegacyOnionKey(t *testing.T) {
+	t.Parallel()
+
+	keyRing := newTestKeyRing(t, NewMemIndexStore())
+
+	legacyKey, err := keyRing.deriveChild(hdkeychain.HardenedKeyStart+3,
+		hdkeychain.HardenedKeyStart+5)
+	if err != nil {
+		t.Fatalf("unable to derive legacy key: %v", err)
+	}
+	legacyPub, err := legacyKey.ECPubKey()
+	if err != nil {
+		t.Fatalf("unable to derive legacy key: %v", err)
+	}
+
+	onionKey, err := keyRing.DeriveKey(KeyLocator{
+		Family: KeyFamilyOnionKey,
+		Index:  5,
+	})
+	if err != nil {
+		t.Fatalf("unable to derive onion key: %v", err)
+	}
+	if !onionKey.PubKey.IsEqual(legacyPub) {
+		t.Fatalf("onion key not derived from legacy path")
+	}
+}
+
+// TestHDKeyRingECDH tests that the point shared between a key of the key ring
+// and another key is the same from either side.
+func TestHDKeyRingECDH(t *testing.T) {
+	t.Parallel()
+
+	keyRing := newTestKeyRing(t, NewMemIndexStore())
+
+	keyDesc, err := keyRing.DeriveNextKey(KeyFamilyRevocationRoot)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	sharedPoint, err := keyRing.ECDH(keyDesc.KeyLocator, otherKey.PubKey())
+	if err != nil {
+		t.Fatalf("unable to perform ECDH: %v", err)
+	}
+	otherPoint, err := (&PrivKeyECDH{PrivKey: otherKey}).ECDH(keyDesc.PubKey)
+	if err != nil {
+		t.Fatalf("unable to perform ECDH: %v", err)
+	}
+	if !sharedPoint.IsEqual(otherPoint) {
+		t.Fatalf("shared points don't match")
+	}
+
+	// The same point should be produced through the key's descriptor.
+	keyECDH := NewPubKeyECDH(keyDesc, keyRing)
+	if !keyECDH.PubKey().IsEqual(keyDesc.PubKey) {
+		t.Fatalf("unexpected public key")
+	}
+	descPoint, err := keyECDH.ECDH(otherKey.PubKey())
+	if err != nil {
+		t.Fatalf("unable to perform ECDH: %v", err)
+	}
+	if !descPoint.IsEqual(sharedPoint) {
+		t.Fatalf("shared points don't match")
+	}
+}
+
+// TestKeyFamilyValues tests that the value of each key family remains
+// unchanged, as the families of the keys derived for each channel are
+// persisted, and keys are re-derived from them.
+func TestKeyFamilyValues(t *testing.T) {
+	t.Parallel()
+
+	families := map[KeyFamily]uint32{
+		KeyFamilyMultiSig:       0,
+		KeyFamilyCommitKey:      3,
+		KeyFamilyRevocationRoot: 5,
+		KeyFamilyNodeKey:        6,
+		KeyFamilyStaticBackup:   7,
+		KeyFamilyOnionKey:       8,
+	}
+	for keyFam, value := range families {
+		if uint32(keyFam) != value {
+			t.Fatalf("expected %v family to have value %v, got %v",
+				keyFam, value, uint32(keyFam))
+		}
+	}
+}
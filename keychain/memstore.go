@@ -0,0 +1,49 @@
+package keychain
+
+import "sync"
+
+// MemIndexStore is an in-memory implementation of the IndexStore interface.
+// As the indexes it reserves aren't persisted, it's only suitable for key
+// rings whose keys needn't be derived anew across restarts, such as those
+// created within tests.
+type MemIndexStore struct {
+	indexes map[KeyFamily]uint32
+	sync.Mutex
+}
+
+// A compile time check to ensure that MemIndexStore implements the IndexStore
+// interface.
+var _ IndexStore = (*MemIndexStore)(nil)
+
+// NewMemIndexStore creates a new MemIndexStore with no indexes reserved
+// within any key family.
+func NewMemIndexStore() *MemIndexStore {
+	return &MemIndexStore{
+		indexes: make(map[KeyFamily]uint32),
+	}
+}
+
+// NextKeyIndex reserves and returns the next unused index within the passed
+// key family.
+//
+// NOTE: This is part of the IndexStore interface.
+func (m *MemIndexStore) NextKeyIndex(keyFam KeyFamily) (uint32, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	index := m.indexes[keyFam]
+	m.indexes[keyFam]++
+
+	return index, nil
+}
+
+// FetchKeyIndex returns the number of indexes reserved within the passed key
+// family.
+//
+// NOTE: This is part of the IndexStore interface.
+func (m *MemIndexStore) FetchKeyIndex(keyFam KeyFamily) (uint32, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.indexes[keyFam], nil
+}
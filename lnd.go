@@ -1,17 +1,13 @@
 package main
 
 import (
-	"encoding/hex"
 	"fmt"
-	"io/ioutil"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strconv"
-	"strings"
 
 	"golang.org/x/net/context"
 
@@ -19,13 +15,10 @@ import (
 
 	flags "github.com/btcsuite/go-flags"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/runtime"
-	"github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
-	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
-
-	"github.com/roasbeef/btcrpcclient"
+	"github.com/lightningnetwork/lnd/lnwallet/remotesigner"
 )
 
 var (
@@ -69,84 +62,108 @@ func lndMain() error {
 	}
 	defer chanDB.Close()
 
-	// Next load btcd's TLS cert for the RPC connection. If a raw cert was
-	// specified in the config, then we'll set that directly. Otherwise, we
-	// attempt to read the cert from the path specified in the config.
-	var rpcCert []byte
-	if cfg.RawRPCCert != "" {
-		rpcCert, err = hex.DecodeString(cfg.RawRPCCert)
-		if err != nil {
-			return err
-		}
-	} else {
-		certFile, err := os.Open(cfg.RPCCert)
-		if err != nil {
-			return err
-		}
-		rpcCert, err = ioutil.ReadAll(certFile)
-		if err != nil {
-			return err
-		}
-		if err := certFile.Close(); err != nil {
-			return err
-		}
+	// Connect to the bitcoin node backing lnd, then create the
+	// ChainNotifier and WalletController drivers selected within the
+	// config on top of it.
+	backend, err := newChainBackend(cfg)
+	if err != nil {
+		fmt.Printf("unable to connect to %v: %v\n", cfg.Bitcoin.Node, err)
+		return err
 	}
+	defer backend.cleanUp()
 
-	// If the specified host for the btcd RPC server already has a port
-	// specified, then we use that directly. Otherwise, we assume the
-	// default port according to the selected chain parameters.
-	var btcdHost string
-	if strings.Contains(cfg.RPCHost, ":") {
-		btcdHost = cfg.RPCHost
-	} else {
-		btcdHost = fmt.Sprintf("%v:%v", cfg.RPCHost, activeNetParams.rpcPort)
+	notifier, err := newChainNotifier(cfg, backend)
+	if err != nil {
+		fmt.Printf("unable to create chain notifier: %v\n", err)
+		return err
 	}
 
-	btcdUser := cfg.RPCUser
-	btcdPass := cfg.RPCPass
-
-	// TODO(roasbeef): parse config here and select chosen notifier instead
-	rpcConfig := &btcrpcclient.ConnConfig{
-		Host:                 btcdHost,
-		Endpoint:             "ws",
-		User:                 btcdUser,
-		Pass:                 btcdPass,
-		Certificates:         rpcCert,
-		DisableTLS:           false,
-		DisableConnectOnNew:  true,
-		DisableAutoReconnect: false,
-	}
-	notifier, err := btcdnotify.New(rpcConfig)
+	wc, err := newWalletController(cfg, backend)
 	if err != nil {
+		fmt.Printf("unable to create wallet controller: %v\n", err)
 		return err
 	}
 
-	// TODO(roasbeef): parse config here select chosen WalletController
-	walletConfig := &btcwallet.Config{
-		PrivatePass: []byte("hello"),
-		DataDir:     filepath.Join(cfg.DataDir, "lnwallet"),
-		RPCHost:     btcdHost,
-		RPCUser:     cfg.RPCUser,
-		RPCPass:     cfg.RPCPass,
-		CACert:      rpcCert,
-		NetParams:   activeNetParams.Params,
+	// The WalletController must also be able to sign on behalf of lnd,
+	// and to query the chain.
+	signer, ok := wc.(lnwallet.Signer)
+	if !ok {
+		return fmt.Errorf("wallet controller %v doesn't implement "+
+			"lnwallet.Signer", cfg.WalletController)
 	}
-	wc, err := btcwallet.New(walletConfig)
+	bio, ok := wc.(lnwallet.BlockChainIO)
+	if !ok {
+		return fmt.Errorf("wallet controller %v doesn't implement "+
+			"lnwallet.BlockChainIO", cfg.WalletController)
+	}
+	fundingSigner, ok := wc.(lnwallet.MessageSigner)
+	if !ok {
+		return fmt.Errorf("wallet controller %v doesn't implement "+
+			"lnwallet.MessageSigner", cfg.WalletController)
+	}
+
+	// With the backend connected, we'll create the fee estimator used to
+	// price all of our on-chain transactions.
+	feeEstimator, err := newFeeEstimator(cfg, backend)
 	if err != nil {
-		fmt.Printf("unable to create wallet controller: %v\n", err)
+		fmt.Printf("unable to create fee estimator: %v\n", err)
 		return err
 	}
-	signer := wc
-	bio := wc
-	fundingSigner := wc
+	if err := feeEstimator.Start(); err != nil {
+		fmt.Printf("unable to start fee estimator: %v\n", err)
+		return err
+	}
+	defer feeEstimator.Stop()
 
 	// Create, and start the lnwallet, which handles the core payment
 	// channel logic, and exposes control via proxy state machines.
-	wallet, err := lnwallet.NewLightningWallet(chanDB, notifier, wc, signer,
-		bio, activeNetParams.Params)
-	if err != nil {
-		fmt.Printf("unable to create wallet: %v\n", err)
-		return err
+	var wallet *lnwallet.LightningWallet
+	if cfg.RemoteSigner != "" {
+		// If a remote signer was specified, then the wallet controller
+		// is watch-only, and the node holds no private keys of its
+		// own. All signing, along with the derivation of the node's
+		// keys and the secrets computed from them, is instead
+		// delegated to the signer over an authenticated gRPC
+		// connection.
+		creds, err := remotesigner.ClientCredentials(
+			cfg.RemoteSignerTLSCert, cfg.RemoteSignerTLSKey,
+			cfg.RemoteSignerCA)
+		if err != nil {
+			fmt.Printf("unable to load remote signer credentials: "+
+				"%v\n", err)
+			return err
+		}
+		conn, err := grpc.Dial(cfg.RemoteSigner,
+			grpc.WithTransportCredentials(creds))
+		if err != nil {
+			fmt.Printf("unable to connect to remote signer %v: %v\n",
+				cfg.RemoteSigner, err)
+			return err
+		}
+		defer conn.Close()
+
+		keyRing, err := remotesigner.NewKeyRing(conn, chanDB)
+		if err != nil {
+			fmt.Printf("unable to create remote key ring: %v\n",
+				err)
+			return err
+		}
+		remoteSigner := remotesigner.New(conn, keyRing)
+
+		ltndLog.Infof("Delegating signing to remote signer %v",
+			cfg.RemoteSigner)
+
+		wallet = lnwallet.NewWatchOnlyLightningWallet(chanDB, notifier,
+			wc, keyRing, remoteSigner, remoteSigner, bio,
+			feeEstimator)
+	} else {
+		wallet, err = lnwallet.NewLightningWallet(chanDB, notifier, wc,
+			signer, fundingSigner, bio, feeEstimator,
+			activeNetParams.Params)
+		if err != nil {
+			fmt.Printf("unable to create wallet: %v\n", err)
+			return err
+		}
 	}
 	if err := wallet.Startup(); err != nil {
 		fmt.Printf("unable to start wallet: %v\n", err)
@@ -160,8 +177,11 @@ func lndMain() error {
 		net.JoinHostPort("", strconv.Itoa(cfg.PeerPort)),
 	}
 
+	// The server signs its announcements through the wallet, which is
+	// able to sign with the keys derived by its key ring, whether they're
+	// held locally or by a remote signer.
 	server, err := newServer(defaultListenAddrs, notifier, bio,
-		fundingSigner, wallet, chanDB)
+		wallet.MessageSigner, wallet, chanDB)
 	if err != nil {
 		srvrLog.Errorf("unable to create server: %v\n", err)
 		return err
@@ -182,6 +202,36 @@ func lndMain() error {
 	grpcServer := grpc.NewServer(opts...)
 	lnrpc.RegisterLightningServer(grpcServer, server.rpcServer)
 
+	// If requested, we'll also expose the wallet's signer over its own
+	// listener, allowing other nodes to delegate their signing to us. As
+	// the service signs arbitrary data, it's only served to clients which
+	// authenticate with a trusted certificate.
+	if cfg.SignRPCListen != "" {
+		creds, err := remotesigner.ServerCredentials(
+			cfg.SignRPCTLSCert, cfg.SignRPCTLSKey,
+			cfg.SignRPCClientCA)
+		if err != nil {
+			fmt.Printf("unable to load signer credentials: %v\n",
+				err)
+			return err
+		}
+		signServer := grpc.NewServer(grpc.Creds(creds))
+		lnrpc.RegisterSignerServer(signServer, remotesigner.NewServer(
+			wallet.Signer, wallet.MessageSigner, wallet.KeyRing))
+
+		signLis, err := net.Listen("tcp", cfg.SignRPCListen)
+		if err != nil {
+			fmt.Printf("failed to listen: %v", err)
+			return err
+		}
+		defer signLis.Close()
+		go func() {
+			rpcsLog.Infof("Signer RPC server listening on %s",
+				signLis.Addr())
+			signServer.Serve(signLis)
+		}()
+	}
+
 	// Next, Start the grpc server listening for HTTP/2 connections.
 	grpcEndpoint := fmt.Sprintf("localhost:%d", loadedConfig.RPCPort)
 	lis, err := net.Listen("tcp", grpcEndpoint)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -21,6 +22,7 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/simchain"
 	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/rpctest"
@@ -2203,6 +2205,13 @@ var testsCases = []*testCase{
 // TestLightningNetworkDaemon performs a series of integration tests amongst a
 // programmatically driven network of lnd nodes.
 func TestLightningNetworkDaemon(t *testing.T) {
+	// The lnd nodes under test run as separate processes, so they can't
+	// share a simulated chain, and are instead backed by a btcd node.
+	// Without one, only the tests driven by the simulated chain are run.
+	if _, err := exec.LookPath("btcd"); err != nil {
+		t.Skipf("btcd not found, skipping integration tests")
+	}
+
 	ht := newHarnessTest(t)
 
 	// First create the network harness to gain access to its
@@ -2277,3 +2286,106 @@ func TestLightningNetworkDaemon(t *testing.T) {
 
 	close(testsFin)
 }
+
+// TestSimchainChainNotifier tests that lnd is able to create its ChainNotifier
+// from the simulated chain driver without a bitcoin node, and that the
+// notifier created drives confirmations as blocks are mined.
+func TestSimchainChainNotifier(t *testing.T) {
+	cfg := &config{ChainNotifier: "simchain"}
+	notifier, err := newChainNotifier(cfg, &chainBackend{})
+	if err != nil {
+		t.Fatalf("unable to create chain notifier: %v", err)
+	}
+	chain, ok := notifier.(*simchain.Chain)
+	if !ok {
+		t.Fatalf("expected simulated chain, got %T", notifier)
+	}
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start chain notifier: %v", err)
+	}
+	defer chain.Stop()
+
+	txid, err := chain.SendOutputs([]*wire.TxOut{{Value: 1e8}}, 0)
+	if err != nil {
+		t.Fatalf("unable to publish tx: %v", err)
+	}
+	confEvent, err := chain.RegisterConfirmationsNtfn(txid, 2)
+	if err != nil {
+		t.Fatalf("unable to register for confirmation: %v", err)
+	}
+
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	select {
+	case <-confEvent.Confirmed:
+		t.Fatalf("confirmation dispatched before reaching 2 confs")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	select {
+	case conf := <-confEvent.Confirmed:
+		_, bestHeight, err := chain.GetBestBlock()
+		if err != nil {
+			t.Fatalf("unable to get best block: %v", err)
+		}
+		if conf.BlockHeight != uint32(bestHeight-1) {
+			t.Fatalf("expected confirmation at height %v, got %v",
+				bestHeight-1, conf.BlockHeight)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("confirmation never dispatched")
+	}
+}
+
+// TestSimchainWalletController tests that lnd is able to create its
+// WalletController from the simulated chain driver on top of the simulated
+// chain's notifier, and that it refuses to do so on top of any other.
+func TestSimchainWalletController(t *testing.T) {
+	cfg := &config{
+		ChainNotifier:    "simchain",
+		WalletController: "simchain",
+	}
+	backend := &chainBackend{}
+	if _, err := newWalletController(cfg, backend); err == nil {
+		t.Fatalf("simchain wallet created without simchain notifier")
+	}
+
+	notifier, err := newChainNotifier(cfg, backend)
+	if err != nil {
+		t.Fatalf("unable to create chain notifier: %v", err)
+	}
+	wc, err := newWalletController(cfg, backend)
+	if err != nil {
+		t.Fatalf("unable to create wallet controller: %v", err)
+	}
+	wallet, ok := wc.(*simchain.Wallet)
+	if !ok {
+		t.Fatalf("expected simulated wallet, got %T", wc)
+	}
+
+	// The wallet should share the notifier's view of the chain.
+	chain := notifier.(*simchain.Chain)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start chain notifier: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	_, chainHeight, err := chain.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get best block: %v", err)
+	}
+	_, walletHeight, err := wallet.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get best block: %v", err)
+	}
+	if walletHeight != chainHeight {
+		t.Fatalf("wallet at height %v, chain at height %v",
+			walletHeight, chainHeight)
+	}
+}
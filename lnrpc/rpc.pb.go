@@ -85,6 +85,43 @@ It has these top-level messages:
 	DebugLevelResponse
 	PayReqString
 	PayReq
+	RotateOnionKeyRequest
+	RotateOnionKeyResponse
+	EstimateFeeRequest
+	EstimateFeeResponse
+	BumpFeeRequest
+	BumpFeeResponse
+	ExportChannelBackupRequest
+	ChannelBackup
+	ChannelBackupSubscription
+	ChanBackupSnapshot
+	RestoreChanBackupRequest
+	RestoreBackupResponse
+	TxOut
+	SignDescriptor
+	SignReq
+	SignResp
+	InputScriptResp
+	SignMessageReq
+	SignMessageResp
+	ListUnspentRequest
+	Utxo
+	ListUnspentResponse
+	ReadyForPsbtFunding
+	FundingPsbtVerify
+	FundingPsbtFinalize
+	FundingTransitionMsg
+	FundingStateStepResp
+	BatchOpenChannel
+	BatchOpenChannelRequest
+	BatchOpenChannelResponse
+	ChannelAcceptRequest
+	ChannelAcceptResponse
+	PendingReservation
+	KeyLocator
+	DeriveKeyResp
+	SharedKeyReq
+	SharedKeyResp
 */
 package lnrpc
 
@@ -385,7 +422,13 @@ func (m *LightningAddress) GetHost() string {
 }
 
 type SendManyRequest struct {
-	AddrToAmount map[string]int64 `protobuf:"bytes,1,rep,name=AddrToAmount" json:"AddrToAmount,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	AddrToAmount     map[string]int64 `protobuf:"bytes,1,rep,name=AddrToAmount" json:"AddrToAmount,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Outpoints        []string         `protobuf:"bytes,2,rep,name=outpoints" json:"outpoints,omitempty"`
+	SatPerVbyte      uint64           `protobuf:"varint,3,opt,name=sat_per_vbyte" json:"sat_per_vbyte,omitempty"`
+	TargetConf       uint32           `protobuf:"varint,4,opt,name=target_conf" json:"target_conf,omitempty"`
+	MinConfs         int32            `protobuf:"varint,5,opt,name=min_confs" json:"min_confs,omitempty"`
+	SendAll          bool             `protobuf:"varint,6,opt,name=send_all" json:"send_all,omitempty"`
+	SpendUnconfirmed bool             `protobuf:"varint,7,opt,name=spend_unconfirmed" json:"spend_unconfirmed,omitempty"`
 }
 
 func (m *SendManyRequest) Reset()                    { *m = SendManyRequest{} }
@@ -400,6 +443,48 @@ func (m *SendManyRequest) GetAddrToAmount() map[string]int64 {
 	return nil
 }
 
+func (m *SendManyRequest) GetOutpoints() []string {
+	if m != nil {
+		return m.Outpoints
+	}
+	return nil
+}
+
+func (m *SendManyRequest) GetSatPerVbyte() uint64 {
+	if m != nil {
+		return m.SatPerVbyte
+	}
+	return 0
+}
+
+func (m *SendManyRequest) GetTargetConf() uint32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *SendManyRequest) GetMinConfs() int32 {
+	if m != nil {
+		return m.MinConfs
+	}
+	return 0
+}
+
+func (m *SendManyRequest) GetSendAll() bool {
+	if m != nil {
+		return m.SendAll
+	}
+	return false
+}
+
+func (m *SendManyRequest) GetSpendUnconfirmed() bool {
+	if m != nil {
+		return m.SpendUnconfirmed
+	}
+	return false
+}
+
 type SendManyResponse struct {
 	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
 }
@@ -417,8 +502,14 @@ func (m *SendManyResponse) GetTxid() string {
 }
 
 type SendCoinsRequest struct {
-	Addr   string `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
-	Amount int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	Addr             string   `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
+	Amount           int64    `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	Outpoints        []string `protobuf:"bytes,3,rep,name=outpoints" json:"outpoints,omitempty"`
+	SatPerVbyte      uint64   `protobuf:"varint,4,opt,name=sat_per_vbyte" json:"sat_per_vbyte,omitempty"`
+	TargetConf       uint32   `protobuf:"varint,5,opt,name=target_conf" json:"target_conf,omitempty"`
+	MinConfs         int32    `protobuf:"varint,6,opt,name=min_confs" json:"min_confs,omitempty"`
+	SendAll          bool     `protobuf:"varint,7,opt,name=send_all" json:"send_all,omitempty"`
+	SpendUnconfirmed bool     `protobuf:"varint,8,opt,name=spend_unconfirmed" json:"spend_unconfirmed,omitempty"`
 }
 
 func (m *SendCoinsRequest) Reset()                    { *m = SendCoinsRequest{} }
@@ -440,6 +531,48 @@ func (m *SendCoinsRequest) GetAmount() int64 {
 	return 0
 }
 
+func (m *SendCoinsRequest) GetOutpoints() []string {
+	if m != nil {
+		return m.Outpoints
+	}
+	return nil
+}
+
+func (m *SendCoinsRequest) GetSatPerVbyte() uint64 {
+	if m != nil {
+		return m.SatPerVbyte
+	}
+	return 0
+}
+
+func (m *SendCoinsRequest) GetTargetConf() uint32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *SendCoinsRequest) GetMinConfs() int32 {
+	if m != nil {
+		return m.MinConfs
+	}
+	return 0
+}
+
+func (m *SendCoinsRequest) GetSendAll() bool {
+	if m != nil {
+		return m.SendAll
+	}
+	return false
+}
+
+func (m *SendCoinsRequest) GetSpendUnconfirmed() bool {
+	if m != nil {
+		return m.SpendUnconfirmed
+	}
+	return false
+}
+
 type SendCoinsResponse struct {
 	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
 }
@@ -597,6 +730,7 @@ type ActiveChannel struct {
 	TotalSatoshisReceived int64   `protobuf:"varint,10,opt,name=total_satoshis_received" json:"total_satoshis_received,omitempty"`
 	NumUpdates            uint64  `protobuf:"varint,11,opt,name=num_updates" json:"num_updates,omitempty"`
 	PendingHtlcs          []*HTLC `protobuf:"bytes,12,rep,name=pending_htlcs" json:"pending_htlcs,omitempty"`
+	Private               bool    `protobuf:"varint,13,opt,name=private" json:"private,omitempty"`
 }
 
 func (m *ActiveChannel) Reset()                    { *m = ActiveChannel{} }
@@ -688,6 +822,13 @@ func (m *ActiveChannel) GetPendingHtlcs() []*HTLC {
 	return nil
 }
 
+func (m *ActiveChannel) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
 type ListChannelsRequest struct {
 }
 
@@ -1185,12 +1326,20 @@ func (m *PendingUpdate) GetOutputIndex() uint32 {
 }
 
 type OpenChannelRequest struct {
-	TargetPeerId       int32  `protobuf:"varint,1,opt,name=target_peer_id" json:"target_peer_id,omitempty"`
-	NodePubkey         []byte `protobuf:"bytes,2,opt,name=node_pubkey,proto3" json:"node_pubkey,omitempty"`
-	NodePubkeyString   string `protobuf:"bytes,3,opt,name=node_pubkey_string" json:"node_pubkey_string,omitempty"`
-	LocalFundingAmount int64  `protobuf:"varint,4,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
-	PushSat            int64  `protobuf:"varint,5,opt,name=push_sat" json:"push_sat,omitempty"`
-	NumConfs           uint32 `protobuf:"varint,6,opt,name=num_confs" json:"num_confs,omitempty"`
+	TargetPeerId       int32    `protobuf:"varint,1,opt,name=target_peer_id" json:"target_peer_id,omitempty"`
+	NodePubkey         []byte   `protobuf:"bytes,2,opt,name=node_pubkey,proto3" json:"node_pubkey,omitempty"`
+	NodePubkeyString   string   `protobuf:"bytes,3,opt,name=node_pubkey_string" json:"node_pubkey_string,omitempty"`
+	LocalFundingAmount int64    `protobuf:"varint,4,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
+	PushSat            int64    `protobuf:"varint,5,opt,name=push_sat" json:"push_sat,omitempty"`
+	NumConfs           uint32   `protobuf:"varint,6,opt,name=num_confs" json:"num_confs,omitempty"`
+	Outpoints          []string `protobuf:"bytes,7,rep,name=outpoints" json:"outpoints,omitempty"`
+	SatPerVbyte        uint64   `protobuf:"varint,8,opt,name=sat_per_vbyte" json:"sat_per_vbyte,omitempty"`
+	TargetConf         uint32   `protobuf:"varint,9,opt,name=target_conf" json:"target_conf,omitempty"`
+	MinConfs           int32    `protobuf:"varint,10,opt,name=min_confs" json:"min_confs,omitempty"`
+	SendAll            bool     `protobuf:"varint,11,opt,name=send_all" json:"send_all,omitempty"`
+	PsbtFunding        bool     `protobuf:"varint,12,opt,name=psbt_funding" json:"psbt_funding,omitempty"`
+	Private            bool     `protobuf:"varint,13,opt,name=private" json:"private,omitempty"`
+	SpendUnconfirmed   bool     `protobuf:"varint,14,opt,name=spend_unconfirmed" json:"spend_unconfirmed,omitempty"`
 }
 
 func (m *OpenChannelRequest) Reset()                    { *m = OpenChannelRequest{} }
@@ -1240,11 +1389,68 @@ func (m *OpenChannelRequest) GetNumConfs() uint32 {
 	return 0
 }
 
+func (m *OpenChannelRequest) GetOutpoints() []string {
+	if m != nil {
+		return m.Outpoints
+	}
+	return nil
+}
+
+func (m *OpenChannelRequest) GetSatPerVbyte() uint64 {
+	if m != nil {
+		return m.SatPerVbyte
+	}
+	return 0
+}
+
+func (m *OpenChannelRequest) GetTargetConf() uint32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *OpenChannelRequest) GetMinConfs() int32 {
+	if m != nil {
+		return m.MinConfs
+	}
+	return 0
+}
+
+func (m *OpenChannelRequest) GetSendAll() bool {
+	if m != nil {
+		return m.SendAll
+	}
+	return false
+}
+
+func (m *OpenChannelRequest) GetPsbtFunding() bool {
+	if m != nil {
+		return m.PsbtFunding
+	}
+	return false
+}
+
+func (m *OpenChannelRequest) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *OpenChannelRequest) GetSpendUnconfirmed() bool {
+	if m != nil {
+		return m.SpendUnconfirmed
+	}
+	return false
+}
+
 type OpenStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*OpenStatusUpdate_ChanPending
 	//	*OpenStatusUpdate_Confirmation
 	//	*OpenStatusUpdate_ChanOpen
+	//	*OpenStatusUpdate_PsbtFund
 	Update isOpenStatusUpdate_Update `protobuf_oneof:"update"`
 }
 
@@ -1266,10 +1472,14 @@ type OpenStatusUpdate_Confirmation struct {
 type OpenStatusUpdate_ChanOpen struct {
 	ChanOpen *ChannelOpenUpdate `protobuf:"bytes,3,opt,name=chan_open,oneof"`
 }
+type OpenStatusUpdate_PsbtFund struct {
+	PsbtFund *ReadyForPsbtFunding `protobuf:"bytes,4,opt,name=psbt_fund,oneof"`
+}
 
 func (*OpenStatusUpdate_ChanPending) isOpenStatusUpdate_Update()  {}
 func (*OpenStatusUpdate_Confirmation) isOpenStatusUpdate_Update() {}
 func (*OpenStatusUpdate_ChanOpen) isOpenStatusUpdate_Update()     {}
+func (*OpenStatusUpdate_PsbtFund) isOpenStatusUpdate_Update()     {}
 
 func (m *OpenStatusUpdate) GetUpdate() isOpenStatusUpdate_Update {
 	if m != nil {
@@ -1299,12 +1509,20 @@ func (m *OpenStatusUpdate) GetChanOpen() *ChannelOpenUpdate {
 	return nil
 }
 
+func (m *OpenStatusUpdate) GetPsbtFund() *ReadyForPsbtFunding {
+	if x, ok := m.GetUpdate().(*OpenStatusUpdate_PsbtFund); ok {
+		return x.PsbtFund
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*OpenStatusUpdate) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _OpenStatusUpdate_OneofMarshaler, _OpenStatusUpdate_OneofUnmarshaler, _OpenStatusUpdate_OneofSizer, []interface{}{
 		(*OpenStatusUpdate_ChanPending)(nil),
 		(*OpenStatusUpdate_Confirmation)(nil),
 		(*OpenStatusUpdate_ChanOpen)(nil),
+		(*OpenStatusUpdate_PsbtFund)(nil),
 	}
 }
 
@@ -1327,6 +1545,11 @@ func _OpenStatusUpdate_OneofMarshaler(msg proto.Message, b *proto.Buffer) error
 		if err := b.EncodeMessage(x.ChanOpen); err != nil {
 			return err
 		}
+	case *OpenStatusUpdate_PsbtFund:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PsbtFund); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("OpenStatusUpdate.Update has unexpected type %T", x)
@@ -1361,6 +1584,14 @@ func _OpenStatusUpdate_OneofUnmarshaler(msg proto.Message, tag, wire int, b *pro
 		err := b.DecodeMessage(msg)
 		m.Update = &OpenStatusUpdate_ChanOpen{msg}
 		return true, err
+	case 4: // update.psbt_fund
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ReadyForPsbtFunding)
+		err := b.DecodeMessage(msg)
+		m.Update = &OpenStatusUpdate_PsbtFund{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -1385,6 +1616,11 @@ func _OpenStatusUpdate_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(3<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *OpenStatusUpdate_PsbtFund:
+		s := proto.Size(x.PsbtFund)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1409,7 +1645,8 @@ func (m *PendingChannelRequest) GetStatus() ChannelStatus {
 }
 
 type PendingChannelResponse struct {
-	PendingChannels []*PendingChannelResponse_PendingChannel `protobuf:"bytes,1,rep,name=pending_channels" json:"pending_channels,omitempty"`
+	PendingChannels     []*PendingChannelResponse_PendingChannel `protobuf:"bytes,1,rep,name=pending_channels" json:"pending_channels,omitempty"`
+	PendingReservations []*PendingReservation                    `protobuf:"bytes,2,rep,name=pending_reservations" json:"pending_reservations,omitempty"`
 }
 
 func (m *PendingChannelResponse) Reset()                    { *m = PendingChannelResponse{} }
@@ -1424,6 +1661,13 @@ func (m *PendingChannelResponse) GetPendingChannels() []*PendingChannelResponse_
 	return nil
 }
 
+func (m *PendingChannelResponse) GetPendingReservations() []*PendingReservation {
+	if m != nil {
+		return m.PendingReservations
+	}
+	return nil
+}
+
 type PendingChannelResponse_PendingChannel struct {
 	IdentityKey   string        `protobuf:"bytes,1,opt,name=identity_key" json:"identity_key,omitempty"`
 	ChannelPoint  string        `protobuf:"bytes,2,opt,name=channel_point" json:"channel_point,omitempty"`
@@ -1432,6 +1676,7 @@ type PendingChannelResponse_PendingChannel struct {
 	RemoteBalance int64         `protobuf:"varint,5,opt,name=remote_balance" json:"remote_balance,omitempty"`
 	ClosingTxid   string        `protobuf:"bytes,6,opt,name=closing_txid" json:"closing_txid,omitempty"`
 	Status        ChannelStatus `protobuf:"varint,7,opt,name=status,enum=lnrpc.ChannelStatus" json:"status,omitempty"`
+	ForgetHeight  uint32        `protobuf:"varint,8,opt,name=forget_height" json:"forget_height,omitempty"`
 }
 
 func (m *PendingChannelResponse_PendingChannel) Reset()         { *m = PendingChannelResponse_PendingChannel{} }
@@ -1490,6 +1735,13 @@ func (m *PendingChannelResponse_PendingChannel) GetStatus() ChannelStatus {
 	return ChannelStatus_ALL
 }
 
+func (m *PendingChannelResponse_PendingChannel) GetForgetHeight() uint32 {
+	if m != nil {
+		return m.ForgetHeight
+	}
+	return 0
+}
+
 type WalletBalanceRequest struct {
 	WitnessOnly bool `protobuf:"varint,1,opt,name=witness_only,json=witnessOnly" json:"witness_only,omitempty"`
 }
@@ -2570,136 +2822,1312 @@ func (m *PayReq) GetNumSatoshis() int64 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
-	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
-	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
-	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
-	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
-	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
-	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
-	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
-	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
-	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
-	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
-	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
-	proto.RegisterType((*NewWitnessAddressRequest)(nil), "lnrpc.NewWitnessAddressRequest")
-	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
-	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
-	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
-	proto.RegisterType((*HTLC)(nil), "lnrpc.HTLC")
-	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
-	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
-	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
-	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
-	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
-	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
-	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
-	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
-	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
-	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
-	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
-	proto.RegisterType((*CloseChannelRequest)(nil), "lnrpc.CloseChannelRequest")
-	proto.RegisterType((*CloseStatusUpdate)(nil), "lnrpc.CloseStatusUpdate")
-	proto.RegisterType((*PendingUpdate)(nil), "lnrpc.PendingUpdate")
-	proto.RegisterType((*OpenChannelRequest)(nil), "lnrpc.OpenChannelRequest")
-	proto.RegisterType((*OpenStatusUpdate)(nil), "lnrpc.OpenStatusUpdate")
-	proto.RegisterType((*PendingChannelRequest)(nil), "lnrpc.PendingChannelRequest")
-	proto.RegisterType((*PendingChannelResponse)(nil), "lnrpc.PendingChannelResponse")
-	proto.RegisterType((*PendingChannelResponse_PendingChannel)(nil), "lnrpc.PendingChannelResponse.PendingChannel")
-	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
-	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
-	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
-	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
-	proto.RegisterType((*QueryRoutesRequest)(nil), "lnrpc.QueryRoutesRequest")
-	proto.RegisterType((*QueryRoutesResponse)(nil), "lnrpc.QueryRoutesResponse")
-	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
-	proto.RegisterType((*Route)(nil), "lnrpc.Route")
-	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
-	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
-	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
-	proto.RegisterType((*NodeAddress)(nil), "lnrpc.NodeAddress")
-	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
-	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
-	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
-	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
-	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
-	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
-	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
-	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
-	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
-	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
-	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
-	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
-	proto.RegisterType((*SetAliasRequest)(nil), "lnrpc.SetAliasRequest")
-	proto.RegisterType((*SetAliasResponse)(nil), "lnrpc.SetAliasResponse")
-	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
-	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
-	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
-	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
-	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
-	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
-	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
-	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
-	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
-	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
-	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
-	proto.RegisterType((*DebugLevelRequest)(nil), "lnrpc.DebugLevelRequest")
-	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
-	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
-	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
-	proto.RegisterEnum("lnrpc.ChannelStatus", ChannelStatus_name, ChannelStatus_value)
-	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+type RotateOnionKeyRequest struct {
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *RotateOnionKeyRequest) Reset()                    { *m = RotateOnionKeyRequest{} }
+func (m *RotateOnionKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*RotateOnionKeyRequest) ProtoMessage()               {}
+func (*RotateOnionKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+type RotateOnionKeyResponse struct {
+	OnionKey string `protobuf:"bytes,1,opt,name=onion_key" json:"onion_key,omitempty"`
+}
 
-// Client API for Lightning service
+func (m *RotateOnionKeyResponse) Reset()                    { *m = RotateOnionKeyResponse{} }
+func (m *RotateOnionKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*RotateOnionKeyResponse) ProtoMessage()               {}
+func (*RotateOnionKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
 
-type LightningClient interface {
-	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
-	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
-	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
-	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
-	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
-	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
-	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
-	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
-	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
-	// TODO(roasbeef): merge with below with bool?
-	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
-	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
-	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
-	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
-	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
-	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
-	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
-	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
-	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
-	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
-	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
-	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
-	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
-	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
-	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
-	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
-	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
-	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
-	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
-	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
-	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
-	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+func (m *RotateOnionKeyResponse) GetOnionKey() string {
+	if m != nil {
+		return m.OnionKey
+	}
+	return ""
 }
 
-type lightningClient struct {
+type EstimateFeeRequest struct {
+	TargetConf int32 `protobuf:"varint,1,opt,name=target_conf" json:"target_conf,omitempty"`
+}
+
+func (m *EstimateFeeRequest) Reset()                    { *m = EstimateFeeRequest{} }
+func (m *EstimateFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeRequest) ProtoMessage()               {}
+func (*EstimateFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+func (m *EstimateFeeRequest) GetTargetConf() int32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+type EstimateFeeResponse struct {
+	FeePerWeight int64 `protobuf:"varint,1,opt,name=fee_per_weight" json:"fee_per_weight,omitempty"`
+	FeePerByte   int64 `protobuf:"varint,2,opt,name=fee_per_byte" json:"fee_per_byte,omitempty"`
+}
+
+func (m *EstimateFeeResponse) Reset()                    { *m = EstimateFeeResponse{} }
+func (m *EstimateFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeResponse) ProtoMessage()               {}
+func (*EstimateFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+func (m *EstimateFeeResponse) GetFeePerWeight() int64 {
+	if m != nil {
+		return m.FeePerWeight
+	}
+	return 0
+}
+
+func (m *EstimateFeeResponse) GetFeePerByte() int64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+type BumpFeeRequest struct {
+	Txid         string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Outpoint     string `protobuf:"bytes,2,opt,name=outpoint" json:"outpoint,omitempty"`
+	TargetConf   int32  `protobuf:"varint,3,opt,name=target_conf" json:"target_conf,omitempty"`
+	FeePerWeight int64  `protobuf:"varint,4,opt,name=fee_per_weight" json:"fee_per_weight,omitempty"`
+}
+
+func (m *BumpFeeRequest) Reset()                    { *m = BumpFeeRequest{} }
+func (m *BumpFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeRequest) ProtoMessage()               {}
+func (*BumpFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+
+func (m *BumpFeeRequest) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *BumpFeeRequest) GetOutpoint() string {
+	if m != nil {
+		return m.Outpoint
+	}
+	return ""
+}
+
+func (m *BumpFeeRequest) GetTargetConf() int32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *BumpFeeRequest) GetFeePerWeight() int64 {
+	if m != nil {
+		return m.FeePerWeight
+	}
+	return 0
+}
+
+type BumpFeeResponse struct {
+	Txid     string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Replaced bool   `protobuf:"varint,2,opt,name=replaced" json:"replaced,omitempty"`
+	Fee      int64  `protobuf:"varint,3,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *BumpFeeResponse) Reset()                    { *m = BumpFeeResponse{} }
+func (m *BumpFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeResponse) ProtoMessage()               {}
+func (*BumpFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+func (m *BumpFeeResponse) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *BumpFeeResponse) GetReplaced() bool {
+	if m != nil {
+		return m.Replaced
+	}
+	return false
+}
+
+func (m *BumpFeeResponse) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+type ExportChannelBackupRequest struct {
+	ChanPoint *ChannelPoint `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+}
+
+func (m *ExportChannelBackupRequest) Reset()                    { *m = ExportChannelBackupRequest{} }
+func (m *ExportChannelBackupRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportChannelBackupRequest) ProtoMessage()               {}
+func (*ExportChannelBackupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *ExportChannelBackupRequest) GetChanPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return nil
+}
+
+type ChannelBackup struct {
+	ChanPoint  *ChannelPoint `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+	ChanBackup []byte        `protobuf:"bytes,2,opt,name=chan_backup,proto3" json:"chan_backup,omitempty"`
+}
+
+func (m *ChannelBackup) Reset()                    { *m = ChannelBackup{} }
+func (m *ChannelBackup) String() string            { return proto.CompactTextString(m) }
+func (*ChannelBackup) ProtoMessage()               {}
+func (*ChannelBackup) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+
+func (m *ChannelBackup) GetChanPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return nil
+}
+
+func (m *ChannelBackup) GetChanBackup() []byte {
+	if m != nil {
+		return m.ChanBackup
+	}
+	return nil
+}
+
+type ChannelBackupSubscription struct {
+}
+
+func (m *ChannelBackupSubscription) Reset()                    { *m = ChannelBackupSubscription{} }
+func (m *ChannelBackupSubscription) String() string            { return proto.CompactTextString(m) }
+func (*ChannelBackupSubscription) ProtoMessage()               {}
+func (*ChannelBackupSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+type ChanBackupSnapshot struct {
+	ChanPoints      []*ChannelPoint `protobuf:"bytes,1,rep,name=chan_points" json:"chan_points,omitempty"`
+	MultiChanBackup []byte          `protobuf:"bytes,2,opt,name=multi_chan_backup,proto3" json:"multi_chan_backup,omitempty"`
+}
+
+func (m *ChanBackupSnapshot) Reset()                    { *m = ChanBackupSnapshot{} }
+func (m *ChanBackupSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*ChanBackupSnapshot) ProtoMessage()               {}
+func (*ChanBackupSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *ChanBackupSnapshot) GetChanPoints() []*ChannelPoint {
+	if m != nil {
+		return m.ChanPoints
+	}
+	return nil
+}
+
+func (m *ChanBackupSnapshot) GetMultiChanBackup() []byte {
+	if m != nil {
+		return m.MultiChanBackup
+	}
+	return nil
+}
+
+type RestoreChanBackupRequest struct {
+	ChanBackups     [][]byte `protobuf:"bytes,1,rep,name=chan_backups,proto3" json:"chan_backups,omitempty"`
+	MultiChanBackup []byte   `protobuf:"bytes,2,opt,name=multi_chan_backup,proto3" json:"multi_chan_backup,omitempty"`
+}
+
+func (m *RestoreChanBackupRequest) Reset()                    { *m = RestoreChanBackupRequest{} }
+func (m *RestoreChanBackupRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestoreChanBackupRequest) ProtoMessage()               {}
+func (*RestoreChanBackupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
+func (m *RestoreChanBackupRequest) GetChanBackups() [][]byte {
+	if m != nil {
+		return m.ChanBackups
+	}
+	return nil
+}
+
+func (m *RestoreChanBackupRequest) GetMultiChanBackup() []byte {
+	if m != nil {
+		return m.MultiChanBackup
+	}
+	return nil
+}
+
+type RestoreBackupResponse struct {
+}
+
+func (m *RestoreBackupResponse) Reset()                    { *m = RestoreBackupResponse{} }
+func (m *RestoreBackupResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestoreBackupResponse) ProtoMessage()               {}
+func (*RestoreBackupResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+
+type TxOut struct {
+	Value    int64  `protobuf:"varint,1,opt,name=value" json:"value,omitempty"`
+	PkScript []byte `protobuf:"bytes,2,opt,name=pk_script,proto3" json:"pk_script,omitempty"`
+}
+
+func (m *TxOut) Reset()                    { *m = TxOut{} }
+func (m *TxOut) String() string            { return proto.CompactTextString(m) }
+func (*TxOut) ProtoMessage()               {}
+func (*TxOut) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
+
+func (m *TxOut) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *TxOut) GetPkScript() []byte {
+	if m != nil {
+		return m.PkScript
+	}
+	return nil
+}
+
+type SignDescriptor struct {
+	PubKey        []byte      `protobuf:"bytes,1,opt,name=pub_key,proto3" json:"pub_key,omitempty"`
+	PrivateTweak  []byte      `protobuf:"bytes,2,opt,name=private_tweak,proto3" json:"private_tweak,omitempty"`
+	WitnessScript []byte      `protobuf:"bytes,3,opt,name=witness_script,proto3" json:"witness_script,omitempty"`
+	Output        *TxOut      `protobuf:"bytes,4,opt,name=output" json:"output,omitempty"`
+	Sighash       uint32      `protobuf:"varint,5,opt,name=sighash" json:"sighash,omitempty"`
+	InputIndex    int32       `protobuf:"varint,6,opt,name=input_index" json:"input_index,omitempty"`
+	KeyLoc        *KeyLocator `protobuf:"bytes,7,opt,name=key_loc" json:"key_loc,omitempty"`
+}
+
+func (m *SignDescriptor) Reset()                    { *m = SignDescriptor{} }
+func (m *SignDescriptor) String() string            { return proto.CompactTextString(m) }
+func (*SignDescriptor) ProtoMessage()               {}
+func (*SignDescriptor) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
+
+func (m *SignDescriptor) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *SignDescriptor) GetPrivateTweak() []byte {
+	if m != nil {
+		return m.PrivateTweak
+	}
+	return nil
+}
+
+func (m *SignDescriptor) GetWitnessScript() []byte {
+	if m != nil {
+		return m.WitnessScript
+	}
+	return nil
+}
+
+func (m *SignDescriptor) GetOutput() *TxOut {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *SignDescriptor) GetSighash() uint32 {
+	if m != nil {
+		return m.Sighash
+	}
+	return 0
+}
+
+func (m *SignDescriptor) GetInputIndex() int32 {
+	if m != nil {
+		return m.InputIndex
+	}
+	return 0
+}
+
+func (m *SignDescriptor) GetKeyLoc() *KeyLocator {
+	if m != nil {
+		return m.KeyLoc
+	}
+	return nil
+}
+
+type SignReq struct {
+	RawTxBytes []byte          `protobuf:"bytes,1,opt,name=raw_tx_bytes,proto3" json:"raw_tx_bytes,omitempty"`
+	SignDesc   *SignDescriptor `protobuf:"bytes,2,opt,name=sign_desc" json:"sign_desc,omitempty"`
+}
+
+func (m *SignReq) Reset()                    { *m = SignReq{} }
+func (m *SignReq) String() string            { return proto.CompactTextString(m) }
+func (*SignReq) ProtoMessage()               {}
+func (*SignReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
+
+func (m *SignReq) GetRawTxBytes() []byte {
+	if m != nil {
+		return m.RawTxBytes
+	}
+	return nil
+}
+
+func (m *SignReq) GetSignDesc() *SignDescriptor {
+	if m != nil {
+		return m.SignDesc
+	}
+	return nil
+}
+
+type SignResp struct {
+	Sig []byte `protobuf:"bytes,1,opt,name=sig,proto3" json:"sig,omitempty"`
+}
+
+func (m *SignResp) Reset()                    { *m = SignResp{} }
+func (m *SignResp) String() string            { return proto.CompactTextString(m) }
+func (*SignResp) ProtoMessage()               {}
+func (*SignResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
+
+func (m *SignResp) GetSig() []byte {
+	if m != nil {
+		return m.Sig
+	}
+	return nil
+}
+
+type InputScriptResp struct {
+	Witness   [][]byte `protobuf:"bytes,1,rep,name=witness,proto3" json:"witness,omitempty"`
+	SigScript []byte   `protobuf:"bytes,2,opt,name=sig_script,proto3" json:"sig_script,omitempty"`
+}
+
+func (m *InputScriptResp) Reset()                    { *m = InputScriptResp{} }
+func (m *InputScriptResp) String() string            { return proto.CompactTextString(m) }
+func (*InputScriptResp) ProtoMessage()               {}
+func (*InputScriptResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
+
+func (m *InputScriptResp) GetWitness() [][]byte {
+	if m != nil {
+		return m.Witness
+	}
+	return nil
+}
+
+func (m *InputScriptResp) GetSigScript() []byte {
+	if m != nil {
+		return m.SigScript
+	}
+	return nil
+}
+
+type SignMessageReq struct {
+	PubKey []byte      `protobuf:"bytes,1,opt,name=pub_key,proto3" json:"pub_key,omitempty"`
+	Msg    []byte      `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	KeyLoc *KeyLocator `protobuf:"bytes,3,opt,name=key_loc" json:"key_loc,omitempty"`
+}
+
+func (m *SignMessageReq) Reset()                    { *m = SignMessageReq{} }
+func (m *SignMessageReq) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageReq) ProtoMessage()               {}
+func (*SignMessageReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
+
+func (m *SignMessageReq) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *SignMessageReq) GetMsg() []byte {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
+func (m *SignMessageReq) GetKeyLoc() *KeyLocator {
+	if m != nil {
+		return m.KeyLoc
+	}
+	return nil
+}
+
+type SignMessageResp struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignMessageResp) Reset()                    { *m = SignMessageResp{} }
+func (m *SignMessageResp) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageResp) ProtoMessage()               {}
+func (*SignMessageResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
+
+func (m *SignMessageResp) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ListUnspentRequest struct {
+	MinConfs int32 `protobuf:"varint,1,opt,name=min_confs" json:"min_confs,omitempty"`
+	MaxConfs int32 `protobuf:"varint,2,opt,name=max_confs" json:"max_confs,omitempty"`
+}
+
+func (m *ListUnspentRequest) Reset()                    { *m = ListUnspentRequest{} }
+func (m *ListUnspentRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListUnspentRequest) ProtoMessage()               {}
+func (*ListUnspentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{95} }
+
+func (m *ListUnspentRequest) GetMinConfs() int32 {
+	if m != nil {
+		return m.MinConfs
+	}
+	return 0
+}
+
+func (m *ListUnspentRequest) GetMaxConfs() int32 {
+	if m != nil {
+		return m.MaxConfs
+	}
+	return 0
+}
+
+type Utxo struct {
+	Outpoint      string `protobuf:"bytes,1,opt,name=outpoint" json:"outpoint,omitempty"`
+	Address       string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	AmountSat     int64  `protobuf:"varint,3,opt,name=amount_sat" json:"amount_sat,omitempty"`
+	PkScript      string `protobuf:"bytes,4,opt,name=pk_script" json:"pk_script,omitempty"`
+	Confirmations int64  `protobuf:"varint,5,opt,name=confirmations" json:"confirmations,omitempty"`
+}
+
+func (m *Utxo) Reset()                    { *m = Utxo{} }
+func (m *Utxo) String() string            { return proto.CompactTextString(m) }
+func (*Utxo) ProtoMessage()               {}
+func (*Utxo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+func (m *Utxo) GetOutpoint() string {
+	if m != nil {
+		return m.Outpoint
+	}
+	return ""
+}
+
+func (m *Utxo) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Utxo) GetAmountSat() int64 {
+	if m != nil {
+		return m.AmountSat
+	}
+	return 0
+}
+
+func (m *Utxo) GetPkScript() string {
+	if m != nil {
+		return m.PkScript
+	}
+	return ""
+}
+
+func (m *Utxo) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+type ListUnspentResponse struct {
+	Utxos []*Utxo `protobuf:"bytes,1,rep,name=utxos" json:"utxos,omitempty"`
+}
+
+func (m *ListUnspentResponse) Reset()                    { *m = ListUnspentResponse{} }
+func (m *ListUnspentResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListUnspentResponse) ProtoMessage()               {}
+func (*ListUnspentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
+
+func (m *ListUnspentResponse) GetUtxos() []*Utxo {
+	if m != nil {
+		return m.Utxos
+	}
+	return nil
+}
+
+type ReadyForPsbtFunding struct {
+	PendingChanId  []byte `protobuf:"bytes,1,opt,name=pending_chan_id,proto3" json:"pending_chan_id,omitempty"`
+	FundingAddress string `protobuf:"bytes,2,opt,name=funding_address" json:"funding_address,omitempty"`
+	FundingAmount  int64  `protobuf:"varint,3,opt,name=funding_amount" json:"funding_amount,omitempty"`
+	FundingScript  []byte `protobuf:"bytes,4,opt,name=funding_script,proto3" json:"funding_script,omitempty"`
+}
+
+func (m *ReadyForPsbtFunding) Reset()                    { *m = ReadyForPsbtFunding{} }
+func (m *ReadyForPsbtFunding) String() string            { return proto.CompactTextString(m) }
+func (*ReadyForPsbtFunding) ProtoMessage()               {}
+func (*ReadyForPsbtFunding) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+func (m *ReadyForPsbtFunding) GetPendingChanId() []byte {
+	if m != nil {
+		return m.PendingChanId
+	}
+	return nil
+}
+
+func (m *ReadyForPsbtFunding) GetFundingAddress() string {
+	if m != nil {
+		return m.FundingAddress
+	}
+	return ""
+}
+
+func (m *ReadyForPsbtFunding) GetFundingAmount() int64 {
+	if m != nil {
+		return m.FundingAmount
+	}
+	return 0
+}
+
+func (m *ReadyForPsbtFunding) GetFundingScript() []byte {
+	if m != nil {
+		return m.FundingScript
+	}
+	return nil
+}
+
+type FundingPsbtVerify struct {
+	PendingChanId []byte `protobuf:"bytes,1,opt,name=pending_chan_id,proto3" json:"pending_chan_id,omitempty"`
+	FundedPsbt    []byte `protobuf:"bytes,2,opt,name=funded_psbt,proto3" json:"funded_psbt,omitempty"`
+}
+
+func (m *FundingPsbtVerify) Reset()                    { *m = FundingPsbtVerify{} }
+func (m *FundingPsbtVerify) String() string            { return proto.CompactTextString(m) }
+func (*FundingPsbtVerify) ProtoMessage()               {}
+func (*FundingPsbtVerify) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+func (m *FundingPsbtVerify) GetPendingChanId() []byte {
+	if m != nil {
+		return m.PendingChanId
+	}
+	return nil
+}
+
+func (m *FundingPsbtVerify) GetFundedPsbt() []byte {
+	if m != nil {
+		return m.FundedPsbt
+	}
+	return nil
+}
+
+type FundingPsbtFinalize struct {
+	PendingChanId []byte `protobuf:"bytes,1,opt,name=pending_chan_id,proto3" json:"pending_chan_id,omitempty"`
+	SignedPsbt    []byte `protobuf:"bytes,2,opt,name=signed_psbt,proto3" json:"signed_psbt,omitempty"`
+	FinalRawTx    []byte `protobuf:"bytes,3,opt,name=final_raw_tx,proto3" json:"final_raw_tx,omitempty"`
+}
+
+func (m *FundingPsbtFinalize) Reset()                    { *m = FundingPsbtFinalize{} }
+func (m *FundingPsbtFinalize) String() string            { return proto.CompactTextString(m) }
+func (*FundingPsbtFinalize) ProtoMessage()               {}
+func (*FundingPsbtFinalize) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
+func (m *FundingPsbtFinalize) GetPendingChanId() []byte {
+	if m != nil {
+		return m.PendingChanId
+	}
+	return nil
+}
+
+func (m *FundingPsbtFinalize) GetSignedPsbt() []byte {
+	if m != nil {
+		return m.SignedPsbt
+	}
+	return nil
+}
+
+func (m *FundingPsbtFinalize) GetFinalRawTx() []byte {
+	if m != nil {
+		return m.FinalRawTx
+	}
+	return nil
+}
+
+type FundingTransitionMsg struct {
+	// Types that are valid to be assigned to Trigger:
+	//	*FundingTransitionMsg_PsbtVerify
+	//	*FundingTransitionMsg_PsbtFinalize
+	Trigger isFundingTransitionMsg_Trigger `protobuf_oneof:"trigger"`
+}
+
+func (m *FundingTransitionMsg) Reset()                    { *m = FundingTransitionMsg{} }
+func (m *FundingTransitionMsg) String() string            { return proto.CompactTextString(m) }
+func (*FundingTransitionMsg) ProtoMessage()               {}
+func (*FundingTransitionMsg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{101} }
+
+type isFundingTransitionMsg_Trigger interface {
+	isFundingTransitionMsg_Trigger()
+}
+
+type FundingTransitionMsg_PsbtVerify struct {
+	PsbtVerify *FundingPsbtVerify `protobuf:"bytes,1,opt,name=psbt_verify,oneof"`
+}
+type FundingTransitionMsg_PsbtFinalize struct {
+	PsbtFinalize *FundingPsbtFinalize `protobuf:"bytes,2,opt,name=psbt_finalize,oneof"`
+}
+
+func (*FundingTransitionMsg_PsbtVerify) isFundingTransitionMsg_Trigger()   {}
+func (*FundingTransitionMsg_PsbtFinalize) isFundingTransitionMsg_Trigger() {}
+
+func (m *FundingTransitionMsg) GetTrigger() isFundingTransitionMsg_Trigger {
+	if m != nil {
+		return m.Trigger
+	}
+	return nil
+}
+
+func (m *FundingTransitionMsg) GetPsbtVerify() *FundingPsbtVerify {
+	if x, ok := m.GetTrigger().(*FundingTransitionMsg_PsbtVerify); ok {
+		return x.PsbtVerify
+	}
+	return nil
+}
+
+func (m *FundingTransitionMsg) GetPsbtFinalize() *FundingPsbtFinalize {
+	if x, ok := m.GetTrigger().(*FundingTransitionMsg_PsbtFinalize); ok {
+		return x.PsbtFinalize
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*FundingTransitionMsg) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _FundingTransitionMsg_OneofMarshaler, _FundingTransitionMsg_OneofUnmarshaler, _FundingTransitionMsg_OneofSizer, []interface{}{
+		(*FundingTransitionMsg_PsbtVerify)(nil),
+		(*FundingTransitionMsg_PsbtFinalize)(nil),
+	}
+}
+
+func _FundingTransitionMsg_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*FundingTransitionMsg)
+	// trigger
+	switch x := m.Trigger.(type) {
+	case *FundingTransitionMsg_PsbtVerify:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PsbtVerify); err != nil {
+			return err
+		}
+	case *FundingTransitionMsg_PsbtFinalize:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PsbtFinalize); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("FundingTransitionMsg.Trigger has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _FundingTransitionMsg_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*FundingTransitionMsg)
+	switch tag {
+	case 1: // trigger.psbt_verify
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FundingPsbtVerify)
+		err := b.DecodeMessage(msg)
+		m.Trigger = &FundingTransitionMsg_PsbtVerify{msg}
+		return true, err
+	case 2: // trigger.psbt_finalize
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FundingPsbtFinalize)
+		err := b.DecodeMessage(msg)
+		m.Trigger = &FundingTransitionMsg_PsbtFinalize{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _FundingTransitionMsg_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*FundingTransitionMsg)
+	// trigger
+	switch x := m.Trigger.(type) {
+	case *FundingTransitionMsg_PsbtVerify:
+		s := proto.Size(x.PsbtVerify)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *FundingTransitionMsg_PsbtFinalize:
+		s := proto.Size(x.PsbtFinalize)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type FundingStateStepResp struct {
+}
+
+func (m *FundingStateStepResp) Reset()                    { *m = FundingStateStepResp{} }
+func (m *FundingStateStepResp) String() string            { return proto.CompactTextString(m) }
+func (*FundingStateStepResp) ProtoMessage()               {}
+func (*FundingStateStepResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{102} }
+
+type BatchOpenChannel struct {
+	NodePubkey         []byte `protobuf:"bytes,1,opt,name=node_pubkey,proto3" json:"node_pubkey,omitempty"`
+	LocalFundingAmount int64  `protobuf:"varint,2,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
+	PushSat            int64  `protobuf:"varint,3,opt,name=push_sat" json:"push_sat,omitempty"`
+}
+
+func (m *BatchOpenChannel) Reset()                    { *m = BatchOpenChannel{} }
+func (m *BatchOpenChannel) String() string            { return proto.CompactTextString(m) }
+func (*BatchOpenChannel) ProtoMessage()               {}
+func (*BatchOpenChannel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{103} }
+
+func (m *BatchOpenChannel) GetNodePubkey() []byte {
+	if m != nil {
+		return m.NodePubkey
+	}
+	return nil
+}
+
+func (m *BatchOpenChannel) GetLocalFundingAmount() int64 {
+	if m != nil {
+		return m.LocalFundingAmount
+	}
+	return 0
+}
+
+func (m *BatchOpenChannel) GetPushSat() int64 {
+	if m != nil {
+		return m.PushSat
+	}
+	return 0
+}
+
+type BatchOpenChannelRequest struct {
+	Channels         []*BatchOpenChannel `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+	NumConfs         uint32              `protobuf:"varint,2,opt,name=num_confs" json:"num_confs,omitempty"`
+	Outpoints        []string            `protobuf:"bytes,3,rep,name=outpoints" json:"outpoints,omitempty"`
+	SatPerVbyte      uint64              `protobuf:"varint,4,opt,name=sat_per_vbyte" json:"sat_per_vbyte,omitempty"`
+	TargetConf       uint32              `protobuf:"varint,5,opt,name=target_conf" json:"target_conf,omitempty"`
+	MinConfs         int32               `protobuf:"varint,6,opt,name=min_confs" json:"min_confs,omitempty"`
+	SpendUnconfirmed bool                `protobuf:"varint,7,opt,name=spend_unconfirmed" json:"spend_unconfirmed,omitempty"`
+}
+
+func (m *BatchOpenChannelRequest) Reset()                    { *m = BatchOpenChannelRequest{} }
+func (m *BatchOpenChannelRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchOpenChannelRequest) ProtoMessage()               {}
+func (*BatchOpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{104} }
+
+func (m *BatchOpenChannelRequest) GetChannels() []*BatchOpenChannel {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *BatchOpenChannelRequest) GetNumConfs() uint32 {
+	if m != nil {
+		return m.NumConfs
+	}
+	return 0
+}
+
+func (m *BatchOpenChannelRequest) GetOutpoints() []string {
+	if m != nil {
+		return m.Outpoints
+	}
+	return nil
+}
+
+func (m *BatchOpenChannelRequest) GetSatPerVbyte() uint64 {
+	if m != nil {
+		return m.SatPerVbyte
+	}
+	return 0
+}
+
+func (m *BatchOpenChannelRequest) GetTargetConf() uint32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *BatchOpenChannelRequest) GetMinConfs() int32 {
+	if m != nil {
+		return m.MinConfs
+	}
+	return 0
+}
+
+func (m *BatchOpenChannelRequest) GetSpendUnconfirmed() bool {
+	if m != nil {
+		return m.SpendUnconfirmed
+	}
+	return false
+}
+
+type BatchOpenChannelResponse struct {
+	PendingChannels []*PendingUpdate `protobuf:"bytes,1,rep,name=pending_channels" json:"pending_channels,omitempty"`
+}
+
+func (m *BatchOpenChannelResponse) Reset()                    { *m = BatchOpenChannelResponse{} }
+func (m *BatchOpenChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*BatchOpenChannelResponse) ProtoMessage()               {}
+func (*BatchOpenChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{105} }
+
+func (m *BatchOpenChannelResponse) GetPendingChannels() []*PendingUpdate {
+	if m != nil {
+		return m.PendingChannels
+	}
+	return nil
+}
+
+type ChannelAcceptRequest struct {
+	NodePubkey       []byte `protobuf:"bytes,1,opt,name=node_pubkey,proto3" json:"node_pubkey,omitempty"`
+	PendingChanId    []byte `protobuf:"bytes,2,opt,name=pending_chan_id,proto3" json:"pending_chan_id,omitempty"`
+	FundingAmt       int64  `protobuf:"varint,3,opt,name=funding_amt" json:"funding_amt,omitempty"`
+	PushAmt          int64  `protobuf:"varint,4,opt,name=push_amt" json:"push_amt,omitempty"`
+	CsvDelay         uint32 `protobuf:"varint,5,opt,name=csv_delay" json:"csv_delay,omitempty"`
+	DustLimit        int64  `protobuf:"varint,6,opt,name=dust_limit" json:"dust_limit,omitempty"`
+	ChannelReserve   int64  `protobuf:"varint,7,opt,name=channel_reserve" json:"channel_reserve,omitempty"`
+	MaxValueInFlight int64  `protobuf:"varint,8,opt,name=max_value_in_flight" json:"max_value_in_flight,omitempty"`
+	MaxAcceptedHtlcs uint32 `protobuf:"varint,9,opt,name=max_accepted_htlcs" json:"max_accepted_htlcs,omitempty"`
+	NumConfs         uint32 `protobuf:"varint,10,opt,name=num_confs" json:"num_confs,omitempty"`
+	RequestId        uint64 `protobuf:"varint,11,opt,name=request_id" json:"request_id,omitempty"`
+}
+
+func (m *ChannelAcceptRequest) Reset()                    { *m = ChannelAcceptRequest{} }
+func (m *ChannelAcceptRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelAcceptRequest) ProtoMessage()               {}
+func (*ChannelAcceptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{106} }
+
+func (m *ChannelAcceptRequest) GetNodePubkey() []byte {
+	if m != nil {
+		return m.NodePubkey
+	}
+	return nil
+}
+
+func (m *ChannelAcceptRequest) GetPendingChanId() []byte {
+	if m != nil {
+		return m.PendingChanId
+	}
+	return nil
+}
+
+func (m *ChannelAcceptRequest) GetFundingAmt() int64 {
+	if m != nil {
+		return m.FundingAmt
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetPushAmt() int64 {
+	if m != nil {
+		return m.PushAmt
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetCsvDelay() uint32 {
+	if m != nil {
+		return m.CsvDelay
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetDustLimit() int64 {
+	if m != nil {
+		return m.DustLimit
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetChannelReserve() int64 {
+	if m != nil {
+		return m.ChannelReserve
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetMaxValueInFlight() int64 {
+	if m != nil {
+		return m.MaxValueInFlight
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetMaxAcceptedHtlcs() uint32 {
+	if m != nil {
+		return m.MaxAcceptedHtlcs
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetNumConfs() uint32 {
+	if m != nil {
+		return m.NumConfs
+	}
+	return 0
+}
+
+func (m *ChannelAcceptRequest) GetRequestId() uint64 {
+	if m != nil {
+		return m.RequestId
+	}
+	return 0
+}
+
+type ChannelAcceptResponse struct {
+	PendingChanId []byte `protobuf:"bytes,1,opt,name=pending_chan_id,proto3" json:"pending_chan_id,omitempty"`
+	Accept        bool   `protobuf:"varint,2,opt,name=accept" json:"accept,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	RequestId     uint64 `protobuf:"varint,4,opt,name=request_id" json:"request_id,omitempty"`
+}
+
+func (m *ChannelAcceptResponse) Reset()                    { *m = ChannelAcceptResponse{} }
+func (m *ChannelAcceptResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelAcceptResponse) ProtoMessage()               {}
+func (*ChannelAcceptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{107} }
+
+func (m *ChannelAcceptResponse) GetPendingChanId() []byte {
+	if m != nil {
+		return m.PendingChanId
+	}
+	return nil
+}
+
+func (m *ChannelAcceptResponse) GetAccept() bool {
+	if m != nil {
+		return m.Accept
+	}
+	return false
+}
+
+func (m *ChannelAcceptResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ChannelAcceptResponse) GetRequestId() uint64 {
+	if m != nil {
+		return m.RequestId
+	}
+	return 0
+}
+
+type PendingReservation struct {
+	IdentityKey   string `protobuf:"bytes,1,opt,name=identity_key" json:"identity_key,omitempty"`
+	PendingChanId []byte `protobuf:"bytes,2,opt,name=pending_chan_id,proto3" json:"pending_chan_id,omitempty"`
+	Capacity      int64  `protobuf:"varint,3,opt,name=capacity" json:"capacity,omitempty"`
+	Initiator     bool   `protobuf:"varint,4,opt,name=initiator" json:"initiator,omitempty"`
+	ExpiresIn     int64  `protobuf:"varint,5,opt,name=expires_in" json:"expires_in,omitempty"`
+}
+
+func (m *PendingReservation) Reset()                    { *m = PendingReservation{} }
+func (m *PendingReservation) String() string            { return proto.CompactTextString(m) }
+func (*PendingReservation) ProtoMessage()               {}
+func (*PendingReservation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{108} }
+
+func (m *PendingReservation) GetIdentityKey() string {
+	if m != nil {
+		return m.IdentityKey
+	}
+	return ""
+}
+
+func (m *PendingReservation) GetPendingChanId() []byte {
+	if m != nil {
+		return m.PendingChanId
+	}
+	return nil
+}
+
+func (m *PendingReservation) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *PendingReservation) GetInitiator() bool {
+	if m != nil {
+		return m.Initiator
+	}
+	return false
+}
+
+func (m *PendingReservation) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+type KeyLocator struct {
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family" json:"key_family,omitempty"`
+	KeyIndex  uint32 `protobuf:"varint,2,opt,name=key_index" json:"key_index,omitempty"`
+}
+
+func (m *KeyLocator) Reset()                    { *m = KeyLocator{} }
+func (m *KeyLocator) String() string            { return proto.CompactTextString(m) }
+func (*KeyLocator) ProtoMessage()               {}
+func (*KeyLocator) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{109} }
+
+func (m *KeyLocator) GetKeyFamily() uint32 {
+	if m != nil {
+		return m.KeyFamily
+	}
+	return 0
+}
+
+func (m *KeyLocator) GetKeyIndex() uint32 {
+	if m != nil {
+		return m.KeyIndex
+	}
+	return 0
+}
+
+type DeriveKeyResp struct {
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,proto3" json:"pub_key,omitempty"`
+}
+
+func (m *DeriveKeyResp) Reset()                    { *m = DeriveKeyResp{} }
+func (m *DeriveKeyResp) String() string            { return proto.CompactTextString(m) }
+func (*DeriveKeyResp) ProtoMessage()               {}
+func (*DeriveKeyResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{110} }
+
+func (m *DeriveKeyResp) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+type SharedKeyReq struct {
+	KeyLoc          *KeyLocator `protobuf:"bytes,1,opt,name=key_loc" json:"key_loc,omitempty"`
+	EphemeralPubkey []byte      `protobuf:"bytes,2,opt,name=ephemeral_pubkey,proto3" json:"ephemeral_pubkey,omitempty"`
+}
+
+func (m *SharedKeyReq) Reset()                    { *m = SharedKeyReq{} }
+func (m *SharedKeyReq) String() string            { return proto.CompactTextString(m) }
+func (*SharedKeyReq) ProtoMessage()               {}
+func (*SharedKeyReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{111} }
+
+func (m *SharedKeyReq) GetKeyLoc() *KeyLocator {
+	if m != nil {
+		return m.KeyLoc
+	}
+	return nil
+}
+
+func (m *SharedKeyReq) GetEphemeralPubkey() []byte {
+	if m != nil {
+		return m.EphemeralPubkey
+	}
+	return nil
+}
+
+type SharedKeyResp struct {
+	SharedPoint []byte `protobuf:"bytes,1,opt,name=shared_point,proto3" json:"shared_point,omitempty"`
+}
+
+func (m *SharedKeyResp) Reset()                    { *m = SharedKeyResp{} }
+func (m *SharedKeyResp) String() string            { return proto.CompactTextString(m) }
+func (*SharedKeyResp) ProtoMessage()               {}
+func (*SharedKeyResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{112} }
+
+func (m *SharedKeyResp) GetSharedPoint() []byte {
+	if m != nil {
+		return m.SharedPoint
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
+	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
+	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
+	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
+	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
+	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
+	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
+	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
+	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
+	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
+	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
+	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
+	proto.RegisterType((*NewWitnessAddressRequest)(nil), "lnrpc.NewWitnessAddressRequest")
+	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
+	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
+	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
+	proto.RegisterType((*HTLC)(nil), "lnrpc.HTLC")
+	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
+	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
+	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
+	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
+	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
+	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
+	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
+	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
+	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
+	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
+	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
+	proto.RegisterType((*CloseChannelRequest)(nil), "lnrpc.CloseChannelRequest")
+	proto.RegisterType((*CloseStatusUpdate)(nil), "lnrpc.CloseStatusUpdate")
+	proto.RegisterType((*PendingUpdate)(nil), "lnrpc.PendingUpdate")
+	proto.RegisterType((*OpenChannelRequest)(nil), "lnrpc.OpenChannelRequest")
+	proto.RegisterType((*OpenStatusUpdate)(nil), "lnrpc.OpenStatusUpdate")
+	proto.RegisterType((*PendingChannelRequest)(nil), "lnrpc.PendingChannelRequest")
+	proto.RegisterType((*PendingChannelResponse)(nil), "lnrpc.PendingChannelResponse")
+	proto.RegisterType((*PendingChannelResponse_PendingChannel)(nil), "lnrpc.PendingChannelResponse.PendingChannel")
+	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
+	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
+	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
+	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
+	proto.RegisterType((*QueryRoutesRequest)(nil), "lnrpc.QueryRoutesRequest")
+	proto.RegisterType((*QueryRoutesResponse)(nil), "lnrpc.QueryRoutesResponse")
+	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
+	proto.RegisterType((*Route)(nil), "lnrpc.Route")
+	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
+	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
+	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
+	proto.RegisterType((*NodeAddress)(nil), "lnrpc.NodeAddress")
+	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
+	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
+	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
+	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
+	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
+	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
+	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
+	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
+	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
+	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
+	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
+	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
+	proto.RegisterType((*SetAliasRequest)(nil), "lnrpc.SetAliasRequest")
+	proto.RegisterType((*SetAliasResponse)(nil), "lnrpc.SetAliasResponse")
+	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
+	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
+	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
+	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
+	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
+	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
+	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
+	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
+	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
+	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
+	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
+	proto.RegisterType((*DebugLevelRequest)(nil), "lnrpc.DebugLevelRequest")
+	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
+	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
+	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
+	proto.RegisterType((*RotateOnionKeyRequest)(nil), "lnrpc.RotateOnionKeyRequest")
+	proto.RegisterType((*RotateOnionKeyResponse)(nil), "lnrpc.RotateOnionKeyResponse")
+	proto.RegisterType((*EstimateFeeRequest)(nil), "lnrpc.EstimateFeeRequest")
+	proto.RegisterType((*EstimateFeeResponse)(nil), "lnrpc.EstimateFeeResponse")
+	proto.RegisterType((*BumpFeeRequest)(nil), "lnrpc.BumpFeeRequest")
+	proto.RegisterType((*BumpFeeResponse)(nil), "lnrpc.BumpFeeResponse")
+	proto.RegisterType((*ExportChannelBackupRequest)(nil), "lnrpc.ExportChannelBackupRequest")
+	proto.RegisterType((*ChannelBackup)(nil), "lnrpc.ChannelBackup")
+	proto.RegisterType((*ChannelBackupSubscription)(nil), "lnrpc.ChannelBackupSubscription")
+	proto.RegisterType((*ChanBackupSnapshot)(nil), "lnrpc.ChanBackupSnapshot")
+	proto.RegisterType((*RestoreChanBackupRequest)(nil), "lnrpc.RestoreChanBackupRequest")
+	proto.RegisterType((*RestoreBackupResponse)(nil), "lnrpc.RestoreBackupResponse")
+	proto.RegisterType((*TxOut)(nil), "lnrpc.TxOut")
+	proto.RegisterType((*SignDescriptor)(nil), "lnrpc.SignDescriptor")
+	proto.RegisterType((*SignReq)(nil), "lnrpc.SignReq")
+	proto.RegisterType((*SignResp)(nil), "lnrpc.SignResp")
+	proto.RegisterType((*InputScriptResp)(nil), "lnrpc.InputScriptResp")
+	proto.RegisterType((*SignMessageReq)(nil), "lnrpc.SignMessageReq")
+	proto.RegisterType((*SignMessageResp)(nil), "lnrpc.SignMessageResp")
+	proto.RegisterType((*ListUnspentRequest)(nil), "lnrpc.ListUnspentRequest")
+	proto.RegisterType((*Utxo)(nil), "lnrpc.Utxo")
+	proto.RegisterType((*ListUnspentResponse)(nil), "lnrpc.ListUnspentResponse")
+	proto.RegisterType((*ReadyForPsbtFunding)(nil), "lnrpc.ReadyForPsbtFunding")
+	proto.RegisterType((*FundingPsbtVerify)(nil), "lnrpc.FundingPsbtVerify")
+	proto.RegisterType((*FundingPsbtFinalize)(nil), "lnrpc.FundingPsbtFinalize")
+	proto.RegisterType((*FundingTransitionMsg)(nil), "lnrpc.FundingTransitionMsg")
+	proto.RegisterType((*FundingStateStepResp)(nil), "lnrpc.FundingStateStepResp")
+	proto.RegisterType((*BatchOpenChannel)(nil), "lnrpc.BatchOpenChannel")
+	proto.RegisterType((*BatchOpenChannelRequest)(nil), "lnrpc.BatchOpenChannelRequest")
+	proto.RegisterType((*BatchOpenChannelResponse)(nil), "lnrpc.BatchOpenChannelResponse")
+	proto.RegisterType((*ChannelAcceptRequest)(nil), "lnrpc.ChannelAcceptRequest")
+	proto.RegisterType((*ChannelAcceptResponse)(nil), "lnrpc.ChannelAcceptResponse")
+	proto.RegisterType((*PendingReservation)(nil), "lnrpc.PendingReservation")
+	proto.RegisterType((*KeyLocator)(nil), "lnrpc.KeyLocator")
+	proto.RegisterType((*DeriveKeyResp)(nil), "lnrpc.DeriveKeyResp")
+	proto.RegisterType((*SharedKeyReq)(nil), "lnrpc.SharedKeyReq")
+	proto.RegisterType((*SharedKeyResp)(nil), "lnrpc.SharedKeyResp")
+	proto.RegisterEnum("lnrpc.ChannelStatus", ChannelStatus_name, ChannelStatus_value)
+	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Lightning service
+
+type LightningClient interface {
+	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
+	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
+	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
+	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// TODO(roasbeef): merge with below with bool?
+	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
+	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
+	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
+	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
+	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
+	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
+	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
+	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	RotateOnionKey(ctx context.Context, in *RotateOnionKeyRequest, opts ...grpc.CallOption) (*RotateOnionKeyResponse, error)
+	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error)
+	ExportChannelBackup(ctx context.Context, in *ExportChannelBackupRequest, opts ...grpc.CallOption) (*ChannelBackup, error)
+	SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error)
+	RestoreChannelBackups(ctx context.Context, in *RestoreChanBackupRequest, opts ...grpc.CallOption) (*RestoreBackupResponse, error)
+	ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error)
+	FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error)
+	BatchOpenChannel(ctx context.Context, in *BatchOpenChannelRequest, opts ...grpc.CallOption) (*BatchOpenChannelResponse, error)
+	ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error)
+}
+
+type lightningClient struct {
 	cc *grpc.ClientConn
 }
 
@@ -3141,6 +4569,141 @@ func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest,
 	return out, nil
 }
 
+func (c *lightningClient) RotateOnionKey(ctx context.Context, in *RotateOnionKeyRequest, opts ...grpc.CallOption) (*RotateOnionKeyResponse, error) {
+	out := new(RotateOnionKeyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RotateOnionKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
+	out := new(EstimateFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/EstimateFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error) {
+	out := new(BumpFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BumpFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportChannelBackup(ctx context.Context, in *ExportChannelBackupRequest, opts ...grpc.CallOption) (*ChannelBackup, error) {
+	out := new(ChannelBackup)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportChannelBackup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[6], c.cc, "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelBackupsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelBackupsClient interface {
+	Recv() (*ChanBackupSnapshot, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelBackupsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelBackupsClient) Recv() (*ChanBackupSnapshot, error) {
+	m := new(ChanBackupSnapshot)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *RestoreChanBackupRequest, opts ...grpc.CallOption) (*RestoreBackupResponse, error) {
+	out := new(RestoreBackupResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RestoreChannelBackups", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error) {
+	out := new(ListUnspentResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error) {
+	out := new(FundingStateStepResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FundingStateStep", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BatchOpenChannel(ctx context.Context, in *BatchOpenChannelRequest, opts ...grpc.CallOption) (*BatchOpenChannelResponse, error) {
+	out := new(BatchOpenChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BatchOpenChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[7], c.cc, "/lnrpc.Lightning/ChannelAcceptor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningChannelAcceptorClient{stream}
+	return x, nil
+}
+
+type Lightning_ChannelAcceptorClient interface {
+	Send(*ChannelAcceptResponse) error
+	Recv() (*ChannelAcceptRequest, error)
+	grpc.ClientStream
+}
+
+type lightningChannelAcceptorClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningChannelAcceptorClient) Send(m *ChannelAcceptResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningChannelAcceptorClient) Recv() (*ChannelAcceptRequest, error) {
+	m := new(ChannelAcceptRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Lightning service
 
 type LightningServer interface {
@@ -3178,6 +4741,16 @@ type LightningServer interface {
 	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
 	SetAlias(context.Context, *SetAliasRequest) (*SetAliasResponse, error)
 	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	RotateOnionKey(context.Context, *RotateOnionKeyRequest) (*RotateOnionKeyResponse, error)
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	BumpFee(context.Context, *BumpFeeRequest) (*BumpFeeResponse, error)
+	ExportChannelBackup(context.Context, *ExportChannelBackupRequest) (*ChannelBackup, error)
+	SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error
+	RestoreChannelBackups(context.Context, *RestoreChanBackupRequest) (*RestoreBackupResponse, error)
+	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
+	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
+	BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error)
+	ChannelAcceptor(Lightning_ChannelAcceptorServer) error
 }
 
 func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
@@ -3606,201 +5179,392 @@ func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec f
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DecodePayReq(ctx, in)
+		return srv.(LightningServer).DecodePayReq(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAllPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DescribeGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetChanInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).QueryRoutes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/QueryRoutes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+}
+
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SetAlias(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+		FullMethod: "/lnrpc.Lightning/SetAlias",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+		return srv.(LightningServer).SetAlias(ctx, req.(*SetAliasRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPaymentsRequest)
+func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugLevelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPayments(ctx, in)
+		return srv.(LightningServer).DebugLevel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPayments",
+		FullMethod: "/lnrpc.Lightning/DebugLevel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAllPaymentsRequest)
+func _Lightning_RotateOnionKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateOnionKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+		return srv.(LightningServer).RotateOnionKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+		FullMethod: "/lnrpc.Lightning/RotateOnionKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+		return srv.(LightningServer).RotateOnionKey(ctx, req.(*RotateOnionKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelGraphRequest)
+func _Lightning_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DescribeGraph(ctx, in)
+		return srv.(LightningServer).EstimateFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+		FullMethod: "/lnrpc.Lightning/EstimateFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+		return srv.(LightningServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanInfoRequest)
+func _Lightning_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpFeeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetChanInfo(ctx, in)
+		return srv.(LightningServer).BumpFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+		FullMethod: "/lnrpc.Lightning/BumpFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+		return srv.(LightningServer).BumpFee(ctx, req.(*BumpFeeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NodeInfoRequest)
+func _Lightning_ExportChannelBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportChannelBackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNodeInfo(ctx, in)
+		return srv.(LightningServer).ExportChannelBackup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+		FullMethod: "/lnrpc.Lightning/ExportChannelBackup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+		return srv.(LightningServer).ExportChannelBackup(ctx, req.(*ExportChannelBackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryRoutesRequest)
+func _Lightning_SubscribeChannelBackups_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChannelBackupSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelBackups(m, &lightningSubscribeChannelBackupsServer{stream})
+}
+
+type Lightning_SubscribeChannelBackupsServer interface {
+	Send(*ChanBackupSnapshot) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelBackupsServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelBackupsServer) Send(m *ChanBackupSnapshot) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_RestoreChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreChanBackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).QueryRoutes(ctx, in)
+		return srv.(LightningServer).RestoreChannelBackups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/QueryRoutes",
+		FullMethod: "/lnrpc.Lightning/RestoreChannelBackups",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
+		return srv.(LightningServer).RestoreChannelBackups(ctx, req.(*RestoreChanBackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NetworkInfoRequest)
+func _Lightning_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnspentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+		return srv.(LightningServer).ListUnspent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+		FullMethod: "/lnrpc.Lightning/ListUnspent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+		return srv.(LightningServer).ListUnspent(ctx, req.(*ListUnspentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GraphTopologySubscription)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
-}
-
-type Lightning_SubscribeChannelGraphServer interface {
-	Send(*GraphTopologyUpdate) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeChannelGraphServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetAliasRequest)
+func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingTransitionMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SetAlias(ctx, in)
+		return srv.(LightningServer).FundingStateStep(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SetAlias",
+		FullMethod: "/lnrpc.Lightning/FundingStateStep",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SetAlias(ctx, req.(*SetAliasRequest))
+		return srv.(LightningServer).FundingStateStep(ctx, req.(*FundingTransitionMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugLevelRequest)
+func _Lightning_BatchOpenChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchOpenChannelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DebugLevel(ctx, in)
+		return srv.(LightningServer).BatchOpenChannel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DebugLevel",
+		FullMethod: "/lnrpc.Lightning/BatchOpenChannel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+		return srv.(LightningServer).BatchOpenChannel(ctx, req.(*BatchOpenChannelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
+}
+
+type Lightning_ChannelAcceptorServer interface {
+	Send(*ChannelAcceptRequest) error
+	Recv() (*ChannelAcceptResponse, error)
+	grpc.ServerStream
+}
+
+type lightningChannelAcceptorServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningChannelAcceptorServer) Send(m *ChannelAcceptRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningChannelAcceptorServer) Recv() (*ChannelAcceptResponse, error) {
+	m := new(ChannelAcceptResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Lightning_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "lnrpc.Lightning",
 	HandlerType: (*LightningServer)(nil),
@@ -3913,6 +5677,38 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DebugLevel",
 			Handler:    _Lightning_DebugLevel_Handler,
 		},
+		{
+			MethodName: "RotateOnionKey",
+			Handler:    _Lightning_RotateOnionKey_Handler,
+		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _Lightning_EstimateFee_Handler,
+		},
+		{
+			MethodName: "BumpFee",
+			Handler:    _Lightning_BumpFee_Handler,
+		},
+		{
+			MethodName: "ExportChannelBackup",
+			Handler:    _Lightning_ExportChannelBackup_Handler,
+		},
+		{
+			MethodName: "RestoreChannelBackups",
+			Handler:    _Lightning_RestoreChannelBackups_Handler,
+		},
+		{
+			MethodName: "ListUnspent",
+			Handler:    _Lightning_ListUnspent_Handler,
+		},
+		{
+			MethodName: "FundingStateStep",
+			Handler:    _Lightning_FundingStateStep_Handler,
+		},
+		{
+			MethodName: "BatchOpenChannel",
+			Handler:    _Lightning_BatchOpenChannel_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3946,261 +5742,570 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeChannelGraph_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeChannelBackups",
+			Handler:       _Lightning_SubscribeChannelBackups_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ChannelAcceptor",
+			Handler:       _Lightning_ChannelAcceptor_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "rpc.proto",
+}
+
+// Client API for Signer service
+
+type SignerClient interface {
+	SignOutputRaw(ctx context.Context, in *SignReq, opts ...grpc.CallOption) (*SignResp, error)
+	ComputeInputScript(ctx context.Context, in *SignReq, opts ...grpc.CallOption) (*InputScriptResp, error)
+	SignMessage(ctx context.Context, in *SignMessageReq, opts ...grpc.CallOption) (*SignMessageResp, error)
+	DeriveKey(ctx context.Context, in *KeyLocator, opts ...grpc.CallOption) (*DeriveKeyResp, error)
+	DeriveSharedKey(ctx context.Context, in *SharedKeyReq, opts ...grpc.CallOption) (*SharedKeyResp, error)
+}
+
+type signerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSignerClient(cc *grpc.ClientConn) SignerClient {
+	return &signerClient{cc}
+}
+
+func (c *signerClient) SignOutputRaw(ctx context.Context, in *SignReq, opts ...grpc.CallOption) (*SignResp, error) {
+	out := new(SignResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Signer/SignOutputRaw", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) ComputeInputScript(ctx context.Context, in *SignReq, opts ...grpc.CallOption) (*InputScriptResp, error) {
+	out := new(InputScriptResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Signer/ComputeInputScript", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) SignMessage(ctx context.Context, in *SignMessageReq, opts ...grpc.CallOption) (*SignMessageResp, error) {
+	out := new(SignMessageResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Signer/SignMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) DeriveKey(ctx context.Context, in *KeyLocator, opts ...grpc.CallOption) (*DeriveKeyResp, error) {
+	out := new(DeriveKeyResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Signer/DeriveKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) DeriveSharedKey(ctx context.Context, in *SharedKeyReq, opts ...grpc.CallOption) (*SharedKeyResp, error) {
+	out := new(SharedKeyResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Signer/DeriveSharedKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Signer service
+
+type SignerServer interface {
+	SignOutputRaw(context.Context, *SignReq) (*SignResp, error)
+	ComputeInputScript(context.Context, *SignReq) (*InputScriptResp, error)
+	SignMessage(context.Context, *SignMessageReq) (*SignMessageResp, error)
+	DeriveKey(context.Context, *KeyLocator) (*DeriveKeyResp, error)
+	DeriveSharedKey(context.Context, *SharedKeyReq) (*SharedKeyResp, error)
+}
+
+func RegisterSignerServer(s *grpc.Server, srv SignerServer) {
+	s.RegisterService(&_Signer_serviceDesc, srv)
+}
+
+func _Signer_SignOutputRaw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignOutputRaw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Signer/SignOutputRaw",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignOutputRaw(ctx, req.(*SignReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_ComputeInputScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).ComputeInputScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Signer/ComputeInputScript",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).ComputeInputScript(ctx, req.(*SignReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Signer/SignMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignMessage(ctx, req.(*SignMessageReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_DeriveKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyLocator)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).DeriveKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Signer/DeriveKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).DeriveKey(ctx, req.(*KeyLocator))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_DeriveSharedKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SharedKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).DeriveSharedKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Signer/DeriveSharedKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).DeriveSharedKey(ctx, req.(*SharedKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Signer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lnrpc.Signer",
+	HandlerType: (*SignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignOutputRaw",
+			Handler:    _Signer_SignOutputRaw_Handler,
+		},
+		{
+			MethodName: "ComputeInputScript",
+			Handler:    _Signer_ComputeInputScript_Handler,
+		},
+		{
+			MethodName: "SignMessage",
+			Handler:    _Signer_SignMessage_Handler,
+		},
+		{
+			MethodName: "DeriveKey",
+			Handler:    _Signer_DeriveKey_Handler,
+		},
+		{
+			MethodName: "DeriveSharedKey",
+			Handler:    _Signer_DeriveSharedKey_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
 }
 
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3972 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x5a, 0xcd, 0x6f, 0x1c, 0xc9,
-	0x75, 0x57, 0x0f, 0x87, 0x1f, 0xf3, 0x66, 0x86, 0x1f, 0x45, 0x8a, 0x1c, 0x0d, 0xb5, 0x6b, 0x6d,
-	0x59, 0x58, 0x31, 0xcc, 0x82, 0xd4, 0x32, 0xc1, 0x46, 0x5e, 0x25, 0x59, 0x70, 0x25, 0x5a, 0x14,
-	0x4c, 0x53, 0x74, 0x53, 0xbb, 0x72, 0x6c, 0x04, 0x93, 0xe6, 0x74, 0x71, 0xd8, 0x56, 0x4f, 0x77,
-	0x6f, 0x77, 0x0d, 0xa9, 0xb1, 0x40, 0x24, 0x70, 0x7c, 0x4b, 0x02, 0x23, 0x30, 0x90, 0xa3, 0x11,
-	0x20, 0xe7, 0x5c, 0x72, 0xcd, 0xdf, 0x10, 0xc0, 0x80, 0x4f, 0x39, 0xe4, 0x16, 0xe4, 0x9e, 0x7b,
-	0x0e, 0xc1, 0xab, 0x8f, 0xee, 0xaa, 0xee, 0xa6, 0x56, 0x41, 0x4e, 0x33, 0xf5, 0xab, 0x57, 0xaf,
-	0xaa, 0x5e, 0xbd, 0xaf, 0x7a, 0xd5, 0xd0, 0x4a, 0x93, 0xe1, 0x4e, 0x92, 0xc6, 0x3c, 0x26, 0xb3,
-	0x61, 0x94, 0x26, 0xc3, 0xfe, 0xdd, 0x51, 0x1c, 0x8f, 0x42, 0xb6, 0xeb, 0x25, 0xc1, 0xae, 0x17,
-	0x45, 0x31, 0xf7, 0x78, 0x10, 0x47, 0x99, 0x24, 0xa2, 0xff, 0xed, 0x40, 0xfb, 0x65, 0xea, 0x45,
-	0x99, 0x37, 0x44, 0x98, 0xf4, 0x60, 0x9e, 0xbf, 0x19, 0x5c, 0x78, 0xd9, 0x45, 0xcf, 0xb9, 0xe7,
-	0x6c, 0xb5, 0x5c, 0xdd, 0x24, 0xeb, 0x30, 0xe7, 0x8d, 0xe3, 0x49, 0xc4, 0x7b, 0x8d, 0x7b, 0xce,
-	0xd6, 0x8c, 0xab, 0x5a, 0xe4, 0x13, 0x58, 0x89, 0x26, 0xe3, 0xc1, 0x30, 0x8e, 0xce, 0x83, 0x74,
-	0x2c, 0x99, 0xf7, 0x66, 0xee, 0x39, 0x5b, 0xb3, 0x6e, 0xb5, 0x83, 0x7c, 0x08, 0x70, 0x16, 0xc6,
-	0xc3, 0xd7, 0x72, 0x8a, 0xa6, 0x98, 0xc2, 0x40, 0x08, 0x85, 0x8e, 0x6a, 0xb1, 0x60, 0x74, 0xc1,
-	0x7b, 0xb3, 0x82, 0x91, 0x85, 0x21, 0x0f, 0x1e, 0x8c, 0xd9, 0x20, 0xe3, 0xde, 0x38, 0xe9, 0xcd,
-	0x89, 0xd5, 0x18, 0x88, 0xe8, 0x8f, 0xb9, 0x17, 0x0e, 0xce, 0x19, 0xcb, 0x7a, 0xf3, 0xaa, 0x3f,
-	0x47, 0x68, 0x0f, 0xd6, 0x9f, 0x31, 0x6e, 0xec, 0x3a, 0x73, 0xd9, 0x37, 0x13, 0x96, 0x71, 0x7a,
-	0x04, 0xc4, 0x80, 0x9f, 0x32, 0xee, 0x05, 0x61, 0x46, 0x3e, 0x83, 0x0e, 0x37, 0x88, 0x7b, 0xce,
-	0xbd, 0x99, 0xad, 0xf6, 0x1e, 0xd9, 0x11, 0xf2, 0xdd, 0x31, 0x06, 0xb8, 0x16, 0x1d, 0xfd, 0xad,
-	0x03, 0xed, 0x53, 0x16, 0xf9, 0x8a, 0x3b, 0x21, 0xd0, 0xf4, 0x59, 0xc6, 0x85, 0x60, 0x3b, 0xae,
-	0xf8, 0x4f, 0xbe, 0x03, 0x6d, 0xfc, 0x1d, 0x64, 0x3c, 0x0d, 0xa2, 0x91, 0x10, 0x6d, 0xcb, 0x05,
-	0x84, 0x4e, 0x05, 0x42, 0x96, 0x61, 0xc6, 0x1b, 0x73, 0x21, 0xd0, 0x19, 0x17, 0xff, 0x92, 0x8f,
-	0xa0, 0x93, 0x78, 0xd3, 0x31, 0x8b, 0x78, 0x21, 0xc4, 0x8e, 0xdb, 0x56, 0xd8, 0x21, 0x4a, 0x71,
-	0x07, 0x56, 0x4d, 0x12, 0xcd, 0x7d, 0x56, 0x70, 0x5f, 0x31, 0x28, 0xd5, 0x24, 0x0f, 0x60, 0x49,
-	0xd3, 0xa7, 0x72, 0xb1, 0x42, 0xac, 0x2d, 0x77, 0x51, 0xc1, 0x5a, 0x40, 0x11, 0x74, 0xe4, 0x8e,
-	0xb2, 0x24, 0x8e, 0x32, 0x46, 0xb6, 0x61, 0x59, 0x0f, 0x4c, 0x52, 0x16, 0x8c, 0xbd, 0x11, 0x53,
-	0xdb, 0xab, 0xe0, 0x64, 0x0f, 0xba, 0xf9, 0x24, 0xf1, 0x84, 0x33, 0xb1, 0xd9, 0xf6, 0x5e, 0x47,
-	0xc9, 0xd1, 0x45, 0xcc, 0xb5, 0x49, 0xe8, 0x2f, 0x1c, 0xe8, 0x3c, 0xb9, 0xf0, 0xa2, 0x88, 0x85,
-	0x27, 0x71, 0x10, 0x71, 0xd4, 0x8f, 0xf3, 0x49, 0xe4, 0x07, 0xd1, 0x68, 0xc0, 0xdf, 0x04, 0xbe,
-	0x9a, 0xcc, 0xc2, 0x70, 0x51, 0x66, 0x1b, 0x77, 0xaf, 0x04, 0x5b, 0xc1, 0x91, 0x5f, 0x3c, 0xe1,
-	0xc9, 0x84, 0x0f, 0x82, 0xc8, 0x67, 0x6f, 0x84, 0x9c, 0xbb, 0xae, 0x85, 0xd1, 0x3f, 0x85, 0xe5,
-	0x23, 0x54, 0xbc, 0x28, 0x88, 0x46, 0xfb, 0xbe, 0x9f, 0xb2, 0x2c, 0x43, 0x6b, 0x48, 0x26, 0x67,
-	0xaf, 0xd9, 0x54, 0x99, 0x89, 0x6a, 0xe1, 0x19, 0x5f, 0xc4, 0x19, 0x57, 0xf3, 0x89, 0xff, 0xf4,
-	0x1f, 0x1d, 0x58, 0x42, 0xa9, 0xfd, 0xd0, 0x8b, 0xa6, 0x5a, 0x17, 0x8e, 0xa0, 0x83, 0xac, 0x5e,
-	0xc6, 0xfb, 0xd2, 0xa6, 0xa4, 0x4e, 0x6d, 0x29, 0x59, 0x94, 0xa8, 0x77, 0x4c, 0xd2, 0x83, 0x88,
-	0xa7, 0x53, 0xd7, 0x1a, 0xdd, 0xff, 0x02, 0x56, 0x2a, 0x24, 0xa8, 0x39, 0xc5, 0xfa, 0xf0, 0x2f,
-	0x59, 0x83, 0xd9, 0x4b, 0x2f, 0x9c, 0x30, 0x65, 0xc1, 0xb2, 0xf1, 0x79, 0xe3, 0x91, 0x43, 0x3f,
-	0x86, 0xe5, 0x62, 0x4e, 0x75, 0xb6, 0x04, 0x9a, 0xb9, 0x88, 0x5b, 0xae, 0xf8, 0x8f, 0xa2, 0x40,
-	0xba, 0x27, 0x71, 0x90, 0x1b, 0x0d, 0xd2, 0x79, 0xbe, 0x9f, 0x6a, 0x3a, 0xfc, 0x7f, 0x93, 0xb3,
-	0xa0, 0x0f, 0x60, 0xc5, 0x18, 0xff, 0x8e, 0x89, 0x7e, 0xe3, 0xc0, 0xca, 0x31, 0xbb, 0x52, 0xe2,
-	0xd6, 0x53, 0x3d, 0x82, 0x26, 0x9f, 0x26, 0x52, 0xc5, 0x16, 0xf7, 0xee, 0x2b, 0x69, 0x55, 0xe8,
-	0x76, 0x54, 0xf3, 0xe5, 0x34, 0x61, 0xae, 0x18, 0x41, 0x5f, 0x40, 0xdb, 0x00, 0xc9, 0x06, 0xac,
-	0xbe, 0x7a, 0xfe, 0xf2, 0xf8, 0xe0, 0xf4, 0x74, 0x70, 0xf2, 0xd5, 0x97, 0x3f, 0x38, 0xf8, 0xb3,
-	0xc1, 0xe1, 0xfe, 0xe9, 0xe1, 0xf2, 0x2d, 0xb2, 0x0e, 0xe4, 0xf8, 0xe0, 0xf4, 0xe5, 0xc1, 0x53,
-	0x0b, 0x77, 0xc8, 0x12, 0xb4, 0x4d, 0xa0, 0x41, 0xfb, 0xd0, 0x3b, 0x66, 0x57, 0xaf, 0x02, 0x1e,
-	0xb1, 0x2c, 0xb3, 0xa7, 0xa7, 0x3b, 0x40, 0xcc, 0x35, 0xa9, 0x6d, 0xf6, 0x60, 0xde, 0x93, 0x90,
-	0x76, 0xad, 0xaa, 0x49, 0xbf, 0x02, 0xf2, 0x24, 0x8e, 0x22, 0x36, 0xe4, 0x27, 0x8c, 0xa5, 0x7a,
-	0xb3, 0xbf, 0x6f, 0xc8, 0xb5, 0xbd, 0xb7, 0xa1, 0x36, 0x5b, 0xd6, 0x44, 0x25, 0x70, 0x02, 0xcd,
-	0x84, 0xa5, 0x63, 0x21, 0xee, 0x05, 0x57, 0xfc, 0xa7, 0xbb, 0xb0, 0x6a, 0xb1, 0x2d, 0xd6, 0x91,
-	0x30, 0x96, 0x0e, 0x94, 0xc4, 0x67, 0x5d, 0xdd, 0xa4, 0xff, 0xe2, 0x40, 0xf3, 0xf0, 0xe5, 0xd1,
-	0x13, 0xd2, 0x87, 0x85, 0x20, 0x1a, 0xc6, 0x63, 0x74, 0x1a, 0x8e, 0xe0, 0x98, 0xb7, 0x6f, 0x8c,
-	0x03, 0x77, 0xa1, 0x25, 0x7c, 0x0d, 0x7a, 0x6a, 0x61, 0x46, 0x1d, 0xb7, 0x00, 0x30, 0x4a, 0xb0,
-	0x37, 0x49, 0x90, 0x8a, 0x30, 0xa0, 0x9d, 0x7b, 0x53, 0x18, 0x5b, 0xb5, 0x03, 0x2d, 0x38, 0x65,
-	0x97, 0xf1, 0x50, 0x82, 0x3e, 0x0b, 0xbd, 0xa9, 0x70, 0x5e, 0x5d, 0xb7, 0x82, 0xd3, 0xff, 0x9a,
-	0x81, 0xee, 0xfe, 0x90, 0x07, 0x97, 0x4c, 0x39, 0x0a, 0xb1, 0x42, 0x01, 0xa8, 0xb5, 0xab, 0x16,
-	0xb9, 0x0f, 0xdd, 0x94, 0x8d, 0x63, 0xce, 0x06, 0xca, 0x74, 0xa5, 0x91, 0xda, 0x20, 0x52, 0x0d,
-	0x25, 0xa3, 0x41, 0x82, 0x2e, 0x47, 0xec, 0xa5, 0xe5, 0xda, 0x20, 0x0a, 0x11, 0x01, 0x14, 0x22,
-	0xee, 0xa2, 0xe9, 0xea, 0x26, 0xca, 0x6e, 0xe8, 0x25, 0xde, 0x30, 0xe0, 0x72, 0xcd, 0x33, 0x6e,
-	0xde, 0x46, 0xde, 0x61, 0x3c, 0xf4, 0xc2, 0xc1, 0x99, 0x17, 0x7a, 0xd1, 0x90, 0xa9, 0xe0, 0x65,
-	0x83, 0xe4, 0x63, 0x58, 0x54, 0x4b, 0xd2, 0x64, 0x32, 0x86, 0x95, 0x50, 0x94, 0xe9, 0x24, 0xca,
-	0x18, 0xe7, 0x21, 0xf3, 0x73, 0xd2, 0x05, 0x41, 0x5a, 0xed, 0x20, 0x0f, 0x61, 0x55, 0xc6, 0xc0,
-	0xcc, 0xe3, 0x71, 0x76, 0x11, 0x64, 0x83, 0x8c, 0x45, 0xbc, 0xd7, 0x12, 0xf4, 0x75, 0x5d, 0xe4,
-	0x11, 0x6c, 0x94, 0xe0, 0x94, 0x0d, 0x59, 0x70, 0xc9, 0xfc, 0x1e, 0x88, 0x51, 0x37, 0x75, 0x93,
-	0x7b, 0xd0, 0xc6, 0xd0, 0x3f, 0x49, 0x7c, 0x8f, 0xb3, 0xac, 0xd7, 0x16, 0x12, 0x32, 0x21, 0xf2,
-	0x29, 0x74, 0x13, 0x26, 0x7d, 0xf1, 0x05, 0x0f, 0x87, 0x59, 0xaf, 0x23, 0x1c, 0x60, 0x5b, 0x69,
-	0x39, 0x6a, 0xa1, 0x6b, 0x53, 0xd0, 0xdb, 0xb0, 0x7a, 0x14, 0x64, 0x5c, 0x9d, 0x72, 0x6e, 0x6c,
-	0x87, 0xb0, 0x66, 0xc3, 0x4a, 0xcd, 0x1f, 0xc2, 0x82, 0x3a, 0x32, 0x5c, 0x00, 0x32, 0x5f, 0x53,
-	0xcc, 0x2d, 0x6d, 0x71, 0x73, 0x2a, 0xfa, 0xcb, 0x06, 0x34, 0xd1, 0x52, 0x84, 0x85, 0x4c, 0xce,
-	0x06, 0x85, 0xf7, 0xd4, 0x4d, 0xd3, 0x76, 0x1a, 0x96, 0xed, 0x98, 0xd6, 0x3d, 0x63, 0x59, 0xb7,
-	0x48, 0x79, 0xa6, 0x9c, 0x29, 0x79, 0x4b, 0x6d, 0x31, 0x90, 0xa2, 0x3f, 0x65, 0xc3, 0x4b, 0xa1,
-	0x32, 0x79, 0x3f, 0x22, 0xa8, 0x50, 0x99, 0xc7, 0xe5, 0x68, 0xa9, 0x2f, 0x79, 0x5b, 0xf7, 0x89,
-	0x91, 0xf3, 0x45, 0x9f, 0x18, 0xd7, 0x83, 0xf9, 0x20, 0x3a, 0x8b, 0x27, 0x91, 0x2f, 0x94, 0x62,
-	0xc1, 0xd5, 0x4d, 0x34, 0xd5, 0x44, 0x44, 0xc1, 0x60, 0xcc, 0x94, 0x02, 0x14, 0x00, 0x25, 0x18,
-	0xee, 0x32, 0xe1, 0x33, 0x72, 0x21, 0x7f, 0x06, 0x2b, 0x06, 0xa6, 0x24, 0xfc, 0x11, 0xcc, 0xe2,
-	0xee, 0x75, 0x42, 0xa4, 0xcf, 0x4e, 0x38, 0x1b, 0xd9, 0x43, 0x97, 0x61, 0xf1, 0x19, 0xe3, 0xcf,
-	0xa3, 0xf3, 0x58, 0x73, 0xfa, 0x8f, 0x06, 0x2c, 0xe5, 0x90, 0x62, 0xb4, 0x05, 0x4b, 0x81, 0xcf,
-	0x22, 0x1e, 0xf0, 0xe9, 0xc0, 0x8a, 0xaa, 0x65, 0x18, 0x23, 0x98, 0x17, 0x06, 0x5e, 0xa6, 0x4c,
-	0x57, 0x36, 0xc8, 0x1e, 0xac, 0xa1, 0x6e, 0x69, 0x75, 0xc9, 0x8f, 0x5d, 0x06, 0xf3, 0xda, 0x3e,
-	0x34, 0x07, 0xc4, 0xa5, 0x6b, 0x28, 0x86, 0x48, 0x97, 0x54, 0xd7, 0x85, 0x52, 0x93, 0x9c, 0x70,
-	0xcb, 0xd2, 0x1b, 0x15, 0x40, 0x25, 0x71, 0x9d, 0x93, 0x89, 0x44, 0x39, 0x71, 0x35, 0x92, 0xdf,
-	0x85, 0x4a, 0xf2, 0xbb, 0x05, 0x4b, 0xd9, 0x34, 0x1a, 0x32, 0x7f, 0xc0, 0x63, 0x9c, 0x37, 0x88,
-	0xc4, 0xe9, 0x2c, 0xb8, 0x65, 0x58, 0xa4, 0xe9, 0x2c, 0xe3, 0x11, 0xe3, 0xc2, 0x14, 0x17, 0x5c,
-	0xdd, 0xa4, 0x3f, 0x17, 0xb1, 0x24, 0xcf, 0xb8, 0xbf, 0x12, 0xf6, 0x46, 0x36, 0xa1, 0x25, 0xe7,
-	0xc9, 0x2e, 0x3c, 0x95, 0x33, 0x2d, 0x08, 0xe0, 0xf4, 0xc2, 0xc3, 0x84, 0xd2, 0x5a, 0xba, 0xd4,
-	0xec, 0xb6, 0xc0, 0x0e, 0xe5, 0xca, 0xef, 0xc3, 0xa2, 0xce, 0xe5, 0xb3, 0x41, 0xc8, 0xce, 0xb9,
-	0x4e, 0x94, 0xa2, 0xc9, 0x18, 0xa7, 0xcb, 0x8e, 0xd8, 0x39, 0xa7, 0xc7, 0xb0, 0xa2, 0xac, 0xea,
-	0x45, 0xc2, 0xf4, 0xd4, 0xdf, 0x2b, 0xfb, 0x53, 0x19, 0xcf, 0x56, 0x95, 0xb6, 0x98, 0xd9, 0x5d,
-	0xc9, 0xc9, 0x52, 0x17, 0x88, 0xea, 0x7e, 0x12, 0xc6, 0x19, 0x53, 0x0c, 0x29, 0x74, 0x86, 0x61,
-	0x9c, 0x95, 0x53, 0x40, 0x13, 0x43, 0xf9, 0x64, 0x93, 0xe1, 0x10, 0xad, 0x51, 0x46, 0x44, 0xdd,
-	0xa4, 0xbf, 0x74, 0x60, 0x55, 0x70, 0xd3, 0xf6, 0x9f, 0xa7, 0x16, 0xef, 0xbf, 0xcc, 0xce, 0xd0,
-	0x4c, 0x49, 0x3f, 0x50, 0xd7, 0x91, 0x30, 0x18, 0x07, 0x3a, 0x28, 0xb6, 0x10, 0x39, 0x42, 0x00,
-	0x55, 0xf6, 0x3c, 0x4e, 0x87, 0x4c, 0x48, 0x6c, 0xc1, 0x95, 0x0d, 0xfa, 0xef, 0x0e, 0xac, 0x88,
-	0x65, 0x9c, 0x72, 0x8f, 0x4f, 0x32, 0xb5, 0xb5, 0x3f, 0x86, 0x2e, 0x6e, 0x83, 0x69, 0x75, 0x55,
-	0x8b, 0x58, 0xcb, 0x2d, 0x4b, 0xa0, 0x92, 0xf8, 0xf0, 0x96, 0x6b, 0x13, 0x93, 0x2f, 0xa0, 0x63,
-	0x5e, 0xb6, 0x54, 0x7e, 0x7d, 0x47, 0xef, 0xa0, 0xa2, 0x15, 0x87, 0xb7, 0x5c, 0x6b, 0x00, 0x79,
-	0x0c, 0x20, 0xa2, 0x98, 0x60, 0x2b, 0xd6, 0x6b, 0x0c, 0xaf, 0x1c, 0xc4, 0xe1, 0x2d, 0xd7, 0x20,
-	0xff, 0x72, 0x01, 0xe6, 0xa4, 0x73, 0xa7, 0xcf, 0xa0, 0x6b, 0xad, 0xd4, 0x4a, 0xf0, 0x3a, 0x32,
-	0xc1, 0xab, 0x24, 0xde, 0x8d, 0x9a, 0xc4, 0xfb, 0x7f, 0x1c, 0x20, 0xa8, 0x49, 0xa5, 0xa3, 0xfa,
-	0x18, 0x16, 0xb9, 0x97, 0x8e, 0x18, 0x1f, 0xd8, 0x79, 0x4c, 0x09, 0x15, 0x51, 0x28, 0xf6, 0xad,
-	0x68, 0xdf, 0x71, 0x4d, 0x88, 0xec, 0x00, 0x31, 0x9a, 0xfa, 0x9a, 0x24, 0xfd, 0x77, 0x4d, 0x0f,
-	0x3a, 0x1a, 0x19, 0xaa, 0xf5, 0x3d, 0x42, 0x65, 0x42, 0x4d, 0x71, 0xe8, 0xb5, 0x7d, 0xe8, 0xa2,
-	0x93, 0x09, 0xde, 0xc1, 0x3c, 0xae, 0xf3, 0x01, 0xdd, 0xd6, 0x2e, 0x45, 0x98, 0x95, 0xf2, 0x18,
-	0x05, 0x40, 0x7f, 0xe7, 0xc0, 0x32, 0x6e, 0xdf, 0x52, 0x91, 0xcf, 0x41, 0x68, 0xdf, 0x7b, 0x6a,
-	0x88, 0x45, 0xfb, 0xff, 0x57, 0x90, 0x47, 0xd0, 0x12, 0x0c, 0xe3, 0x84, 0x45, 0x4a, 0x3f, 0x7a,
-	0xb6, 0x7e, 0x14, 0x86, 0x7f, 0x78, 0xcb, 0x2d, 0x88, 0x0d, 0xed, 0x38, 0x80, 0xdb, 0x6a, 0x95,
-	0xa5, 0x63, 0xfd, 0x04, 0xe6, 0x32, 0xb1, 0x53, 0x95, 0xde, 0xaf, 0xd9, 0x9c, 0xa5, 0x14, 0x5c,
-	0x45, 0x43, 0xff, 0x66, 0x06, 0xd6, 0xcb, 0x7c, 0x54, 0x38, 0xf9, 0x31, 0x2c, 0x57, 0x42, 0x81,
-	0x0c, 0x51, 0x9f, 0xd8, 0x62, 0x2a, 0x0d, 0x2c, 0xc3, 0x15, 0x2e, 0xfd, 0x7f, 0x68, 0xc0, 0xa2,
-	0x4d, 0x84, 0x7a, 0x9c, 0x07, 0xa9, 0x22, 0x70, 0x59, 0x58, 0x35, 0xa5, 0x6c, 0xd4, 0xa5, 0x94,
-	0x66, 0xe2, 0x38, 0xf3, 0x6d, 0x89, 0x63, 0xf3, 0xfd, 0x12, 0xc7, 0xd9, 0xda, 0xc4, 0xb1, 0xec,
-	0x41, 0xe5, 0x5d, 0xdf, 0xf6, 0xa0, 0xc5, 0x69, 0xcc, 0xbf, 0xc7, 0x69, 0x7c, 0x0f, 0xd6, 0x5e,
-	0x79, 0x61, 0xc8, 0xf8, 0x97, 0x72, 0x0a, 0x7d, 0xa6, 0x1f, 0x41, 0xe7, 0x4a, 0x5e, 0x91, 0x06,
-	0x71, 0x14, 0x4e, 0x55, 0x42, 0xde, 0x56, 0xd8, 0x8b, 0x28, 0x9c, 0xd2, 0x4f, 0xe1, 0x76, 0x69,
-	0x68, 0x71, 0x4f, 0xd1, 0xdb, 0xc0, 0x61, 0x8e, 0xab, 0x9b, 0x74, 0x03, 0x6e, 0xab, 0x65, 0xd8,
-	0xd3, 0xd1, 0x3d, 0x58, 0x2f, 0x77, 0xd4, 0x33, 0x9b, 0x29, 0x98, 0x7d, 0x01, 0xe4, 0x47, 0x13,
-	0x96, 0x4e, 0x45, 0xfd, 0x21, 0xbf, 0x69, 0x6e, 0x94, 0x53, 0x40, 0xbc, 0xe0, 0xff, 0x80, 0x4d,
-	0x75, 0x3d, 0xa6, 0x91, 0xd7, 0x63, 0xe8, 0x63, 0x58, 0xb5, 0x18, 0xa8, 0x19, 0xef, 0xc3, 0x9c,
-	0xa8, 0x61, 0x68, 0xdd, 0xb3, 0xeb, 0x1c, 0xaa, 0x8f, 0xfe, 0x25, 0xcc, 0x1c, 0xc6, 0x89, 0x79,
-	0x9d, 0x70, 0xec, 0xeb, 0x84, 0xd2, 0x9d, 0x41, 0xae, 0x1a, 0x72, 0x66, 0x1b, 0xc4, 0x93, 0xf7,
-	0xc6, 0x1c, 0xf3, 0x83, 0xf3, 0x38, 0xbd, 0xf2, 0x52, 0x5f, 0x69, 0x50, 0x09, 0xc5, 0xd5, 0x9f,
-	0x33, 0xad, 0x3d, 0xf8, 0x97, 0xfe, 0xca, 0x81, 0x59, 0xb1, 0x24, 0xcc, 0x3e, 0x64, 0x3e, 0x2f,
-	0xa3, 0x19, 0x5e, 0xe3, 0x1c, 0xe1, 0x92, 0xca, 0x70, 0xa9, 0xc0, 0xd6, 0x28, 0x17, 0xd8, 0xd0,
-	0xad, 0xc9, 0x56, 0x51, 0xb9, 0x2a, 0x00, 0xf2, 0x21, 0x34, 0x2f, 0xe2, 0x04, 0x53, 0x2d, 0x14,
-	0x0b, 0xe8, 0x8c, 0x3f, 0x4e, 0x5c, 0x81, 0xd3, 0x6d, 0x58, 0x3a, 0x8e, 0x7d, 0x66, 0x24, 0x8d,
-	0x37, 0x9e, 0x06, 0xfd, 0x2b, 0x07, 0x16, 0x34, 0x31, 0xd9, 0x82, 0x26, 0xfa, 0xec, 0x92, 0x4b,
-	0xcc, 0x2f, 0xcc, 0x48, 0xe7, 0x0a, 0x0a, 0x34, 0x00, 0xe1, 0x66, 0xb5, 0x77, 0x68, 0xe4, 0xc9,
-	0x4c, 0x91, 0xee, 0x61, 0x94, 0x11, 0x6b, 0x2e, 0x19, 0x65, 0x09, 0xa5, 0xbf, 0x76, 0xa0, 0x6b,
-	0xcd, 0x81, 0x71, 0x27, 0xf4, 0x32, 0xae, 0xee, 0x3a, 0x4a, 0x88, 0x26, 0x64, 0x5e, 0x30, 0x1a,
-	0xf6, 0x05, 0x23, 0x4f, 0x70, 0x67, 0xcc, 0x04, 0xf7, 0x21, 0xb4, 0xd4, 0x6d, 0x82, 0x69, 0xb9,
-	0xe9, 0xf2, 0x23, 0xce, 0xa8, 0x4b, 0x01, 0x05, 0x11, 0x7d, 0x0c, 0x6d, 0xa3, 0x07, 0x27, 0x8c,
-	0x18, 0xbf, 0x8a, 0xd3, 0xd7, 0xfa, 0x46, 0xa3, 0x9a, 0x79, 0xf5, 0xa6, 0x51, 0x54, 0x6f, 0xe8,
-	0x3f, 0x3b, 0xd0, 0x45, 0x9d, 0x08, 0xa2, 0xd1, 0x49, 0x1c, 0x06, 0xc3, 0xa9, 0xd0, 0x0d, 0x7d,
-	0xfc, 0x78, 0xef, 0xe6, 0x5e, 0xae, 0x1b, 0x36, 0x8c, 0x5e, 0x6c, 0x1c, 0x44, 0xe2, 0xca, 0xa6,
-	0x34, 0x23, 0x6f, 0xa3, 0x2e, 0x9f, 0x33, 0x74, 0x43, 0x19, 0x1b, 0x8c, 0x31, 0x1e, 0x4a, 0x89,
-	0xda, 0x20, 0x66, 0xe6, 0x08, 0xa4, 0x1e, 0x67, 0x83, 0x71, 0x10, 0x86, 0x81, 0xa4, 0x95, 0x3a,
-	0x5b, 0xd7, 0x45, 0xff, 0xb5, 0x01, 0x6d, 0x65, 0xf7, 0x07, 0xfe, 0x88, 0xa1, 0x7e, 0x6a, 0xd7,
-	0x9a, 0x1b, 0x94, 0x81, 0xe8, 0x7e, 0xcb, 0x19, 0x1b, 0x48, 0xf9, 0x00, 0x67, 0xaa, 0x07, 0x88,
-	0x81, 0x3b, 0xf6, 0xd9, 0xa7, 0x98, 0x1f, 0xa8, 0x2a, 0x76, 0x01, 0xe8, 0xde, 0x3d, 0xd1, 0x3b,
-	0x5b, 0xf4, 0x0a, 0xc0, 0xf2, 0xf3, 0x73, 0x25, 0x3f, 0xff, 0x08, 0x3a, 0x8a, 0x8d, 0x90, 0xbb,
-	0xf0, 0xbd, 0x85, 0x2a, 0x5b, 0x67, 0xe2, 0x5a, 0x94, 0x7a, 0xe4, 0x9e, 0x1e, 0xb9, 0xf0, 0x6d,
-	0x23, 0x35, 0x25, 0xde, 0xab, 0x95, 0xf0, 0x9e, 0xa5, 0x5e, 0x72, 0xa1, 0x7d, 0xa9, 0x9f, 0x57,
-	0x5e, 0x05, 0x4c, 0xb6, 0x61, 0x16, 0x87, 0x69, 0x77, 0x56, 0x6f, 0x5e, 0x92, 0x84, 0x6c, 0xc1,
-	0x2c, 0xf3, 0x47, 0xc2, 0x37, 0x98, 0xba, 0x6a, 0x9c, 0x91, 0x2b, 0x09, 0xd0, 0xd8, 0x11, 0x2d,
-	0x19, 0xbb, 0xed, 0x0b, 0xe7, 0xb0, 0xf9, 0xdc, 0xa7, 0x6b, 0x40, 0x8e, 0xa5, 0xd6, 0x9a, 0x17,
-	0xca, 0xbf, 0x9e, 0x81, 0xb6, 0x01, 0xa3, 0xdd, 0x8e, 0x70, 0xc1, 0x03, 0x3f, 0xf0, 0xc6, 0x8c,
-	0xb3, 0x54, 0x69, 0x6a, 0x09, 0x15, 0x2e, 0xf3, 0x72, 0x34, 0x88, 0x27, 0x7c, 0xe0, 0xb3, 0x51,
-	0xca, 0x64, 0x55, 0xd4, 0x71, 0x4b, 0x28, 0xd2, 0x8d, 0xbd, 0x37, 0x26, 0x9d, 0xd4, 0x87, 0x12,
-	0xaa, 0x73, 0x39, 0x29, 0xa3, 0x66, 0x91, 0xcb, 0x49, 0x89, 0x94, 0x3d, 0xce, 0x6c, 0x8d, 0xc7,
-	0xf9, 0x0c, 0xd6, 0xa5, 0x6f, 0x51, 0xb6, 0x39, 0x28, 0xa9, 0xc9, 0x0d, 0xbd, 0x64, 0x1b, 0x96,
-	0x71, 0xcd, 0x5a, 0xc1, 0xb3, 0xe0, 0xe7, 0xb2, 0x62, 0xe4, 0xb8, 0x15, 0x1c, 0x69, 0xd1, 0x1c,
-	0x2d, 0x5a, 0x59, 0x32, 0xaa, 0xe0, 0x82, 0xd6, 0x7b, 0x63, 0xd3, 0xb6, 0x14, 0x6d, 0x09, 0xa7,
-	0x9b, 0x70, 0x47, 0xa8, 0xc9, 0xcb, 0x38, 0x89, 0xc3, 0x78, 0x34, 0x3d, 0x9d, 0x9c, 0x65, 0xc3,
-	0x34, 0x48, 0x30, 0x6d, 0xa4, 0xff, 0xe6, 0xc0, 0xaa, 0xd5, 0xab, 0x72, 0xd9, 0x3f, 0x94, 0x3a,
-	0x9b, 0xd7, 0x89, 0xa4, 0x66, 0xad, 0x18, 0x9e, 0x4d, 0x12, 0xca, 0xa4, 0xfd, 0x2b, 0x55, 0x3a,
-	0xda, 0x87, 0x25, 0x3d, 0xb5, 0x1e, 0x28, 0xd5, 0xac, 0x57, 0x55, 0x33, 0x35, 0x7e, 0x51, 0x0d,
-	0xd0, 0x2c, 0xfe, 0x44, 0x26, 0x40, 0xcc, 0x17, 0x9b, 0x40, 0x67, 0x8b, 0xe3, 0xfb, 0x7a, 0xbc,
-	0xe8, 0x7a, 0x62, 0x0e, 0x71, 0xdb, 0xc3, 0x1c, 0xcc, 0xe8, 0xdf, 0x3a, 0x00, 0xc5, 0xea, 0xf0,
-	0xe4, 0x0b, 0xef, 0x8c, 0x7b, 0x68, 0x19, 0x9e, 0x18, 0x53, 0x20, 0x2b, 0x41, 0x94, 0xee, 0xa6,
-	0xad, 0x31, 0xcc, 0x29, 0x1e, 0xc0, 0xd2, 0x28, 0x8c, 0xcf, 0x44, 0xf8, 0xf4, 0xf8, 0x24, 0x65,
-	0x99, 0x2a, 0xa0, 0x2e, 0x4a, 0xf8, 0xfb, 0x0a, 0x2d, 0xa2, 0x43, 0xd3, 0x88, 0x0e, 0xf4, 0xef,
-	0x1a, 0xf9, 0xbd, 0xbb, 0xd8, 0xf3, 0x8d, 0x66, 0x44, 0xf6, 0x2a, 0xde, 0xef, 0x86, 0x6b, 0xae,
-	0x48, 0xdf, 0x4f, 0xbe, 0x35, 0x37, 0x7d, 0x0c, 0x8b, 0xa9, 0x74, 0x2f, 0xda, 0xf7, 0x34, 0xdf,
-	0xe1, 0x7b, 0xba, 0xa9, 0x15, 0x58, 0x7e, 0x0f, 0x96, 0x3d, 0xff, 0x92, 0xa5, 0x3c, 0x10, 0xa9,
-	0xa7, 0x88, 0xdf, 0xd2, 0x63, 0x2e, 0x19, 0xb8, 0x08, 0xab, 0x0f, 0x60, 0x69, 0x28, 0xcb, 0xd9,
-	0x39, 0xa5, 0x7a, 0xa4, 0x2a, 0x60, 0x24, 0xa4, 0xff, 0xa4, 0xaf, 0xf8, 0xf6, 0x19, 0xde, 0x2c,
-	0x11, 0x73, 0x77, 0x8d, 0xd2, 0xee, 0xbe, 0xab, 0xae, 0xe4, 0xbe, 0xae, 0x8e, 0xa8, 0xc2, 0x87,
-	0x04, 0x55, 0x79, 0xc4, 0x16, 0x69, 0xf3, 0x7d, 0x44, 0x4a, 0x77, 0x60, 0xe9, 0x94, 0xf1, 0x7d,
-	0x3c, 0x41, 0xed, 0xf9, 0x36, 0xa1, 0x15, 0xb1, 0xab, 0x81, 0x3c, 0x62, 0x19, 0xa7, 0x17, 0x22,
-	0x76, 0x25, 0x68, 0x28, 0x81, 0xe5, 0x82, 0x5e, 0xe6, 0x98, 0xf4, 0xef, 0x1b, 0x30, 0xff, 0x3c,
-	0xba, 0x8c, 0x83, 0xa1, 0xb8, 0x64, 0x8f, 0xd9, 0x38, 0xd6, 0xaf, 0x28, 0xf8, 0x1f, 0xc3, 0xbe,
-	0xa8, 0xc9, 0x26, 0x5c, 0xdd, 0x7e, 0x75, 0x13, 0x43, 0x60, 0x5a, 0x3c, 0xd9, 0x49, 0x6d, 0x33,
-	0x10, 0xb2, 0x0e, 0x73, 0xa9, 0xf9, 0xbc, 0xa8, 0x5a, 0xc5, 0x13, 0xd2, 0xac, 0xf1, 0x84, 0x24,
-	0xca, 0x2d, 0xb2, 0xdc, 0x2c, 0x8e, 0x64, 0xc1, 0xd5, 0x4d, 0x91, 0xbe, 0xa6, 0x4c, 0xd5, 0xeb,
-	0x31, 0x98, 0xce, 0xab, 0xf4, 0xd5, 0x04, 0x31, 0xe0, 0xca, 0x01, 0x92, 0x46, 0x3a, 0x24, 0x13,
-	0xc2, 0x04, 0xa4, 0xfc, 0x42, 0xd9, 0x92, 0x6a, 0x52, 0x82, 0xe9, 0xd7, 0x40, 0xf6, 0x7d, 0x5f,
-	0x49, 0x25, 0xcf, 0xc6, 0x8b, 0xfd, 0x38, 0xd6, 0x7e, 0x6a, 0xf8, 0x36, 0xea, 0xf9, 0x1e, 0x40,
-	0xfb, 0xc4, 0x78, 0x62, 0x15, 0x02, 0xd4, 0x8f, 0xab, 0x4a, 0xe8, 0x06, 0x62, 0x4c, 0xd8, 0x30,
-	0x27, 0xa4, 0x7f, 0x04, 0xe4, 0x28, 0xc8, 0x78, 0xbe, 0xbe, 0xfc, 0x9e, 0xa4, 0x2f, 0x9b, 0xe6,
-	0x3d, 0x49, 0x61, 0xe2, 0x9e, 0xb4, 0x2f, 0xcb, 0xdf, 0xe5, 0x8d, 0x6d, 0xc3, 0x42, 0x20, 0x21,
-	0xed, 0x3f, 0x17, 0x95, 0xe2, 0x69, 0xca, 0xbc, 0x1f, 0x23, 0xbd, 0x02, 0x2d, 0xf7, 0xfc, 0x2b,
-	0x07, 0xe6, 0xd5, 0xd6, 0x30, 0x4e, 0x59, 0x8f, 0xcb, 0xea, 0x3a, 0x6b, 0x62, 0xf5, 0xcf, 0x88,
-	0xd5, 0x93, 0x9e, 0xa9, 0x3b, 0x69, 0x02, 0xcd, 0xc4, 0xe3, 0x17, 0x22, 0x89, 0x6d, 0xb9, 0xe2,
-	0xbf, 0xbe, 0x94, 0xcc, 0x16, 0x97, 0x12, 0x55, 0xea, 0x57, 0x8b, 0xca, 0xab, 0xd0, 0x5f, 0xca,
-	0x52, 0x7f, 0x01, 0x17, 0x32, 0x50, 0x0b, 0x2c, 0xcb, 0x40, 0x91, 0xba, 0x79, 0x3f, 0xed, 0x43,
-	0xef, 0x29, 0x0b, 0x19, 0x67, 0xfb, 0x61, 0x58, 0xe6, 0xbf, 0x09, 0x77, 0x6a, 0xfa, 0x94, 0xad,
-	0x7d, 0x1f, 0x56, 0x9e, 0xb2, 0xb3, 0xc9, 0xe8, 0x88, 0x5d, 0x16, 0x35, 0x0b, 0x02, 0xcd, 0xec,
-	0x22, 0xbe, 0x52, 0xe7, 0x25, 0xfe, 0x93, 0x0f, 0x00, 0x42, 0xa4, 0x19, 0x64, 0x09, 0x1b, 0x2a,
-	0x6d, 0x6a, 0x09, 0xe4, 0x34, 0x61, 0x43, 0xfa, 0x19, 0x10, 0x93, 0x8f, 0xda, 0x02, 0x5a, 0xc0,
-	0xe4, 0x6c, 0x90, 0x4d, 0x33, 0xce, 0xc6, 0xda, 0xf8, 0x4d, 0x88, 0x3e, 0x80, 0xce, 0x89, 0x37,
-	0x75, 0xd9, 0x37, 0xea, 0xcd, 0x1e, 0xef, 0x44, 0xde, 0x14, 0xd5, 0x33, 0xbf, 0x13, 0x89, 0x6e,
-	0x9a, 0xc2, 0x9c, 0x24, 0x44, 0xa6, 0x3e, 0xcb, 0x78, 0x10, 0xc9, 0x72, 0x8f, 0x62, 0x6a, 0x40,
-	0x95, 0xe3, 0x6e, 0xd4, 0x1c, 0xb7, 0x4a, 0x5d, 0xf4, 0x2b, 0x8f, 0x3a, 0x57, 0x0b, 0xdb, 0xde,
-	0x83, 0xae, 0x55, 0x18, 0x20, 0xf3, 0x30, 0xb3, 0x7f, 0x74, 0xb4, 0x7c, 0x8b, 0xb4, 0x61, 0xfe,
-	0xc5, 0xc9, 0xc1, 0xf1, 0xf3, 0xe3, 0x67, 0xcb, 0x0e, 0x36, 0x9e, 0x1c, 0xbd, 0x38, 0xc5, 0x46,
-	0x63, 0xef, 0xb7, 0x1b, 0xd0, 0xca, 0xb3, 0x47, 0xf2, 0x33, 0xe8, 0x5a, 0x65, 0x00, 0xb2, 0xa9,
-	0x8e, 0xb0, 0xae, 0xae, 0xd0, 0xbf, 0x5b, 0xdf, 0xa9, 0x8e, 0xea, 0xc3, 0x5f, 0xfc, 0xee, 0x3f,
-	0x7f, 0xdd, 0xe8, 0x91, 0xf5, 0xdd, 0xcb, 0x4f, 0x77, 0xd5, 0x3d, 0x7f, 0x57, 0x94, 0xb3, 0x65,
-	0xf5, 0xfc, 0x35, 0x2c, 0xda, 0x65, 0x02, 0x72, 0xd7, 0x76, 0xd6, 0xa5, 0xd9, 0x3e, 0xb8, 0xa1,
-	0x57, 0x4d, 0x77, 0x57, 0x4c, 0xb7, 0x4e, 0xd6, 0xcc, 0xe9, 0xf2, 0xac, 0x8e, 0x89, 0xf7, 0x0e,
-	0xf3, 0x6b, 0x13, 0xa2, 0xf9, 0xd5, 0x7f, 0x85, 0xd2, 0xbf, 0x53, 0xfd, 0xb2, 0x44, 0x7d, 0x8a,
-	0x42, 0x7b, 0x62, 0x2a, 0x42, 0x96, 0x71, 0x2a, 0xf3, 0x63, 0x13, 0xf2, 0x53, 0x68, 0xe5, 0x2f,
-	0xeb, 0x64, 0xc3, 0xf8, 0x8e, 0xc0, 0x7c, 0xab, 0xef, 0xf7, 0xaa, 0x1d, 0x6a, 0x13, 0x9b, 0x82,
-	0xf3, 0x6d, 0x5a, 0xe1, 0xfc, 0xb9, 0xb3, 0x4d, 0x8e, 0xe0, 0xb6, 0xf2, 0x18, 0x67, 0xec, 0xff,
-	0xb2, 0x93, 0x9a, 0x6f, 0x64, 0x1e, 0x3a, 0xe4, 0x31, 0x2c, 0xe8, 0x8f, 0x0d, 0xc8, 0x7a, 0xfd,
-	0x17, 0x0f, 0xfd, 0x8d, 0x0a, 0xae, 0x0c, 0x65, 0x1f, 0xa0, 0x78, 0x5b, 0x27, 0xbd, 0x9b, 0x3e,
-	0x01, 0xc8, 0x85, 0x58, 0xf3, 0x10, 0x3f, 0x12, 0x9f, 0x16, 0xd8, 0x4f, 0xf7, 0xe4, 0x3b, 0x05,
-	0x7d, 0xed, 0xa3, 0xfe, 0x3b, 0x18, 0xd2, 0x75, 0x21, 0xbb, 0x65, 0xb2, 0x88, 0xb2, 0x8b, 0xd8,
-	0x95, 0x7e, 0xf9, 0xfb, 0x09, 0xb4, 0x8d, 0x07, 0x78, 0x62, 0x14, 0x5a, 0x4b, 0x6f, 0xfd, 0xfd,
-	0x7e, 0x5d, 0x97, 0xe2, 0xbe, 0x26, 0xb8, 0x2f, 0xd2, 0x16, 0x72, 0x17, 0x8f, 0x4d, 0x78, 0x24,
-	0x3f, 0x42, 0xe3, 0x51, 0x2f, 0x72, 0xa4, 0xf8, 0x38, 0xc0, 0x7e, 0xb7, 0xcb, 0xcf, 0xbb, 0xf2,
-	0x78, 0x47, 0x57, 0x04, 0xd7, 0x36, 0x29, 0xb8, 0x92, 0x1f, 0xc2, 0xbc, 0x7a, 0x99, 0x23, 0xb7,
-	0x8b, 0x73, 0x35, 0xee, 0x5a, 0xfd, 0xf5, 0x32, 0xac, 0x98, 0xad, 0x0a, 0x66, 0x5d, 0xd2, 0x46,
-	0x66, 0x23, 0xc6, 0x03, 0xe4, 0x11, 0xc2, 0x92, 0x5d, 0x2b, 0xcd, 0x72, 0x33, 0xab, 0x2d, 0x00,
-	0xe7, 0x66, 0x56, 0x5f, 0x9d, 0xb5, 0xcd, 0x4c, 0x9b, 0xd7, 0xae, 0xae, 0x6d, 0xff, 0x39, 0x74,
-	0xcc, 0x67, 0x60, 0xd2, 0x37, 0x76, 0x5e, 0x7a, 0x32, 0xee, 0x6f, 0xd6, 0xf6, 0xd9, 0xe2, 0x26,
-	0x1d, 0x73, 0x1a, 0xf2, 0x13, 0x58, 0x32, 0x5e, 0x22, 0x4e, 0xa7, 0xd1, 0x30, 0x3f, 0xce, 0xea,
-	0x0b, 0x45, 0xbf, 0x2e, 0xf7, 0xa3, 0x1b, 0x82, 0xf1, 0x0a, 0xb5, 0x18, 0xe3, 0x51, 0x3e, 0x81,
-	0xb6, 0xc1, 0xe3, 0x5d, 0x7c, 0x37, 0x8c, 0x2e, 0xf3, 0x55, 0xe0, 0xa1, 0x43, 0x7e, 0xe3, 0x40,
-	0xc7, 0x7c, 0xd7, 0x22, 0xd6, 0x6d, 0xa6, 0xc4, 0xa7, 0x67, 0xf6, 0x99, 0x8c, 0xe8, 0xd7, 0x62,
-	0x91, 0x27, 0xdb, 0xc7, 0x96, 0x90, 0xdf, 0x5a, 0x95, 0xea, 0x1d, 0xf3, 0x2b, 0xaa, 0xeb, 0x72,
-	0xa7, 0xf9, 0x82, 0x73, 0xbd, 0xfb, 0x56, 0x3c, 0x77, 0x5d, 0x3f, 0x74, 0xc8, 0xe7, 0xf2, 0x63,
-	0x38, 0x9d, 0x68, 0x10, 0xc3, 0xc0, 0xcb, 0x62, 0x33, 0x3f, 0x31, 0xdb, 0x72, 0x1e, 0x3a, 0xe4,
-	0x2f, 0xe4, 0x07, 0x54, 0x6a, 0xac, 0x90, 0xfe, 0xfb, 0x8e, 0xa7, 0xf7, 0xc5, 0x8e, 0x3e, 0xa4,
-	0x77, 0xac, 0x1d, 0x95, 0x3d, 0xdc, 0x09, 0x40, 0x91, 0x35, 0x92, 0x52, 0x0a, 0x95, 0xdb, 0x7e,
-	0x35, 0xb1, 0xb4, 0x4f, 0x55, 0x67, 0x5a, 0xc8, 0xf1, 0x67, 0x52, 0x21, 0x15, 0x7d, 0x96, 0x1f,
-	0x6b, 0x35, 0xfb, 0xeb, 0xf7, 0xeb, 0xba, 0x14, 0xff, 0xef, 0x0a, 0xfe, 0x1f, 0x90, 0x4d, 0x93,
-	0xff, 0xee, 0x5b, 0x33, 0x5b, 0xbc, 0x26, 0x5f, 0x43, 0xf7, 0x28, 0x8e, 0x5f, 0x4f, 0x92, 0xfc,
-	0x32, 0x60, 0xe7, 0x3f, 0x98, 0xb1, 0xf6, 0x4b, 0x9b, 0xa2, 0x1f, 0x09, 0xce, 0x9b, 0xe4, 0x8e,
-	0xcd, 0xb9, 0xc8, 0x61, 0xaf, 0x89, 0x07, 0x2b, 0xb9, 0xdf, 0xcf, 0x37, 0xd2, 0xb7, 0xf9, 0x98,
-	0xa9, 0x64, 0x65, 0x0e, 0x2b, 0x12, 0xe7, 0x73, 0x64, 0x9a, 0xe7, 0x43, 0x87, 0x9c, 0x40, 0xe7,
-	0x29, 0x1b, 0xc6, 0x3e, 0x53, 0x39, 0xcb, 0x6a, 0xb1, 0xf2, 0x3c, 0xd7, 0xe9, 0x77, 0x2d, 0xd0,
-	0xf6, 0x04, 0x89, 0x37, 0x4d, 0xd9, 0x37, 0xbb, 0x6f, 0x55, 0x32, 0x74, 0xad, 0x3d, 0x81, 0x4e,
-	0xe0, 0x2c, 0x4f, 0x50, 0xca, 0xf8, 0x2c, 0x4f, 0x50, 0xc9, 0xf8, 0x2c, 0x4f, 0xa0, 0x13, 0x48,
-	0x12, 0x62, 0x1e, 0x58, 0x4a, 0x12, 0xf3, 0xe8, 0x71, 0x53, 0x6a, 0xd9, 0xbf, 0x77, 0x33, 0x81,
-	0x3d, 0xdb, 0xb6, 0x3d, 0xdb, 0x29, 0x74, 0x9f, 0x32, 0x29, 0x2c, 0x59, 0x86, 0xeb, 0xdb, 0xae,
-	0xc5, 0x2c, 0xd9, 0x95, 0xdd, 0x8e, 0xe8, 0xb3, 0x1d, 0xbd, 0xa8, 0x81, 0x91, 0x9f, 0x42, 0xfb,
-	0x19, 0xe3, 0xba, 0xee, 0x96, 0xc7, 0xe0, 0x52, 0x21, 0xae, 0x5f, 0x53, 0xb6, 0xa3, 0xf7, 0x04,
-	0xb7, 0x3e, 0xe9, 0xe5, 0xdc, 0x76, 0x99, 0x3f, 0x62, 0xd2, 0x09, 0x0c, 0x02, 0xff, 0x9a, 0xfc,
-	0x58, 0x30, 0xcf, 0x8b, 0xf2, 0xeb, 0x46, 0x35, 0xc7, 0x64, 0xbe, 0x54, 0xc2, 0xeb, 0x38, 0xe3,
-	0x1d, 0x7f, 0xf7, 0xad, 0x2a, 0x8d, 0x5f, 0x93, 0x08, 0xda, 0xc6, 0x43, 0x4b, 0x6e, 0x50, 0xd5,
-	0xd7, 0x9b, 0xdc, 0xa0, 0x6a, 0xde, 0x65, 0xe8, 0x96, 0x98, 0x87, 0x92, 0x7b, 0xc5, 0x3c, 0xf2,
-	0x2d, 0xa6, 0x98, 0x69, 0xf7, 0xad, 0x37, 0xe6, 0xd7, 0xe4, 0x95, 0xf8, 0x78, 0xc5, 0xac, 0x2d,
-	0x16, 0x39, 0x40, 0xb9, 0x0c, 0x99, 0x0b, 0xcb, 0xe8, 0xb2, 0xf3, 0x02, 0x39, 0x95, 0x88, 0x8c,
-	0xaf, 0x8c, 0x74, 0xca, 0xaa, 0xb1, 0x6a, 0x2d, 0xb9, 0xb1, 0x94, 0x96, 0xef, 0xac, 0xa6, 0x9c,
-	0xa6, 0x33, 0x2b, 0x59, 0x23, 0x30, 0x32, 0x2b, 0xab, 0xc8, 0x60, 0x64, 0x56, 0x76, 0x31, 0x01,
-	0x33, 0xab, 0xe2, 0x62, 0x92, 0x67, 0x56, 0x95, 0x3b, 0x4f, 0xee, 0x0c, 0xab, 0xb7, 0x98, 0xb3,
-	0x39, 0xf1, 0x51, 0xf9, 0x1f, 0xfc, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd5, 0xa2, 0x29, 0xef,
-	0x86, 0x2e, 0x00, 0x00,
+	// 5611 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x7c, 0x5b, 0x6f, 0x1c, 0xc9,
+	0x75, 0xbf, 0x7a, 0x86, 0x97, 0x99, 0x33, 0x33, 0xbc, 0x14, 0x29, 0x72, 0xd4, 0xd2, 0xae, 0xb9,
+	0xe5, 0xc5, 0x2e, 0x2d, 0x1b, 0xa2, 0x96, 0xeb, 0xff, 0xfe, 0xe5, 0xdd, 0x4d, 0x1c, 0xea, 0x4a,
+	0x65, 0x29, 0x89, 0x6e, 0x6a, 0x77, 0x1d, 0x3b, 0xc1, 0xa4, 0x39, 0x53, 0x1c, 0xb6, 0x35, 0xd3,
+	0xdd, 0xee, 0xae, 0x21, 0x39, 0x16, 0x84, 0x18, 0x8e, 0x83, 0x04, 0x81, 0x03, 0x23, 0xf0, 0xbb,
+	0x11, 0x20, 0x2f, 0x41, 0x80, 0xbc, 0xe4, 0x2d, 0x88, 0xbf, 0x42, 0x80, 0x00, 0x79, 0x0a, 0x90,
+	0xbc, 0xe5, 0x39, 0x40, 0xbe, 0x41, 0x70, 0xea, 0xd2, 0x5d, 0xd5, 0xdd, 0x23, 0x71, 0x93, 0x87,
+	0x3c, 0x71, 0xea, 0x57, 0x55, 0xa7, 0xaa, 0x4e, 0x9d, 0x3a, 0x75, 0x2e, 0xd5, 0x84, 0x66, 0x12,
+	0xf7, 0x6f, 0xc5, 0x49, 0xc4, 0x23, 0x32, 0x3f, 0x0a, 0x93, 0xb8, 0xef, 0xde, 0x18, 0x46, 0xd1,
+	0x70, 0xc4, 0x76, 0xfc, 0x38, 0xd8, 0xf1, 0xc3, 0x30, 0xe2, 0x3e, 0x0f, 0xa2, 0x30, 0x95, 0x8d,
+	0xe8, 0x7f, 0x39, 0xd0, 0x7a, 0x9e, 0xf8, 0x61, 0xea, 0xf7, 0x11, 0x26, 0x5d, 0x58, 0xe4, 0x17,
+	0xbd, 0x53, 0x3f, 0x3d, 0xed, 0x3a, 0x5b, 0xce, 0x76, 0xd3, 0xd3, 0x45, 0xb2, 0x01, 0x0b, 0xfe,
+	0x38, 0x9a, 0x84, 0xbc, 0x5b, 0xdb, 0x72, 0xb6, 0xeb, 0x9e, 0x2a, 0x91, 0x6f, 0xc1, 0x6a, 0x38,
+	0x19, 0xf7, 0xfa, 0x51, 0x78, 0x12, 0x24, 0x63, 0x49, 0xbc, 0x5b, 0xdf, 0x72, 0xb6, 0xe7, 0xbd,
+	0x72, 0x05, 0x79, 0x1b, 0xe0, 0x78, 0x14, 0xf5, 0x5f, 0xc8, 0x21, 0xe6, 0xc4, 0x10, 0x06, 0x42,
+	0x28, 0xb4, 0x55, 0x89, 0x05, 0xc3, 0x53, 0xde, 0x9d, 0x17, 0x84, 0x2c, 0x0c, 0x69, 0xf0, 0x60,
+	0xcc, 0x7a, 0x29, 0xf7, 0xc7, 0x71, 0x77, 0x41, 0xcc, 0xc6, 0x40, 0x44, 0x7d, 0xc4, 0xfd, 0x51,
+	0xef, 0x84, 0xb1, 0xb4, 0xbb, 0xa8, 0xea, 0x33, 0x84, 0x76, 0x61, 0xe3, 0x11, 0xe3, 0xc6, 0xaa,
+	0x53, 0x8f, 0xfd, 0x78, 0xc2, 0x52, 0x4e, 0x0f, 0x80, 0x18, 0xf0, 0x7d, 0xc6, 0xfd, 0x60, 0x94,
+	0x92, 0x8f, 0xa0, 0xcd, 0x8d, 0xc6, 0x5d, 0x67, 0xab, 0xbe, 0xdd, 0xda, 0x25, 0xb7, 0x04, 0x7f,
+	0x6f, 0x19, 0x1d, 0x3c, 0xab, 0x1d, 0xfd, 0x67, 0x07, 0x5a, 0x47, 0x2c, 0x1c, 0x28, 0xea, 0x84,
+	0xc0, 0xdc, 0x80, 0xa5, 0x5c, 0x30, 0xb6, 0xed, 0x89, 0xdf, 0xe4, 0x6b, 0xd0, 0xc2, 0xbf, 0xbd,
+	0x94, 0x27, 0x41, 0x38, 0x14, 0xac, 0x6d, 0x7a, 0x80, 0xd0, 0x91, 0x40, 0xc8, 0x0a, 0xd4, 0xfd,
+	0x31, 0x17, 0x0c, 0xad, 0x7b, 0xf8, 0x93, 0xbc, 0x03, 0xed, 0xd8, 0x9f, 0x8e, 0x59, 0xc8, 0x73,
+	0x26, 0xb6, 0xbd, 0x96, 0xc2, 0xf6, 0x91, 0x8b, 0xb7, 0x60, 0xcd, 0x6c, 0xa2, 0xa9, 0xcf, 0x0b,
+	0xea, 0xab, 0x46, 0x4b, 0x35, 0xc8, 0xfb, 0xb0, 0xac, 0xdb, 0x27, 0x72, 0xb2, 0x82, 0xad, 0x4d,
+	0x6f, 0x49, 0xc1, 0x9a, 0x41, 0x21, 0xb4, 0xe5, 0x8a, 0xd2, 0x38, 0x0a, 0x53, 0x46, 0x6e, 0xc2,
+	0x8a, 0xee, 0x18, 0x27, 0x2c, 0x18, 0xfb, 0x43, 0xa6, 0x96, 0x57, 0xc2, 0xc9, 0x2e, 0x74, 0xb2,
+	0x41, 0xa2, 0x09, 0x67, 0x62, 0xb1, 0xad, 0xdd, 0xb6, 0xe2, 0xa3, 0x87, 0x98, 0x67, 0x37, 0xa1,
+	0x3f, 0x73, 0xa0, 0x7d, 0xef, 0xd4, 0x0f, 0x43, 0x36, 0x3a, 0x8c, 0x82, 0x90, 0xa3, 0x7c, 0x9c,
+	0x4c, 0xc2, 0x41, 0x10, 0x0e, 0x7b, 0xfc, 0x22, 0x18, 0xa8, 0xc1, 0x2c, 0x0c, 0x27, 0x65, 0x96,
+	0x71, 0xf5, 0x8a, 0xb1, 0x25, 0x1c, 0xe9, 0x45, 0x13, 0x1e, 0x4f, 0x78, 0x2f, 0x08, 0x07, 0xec,
+	0x42, 0xf0, 0xb9, 0xe3, 0x59, 0x18, 0xfd, 0x6d, 0x58, 0x39, 0x40, 0xc1, 0x0b, 0x83, 0x70, 0xb8,
+	0x37, 0x18, 0x24, 0x2c, 0x4d, 0xf1, 0x34, 0xc4, 0x93, 0xe3, 0x17, 0x6c, 0xaa, 0x8e, 0x89, 0x2a,
+	0xe1, 0x1e, 0x9f, 0x46, 0x29, 0x57, 0xe3, 0x89, 0xdf, 0xf4, 0x3f, 0x6b, 0xb0, 0x8c, 0x5c, 0x7b,
+	0xe2, 0x87, 0x53, 0x2d, 0x0b, 0x07, 0xd0, 0x46, 0x52, 0xcf, 0xa3, 0x3d, 0x79, 0xa6, 0xa4, 0x4c,
+	0x6d, 0x2b, 0x5e, 0x14, 0x5a, 0xdf, 0x32, 0x9b, 0x3e, 0x08, 0x79, 0x32, 0xf5, 0xac, 0xde, 0xe4,
+	0x06, 0x34, 0x71, 0xc6, 0xc8, 0xa1, 0xb4, 0x5b, 0xdb, 0xaa, 0x6f, 0x37, 0xbd, 0x1c, 0x20, 0xef,
+	0x42, 0x27, 0xf5, 0x79, 0x2f, 0x66, 0x49, 0xef, 0xec, 0x78, 0xca, 0x99, 0x58, 0xe4, 0x9c, 0x67,
+	0x83, 0x64, 0x0b, 0x5a, 0xdc, 0x4f, 0x86, 0x8c, 0x8b, 0x13, 0x2b, 0xa4, 0xaa, 0xe3, 0x99, 0x10,
+	0x8e, 0x32, 0x0e, 0x42, 0xf1, 0x3b, 0x55, 0x07, 0x33, 0x07, 0x88, 0x0b, 0x8d, 0x94, 0x85, 0x83,
+	0x9e, 0x3f, 0x1a, 0x09, 0xe1, 0x69, 0x78, 0x59, 0x19, 0x75, 0x44, 0x1a, 0x63, 0x61, 0x12, 0x2a,
+	0x75, 0xc0, 0x06, 0xe2, 0x60, 0x36, 0xbc, 0x72, 0x85, 0xfb, 0x5d, 0x58, 0x2d, 0x2d, 0x18, 0xcf,
+	0x41, 0xce, 0x6d, 0xfc, 0x49, 0xd6, 0x61, 0xfe, 0xcc, 0x1f, 0x4d, 0x98, 0xd2, 0x47, 0xb2, 0xf0,
+	0x71, 0xed, 0x8e, 0x43, 0xdf, 0x83, 0x95, 0x9c, 0x83, 0x4a, 0x52, 0x09, 0xcc, 0x65, 0x02, 0xd3,
+	0xf4, 0xc4, 0x6f, 0xfa, 0x8b, 0x9a, 0x6c, 0x78, 0x2f, 0x0a, 0x32, 0x1d, 0x80, 0x0d, 0xfd, 0xc1,
+	0x20, 0xd1, 0x0d, 0xf1, 0xf7, 0x4c, 0xdd, 0x67, 0xf1, 0xbd, 0xfe, 0x46, 0xbe, 0xcf, 0x5d, 0x82,
+	0xef, 0xf3, 0x6f, 0xe0, 0xfb, 0xc2, 0xeb, 0xf8, 0xbe, 0x78, 0x19, 0xbe, 0x37, 0x66, 0xf0, 0x9d,
+	0xbe, 0x0f, 0xab, 0x06, 0x37, 0x5e, 0xc3, 0xb7, 0x5f, 0x3b, 0xb0, 0xfa, 0x94, 0x9d, 0xab, 0xb3,
+	0xa0, 0x19, 0x77, 0x07, 0xe6, 0xf8, 0x34, 0x96, 0xe7, 0x7f, 0x69, 0xf7, 0x5d, 0x25, 0xca, 0xa5,
+	0x76, 0xb7, 0x54, 0xf1, 0xf9, 0x34, 0x66, 0x9e, 0xe8, 0x41, 0x9f, 0x41, 0xcb, 0x00, 0xc9, 0x26,
+	0xac, 0x7d, 0xf9, 0xf8, 0xf9, 0xd3, 0x07, 0x47, 0x47, 0xbd, 0xc3, 0xcf, 0xef, 0x7e, 0xf6, 0xe0,
+	0xf7, 0x7a, 0xfb, 0x7b, 0x47, 0xfb, 0x2b, 0x57, 0xc8, 0x06, 0x90, 0xa7, 0x0f, 0x8e, 0x9e, 0x3f,
+	0xb8, 0x6f, 0xe1, 0x0e, 0x59, 0x86, 0x96, 0x09, 0xd4, 0xa8, 0x0b, 0xdd, 0xa7, 0xec, 0xfc, 0xcb,
+	0x80, 0x87, 0x2c, 0x4d, 0xed, 0xe1, 0xe9, 0x2d, 0x20, 0xe6, 0x9c, 0xd4, 0x32, 0xbb, 0xb0, 0xe8,
+	0x4b, 0x48, 0xdf, 0x7b, 0xaa, 0x48, 0x3f, 0x07, 0x72, 0x2f, 0x0a, 0x43, 0xd6, 0xe7, 0x87, 0x8c,
+	0x25, 0x7a, 0xb1, 0xdf, 0x34, 0xa4, 0xa4, 0xb5, 0xbb, 0xa9, 0x16, 0x5b, 0x54, 0x13, 0x4a, 0x7c,
+	0x08, 0xcc, 0xc5, 0x2c, 0x19, 0x0b, 0xe1, 0x69, 0x78, 0xe2, 0x37, 0xdd, 0x81, 0x35, 0x8b, 0x6c,
+	0x3e, 0x8f, 0x98, 0xb1, 0xa4, 0xa7, 0x38, 0x3e, 0xef, 0xe9, 0x22, 0xfd, 0x7b, 0x07, 0xe6, 0xf6,
+	0x9f, 0x1f, 0xdc, 0xc3, 0x0d, 0x0f, 0xc2, 0x7e, 0x34, 0x46, 0x8d, 0xee, 0xc8, 0x0d, 0xd7, 0xe5,
+	0xd7, 0x09, 0xaa, 0xb8, 0x08, 0xf0, 0x1a, 0x15, 0xc7, 0xbf, 0xed, 0xe5, 0x00, 0x8a, 0x09, 0xbb,
+	0x88, 0x83, 0x44, 0xdc, 0xd1, 0xfa, 0xe6, 0x95, 0x0a, 0xa0, 0x5c, 0x81, 0xea, 0x35, 0x61, 0x67,
+	0x51, 0x5f, 0x82, 0x03, 0x36, 0xf2, 0xa7, 0x4a, 0x6a, 0x4b, 0x38, 0xfd, 0xe9, 0x1c, 0x74, 0xf6,
+	0xfa, 0x3c, 0x38, 0x63, 0x4a, 0x8b, 0x8b, 0x19, 0x0a, 0x40, 0xcd, 0x5d, 0x95, 0xf0, 0xb0, 0x24,
+	0x6c, 0x1c, 0x71, 0xd6, 0x53, 0x7a, 0x55, 0x6a, 0x50, 0x1b, 0xc4, 0x56, 0x7d, 0x49, 0xa8, 0x27,
+	0x0e, 0x99, 0x58, 0x4b, 0xd3, 0xb3, 0x41, 0x64, 0x22, 0x02, 0xc8, 0x44, 0x79, 0xe4, 0x74, 0x11,
+	0x79, 0xd7, 0xf7, 0x63, 0xbf, 0x1f, 0x70, 0x39, 0xe7, 0xba, 0x97, 0x95, 0x91, 0xf6, 0x28, 0xea,
+	0xfb, 0xa3, 0xde, 0xb1, 0x3f, 0xf2, 0xc3, 0x3e, 0x53, 0x96, 0x85, 0x0d, 0x92, 0xf7, 0x60, 0x49,
+	0x4d, 0x49, 0x37, 0x93, 0x06, 0x46, 0x01, 0x45, 0x9e, 0x4e, 0xc2, 0x94, 0x71, 0x3e, 0x62, 0x83,
+	0xac, 0x69, 0x43, 0x34, 0x2d, 0x57, 0x90, 0xdb, 0xb0, 0x26, 0x0d, 0x94, 0xd4, 0xe7, 0x51, 0x7a,
+	0x1a, 0xa4, 0xbd, 0x94, 0x85, 0xbc, 0xdb, 0x14, 0xed, 0xab, 0xaa, 0xc8, 0x1d, 0xd8, 0x2c, 0xc0,
+	0x09, 0xeb, 0xb3, 0xe0, 0x8c, 0x0d, 0xba, 0x20, 0x7a, 0xcd, 0xaa, 0x46, 0x85, 0x83, 0x76, 0xd9,
+	0x24, 0x1e, 0xf8, 0x9c, 0xa5, 0xdd, 0x96, 0xe0, 0x90, 0x09, 0x91, 0x0f, 0xa0, 0x13, 0x33, 0x79,
+	0x51, 0x9e, 0xf2, 0x51, 0x3f, 0xed, 0xb6, 0xc5, 0xed, 0xd4, 0x52, 0x52, 0x8e, 0x52, 0xe8, 0xd9,
+	0x2d, 0x84, 0xdc, 0x26, 0xc1, 0x99, 0xcf, 0x59, 0xb7, 0x23, 0xf6, 0x55, 0x17, 0xe9, 0x55, 0x58,
+	0x3b, 0x08, 0x52, 0xae, 0xf6, 0x3f, 0x3b, 0x86, 0xfb, 0xb0, 0x6e, 0xc3, 0xea, 0x00, 0xdc, 0x86,
+	0x86, 0xda, 0x4c, 0x9c, 0x1a, 0x0e, 0xbb, 0xae, 0x86, 0xb5, 0xe4, 0xc8, 0xcb, 0x5a, 0xd1, 0x9f,
+	0xd7, 0x60, 0x0e, 0xcf, 0x90, 0x98, 0xc3, 0xe4, 0xb8, 0x97, 0x5f, 0x13, 0xba, 0x68, 0x9e, 0xaa,
+	0x9a, 0x75, 0xaa, 0xcc, 0x73, 0x5f, 0xb7, 0xce, 0xbd, 0xb0, 0x54, 0xa7, 0x9c, 0xa9, 0x9d, 0x90,
+	0x72, 0x64, 0x20, 0x79, 0x7d, 0xc2, 0xfa, 0x67, 0xdd, 0x79, 0xb3, 0x1e, 0x11, 0xa1, 0x97, 0x7d,
+	0x2e, 0x7b, 0x4b, 0x49, 0xca, 0xca, 0xba, 0x4e, 0xf4, 0x5c, 0xcc, 0xeb, 0x44, 0xbf, 0x2e, 0x2c,
+	0x06, 0xe1, 0x71, 0x34, 0x09, 0xb5, 0xa6, 0xd6, 0x45, 0x3c, 0xc4, 0xb1, 0x30, 0x5e, 0x82, 0x31,
+	0x53, 0xa2, 0x91, 0x03, 0x94, 0xa0, 0x95, 0x92, 0x0a, 0x6d, 0x92, 0x31, 0xf9, 0x23, 0x58, 0x35,
+	0x30, 0xc5, 0xe1, 0x77, 0x60, 0x1e, 0x57, 0xaf, 0xed, 0x58, 0xbd, 0xab, 0xd8, 0xc8, 0x93, 0x35,
+	0x74, 0x05, 0x96, 0x1e, 0x31, 0xfe, 0x38, 0x3c, 0x89, 0x34, 0xa5, 0x7f, 0xaf, 0xc1, 0x72, 0x06,
+	0x29, 0x42, 0xdb, 0xb0, 0x1c, 0x0c, 0x58, 0xc8, 0x03, 0x3e, 0xed, 0x59, 0xc6, 0x50, 0x11, 0xc6,
+	0xab, 0xda, 0x1f, 0x05, 0x7e, 0xaa, 0x0e, 0xb5, 0x2c, 0x90, 0x5d, 0x58, 0x47, 0xa9, 0xd3, 0x82,
+	0x94, 0x6d, 0xbb, 0xb4, 0xc1, 0x2a, 0xeb, 0xf0, 0xa0, 0x20, 0x2e, 0x95, 0x46, 0xde, 0x45, 0x2a,
+	0xab, 0xaa, 0x2a, 0xe4, 0x9a, 0xa4, 0x84, 0x4b, 0x96, 0x7a, 0x2a, 0x07, 0x4a, 0xfe, 0xc6, 0x82,
+	0xb4, 0xff, 0x8a, 0xfe, 0x86, 0xe1, 0xb3, 0x34, 0x4a, 0x3e, 0xcb, 0x36, 0x2c, 0xa7, 0xd3, 0xb0,
+	0xcf, 0x06, 0x3d, 0x1e, 0xe1, 0xb8, 0x41, 0x28, 0x76, 0xa7, 0xe1, 0x15, 0x61, 0xe1, 0x5d, 0xb1,
+	0x94, 0x87, 0x8c, 0x8b, 0x43, 0xda, 0xf0, 0x74, 0x91, 0xfe, 0x44, 0xdc, 0x32, 0x99, 0xa3, 0xf4,
+	0xb9, 0x38, 0x89, 0xe4, 0x3a, 0x34, 0xe5, 0x38, 0xe9, 0xa9, 0xaf, 0x4c, 0xdd, 0x86, 0x00, 0x8e,
+	0x4e, 0x7d, 0xf4, 0x03, 0xac, 0xa9, 0x4b, 0xc9, 0x6e, 0x09, 0x6c, 0x5f, 0xce, 0xfc, 0x5d, 0x58,
+	0xd2, 0x2e, 0x58, 0xda, 0x1b, 0xb1, 0x13, 0xae, 0xed, 0xdb, 0x70, 0x32, 0xc6, 0xe1, 0xd2, 0x03,
+	0x76, 0xc2, 0xe9, 0x53, 0x58, 0x55, 0xa7, 0xea, 0x59, 0xcc, 0xf4, 0xd0, 0xdf, 0x29, 0x6a, 0x5a,
+	0x79, 0xd3, 0xad, 0x29, 0x69, 0x31, 0x8d, 0xf2, 0x82, 0xfa, 0xa5, 0x1e, 0x10, 0x55, 0x7d, 0x6f,
+	0x14, 0xa5, 0x4c, 0x11, 0xa4, 0xd0, 0xee, 0x8f, 0xa2, 0xb4, 0x68, 0xb9, 0x9b, 0x18, 0xf2, 0x27,
+	0x9d, 0xf4, 0xfb, 0x78, 0x1a, 0xe5, 0x5d, 0xa9, 0x8b, 0xf4, 0xe7, 0x0e, 0xac, 0x09, 0x6a, 0xfa,
+	0xfc, 0x67, 0x46, 0xc7, 0xe5, 0xa7, 0xd9, 0xee, 0x1b, 0x25, 0xf2, 0x96, 0xf2, 0x22, 0x47, 0xc1,
+	0x38, 0xd0, 0xd7, 0x65, 0x13, 0x91, 0x03, 0x04, 0x50, 0x64, 0x4f, 0xa2, 0xa4, 0x2f, 0x8d, 0xe5,
+	0x86, 0x27, 0x0b, 0xf4, 0x5f, 0x1d, 0x58, 0x15, 0xd3, 0x38, 0xe2, 0x3e, 0x9f, 0xa4, 0x6a, 0x69,
+	0x9f, 0x42, 0x07, 0x97, 0xc1, 0xb4, 0xb8, 0xaa, 0x49, 0xac, 0x67, 0x27, 0x4b, 0xa0, 0xb2, 0xf1,
+	0xfe, 0x15, 0xcf, 0x6e, 0x4c, 0xbe, 0x0b, 0x6d, 0xd3, 0x47, 0x56, 0x6e, 0xd1, 0x35, 0xbd, 0x82,
+	0x92, 0x54, 0xec, 0x5f, 0xf1, 0xac, 0x0e, 0xe4, 0x13, 0x00, 0x71, 0xbf, 0x09, 0xb2, 0xdd, 0xba,
+	0xdd, 0xbd, 0xb4, 0x11, 0xfb, 0x57, 0x3c, 0xa3, 0xf9, 0xdd, 0x06, 0x2c, 0x48, 0xb5, 0x4f, 0x1f,
+	0x41, 0xc7, 0x9a, 0xa9, 0x65, 0xfa, 0xb5, 0xa5, 0xe9, 0x57, 0xf2, 0x97, 0x6a, 0x15, 0xfe, 0xd2,
+	0x9f, 0xcd, 0x01, 0x41, 0x49, 0x2a, 0x6c, 0xd5, 0x7b, 0xb0, 0xa4, 0xac, 0x5a, 0xdb, 0xc2, 0x29,
+	0xa0, 0xe2, 0x7e, 0x8a, 0x06, 0x96, 0x1d, 0xd0, 0xf6, 0x4c, 0x88, 0xdc, 0x02, 0x62, 0x14, 0xb5,
+	0x77, 0x2b, 0xf5, 0x77, 0x45, 0x0d, 0x2a, 0x1a, 0x79, 0x89, 0x6b, 0xf7, 0x4f, 0xd9, 0x48, 0x73,
+	0x62, 0xd3, 0x2b, 0xeb, 0x50, 0x45, 0xc7, 0x13, 0x74, 0x9d, 0x7d, 0xae, 0x2d, 0x05, 0x5d, 0xd6,
+	0x2a, 0x25, 0x37, 0xc8, 0x3b, 0x5e, 0x0e, 0xd8, 0x4e, 0xc1, 0xe2, 0x1b, 0x9d, 0x82, 0xc6, 0x25,
+	0x9c, 0x82, 0xe6, 0x1b, 0x9c, 0x02, 0x78, 0x9d, 0x53, 0xd0, 0x2a, 0x38, 0x05, 0x14, 0xda, 0x71,
+	0x7a, 0xcc, 0xf5, 0x82, 0xbb, 0x6d, 0x51, 0x6f, 0x61, 0xb3, 0xaf, 0xf3, 0x6a, 0x97, 0x62, 0x69,
+	0x96, 0x4b, 0xf1, 0xcb, 0x1a, 0xac, 0xa0, 0x28, 0x58, 0xc7, 0xe5, 0x63, 0x10, 0x27, 0xf1, 0x92,
+	0xa7, 0xc5, 0x6a, 0xfb, 0xbf, 0x3f, 0x2c, 0x77, 0xa0, 0x29, 0x08, 0x46, 0x31, 0x0b, 0xd5, 0x59,
+	0xe9, 0xda, 0x67, 0x25, 0x57, 0x82, 0xfb, 0x57, 0xbc, 0xbc, 0x31, 0xf9, 0x18, 0x9a, 0x19, 0x8f,
+	0x84, 0xe8, 0xb4, 0x76, 0x5d, 0xd5, 0xd3, 0x63, 0xfe, 0x60, 0xfa, 0x30, 0x4a, 0x0e, 0xd3, 0x63,
+	0xfe, 0x50, 0xb2, 0x10, 0xfb, 0x66, 0xcd, 0x8d, 0x53, 0xf6, 0x00, 0xae, 0xaa, 0x15, 0x16, 0x8e,
+	0xc7, 0xb7, 0x60, 0x21, 0x15, 0x5c, 0x52, 0x0e, 0xd4, 0xba, 0x3d, 0x2b, 0xc9, 0x41, 0x4f, 0xb5,
+	0x41, 0xc3, 0x7a, 0xa3, 0x48, 0x47, 0x5d, 0xcb, 0xdf, 0x87, 0x95, 0xd2, 0x95, 0x2a, 0xaf, 0xfa,
+	0x6f, 0xd9, 0x2c, 0x2e, 0x74, 0x2c, 0xc2, 0x25, 0x2a, 0xe4, 0x09, 0xac, 0x6b, 0x2c, 0x61, 0x29,
+	0x4b, 0xce, 0x54, 0xb4, 0xaf, 0xb6, 0x55, 0x37, 0x36, 0x41, 0x91, 0xf1, 0xf2, 0x16, 0x5e, 0x65,
+	0x37, 0xf7, 0x37, 0x35, 0x58, 0xb2, 0xc7, 0x44, 0xd9, 0xcc, 0x6c, 0x87, 0xdc, 0x9e, 0xb0, 0xb0,
+	0xb2, 0x0f, 0x50, 0xab, 0xf2, 0x01, 0x4c, 0x4b, 0xbf, 0xfe, 0x26, 0x4b, 0x7f, 0xee, 0x72, 0x96,
+	0xfe, 0x7c, 0xa5, 0xa5, 0x5f, 0xbc, 0xd8, 0x64, 0xe4, 0xcc, 0xc2, 0x8c, 0xcd, 0x5d, 0x7c, 0xf3,
+	0xe6, 0xe2, 0xfc, 0x4e, 0x22, 0x71, 0xd4, 0xd5, 0xd5, 0xde, 0x10, 0xe7, 0xdf, 0x06, 0xe9, 0x77,
+	0x60, 0xfd, 0x4b, 0x7f, 0x34, 0x62, 0xfc, 0xae, 0x9c, 0x88, 0x16, 0xa4, 0x77, 0xa0, 0x7d, 0x2e,
+	0x3d, 0xdf, 0x5e, 0x14, 0x8e, 0xa6, 0xca, 0xcf, 0x6a, 0x29, 0xec, 0x59, 0x38, 0x9a, 0xd2, 0x0f,
+	0xe0, 0x6a, 0xa1, 0x6b, 0xee, 0x7e, 0xea, 0xc5, 0x62, 0x37, 0xc7, 0xd3, 0x45, 0xba, 0x09, 0x57,
+	0xd5, 0x64, 0xed, 0xe1, 0xe8, 0x2e, 0x6c, 0x14, 0x2b, 0xaa, 0x89, 0xd5, 0x73, 0x62, 0xdf, 0x05,
+	0xf2, 0xbd, 0x09, 0x4b, 0xa6, 0x22, 0xe6, 0x97, 0x05, 0x10, 0x36, 0x8b, 0xf6, 0x3b, 0x06, 0xd5,
+	0x3e, 0x63, 0x53, 0x1d, 0x03, 0xad, 0x65, 0x31, 0x50, 0xfa, 0x09, 0xac, 0x59, 0x04, 0xd4, 0x88,
+	0xef, 0xc2, 0x82, 0x88, 0x1b, 0x6a, 0x81, 0xb7, 0x63, 0x8b, 0xaa, 0x8e, 0xfe, 0x11, 0xd4, 0xf7,
+	0xa3, 0xd8, 0xf4, 0x12, 0x1d, 0xdb, 0x4b, 0x54, 0x12, 0xd6, 0xcb, 0x04, 0x48, 0x8e, 0x6c, 0x83,
+	0x28, 0x1f, 0xfe, 0x98, 0xa3, 0x71, 0x77, 0x12, 0x25, 0xe7, 0x7e, 0x32, 0x50, 0x72, 0x56, 0x40,
+	0x71, 0xf6, 0x27, 0x4c, 0xcb, 0x18, 0xfe, 0xa4, 0xbf, 0x74, 0x60, 0x5e, 0x4c, 0x09, 0x4d, 0x47,
+	0xe9, 0xa6, 0x49, 0x53, 0x04, 0xbd, 0x73, 0x47, 0xec, 0x75, 0x11, 0x2e, 0x04, 0xb5, 0x6b, 0xc5,
+	0xa0, 0x36, 0xde, 0x07, 0xb2, 0x94, 0x47, 0x8b, 0x73, 0x80, 0xbc, 0x8d, 0x61, 0xc9, 0x18, 0xed,
+	0x64, 0x64, 0x0b, 0x68, 0x47, 0x2e, 0x8a, 0x3d, 0x81, 0xd3, 0x9b, 0xb0, 0xfc, 0x34, 0x1a, 0x30,
+	0xc3, 0xe2, 0x9f, 0xb9, 0x1b, 0xf4, 0xa7, 0x0e, 0x34, 0x74, 0x63, 0xb2, 0x0d, 0x73, 0x78, 0xe1,
+	0x16, 0x74, 0x78, 0x16, 0x07, 0xc1, 0x76, 0x9e, 0x68, 0x81, 0xc7, 0x44, 0xdc, 0x91, 0x5a, 0x25,
+	0xd5, 0x32, 0x4b, 0x34, 0xc3, 0x84, 0x89, 0x20, 0xe6, 0x5c, 0x38, 0xba, 0x05, 0x94, 0xfe, 0xca,
+	0x81, 0x8e, 0x35, 0x06, 0x5e, 0x98, 0x23, 0x3f, 0xe5, 0xca, 0x85, 0x55, 0x4c, 0x34, 0x21, 0xd3,
+	0x3b, 0xac, 0xd9, 0xde, 0x61, 0xe6, 0x9d, 0xd4, 0x4d, 0xef, 0xe4, 0x36, 0x34, 0x95, 0x2b, 0xc8,
+	0x34, 0xdf, 0x74, 0xc8, 0x1f, 0x47, 0xd4, 0x11, 0x9e, 0xbc, 0x11, 0xfd, 0x04, 0x5a, 0x46, 0x0d,
+	0x0e, 0x18, 0x32, 0x7e, 0x1e, 0x25, 0x2f, 0xb4, 0x3b, 0xaa, 0x8a, 0x59, 0x88, 0xb1, 0x96, 0x87,
+	0x18, 0xe9, 0xdf, 0x39, 0xd0, 0x41, 0x99, 0x08, 0xc2, 0xe1, 0x61, 0x34, 0x0a, 0xfa, 0x53, 0x21,
+	0x1b, 0x7a, 0xfb, 0x31, 0x9c, 0xc2, 0xfd, 0x4c, 0x36, 0x6c, 0x18, 0x75, 0x1d, 0x5e, 0xfd, 0xe8,
+	0x89, 0x2b, 0xc9, 0xc8, 0xca, 0x42, 0x97, 0x30, 0x54, 0x56, 0x29, 0xeb, 0x8d, 0xd1, 0x98, 0x91,
+	0x1c, 0xb5, 0x41, 0x74, 0xab, 0x10, 0x48, 0x7c, 0xce, 0x7a, 0xe3, 0x60, 0x34, 0x0a, 0x64, 0x5b,
+	0x29, 0xb3, 0x55, 0x55, 0xf4, 0x1f, 0x6b, 0xd0, 0x52, 0xe7, 0xfe, 0xc1, 0x60, 0xc8, 0x50, 0x3e,
+	0xb5, 0x02, 0xce, 0x0e, 0x94, 0x81, 0xe8, 0x7a, 0x4b, 0x65, 0x1b, 0x48, 0x71, 0x03, 0xeb, 0xe5,
+	0x0d, 0x44, 0xab, 0x2b, 0x1a, 0xb0, 0x0f, 0xd0, 0xb8, 0x53, 0x99, 0xa3, 0x1c, 0xd0, 0xb5, 0xbb,
+	0xa2, 0x76, 0x3e, 0xaf, 0x15, 0x80, 0x75, 0x1b, 0x2c, 0x14, 0x6e, 0x83, 0x3b, 0xd0, 0x56, 0x64,
+	0x04, 0xdf, 0xbb, 0x8b, 0x96, 0x28, 0x5b, 0x7b, 0xe2, 0x59, 0x2d, 0x75, 0xcf, 0x5d, 0xdd, 0xb3,
+	0xf1, 0xa6, 0x9e, 0xba, 0x25, 0x06, 0x45, 0x14, 0xf3, 0x1e, 0x25, 0x7e, 0x7c, 0xaa, 0x75, 0xe9,
+	0x00, 0xda, 0x26, 0x4c, 0x6e, 0xc2, 0x3c, 0x76, 0xd3, 0xea, 0xac, 0xfa, 0x78, 0xc9, 0x26, 0x64,
+	0x1b, 0xe6, 0xd9, 0x60, 0xc8, 0xf4, 0x6d, 0x4c, 0xec, 0x1b, 0x06, 0xf7, 0xc8, 0x93, 0x0d, 0xf0,
+	0xb0, 0x23, 0x5a, 0x38, 0xec, 0xb6, 0x2e, 0x5c, 0xc0, 0xe2, 0xe3, 0x01, 0x5d, 0xc7, 0x68, 0xa9,
+	0x90, 0x5a, 0xa3, 0x39, 0xfd, 0xe3, 0x3a, 0xb4, 0x0c, 0x18, 0xcf, 0xed, 0x10, 0x27, 0xdc, 0x1b,
+	0x04, 0xfe, 0x98, 0x71, 0x96, 0x28, 0x49, 0x2d, 0xa0, 0xd8, 0xce, 0x3f, 0x1b, 0xf6, 0xa2, 0x09,
+	0xef, 0x0d, 0xd8, 0x30, 0x61, 0x32, 0x76, 0xef, 0x78, 0x05, 0x14, 0xdb, 0x8d, 0xfd, 0x0b, 0xb3,
+	0x9d, 0x94, 0x87, 0x02, 0xaa, 0x0d, 0x71, 0xc9, 0xa3, 0xb9, 0xdc, 0x10, 0x97, 0x1c, 0x29, 0x6a,
+	0x9c, 0xf9, 0x0a, 0x8d, 0xf3, 0x11, 0x6c, 0x48, 0xdd, 0xa2, 0xce, 0x66, 0xaf, 0x20, 0x26, 0x33,
+	0x6a, 0x31, 0x08, 0x8a, 0x73, 0xd6, 0x02, 0x9e, 0x06, 0x3f, 0x91, 0x81, 0x40, 0xc7, 0x2b, 0xe1,
+	0xd8, 0x56, 0x58, 0xe6, 0x66, 0x5b, 0x19, 0x09, 0x2c, 0xe1, 0xa2, 0xad, 0x7f, 0x61, 0xb7, 0x6d,
+	0xaa, 0xb6, 0x05, 0x9c, 0x5e, 0x87, 0x6b, 0x42, 0x4c, 0x9e, 0x47, 0x71, 0x34, 0x8a, 0x86, 0xd3,
+	0xa3, 0xc9, 0x71, 0xda, 0x4f, 0x82, 0x18, 0xad, 0x2b, 0xfa, 0x4f, 0x0e, 0xac, 0x59, 0xb5, 0xca,
+	0xf8, 0xfe, 0xb6, 0x94, 0xd9, 0x2c, 0xfc, 0x27, 0x25, 0x6b, 0xd5, 0xd0, 0x6c, 0xb2, 0xa1, 0xf4,
+	0xb8, 0xe4, 0xef, 0x94, 0xec, 0xc1, 0xb2, 0x1e, 0x5a, 0x77, 0x94, 0x62, 0xd6, 0x2d, 0x8b, 0x99,
+	0xea, 0xbf, 0xa4, 0x3a, 0x68, 0x12, 0xbf, 0x25, 0xcd, 0x24, 0x36, 0x10, 0x8b, 0x90, 0xe9, 0x92,
+	0xdc, 0x82, 0x16, 0x0e, 0xea, 0xe0, 0x9e, 0xd9, 0xc5, 0x6b, 0xf5, 0x33, 0x30, 0xa5, 0xbf, 0x70,
+	0x00, 0xf2, 0xd9, 0xe1, 0xce, 0xe7, 0xda, 0xd9, 0x91, 0x4e, 0x56, 0x06, 0xa0, 0x09, 0x64, 0x99,
+	0x91, 0x52, 0xdd, 0xb4, 0x34, 0x86, 0x36, 0xc5, 0xfb, 0xb0, 0x3c, 0x1c, 0x45, 0xc7, 0xe2, 0xfa,
+	0xf4, 0xf9, 0x24, 0x61, 0xa9, 0x8a, 0x8b, 0x2f, 0x49, 0xf8, 0xa1, 0x42, 0xf3, 0xdb, 0x61, 0xce,
+	0xb8, 0x1d, 0xe8, 0x5f, 0xd4, 0x60, 0xb5, 0xb4, 0xe6, 0x99, 0xc7, 0x88, 0xec, 0x96, 0xb4, 0xdf,
+	0x8c, 0x18, 0x85, 0xf0, 0x37, 0x0e, 0xdf, 0x68, 0xc1, 0x7e, 0x02, 0x4b, 0x89, 0x54, 0x2f, 0x5a,
+	0xf7, 0xcc, 0xbd, 0x46, 0xf7, 0x74, 0x12, 0xb3, 0x48, 0xbe, 0x01, 0x2b, 0xfe, 0xe0, 0x8c, 0x25,
+	0x3c, 0x10, 0x06, 0xaa, 0xb8, 0xbf, 0xa5, 0xc6, 0x5c, 0x36, 0x70, 0x71, 0xad, 0xbe, 0x0f, 0xcb,
+	0x7d, 0x99, 0xa5, 0xc8, 0x5a, 0xaa, 0xc4, 0x70, 0x0e, 0x63, 0x43, 0xfa, 0xd7, 0x3a, 0x3e, 0x63,
+	0xef, 0xe1, 0x6c, 0x8e, 0x98, 0xab, 0xab, 0x15, 0x56, 0xf7, 0x75, 0x15, 0x4f, 0x19, 0x68, 0xfb,
+	0x57, 0x45, 0xad, 0x24, 0xa8, 0x62, 0x5b, 0x36, 0x4b, 0xe7, 0x2e, 0xc3, 0x52, 0x7a, 0x0b, 0x13,
+	0xb1, 0x7c, 0x0f, 0x77, 0x50, 0x6b, 0xbe, 0xeb, 0xd0, 0x0c, 0xd9, 0x79, 0x4f, 0x6e, 0xb1, 0xbc,
+	0xa7, 0x1b, 0x21, 0x3b, 0x17, 0x6d, 0x30, 0xa6, 0x9a, 0xb7, 0x97, 0x36, 0x26, 0xfd, 0xcb, 0x1a,
+	0x2c, 0x3e, 0x0e, 0xcf, 0xa2, 0xa0, 0x2f, 0x22, 0x24, 0x63, 0x36, 0x8e, 0x74, 0x72, 0x0c, 0x7f,
+	0xe3, 0xb5, 0x2f, 0x42, 0xed, 0x31, 0x57, 0xa1, 0x0b, 0x5d, 0xc4, 0x2b, 0x30, 0xc9, 0xd3, 0xe4,
+	0x52, 0xda, 0x0c, 0x04, 0x53, 0x23, 0x89, 0x99, 0xd2, 0x57, 0xa5, 0x3c, 0xd1, 0x39, 0x6f, 0x24,
+	0x3a, 0x71, 0x1c, 0x95, 0x45, 0x50, 0xe9, 0x56, 0x5d, 0x14, 0xe6, 0x6b, 0xc2, 0x54, 0x1a, 0xc6,
+	0xe7, 0x52, 0x31, 0xd5, 0x3d, 0x1b, 0xc4, 0x0b, 0x57, 0x76, 0x90, 0x6d, 0xa4, 0x42, 0x32, 0x21,
+	0x34, 0x40, 0x8a, 0xaf, 0x02, 0x9a, 0x52, 0x4c, 0x0a, 0x30, 0xfd, 0x02, 0xc8, 0xde, 0x60, 0xa0,
+	0xb8, 0x92, 0x59, 0xe3, 0xf9, 0x7a, 0x1c, 0x6b, 0x3d, 0x15, 0x74, 0x6b, 0xd5, 0x74, 0x1f, 0x40,
+	0xeb, 0xd0, 0x78, 0xd6, 0x20, 0x18, 0xa8, 0x1f, 0x34, 0x28, 0xa6, 0x1b, 0x88, 0x31, 0x60, 0xcd,
+	0x1c, 0x90, 0xfe, 0x7f, 0x20, 0x18, 0x06, 0xcf, 0xe6, 0x97, 0xf9, 0x49, 0xda, 0x2d, 0x35, 0xfd,
+	0x24, 0x85, 0x09, 0x3f, 0x69, 0x0f, 0xd6, 0xac, 0x8e, 0xd9, 0xab, 0x87, 0x46, 0x20, 0x21, 0xad,
+	0x3f, 0x97, 0x94, 0xe0, 0xe9, 0x96, 0x59, 0x3d, 0xde, 0xf4, 0x0a, 0xb4, 0xd4, 0xf3, 0x2f, 0x1d,
+	0x58, 0x54, 0x4b, 0x13, 0x01, 0x19, 0xf3, 0x41, 0x87, 0x72, 0x7a, 0x4d, 0xac, 0x3a, 0xd9, 0x5d,
+	0xde, 0xe9, 0x7a, 0xd5, 0x4e, 0x63, 0xfa, 0xd1, 0xe7, 0xa7, 0xc2, 0x88, 0x6d, 0x7a, 0xe2, 0xb7,
+	0x76, 0x4a, 0xe6, 0x73, 0xa7, 0x44, 0xe5, 0x69, 0xd4, 0xa4, 0xb2, 0x14, 0xc2, 0x5d, 0x58, 0xb7,
+	0xe1, 0x9c, 0x07, 0x6a, 0x82, 0x45, 0x1e, 0xa8, 0xa6, 0x5e, 0x56, 0x8f, 0xe9, 0xd8, 0xfb, 0x6c,
+	0xc4, 0x38, 0xdb, 0x1b, 0x8d, 0x8a, 0xf4, 0xaf, 0xc3, 0xb5, 0x8a, 0x3a, 0x75, 0xd6, 0x1e, 0xc2,
+	0xea, 0x7d, 0x76, 0x3c, 0x19, 0x1e, 0xb0, 0xb3, 0x3c, 0x50, 0x42, 0x60, 0x2e, 0x3d, 0x8d, 0xce,
+	0xd5, 0x7e, 0x89, 0xdf, 0x18, 0xcc, 0x1d, 0x61, 0x9b, 0x5e, 0x1a, 0xb3, 0xbe, 0x92, 0xa6, 0xa6,
+	0x40, 0x8e, 0x62, 0xd6, 0xa7, 0x1f, 0x01, 0x31, 0xe9, 0xa8, 0x25, 0xe0, 0x09, 0x98, 0x1c, 0xf7,
+	0xd2, 0x69, 0xca, 0xd9, 0x58, 0x1f, 0x7e, 0x13, 0xa2, 0xef, 0x43, 0xfb, 0xd0, 0xc7, 0x57, 0x18,
+	0xea, 0x9d, 0x0c, 0xfa, 0x44, 0xfe, 0x14, 0xc5, 0x33, 0xf3, 0x89, 0x44, 0x35, 0x4d, 0x60, 0x41,
+	0x36, 0x44, 0xa2, 0x03, 0x96, 0xf2, 0x20, 0x94, 0xf1, 0x29, 0x45, 0xd4, 0x80, 0x4a, 0xdb, 0x5d,
+	0xab, 0xd8, 0x6e, 0x65, 0xba, 0xe8, 0xe4, 0x9d, 0xda, 0x57, 0x0b, 0x43, 0x8f, 0xdc, 0x8b, 0xb8,
+	0xcf, 0xd9, 0xb3, 0x30, 0x88, 0xc2, 0xcf, 0xd8, 0x34, 0xcf, 0xfa, 0x6c, 0x14, 0x2b, 0xd4, 0x8a,
+	0x31, 0x34, 0x89, 0x98, 0xe1, 0xd5, 0xe5, 0x00, 0x72, 0xe9, 0x41, 0xca, 0x83, 0xb1, 0xcf, 0xd9,
+	0x43, 0x96, 0x1d, 0x93, 0x42, 0x28, 0x52, 0xc6, 0x6c, 0x4d, 0x88, 0xfa, 0xb0, 0x66, 0xf5, 0x53,
+	0x83, 0xbd, 0x07, 0x4b, 0xe8, 0x38, 0x60, 0x50, 0xf3, 0x5c, 0xaa, 0x71, 0x19, 0x05, 0x28, 0xa0,
+	0xe2, 0x49, 0x8f, 0x42, 0x44, 0x40, 0x54, 0x4a, 0xb8, 0x85, 0xd1, 0x3f, 0x77, 0x60, 0xe9, 0xee,
+	0x64, 0x1c, 0x1b, 0xf3, 0xaa, 0x78, 0x98, 0x80, 0x97, 0x8a, 0x8e, 0xb4, 0x2a, 0xb6, 0x66, 0xe5,
+	0xe2, 0x3a, 0xea, 0xa5, 0x75, 0x54, 0x4c, 0x78, 0xae, 0x6a, 0xc2, 0xf4, 0x08, 0x96, 0xb3, 0xb9,
+	0xcc, 0x7e, 0x25, 0x81, 0x93, 0x49, 0x58, 0x3c, 0xf2, 0xfb, 0x6c, 0xa0, 0xb2, 0x19, 0x59, 0x59,
+	0x1f, 0xbf, 0x7a, 0x7e, 0xfc, 0xbe, 0x07, 0xee, 0x83, 0x8b, 0x38, 0x4a, 0x78, 0x16, 0x4c, 0xe9,
+	0xbf, 0x98, 0xc4, 0x7a, 0xb1, 0x1f, 0x5a, 0x97, 0xdd, 0x6b, 0x72, 0x1c, 0x46, 0x33, 0x7a, 0x02,
+	0x1d, 0x8b, 0xd8, 0xff, 0x88, 0x0a, 0xf2, 0x4d, 0x94, 0x8e, 0x05, 0x0d, 0x1d, 0x8e, 0x37, 0x20,
+	0x3c, 0xc2, 0xd6, 0x38, 0x96, 0xa2, 0x9b, 0xca, 0x64, 0x90, 0xaa, 0x09, 0xfd, 0x38, 0x3d, 0x8d,
+	0x38, 0xf9, 0x7f, 0xd0, 0xca, 0x87, 0xd0, 0x0a, 0xa4, 0x72, 0x2a, 0x66, 0x3b, 0x0c, 0x3e, 0x8f,
+	0x27, 0x23, 0x1e, 0xf4, 0xca, 0x33, 0x2a, 0x57, 0xd0, 0x11, 0x74, 0x3d, 0x96, 0xf2, 0x28, 0x61,
+	0xf9, 0x0c, 0x34, 0x43, 0x29, 0xb4, 0x8d, 0xa6, 0x72, 0x06, 0x6d, 0xcf, 0xc2, 0xbe, 0xe2, 0x68,
+	0x78, 0x1c, 0xe5, 0x68, 0x7a, 0x24, 0xa5, 0xc4, 0x3e, 0x81, 0xf9, 0xe7, 0x17, 0xcf, 0x26, 0x3c,
+	0xd7, 0xe1, 0x8e, 0xa9, 0xc3, 0x31, 0xab, 0xfb, 0xa2, 0x27, 0x19, 0xa6, 0xa8, 0xe7, 0x00, 0xfd,
+	0xd3, 0x1a, 0x2c, 0x1d, 0x05, 0xc3, 0xf0, 0x3e, 0x93, 0x40, 0x54, 0x4a, 0x73, 0xb7, 0xf3, 0x40,
+	0xc6, 0xbb, 0xd0, 0x51, 0x61, 0xfa, 0x1e, 0x3f, 0x67, 0xfe, 0x0b, 0x45, 0xce, 0x06, 0x51, 0xcc,
+	0x75, 0x7c, 0x50, 0x8d, 0xaa, 0x0c, 0x5f, 0x1b, 0xc5, 0x60, 0x9a, 0x4c, 0xeb, 0x28, 0xe3, 0x4a,
+	0x07, 0xd3, 0xc4, 0x62, 0x3c, 0x55, 0x87, 0xb3, 0x49, 0x83, 0xa1, 0x50, 0x64, 0xd2, 0xbf, 0xd2,
+	0x45, 0x14, 0x9c, 0x20, 0xcc, 0x33, 0x45, 0xf2, 0xe1, 0x92, 0x09, 0x91, 0x6f, 0xc2, 0x22, 0x66,
+	0x69, 0x46, 0x51, 0x5f, 0x39, 0xdd, 0xda, 0x0d, 0xf9, 0x8c, 0x4d, 0x0f, 0xa2, 0xbe, 0xcf, 0xa3,
+	0xc4, 0xd3, 0x2d, 0xe8, 0x31, 0x2c, 0x22, 0x23, 0x50, 0xc7, 0x52, 0x68, 0x27, 0xfe, 0x79, 0x8f,
+	0x5f, 0x08, 0xe5, 0x90, 0xea, 0x54, 0xa2, 0x89, 0x91, 0x0f, 0xa1, 0x99, 0x06, 0xc3, 0xb0, 0x37,
+	0x60, 0x69, 0x5f, 0x19, 0xdc, 0x57, 0xf5, 0xeb, 0x3a, 0x8b, 0x9f, 0x5e, 0xde, 0x8e, 0xde, 0x80,
+	0x86, 0x1c, 0x23, 0x8d, 0xf1, 0x88, 0xa6, 0xc1, 0x50, 0xd1, 0xc6, 0x9f, 0xf4, 0x33, 0x58, 0x7e,
+	0x8c, 0xb3, 0x3f, 0x12, 0x3d, 0x45, 0xa3, 0x2e, 0x2c, 0x2a, 0xae, 0x29, 0x09, 0xd2, 0x45, 0xb4,
+	0x55, 0xd2, 0x60, 0x68, 0xef, 0xab, 0x81, 0xd0, 0x40, 0xee, 0xeb, 0x13, 0x96, 0xa6, 0xfe, 0x10,
+	0x95, 0xda, 0x6b, 0xf6, 0x75, 0x05, 0xea, 0xe3, 0x74, 0xa8, 0x88, 0xe0, 0x4f, 0x93, 0x73, 0xf5,
+	0x37, 0x72, 0x6e, 0x07, 0x96, 0xad, 0xa1, 0xd2, 0x18, 0x85, 0x0e, 0x57, 0x2d, 0x5c, 0x1c, 0x35,
+	0x5a, 0x0e, 0xd0, 0x43, 0x69, 0x2f, 0x7d, 0x1e, 0xa6, 0x71, 0xfe, 0xf6, 0xd3, 0xce, 0x38, 0x39,
+	0xc5, 0x8c, 0x13, 0xd6, 0xfa, 0x17, 0xb2, 0xa0, 0x52, 0xd1, 0x39, 0x40, 0xff, 0xca, 0x81, 0xb9,
+	0xcf, 0xf9, 0x45, 0x64, 0x69, 0x68, 0xa7, 0xa0, 0xa1, 0x8d, 0xb7, 0x18, 0xb5, 0xd2, 0x5b, 0x0c,
+	0x99, 0x96, 0xeb, 0xe5, 0x11, 0x2c, 0x03, 0xb1, 0xcf, 0x90, 0x0a, 0x0d, 0x65, 0x80, 0xb0, 0x92,
+	0xac, 0xd7, 0xc9, 0xf3, 0xca, 0x4a, 0x32, 0x41, 0x7a, 0x07, 0xd6, 0xac, 0x45, 0xe7, 0xaf, 0x25,
+	0x26, 0xfc, 0x22, 0x2a, 0xbe, 0x96, 0xc0, 0xc5, 0x78, 0xb2, 0x86, 0xfe, 0x83, 0x03, 0x6b, 0x15,
+	0x19, 0x20, 0x61, 0xe7, 0x1a, 0x29, 0x94, 0x5e, 0x96, 0x4a, 0x2d, 0xc2, 0xd8, 0x32, 0x4b, 0x3f,
+	0x5a, 0x1c, 0x28, 0xc2, 0xe2, 0x8e, 0xb2, 0x93, 0x98, 0x2a, 0x42, 0x6a, 0xa3, 0x66, 0x3b, 0x83,
+	0x2d, 0x6d, 0xaf, 0x80, 0xd2, 0x1e, 0xac, 0xaa, 0xe9, 0xe2, 0xcc, 0xbf, 0x60, 0x49, 0x70, 0x32,
+	0xfd, 0x0a, 0x13, 0xdf, 0x82, 0x16, 0x12, 0x64, 0x83, 0x1e, 0xe6, 0xba, 0xf4, 0xe5, 0x60, 0x40,
+	0xf4, 0x4f, 0x1c, 0x58, 0x33, 0x46, 0x78, 0x18, 0x84, 0xfe, 0x08, 0x03, 0x1d, 0x5f, 0x69, 0x0c,
+	0x14, 0xcd, 0xc2, 0x18, 0x06, 0x24, 0x2c, 0x08, 0xa4, 0xdb, 0x93, 0x1a, 0x40, 0xe9, 0x33, 0x0b,
+	0x43, 0x07, 0x75, 0x5d, 0xcd, 0x43, 0xbc, 0xd8, 0x0e, 0x70, 0xd7, 0x9f, 0xa4, 0x43, 0xf2, 0x29,
+	0xb4, 0x90, 0x48, 0xef, 0x4c, 0xac, 0x5d, 0xdd, 0x8a, 0x3a, 0xac, 0x51, 0xe2, 0xcd, 0xfe, 0x15,
+	0xcf, 0x6c, 0x4e, 0xee, 0x42, 0x47, 0x14, 0x4f, 0xd4, 0xba, 0x94, 0xaa, 0x71, 0xcb, 0xfd, 0xf5,
+	0xca, 0xf1, 0x01, 0x80, 0xd5, 0xe5, 0x6e, 0x13, 0x16, 0x79, 0x12, 0x0c, 0x87, 0x2c, 0xa1, 0x1b,
+	0xd9, 0x24, 0x31, 0x25, 0xc4, 0x8e, 0x38, 0x13, 0xf7, 0x08, 0x3e, 0x7f, 0x58, 0xb9, 0xeb, 0xf3,
+	0xfe, 0xa9, 0x91, 0x57, 0x2f, 0x26, 0xca, 0x9d, 0x72, 0xa2, 0x7c, 0x56, 0xe2, 0xbb, 0x76, 0xc9,
+	0xc4, 0x77, 0xdd, 0x4e, 0x7c, 0xd3, 0x5f, 0xd7, 0x60, 0xb3, 0x38, 0x8d, 0xdc, 0x44, 0x69, 0x14,
+	0xd2, 0x8d, 0xfa, 0x55, 0x64, 0xa9, 0x47, 0xa3, 0xf8, 0x38, 0x27, 0xd7, 0x1a, 0xb3, 0x33, 0xe9,
+	0xff, 0x47, 0xcf, 0x6b, 0xbf, 0xd2, 0xd3, 0x65, 0xfa, 0xfb, 0xd0, 0x2d, 0xf3, 0x47, 0x69, 0x92,
+	0xdf, 0x99, 0x99, 0x97, 0xad, 0x4c, 0x7d, 0x97, 0xf3, 0xaf, 0xf4, 0x6f, 0xea, 0xb0, 0xae, 0xa8,
+	0xee, 0xf5, 0xfb, 0x2c, 0xe6, 0x86, 0x8d, 0xfe, 0x06, 0x49, 0xa8, 0x38, 0x6e, 0xb5, 0xd7, 0x1e,
+	0x69, 0x29, 0x11, 0x5a, 0x04, 0x4c, 0x28, 0x93, 0x10, 0xac, 0x9e, 0x33, 0x24, 0x04, 0xeb, 0x6e,
+	0x40, 0xb3, 0x9f, 0x9e, 0x59, 0xaf, 0x42, 0x73, 0x00, 0xf5, 0xf8, 0x60, 0x92, 0x72, 0xf5, 0xe6,
+	0x46, 0x7d, 0xb9, 0x91, 0x23, 0x38, 0x4b, 0xb5, 0x58, 0x95, 0x29, 0xd6, 0xb1, 0x8b, 0x22, 0x8c,
+	0x09, 0x0b, 0xbc, 0x5d, 0x84, 0x09, 0xd5, 0x0b, 0xc2, 0xde, 0xc9, 0x28, 0x4b, 0x94, 0xd6, 0xbd,
+	0xaa, 0x2a, 0x7c, 0x34, 0x82, 0xb0, 0x2f, 0x18, 0xc7, 0x06, 0xf2, 0xdd, 0xa2, 0x7a, 0x59, 0x51,
+	0x51, 0x63, 0x8b, 0x26, 0x14, 0x45, 0x13, 0x43, 0x11, 0x92, 0xf9, 0xc8, 0x4a, 0xf9, 0x86, 0xd2,
+	0x40, 0xd0, 0xbf, 0xbf, 0x5a, 0xd8, 0xaa, 0xfc, 0xd5, 0xdc, 0x25, 0x15, 0x9f, 0x3d, 0xc6, 0x5c,
+	0x71, 0x0c, 0xf9, 0x94, 0x16, 0x69, 0x2b, 0xf7, 0x42, 0x95, 0xd0, 0xce, 0x64, 0x49, 0x12, 0x25,
+	0x3a, 0x9f, 0x25, 0x0a, 0xf4, 0x37, 0x0e, 0x90, 0x72, 0x6a, 0xfe, 0x52, 0x19, 0xf7, 0xcb, 0x0b,
+	0xcf, 0xeb, 0x62, 0x96, 0x37, 0xa0, 0x19, 0x84, 0x01, 0x0f, 0xd0, 0x38, 0x11, 0xab, 0x69, 0x78,
+	0x39, 0x80, 0x8b, 0x15, 0x4f, 0x8d, 0x59, 0xda, 0x0b, 0x42, 0x75, 0x43, 0x1b, 0x08, 0xfd, 0x5d,
+	0x80, 0xdc, 0xb6, 0xc1, 0xd6, 0x68, 0xdd, 0x9c, 0xf8, 0xe3, 0x40, 0x45, 0x6e, 0x3a, 0x9e, 0x81,
+	0xe0, 0x58, 0x58, 0x32, 0xdf, 0x28, 0xe5, 0x00, 0xfd, 0x06, 0x74, 0xee, 0xb3, 0x24, 0x38, 0x63,
+	0xca, 0x37, 0x9e, 0x6d, 0x7a, 0xd1, 0x21, 0xb4, 0x8f, 0x4e, 0xfd, 0x84, 0x0d, 0xa4, 0x7f, 0x6d,
+	0x1a, 0x5e, 0xce, 0x9b, 0x0c, 0x2f, 0x0c, 0xe6, 0xb3, 0xf8, 0x94, 0x8d, 0x59, 0xe2, 0x8f, 0xec,
+	0xe7, 0x4c, 0x25, 0x9c, 0x7e, 0x08, 0x1d, 0x63, 0xa0, 0x34, 0xc6, 0x8d, 0x49, 0x05, 0x60, 0xb8,
+	0x6b, 0x6d, 0xcf, 0xc2, 0x6e, 0xee, 0x66, 0x1e, 0x9e, 0x7c, 0x41, 0x40, 0x16, 0xa1, 0xbe, 0x77,
+	0x70, 0xb0, 0x72, 0x85, 0xb4, 0x60, 0xf1, 0xd9, 0xe1, 0x83, 0xa7, 0x8f, 0x9f, 0x3e, 0x5a, 0x71,
+	0xb0, 0x70, 0xef, 0xe0, 0xd9, 0x11, 0x16, 0x6a, 0xbb, 0xff, 0xf6, 0x16, 0x34, 0xb3, 0x04, 0x12,
+	0xf9, 0x11, 0x74, 0xac, 0x97, 0x00, 0xe4, 0xba, 0x5a, 0x4f, 0xd5, 0xd3, 0x02, 0xf7, 0x46, 0x75,
+	0xa5, 0x72, 0x74, 0xde, 0xfe, 0xd9, 0xbf, 0xfc, 0xc7, 0xaf, 0x6a, 0x5d, 0xb2, 0xb1, 0x73, 0xf6,
+	0xc1, 0x8e, 0x4a, 0xf5, 0xef, 0x88, 0xe7, 0x88, 0xf2, 0xf5, 0xe3, 0x0b, 0x58, 0xb2, 0x5f, 0x0a,
+	0x90, 0x1b, 0xb6, 0xc7, 0x57, 0x18, 0xed, 0xad, 0x19, 0xb5, 0x6a, 0xb8, 0x1b, 0x62, 0xb8, 0x0d,
+	0xb2, 0x6e, 0x0e, 0x97, 0xdd, 0x2c, 0x4c, 0xbc, 0x57, 0x35, 0x3f, 0xf2, 0x22, 0x9a, 0x5e, 0xf5,
+	0xc7, 0x5f, 0xee, 0xb5, 0xf2, 0x07, 0x5d, 0xea, 0x0b, 0x30, 0xda, 0x15, 0x43, 0x11, 0xb2, 0x82,
+	0x43, 0x99, 0xdf, 0x78, 0x91, 0x1f, 0x42, 0x33, 0xfb, 0x66, 0x82, 0x6c, 0x1a, 0x9f, 0xef, 0x98,
+	0xdf, 0x94, 0xb8, 0xdd, 0x72, 0x85, 0x5a, 0xc4, 0x75, 0x41, 0xf9, 0x2a, 0x2d, 0x51, 0xfe, 0xd8,
+	0xb9, 0x49, 0x0e, 0xe0, 0xaa, 0xf2, 0xa5, 0x8f, 0xd9, 0x57, 0x59, 0x49, 0xc5, 0xa7, 0x69, 0xb7,
+	0x1d, 0xf2, 0x09, 0x34, 0xf4, 0x57, 0x31, 0x64, 0xa3, 0xfa, 0x43, 0x23, 0x77, 0xb3, 0x84, 0x2b,
+	0xad, 0x75, 0x1f, 0x5a, 0x86, 0x75, 0x4c, 0xae, 0x65, 0x99, 0xc8, 0xa2, 0x9b, 0xe0, 0xba, 0x55,
+	0x55, 0x8a, 0xca, 0x1e, 0x40, 0xfe, 0xed, 0x05, 0xe9, 0xce, 0xfa, 0x44, 0xc4, 0xbd, 0x56, 0x51,
+	0xa3, 0x48, 0x0c, 0x61, 0xb5, 0xf4, 0x69, 0x07, 0xf9, 0x5a, 0xde, 0xbe, 0xf2, 0xa3, 0x8f, 0xd7,
+	0x10, 0xa4, 0x1b, 0x62, 0x07, 0x56, 0xc8, 0x12, 0xee, 0x40, 0xc8, 0xce, 0xb5, 0xa5, 0xfd, 0x03,
+	0x68, 0x19, 0x1f, 0x68, 0x10, 0xe3, 0x89, 0x59, 0xe1, 0x5b, 0x10, 0xd7, 0xad, 0xaa, 0x52, 0xd4,
+	0xd7, 0x05, 0xf5, 0x25, 0xda, 0x44, 0xea, 0xe2, 0xc9, 0x31, 0x6e, 0xec, 0xf7, 0xa0, 0x99, 0xbd,
+	0xcb, 0x26, 0x9b, 0x06, 0xc3, 0xcc, 0xd7, 0xdb, 0x6e, 0xb7, 0x5c, 0xa1, 0xa8, 0xae, 0x0a, 0xaa,
+	0x2d, 0x92, 0x53, 0x25, 0x4f, 0x60, 0x51, 0xbd, 0xcf, 0x26, 0x57, 0x73, 0xe9, 0x30, 0x92, 0xb6,
+	0xee, 0x46, 0x11, 0x56, 0xc4, 0xd6, 0x04, 0xb1, 0x0e, 0x69, 0x21, 0xb1, 0x21, 0xe3, 0x01, 0xd2,
+	0x18, 0xc1, 0xb2, 0xfd, 0x34, 0x2b, 0xcd, 0x0e, 0x6b, 0xe5, 0xf3, 0x35, 0xf7, 0xad, 0x19, 0xb5,
+	0x55, 0x87, 0x55, 0x1f, 0xd2, 0x1d, 0xfd, 0xaa, 0xef, 0x0f, 0xa0, 0x6d, 0x7e, 0x0c, 0x40, 0x4c,
+	0x19, 0x2a, 0x7c, 0x38, 0xe0, 0x5e, 0xaf, 0xac, 0xb3, 0xd9, 0x4d, 0xda, 0xe6, 0x30, 0xe4, 0x07,
+	0xb0, 0x6c, 0x18, 0x64, 0x47, 0xd3, 0xb0, 0x9f, 0x6d, 0x67, 0xd9, 0x90, 0x75, 0xab, 0xc2, 0x50,
+	0x74, 0x53, 0x10, 0x5e, 0xa5, 0x16, 0x61, 0xdc, 0xca, 0x7b, 0xd0, 0x32, 0x68, 0xbc, 0x8e, 0xee,
+	0xa6, 0x51, 0x65, 0xbe, 0x87, 0xbc, 0xed, 0x90, 0x03, 0x58, 0x29, 0x9a, 0xfd, 0x99, 0x22, 0xae,
+	0x72, 0x5a, 0xdc, 0x42, 0xa5, 0xe5, 0x2c, 0x90, 0xa3, 0x0a, 0x5f, 0xe1, 0xed, 0x59, 0xb6, 0xb8,
+	0x9a, 0xdc, 0xd7, 0x66, 0xd6, 0xab, 0x73, 0x77, 0x08, 0xcb, 0x96, 0x3d, 0x13, 0x25, 0x45, 0xed,
+	0x6d, 0xdb, 0x39, 0xee, 0xf5, 0xea, 0x5a, 0x31, 0xdc, 0xb6, 0x73, 0xdb, 0x21, 0xbf, 0xc6, 0x6f,
+	0x3b, 0x8d, 0x27, 0xdd, 0xc4, 0xca, 0x05, 0x17, 0xe6, 0xd7, 0x35, 0xeb, 0x4c, 0xee, 0xd1, 0x2f,
+	0xc4, 0xce, 0x1c, 0xde, 0x7c, 0x6a, 0x49, 0xd6, 0x4b, 0xeb, 0x35, 0xe0, 0x2d, 0xf3, 0xbb, 0xcf,
+	0x57, 0xc5, 0x4a, 0xf3, 0xf1, 0xf2, 0xab, 0x9d, 0x97, 0xe2, 0xa5, 0xf7, 0xab, 0xdb, 0x0e, 0xf9,
+	0x58, 0x7e, 0xbe, 0xab, 0xd3, 0x34, 0xc4, 0xd0, 0x8d, 0x45, 0x59, 0x31, 0x3f, 0x8a, 0x15, 0x8b,
+	0xfb, 0x43, 0x58, 0x36, 0xfa, 0x0a, 0x91, 0xbb, 0x6c, 0x7f, 0xfa, 0xae, 0x58, 0xd1, 0xdb, 0xf4,
+	0x9a, 0xb5, 0xa2, 0xe2, 0xe5, 0x70, 0x08, 0x90, 0xe7, 0xdc, 0x48, 0x21, 0x01, 0x95, 0x29, 0xbc,
+	0x72, 0x5a, 0xce, 0x16, 0x65, 0x9d, 0xa7, 0x42, 0x8a, 0x3f, 0x92, 0xa7, 0x50, 0xb5, 0x4f, 0x2d,
+	0x25, 0x6f, 0xe7, 0xce, 0x5c, 0xb7, 0xaa, 0x4a, 0xd1, 0xff, 0xba, 0xa0, 0xff, 0x16, 0xb9, 0x6e,
+	0xd2, 0xdf, 0x79, 0x69, 0xe6, 0xda, 0x5e, 0x91, 0x2f, 0xa0, 0x73, 0x10, 0x45, 0x2f, 0x26, 0xb1,
+	0x5e, 0x00, 0xb1, 0xb3, 0x47, 0x98, 0xef, 0x73, 0x0b, 0x8b, 0xa2, 0xef, 0x08, 0xca, 0xd7, 0xc9,
+	0x35, 0x9b, 0x72, 0x9e, 0x01, 0x7c, 0x45, 0x7c, 0x58, 0xcd, 0xae, 0xcc, 0x6c, 0x21, 0xae, 0x4d,
+	0xc7, 0x8c, 0x4f, 0x97, 0xc6, 0xb0, 0x8c, 0x98, 0x6c, 0x8c, 0x54, 0xd3, 0xbc, 0xed, 0x90, 0x43,
+	0x68, 0xdf, 0x67, 0xfd, 0x68, 0xc0, 0x54, 0xc6, 0x67, 0x2d, 0x9f, 0x79, 0x96, 0x29, 0x72, 0x3b,
+	0x16, 0x68, 0xab, 0xbf, 0xd8, 0x9f, 0x26, 0xec, 0xc7, 0x3b, 0x2f, 0x55, 0x2a, 0xe9, 0x95, 0x56,
+	0x7f, 0x6a, 0xe9, 0xb6, 0xfa, 0x2b, 0xe4, 0xcb, 0xdc, 0xeb, 0x95, 0x75, 0x55, 0xea, 0x4f, 0xa7,
+	0xdf, 0xc8, 0x08, 0x56, 0x4b, 0x29, 0xb6, 0xec, 0xca, 0x9c, 0x95, 0x98, 0x73, 0xb7, 0x66, 0x37,
+	0xb0, 0x47, 0xbb, 0x69, 0x8f, 0x76, 0x84, 0xc6, 0xb5, 0x64, 0x96, 0x7c, 0xc4, 0xe4, 0xda, 0x8a,
+	0xc0, 0x7c, 0xf0, 0xe4, 0xae, 0x55, 0xd4, 0xd9, 0xb7, 0x9b, 0x78, 0x41, 0x44, 0x7e, 0x08, 0xad,
+	0x47, 0x8c, 0xeb, 0x57, 0x4b, 0x99, 0xf9, 0x52, 0x78, 0xc6, 0xe4, 0x56, 0x3c, 0x7a, 0xa2, 0x5b,
+	0x82, 0x9a, 0x4b, 0xba, 0x19, 0xb5, 0x1d, 0x36, 0x18, 0x32, 0xa9, 0x04, 0x7a, 0xc1, 0xe0, 0x15,
+	0xf9, 0xbe, 0x20, 0x9e, 0x3d, 0x69, 0xdc, 0x30, 0xde, 0xc2, 0x98, 0xc4, 0x97, 0x0b, 0x78, 0x15,
+	0x65, 0x74, 0xb9, 0x77, 0x5e, 0x2a, 0xe7, 0xe1, 0x15, 0x09, 0xa1, 0x65, 0x3c, 0x53, 0xcd, 0x0e,
+	0x54, 0xf9, 0xed, 0xab, 0xeb, 0x56, 0x55, 0x29, 0x3e, 0x6f, 0x8b, 0x71, 0x28, 0xd9, 0xca, 0xc7,
+	0x91, 0x2f, 0x59, 0xf3, 0x91, 0x76, 0x5e, 0xfa, 0x63, 0xfe, 0x8a, 0x7c, 0x29, 0xbe, 0xdb, 0x32,
+	0x5f, 0x66, 0xe5, 0x86, 0x4f, 0xf1, 0x11, 0x97, 0x4b, 0xca, 0x55, 0xb6, 0x31, 0x24, 0x87, 0x12,
+	0xe6, 0xc0, 0x97, 0x86, 0x25, 0x6a, 0xee, 0x15, 0xd1, 0x52, 0x32, 0xf3, 0x21, 0x92, 0xeb, 0x56,
+	0xb5, 0xc8, 0x6e, 0x3e, 0x61, 0x94, 0xca, 0x17, 0x16, 0x86, 0x51, 0x6a, 0x3d, 0xd1, 0x70, 0x37,
+	0x4b, 0x78, 0x6e, 0x4e, 0xe6, 0x69, 0xdd, 0xcc, 0x9c, 0x2c, 0x65, 0x8c, 0xdd, 0x6b, 0x15, 0x35,
+	0x8a, 0xc4, 0x13, 0x58, 0xb2, 0x73, 0xa5, 0xd9, 0xad, 0x56, 0x99, 0x5b, 0x75, 0xdf, 0x9a, 0x51,
+	0x9b, 0x9b, 0xc9, 0x46, 0x2a, 0x34, 0xe3, 0x7e, 0x39, 0xad, 0xea, 0xba, 0x55, 0x55, 0x8a, 0xca,
+	0x1d, 0x58, 0x54, 0x09, 0xc6, 0xcc, 0x96, 0xb3, 0x93, 0x9f, 0xee, 0x46, 0x11, 0x56, 0x3d, 0x9f,
+	0xc2, 0x5a, 0x45, 0x16, 0x91, 0xbc, 0xa3, 0x07, 0x9b, 0x99, 0x61, 0x74, 0xd7, 0x8b, 0xce, 0x96,
+	0xe8, 0xf8, 0x7d, 0xd8, 0x2c, 0xee, 0xfb, 0x5d, 0x95, 0x1d, 0xdb, 0xaa, 0xea, 0x60, 0xed, 0xbc,
+	0xf9, 0x0d, 0x92, 0x9d, 0xff, 0xbb, 0xed, 0x90, 0x2f, 0xb2, 0x74, 0x59, 0x81, 0xae, 0x56, 0x4c,
+	0xb3, 0x52, 0x77, 0xee, 0x0d, 0xbb, 0x81, 0x9d, 0x6d, 0xdb, 0xfd, 0xdb, 0x1a, 0x2c, 0x60, 0xb6,
+	0x83, 0x25, 0xe4, 0x36, 0x74, 0xf0, 0xd7, 0x33, 0x71, 0xbd, 0x7b, 0xfe, 0x79, 0x76, 0x49, 0xaa,
+	0x3c, 0x92, 0xbb, 0x6c, 0x95, 0xd3, 0x98, 0x7c, 0x8a, 0x5f, 0xe1, 0x8d, 0xe3, 0x09, 0x67, 0x46,
+	0xa2, 0xa7, 0xd4, 0x6d, 0x23, 0xbb, 0x32, 0xec, 0x64, 0xd0, 0xa7, 0xd0, 0x32, 0xf2, 0x2c, 0xc4,
+	0x4c, 0x37, 0xe5, 0x69, 0x1e, 0x77, 0xa3, 0x0a, 0x4e, 0x63, 0xf2, 0x6d, 0x68, 0x66, 0x41, 0x09,
+	0x52, 0x8e, 0x2a, 0x64, 0x1b, 0x64, 0x47, 0x2e, 0x3e, 0x85, 0x65, 0x09, 0x64, 0xc1, 0x83, 0xec,
+	0x3e, 0x32, 0xe3, 0x16, 0xee, 0x7a, 0x19, 0x4c, 0xe3, 0xe3, 0x05, 0xf1, 0x4f, 0x60, 0x3e, 0xfc,
+	0xef, 0x01, 0x00, 0x2a, 0xa7, 0x61, 0x96, 0x36, 0x46, 0x00, 0x00,
 }
@@ -3,10 +3,16 @@ package btcwallet
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 
+	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcjson"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcwallet/chain"
+	"github.com/roasbeef/btcwallet/waddrmgr"
 )
 
 var (
@@ -20,14 +26,57 @@ var (
 //
 // This method is a part of the lnwallet.BlockChainIO interface.
 func (b *BtcWallet) GetBestBlock() (*chainhash.Hash, int32, error) {
-	return b.rpc.GetBestBlock()
+	return b.chain.GetBestBlock()
 }
 
-// GetUtxo returns the original output referenced by the passed outpoint.
+// GetUtxo returns the original output referenced by the passed outpoint. The
+// heightHint is only used by light clients, which must scan the chain from
+// that height in order to locate the output.
 //
 // This method is a part of the lnwallet.BlockChainIO interface.
-func (b *BtcWallet) GetUtxo(txid *chainhash.Hash, index uint32) (*wire.TxOut, error) {
-	txout, err := b.rpc.GetTxOut(txid, index, false)
+func (b *BtcWallet) GetUtxo(txid *chainhash.Hash, index uint32,
+	heightHint uint32) (*wire.TxOut, error) {
+
+	var (
+		txout *btcjson.GetTxOutResult
+		err   error
+	)
+	switch backend := b.chain.(type) {
+	case *chain.NeutrinoClient:
+		spendReport, err := backend.CS.GetUtxo(
+			neutrino.WatchOutPoints(wire.OutPoint{
+				Hash:  *txid,
+				Index: index,
+			}),
+			neutrino.StartBlock(&waddrmgr.BlockStamp{
+				Height: int32(heightHint),
+			}),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the spend report is nil, then the output was never
+		// created.
+		if spendReport == nil || spendReport.Output == nil {
+			return nil, fmt.Errorf("output %v:%v not found", txid,
+				index)
+		}
+
+		// If the spending transaction is populated, then the output
+		// has already been spent.
+		if spendReport.SpendingTx != nil {
+			return nil, ErrOutputSpent
+		}
+
+		return spendReport.Output, nil
+	case *chain.RPCClient:
+		txout, err = backend.GetTxOut(txid, index, false)
+	case *chain.BitcoindClient:
+		txout, err = backend.GetTxOut(txid, index, false)
+	default:
+		return nil, fmt.Errorf("unknown backend type: %T", backend)
+	}
 	if err != nil {
 		return nil, err
 	} else if txout == nil {
@@ -52,7 +101,23 @@ func (b *BtcWallet) GetUtxo(txid *chainhash.Hash, index uint32) (*wire.TxOut, er
 //
 // This method is a part of the lnwallet.BlockChainIO interface.
 func (b *BtcWallet) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
-	tx, err := b.rpc.GetRawTransaction(txid)
+	var (
+		tx  *btcutil.Tx
+		err error
+	)
+	switch backend := b.chain.(type) {
+	case *chain.RPCClient:
+		tx, err = backend.GetRawTransaction(txid)
+	case *chain.BitcoindClient:
+		tx, err = backend.GetRawTransaction(txid)
+	case *chain.NeutrinoClient:
+		// Light clients lack a transaction index, so are unable to
+		// look up arbitrary transactions.
+		return nil, fmt.Errorf("transaction lookup is not supported " +
+			"by the neutrino backend")
+	default:
+		return nil, fmt.Errorf("unknown backend type: %T", backend)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +129,7 @@ func (b *BtcWallet) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
 //
 // This method is a part of the lnwallet.BlockChainIO interface.
 func (b *BtcWallet) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
-	block, err := b.rpc.GetBlock(blockHash)
+	block, err := b.chain.GetBlock(blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +142,7 @@ func (b *BtcWallet) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error)
 //
 // This method is a part of the lnwallet.BlockChainIO interface.
 func (b *BtcWallet) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
-	blockHash, err := b.rpc.GetBlockHash(blockHeight)
+	blockHash, err := b.chain.GetBlockHash(blockHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package btcwallet
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -28,18 +29,24 @@ const (
 var (
 	lnNamespace = []byte("ln")
 	rootKey     = []byte("ln-root")
+
+	// errWatchOnly is returned when an operation requiring private keys
+	// is attempted by a watch-only wallet.
+	errWatchOnly = errors.New("private keys unavailable to watch-only " +
+		"wallet")
 )
 
 // BtcWallet is an implementation of the lnwallet.WalletController interface
 // backed by an active instance of btcwallet. At the time of the writing of
-// this documentation, this implementation requires a full btcd node to
-// operate.
+// this documentation, this implementation requires a full btcd or bitcoind
+// node to operate.
 type BtcWallet struct {
 	// wallet is an active instance of btcwallet.
 	wallet *base.Wallet
 
-	// rpc is an an active RPC connection to btcd full-node.
-	rpc *chain.RPCClient
+	// chain is the source of chain data for the wallet, backed by an
+	// active connection to either a btcd or bitcoind full-node.
+	chain chain.Interface
 
 	// lnNamespace is a namespace within btcwallet's walletdb used to store
 	// persistent state required by the WalletController interface but not
@@ -52,6 +59,22 @@ type BtcWallet struct {
 	// FetchInputInfo.
 	utxoCache map[wire.OutPoint]*wire.TxOut
 	cacheMtx  sync.RWMutex
+
+	// birthday is the time at which the seed of a restored wallet was
+	// created, and recoveryWindow the number of unused addresses watched
+	// while recovering its funds. A recoveryWindow of zero indicates
+	// that the wallet wasn't just restored, though a previously
+	// interrupted recovery may still be resumed.
+	birthday       time.Time
+	recoveryWindow uint32
+
+	// watchOnly is true if the wallet holds no private keys.
+	watchOnly bool
+
+	// lookaheadMtx serializes the derivation of addresses beyond those
+	// already known to the wallet while searching for an address to sign
+	// for.
+	lookaheadMtx sync.Mutex
 }
 
 // A compile time check to ensure that BtcWallet implements the
@@ -77,7 +100,10 @@ func New(cfg *Config) (*BtcWallet, error) {
 		return nil, err
 	}
 
-	var wallet *base.Wallet
+	var (
+		wallet         *base.Wallet
+		recoveryWindow uint32
+	)
 	if !walletExists {
 		// Wallet has never been created, perform initial set up.
 		wallet, err = loader.CreateNewWallet(pubPass, cfg.PrivatePass,
@@ -85,6 +111,10 @@ func New(cfg *Config) (*BtcWallet, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		// Only a freshly created wallet needs to be recovered, as an
+		// existing wallet has already found its funds.
+		recoveryWindow = cfg.RecoveryWindow
 	} else {
 		// Wallet has been created and been initialized at this point, open it
 		// along with all the required DB namepsaces, and the DB itself.
@@ -94,16 +124,32 @@ func New(cfg *Config) (*BtcWallet, error) {
 		}
 	}
 
-	if err := wallet.Manager.Unlock(cfg.PrivatePass); err != nil {
-		return nil, err
+	// A watch-only wallet is never unlocked, as it holds no private keys.
+	// If the wallet was created with, or previously held, private keys,
+	// then we'll discard them.
+	switch {
+	case cfg.WatchOnly && !wallet.Manager.WatchingOnly():
+		if err := wallet.Manager.ConvertToWatchingOnly(); err != nil {
+			return nil, err
+		}
+
+	case !cfg.WatchOnly:
+		if err := wallet.Manager.Unlock(cfg.PrivatePass); err != nil {
+			return nil, err
+		}
 	}
 
-	// Create a special websockets rpc client for btcd which will be used
-	// by the wallet for notifications, calls, etc.
-	rpcc, err := chain.NewRPCClient(cfg.NetParams, cfg.RPCHost,
-		cfg.RPCUser, cfg.RPCPass, cfg.CACert, false, 20)
-	if err != nil {
-		return nil, err
+	// If a chain source wasn't provided, then we'll create a special
+	// websockets rpc client for btcd which will be used by the wallet for
+	// notifications, calls, etc.
+	chainSource := cfg.ChainSource
+	if chainSource == nil {
+		rpcc, err := chain.NewRPCClient(cfg.NetParams, cfg.RPCHost,
+			cfg.RPCUser, cfg.RPCPass, cfg.CACert, false, 20)
+		if err != nil {
+			return nil, err
+		}
+		chainSource = rpcc
 	}
 
 	db := wallet.Database()
@@ -113,14 +159,24 @@ func New(cfg *Config) (*BtcWallet, error) {
 	}
 
 	return &BtcWallet{
-		wallet:      wallet,
-		rpc:         rpcc,
-		lnNamespace: walletNamespace,
-		netParams:   cfg.NetParams,
-		utxoCache:   make(map[wire.OutPoint]*wire.TxOut),
+		wallet:         wallet,
+		chain:          chainSource,
+		lnNamespace:    walletNamespace,
+		netParams:      cfg.NetParams,
+		utxoCache:      make(map[wire.OutPoint]*wire.TxOut),
+		birthday:       cfg.Birthday,
+		recoveryWindow: recoveryWindow,
+		watchOnly:      cfg.WatchOnly,
 	}, nil
 }
 
+// WalletExists returns true if the wallet described by the passed config has
+// already been created.
+func WalletExists(cfg *Config) (bool, error) {
+	netDir := networkDir(cfg.DataDir, cfg.NetParams)
+	return base.NewLoader(cfg.NetParams, netDir).WalletExists()
+}
+
 // Start initializes the underlying rpc connection, the wallet itself, and
 // begins syncing to the current available blockchain state.
 //
@@ -128,16 +184,23 @@ func New(cfg *Config) (*BtcWallet, error) {
 func (b *BtcWallet) Start() error {
 	// Establish an RPC connection in additino to starting the goroutines
 	// in the underlying wallet.
-	if err := b.rpc.Start(); err != nil {
+	if err := b.chain.Start(); err != nil {
+		return err
+	}
+
+	// If the wallet was restored from an existing seed, or a previous
+	// recovery was interrupted, then we'll scan the chain for its funds
+	// before it begins to sync.
+	if err := b.recoverFunds(); err != nil {
 		return err
 	}
 
 	// Start the underlying btcwallet core.
 	b.wallet.Start()
 
-	// Pass the rpc client into the wallet so it can sync up to the
+	// Pass the chain client into the wallet so it can sync up to the
 	// current main chain.
-	b.wallet.SynchronizeRPC(b.rpc)
+	b.wallet.SynchronizeRPC(b.chain)
 
 	return nil
 }
@@ -151,7 +214,8 @@ func (b *BtcWallet) Stop() error {
 
 	b.wallet.WaitForShutdown()
 
-	b.rpc.Shutdown()
+	b.chain.Stop()
+	b.chain.WaitForShutdown()
 
 	return nil
 }
@@ -254,6 +318,10 @@ func (b *BtcWallet) NewRawKey() (*btcec.PublicKey, error) {
 //
 // This is a part of the WalletController interface.
 func (b *BtcWallet) FetchRootKey() (*btcec.PrivateKey, error) {
+	if b.watchOnly {
+		return nil, errWatchOnly
+	}
+
 	// Fetch the root address hash from the database, this is persisted
 	// locally within the database, then used to obtain the key from the
 	// wallet based on the address hash.
@@ -309,6 +377,10 @@ func (b *BtcWallet) FetchRootKey() (*btcec.PrivateKey, error) {
 //
 // This is a part of the WalletController interface.
 func (b *BtcWallet) SendOutputs(outputs []*wire.TxOut) (*chainhash.Hash, error) {
+	if b.watchOnly {
+		return nil, errWatchOnly
+	}
+
 	return b.wallet.SendOutputs(outputs, defaultAccount, 1)
 }
 
@@ -366,6 +438,8 @@ func (b *BtcWallet) ListUnspentWitness(minConfs int32) ([]*lnwallet.Utxo, error)
 					Hash:  *txid,
 					Index: output.Vout,
 				},
+				PkScript:      pkScript,
+				Confirmations: output.Confirmations,
 			}
 			witnessOutputs = append(witnessOutputs, utxo)
 		}
@@ -620,9 +694,9 @@ func (b *BtcWallet) IsSynced() (bool, error) {
 	// Grab the best chain state the wallet is currently aware of.
 	syncState := b.wallet.Manager.SyncedTo()
 
-	// Next, query the btcd node to grab the info about the tip of the main
-	// chain.
-	bestHash, bestHeight, err := b.rpc.GetBestBlock()
+	// Next, query the backing node to grab the info about the tip of the
+	// main chain.
+	bestHash, bestHeight, err := b.chain.GetBestBlock()
 	if err != nil {
 		return false, err
 	}
@@ -634,10 +708,10 @@ func (b *BtcWallet) IsSynced() (bool, error) {
 	}
 
 	// If the wallet is on par with the current best chain tip, then we
-	// still may not yet be synced as the btcd node may still be catching
+	// still may not yet be synced as the backing node may still be catching
 	// up to the main chain. So we'll grab the block header in order to
 	// make a guess based on the current time stamp.
-	blockHeader, err := b.rpc.GetBlockHeader(bestHash)
+	blockHeader, err := b.chain.GetBlockHeader(bestHash)
 	if err != nil {
 		return false, err
 	}
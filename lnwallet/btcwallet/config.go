@@ -2,10 +2,12 @@ package btcwallet
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcwallet/chain"
 
 	// This is required to register bdb as a valid walletdb driver. In the
 	// init function of the package, it registers itself. The import is used
@@ -74,10 +76,36 @@ type Config struct {
 	// CACert is the raw RPC cert for btcd.
 	CACert []byte
 
+	// ChainSource is the source of chain data for the wallet, such as a
+	// chain.BitcoindClient connected to a bitcoind node. If nil, then a
+	// websockets client for the btcd node detailed by the RPC parameters
+	// above will be used.
+	ChainSource chain.Interface
+
 	PrivatePass []byte
 	PublicPass  []byte
 	HdSeed      []byte
 
+	// Birthday is the time at which the HdSeed was created. No
+	// transactions relevant to the wallet can have been confirmed before
+	// this time.
+	Birthday time.Time
+
+	// RecoveryWindow is the number of unused addresses beyond the last
+	// used address the wallet watches while recovering the funds of a
+	// wallet restored from an existing HdSeed. If non-zero, and the
+	// wallet doesn't yet exist, then when started the wallet will scan
+	// the chain from its Birthday onwards in order to recover its funds.
+	RecoveryWindow uint32
+
+	// WatchOnly indicates that the wallet should hold no private keys,
+	// such as when its signing is delegated to a remote signer. An
+	// existing wallet is converted to watch-only when opened, discarding
+	// its private keys. A wallet created with this flag set derives its
+	// addresses from the HdSeed, and then discards it, so it's able to
+	// watch the addresses of a signer restored from the same seed.
+	WatchOnly bool
+
 	NetParams *chaincfg.Params
 }
 
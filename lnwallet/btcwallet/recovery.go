@@ -0,0 +1,488 @@
+package btcwallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcwallet/waddrmgr"
+	"github.com/roasbeef/btcwallet/walletdb"
+	"github.com/roasbeef/btcwallet/wtxmgr"
+)
+
+const (
+	// birthdayMargin is the period before the wallet's birthday from which
+	// the chain is scanned while recovering its funds. Block timestamps
+	// are only loosely ordered, and the birthday is only precise to the
+	// day, so we scan from a little earlier to be safe.
+	birthdayMargin = 48 * time.Hour
+
+	// recoveryCheckpointInterval is the number of blocks scanned between
+	// each checkpoint of the recovery's progress.
+	recoveryCheckpointInterval = 1000
+)
+
+var (
+	// recoveryStateKey is the key within the lnNamespace under which the
+	// state of an in-progress recovery is persisted, allowing the
+	// recovery to resume if lnd is restarted before it completes.
+	recoveryStateKey = []byte("recovery-state")
+)
+
+// recoveredAddr is an address watched while recovering the wallet's funds.
+type recoveredAddr struct {
+	addr btcutil.Address
+
+	// internal is true if the address is on the wallet's internal (change)
+	// branch.
+	internal bool
+
+	// index is the position of the address on its branch, relative to the
+	// first address watched.
+	index uint32
+}
+
+// recoveryState tracks the addresses watched, and the outputs found, while
+// recovering the wallet's funds.
+type recoveryState struct {
+	// recoveryWindow is the number of unused addresses watched beyond the
+	// last used address on each branch.
+	recoveryWindow uint32
+
+	// nextHeight is the height of the next block to be scanned.
+	nextHeight int32
+
+	// addrs maps the output script of each watched address to the
+	// address.
+	addrs map[string]*recoveredAddr
+
+	// numAddrs is the number of addresses watched on the external (false)
+	// and internal (true) branches.
+	numAddrs map[bool]uint32
+
+	// outPoints is the set of outputs recovered so far. Transactions
+	// spending any of these outputs are also relevant to the wallet.
+	outPoints map[wire.OutPoint]struct{}
+}
+
+// newRecoveryState creates the state of a recovery which begins scanning the
+// chain at the passed height.
+func newRecoveryState(recoveryWindow uint32, startHeight int32) *recoveryState {
+	return &recoveryState{
+		recoveryWindow: recoveryWindow,
+		nextHeight:     startHeight,
+		addrs:          make(map[string]*recoveredAddr),
+		numAddrs:       make(map[bool]uint32),
+		outPoints:      make(map[wire.OutPoint]struct{}),
+	}
+}
+
+// recoverFunds recovers the funds of a wallet restored from an existing seed.
+// The chain is scanned from the wallet's birthday onwards for transactions
+// paying to, or spending from, the wallet's addresses. As the addresses used
+// by the wallet are unknown, we watch a window of addresses on each branch,
+// which is extended each time an address within it is found to be used.
+//
+// The progress of the recovery is persisted as it proceeds, so if lnd is
+// restarted before the recovery completes, it's resumed from the last
+// checkpoint.
+//
+// NOTE: Only native witness addresses are recovered, as these are the
+// addresses handed out by default.
+func (b *BtcWallet) recoverFunds() error {
+	state, err := b.fetchRecoveryState()
+	if err != nil {
+		return err
+	}
+
+	// If there isn't a recovery in progress, and the wallet wasn't just
+	// restored, then there's nothing to recover.
+	if state == nil && b.recoveryWindow == 0 {
+		return nil
+	}
+
+	bestHash, bestHeight, err := b.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		startHeight, err := b.birthdayHeight(bestHeight)
+		if err != nil {
+			return err
+		}
+
+		state = newRecoveryState(b.recoveryWindow, startHeight)
+		for _, internal := range []bool{false, true} {
+			err := b.watchAddrs(state, internal, state.recoveryWindow)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for height := state.nextHeight; ; height++ {
+		// New blocks may have been mined while we were scanning, so
+		// once we reach the prior best block, we'll check whether
+		// there are any more blocks to scan.
+		if height > bestHeight {
+			bestHash, bestHeight, err = b.GetBestBlock()
+			if err != nil {
+				return err
+			}
+			if height > bestHeight {
+				break
+			}
+		}
+
+		blockHash, err := b.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		block, err := b.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+
+		blockMeta := &wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   *blockHash,
+				Height: height,
+			},
+			Time: block.Header.Timestamp,
+		}
+		for _, tx := range block.Transactions {
+			if err := b.recoverTx(state, tx, blockMeta); err != nil {
+				return err
+			}
+		}
+
+		state.nextHeight = height + 1
+		if state.nextHeight%recoveryCheckpointInterval == 0 {
+			if err := b.putRecoveryState(state); err != nil {
+				return err
+			}
+		}
+	}
+
+	// With the scan complete, we'll mark the wallet as synced up to the
+	// last block scanned, so it'll resume syncing from there.
+	err = b.wallet.Manager.SetSyncedTo(&waddrmgr.BlockStamp{
+		Hash:   *bestHash,
+		Height: bestHeight,
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.deleteRecoveryState()
+}
+
+// birthdayHeight returns the height of the first block within the main chain
+// with a timestamp after the wallet's birthday, less the birthdayMargin.
+func (b *BtcWallet) birthdayHeight(bestHeight int32) (int32, error) {
+	birthday := b.birthday.Add(-birthdayMargin)
+
+	low, high := int32(0), bestHeight
+	for low < high {
+		mid := low + (high-low)/2
+
+		blockHash, err := b.GetBlockHash(int64(mid))
+		if err != nil {
+			return 0, err
+		}
+		block, err := b.GetBlock(blockHash)
+		if err != nil {
+			return 0, err
+		}
+
+		if block.Header.Timestamp.Before(birthday) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	return low, nil
+}
+
+// watchAddrs derives the next n addresses on the external or internal branch
+// of the wallet, and adds them to the set of watched addresses.
+func (b *BtcWallet) watchAddrs(state *recoveryState, internal bool,
+	n uint32) error {
+
+	var (
+		addrs []waddrmgr.ManagedAddress
+		err   error
+	)
+	if internal {
+		addrs, err = b.wallet.Manager.NextInternalAddresses(
+			defaultAccount, n, waddrmgr.WitnessPubKey)
+	} else {
+		addrs, err = b.wallet.Manager.NextExternalAddresses(
+			defaultAccount, n, waddrmgr.WitnessPubKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		pkScript, err := txscript.PayToAddrScript(addr.Address())
+		if err != nil {
+			return err
+		}
+
+		state.addrs[string(pkScript)] = &recoveredAddr{
+			addr:     addr.Address(),
+			internal: internal,
+			index:    state.numAddrs[internal],
+		}
+		state.numAddrs[internal]++
+	}
+
+	// The addresses derived are persisted by the address manager, so
+	// we'll persist the recovery's state along with them, ensuring that
+	// they'll continue to be watched if the recovery is resumed.
+	return b.putRecoveryState(state)
+}
+
+// recoverTx adds the passed transaction to the wallet's transaction store if
+// it pays to any of the watched addresses, or spends any of the outputs
+// recovered so far.
+func (b *BtcWallet) recoverTx(state *recoveryState, tx *wire.MsgTx,
+	blockMeta *wtxmgr.BlockMeta) error {
+
+	var (
+		credits []uint32
+		spends  bool
+	)
+	for i, txOut := range tx.TxOut {
+		addr, ok := state.addrs[string(txOut.PkScript)]
+		if !ok {
+			continue
+		}
+		credits = append(credits, uint32(i))
+
+		if err := b.wallet.Manager.MarkUsed(addr.addr); err != nil {
+			return err
+		}
+
+		// If this address is within the recovery window of the last
+		// address watched on its branch, then we'll extend the window
+		// beyond it.
+		horizon := addr.index + 1 + state.recoveryWindow
+		if numAddrs := state.numAddrs[addr.internal]; horizon > numAddrs {
+			err := b.watchAddrs(state, addr.internal, horizon-numAddrs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, txIn := range tx.TxIn {
+		if _, ok := state.outPoints[txIn.PreviousOutPoint]; ok {
+			delete(state.outPoints, txIn.PreviousOutPoint)
+			spends = true
+		}
+	}
+
+	if len(credits) == 0 && !spends {
+		return nil
+	}
+
+	// The transaction store will mark any of our outputs spent by the
+	// transaction as such when it's inserted.
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, blockMeta.Time)
+	if err != nil {
+		return err
+	}
+	if err := b.wallet.TxStore.InsertTx(rec, blockMeta); err != nil {
+		return err
+	}
+
+	txHash := tx.TxHash()
+	for _, index := range credits {
+		addr := state.addrs[string(tx.TxOut[index].PkScript)]
+		err := b.wallet.TxStore.AddCredit(rec, blockMeta, index,
+			addr.internal)
+		if err != nil {
+			return err
+		}
+
+		state.outPoints[wire.OutPoint{Hash: txHash, Index: index}] =
+			struct{}{}
+	}
+
+	return nil
+}
+
+// fetchRecoveryState returns the persisted state of an in-progress recovery,
+// or nil if there isn't one.
+func (b *BtcWallet) fetchRecoveryState() (*recoveryState, error) {
+	var state *recoveryState
+	err := b.lnNamespace.View(func(tx walletdb.Tx) error {
+		stateBytes := tx.RootBucket().Get(recoveryStateKey)
+		if stateBytes == nil {
+			return nil
+		}
+
+		var err error
+		state, err = deserializeRecoveryState(
+			bytes.NewReader(stateBytes), b.netParams,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// putRecoveryState persists the state of an in-progress recovery.
+func (b *BtcWallet) putRecoveryState(state *recoveryState) error {
+	var buf bytes.Buffer
+	if err := serializeRecoveryState(&buf, state); err != nil {
+		return err
+	}
+
+	return b.lnNamespace.Update(func(tx walletdb.Tx) error {
+		return tx.RootBucket().Put(recoveryStateKey, buf.Bytes())
+	})
+}
+
+// deleteRecoveryState deletes the persisted state of a completed recovery.
+func (b *BtcWallet) deleteRecoveryState() error {
+	return b.lnNamespace.Update(func(tx walletdb.Tx) error {
+		return tx.RootBucket().Delete(recoveryStateKey)
+	})
+}
+
+// serializeRecoveryState writes the passed recoveryState to the passed
+// io.Writer.
+func serializeRecoveryState(w io.Writer, state *recoveryState) error {
+	byteOrder := binary.BigEndian
+
+	var scratch [8]byte
+	byteOrder.PutUint32(scratch[:4], state.recoveryWindow)
+	byteOrder.PutUint32(scratch[4:], uint32(state.nextHeight))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint32(scratch[:4], uint32(len(state.addrs)))
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+	for _, addr := range state.addrs {
+		var internal byte
+		if addr.internal {
+			internal = 1
+		}
+		if _, err := w.Write([]byte{internal}); err != nil {
+			return err
+		}
+
+		byteOrder.PutUint32(scratch[:4], addr.index)
+		if _, err := w.Write(scratch[:4]); err != nil {
+			return err
+		}
+
+		err := wire.WriteVarString(w, 0, addr.addr.EncodeAddress())
+		if err != nil {
+			return err
+		}
+	}
+
+	byteOrder.PutUint32(scratch[:4], uint32(len(state.outPoints)))
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+	for outPoint := range state.outPoints {
+		if _, err := w.Write(outPoint.Hash[:]); err != nil {
+			return err
+		}
+
+		byteOrder.PutUint32(scratch[:4], outPoint.Index)
+		if _, err := w.Write(scratch[:4]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeRecoveryState reads a recoveryState from the passed io.Reader.
+// The number of addresses watched on each branch is recovered from the
+// addresses themselves.
+func deserializeRecoveryState(r io.Reader,
+	netParams *chaincfg.Params) (*recoveryState, error) {
+
+	byteOrder := binary.BigEndian
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	state := newRecoveryState(
+		byteOrder.Uint32(scratch[:4]),
+		int32(byteOrder.Uint32(scratch[4:])),
+	)
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	numAddrs := byteOrder.Uint32(scratch[:4])
+	for i := uint32(0); i < numAddrs; i++ {
+		if _, err := io.ReadFull(r, scratch[:5]); err != nil {
+			return nil, err
+		}
+		internal := scratch[0] == 1
+		index := byteOrder.Uint32(scratch[1:5])
+
+		encodedAddr, err := wire.ReadVarString(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := btcutil.DecodeAddress(encodedAddr, netParams)
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		state.addrs[string(pkScript)] = &recoveredAddr{
+			addr:     addr,
+			internal: internal,
+			index:    index,
+		}
+		if index+1 > state.numAddrs[internal] {
+			state.numAddrs[internal] = index + 1
+		}
+	}
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	numOutPoints := byteOrder.Uint32(scratch[:4])
+	for i := uint32(0); i < numOutPoints; i++ {
+		var outPoint wire.OutPoint
+		if _, err := io.ReadFull(r, outPoint.Hash[:]); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+			return nil, err
+		}
+		outPoint.Index = byteOrder.Uint32(scratch[:4])
+
+		state.outPoints[outPoint] = struct{}{}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,117 @@
+package btcwallet
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestRecoveryStateSerialization tests that the state of an in-progress
+// recovery survives a round trip through its serialization, including the
+// number of addresses watched on each branch.
+func TestRecoveryStateSerialization(t *testing.T) {
+	t.Parallel()
+
+	netParams := &chaincfg.RegressionNetParams
+
+	state := newRecoveryState(20, 1234)
+	for i := 0; i < 5; i++ {
+		internal := i%2 == 1
+
+		var pubKeyHash [20]byte
+		pubKeyHash[0] = byte(i)
+		addr, err := btcutil.NewAddressWitnessPubKeyHash(
+			pubKeyHash[:], netParams,
+		)
+		if err != nil {
+			t.Fatalf("unable to create address: %v", err)
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatalf("unable to create pkScript: %v", err)
+		}
+
+		state.addrs[string(pkScript)] = &recoveredAddr{
+			addr:     addr,
+			internal: internal,
+			index:    state.numAddrs[internal],
+		}
+		state.numAddrs[internal]++
+	}
+	for i := uint32(0); i < 3; i++ {
+		outPoint := wire.OutPoint{Hash: chainhash.Hash{byte(i)}, Index: i}
+		state.outPoints[outPoint] = struct{}{}
+	}
+
+	var b bytes.Buffer
+	if err := serializeRecoveryState(&b, state); err != nil {
+		t.Fatalf("unable to serialize recovery state: %v", err)
+	}
+	restored, err := deserializeRecoveryState(&b, netParams)
+	if err != nil {
+		t.Fatalf("unable to deserialize recovery state: %v", err)
+	}
+
+	if restored.recoveryWindow != state.recoveryWindow {
+		t.Fatalf("expected recovery window of %v, got %v",
+			state.recoveryWindow, restored.recoveryWindow)
+	}
+	if restored.nextHeight != state.nextHeight {
+		t.Fatalf("expected next height of %v, got %v",
+			state.nextHeight, restored.nextHeight)
+	}
+	if !reflect.DeepEqual(restored.numAddrs, state.numAddrs) {
+		t.Fatalf("expected address counts of %v, got %v",
+			state.numAddrs, restored.numAddrs)
+	}
+	if !reflect.DeepEqual(restored.outPoints, state.outPoints) {
+		t.Fatalf("expected outpoints %v, got %v", state.outPoints,
+			restored.outPoints)
+	}
+
+	if len(restored.addrs) != len(state.addrs) {
+		t.Fatalf("expected %v addresses, got %v", len(state.addrs),
+			len(restored.addrs))
+	}
+	for pkScript, addr := range state.addrs {
+		restoredAddr, ok := restored.addrs[pkScript]
+		if !ok {
+			t.Fatalf("address %v not restored", addr.addr)
+		}
+		if restoredAddr.addr.EncodeAddress() != addr.addr.EncodeAddress() ||
+			restoredAddr.internal != addr.internal ||
+			restoredAddr.index != addr.index {
+
+			t.Fatalf("expected address %+v, got %+v", addr,
+				restoredAddr)
+		}
+	}
+}
+
+// TestRecoveryStateDeserializeTruncated tests that a truncated recovery state
+// is rejected.
+func TestRecoveryStateDeserializeTruncated(t *testing.T) {
+	t.Parallel()
+
+	netParams := &chaincfg.RegressionNetParams
+
+	state := newRecoveryState(20, 1234)
+	state.outPoints[wire.OutPoint{Index: 1}] = struct{}{}
+
+	var b bytes.Buffer
+	if err := serializeRecoveryState(&b, state); err != nil {
+		t.Fatalf("unable to serialize recovery state: %v", err)
+	}
+
+	truncated := b.Bytes()[:b.Len()-1]
+	_, err := deserializeRecoveryState(bytes.NewReader(truncated), netParams)
+	if err == nil {
+		t.Fatalf("expected truncated recovery state to be rejected")
+	}
+}
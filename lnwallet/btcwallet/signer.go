@@ -13,6 +13,11 @@ import (
 	"github.com/roasbeef/btcwallet/waddrmgr"
 )
 
+// signerLookahead is the number of addresses beyond the last derived on each
+// branch which are searched for an unknown address the wallet is asked to
+// sign for.
+const signerLookahead = 100
+
 // FetchInputInfo queries for the WalletController's knowledge of the passed
 // outpoint. If the base wallet determines this output is under its control,
 // then the original txout should be returned. Otherwise, a non-nil error value
@@ -64,7 +69,7 @@ func (b *BtcWallet) fetchOutputAddr(script []byte) (waddrmgr.ManagedAddress, err
 	// Therefore, we simply select the key for the first address we know
 	// of.
 	for _, addr := range addrs {
-		wAddr, err := b.wallet.Manager.Address(addr)
+		wAddr, err := b.lookupAddr(addr)
 		if err == nil {
 			return wAddr, nil
 		}
@@ -85,7 +90,7 @@ func (b *BtcWallet) fetchPrivKey(pub *btcec.PublicKey) (*btcec.PrivateKey, error
 		return nil, err
 	}
 
-	walletddr, err := b.wallet.Manager.Address(addr)
+	walletddr, err := b.lookupAddr(addr)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +98,56 @@ func (b *BtcWallet) fetchPrivKey(pub *btcec.PublicKey) (*btcec.PrivateKey, error
 	return walletddr.(waddrmgr.ManagedPubKeyAddress).PrivKey()
 }
 
+// lookupAddr returns the managed address of the passed address. If the
+// address isn't yet known to the wallet, then up to signerLookahead addresses
+// beyond the last derived on each branch are derived in search of it. This
+// allows the wallet to act as the remote signer of a watch-only wallet
+// restored from the same seed, whose addresses may have been derived beyond
+// those of the signer.
+func (b *BtcWallet) lookupAddr(
+	addr btcutil.Address) (waddrmgr.ManagedAddress, error) {
+
+	walletAddr, err := b.wallet.Manager.Address(addr)
+	if err == nil || b.watchOnly {
+		return walletAddr, err
+	}
+
+	// Only p2wkh addresses are derived by the watch-only wallet, so we
+	// won't search for any other type of address.
+	if _, ok := addr.(*btcutil.AddressWitnessPubKeyHash); !ok {
+		return nil, err
+	}
+
+	b.lookaheadMtx.Lock()
+	defer b.lookaheadMtx.Unlock()
+
+	// Another lookup may have derived the address while we were waiting.
+	if walletAddr, err := b.wallet.Manager.Address(addr); err == nil {
+		return walletAddr, nil
+	}
+
+	for _, internal := range []bool{false, true} {
+		nextAddrs := b.wallet.Manager.NextExternalAddresses
+		if internal {
+			nextAddrs = b.wallet.Manager.NextInternalAddresses
+		}
+
+		addrs, deriveErr := nextAddrs(defaultAccount, signerLookahead,
+			waddrmgr.WitnessPubKey)
+		if deriveErr != nil {
+			return nil, deriveErr
+		}
+
+		for _, derivedAddr := range addrs {
+			if derivedAddr.Address().String() == addr.String() {
+				return derivedAddr, nil
+			}
+		}
+	}
+
+	return nil, err
+}
+
 // SignOutputRaw generates a signature for the passed transaction according to
 // the data within the passed SignDescriptor.
 //
@@ -44,6 +44,17 @@ var (
 	// maximum number of allowed HTLC's if committed in a state transition
 	ErrMaxHTLCNumber = fmt.Errorf("commitment transaction exceed max " +
 		"htlc number")
+
+	// ErrMaxPendingAmount is returned when a proposed HTLC would exceed
+	// the total value of outstanding HTLCs the offering party is allowed
+	// to have within the channel.
+	ErrMaxPendingAmount = fmt.Errorf("commitment transaction exceed max " +
+		"pending amount")
+
+	// ErrBelowChanReserve is returned when a proposed HTLC would cause the
+	// offering party's balance to dip below their channel reserve.
+	ErrBelowChanReserve = fmt.Errorf("commitment transaction dips peer " +
+		"below chan reserve")
 )
 
 const (
@@ -770,7 +781,8 @@ func NewLightningChannel(signer Signer, events chainntnfs.ChainNotifier,
 		// the remote party has broadcasted a commitment transaction
 		// on-chain.
 		fundingOut := &lc.fundingTxIn.PreviousOutPoint
-		channelCloseNtfn, err := lc.channelEvents.RegisterSpendNtfn(fundingOut)
+		channelCloseNtfn, err := lc.channelEvents.RegisterSpendNtfn(fundingOut,
+			lc.channelState.FundingBroadcastHeight)
 		if err != nil {
 			return nil, err
 		}
@@ -1526,7 +1538,7 @@ func (lc *LightningChannel) SignNextCommitment() ([]byte, error) {
 	// party set up when we initially set up the channel. If we are, then
 	// we'll abort this state transition.
 	err := lc.validateCommitmentSanity(lc.remoteUpdateLog.ackedIndex,
-		lc.localUpdateLog.logIndex, false)
+		lc.localUpdateLog.logIndex, true, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1593,40 +1605,172 @@ func (lc *LightningChannel) SignNextCommitment() ([]byte, error) {
 // validateCommitmentSanity is used to validate that on current state the commitment
 // transaction is valid in terms of propagating it over Bitcoin network, and
 // also that all outputs are meet Bitcoin spec requirements and they are
-// spendable.
+// spendable. Additionally, the HTLCs offered by each party within the view
+// are checked against the constraints the opposite party imposed during
+// funding: the number of outstanding HTLCs, their total value, and the
+// channel reserve. The predicted adds, if non-nil, are HTLCs which are about
+// to be added to our, or their update log respectively and are evaluated as
+// if they were already present.
+//
+// NOTE: Unlike evaluateHTLCView, this method doesn't mutate any of the log
+// entries or the channel state.
 func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
-	ourLogCounter uint64, prediction bool) error {
+	ourLogCounter uint64, remoteChain bool, ourPredictedAdd,
+	theirPredictedAdd *PaymentDescriptor) error {
 
-	htlcCount := 0
+	var commitChain *commitmentChain
+	if remoteChain {
+		commitChain = lc.remoteCommitChain
+	} else {
+		commitChain = lc.localCommitChain
+	}
 
-	if prediction {
-		htlcCount++
+	// We'll start from the balances at the tip of the target chain, then
+	// apply all the updates that haven't yet been committed to it.
+	var ourBalance, theirBalance btcutil.Amount
+	if commitChain.tip() == nil {
+		ourBalance = lc.channelState.OurBalance
+		theirBalance = lc.channelState.TheirBalance
+	} else {
+		ourBalance = commitChain.tip().ourBalance
+		theirBalance = commitChain.tip().theirBalance
 	}
 
-	// Run through all the HTLCs that will be covered by this transaction
-	// in order to calculate theirs count.
 	htlcView := lc.fetchHTLCView(theirLogCounter, ourLogCounter)
+	if ourPredictedAdd != nil {
+		htlcView.ourUpdates = append(htlcView.ourUpdates, ourPredictedAdd)
+	}
+	if theirPredictedAdd != nil {
+		htlcView.theirUpdates = append(htlcView.theirUpdates,
+			theirPredictedAdd)
+	}
+
+	// isProcessed returns true if the entry has already been applied to
+	// the target chain, in which case its effect on the balances is
+	// already reflected at the tip.
+	isProcessed := func(entry *PaymentDescriptor) bool {
+		if entry.EntryType == Add {
+			if remoteChain {
+				return entry.addCommitHeightRemote != 0
+			}
+			return entry.addCommitHeightLocal != 0
+		}
+
+		if remoteChain {
+			return entry.removeCommitHeightRemote != 0
+		}
+		return entry.removeCommitHeightLocal != 0
+	}
 
+	// First, we'll run through the removal entries in both logs in order
+	// to determine which HTLCs are no longer active within this view,
+	// crediting the balances for any removals not yet processed.
+	skipUs := make(map[uint64]struct{})
+	skipThem := make(map[uint64]struct{})
 	for _, entry := range htlcView.ourUpdates {
 		if entry.EntryType == Add {
-			htlcCount++
+			continue
+		}
+
+		skipThem[entry.ParentIndex] = struct{}{}
+		if isProcessed(entry) {
+			continue
+		}
+
+		if entry.EntryType == Settle {
+			ourBalance += entry.Amount
 		} else {
-			htlcCount--
+			theirBalance += entry.Amount
 		}
 	}
-
 	for _, entry := range htlcView.theirUpdates {
 		if entry.EntryType == Add {
-			htlcCount++
+			continue
+		}
+
+		skipUs[entry.ParentIndex] = struct{}{}
+		if isProcessed(entry) {
+			continue
+		}
+
+		if entry.EntryType == Settle {
+			theirBalance += entry.Amount
 		} else {
-			htlcCount--
+			ourBalance += entry.Amount
 		}
 	}
 
-	if htlcCount > MaxHTLCNumber {
+	// Next, we'll tally up the active HTLCs offered by each side, debiting
+	// the balances for any additions not yet processed.
+	var (
+		ourHtlcs, theirHtlcs     int
+		ourPending, theirPending btcutil.Amount
+		ourNewAdds, theirNewAdds bool
+	)
+	for _, entry := range htlcView.ourUpdates {
+		if _, ok := skipUs[entry.Index]; entry.EntryType != Add || ok {
+			continue
+		}
+
+		ourHtlcs++
+		ourPending += entry.Amount
+		if !isProcessed(entry) {
+			ourBalance -= entry.Amount
+			ourNewAdds = true
+		}
+	}
+	for _, entry := range htlcView.theirUpdates {
+		if _, ok := skipThem[entry.Index]; entry.EntryType != Add || ok {
+			continue
+		}
+
+		theirHtlcs++
+		theirPending += entry.Amount
+		if !isProcessed(entry) {
+			theirBalance -= entry.Amount
+			theirNewAdds = true
+		}
+	}
+
+	if ourHtlcs+theirHtlcs > MaxHTLCNumber {
 		return ErrMaxHTLCNumber
 	}
 
+	// The HTLCs offered by the remote party are bound by the constraints
+	// we imposed upon them, while our own HTLCs are bound by the
+	// constraints they imposed upon us.
+	err := validateConstraints(&lc.channelState.OurConstraints,
+		theirHtlcs, theirPending, theirBalance, theirNewAdds)
+	if err != nil {
+		return err
+	}
+	return validateConstraints(&lc.channelState.TheirConstraints,
+		ourHtlcs, ourPending, ourBalance, ourNewAdds)
+}
+
+// validateConstraints checks the HTLCs offered by a party against the
+// constraints imposed upon it. A zero valued constraint wasn't negotiated,
+// and as a result isn't enforced. The channel reserve is only enforced if
+// the party is adding new HTLCs, as a party's balance may legitimately be
+// below the reserve, for example directly after funding.
+func validateConstraints(constraints *channeldb.ChannelConstraints,
+	numHtlcs int, pendingAmt, balance btcutil.Amount, newAdds bool) error {
+
+	if constraints.MaxAcceptedHtlcs != 0 &&
+		numHtlcs > int(constraints.MaxAcceptedHtlcs) {
+		return ErrMaxHTLCNumber
+	}
+
+	if constraints.MaxPendingAmount != 0 &&
+		pendingAmt > constraints.MaxPendingAmount {
+		return ErrMaxPendingAmount
+	}
+
+	if constraints.ChanReserve != 0 && newAdds &&
+		balance < constraints.ChanReserve {
+		return ErrBelowChanReserve
+	}
+
 	return nil
 }
 
@@ -1646,7 +1790,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(rawSig []byte) error {
 	// the constraints we specified during initial channel setup. If not,
 	// then we'll abort the channel as they've violated our constraints.
 	err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
-		lc.localUpdateLog.ackedIndex, false)
+		lc.localUpdateLog.ackedIndex, false, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -2002,11 +2146,6 @@ func (lc *LightningChannel) AddHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, error)
 	lc.Lock()
 	defer lc.Unlock()
 
-	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
-		lc.localUpdateLog.logIndex, true); err != nil {
-		return 0, err
-	}
-
 	pd := &PaymentDescriptor{
 		EntryType:    Add,
 		RHash:        PaymentHash(htlc.PaymentHash),
@@ -2017,6 +2156,13 @@ func (lc *LightningChannel) AddHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, error)
 		isDustRemote: htlc.Amount < lc.channelState.TheirDustLimit,
 	}
 
+	// Ensure that adding this HTLC to the remote party's next commitment
+	// doesn't violate any of the constraints they imposed upon us.
+	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
+		lc.localUpdateLog.logIndex, true, pd, nil); err != nil {
+		return 0, err
+	}
+
 	lc.localUpdateLog.appendUpdate(pd)
 
 	return pd.Index, nil
@@ -2029,11 +2175,6 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 	lc.Lock()
 	defer lc.Unlock()
 
-	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
-		lc.localUpdateLog.logIndex, true); err != nil {
-		return 0, err
-	}
-
 	pd := &PaymentDescriptor{
 		EntryType:    Add,
 		RHash:        PaymentHash(htlc.PaymentHash),
@@ -2044,6 +2185,13 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 		isDustRemote: htlc.Amount < lc.channelState.TheirDustLimit,
 	}
 
+	// Ensure that the remote party adding this HTLC to our next commitment
+	// doesn't violate any of the constraints we imposed upon them.
+	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
+		lc.localUpdateLog.logIndex, false, nil, pd); err != nil {
+		return 0, err
+	}
+
 	lc.remoteUpdateLog.appendUpdate(pd)
 
 	lc.rHashMap[pd.RHash] = append(lc.rHashMap[pd.RHash], pd)
@@ -2271,7 +2419,7 @@ func (lc *LightningChannel) addHTLC(commitTx *wire.MsgTx, ourCommit bool,
 // locked-down to initiate a force closure by broadcasting the latest state
 // on-chain. The summary includes all the information required to claim all
 // rightfully owned outputs.
-// TODO(roasbeef): generalize, add HTLC info, etc.
+// TODO(roasbeef): generalize, add incoming HTLC info, etc.
 type ForceCloseSummary struct {
 	// CloseTx is the transaction which closed the channel on-chain. If we
 	// initiate the force close, then this'll be our latest commitment
@@ -2290,6 +2438,36 @@ type ForceCloseSummary struct {
 	// SelfOutputSignDesc is a fully populated sign descriptor capable of
 	// generating a valid signature to sweep the self output.
 	SelfOutputSignDesc *SignDescriptor
+
+	// HtlcResolutions contains the information required to sweep each of
+	// our outgoing HTLCs from the close tx once they've timed out. HTLCs
+	// below the dust limit don't have an output, so they're omitted.
+	HtlcResolutions []OutgoingHtlcResolution
+}
+
+// OutgoingHtlcResolution houses the information necessary to sweep an outgoing
+// HTLC within our commitment transaction back into the wallet once it has
+// timed out. Until the HTLC has timed out, the remote party is still able to
+// claim the output by revealing the payment preimage on-chain.
+type OutgoingHtlcResolution struct {
+	// Expiry is the absolute timeout of the HTLC. The output can't be
+	// swept by us until the chain has reached this height.
+	Expiry uint32
+
+	// MaturityDelay is the relative delay, in blocks, after the
+	// confirmation of the close tx before the output can be swept.
+	MaturityDelay uint32
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// ClaimOutpoint is the outpoint of the HTLC output within the close
+	// tx.
+	ClaimOutpoint wire.OutPoint
+
+	// SweepSignDesc is a sign descriptor capable of generating a valid
+	// signature to sweep the HTLC output via the timeout clause.
+	SweepSignDesc SignDescriptor
 }
 
 // getSignedCommitTx function take the latest commitment transaction and populate
@@ -2401,6 +2579,15 @@ func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
 		}
 	}
 
+	// Next, gather the information required to sweep each of our outgoing
+	// HTLCs once they've timed out.
+	revocationHash := sha256.Sum256(unusedRevocation[:])
+	htlcResolutions, err := lc.outgoingHtlcResolutions(commitTx,
+		revocationHash)
+	if err != nil {
+		return nil, err
+	}
+
 	// Finally, close the channel force close signal which notifies any
 	// subscribers that the channel has now been forcibly closed. This
 	// allows callers to begin to carry out any post channel closure
@@ -2415,9 +2602,66 @@ func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
 		},
 		SelfOutputMaturity: csvTimeout,
 		SelfOutputSignDesc: selfSignDesc,
+		HtlcResolutions:    htlcResolutions,
 	}, nil
 }
 
+// outgoingHtlcResolutions returns the information required to sweep each of
+// the outgoing HTLCs within our current commitment transaction once they've
+// timed out. The passed revocation hash MUST be the one used to construct the
+// commitment transaction.
+func (lc *LightningChannel) outgoingHtlcResolutions(commitTx *wire.MsgTx,
+	revocationHash [32]byte) ([]OutgoingHtlcResolution, error) {
+
+	csvTimeout := lc.channelState.LocalCsvDelay
+	localKey := lc.channelState.OurCommitKey
+	remoteKey := lc.channelState.TheirCommitKey
+	commitHash := commitTx.TxHash()
+
+	var resolutions []OutgoingHtlcResolution
+	for _, htlc := range lc.channelState.Htlcs {
+		// Incoming HTLCs can only be swept with the preimage, and dust
+		// HTLCs don't have an output within the commitment transaction
+		// at all, so we'll skip both.
+		if htlc.Incoming || htlc.OutputIndex == maxUint16 {
+			continue
+		}
+		if int(htlc.OutputIndex) >= len(commitTx.TxOut) {
+			return nil, fmt.Errorf("htlc output index %v out of "+
+				"range", htlc.OutputIndex)
+		}
+
+		htlcScript, err := senderHTLCScript(htlc.RefundTimeout,
+			csvTimeout, localKey, remoteKey, revocationHash[:],
+			htlc.RHash[:])
+		if err != nil {
+			return nil, err
+		}
+
+		htlcOutput := commitTx.TxOut[htlc.OutputIndex]
+		resolutions = append(resolutions, OutgoingHtlcResolution{
+			Expiry:        htlc.RefundTimeout,
+			MaturityDelay: csvTimeout,
+			PaymentHash:   htlc.RHash,
+			ClaimOutpoint: wire.OutPoint{
+				Hash:  commitHash,
+				Index: uint32(htlc.OutputIndex),
+			},
+			SweepSignDesc: SignDescriptor{
+				PubKey:        localKey,
+				WitnessScript: htlcScript,
+				Output: &wire.TxOut{
+					PkScript: htlcOutput.PkScript,
+					Value:    htlcOutput.Value,
+				},
+				HashType: txscript.SigHashAll,
+			},
+		})
+	}
+
+	return resolutions, nil
+}
+
 // InitCooperativeClose initiates a cooperative closure of an active lightning
 // channel. This method should only be executed once all pending HTLCs (if any)
 // on the channel have been cleared/removed. Upon completion, the source
@@ -2428,11 +2672,15 @@ func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
 // confirmation of the closing transaction before considering the channel
 // terminated. In the case of an unresponsive remote party, the initiator can
 // either choose to execute a force closure, or backoff for a period of time,
-// and retry the cooperative closure.
+// and retry the cooperative closure. The proposed fee is the absolute fee the
+// closing transaction will pay, and must be sent to the remote party along
+// with our signature.
 //
 // TODO(roasbeef): caller should initiate signal to reject all incoming HTLCs,
 // settle any inflight.
-func (lc *LightningChannel) InitCooperativeClose() ([]byte, *chainhash.Hash, error) {
+func (lc *LightningChannel) InitCooperativeClose(
+	proposedFee btcutil.Amount) ([]byte, *chainhash.Hash, error) {
+
 	lc.Lock()
 	defer lc.Unlock()
 
@@ -2446,7 +2694,7 @@ func (lc *LightningChannel) InitCooperativeClose() ([]byte, *chainhash.Hash, err
 		lc.channelState.OurDustLimit, lc.channelState.TheirDustLimit,
 		lc.channelState.OurBalance, lc.channelState.TheirBalance,
 		lc.channelState.OurDeliveryScript, lc.channelState.TheirDeliveryScript,
-		lc.channelState.IsInitiator)
+		proposedFee, lc.channelState.IsInitiator)
 
 	// Ensure that the transaction doesn't explicitly violate any
 	// consensus rules such as being too big, or having any value with a
@@ -2481,8 +2729,11 @@ func (lc *LightningChannel) InitCooperativeClose() ([]byte, *chainhash.Hash, err
 // a signed+valid closure transaction to the network.
 //
 // NOTE: The passed remote sig is expected to be a fully complete signature
-// including the proper sighash byte.
-func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte) (*wire.MsgTx, error) {
+// including the proper sighash byte, and the proposed fee must be the fee
+// proposed by the remote node alongside it.
+func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte,
+	proposedFee btcutil.Amount) (*wire.MsgTx, error) {
+
 	lc.Lock()
 	defer lc.Unlock()
 
@@ -2499,7 +2750,7 @@ func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte) (*wire.Ms
 		lc.channelState.OurDustLimit, lc.channelState.TheirDustLimit,
 		lc.channelState.OurBalance, lc.channelState.TheirBalance,
 		lc.channelState.OurDeliveryScript, lc.channelState.TheirDeliveryScript,
-		lc.channelState.IsInitiator)
+		proposedFee, lc.channelState.IsInitiator)
 
 	// Ensure that the transaction doesn't explicitly validate any
 	// consensus rules such as being too big, or having any value with a
@@ -2621,11 +2872,11 @@ func CreateCommitTx(fundingOutput *wire.TxIn, selfKey, theirKey *btcec.PublicKey
 // parties, then broadcast cooperatively closes an active channel. The creation
 // of the closure transaction is modified by a boolean indicating if the party
 // constructing the channel is the initiator of the closure. Currently it is
-// expected that the initiator pays the transaction fees for the closing
-// transaction in full.
+// expected that the initiator pays the passed fee for the closing transaction
+// in full.
 func CreateCooperativeCloseTx(fundingTxIn *wire.TxIn,
 	localDust, remoteDust, ourBalance, theirBalance btcutil.Amount,
-	ourDeliveryScript, theirDeliveryScript []byte,
+	ourDeliveryScript, theirDeliveryScript []byte, fee btcutil.Amount,
 	initiator bool) *wire.MsgTx {
 
 	// Construct the transaction to perform a cooperative closure of the
@@ -2638,10 +2889,9 @@ func CreateCooperativeCloseTx(fundingTxIn *wire.TxIn,
 	// The initiator of a cooperative closure pays the fee in entirety.
 	// Determine if we're the initiator so we can compute fees properly.
 	if initiator {
-		// TODO(roasbeef): take sat/byte here instead of properly calc
-		ourBalance -= 5000
+		ourBalance -= fee
 	} else {
-		theirBalance -= 5000
+		theirBalance -= fee
 	}
 
 	// Create both cooperative closure outputs, properly respecting the
@@ -3,6 +3,7 @@ package lnwallet
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -51,6 +52,10 @@ var (
 	// The number of confirmations required to consider any created channel
 	// open.
 	numReqConfs = uint16(1)
+
+	// closeFee is the fee paid by the initiator of the test channels for
+	// all cooperative close transactions.
+	closeFee = btcutil.Amount(5000)
 )
 
 type mockSigner struct {
@@ -101,7 +106,9 @@ func (m *mockNotfier) Start() error {
 func (m *mockNotfier) Stop() error {
 	return nil
 }
-func (m *mockNotfier) RegisterSpendNtfn(outpoint *wire.OutPoint) (*chainntnfs.SpendEvent, error) {
+func (m *mockNotfier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
 	return &chainntnfs.SpendEvent{
 		Spend: make(chan *chainntnfs.SpendDetail),
 	}, nil
@@ -202,7 +209,7 @@ func createTestChannels(revocationWindow int) (*LightningChannel, *LightningChan
 	}
 	fundingTxIn := wire.NewTxIn(prevOut, nil, nil)
 
-	bobRoot := deriveRevocationRoot(bobKeyPriv, bobKeyPub, aliceKeyPub)
+	bobRoot := deriveRevocationRoot(bobKeyPriv.Serialize(), bobKeyPub, aliceKeyPub)
 	bobPreimageProducer := shachain.NewRevocationProducer(*bobRoot)
 	bobFirstRevoke, err := bobPreimageProducer.AtIndex(0)
 	if err != nil {
@@ -210,7 +217,7 @@ func createTestChannels(revocationWindow int) (*LightningChannel, *LightningChan
 	}
 	bobRevokeKey := DeriveRevocationPubkey(aliceKeyPub, bobFirstRevoke[:])
 
-	aliceRoot := deriveRevocationRoot(aliceKeyPriv, aliceKeyPub, bobKeyPub)
+	aliceRoot := deriveRevocationRoot(aliceKeyPriv.Serialize(), aliceKeyPub, bobKeyPub)
 	alicePreimageProducer := shachain.NewRevocationProducer(*aliceRoot)
 	aliceFirstRevoke, err := alicePreimageProducer.AtIndex(0)
 	if err != nil {
@@ -700,12 +707,12 @@ func TestCooperativeChannelClosure(t *testing.T) {
 	defer cleanUp()
 
 	// First we test the channel initiator requesting a cooperative close.
-	sig, txid, err := aliceChannel.InitCooperativeClose()
+	sig, txid, err := aliceChannel.InitCooperativeClose(closeFee)
 	if err != nil {
 		t.Fatalf("unable to initiate alice cooperative close: %v", err)
 	}
 	finalSig := append(sig, byte(txscript.SigHashAll))
-	closeTx, err := bobChannel.CompleteCooperativeClose(finalSig)
+	closeTx, err := bobChannel.CompleteCooperativeClose(finalSig, closeFee)
 	if err != nil {
 		t.Fatalf("unable to complete alice cooperative close: %v", err)
 	}
@@ -720,12 +727,12 @@ func TestCooperativeChannelClosure(t *testing.T) {
 
 	// Next we test the channel recipient requesting a cooperative closure.
 	// First we test the channel initiator requesting a cooperative close.
-	sig, txid, err = bobChannel.InitCooperativeClose()
+	sig, txid, err = bobChannel.InitCooperativeClose(closeFee)
 	if err != nil {
 		t.Fatalf("unable to initiate bob cooperative close: %v", err)
 	}
 	finalSig = append(sig, byte(txscript.SigHashAll))
-	closeTx, err = aliceChannel.CompleteCooperativeClose(finalSig)
+	closeTx, err = aliceChannel.CompleteCooperativeClose(finalSig, closeFee)
 	if err != nil {
 		t.Fatalf("unable to complete bob cooperative close: %v", err)
 	}
@@ -842,6 +849,107 @@ func TestCheckHTLCNumberConstraint(t *testing.T) {
 
 }
 
+// TestChannelConstraints checks that HTLCs violating the constraints
+// negotiated during funding are rejected by both the offering and the
+// receiving side: the maximum number of accepted HTLCs, the maximum pending
+// amount, and the channel reserve.
+func TestChannelConstraints(t *testing.T) {
+	createHTLC := func(i int, amt btcutil.Amount) *lnwire.UpdateAddHTLC {
+		preimage := bytes.Repeat([]byte{byte(i)}, 32)
+		paymentHash := sha256.Sum256(preimage)
+		return &lnwire.UpdateAddHTLC{
+			PaymentHash: paymentHash,
+			Amount:      amt,
+			Expiry:      uint32(5),
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		constraints channeldb.ChannelConstraints
+		validAmts   []btcutil.Amount
+		invalidAmt  btcutil.Amount
+		expectedErr error
+	}{
+		{
+			name: "max accepted htlcs",
+			constraints: channeldb.ChannelConstraints{
+				MaxAcceptedHtlcs: 2,
+			},
+			validAmts:   []btcutil.Amount{1e6, 1e6},
+			invalidAmt:  1e6,
+			expectedErr: ErrMaxHTLCNumber,
+		},
+		{
+			name: "max pending amount",
+			constraints: channeldb.ChannelConstraints{
+				MaxPendingAmount: 1e8,
+			},
+			validAmts:   []btcutil.Amount{5e7, 5e7},
+			invalidAmt:  1,
+			expectedErr: ErrMaxPendingAmount,
+		},
+		{
+			name: "chan reserve",
+			constraints: channeldb.ChannelConstraints{
+				ChanReserve: 4e8,
+			},
+			validAmts:   []btcutil.Amount{1e8},
+			invalidAmt:  1,
+			expectedErr: ErrBelowChanReserve,
+		},
+	}
+
+	for _, test := range testCases {
+		// Create a test channel funded evenly with Alice having 5 BTC,
+		// and Bob having 5 BTC. Bob imposes the constraints upon Alice,
+		// so Alice's outgoing HTLCs are subject to them.
+		aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+		if err != nil {
+			t.Fatalf("unable to create test channels: %v", err)
+		}
+
+		aliceChannel.channelState.TheirConstraints = test.constraints
+		bobChannel.channelState.OurConstraints = test.constraints
+
+		for i, amt := range test.validAmts {
+			htlc := createHTLC(i, amt)
+			if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+				t.Fatalf("%v: alice unable to add htlc: %v",
+					test.name, err)
+			}
+			if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+				t.Fatalf("%v: bob unable to receive htlc: %v",
+					test.name, err)
+			}
+		}
+
+		// The next HTLC violates the constraints, so Alice should
+		// refuse to add it, and Bob should refuse to accept it.
+		htlc := createHTLC(len(test.validAmts), test.invalidAmt)
+		if _, err := aliceChannel.AddHTLC(htlc); err != test.expectedErr {
+			t.Fatalf("%v: expected alice to fail with %v, "+
+				"instead got: %v", test.name, test.expectedErr, err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != test.expectedErr {
+			t.Fatalf("%v: expected bob to fail with %v, "+
+				"instead got: %v", test.name, test.expectedErr, err)
+		}
+
+		// Bob isn't subject to any constraints, so he should still be
+		// able to offer an HTLC to Alice.
+		if _, err := bobChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("%v: bob unable to add htlc: %v", test.name, err)
+		}
+		if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("%v: alice unable to receive htlc: %v",
+				test.name, err)
+		}
+
+		cleanUp()
+	}
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when
 // a peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit.
@@ -1018,6 +1126,90 @@ func TestForceClose(t *testing.T) {
 	}
 }
 
+// TestForceCloseHtlcResolutions checks that the ForceCloseSummary includes
+// the information required to sweep each of our outgoing HTLCs once they've
+// timed out, and that incoming HTLCs aren't included.
+func TestForceCloseHtlcResolutions(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	paymentHash := sha256.Sum256(preimage)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: paymentHash,
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(100),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("Can't update the channel state: %v", err)
+	}
+
+	closeSummary, err := aliceChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+
+	if len(closeSummary.HtlcResolutions) != 1 {
+		t.Fatalf("expected 1 htlc resolution, got %v",
+			len(closeSummary.HtlcResolutions))
+	}
+	htlcRes := closeSummary.HtlcResolutions[0]
+	if htlcRes.Expiry != htlc.Expiry {
+		t.Fatalf("incorrect expiry: expected %v, got %v", htlc.Expiry,
+			htlcRes.Expiry)
+	}
+	if htlcRes.MaturityDelay != aliceChannel.channelState.LocalCsvDelay {
+		t.Fatalf("incorrect maturity delay: expected %v, got %v",
+			aliceChannel.channelState.LocalCsvDelay,
+			htlcRes.MaturityDelay)
+	}
+	if htlcRes.PaymentHash != paymentHash {
+		t.Fatalf("incorrect payment hash")
+	}
+
+	// The claim outpoint should reference an output of the close tx which
+	// pays to the witness script within the sign descriptor.
+	closeTxHash := closeSummary.CloseTx.TxHash()
+	if htlcRes.ClaimOutpoint.Hash != closeTxHash {
+		t.Fatalf("claim outpoint doesn't reference close tx")
+	}
+	htlcOutput := closeSummary.CloseTx.TxOut[htlcRes.ClaimOutpoint.Index]
+	if htlcOutput.Value != int64(htlc.Amount) {
+		t.Fatalf("incorrect htlc output value: expected %v, got %v",
+			htlc.Amount, htlcOutput.Value)
+	}
+	pkScript, err := witnessScriptHash(htlcRes.SweepSignDesc.WitnessScript)
+	if err != nil {
+		t.Fatalf("unable to create p2wsh script: %v", err)
+	}
+	if !bytes.Equal(pkScript, htlcOutput.PkScript) {
+		t.Fatalf("witness script doesn't match htlc output")
+	}
+	if htlcRes.SweepSignDesc.PubKey != aliceChannel.channelState.OurCommitKey {
+		t.Fatalf("incorrect pubkey in sweep sign descriptor")
+	}
+
+	// Bob is the receiver of the HTLC, so his summary shouldn't contain
+	// any resolutions.
+	closeSummary, err = bobChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+	if len(closeSummary.HtlcResolutions) != 0 {
+		t.Fatalf("expected no htlc resolutions, got %v",
+			len(closeSummary.HtlcResolutions))
+	}
+}
+
 // TestCheckDustLimit checks that unsettled HTLC with dust limit not included in
 // commitment transaction as output, but sender balance is decreased (thereby all
 // unsettled dust HTLCs will go to miners fee).
@@ -1633,12 +1825,12 @@ func TestCooperativeCloseDustAdherance(t *testing.T) {
 	// Both sides currently have over 1 BTC settled as part of their
 	// balances. As a result, performing a cooperative closure now result
 	// in both sides having an output within the closure transaction.
-	closeSig, _, err := aliceChannel.InitCooperativeClose()
+	closeSig, _, err := aliceChannel.InitCooperativeClose(closeFee)
 	if err != nil {
 		t.Fatalf("unable to close channel: %v", err)
 	}
 	closeSig = append(closeSig, byte(txscript.SigHashAll))
-	closeTx, err := bobChannel.CompleteCooperativeClose(closeSig)
+	closeTx, err := bobChannel.CompleteCooperativeClose(closeSig, closeFee)
 	if err != nil {
 		t.Fatalf("unable to accept channel close: %v", err)
 	}
@@ -1660,26 +1852,25 @@ func TestCooperativeCloseDustAdherance(t *testing.T) {
 
 	// Attempt another cooperative channel closure. It should succeed
 	// without any issues.
-	closeSig, _, err = aliceChannel.InitCooperativeClose()
+	closeSig, _, err = aliceChannel.InitCooperativeClose(closeFee)
 	if err != nil {
 		t.Fatalf("unable to close channel: %v", err)
 	}
 	closeSig = append(closeSig, byte(txscript.SigHashAll))
-	closeTx, err = bobChannel.CompleteCooperativeClose(closeSig)
+	closeTx, err = bobChannel.CompleteCooperativeClose(closeSig, closeFee)
 	if err != nil {
 		t.Fatalf("unable to accept channel close: %v", err)
 	}
 
 	// The closure transaction should only have a single output, and that
 	// output should be Alice's balance.
-	// TODO(roasbeef): remove -5000 after fees are no longer hard coded
 	if len(closeTx.TxOut) != 1 {
 		t.Fatalf("close tx has wrong number of outputs: expected %v "+
 			"got %v", 1, len(closeTx.TxOut))
 	}
-	if closeTx.TxOut[0].Value != int64(aliceBal-5000) {
+	if closeTx.TxOut[0].Value != int64(aliceBal-closeFee) {
 		t.Fatalf("alice's balance is incorrect: expected %v, got %v",
-			aliceBal-5000, closeTx.TxOut[0].Value)
+			aliceBal-closeFee, closeTx.TxOut[0].Value)
 	}
 
 	// Finally, we'll modify the current balances and dust limits such that
@@ -1689,19 +1880,18 @@ func TestCooperativeCloseDustAdherance(t *testing.T) {
 
 	// Our final attempt at another cooperative channel closure. It should
 	// succeed without any issues.
-	closeSig, _, err = aliceChannel.InitCooperativeClose()
+	closeSig, _, err = aliceChannel.InitCooperativeClose(closeFee)
 	if err != nil {
 		t.Fatalf("unable to close channel: %v", err)
 	}
 	closeSig = append(closeSig, byte(txscript.SigHashAll))
-	closeTx, err = bobChannel.CompleteCooperativeClose(closeSig)
+	closeTx, err = bobChannel.CompleteCooperativeClose(closeSig, closeFee)
 	if err != nil {
 		t.Fatalf("unable to accept channel close: %v", err)
 	}
 
 	// The closure transaction should only have a single output, and that
 	// output should be Bob's balance.
-	// TODO(roasbeef): remove -5000 after fees are no longer hard coded
 	if len(closeTx.TxOut) != 1 {
 		t.Fatalf("close tx has wrong number of outputs: expected %v "+
 			"got %v", 1, len(closeTx.TxOut))
@@ -1711,3 +1901,83 @@ func TestCooperativeCloseDustAdherance(t *testing.T) {
 			aliceBal, closeTx.TxOut[0].Value)
 	}
 }
+
+// benchmarkChannelUpdates measures the throughput of a channel as payments
+// are sent from Alice to Bob. Each payment requires an HTLC to be added, then
+// settled. Rather than signing a new commitment after each update, a state
+// transition is only executed once batchSize updates have been collected,
+// allowing the cost of signing to be amortized across the batch.
+func benchmarkChannelUpdates(b *testing.B, batchSize int) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		b.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Each payment is of a value just above Bob's dust limit, ensuring
+	// all HTLCs are present on both commitment transactions.
+	htlcAmt := btcutil.Amount(1000)
+
+	var preimages [][32]byte
+	settleBatch := func() {
+		if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+			b.Fatalf("unable to complete state update: %v", err)
+		}
+
+		for _, preimage := range preimages {
+			settleIndex, err := bobChannel.SettleHTLC(preimage)
+			if err != nil {
+				b.Fatalf("bob unable to settle inbound htlc: %v",
+					err)
+			}
+			err = aliceChannel.ReceiveHTLCSettle(preimage, settleIndex)
+			if err != nil {
+				b.Fatalf("alice unable to accept settle of "+
+					"outbound htlc: %v", err)
+			}
+		}
+		if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+			b.Fatalf("unable to complete state update: %v", err)
+		}
+
+		preimages = preimages[:0]
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var preimage [32]byte
+		binary.BigEndian.PutUint64(preimage[:8], uint64(i))
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(preimage[:]),
+			Amount:      htlcAmt,
+			Expiry:      uint32(5),
+		}
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			b.Fatalf("alice unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			b.Fatalf("bob unable to receive htlc: %v", err)
+		}
+		preimages = append(preimages, preimage)
+
+		if len(preimages) == batchSize {
+			settleBatch()
+		}
+	}
+
+	if len(preimages) != 0 {
+		settleBatch()
+	}
+}
+
+// BenchmarkChannelUpdatesUnbatched benchmarks payment throughput when a new
+// commitment is signed for every update.
+func BenchmarkChannelUpdatesUnbatched(b *testing.B) {
+	benchmarkChannelUpdates(b, 1)
+}
+
+// BenchmarkChannelUpdatesBatched benchmarks payment throughput when updates
+// are collected into batches before a new commitment is signed.
+func BenchmarkChannelUpdatesBatched(b *testing.B) {
+	benchmarkChannelUpdates(b, 10)
+}
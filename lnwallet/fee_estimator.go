@@ -0,0 +1,339 @@
+package lnwallet
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/roasbeef/btcrpcclient"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// weightPerKiloVByte is the number of weight units within a kilo
+	// virtual byte. Fee rates reported by bitcoin nodes are expressed in
+	// BTC/kvB, while we deal in satoshis per weight unit.
+	weightPerKiloVByte = 4000
+
+	// minFeePerWeight is the smallest fee rate, in satoshis per weight
+	// unit, that an estimator will return.
+	minFeePerWeight = btcutil.Amount(1)
+)
+
+const (
+	// FundingTxConfTarget is the number of blocks within which we aim
+	// for funding transactions to confirm.
+	FundingTxConfTarget = 6
+
+	// SweepTxConfTarget is the number of blocks within which we aim for
+	// transactions sweeping matured outputs back into the wallet to
+	// confirm.
+	SweepTxConfTarget = 6
+
+	// JusticeTxConfTarget is the number of blocks within which we aim for
+	// justice transactions to confirm. As the breaching party is able to
+	// sweep their funds once their CSV delay expires, this target is
+	// kept aggressive.
+	JusticeTxConfTarget = 1
+
+	// CloseTxConfTarget is the number of blocks within which we aim for
+	// cooperative close transactions to confirm.
+	CloseTxConfTarget = 6
+
+	// SendTxConfTarget is the number of blocks within which we aim for
+	// transactions sending coins on-chain to confirm, unless the caller
+	// specifies otherwise.
+	SendTxConfTarget = 6
+)
+
+// FeeEstimator provides the ability to estimate on-chain transaction fees for
+// various combinations of transaction sizes and desired confirmation time
+// (measured by number of blocks).
+type FeeEstimator interface {
+	// EstimateFeePerWeight takes in a target for the number of blocks
+	// until an initial confirmation and returns the estimated fee
+	// expressed in satoshis per weight unit.
+	EstimateFeePerWeight(numBlocks uint32) (btcutil.Amount, error)
+
+	// Start signals the FeeEstimator to start any processes or
+	// goroutines it needs to perform its duty.
+	Start() error
+
+	// Stop stops any spawned goroutines and cleans up the resources used
+	// by the fee estimator.
+	Stop() error
+}
+
+// StaticFeeEstimator will return a static value for all fee calculation
+// requests. It is designed to be replaced by a proper fee calculation
+// implementation, or to serve as the last resort of a FallbackFeeEstimator.
+type StaticFeeEstimator struct {
+	// FeeRate is the static fee rate in satoshis-per-weight-unit that
+	// will be returned by this fee estimator.
+	FeeRate btcutil.Amount
+}
+
+// EstimateFeePerWeight returns a static value for fee per weight unit.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (e StaticFeeEstimator) EstimateFeePerWeight(numBlocks uint32) (btcutil.Amount, error) {
+	return e.FeeRate, nil
+}
+
+// Start signals the FeeEstimator to start any processes or goroutines
+// it needs to perform its duty.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (e StaticFeeEstimator) Start() error {
+	return nil
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used
+// by the fee estimator.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (e StaticFeeEstimator) Stop() error {
+	return nil
+}
+
+// A compile-time assertion to ensure that StaticFeeEstimator implements the
+// FeeEstimator interface.
+var _ FeeEstimator = (*StaticFeeEstimator)(nil)
+
+// BtcdFeeEstimator is an implementation of the FeeEstimator interface backed
+// by the RPC interface of an active btcd node. It queries btcd's estimatefee
+// call, returning an error if btcd is unable to produce an estimate.
+type BtcdFeeEstimator struct {
+	btcdConn *btcrpcclient.Client
+}
+
+// NewBtcdFeeEstimator creates a new BtcdFeeEstimator given a fully populated
+// rpc config that is able to successfully connect and authenticate with the
+// btcd node.
+func NewBtcdFeeEstimator(rpcConfig btcrpcclient.ConnConfig) (*BtcdFeeEstimator, error) {
+	rpcConfig.DisableConnectOnNew = true
+	rpcConfig.DisableAutoReconnect = false
+	chainConn, err := btcrpcclient.New(&rpcConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BtcdFeeEstimator{
+		btcdConn: chainConn,
+	}, nil
+}
+
+// Start signals the FeeEstimator to start any processes or goroutines
+// it needs to perform its duty.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BtcdFeeEstimator) Start() error {
+	return b.btcdConn.Connect(20)
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used
+// by the fee estimator.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BtcdFeeEstimator) Stop() error {
+	b.btcdConn.Shutdown()
+	return nil
+}
+
+// EstimateFeePerWeight takes in a target for the number of blocks until an
+// initial confirmation and returns the estimated fee expressed in
+// satoshis per weight unit.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BtcdFeeEstimator) EstimateFeePerWeight(numBlocks uint32) (btcutil.Amount, error) {
+	// The fee rate is returned as BTC/kvB, or -1 if btcd doesn't yet have
+	// enough data to produce an estimate.
+	btcPerKB, err := b.btcdConn.EstimateFee(int64(numBlocks))
+	if err != nil {
+		return 0, err
+	}
+	if btcPerKB <= 0 {
+		return 0, fmt.Errorf("btcd unable to estimate fee for %v "+
+			"blocks", numBlocks)
+	}
+
+	return feeRateToWeight(btcPerKB)
+}
+
+// A compile-time assertion to ensure that BtcdFeeEstimator implements the
+// FeeEstimator interface.
+var _ FeeEstimator = (*BtcdFeeEstimator)(nil)
+
+// BitcoindFeeEstimator is an implementation of the FeeEstimator interface
+// backed by the RPC interface of an active bitcoind node. It queries
+// bitcoind's estimatesmartfee call, returning an error if bitcoind is unable
+// to produce an estimate.
+type BitcoindFeeEstimator struct {
+	bitcoindConn *btcrpcclient.Client
+}
+
+// NewBitcoindFeeEstimator creates a new BitcoindFeeEstimator given a fully
+// populated rpc config that is able to successfully connect and authenticate
+// with the bitcoind node.
+func NewBitcoindFeeEstimator(rpcConfig btcrpcclient.ConnConfig) (*BitcoindFeeEstimator, error) {
+	// bitcoind doesn't support websockets, so all requests are issued
+	// as HTTP POST requests.
+	rpcConfig.DisableConnectOnNew = true
+	rpcConfig.DisableAutoReconnect = false
+	rpcConfig.DisableTLS = true
+	rpcConfig.HTTPPostMode = true
+	chainConn, err := btcrpcclient.New(&rpcConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BitcoindFeeEstimator{
+		bitcoindConn: chainConn,
+	}, nil
+}
+
+// Start signals the FeeEstimator to start any processes or goroutines
+// it needs to perform its duty.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BitcoindFeeEstimator) Start() error {
+	return nil
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used
+// by the fee estimator.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BitcoindFeeEstimator) Stop() error {
+	b.bitcoindConn.Shutdown()
+	return nil
+}
+
+// EstimateFeePerWeight takes in a target for the number of blocks until an
+// initial confirmation and returns the estimated fee expressed in
+// satoshis per weight unit.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BitcoindFeeEstimator) EstimateFeePerWeight(numBlocks uint32) (btcutil.Amount, error) {
+	// estimatesmartfee isn't exposed by btcrpcclient, so we'll issue the
+	// request ourselves.
+	target, err := json.Marshal(numBlocks)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := b.bitcoindConn.RawRequest("estimatesmartfee",
+		[]json.RawMessage{target})
+	if err != nil {
+		return 0, err
+	}
+
+	// The fee rate is returned as BTC/kvB, or -1 if bitcoind doesn't yet
+	// have enough data to produce an estimate.
+	var estimate struct {
+		FeeRate float64 `json:"feerate"`
+		Blocks  int64   `json:"blocks"`
+	}
+	if err := json.Unmarshal(resp, &estimate); err != nil {
+		return 0, err
+	}
+	if estimate.FeeRate <= 0 {
+		return 0, fmt.Errorf("bitcoind unable to estimate fee for %v "+
+			"blocks", numBlocks)
+	}
+
+	return feeRateToWeight(estimate.FeeRate)
+}
+
+// A compile-time assertion to ensure that BitcoindFeeEstimator implements the
+// FeeEstimator interface.
+var _ FeeEstimator = (*BitcoindFeeEstimator)(nil)
+
+// FallbackFeeEstimator is an implementation of the FeeEstimator interface
+// which consults a chain of estimators in order, returning the first
+// successful estimate. This allows an estimator backed by a node, which may
+// lack the data to produce an estimate, to fall back to a static fee rate.
+type FallbackFeeEstimator struct {
+	estimators []FeeEstimator
+}
+
+// NewFallbackFeeEstimator creates a new FallbackFeeEstimator which consults
+// the passed estimators in order.
+func NewFallbackFeeEstimator(estimators ...FeeEstimator) *FallbackFeeEstimator {
+	return &FallbackFeeEstimator{
+		estimators: estimators,
+	}
+}
+
+// Start signals the FeeEstimator to start any processes or goroutines
+// it needs to perform its duty.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (f *FallbackFeeEstimator) Start() error {
+	for _, estimator := range f.estimators {
+		if err := estimator.Start(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used
+// by the fee estimator.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (f *FallbackFeeEstimator) Stop() error {
+	for _, estimator := range f.estimators {
+		if err := estimator.Stop(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EstimateFeePerWeight takes in a target for the number of blocks until an
+// initial confirmation and returns the first estimate produced by the chain
+// of estimators, expressed in satoshis per weight unit.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (f *FallbackFeeEstimator) EstimateFeePerWeight(numBlocks uint32) (btcutil.Amount, error) {
+	var lastErr error
+	for _, estimator := range f.estimators {
+		feeRate, err := estimator.EstimateFeePerWeight(numBlocks)
+		if err != nil {
+			walletLog.Debugf("Unable to estimate fee for %v "+
+				"blocks: %v", numBlocks, err)
+			lastErr = err
+			continue
+		}
+
+		return feeRate, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no fee estimators available")
+	}
+
+	return 0, lastErr
+}
+
+// A compile-time assertion to ensure that FallbackFeeEstimator implements the
+// FeeEstimator interface.
+var _ FeeEstimator = (*FallbackFeeEstimator)(nil)
+
+// feeRateToWeight converts a fee rate expressed in BTC/kvB, as reported by
+// bitcoin nodes, into satoshis per weight unit. The result is floored at
+// minFeePerWeight to ensure transactions always pay some fee.
+func feeRateToWeight(btcPerKB float64) (btcutil.Amount, error) {
+	satPerKB, err := btcutil.NewAmount(btcPerKB)
+	if err != nil {
+		return 0, err
+	}
+
+	feePerWeight := satPerKB / weightPerKiloVByte
+	if feePerWeight < minFeePerWeight {
+		feePerWeight = minFeePerWeight
+	}
+
+	return feePerWeight, nil
+}
@@ -0,0 +1,104 @@
+package lnwallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// failingFeeEstimator is a FeeEstimator which is unable to produce an
+// estimate, such as a node which lacks the data to do so.
+type failingFeeEstimator struct {
+	StaticFeeEstimator
+}
+
+// EstimateFeePerWeight always returns an error.
+func (f failingFeeEstimator) EstimateFeePerWeight(
+	numBlocks uint32) (btcutil.Amount, error) {
+
+	return 0, errors.New("unable to estimate fee")
+}
+
+// TestStaticFeeEstimator tests that the StaticFeeEstimator returns its fee
+// rate regardless of the confirmation target.
+func TestStaticFeeEstimator(t *testing.T) {
+	t.Parallel()
+
+	estimator := StaticFeeEstimator{FeeRate: 25}
+	for _, numBlocks := range []uint32{1, 6, 144} {
+		feeRate, err := estimator.EstimateFeePerWeight(numBlocks)
+		if err != nil {
+			t.Fatalf("unable to estimate fee: %v", err)
+		}
+		if feeRate != 25 {
+			t.Fatalf("expected fee rate of 25, got %v", feeRate)
+		}
+	}
+}
+
+// TestFallbackFeeEstimator tests that the FallbackFeeEstimator returns the
+// estimate of the first estimator in its chain able to produce one, and an
+// error if none are able to.
+func TestFallbackFeeEstimator(t *testing.T) {
+	t.Parallel()
+
+	estimator := NewFallbackFeeEstimator(
+		failingFeeEstimator{},
+		StaticFeeEstimator{FeeRate: 10},
+		StaticFeeEstimator{FeeRate: 20},
+	)
+	feeRate, err := estimator.EstimateFeePerWeight(6)
+	if err != nil {
+		t.Fatalf("unable to estimate fee: %v", err)
+	}
+	if feeRate != 10 {
+		t.Fatalf("expected fee rate of 10, got %v", feeRate)
+	}
+
+	estimator = NewFallbackFeeEstimator(failingFeeEstimator{})
+	if _, err := estimator.EstimateFeePerWeight(6); err == nil {
+		t.Fatalf("expected error when all estimators fail")
+	}
+
+	estimator = NewFallbackFeeEstimator()
+	if _, err := estimator.EstimateFeePerWeight(6); err == nil {
+		t.Fatalf("expected error without any estimators")
+	}
+}
+
+// TestFeeRateToWeight tests the conversion of fee rates reported by bitcoin
+// nodes in BTC/kvB into satoshis per weight unit, ensuring the result is
+// floored at the minimum fee rate.
+func TestFeeRateToWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		btcPerKB     float64
+		feePerWeight btcutil.Amount
+	}{
+		// 100,000 sat/kvB is 25 sat/weight.
+		{
+			btcPerKB:     0.001,
+			feePerWeight: 25,
+		},
+
+		// 1,000 sat/kvB is below a single sat/weight, so the minimum
+		// fee rate is returned.
+		{
+			btcPerKB:     0.00001,
+			feePerWeight: minFeePerWeight,
+		},
+	}
+
+	for _, test := range tests {
+		feePerWeight, err := feeRateToWeight(test.btcPerKB)
+		if err != nil {
+			t.Fatalf("unable to convert fee rate: %v", err)
+		}
+		if feePerWeight != test.feePerWeight {
+			t.Fatalf("expected %v sat/weight for %v BTC/kvB, got %v",
+				test.feePerWeight, test.btcPerKB, feePerWeight)
+		}
+	}
+}
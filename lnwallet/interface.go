@@ -37,6 +37,14 @@ const (
 type Utxo struct {
 	Value btcutil.Amount
 	wire.OutPoint
+
+	// PkScript is the output script of the unspent output.
+	PkScript []byte
+
+	// Confirmations is the number of confirmations the transaction
+	// creating the output has received. An unconfirmed output has zero
+	// confirmations.
+	Confirmations int64
 }
 
 // TransactionDetail describes a transaction with either inputs which belong to
@@ -209,9 +217,13 @@ type BlockChainIO interface {
 	// most-work chain the implementation is aware of.
 	GetBestBlock() (*chainhash.Hash, int32, error)
 
-	// GetTxOut returns the original output referenced by the passed
-	// outpoint.
-	GetUtxo(txid *chainhash.Hash, index uint32) (*wire.TxOut, error)
+	// GetUtxo returns the original output referenced by the passed
+	// outpoint. The heightHint should be the earliest height at which the
+	// output could have been created, as implementations backed by a light
+	// client must scan the chain from that height in order to locate the
+	// output. A heightHint of zero scans the entire chain.
+	GetUtxo(txid *chainhash.Hash, index uint32,
+		heightHint uint32) (*wire.TxOut, error)
 
 	// GetTransaction returns the full transaction identified by the passed
 	// transaction ID.
@@ -354,6 +366,22 @@ func RegisterWallet(driver *WalletDriver) error {
 	return nil
 }
 
+// LookupWallet returns the registered WalletDriver of the passed type. An
+// error is returned if no such driver has been registered.
+//
+// NOTE: This function is safe for concurrent access.
+func LookupWallet(walletType string) (*WalletDriver, error) {
+	registerMtx.Lock()
+	defer registerMtx.Unlock()
+
+	driver, ok := wallets[walletType]
+	if !ok {
+		return nil, fmt.Errorf("wallet %v not registered", walletType)
+	}
+
+	return driver, nil
+}
+
 // SupportedWallets returns a slice of strings that represents the wallet
 // drivers that have been registered and are therefore supported.
 //
@@ -5,21 +5,27 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
 	"github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/lightningnetwork/lnd/simchain"
 	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcrpcclient"
 	"github.com/roasbeef/btcutil/txsort"
+	"github.com/roasbeef/btcwallet/chain"
 	_ "github.com/roasbeef/btcwallet/walletdb/bdb"
 
 	"github.com/roasbeef/btcd/btcec"
@@ -63,8 +69,46 @@ var (
 	numReqConfs = uint16(1)
 
 	bobAddr, _ = net.ResolveTCPAddr("tcp", "10.0.0.2:9000")
+
+	// netParams is the network each wallet under test operates on. We use
+	// the regression test network, as unlike the simulation network, it's
+	// supported by both btcd and bitcoind.
+	netParams = &chaincfg.RegressionNetParams
 )
 
+// chainHarness is the chain backing the wallet under test, which the tests
+// use to send funds to the wallet and mine blocks.
+type chainHarness interface {
+	// Generate mines numBlocks blocks, returning their hashes.
+	Generate(numBlocks uint32) ([]*chainhash.Hash, error)
+
+	// SendOutputs publishes a transaction creating the passed outputs,
+	// funded by the harness.
+	SendOutputs(outputs []*wire.TxOut,
+		feeRate btcutil.Amount) (*chainhash.Hash, error)
+
+	// GetRawTransaction returns the transaction identified by the passed
+	// txid.
+	GetRawTransaction(txid *chainhash.Hash) (*btcutil.Tx, error)
+}
+
+// btcdHarness is a chainHarness backed by a btcd miner.
+type btcdHarness struct {
+	*rpctest.Harness
+}
+
+// Generate mines numBlocks blocks with the miner.
+func (h *btcdHarness) Generate(numBlocks uint32) ([]*chainhash.Hash, error) {
+	return h.Node.Generate(numBlocks)
+}
+
+// GetRawTransaction returns the transaction identified by the passed txid.
+func (h *btcdHarness) GetRawTransaction(
+	txid *chainhash.Hash) (*btcutil.Tx, error) {
+
+	return h.Node.GetRawTransaction(txid)
+}
+
 // assertProperBalance asserts than the total value of the unspent outputs
 // within the wallet are *exactly* amount. If unable to retrieve the current
 // balance, or the assertion fails, the test will halt with a fatal error.
@@ -79,11 +123,11 @@ func assertProperBalance(t *testing.T, lw *lnwallet.LightningWallet, numConfirms
 	}
 }
 
-func assertChannelOpen(t *testing.T, miner *rpctest.Harness, numConfs uint32,
+func assertChannelOpen(t *testing.T, miner chainHarness, numConfs uint32,
 	c <-chan *lnwallet.LightningChannel) *lnwallet.LightningChannel {
 	// Mine a single block. After this block is mined, the channel should
 	// be considered fully open.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate block: %v", err)
 	}
 	select {
@@ -194,7 +238,7 @@ func (b *bobNode) signCommitTx(commitTx *wire.MsgTx, fundingScript []byte,
 // funding transaction, bob has a single output totaling 7BTC. For our basic
 // test, he'll fund the channel with 5BTC, leaving 2BTC to the change output.
 // TODO(roasbeef): proper handling of change etc.
-func newBobNode(miner *rpctest.Harness, amt btcutil.Amount) (*bobNode, error) {
+func newBobNode(miner chainHarness, amt btcutil.Amount) (*bobNode, error) {
 	// First, parse Bob's priv key in order to obtain a key he'll use for the
 	// multi-sig funding transaction.
 	privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), bobsPrivKey)
@@ -203,7 +247,7 @@ func newBobNode(miner *rpctest.Harness, amt btcutil.Amount) (*bobNode, error) {
 	pkHash := btcutil.Hash160(pubKey.SerializeCompressed())
 	bobAddr, err := btcutil.NewAddressWitnessPubKeyHash(
 		pkHash,
-		miner.ActiveNet)
+		netParams)
 	if err != nil {
 		return nil, err
 	}
@@ -225,12 +269,12 @@ func newBobNode(miner *rpctest.Harness, amt btcutil.Amount) (*bobNode, error) {
 	// Mine a block in order to include the above output in a block. During
 	// the reservation workflow, we currently test to ensure that the funding
 	// output we're given actually exists.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		return nil, err
 	}
 
 	// Grab the transaction in order to locate the output index to Bob.
-	tx, err := miner.Node.GetRawTransaction(mainTxid)
+	tx, err := miner.GetRawTransaction(mainTxid)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +315,7 @@ func newBobNode(miner *rpctest.Harness, amt btcutil.Amount) (*bobNode, error) {
 	}, nil
 }
 
-func loadTestCredits(miner *rpctest.Harness, w *lnwallet.LightningWallet, numOutputs, btcPerOutput int) error {
+func loadTestCredits(miner chainHarness, w *lnwallet.LightningWallet, numOutputs, btcPerOutput int) error {
 	// Using the mining node, spend from a coinbase output numOutputs to
 	// give us btcPerOutput with each output.
 	satoshiPerOutput := int64(btcPerOutput * 1e8)
@@ -304,7 +348,7 @@ func loadTestCredits(miner *rpctest.Harness, w *lnwallet.LightningWallet, numOut
 	// Generate 10 blocks with the mining node, this should mine all
 	// numOutputs transactions created above. We generate 10 blocks here
 	// in order to give all the outputs a "sufficient" number of confirmations.
-	if _, err := miner.Node.Generate(10); err != nil {
+	if _, err := miner.Generate(10); err != nil {
 		return err
 	}
 
@@ -328,7 +372,7 @@ func loadTestCredits(miner *rpctest.Harness, w *lnwallet.LightningWallet, numOut
 
 // createTestWallet creates a test LightningWallet will a total of 20BTC
 // available for funding channels.
-func createTestWallet(tempTestDir string, miningNode *rpctest.Harness,
+func createTestWallet(tempTestDir string, miningNode chainHarness,
 	netParams *chaincfg.Params, notifier chainntnfs.ChainNotifier,
 	wc lnwallet.WalletController, signer lnwallet.Signer,
 	bio lnwallet.BlockChainIO) (*lnwallet.LightningWallet, error) {
@@ -339,8 +383,15 @@ func createTestWallet(tempTestDir string, miningNode *rpctest.Harness,
 		return nil, err
 	}
 
+	msgSigner, ok := wc.(lnwallet.MessageSigner)
+	if !ok {
+		return nil, fmt.Errorf("wallet controller doesn't implement " +
+			"lnwallet.MessageSigner")
+	}
+
+	estimator := lnwallet.StaticFeeEstimator{FeeRate: 50}
 	wallet, err := lnwallet.NewLightningWallet(cdb, notifier, wc, signer,
-		bio, netParams)
+		msgSigner, bio, estimator, netParams)
 	if err != nil {
 		return nil, err
 	}
@@ -357,7 +408,7 @@ func createTestWallet(tempTestDir string, miningNode *rpctest.Harness,
 	return wallet, nil
 }
 
-func testDualFundingReservationWorkflow(miner *rpctest.Harness, wallet *lnwallet.LightningWallet, t *testing.T) {
+func testDualFundingReservationWorkflow(miner chainHarness, wallet *lnwallet.LightningWallet, t *testing.T) {
 	t.Log("Running dual reservation workflow test")
 
 	// Create the bob-test wallet which will be the other side of our funding
@@ -372,7 +423,7 @@ func testDualFundingReservationWorkflow(miner *rpctest.Harness, wallet *lnwallet
 	// BTC total. He also generates 2 BTC in change.
 	chanReservation, err := wallet.InitChannelReservation(fundingAmount*2,
 		fundingAmount, bobNode.id, bobAddr, numReqConfs, 4,
-		lnwallet.DefaultDustLimit(), 0)
+		lnwallet.DefaultDustLimit(), 0, nil)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation: %v", err)
 	}
@@ -483,7 +534,7 @@ func testDualFundingReservationWorkflow(miner *rpctest.Harness, wallet *lnwallet
 	}
 }
 
-func testFundingTransactionLockedOutputs(miner *rpctest.Harness,
+func testFundingTransactionLockedOutputs(miner chainHarness,
 	wallet *lnwallet.LightningWallet, t *testing.T) {
 
 	t.Log("Running funding txn locked outputs test")
@@ -491,7 +542,8 @@ func testFundingTransactionLockedOutputs(miner *rpctest.Harness,
 	// Create a single channel asking for 16 BTC total.
 	fundingAmount := btcutil.Amount(8 * 1e8)
 	_, err := wallet.InitChannelReservation(fundingAmount, fundingAmount,
-		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0)
+		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0,
+		nil)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation 1: %v", err)
 	}
@@ -501,7 +553,8 @@ func testFundingTransactionLockedOutputs(miner *rpctest.Harness,
 	// that aren't locked, so this should fail.
 	amt := btcutil.Amount(900 * 1e8)
 	failedReservation, err := wallet.InitChannelReservation(amt, amt,
-		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0)
+		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0,
+		nil)
 	if err == nil {
 		t.Fatalf("not error returned, should fail on coin selection")
 	}
@@ -513,7 +566,7 @@ func testFundingTransactionLockedOutputs(miner *rpctest.Harness,
 	}
 }
 
-func testFundingCancellationNotEnoughFunds(miner *rpctest.Harness,
+func testFundingCancellationNotEnoughFunds(miner chainHarness,
 	wallet *lnwallet.LightningWallet, t *testing.T) {
 
 	t.Log("Running funding insufficient funds tests")
@@ -522,7 +575,7 @@ func testFundingCancellationNotEnoughFunds(miner *rpctest.Harness,
 	fundingAmount := btcutil.Amount(44 * 1e8)
 	chanReservation, err := wallet.InitChannelReservation(fundingAmount,
 		fundingAmount, testPub, bobAddr, numReqConfs, 4,
-		lnwallet.DefaultDustLimit(), 0)
+		lnwallet.DefaultDustLimit(), 0, nil)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation: %v", err)
 	}
@@ -530,7 +583,7 @@ func testFundingCancellationNotEnoughFunds(miner *rpctest.Harness,
 	// Attempt to create another channel with 44 BTC, this should fail.
 	_, err = wallet.InitChannelReservation(fundingAmount,
 		fundingAmount, testPub, bobAddr, numReqConfs, 4,
-		lnwallet.DefaultDustLimit(), 0)
+		lnwallet.DefaultDustLimit(), 0, nil)
 	if _, ok := err.(*lnwallet.ErrInsufficientFunds); !ok {
 		t.Fatalf("coin selection succeded should have insufficient funds: %v",
 			err)
@@ -560,13 +613,133 @@ func testFundingCancellationNotEnoughFunds(miner *rpctest.Harness,
 
 	// Request to fund a new channel should now succeed.
 	_, err = wallet.InitChannelReservation(fundingAmount, fundingAmount,
-		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0)
+		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0,
+		nil)
+	if err != nil {
+		t.Fatalf("unable to initialize funding reservation: %v", err)
+	}
+}
+
+// testFundingUnconfirmedOutputs tests that unconfirmed outputs are only
+// selected to fund a channel if the caller allows outputs without any
+// confirmations, and that coins selected for a reservation which fails are
+// unlocked.
+func testFundingUnconfirmedOutputs(miner chainHarness,
+	wallet *lnwallet.LightningWallet, t *testing.T) {
+
+	t.Log("Running funding unconfirmed outputs test")
+
+	// Send a large and a small output to the wallet, neither of which
+	// we'll confirm.
+	newOutput := func(amt btcutil.Amount) *wire.TxOut {
+		addr, err := wallet.NewAddress(lnwallet.WitnessPubKey, false)
+		if err != nil {
+			t.Fatalf("unable to create address: %v", err)
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatalf("unable to create script: %v", err)
+		}
+		return &wire.TxOut{Value: int64(amt), PkScript: script}
+	}
+	largeOutput := newOutput(btcutil.SatoshiPerBitcoin)
+	smallOutput := newOutput(3000)
+	txid, err := miner.SendOutputs([]*wire.TxOut{largeOutput, smallOutput},
+		10)
+	if err != nil {
+		t.Fatalf("unable to send outputs: %v", err)
+	}
+	tx, err := miner.GetRawTransaction(txid)
+	if err != nil {
+		t.Fatalf("unable to fetch transaction: %v", err)
+	}
+
+	var largeOutPoint, smallOutPoint wire.OutPoint
+	for i, txOut := range tx.MsgTx().TxOut {
+		outPoint := wire.OutPoint{Hash: *txid, Index: uint32(i)}
+		switch {
+		case bytes.Equal(txOut.PkScript, largeOutput.PkScript):
+			largeOutPoint = outPoint
+		case bytes.Equal(txOut.PkScript, smallOutput.PkScript):
+			smallOutPoint = outPoint
+		}
+	}
+
+	// Wait until the wallet is aware of both unconfirmed outputs.
+	timeout := time.After(15 * time.Second)
+	for found := 0; found != 2; {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("unconfirmed outputs not found within wallet")
+		}
+
+		utxos, err := wallet.ListUnspentWitness(0)
+		if err != nil {
+			t.Fatalf("unable to list unspent outputs: %v", err)
+		}
+		found = 0
+		for _, utxo := range utxos {
+			if utxo.OutPoint == largeOutPoint ||
+				utxo.OutPoint == smallOutPoint {
+
+				found++
+			}
+		}
+	}
+
+	// As the large output is unconfirmed, it can't fund a channel
+	// requiring outputs with a confirmation.
+	fundingAmount := btcutil.Amount(btcutil.SatoshiPerBitcoin / 2)
+	_, err = wallet.InitChannelReservation(fundingAmount, fundingAmount,
+		testPub, bobAddr, numReqConfs, 4, lnwallet.DefaultDustLimit(), 0,
+		&lnwallet.CoinSelectOpts{
+			Outpoints: []wire.OutPoint{largeOutPoint},
+			MinConfs:  1,
+		})
+	if err == nil {
+		t.Fatalf("unconfirmed output selected")
+	}
+
+	// However, once outputs without any confirmations are allowed, it
+	// should be selected.
+	reservation, err := wallet.InitChannelReservation(fundingAmount,
+		fundingAmount, testPub, bobAddr, numReqConfs, 4,
+		lnwallet.DefaultDustLimit(), 0, &lnwallet.CoinSelectOpts{
+			Outpoints: []wire.OutPoint{largeOutPoint},
+			MinConfs:  0,
+		})
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation: %v", err)
 	}
+	if err := reservation.Cancel(); err != nil {
+		t.Fatalf("unable to cancel reservation: %v", err)
+	}
+
+	// The small output is unable to pay for the commitment fee, so
+	// committing it to a channel should fail once it has been selected.
+	// The failed reservation shouldn't leave it locked.
+	numLocked := len(wallet.LockedOutpoints())
+	_, err = wallet.InitChannelReservation(0, 0, testPub, bobAddr,
+		numReqConfs, 4, lnwallet.DefaultDustLimit(), 0,
+		&lnwallet.CoinSelectOpts{
+			Outpoints: []wire.OutPoint{smallOutPoint},
+			SpendAll:  true,
+		})
+	if _, ok := err.(*lnwallet.ErrInsufficientFunds); !ok {
+		t.Fatalf("expected insufficient funds, got: %v", err)
+	}
+	if len(wallet.LockedOutpoints()) != numLocked {
+		t.Fatalf("coins of failed reservation still locked")
+	}
+
+	// Confirm both outputs, so they don't linger within the mempool.
+	if _, err := miner.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
 }
 
-func testCancelNonExistantReservation(miner *rpctest.Harness,
+func testCancelNonExistantReservation(miner chainHarness,
 	wallet *lnwallet.LightningWallet, t *testing.T) {
 
 	t.Log("Running cancel reservation tests")
@@ -582,7 +755,7 @@ func testCancelNonExistantReservation(miner *rpctest.Harness,
 	}
 }
 
-func testSingleFunderReservationWorkflowInitiator(miner *rpctest.Harness,
+func testSingleFunderReservationWorkflowInitiator(miner chainHarness,
 	wallet *lnwallet.LightningWallet, t *testing.T) {
 
 	t.Log("Running single funder workflow initiator test")
@@ -604,7 +777,7 @@ func testSingleFunderReservationWorkflowInitiator(miner *rpctest.Harness,
 	pushAmt := btcutil.Amount(btcutil.SatoshiPerBitcoin)
 	chanReservation, err := wallet.InitChannelReservation(fundingAmt,
 		fundingAmt, bobNode.id, bobAddr, numReqConfs, 4,
-		lnwallet.DefaultDustLimit(), pushAmt)
+		lnwallet.DefaultDustLimit(), pushAmt, nil)
 	if err != nil {
 		t.Fatalf("unable to init channel reservation: %v", err)
 	}
@@ -725,7 +898,7 @@ func testSingleFunderReservationWorkflowInitiator(miner *rpctest.Harness,
 	assertReservationDeleted(chanReservation, t)
 }
 
-func testSingleFunderReservationWorkflowResponder(miner *rpctest.Harness,
+func testSingleFunderReservationWorkflowResponder(miner chainHarness,
 	wallet *lnwallet.LightningWallet, t *testing.T) {
 
 	t.Log("Running single funder workflow responder test")
@@ -746,7 +919,7 @@ func testSingleFunderReservationWorkflowResponder(miner *rpctest.Harness,
 	fundingAmt := btcutil.Amount(0)
 	chanReservation, err := wallet.InitChannelReservation(capacity,
 		fundingAmt, bobNode.id, bobAddr, numReqConfs, 4,
-		lnwallet.DefaultDustLimit(), 0)
+		lnwallet.DefaultDustLimit(), 0, nil)
 	if err != nil {
 		t.Fatalf("unable to init channel reservation: %v", err)
 	}
@@ -879,7 +1052,7 @@ func testSingleFunderReservationWorkflowResponder(miner *rpctest.Harness,
 	assertReservationDeleted(chanReservation, t)
 }
 
-func testListTransactionDetails(miner *rpctest.Harness, wallet *lnwallet.LightningWallet, t *testing.T) {
+func testListTransactionDetails(miner chainHarness, wallet *lnwallet.LightningWallet, t *testing.T) {
 	t.Log("Running list transaction details test")
 
 	// Create 5 new outputs spendable by the wallet.
@@ -909,7 +1082,7 @@ func testListTransactionDetails(miner *rpctest.Harness, wallet *lnwallet.Lightni
 
 	// Generate 10 blocks to mine all the transactions created above.
 	const numBlocksMined = 10
-	blocks, err := miner.Node.Generate(numBlocksMined)
+	blocks, err := miner.Generate(numBlocksMined)
 	if err != nil {
 		t.Fatalf("unable to mine blocks: %v", err)
 	}
@@ -961,7 +1134,7 @@ func testListTransactionDetails(miner *rpctest.Harness, wallet *lnwallet.Lightni
 	if err != nil {
 		t.Fatalf("unable to create burn tx: %v", err)
 	}
-	burnBlock, err := miner.Node.Generate(1)
+	burnBlock, err := miner.Generate(1)
 	if err != nil {
 		t.Fatalf("unable to mine block: %v", err)
 	}
@@ -998,7 +1171,7 @@ func testListTransactionDetails(miner *rpctest.Harness, wallet *lnwallet.Lightni
 	}
 }
 
-func testTransactionSubscriptions(miner *rpctest.Harness, w *lnwallet.LightningWallet, t *testing.T) {
+func testTransactionSubscriptions(miner chainHarness, w *lnwallet.LightningWallet, t *testing.T) {
 	t.Log("Running transaction subscriptions test")
 
 	// First, check to see if this wallet meets the TransactionNotifier
@@ -1082,7 +1255,7 @@ func testTransactionSubscriptions(miner *rpctest.Harness, w *lnwallet.LightningW
 
 	// Next mine a single block, all the transactions generated above
 	// should be included.
-	if _, err := miner.Node.Generate(1); err != nil {
+	if _, err := miner.Generate(1); err != nil {
 		t.Fatalf("unable to generate block: %v", err)
 	}
 
@@ -1095,7 +1268,7 @@ func testTransactionSubscriptions(miner *rpctest.Harness, w *lnwallet.LightningW
 	}
 }
 
-func testSignOutputPrivateTweak(r *rpctest.Harness, w *lnwallet.LightningWallet, t *testing.T) {
+func testSignOutputPrivateTweak(r chainHarness, w *lnwallet.LightningWallet, t *testing.T) {
 	t.Logf("Running private tweak test")
 
 	// We'd like to test the ability of the wallet's Signer implementation
@@ -1198,19 +1371,99 @@ func testSignOutputPrivateTweak(r *rpctest.Harness, w *lnwallet.LightningWallet,
 	}
 }
 
-var walletTests = []func(miner *rpctest.Harness, w *lnwallet.LightningWallet, test *testing.T){
+var walletTests = []func(miner chainHarness, w *lnwallet.LightningWallet, test *testing.T){
 	// TODO(roasbeef): reservation tests should prob be split out
 	testDualFundingReservationWorkflow,
 	testSingleFunderReservationWorkflowInitiator,
 	testSingleFunderReservationWorkflowResponder,
 	testFundingTransactionLockedOutputs,
 	testFundingCancellationNotEnoughFunds,
+	testFundingUnconfirmedOutputs,
 	testTransactionSubscriptions,
 	testListTransactionDetails,
 	testSignOutputPrivateTweak,
 	testCancelNonExistantReservation,
 }
 
+// launchBitcoind starts a bitcoind node on the regression test network which
+// syncs the chain of the passed miner, and publishes new blocks and
+// transactions over ZMQ. The RPC config and ZMQ address of the node are
+// returned, along with a function which stops the node and removes its data.
+func launchBitcoind(miner *rpctest.Harness,
+	t *testing.T) (*btcrpcclient.ConnConfig, string, func()) {
+
+	tempBitcoindDir, err := ioutil.TempDir("", "bitcoind")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
+	}
+
+	rpcPort := rand.Intn(65000-1024) + 1024
+	zmqPath := fmt.Sprintf("tcp://127.0.0.1:%d", rpcPort+1)
+	bitcoind := exec.Command(
+		"bitcoind",
+		"-datadir="+tempBitcoindDir,
+		"-regtest",
+		"-txindex",
+		"-disablewallet",
+		"-whitelist=127.0.0.1",
+		"-connect="+miner.P2PAddress(),
+		"-rpcuser=weks",
+		"-rpcpassword=weks",
+		fmt.Sprintf("-rpcport=%d", rpcPort),
+		"-zmqpubhashblock="+zmqPath,
+		"-zmqpubrawtx="+zmqPath,
+		"-zmqpubrawblock="+zmqPath,
+	)
+	if err := bitcoind.Start(); err != nil {
+		os.RemoveAll(tempBitcoindDir)
+		t.Fatalf("unable to start bitcoind: %v", err)
+	}
+	cleanUp := func() {
+		bitcoind.Process.Kill()
+		bitcoind.Wait()
+		os.RemoveAll(tempBitcoindDir)
+	}
+
+	rpcConfig := &btcrpcclient.ConnConfig{
+		Host:         fmt.Sprintf("127.0.0.1:%d", rpcPort),
+		User:         "weks",
+		Pass:         "weks",
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}
+	rpcClient, err := btcrpcclient.New(rpcConfig, nil)
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create rpc client: %v", err)
+	}
+	defer rpcClient.Shutdown()
+
+	// Before handing the node off, we'll wait for it to sync up to the
+	// tip of the miner's chain.
+	_, minerHeight, err := miner.Node.GetBestBlock()
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to get miner's height: %v", err)
+	}
+	timeout := time.After(30 * time.Second)
+	for {
+		height, err := rpcClient.GetBlockCount()
+		if err == nil && height >= int64(minerHeight) {
+			break
+		}
+
+		select {
+		case <-timeout:
+			cleanUp()
+			t.Fatalf("bitcoind never synced to height %v",
+				minerHeight)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return rpcConfig, zmqPath, cleanUp
+}
+
 type testLnWallet struct {
 	lnwallet    *lnwallet.LightningWallet
 	cleanUpFunc func()
@@ -1222,6 +1475,33 @@ func clearWalletState(w *lnwallet.LightningWallet) error {
 	return w.ChannelDB.Wipe()
 }
 
+// setUpMiner creates a btcd node which will serve as the dedicated miner of
+// the wallets backed by a full node, with segwit and CSV activated.
+func setUpMiner(t *testing.T) *rpctest.Harness {
+	// Initialize the harness around a btcd node which will serve as our
+	// dedicated miner to generate blocks, cause re-orgs, etc. We'll set
+	// up this node with a chain length of 125, so we have plentyyy of BTC
+	// to play around with.
+	miningNode, err := rpctest.New(netParams, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create mining node: %v", err)
+	}
+	if err := miningNode.SetUp(true, 25); err != nil {
+		miningNode.TearDown()
+		t.Fatalf("unable to set up mining node: %v", err)
+	}
+
+	// Next mine enough blocks in order for segwit and the CSV package
+	// soft-fork to activate on RegTest.
+	numBlocks := netParams.MinerConfirmationWindow * 2
+	if _, err := miningNode.Node.Generate(numBlocks); err != nil {
+		miningNode.TearDown()
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+
+	return miningNode
+}
+
 // TestInterfaces tests all registered interfaces with a unified set of tests
 // which excersie each of the required methods found within the WalletController
 // interface.
@@ -1236,94 +1516,188 @@ func clearWalletState(w *lnwallet.LightningWallet) error {
 //
 // TODO(roasbeef): purge bobNode in favor of dual lnwallet's
 func TestLightningWallet(t *testing.T) {
-	netParams := &chaincfg.SimNetParams
+	// The btcd miner is only set up once a driver requiring a full node
+	// is tested, so the drivers backed by the simulated chain can be
+	// tested without one.
+	var miningNode *rpctest.Harness
+	getMiner := func() *rpctest.Harness {
+		if miningNode == nil {
+			miningNode = setUpMiner(t)
+		}
+		return miningNode
+	}
+	defer func() {
+		if miningNode != nil {
+			miningNode.TearDown()
+		}
+	}()
 
-	// Initialize the harness around a btcd node which will serve as our
-	// dedicated miner to generate blocks, cause re-orgs, etc. We'll set
-	// up this node with a chain length of 125, so we have plentyyy of BTC
-	// to play around with.
-	miningNode, err := rpctest.New(netParams, nil, nil)
+	for _, walletDriver := range lnwallet.RegisteredWallets() {
+		walletType := walletDriver.WalletType
+		switch walletType {
+		case "btcwallet":
+			// The btcwallet driver can be backed by either a btcd
+			// or bitcoind node, so we'll test it with each.
+			runBtcdWalletTests(t, walletDriver, getMiner())
+
+			if _, err := exec.LookPath("bitcoind"); err != nil {
+				t.Logf("bitcoind not found, skipping %v backed "+
+					"by bitcoind", walletType)
+				continue
+			}
+			runBitcoindWalletTests(t, walletDriver, getMiner())
+		case "simchain":
+			runSimchainWalletTests(t, walletDriver)
+		default:
+			t.Fatalf("unknown wallet driver: %v", walletType)
+		}
+	}
+}
+
+// runBtcdWalletTests executes the wallet test suite against an instance of
+// the btcwallet driver backed by the btcd miner.
+func runBtcdWalletTests(t *testing.T, walletDriver *lnwallet.WalletDriver,
+	miningNode *rpctest.Harness) {
+
+	rpcConfig := miningNode.RPCConfig()
+
+	chainNotifier, err := btcdnotify.New(&rpcConfig)
 	if err != nil {
-		t.Fatalf("unable to create mining node: %v", err)
+		t.Fatalf("unable to create notifier: %v", err)
 	}
-	defer miningNode.TearDown()
-	if err := miningNode.SetUp(true, 25); err != nil {
-		t.Fatalf("unable to set up mining node: %v", err)
+	if err := chainNotifier.Start(); err != nil {
+		t.Fatalf("unable to start notifier: %v", err)
 	}
+	defer chainNotifier.Stop()
 
-	// Next mine enough blocks in order for segwit and the CSV package
-	// soft-fork to activate on SimNet.
-	numBlocks := netParams.MinerConfirmationWindow * 2
-	if _, err := miningNode.Node.Generate(numBlocks); err != nil {
-		t.Fatalf("unable to generate blocks: %v", err)
+	tempTestDir, err := ioutil.TempDir("", "lnwallet")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
 	}
+	defer os.RemoveAll(tempTestDir)
 
-	rpcConfig := miningNode.RPCConfig()
+	btcwalletConfig := &btcwallet.Config{
+		PrivatePass: privPass,
+		HdSeed:      testHdSeed[:],
+		DataDir:     tempTestDir,
+		NetParams:   netParams,
+		RPCHost:     rpcConfig.Host,
+		RPCUser:     rpcConfig.User,
+		RPCPass:     rpcConfig.Pass,
+		CACert:      rpcConfig.Certificates,
+	}
+	wc, err := walletDriver.New(btcwalletConfig)
+	if err != nil {
+		t.Fatalf("unable to create btcwallet: %v", err)
+	}
+	btcWallet := wc.(*btcwallet.BtcWallet)
 
-	chainNotifier, err := btcdnotify.New(&rpcConfig)
+	runWalletTests(t, tempTestDir, &btcdHarness{miningNode},
+		chainNotifier, wc, btcWallet, btcWallet)
+}
+
+// runBitcoindWalletTests executes the wallet test suite against an instance
+// of the btcwallet driver backed by a bitcoind node synced to the btcd miner.
+func runBitcoindWalletTests(t *testing.T, walletDriver *lnwallet.WalletDriver,
+	miningNode *rpctest.Harness) {
+
+	bitcoindConfig, zmqPath, cleanUp := launchBitcoind(miningNode, t)
+	defer cleanUp()
+
+	chainNotifier, err := bitcoindnotify.New(bitcoindConfig, zmqPath)
 	if err != nil {
 		t.Fatalf("unable to create notifier: %v", err)
 	}
 	if err := chainNotifier.Start(); err != nil {
 		t.Fatalf("unable to start notifier: %v", err)
 	}
+	defer chainNotifier.Stop()
 
-	var bio lnwallet.BlockChainIO
-	var signer lnwallet.Signer
-	var wc lnwallet.WalletController
-	for _, walletDriver := range lnwallet.RegisteredWallets() {
-		tempTestDir, err := ioutil.TempDir("", "lnwallet")
-		if err != nil {
-			t.Fatalf("unable to create temp directory: %v", err)
-		}
-		defer os.RemoveAll(tempTestDir)
+	tempTestDir, err := ioutil.TempDir("", "lnwallet")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempTestDir)
 
-		walletType := walletDriver.WalletType
-		switch walletType {
-		case "btcwallet":
-			btcwalletConfig := &btcwallet.Config{
-				PrivatePass: privPass,
-				HdSeed:      testHdSeed[:],
-				DataDir:     tempTestDir,
-				NetParams:   netParams,
-				RPCHost:     rpcConfig.Host,
-				RPCUser:     rpcConfig.User,
-				RPCPass:     rpcConfig.Pass,
-				CACert:      rpcConfig.Certificates,
-			}
-			wc, err = walletDriver.New(btcwalletConfig)
-			if err != nil {
-				t.Fatalf("unable to create btcwallet: %v", err)
-			}
-			signer = wc.(*btcwallet.BtcWallet)
-			bio = wc.(*btcwallet.BtcWallet)
-		default:
-			t.Fatalf("unknown wallet driver: %v", walletType)
-		}
+	chainConn, err := chain.NewBitcoindClient(netParams,
+		bitcoindConfig.Host, bitcoindConfig.User, bitcoindConfig.Pass,
+		zmqPath, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unable to create bitcoind client: %v", err)
+	}
 
-		// Funding via 20 outputs with 4BTC each.
-		lnw, err := createTestWallet(tempTestDir, miningNode, netParams,
-			chainNotifier, wc, signer, bio)
-		if err != nil {
-			t.Fatalf("unable to create test ln wallet: %v", err)
-		}
+	btcwalletConfig := &btcwallet.Config{
+		PrivatePass: privPass,
+		HdSeed:      testHdSeed[:],
+		DataDir:     tempTestDir,
+		NetParams:   netParams,
+		ChainSource: chainConn,
+	}
+	wc, err := walletDriver.New(btcwalletConfig)
+	if err != nil {
+		t.Fatalf("unable to create btcwallet: %v", err)
+	}
+	btcWallet := wc.(*btcwallet.BtcWallet)
 
-		// The wallet should now have 80BTC available for spending.
-		assertProperBalance(t, lnw, 1, 80)
+	runWalletTests(t, tempTestDir, &btcdHarness{miningNode},
+		chainNotifier, wc, btcWallet, btcWallet)
+}
 
-		// Execute every test, clearing possibly mutated wallet state after
-		// each step.
-		for _, walletTest := range walletTests {
-			walletTest(miningNode, lnw, t)
+// runSimchainWalletTests executes the wallet test suite against an instance
+// of the simchain driver, using the simulated chain it's created on top of as
+// the chain notifier and miner.
+func runSimchainWalletTests(t *testing.T,
+	walletDriver *lnwallet.WalletDriver) {
 
-			// TODO(roasbeef): possible reset mining node's chainstate to
-			// initial level, cleanly wipe buckets
-			if err := clearWalletState(lnw); err != nil &&
-				err != bolt.ErrBucketNotFound {
-				t.Fatalf("unable to wipe wallet state: %v", err)
-			}
-		}
+	simChain := simchain.New(netParams)
+	if err := simChain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+	defer simChain.Stop()
+
+	tempTestDir, err := ioutil.TempDir("", "lnwallet")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempTestDir)
+
+	wc, err := walletDriver.New(simChain, testHdSeed[:])
+	if err != nil {
+		t.Fatalf("unable to create simchain wallet: %v", err)
+	}
+	simWallet := wc.(*simchain.Wallet)
+
+	runWalletTests(t, tempTestDir, simChain, simChain, wc, simWallet,
+		simWallet)
+}
+
+// runWalletTests executes every wallet test against a LightningWallet backed
+// by the passed WalletController.
+func runWalletTests(t *testing.T, tempTestDir string, miner chainHarness,
+	chainNotifier chainntnfs.ChainNotifier, wc lnwallet.WalletController,
+	signer lnwallet.Signer, bio lnwallet.BlockChainIO) {
 
-		lnw.Shutdown()
+	// Funding via 20 outputs with 4BTC each.
+	lnw, err := createTestWallet(tempTestDir, miner, netParams,
+		chainNotifier, wc, signer, bio)
+	if err != nil {
+		t.Fatalf("unable to create test ln wallet: %v", err)
+	}
+	defer lnw.Shutdown()
+
+	// The wallet should now have 80BTC available for spending.
+	assertProperBalance(t, lnw, 1, 80)
+
+	// Execute every test, clearing possibly mutated wallet state after
+	// each step.
+	for _, walletTest := range walletTests {
+		walletTest(miner, lnw, t)
+
+		// TODO(roasbeef): possible reset mining node's chainstate to
+		// initial level, cleanly wipe buckets
+		if err := clearWalletState(lnw); err != nil &&
+			err != bolt.ErrBucketNotFound {
+			t.Fatalf("unable to wipe wallet state: %v", err)
+		}
 	}
 }
@@ -10,3 +10,25 @@ import (
 func DefaultDustLimit() btcutil.Amount {
 	return txrules.GetDustThreshold(P2WSHSize, txrules.DefaultRelayFeePerKb)
 }
+
+// DefaultMaxAcceptedHTLCs is the default number of outstanding HTLCs we'll
+// allow the remote party to offer us at any given time. The limit is half of
+// MaxHTLCNumber, which ensures that even if both parties max out their
+// allotment, the commitment transaction remains sweepable within a single
+// penalty transaction.
+const DefaultMaxAcceptedHTLCs = MaxHTLCNumber / 2
+
+// DefaultChanReserve returns the default channel reserve we'll require the
+// remote party to maintain within a channel of the target capacity. The
+// reserve ensures the remote party always has funds at stake, giving them an
+// incentive not to broadcast a revoked state.
+func DefaultChanReserve(capacity btcutil.Amount) btcutil.Amount {
+	return capacity / 100
+}
+
+// DefaultMaxPendingAmount returns the default maximum total value of
+// outstanding HTLCs we'll allow the remote party to offer us within a channel
+// of the target capacity.
+func DefaultMaxPendingAmount(capacity btcutil.Amount) btcutil.Amount {
+	return capacity - DefaultChanReserve(capacity)
+}
@@ -0,0 +1,156 @@
+package remotesigner
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcd/btcec"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// KeyRing is an implementation of the keychain.ECDHRing interface which
+// derives keys, and performs ECDH with them, using the key ring of a Server
+// reached over gRPC. This allows a node to derive its keys, and the secrets
+// computed from them, without ever holding their private keys. The number of
+// keys derived within each family is persisted locally, so the Server needn't
+// track the keys derived by the node.
+type KeyRing struct {
+	client lnrpc.SignerClient
+
+	store keychain.IndexStore
+
+	// pubKeys maps the serialized public key of each key derived by the
+	// key ring to its locator, allowing the locator to be sent along with
+	// each request to sign with the key.
+	pubKeys map[[33]byte]keychain.KeyLocator
+	sync.RWMutex
+}
+
+// A compile time check to ensure that KeyRing implements the
+// keychain.ECDHRing interface.
+var _ keychain.ECDHRing = (*KeyRing)(nil)
+
+// NewKeyRing creates a new KeyRing which derives keys using the Signer
+// service reached over the passed connection, persisting the next index of
+// each key family to the passed store. All keys previously derived by the key
+// ring are re-derived so they can be located.
+func NewKeyRing(conn *grpc.ClientConn,
+	store keychain.IndexStore) (*KeyRing, error) {
+
+	k := &KeyRing{
+		client:  lnrpc.NewSignerClient(conn),
+		store:   store,
+		pubKeys: make(map[[33]byte]keychain.KeyLocator),
+	}
+
+	keyFam := keychain.KeyFamilyMultiSig
+	for ; keyFam <= keychain.LastKeyFamily; keyFam++ {
+		numKeys, err := store.FetchKeyIndex(keyFam)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint32(0); i < numKeys; i++ {
+			_, err := k.DeriveKey(keychain.KeyLocator{
+				Family: keyFam,
+				Index:  i,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return k, nil
+}
+
+// DeriveNextKey attempts to derive the *next* key within the key family
+// specified.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (k *KeyRing) DeriveNextKey(
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	index, err := k.store.NextKeyIndex(keyFam)
+	if err != nil {
+		return keychain.KeyDescriptor{}, err
+	}
+
+	return k.DeriveKey(keychain.KeyLocator{Family: keyFam, Index: index})
+}
+
+// DeriveKey attempts to derive an arbitrary key specified by the passed
+// KeyLocator.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (k *KeyRing) DeriveKey(
+	keyLoc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	resp, err := k.client.DeriveKey(context.Background(), &lnrpc.KeyLocator{
+		KeyFamily: uint32(keyLoc.Family),
+		KeyIndex:  keyLoc.Index,
+	})
+	if err != nil {
+		return keychain.KeyDescriptor{}, err
+	}
+	pubKey, err := btcec.ParsePubKey(resp.PubKey, btcec.S256())
+	if err != nil {
+		return keychain.KeyDescriptor{}, err
+	}
+
+	var serializedKey [33]byte
+	copy(serializedKey[:], pubKey.SerializeCompressed())
+
+	k.Lock()
+	k.pubKeys[serializedKey] = keyLoc
+	k.Unlock()
+
+	return keychain.KeyDescriptor{
+		KeyLocator: keyLoc,
+		PubKey:     pubKey,
+	}, nil
+}
+
+// LocateKey returns the KeyLocator of the passed public key, which must have
+// been derived by the key ring.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (k *KeyRing) LocateKey(
+	pubKey *btcec.PublicKey) (keychain.KeyLocator, error) {
+
+	var serializedKey [33]byte
+	copy(serializedKey[:], pubKey.SerializeCompressed())
+
+	k.RLock()
+	keyLoc, ok := k.pubKeys[serializedKey]
+	k.RUnlock()
+	if !ok {
+		return keychain.KeyLocator{}, keychain.ErrUnknownKey
+	}
+
+	return keyLoc, nil
+}
+
+// ECDH returns the point shared between the private key identified by the
+// passed KeyLocator and the passed public key.
+//
+// NOTE: This is part of the keychain.ECDHRing interface.
+func (k *KeyRing) ECDH(keyLoc keychain.KeyLocator,
+	pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	req := &lnrpc.SharedKeyReq{
+		KeyLoc: &lnrpc.KeyLocator{
+			KeyFamily: uint32(keyLoc.Family),
+			KeyIndex:  keyLoc.Index,
+		},
+		EphemeralPubkey: pubKey.SerializeCompressed(),
+	}
+	resp, err := k.client.DeriveSharedKey(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(resp.SharedPoint, btcec.S256())
+}
@@ -0,0 +1,575 @@
+package remotesigner
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/hdkeychain"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// newTestKeyRing creates a new key ring from a fixed seed, which hasn't yet
+// derived any keys.
+func newTestKeyRing(t *testing.T) keychain.SecretKeyRing {
+	seed := bytes.Repeat([]byte{0x01}, 32)
+	root, err := hdkeychain.NewMaster(seed, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create root key: %v", err)
+	}
+
+	store := keychain.NewMemIndexStore()
+	keyRing, err := keychain.NewHDKeyRing(root, store)
+	if err != nil {
+		t.Fatalf("unable to create key ring: %v", err)
+	}
+
+	return keyRing
+}
+
+// mockSigner is a local signer which signs using a single private key, along
+// with any key derived by its key ring.
+type mockSigner struct {
+	key     *btcec.PrivateKey
+	keyRing keychain.SecretKeyRing
+}
+
+// fetchKey returns the private key of the passed public key.
+func (m *mockSigner) fetchKey(pubKey *btcec.PublicKey) (*btcec.PrivateKey, error) {
+	if pubKey.IsEqual(m.key.PubKey()) {
+		return m.key, nil
+	}
+	if m.keyRing == nil {
+		return nil, fmt.Errorf("unknown public key")
+	}
+
+	return m.keyRing.FetchPrivKey(pubKey)
+}
+
+func (m *mockSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	key, err := m.fetchKey(signDesc.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := txscript.RawTxInWitnessSignature(tx, signDesc.SigHashes,
+		signDesc.InputIndex, signDesc.Output.Value,
+		signDesc.WitnessScript, signDesc.HashType, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig[:len(sig)-1], nil
+}
+
+func (m *mockSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	witness, err := txscript.WitnessScript(tx, signDesc.SigHashes,
+		signDesc.InputIndex, signDesc.Output.Value,
+		signDesc.Output.PkScript, signDesc.HashType, m.key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwallet.InputScript{Witness: witness}, nil
+}
+
+func (m *mockSigner) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	key, err := m.fetchKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Sign(chainhash.DoubleHashB(msg))
+}
+
+// writeTestCert creates a self-signed certificate valid for the loopback
+// address, writing it and its key to the passed directory.
+func writeTestCert(t *testing.T, dir, name string) (string, string) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage: x509.KeyUsageDigitalSignature |
+			x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth,
+		},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, &template,
+		&template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("unable to serialize key: %v", err)
+	}
+
+	certPath := filepath.Join(dir, name+".cert")
+	keyPath := filepath.Join(dir, name+".key")
+	err = ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: certBytes,
+	}), 0600)
+	if err != nil {
+		t.Fatalf("unable to write certificate: %v", err)
+	}
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type: "EC PRIVATE KEY", Bytes: keyBytes,
+	}), 0600)
+	if err != nil {
+		t.Fatalf("unable to write key: %v", err)
+	}
+
+	return certPath, keyPath
+}
+
+// signerHarness is a signer service running on a local port, along with the
+// certificates of the service and its trusted client.
+type signerHarness struct {
+	t *testing.T
+
+	addr string
+
+	serverCert string
+	clientCert string
+	clientKey  string
+
+	cleanUp func()
+}
+
+// startSigner runs a signer service backed by the passed signer and key ring
+// on a local port, serving only clients which authenticate with the trusted
+// client certificate.
+func startSigner(t *testing.T, signer *mockSigner,
+	keyRing keychain.ECDHRing) *signerHarness {
+
+	tempDir, err := ioutil.TempDir("", "remotesigner")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	serverCert, serverKey := writeTestCert(t, tempDir, "server")
+	clientCert, clientKey := writeTestCert(t, tempDir, "client")
+
+	creds, err := ServerCredentials(serverCert, serverKey, clientCert)
+	if err != nil {
+		t.Fatalf("unable to load server credentials: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	lnrpc.RegisterSignerServer(grpcServer, NewServer(signer, signer,
+		keyRing))
+	go grpcServer.Serve(lis)
+
+	return &signerHarness{
+		t:          t,
+		addr:       lis.Addr().String(),
+		serverCert: serverCert,
+		clientCert: clientCert,
+		clientKey:  clientKey,
+		cleanUp: func() {
+			grpcServer.Stop()
+			os.RemoveAll(tempDir)
+		},
+	}
+}
+
+// dial returns a connection to the signer service authenticated using the
+// passed client certificate.
+func (h *signerHarness) dial(certPath, keyPath string) *grpc.ClientConn {
+	creds, err := ClientCredentials(certPath, keyPath, h.serverCert)
+	if err != nil {
+		h.t.Fatalf("unable to load client credentials: %v", err)
+	}
+	conn, err := grpc.Dial(h.addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		h.t.Fatalf("unable to dial signer: %v", err)
+	}
+
+	return conn
+}
+
+// connect returns a RemoteSigner connected to the signer service using the
+// passed client certificate, which locates keys using the passed key ring.
+func (h *signerHarness) connect(certPath, keyPath string,
+	keyRing keychain.KeyRing) (*RemoteSigner, func()) {
+
+	conn := h.dial(certPath, keyPath)
+
+	return New(conn, keyRing), func() { conn.Close() }
+}
+
+// startTrustedSigner runs a signer service backed by the passed signer,
+// returning a RemoteSigner authenticated with it, along with a function
+// which tears down both ends of the connection.
+func startTrustedSigner(t *testing.T, signer *mockSigner) (*RemoteSigner,
+	func()) {
+
+	harness := startSigner(t, signer, newTestKeyRing(t))
+	remoteSigner, closeConn := harness.connect(harness.clientCert,
+		harness.clientKey, newTestKeyRing(t))
+
+	return remoteSigner, func() {
+		closeConn()
+		harness.cleanUp()
+	}
+}
+
+// newSpendTx creates a transaction spending the output at index 0 of a
+// transaction paying to the passed output script.
+func newSpendTx(pkScript []byte, amt int64) *wire.MsgTx {
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxOut(wire.NewTxOut(amt, pkScript))
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+		Hash:  prevTx.TxHash(),
+		Index: 0,
+	}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(amt-1000, pkScript))
+
+	return tx
+}
+
+// verifySpend checks that the passed transaction validly spends the passed
+// output.
+func verifySpend(t *testing.T, tx *wire.MsgTx, output *wire.TxOut) {
+	vm, err := txscript.NewEngine(output.PkScript, tx, 0,
+		txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(tx),
+		output.Value)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("invalid spend: %v", err)
+	}
+}
+
+// TestRemoteSignOutputRaw tests that a signature generated by the remote
+// signer for a p2wsh output is valid, and matches that of the local signer.
+func TestRemoteSignOutputRaw(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	signer := &mockSigner{key: key}
+	remoteSigner, cleanUp := startTrustedSigner(t, signer)
+	defer cleanUp()
+
+	// We'll spend a p2wsh output whose witness script requires a
+	// signature of our key.
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(key.PubKey().SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIG)
+	witnessScript, err := builder.Script()
+	if err != nil {
+		t.Fatalf("unable to create witness script: %v", err)
+	}
+	scriptHash := sha256.Sum256(witnessScript)
+	builder = txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_0)
+	builder.AddData(scriptHash[:])
+	pkScript, err := builder.Script()
+	if err != nil {
+		t.Fatalf("unable to create output script: %v", err)
+	}
+
+	output := wire.NewTxOut(btcutil.SatoshiPerBitcoin, pkScript)
+	tx := newSpendTx(pkScript, output.Value)
+	signDesc := &lnwallet.SignDescriptor{
+		PubKey:        key.PubKey(),
+		WitnessScript: witnessScript,
+		Output:        output,
+		HashType:      txscript.SigHashAll,
+		SigHashes:     txscript.NewTxSigHashes(tx),
+		InputIndex:    0,
+	}
+
+	sig, err := remoteSigner.SignOutputRaw(tx, signDesc)
+	if err != nil {
+		t.Fatalf("unable to sign remotely: %v", err)
+	}
+	localSig, err := signer.SignOutputRaw(tx, signDesc)
+	if err != nil {
+		t.Fatalf("unable to sign locally: %v", err)
+	}
+	if !bytes.Equal(sig, localSig) {
+		t.Fatalf("remote signature %x doesn't match local "+
+			"signature %x", sig, localSig)
+	}
+
+	tx.TxIn[0].Witness = wire.TxWitness{
+		append(sig, byte(txscript.SigHashAll)), witnessScript,
+	}
+	verifySpend(t, tx, output)
+
+	// Signing with a key unknown to the remote signer should fail.
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	signDesc.PubKey = otherKey.PubKey()
+	if _, err := remoteSigner.SignOutputRaw(tx, signDesc); err == nil {
+		t.Fatalf("signed with unknown key")
+	}
+}
+
+// TestRemoteComputeInputScript tests that the remote signer generates a
+// valid witness spending a p2wkh output.
+func TestRemoteComputeInputScript(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	remoteSigner, cleanUp := startTrustedSigner(t, &mockSigner{key: key})
+	defer cleanUp()
+
+	pubKeyHash := btcutil.Hash160(key.PubKey().SerializeCompressed())
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_0)
+	builder.AddData(pubKeyHash)
+	pkScript, err := builder.Script()
+	if err != nil {
+		t.Fatalf("unable to create output script: %v", err)
+	}
+
+	output := wire.NewTxOut(btcutil.SatoshiPerBitcoin, pkScript)
+	tx := newSpendTx(pkScript, output.Value)
+	signDesc := &lnwallet.SignDescriptor{
+		Output:     output,
+		HashType:   txscript.SigHashAll,
+		SigHashes:  txscript.NewTxSigHashes(tx),
+		InputIndex: 0,
+	}
+
+	inputScript, err := remoteSigner.ComputeInputScript(tx, signDesc)
+	if err != nil {
+		t.Fatalf("unable to compute input script remotely: %v", err)
+	}
+
+	tx.TxIn[0].Witness = inputScript.Witness
+	tx.TxIn[0].SignatureScript = inputScript.ScriptSig
+	verifySpend(t, tx, output)
+}
+
+// TestRemoteSignMessage tests that the remote signer signs the double-sha256
+// digest of a message.
+func TestRemoteSignMessage(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	remoteSigner, cleanUp := startTrustedSigner(t, &mockSigner{key: key})
+	defer cleanUp()
+
+	msg := []byte("channel announcement")
+	sig, err := remoteSigner.SignMessage(key.PubKey(), msg)
+	if err != nil {
+		t.Fatalf("unable to sign message remotely: %v", err)
+	}
+	if !sig.Verify(chainhash.DoubleHashB(msg), key.PubKey()) {
+		t.Fatalf("invalid message signature")
+	}
+}
+
+// TestRemoteSignKeyRingKey tests that signing with a key derived by the
+// requesting node's key ring is routed to the remote signer, which re-derives
+// the key from its locator, and that a locator not matching its public key is
+// rejected.
+func TestRemoteSignKeyRingKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	// The signer's key ring shares its root with the client's, but hasn't
+	// derived any keys, so it's only able to sign with a key once it's
+	// been located by the client.
+	signerKeyRing := newTestKeyRing(t)
+	signer := &mockSigner{key: key, keyRing: signerKeyRing}
+	harness := startSigner(t, signer, signerKeyRing)
+	defer harness.cleanUp()
+
+	clientKeyRing := newTestKeyRing(t)
+	remoteSigner, closeConn := harness.connect(harness.clientCert,
+		harness.clientKey, clientKeyRing)
+	defer closeConn()
+
+	keyDesc, err := clientKeyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamilyMultiSig,
+		Index:  5,
+	})
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+
+	msg := []byte("funding locked")
+	sig, err := remoteSigner.SignMessage(keyDesc.PubKey, msg)
+	if err != nil {
+		t.Fatalf("unable to sign message remotely: %v", err)
+	}
+	if !sig.Verify(chainhash.DoubleHashB(msg), keyDesc.PubKey) {
+		t.Fatalf("invalid message signature")
+	}
+
+	// A request locating a different key than the one requested should
+	// be rejected, rather than signing with the located key.
+	server := NewServer(signer, signer, signerKeyRing)
+	_, err = server.SignMessage(context.Background(), &lnrpc.SignMessageReq{
+		PubKey: key.PubKey().SerializeCompressed(),
+		Msg:    msg,
+		KeyLoc: &lnrpc.KeyLocator{
+			KeyFamily: uint32(keychain.KeyFamilyMultiSig),
+			KeyIndex:  5,
+		},
+	})
+	if err == nil {
+		t.Fatalf("signed with mismatched key locator")
+	}
+}
+
+// TestRemoteKeyRing tests that a KeyRing backed by the signer service derives
+// the same keys, and computes the same shared points, as the signer's own key
+// ring, and that the keys it derived are located again once it's recreated.
+func TestRemoteKeyRing(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	signerKeyRing := newTestKeyRing(t)
+	harness := startSigner(t, &mockSigner{key: key}, signerKeyRing)
+	defer harness.cleanUp()
+
+	conn := harness.dial(harness.clientCert, harness.clientKey)
+	defer conn.Close()
+
+	store := keychain.NewMemIndexStore()
+	keyRing, err := NewKeyRing(conn, store)
+	if err != nil {
+		t.Fatalf("unable to create remote key ring: %v", err)
+	}
+
+	keyDesc, err := keyRing.DeriveNextKey(keychain.KeyFamilyRevocationRoot)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	localDesc, err := signerKeyRing.DeriveKey(keyDesc.KeyLocator)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if !keyDesc.PubKey.IsEqual(localDesc.PubKey) {
+		t.Fatalf("remote key ring derived %x, expected %x",
+			keyDesc.PubKey.SerializeCompressed(),
+			localDesc.PubKey.SerializeCompressed())
+	}
+
+	sharedPoint, err := keyRing.ECDH(keyDesc.KeyLocator, key.PubKey())
+	if err != nil {
+		t.Fatalf("unable to perform ECDH: %v", err)
+	}
+	localPoint, err := signerKeyRing.ECDH(keyDesc.KeyLocator, key.PubKey())
+	if err != nil {
+		t.Fatalf("unable to perform ECDH: %v", err)
+	}
+	if !sharedPoint.IsEqual(localPoint) {
+		t.Fatalf("remote key ring computed shared point %x, "+
+			"expected %x", sharedPoint.SerializeCompressed(),
+			localPoint.SerializeCompressed())
+	}
+
+	// A key ring recreated from the same store should locate the key
+	// without it being derived again.
+	keyRing, err = NewKeyRing(conn, store)
+	if err != nil {
+		t.Fatalf("unable to create remote key ring: %v", err)
+	}
+	keyLoc, err := keyRing.LocateKey(keyDesc.PubKey)
+	if err != nil {
+		t.Fatalf("unable to locate key: %v", err)
+	}
+	if keyLoc != keyDesc.KeyLocator {
+		t.Fatalf("located key at %v, expected %v", keyLoc,
+			keyDesc.KeyLocator)
+	}
+}
+
+// TestRemoteSignerRequiresClientCert tests that the signer service refuses
+// clients which don't authenticate with a trusted certificate.
+func TestRemoteSignerRequiresClientCert(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	harness := startSigner(t, &mockSigner{key: key}, newTestKeyRing(t))
+	defer harness.cleanUp()
+
+	tempDir, err := ioutil.TempDir("", "remotesigner")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	certPath, keyPath := writeTestCert(t, tempDir, "untrusted")
+
+	remoteSigner, closeConn := harness.connect(certPath, keyPath,
+		newTestKeyRing(t))
+	defer closeConn()
+
+	_, err = remoteSigner.SignMessage(key.PubKey(), []byte("msg"))
+	if err == nil {
+		t.Fatalf("signed for untrusted client")
+	}
+}
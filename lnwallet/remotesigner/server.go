@@ -0,0 +1,164 @@
+package remotesigner
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"golang.org/x/net/context"
+)
+
+// Server is an implementation of the lnrpc.SignerServer interface which
+// exposes a local Signer and MessageSigner over gRPC. A node holding the
+// private keys runs the Server, allowing a node without them to sign using a
+// RemoteSigner. Keys derived by the remote node's key ring are re-derived by
+// the Server's key ring from the locators sent along with each request, so
+// both key rings must share the same root key. The Server's key ring also
+// derives the public keys of, and performs ECDH for, a remote node which has
+// no key ring of its own.
+type Server struct {
+	signer    lnwallet.Signer
+	msgSigner lnwallet.MessageSigner
+	keyRing   keychain.ECDHRing
+}
+
+// A compile time check to ensure that Server implements the
+// lnrpc.SignerServer interface.
+var _ lnrpc.SignerServer = (*Server)(nil)
+
+// NewServer creates a new Server which signs using the passed signers, which
+// must be able to sign with any key derived by the passed key ring.
+func NewServer(signer lnwallet.Signer, msgSigner lnwallet.MessageSigner,
+	keyRing keychain.ECDHRing) *Server {
+
+	return &Server{
+		signer:    signer,
+		msgSigner: msgSigner,
+		keyRing:   keyRing,
+	}
+}
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the passed SignDescriptor.
+//
+// NOTE: This is part of the lnrpc.SignerServer interface.
+func (s *Server) SignOutputRaw(ctx context.Context,
+	req *lnrpc.SignReq) (*lnrpc.SignResp, error) {
+
+	tx, signDesc, err := parseSignReq(req, s.keyRing)
+	if err != nil {
+		return nil, err
+	}
+	if signDesc.PubKey == nil {
+		return nil, fmt.Errorf("public key must be set")
+	}
+
+	sig, err := s.signer.SignOutputRaw(tx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SignResp{Sig: sig}, nil
+}
+
+// ComputeInputScript generates the witness, and for nested p2sh outputs the
+// sigScript, spending an input of the passed transaction as described by the
+// passed SignDescriptor.
+//
+// NOTE: This is part of the lnrpc.SignerServer interface.
+func (s *Server) ComputeInputScript(ctx context.Context,
+	req *lnrpc.SignReq) (*lnrpc.InputScriptResp, error) {
+
+	tx, signDesc, err := parseSignReq(req, s.keyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	inputScript, err := s.signer.ComputeInputScript(tx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+	if inputScript == nil {
+		return nil, fmt.Errorf("unable to compute input script for "+
+			"output script %x", signDesc.Output.PkScript)
+	}
+
+	return &lnrpc.InputScriptResp{
+		Witness:   inputScript.Witness,
+		SigScript: inputScript.ScriptSig,
+	}, nil
+}
+
+// SignMessage signs the double-sha256 digest of the passed message under the
+// passed public key.
+//
+// NOTE: This is part of the lnrpc.SignerServer interface.
+func (s *Server) SignMessage(ctx context.Context,
+	req *lnrpc.SignMessageReq) (*lnrpc.SignMessageResp, error) {
+
+	pubKey, err := btcec.ParsePubKey(req.PubKey, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	if req.KeyLoc != nil {
+		if err := deriveKey(s.keyRing, req.KeyLoc, pubKey); err != nil {
+			return nil, err
+		}
+	}
+
+	sig, err := s.msgSigner.SignMessage(pubKey, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SignMessageResp{Signature: sig.Serialize()}, nil
+}
+
+// DeriveKey derives the public key identified by the passed locator.
+//
+// NOTE: This is part of the lnrpc.SignerServer interface.
+func (s *Server) DeriveKey(ctx context.Context,
+	req *lnrpc.KeyLocator) (*lnrpc.DeriveKeyResp, error) {
+
+	keyDesc, err := s.keyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamily(req.KeyFamily),
+		Index:  req.KeyIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeriveKeyResp{
+		PubKey: keyDesc.PubKey.SerializeCompressed(),
+	}, nil
+}
+
+// DeriveSharedKey returns the point shared between the private key identified
+// by the passed locator and the passed public key.
+//
+// NOTE: This is part of the lnrpc.SignerServer interface.
+func (s *Server) DeriveSharedKey(ctx context.Context,
+	req *lnrpc.SharedKeyReq) (*lnrpc.SharedKeyResp, error) {
+
+	if req.KeyLoc == nil {
+		return nil, fmt.Errorf("key locator must be set")
+	}
+	pubKey, err := btcec.ParsePubKey(req.EphemeralPubkey, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	sharedPoint, err := s.keyRing.ECDH(keychain.KeyLocator{
+		Family: keychain.KeyFamily(req.KeyLoc.KeyFamily),
+		Index:  req.KeyLoc.KeyIndex,
+	}, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SharedKeyResp{
+		SharedPoint: sharedPoint.SerializeCompressed(),
+	}, nil
+}
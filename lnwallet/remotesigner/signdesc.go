@@ -0,0 +1,145 @@
+package remotesigner
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// newSignReq serializes the passed transaction and SignDescriptor into a
+// request to sign an input of the transaction. The descriptor's sighash
+// midstate isn't transmitted, as the signer recomputes it from the
+// transaction. If the key to sign with was derived by the passed key ring,
+// then its locator is included, allowing the signer to re-derive it.
+func newSignReq(tx *wire.MsgTx, signDesc *lnwallet.SignDescriptor,
+	keyRing keychain.KeyRing) (*lnrpc.SignReq, error) {
+
+	var rawTx bytes.Buffer
+	if err := tx.Serialize(&rawTx); err != nil {
+		return nil, err
+	}
+
+	rpcDesc := &lnrpc.SignDescriptor{
+		PrivateTweak:  signDesc.PrivateTweak,
+		WitnessScript: signDesc.WitnessScript,
+		Sighash:       uint32(signDesc.HashType),
+		InputIndex:    int32(signDesc.InputIndex),
+	}
+	if signDesc.PubKey != nil {
+		rpcDesc.PubKey = signDesc.PubKey.SerializeCompressed()
+		rpcDesc.KeyLoc = locateKey(keyRing, signDesc.PubKey)
+	}
+	if signDesc.Output != nil {
+		rpcDesc.Output = &lnrpc.TxOut{
+			Value:    signDesc.Output.Value,
+			PkScript: signDesc.Output.PkScript,
+		}
+	}
+
+	return &lnrpc.SignReq{
+		RawTxBytes: rawTx.Bytes(),
+		SignDesc:   rpcDesc,
+	}, nil
+}
+
+// parseSignReq deserializes the transaction and SignDescriptor within the
+// passed request to sign an input of the transaction. If the request locates
+// the key to sign with, then the key is re-derived by the passed key ring so
+// it's able to be signed with.
+func parseSignReq(req *lnrpc.SignReq,
+	keyRing keychain.KeyRing) (*wire.MsgTx, *lnwallet.SignDescriptor, error) {
+
+	if req.SignDesc == nil || req.SignDesc.Output == nil {
+		return nil, nil, fmt.Errorf("sign descriptor and its output " +
+			"must be set")
+	}
+
+	tx := wire.NewMsgTx(2)
+	if err := tx.Deserialize(bytes.NewReader(req.RawTxBytes)); err != nil {
+		return nil, nil, err
+	}
+
+	rpcDesc := req.SignDesc
+	if rpcDesc.InputIndex < 0 || int(rpcDesc.InputIndex) >= len(tx.TxIn) {
+		return nil, nil, fmt.Errorf("input index %v out of range",
+			rpcDesc.InputIndex)
+	}
+
+	signDesc := &lnwallet.SignDescriptor{
+		WitnessScript: rpcDesc.WitnessScript,
+		Output: &wire.TxOut{
+			Value:    rpcDesc.Output.Value,
+			PkScript: rpcDesc.Output.PkScript,
+		},
+		HashType:   txscript.SigHashType(rpcDesc.Sighash),
+		SigHashes:  txscript.NewTxSigHashes(tx),
+		InputIndex: int(rpcDesc.InputIndex),
+	}
+	if len(rpcDesc.PrivateTweak) != 0 {
+		signDesc.PrivateTweak = rpcDesc.PrivateTweak
+	}
+	if len(rpcDesc.PubKey) != 0 {
+		pubKey, err := btcec.ParsePubKey(rpcDesc.PubKey, btcec.S256())
+		if err != nil {
+			return nil, nil, err
+		}
+		signDesc.PubKey = pubKey
+	}
+	if rpcDesc.KeyLoc != nil {
+		if signDesc.PubKey == nil {
+			return nil, nil, fmt.Errorf("public key must be set " +
+				"along with its locator")
+		}
+
+		err := deriveKey(keyRing, rpcDesc.KeyLoc, signDesc.PubKey)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return tx, signDesc, nil
+}
+
+// locateKey returns the locator of the passed public key if it was derived by
+// the passed key ring, otherwise nil.
+func locateKey(keyRing keychain.KeyRing,
+	pubKey *btcec.PublicKey) *lnrpc.KeyLocator {
+
+	keyLoc, err := keyRing.LocateKey(pubKey)
+	if err != nil {
+		return nil
+	}
+
+	return &lnrpc.KeyLocator{
+		KeyFamily: uint32(keyLoc.Family),
+		KeyIndex:  keyLoc.Index,
+	}
+}
+
+// deriveKey derives the key identified by the passed locator using the passed
+// key ring, ensuring it matches the passed public key. Once derived, the key
+// ring is able to sign with the key.
+func deriveKey(keyRing keychain.KeyRing, rpcLoc *lnrpc.KeyLocator,
+	pubKey *btcec.PublicKey) error {
+
+	keyDesc, err := keyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamily(rpcLoc.KeyFamily),
+		Index:  rpcLoc.KeyIndex,
+	})
+	if err != nil {
+		return err
+	}
+	if !keyDesc.PubKey.IsEqual(pubKey) {
+		return fmt.Errorf("key locator (%v, %v) doesn't match public "+
+			"key %x", rpcLoc.KeyFamily, rpcLoc.KeyIndex,
+			pubKey.SerializeCompressed())
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package remotesigner
+
+import (
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// RemoteSigner is an implementation of the lnwallet.Signer and
+// lnwallet.MessageSigner interfaces which delegates all signing to a Server
+// over gRPC. This allows a node to run without access to its private keys,
+// which are instead held by the remote signer, such as a node running within
+// a more secure environment. The locator of each key derived by the node's
+// key ring is sent along with each request to sign with it, allowing the
+// remote signer to re-derive the key.
+type RemoteSigner struct {
+	client  lnrpc.SignerClient
+	keyRing keychain.KeyRing
+}
+
+// A compile time check to ensure that RemoteSigner implements the Signer and
+// MessageSigner interfaces.
+var _ lnwallet.Signer = (*RemoteSigner)(nil)
+var _ lnwallet.MessageSigner = (*RemoteSigner)(nil)
+
+// New creates a new RemoteSigner which signs using the Signer service reached
+// over the passed connection, locating keys using the passed key ring.
+func New(conn *grpc.ClientConn, keyRing keychain.KeyRing) *RemoteSigner {
+	return &RemoteSigner{
+		client:  lnrpc.NewSignerClient(conn),
+		keyRing: keyRing,
+	}
+}
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor.
+//
+// NOTE: This is a part of the Signer interface.
+func (r *RemoteSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	req, err := newSignReq(tx, signDesc, r.keyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.SignOutputRaw(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Sig, nil
+}
+
+// ComputeInputScript generates a complete InputIndex for the passed
+// transaction with the signature as defined within the passed SignDescriptor.
+//
+// NOTE: This is a part of the Signer interface.
+func (r *RemoteSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	req, err := newSignReq(tx, signDesc, r.keyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.ComputeInputScript(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwallet.InputScript{
+		Witness:   resp.Witness,
+		ScriptSig: resp.SigScript,
+	}, nil
+}
+
+// SignMessage signs a double-sha256 digest of the passed msg under the
+// private key corresponding to the passed public key.
+//
+// NOTE: This is a part of the MessageSigner interface.
+func (r *RemoteSigner) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	req := &lnrpc.SignMessageReq{
+		PubKey: pubKey.SerializeCompressed(),
+		Msg:    msg,
+		KeyLoc: locateKey(r.keyRing, pubKey),
+	}
+	resp, err := r.client.SignMessage(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParseDERSignature(resp.Signature, btcec.S256())
+}
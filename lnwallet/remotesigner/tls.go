@@ -0,0 +1,74 @@
+package remotesigner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// loadCertPool creates a certificate pool holding the PEM encoded
+// certificates within the passed file.
+func loadCertPool(certPath string) (*x509.CertPool, error) {
+	pemCerts, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("no certificates found within %v",
+			certPath)
+	}
+
+	return certPool, nil
+}
+
+// ServerCredentials creates the transport credentials of a Server, which
+// presents the certificate and key within the passed files. As the Server
+// signs arbitrary data, each client must authenticate itself using a
+// certificate signed by one of the certificates within clientCAPath.
+func ServerCredentials(certPath, keyPath,
+	clientCAPath string) (credentials.TransportCredentials, error) {
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	clientCAs, err := loadCertPool(clientCAPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// ClientCredentials creates the transport credentials of a RemoteSigner,
+// which authenticates itself using the certificate and key within the passed
+// files. The Server must present a certificate signed by one of the
+// certificates within serverCAPath.
+func ClientCredentials(certPath, keyPath,
+	serverCAPath string) (credentials.TransportCredentials, error) {
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	serverCAs, err := loadCertPool(serverCAPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      serverCAs,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
@@ -126,6 +126,19 @@ type ChannelReservation struct {
 	// commitment state.
 	pushSat btcutil.Amount
 
+	// ourFundingAmt is the amount of funds we've committed to the
+	// channel.
+	ourFundingAmt btcutil.Amount
+
+	// externalFunding indicates that the funding transaction is to be
+	// crafted and signed by an external wallet.
+	externalFunding bool
+
+	// deferBroadcast indicates that the funding transaction shouldn't be
+	// broadcast once the reservation is completed, as the caller will do
+	// so.
+	deferBroadcast bool
+
 	// chanOpen houses a struct containing the channel and additional
 	// confirmation details will be sent on once the channel is considered
 	// 'open'. A channel is open once the funding transaction has reached a
@@ -213,6 +226,7 @@ func NewChannelReservation(capacity, fundingAmt btcutil.Amount, minFeeRate btcut
 		},
 		numConfsToOpen: numConfs,
 		pushSat:        pushSat,
+		ourFundingAmt:  fundingAmt,
 		reservationID:  id,
 		chanOpen:       make(chan *openChanDetails, 1),
 		chanOpenErr:    make(chan error, 1),
@@ -250,6 +264,59 @@ func (r *ChannelReservation) ProcessContribution(theirContribution *ChannelContr
 	return <-errChan
 }
 
+// ExternalFundingOutput returns the 2-of-2 multi-sig output an externally
+// crafted funding transaction must pay to, given the counterparty's multi-sig
+// key.
+func (r *ChannelReservation) ExternalFundingOutput(
+	theirMultiSigKey *btcec.PublicKey) (*wire.TxOut, error) {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	ourKey := r.partialState.OurMultiSigKey
+	_, multiSigOut, err := GenFundingPkScript(ourKey.SerializeCompressed(),
+		theirMultiSigKey.SerializeCompressed(),
+		int64(r.partialState.Capacity))
+	if err != nil {
+		return nil, err
+	}
+
+	return multiSigOut, nil
+}
+
+// DeferFundingBroadcast instructs the wallet not to broadcast the funding
+// transaction of an externally funded reservation once it's completed, nor
+// to commit the channel to disk, leaving both to the caller. This allows
+// several reservations to share a single funding transaction, which is only
+// broadcast once all of them have been completed and committed together.
+func (r *ChannelReservation) DeferFundingBroadcast() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.deferBroadcast = true
+}
+
+// ProcessExternalContribution is the analogue of ProcessContribution for
+// externally funded reservations. Rather than assembling the funding
+// transaction from both contributions, the passed unsigned funding
+// transaction crafted by an external wallet is used, once it has been
+// verified to pay to the channel's multi-sig output. The signatures for its
+// inputs must later be passed to CompleteReservation.
+func (r *ChannelReservation) ProcessExternalContribution(
+	theirContribution *ChannelContribution, fundingTx *wire.MsgTx) error {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addContributionMsg{
+		pendingFundingID: r.reservationID,
+		contribution:     theirContribution,
+		fundingTx:        fundingTx,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
 // ProcessSingleContribution verifies, and records the initiator's contribution
 // to this pending single funder channel. Internally, no further action is
 // taken other than recording the initiator's contribution to the single funder
@@ -393,6 +460,26 @@ func (r *ChannelReservation) LocalCommitTx() *wire.MsgTx {
 	return r.partialState.OurCommitTx
 }
 
+// Capacity returns the total capacity of the channel being created by this
+// reservation.
+func (r *ChannelReservation) Capacity() btcutil.Amount {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.partialState.Capacity
+}
+
+// OurFundingAmt returns the amount of funds we've committed to the channel
+// being created by this reservation. If the reservation was created in order
+// to commit all eligible coins to the channel, then this amount is only known
+// once the reservation has been initialized.
+func (r *ChannelReservation) OurFundingAmt() btcutil.Amount {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.ourFundingAmt
+}
+
 // SetTheirDustLimit set dust limit of the remote party.
 func (r *ChannelReservation) SetTheirDustLimit(dustLimit btcutil.Amount) {
 	r.Lock()
@@ -401,6 +488,33 @@ func (r *ChannelReservation) SetTheirDustLimit(dustLimit btcutil.Amount) {
 	r.partialState.TheirDustLimit = dustLimit
 }
 
+// SetOurConstraints sets the constraints we impose upon the remote party,
+// bounding the HTLCs they may offer to us.
+func (r *ChannelReservation) SetOurConstraints(constraints channeldb.ChannelConstraints) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.OurConstraints = constraints
+}
+
+// SetTheirConstraints sets the constraints the remote party imposes upon us,
+// bounding the HTLCs we may offer to them.
+func (r *ChannelReservation) SetTheirConstraints(constraints channeldb.ChannelConstraints) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.TheirConstraints = constraints
+}
+
+// SetPrivate marks the channel being created as private, meaning it won't be
+// announced to the rest of the network.
+func (r *ChannelReservation) SetPrivate(private bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.IsPrivate = private
+}
+
 // FundingOutpoint returns the outpoint of the funding transaction.
 //
 // NOTE: The pointer returned will only be set once the .ProcesContribution()
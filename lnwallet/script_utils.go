@@ -307,47 +307,114 @@ func senderHtlcSpendRedeem(commitScript []byte, outputAmt btcutil.Amount,
 	return witnessStack, nil
 }
 
-// htlcSpendTimeout constructs a valid witness allowing the sender of an HTLC
-// to recover the pending funds after an absolute, then relative locktime
-// period.
-func senderHtlcSpendTimeout(commitScript []byte, outputAmt btcutil.Amount,
-	senderKey *btcec.PrivateKey, sweepTx *wire.MsgTx,
-	absoluteTimeout, relativeTimeout uint32) (wire.TxWitness, error) {
-
-	// Since the HTLC output has an absolute timeout before we're permitted
-	// to sweep the output, we need to set the locktime of this sweeping
-	// transaction to that absolute value in order to pass Script
-	// verification.
-	sweepTx.LockTime = absoluteTimeout
-
-	// Additionally, we're required to wait a relative period of time
-	// before we can sweep the output in order to allow the other party to
-	// contest our claim of validity to this version of the commitment
-	// transaction.
-	sweepTx.TxIn[0].Sequence = lockTimeToSequence(false, relativeTimeout)
+// HtlcSpendTimeout constructs a valid witness allowing the sender of an HTLC
+// to sweep the HTLC output from their own commitment transaction via the
+// timeout clause. The passed sweep transaction MUST already have its lock
+// time set to at least the absolute timeout of the HTLC, and the sequence
+// number of the spending input set to the relative timeout.
+func HtlcSpendTimeout(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
 
-	// Finally, OP_CSV requires that the version of the transaction
-	// spending a pkscript with OP_CSV within it *must* be >= 2.
-	sweepTx.Version = 2
+	// Ensure the transaction version supports the validation of sequence
+	// locks and CSV semantics.
+	if sweepTx.Version < 2 {
+		return nil, fmt.Errorf("version of passed transaction MUST "+
+			"be >= 2, not %v", sweepTx.Version)
+	}
 
-	hashCache := txscript.NewTxSigHashes(sweepTx)
-	sweepSig, err := txscript.RawTxInWitnessSignature(
-		sweepTx, hashCache, 0, int64(outputAmt), commitScript,
-		txscript.SigHashAll, senderKey)
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
 	if err != nil {
 		return nil, err
 	}
 
-	// We place a zero as the first item of the evaluated witness stack in
-	// order to force Script execution to the HTLC timeout clause.
+	// We place an empty byte as the first item of the evaluated witness
+	// stack in order to force Script execution to the HTLC timeout clause.
 	witnessStack := wire.TxWitness(make([][]byte, 3))
-	witnessStack[0] = sweepSig
-	witnessStack[1] = []byte{0}
-	witnessStack[2] = commitScript
+	witnessStack[0] = append(sweepSig, byte(txscript.SigHashAll))
+	witnessStack[1] = nil
+	witnessStack[2] = signDesc.WitnessScript
 
 	return witnessStack, nil
 }
 
+// HtlcSpendType describes which clause of the script of an HTLC offered by
+// us was used to spend the HTLC output.
+type HtlcSpendType uint8
+
+const (
+	// HtlcSpendUnknown denotes a witness which doesn't match any of the
+	// clauses of the HTLC script.
+	HtlcSpendUnknown HtlcSpendType = iota
+
+	// HtlcSpendTimedOut denotes a spend by us via the timeout clause,
+	// after the HTLC's expiry.
+	HtlcSpendTimedOut
+
+	// HtlcSpendRedeemed denotes a spend by the receiver of the HTLC which
+	// reveals the HTLC's payment preimage.
+	HtlcSpendRedeemed
+
+	// HtlcSpendRevoked denotes a spend by the receiver of the HTLC with
+	// knowledge of the commitment transaction's revocation preimage.
+	HtlcSpendRevoked
+)
+
+// String returns a human readable description of the spend type.
+func (h HtlcSpendType) String() string {
+	switch h {
+	case HtlcSpendTimedOut:
+		return "timed out"
+	case HtlcSpendRedeemed:
+		return "redeemed"
+	case HtlcSpendRevoked:
+		return "revoked"
+	default:
+		return "unknown"
+	}
+}
+
+// isWitnessFalse returns true if the passed witness element is evaluated as
+// false when used as the condition of an OP_IF.
+func isWitnessFalse(elem []byte) bool {
+	return len(elem) == 0 || bytes.Equal(elem, []byte{0})
+}
+
+// ClassifyHtlcSpend inspects a witness which spends an HTLC output offered by
+// us in order to determine which clause of the HTLC script was used. If the
+// receiver of the HTLC swept the output via the redemption clause, then the
+// preimage to the passed payment hash is also returned. The witnesses for
+// each clause have the forms:
+//
+//    TIMEOUT: <sig> 0 <witness script>
+//    REDEEM:  <sig> <preimage> 0 1 <witness script>
+//    REVOKE:  <sig> <revocation preimage> 1 1 <witness script>
+func ClassifyHtlcSpend(witness wire.TxWitness,
+	paymentHash [32]byte) (HtlcSpendType, [32]byte) {
+
+	var preimage [32]byte
+
+	switch {
+	case len(witness) == 3 && isWitnessFalse(witness[1]):
+		return HtlcSpendTimedOut, preimage
+
+	case len(witness) != 5 || !bytes.Equal(witness[3], []byte{1}):
+		return HtlcSpendUnknown, preimage
+
+	case bytes.Equal(witness[2], []byte{1}):
+		return HtlcSpendRevoked, preimage
+
+	case !isWitnessFalse(witness[2]) || len(witness[1]) != 32:
+		return HtlcSpendUnknown, preimage
+	}
+
+	copy(preimage[:], witness[1])
+	if sha256.Sum256(preimage[:]) != paymentHash {
+		return HtlcSpendUnknown, preimage
+	}
+
+	return HtlcSpendRedeemed, preimage
+}
+
 // receiverHTLCScript constructs the public key script for an incoming HTLC
 // output payment for the receiver's version of the commitment transaction:
 //
@@ -754,19 +821,17 @@ func DeriveRevocationPrivKey(commitPrivKey *btcec.PrivateKey,
 	return privRevoke
 }
 
-// deriveRevocationRoot derives an root unique to a channel given the
-// private key for our public key in the 2-of-2 multi-sig, and the remote
+// deriveRevocationRoot derives an root unique to a channel given a secret
+// known only to us, our public key in the 2-of-2 multi-sig, and the remote
 // node's multi-sig public key. The seed is derived using the HKDF[1][2]
-// instantiated with sha-256. The secret data used is our multi-sig private
-// key, with the salt being the remote node's public key.
+// instantiated with sha-256. The secret data used is the passed secret, with
+// the salt being our multi-sig public key.
 //
 // [1]: https://eprint.iacr.org/2010/264.pdf
 // [2]: https://tools.ietf.org/html/rfc5869
-func deriveRevocationRoot(derivationRoot *btcec.PrivateKey,
-	localMultiSigKey *btcec.PublicKey,
+func deriveRevocationRoot(secret []byte, localMultiSigKey *btcec.PublicKey,
 	remoteMultiSigKey *btcec.PublicKey) *chainhash.Hash {
 
-	secret := derivationRoot.Serialize()
 	salt := localMultiSigKey.SerializeCompressed()
 	info := remoteMultiSigKey.SerializeCompressed()
 
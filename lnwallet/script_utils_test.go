@@ -295,6 +295,28 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 		},
 	)
 
+	// htlcTimeout sets the lock-time and sequence of the sweep
+	// transaction to the passed values, then signs it as the sender of the
+	// HTLC via the timeout clause.
+	htlcTimeout := func(lockTime, csvDelay uint32) (wire.TxWitness, error) {
+		sweepTx.LockTime = lockTime
+		sweepTx.TxIn[0].Sequence = lockTimeToSequence(false, csvDelay)
+
+		signDesc := &SignDescriptor{
+			PubKey:        aliceKeyPub,
+			WitnessScript: htlcScript,
+			Output: &wire.TxOut{
+				Value: int64(paymentAmt),
+			},
+			HashType:   txscript.SigHashAll,
+			SigHashes:  txscript.NewTxSigHashes(sweepTx),
+			InputIndex: 0,
+		}
+
+		return HtlcSpendTimeout(&mockSigner{aliceKeyPriv}, signDesc,
+			sweepTx)
+	}
+
 	testCases := []struct {
 		witness func() wire.TxWitness
 		valid   bool
@@ -332,24 +354,21 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 		{
 			// invalid lock-time for CLTV
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
-				return senderHtlcSpendTimeout(htlcScript, paymentAmt,
-					aliceKeyPriv, sweepTx, cltvTimeout-2, csvTimeout)
+				return htlcTimeout(cltvTimeout-2, csvTimeout)
 			}),
 			false,
 		},
 		{
 			// invalid sequence for CSV
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
-				return senderHtlcSpendTimeout(htlcScript, paymentAmt,
-					aliceKeyPriv, sweepTx, cltvTimeout, csvTimeout-2)
+				return htlcTimeout(cltvTimeout, csvTimeout-2)
 			}),
 			false,
 		},
 		{
 			// valid lock-time+sequence, valid sig
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
-				return senderHtlcSpendTimeout(htlcScript, paymentAmt,
-					aliceKeyPriv, sweepTx, cltvTimeout, csvTimeout)
+				return htlcTimeout(cltvTimeout, csvTimeout)
 			}),
 			true,
 		},
@@ -392,6 +411,75 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 	}
 }
 
+// TestClassifyHtlcSpend checks that each of the witnesses spending an HTLC
+// offered by us is correctly classified, and that the payment preimage is
+// only extracted from witnesses which use the redemption clause.
+func TestClassifyHtlcSpend(t *testing.T) {
+	paymentPreimage := sha256.Sum256(testHdSeed[:])
+	paymentHash := sha256.Sum256(paymentPreimage[:])
+	revokePreimage := sha256.Sum256(paymentHash[:])
+	revokeHash := sha256.Sum256(revokePreimage[:])
+
+	bobKeyPriv, _ := btcec.PrivKeyFromBytes(btcec.S256(), bobsPrivKey)
+	_, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+
+	htlcScript, err := senderHTLCScript(8, 5, aliceKeyPub,
+		bobKeyPriv.PubKey(), revokeHash[:], paymentHash[:])
+	if err != nil {
+		t.Fatalf("unable to create htlc sender script: %v", err)
+	}
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	sweepTx.AddTxOut(&wire.TxOut{Value: 1e8})
+
+	// A witness redeeming the HTLC with the preimage should reveal it.
+	redeemWitness, err := senderHtlcSpendRedeem(htlcScript, 1e8,
+		bobKeyPriv, sweepTx, paymentPreimage[:])
+	if err != nil {
+		t.Fatalf("unable to create redeem witness: %v", err)
+	}
+	spendType, preimage := ClassifyHtlcSpend(redeemWitness, paymentHash)
+	if spendType != HtlcSpendRedeemed {
+		t.Fatalf("redeem witness classified as %v", spendType)
+	}
+	if preimage != paymentPreimage {
+		t.Fatalf("wrong preimage extracted: expected %x, got %x",
+			paymentPreimage[:], preimage[:])
+	}
+
+	// The preimage shouldn't be accepted if the payment hash doesn't
+	// match.
+	var wrongHash [32]byte
+	spendType, _ = ClassifyHtlcSpend(redeemWitness, wrongHash)
+	if spendType != HtlcSpendUnknown {
+		t.Fatalf("redeem witness with wrong payment hash "+
+			"classified as %v", spendType)
+	}
+
+	// A witness sweeping the HTLC via the revocation clause also carries
+	// a 32-byte preimage, but it mustn't be mistaken for either a
+	// redemption or a timeout.
+	revokeWitness, err := senderHtlcSpendRevoke(htlcScript, 1e8,
+		bobKeyPriv, sweepTx, revokePreimage[:])
+	if err != nil {
+		t.Fatalf("unable to create revoke witness: %v", err)
+	}
+	spendType, _ = ClassifyHtlcSpend(revokeWitness, paymentHash)
+	if spendType != HtlcSpendRevoked {
+		t.Fatalf("revoke witness classified as %v", spendType)
+	}
+
+	// Finally, a witness sweeping the HTLC via the timeout clause doesn't
+	// reveal the preimage.
+	timeoutWitness := wire.TxWitness{[]byte("sig"), nil, htlcScript}
+	spendType, _ = ClassifyHtlcSpend(timeoutWitness, paymentHash)
+	if spendType != HtlcSpendTimedOut {
+		t.Fatalf("timeout witness classified as %v", spendType)
+	}
+}
+
 // TestHTLCReceiverSpendValidation tests all possible valid+invalid redemption
 // paths in the script used within the receiver's commitment transaction for an
 // incoming HTLC.
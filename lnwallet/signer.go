@@ -0,0 +1,131 @@
+package lnwallet
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// keyRingSigner is an implementation of the Signer and MessageSigner
+// interfaces which signs using the private keys of the wallet's key ring.
+// Signing with any key not derived by the key ring, such as the keys of the
+// outputs within the WalletController, is delegated to the underlying
+// signers.
+type keyRingSigner struct {
+	keyRing keychain.SecretKeyRing
+
+	signer    Signer
+	msgSigner MessageSigner
+}
+
+// A compile time check to ensure that keyRingSigner implements the Signer and
+// MessageSigner interfaces.
+var _ Signer = (*keyRingSigner)(nil)
+var _ MessageSigner = (*keyRingSigner)(nil)
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor.
+//
+// NOTE: This is a part of the Signer interface.
+func (k *keyRingSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *SignDescriptor) ([]byte, error) {
+
+	privKey, err := k.keyRing.FetchPrivKey(signDesc.PubKey)
+	if err == keychain.ErrUnknownKey {
+		return k.signer.SignOutputRaw(tx, signDesc)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// If a tweak is specified, then we'll need to use this tweak to derive
+	// the final private key to be used for signing this output.
+	if signDesc.PrivateTweak != nil {
+		privKey = DeriveRevocationPrivKey(privKey, signDesc.PrivateTweak)
+	}
+
+	amt := signDesc.Output.Value
+	sig, err := txscript.RawTxInWitnessSignature(tx, signDesc.SigHashes,
+		signDesc.InputIndex, amt, signDesc.WitnessScript,
+		txscript.SigHashAll, privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chop off the sighash flag at the end of the signature.
+	return sig[:len(sig)-1], nil
+}
+
+// ComputeInputScript generates a complete InputIndex for the passed
+// transaction with the signature as defined within the passed SignDescriptor.
+// If the descriptor's public key was derived by the key ring, such as the key
+// paying to us within the remote party's commitment transaction, then the
+// witness is generated using the key ring's private key. The key ring's keys
+// are only ever paid to directly within p2wkh outputs, so any other output
+// is rejected. Otherwise, this is delegated to the underlying signer.
+//
+// NOTE: This is a part of the Signer interface.
+func (k *keyRingSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *SignDescriptor) (*InputScript, error) {
+
+	if signDesc.PubKey == nil {
+		return k.signer.ComputeInputScript(tx, signDesc)
+	}
+
+	privKey, err := k.keyRing.FetchPrivKey(signDesc.PubKey)
+	if err == keychain.ErrUnknownKey {
+		return k.signer.ComputeInputScript(tx, signDesc)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Before signing, we'll ensure the output being spent is a p2wkh
+	// output paying to the key, as that's the only script the witness
+	// generated below is able to satisfy.
+	pkScript, err := commitScriptUnencumbered(signDesc.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signDesc.Output.PkScript, pkScript) {
+		return nil, fmt.Errorf("unable to compute input script for "+
+			"key %x: output script %x isn't p2wkh",
+			signDesc.PubKey.SerializeCompressed(),
+			signDesc.Output.PkScript)
+	}
+
+	witness, err := txscript.WitnessScript(tx, signDesc.SigHashes,
+		signDesc.InputIndex, signDesc.Output.Value,
+		signDesc.Output.PkScript, txscript.SigHashAll, privKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InputScript{Witness: witness}, nil
+}
+
+// SignMessage signs a double-sha256 digest of the passed msg under the
+// private key corresponding to the passed public key.
+//
+// NOTE: This is a part of the MessageSigner interface.
+func (k *keyRingSigner) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	privKey, err := k.keyRing.FetchPrivKey(pubKey)
+	if err == keychain.ErrUnknownKey {
+		return k.msgSigner.SignMessage(pubKey, msg)
+	} else if err != nil {
+		return nil, err
+	}
+
+	digest := chainhash.DoubleHashB(msg)
+	sign, err := privKey.Sign(digest)
+	if err != nil {
+		return nil, fmt.Errorf("can't sign the message: %v", err)
+	}
+
+	return sign, nil
+}
@@ -0,0 +1,84 @@
+package lnwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+// TestKeyRingSignerComputeInputScript tests that the keyRingSigner is able to
+// spend a p2wkh output paying to a key derived by the key ring, and refuses to
+// generate a witness for any other output script.
+func TestKeyRingSignerComputeInputScript(t *testing.T) {
+	root, err := hdkeychain.NewMaster(bytes.Repeat([]byte{1}, 32),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create root key: %v", err)
+	}
+	store := keychain.NewMemIndexStore()
+	keyRing, err := keychain.NewHDKeyRing(root, store)
+	if err != nil {
+		t.Fatalf("unable to create key ring: %v", err)
+	}
+	signer := &keyRingSigner{keyRing: keyRing}
+
+	keyDesc, err := keyRing.DeriveNextKey(keychain.KeyFamilyCommitKey)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	pkScript, err := commitScriptUnencumbered(keyDesc.PubKey)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	const amt = 100000
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	sweepTx.AddTxOut(&wire.TxOut{Value: amt - 1000, PkScript: pkScript})
+
+	signDesc := &SignDescriptor{
+		PubKey:     keyDesc.PubKey,
+		Output:     &wire.TxOut{Value: amt, PkScript: pkScript},
+		HashType:   txscript.SigHashAll,
+		SigHashes:  txscript.NewTxSigHashes(sweepTx),
+		InputIndex: 0,
+	}
+
+	inputScript, err := signer.ComputeInputScript(sweepTx, signDesc)
+	if err != nil {
+		t.Fatalf("unable to compute input script: %v", err)
+	}
+	sweepTx.TxIn[0].Witness = inputScript.Witness
+
+	vm, err := txscript.NewEngine(pkScript, sweepTx, 0,
+		txscript.StandardVerifyFlags, nil, nil, amt)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("p2wkh spend is invalid: %v", err)
+	}
+
+	// An output paying to a script which includes the key, but isn't
+	// p2wkh, can't be spent by the witness we'd generate, so it should be
+	// rejected.
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(keyDesc.PubKey.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIG)
+	p2pkScript, err := builder.Script()
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	signDesc.Output = &wire.TxOut{Value: amt, PkScript: p2pkScript}
+
+	if _, err := signer.ComputeInputScript(sweepTx, signDesc); err == nil {
+		t.Fatalf("input script computed for non-p2wkh output")
+	}
+}
@@ -106,6 +106,102 @@ const (
 	// HTLCCost 172 weight
 	HTLCCost = blockchain.WitnessScaleFactor * HTLCSize
 
+	// CooperativeCloseTxSize 113 bytes
+	//	- Version: 4 bytes
+	//	- WitnessHeader <---- part of the witness data
+	//	- CountTxIn: 1 byte
+	//	- TxIn: 41 bytes
+	//		FundingInput
+	//	- CountTxOut: 1 byte
+	//	- TxOut: 62 bytes
+	//		OutputPayingToUs,
+	//		OutputPayingToThem
+	//	- LockTime: 4 bytes
+	CooperativeCloseTxSize = 4 + 1 + FundingInputSize + 1 +
+		2*CommitmentKeyHashOutput + 4
+
+	// CooperativeCloseTxCost 676 weight
+	CooperativeCloseTxCost = blockchain.WitnessScaleFactor*
+		CooperativeCloseTxSize + WitnessHeaderSize + WitnessSize
+
+	// P2WKHWitnessSize 109 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- SignatureLength: 1 byte
+	//	- Signature: 73 bytes
+	//	- PublicKeyLength: 1 byte
+	//	- PublicKey: 33 bytes
+	P2WKHWitnessSize = 1 + 1 + 73 + 1 + 33
+
+	// ToLocalScriptSize 79 bytes
+	//	- OP_IF: 1 byte
+	//	- OP_DATA: 1 byte (revocationkey length)
+	//	- revocationkey: 33 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_DATA: 1 byte (localkey length)
+	//	- localkey: 33 bytes
+	//	- OP_CHECKSIGVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (delay length)
+	//	- delay: 4 bytes
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_ENDIF: 1 byte
+	ToLocalScriptSize = 1 + 1 + 33 + 1 + 1 + 1 + 33 + 1 + 1 + 4 + 1 + 1
+
+	// ToLocalWitnessSize 157 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- SignatureLength: 1 byte
+	//	- Signature: 73 bytes
+	//	- SelectorLength: 1 byte
+	//	- Selector: 1 byte (empty for the delayed path)
+	//	- WitnessScriptLength: 1 byte
+	//	- WitnessScript (ToLocal)
+	ToLocalWitnessSize = 1 + 1 + 73 + 1 + 1 + 1 + ToLocalScriptSize
+
+	// OfferedHtlcScriptSize 162 bytes
+	//	- OP_IF: 1 byte
+	//	- OP_IF: 1 byte
+	//	- OP_DATA: 1 byte (revocation hash length)
+	//	- revocation hash: 32 bytes
+	//	- OP_ELSE: 1 byte
+	//	- OP_SIZE: 1 byte
+	//	- OP_DATA: 1 byte (32 length)
+	//	- 32: 1 byte
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (payment hash length)
+	//	- payment hash: 32 bytes
+	//	- OP_ENDIF: 1 byte
+	//	- OP_SWAP: 1 byte
+	//	- OP_SHA256: 1 byte
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (receiverkey length)
+	//	- receiverkey: 33 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_DATA: 1 byte (absolute timeout length)
+	//	- absolute timeout: 4 bytes
+	//	- OP_CHECKLOCKTIMEVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (relative timeout length)
+	//	- relative timeout: 4 bytes
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_2DROP: 1 byte
+	//	- OP_DATA: 1 byte (senderkey length)
+	//	- senderkey: 33 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ENDIF: 1 byte
+	OfferedHtlcScriptSize = 1 + 1 + 1 + 32 + 1 + 1 + 1 + 1 + 1 + 1 + 32 +
+		1 + 1 + 1 + 1 + 1 + 33 + 1 + 1 + 1 + 4 + 1 + 1 + 4 + 1 + 1 +
+		1 + 33 + 1 + 1
+
+	// OfferedHtlcTimeoutWitnessSize 239 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- SignatureLength: 1 byte
+	//	- Signature: 73 bytes
+	//	- NilLength: 1 byte
+	//	- WitnessScriptLength: 1 byte
+	//	- WitnessScript (OfferedHtlc)
+	OfferedHtlcTimeoutWitnessSize = 1 + 1 + 73 + 1 + 1 +
+		OfferedHtlcScriptSize
+
 	// MaxHTLCNumber shows as the maximum number HTLCs which can be
 	// included in commitment transaction. This numbers was calculated by
 	// Rusty Russel in "BOLT #5: Recommendations for On-chain Transaction
@@ -130,3 +226,25 @@ func estimateCommitTxCost(count int, prediction bool) int64 {
 
 	return htlcCost + baseCost + witnessCost
 }
+
+// EstimateSweepTxCost estimates the cost of a transaction sweeping a set of
+// inputs, each spent by a witness of the passed size, into a single p2wkh
+// output.
+func EstimateSweepTxCost(witnessSizes ...int) int64 {
+	// BaseSize
+	//	- Version: 4 bytes
+	//	- CountTxIn: 1 byte
+	//	- TxIn: 41 * num-inputs bytes
+	//	- CountTxOut: 1 byte
+	//	- TxOut: 31 bytes
+	//	- LockTime: 4 bytes
+	baseSize := 4 + 1 + len(witnessSizes)*FundingInputSize + 1 +
+		CommitmentKeyHashOutput + 4
+
+	witnessCost := WitnessHeaderSize
+	for _, size := range witnessSizes {
+		witnessCost += size
+	}
+
+	return int64(blockchain.WitnessScaleFactor*baseSize + witnessCost)
+}
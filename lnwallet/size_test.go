@@ -0,0 +1,133 @@
+package lnwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// maxSig is a placeholder for a signature of the maximum size, including its
+// sighash flag.
+var maxSig = bytes.Repeat([]byte{1}, 73)
+
+// witnessOfSize returns a witness consisting of the passed items, each of which
+// is filled with placeholder bytes.
+func witnessOfSize(itemSizes ...int) wire.TxWitness {
+	witness := make(wire.TxWitness, len(itemSizes))
+	for i, size := range itemSizes {
+		witness[i] = bytes.Repeat([]byte{1}, size)
+	}
+
+	return witness
+}
+
+// txWeight returns the actual weight of the passed transaction.
+func txWeight(tx *wire.MsgTx) int64 {
+	return blockchain.GetTransactionWeight(btcutil.NewTx(tx))
+}
+
+// TestEstimateSweepTxCost tests that the estimated weight of a sweep
+// transaction is an upper bound on the weight of a sweep transaction spending
+// inputs with the maximum sized witnesses of each type, and no more than a
+// single weight unit per input above it.
+func TestEstimateSweepTxCost(t *testing.T) {
+	t.Parallel()
+
+	witnesses := []struct {
+		size    int
+		witness wire.TxWitness
+	}{
+		{
+			size:    P2WKHWitnessSize,
+			witness: witnessOfSize(len(maxSig), 33),
+		},
+		{
+			size: ToLocalWitnessSize,
+			witness: witnessOfSize(len(maxSig), 0,
+				ToLocalScriptSize),
+		},
+		{
+			size: OfferedHtlcTimeoutWitnessSize,
+			witness: witnessOfSize(len(maxSig), 0,
+				OfferedHtlcScriptSize),
+		},
+	}
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxOut(wire.NewTxOut(1e8, make([]byte, P2WPKHSize)))
+
+	var witnessSizes []int
+	for _, w := range witnesses {
+		sweepTx.AddTxIn(&wire.TxIn{Witness: w.witness})
+		witnessSizes = append(witnessSizes, w.size)
+
+		estimate := EstimateSweepTxCost(witnessSizes...)
+		actual := txWeight(sweepTx)
+		if estimate < actual {
+			t.Fatalf("estimated weight of %v below actual weight "+
+				"of %v with %v inputs", estimate, actual,
+				len(witnessSizes))
+		}
+		if estimate-actual > int64(len(witnessSizes)) {
+			t.Fatalf("estimated weight of %v exceeds actual "+
+				"weight of %v by more than %v", estimate, actual,
+				len(witnessSizes))
+		}
+	}
+}
+
+// TestCooperativeCloseTxCost tests that the estimated weight of a cooperative
+// close transaction matches that of a close transaction paying to both parties
+// with the maximum sized multi-sig witness.
+func TestCooperativeCloseTxCost(t *testing.T) {
+	t.Parallel()
+
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(&wire.TxIn{
+		Witness: witnessOfSize(0, len(maxSig), len(maxSig),
+			MultiSigSize),
+	})
+	closeTx.AddTxOut(wire.NewTxOut(1e8, make([]byte, P2WPKHSize)))
+	closeTx.AddTxOut(wire.NewTxOut(1e8, make([]byte, P2WPKHSize)))
+
+	if actual := txWeight(closeTx); actual != CooperativeCloseTxCost {
+		t.Fatalf("expected weight of %v, got %v",
+			CooperativeCloseTxCost, actual)
+	}
+}
+
+// TestEstimateCommitTxCost tests that the estimated weight of a commitment
+// transaction matches that of a commitment transaction with the same number
+// of HTLC outputs, including when predicting the addition of an HTLC.
+func TestEstimateCommitTxCost(t *testing.T) {
+	t.Parallel()
+
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&wire.TxIn{
+		Witness: witnessOfSize(0, len(maxSig), len(maxSig),
+			MultiSigSize),
+	})
+	commitTx.AddTxOut(wire.NewTxOut(1e8, make([]byte, P2WSHSize)))
+	commitTx.AddTxOut(wire.NewTxOut(1e8, make([]byte, P2WPKHSize)))
+
+	for numHtlcs := 0; numHtlcs < 3; numHtlcs++ {
+		actual := txWeight(commitTx)
+		if estimate := estimateCommitTxCost(numHtlcs, false); estimate != actual {
+			t.Fatalf("expected weight of %v with %v htlcs, got %v",
+				actual, numHtlcs, estimate)
+		}
+
+		commitTx.AddTxOut(wire.NewTxOut(1e6, make([]byte, P2WSHSize)))
+
+		// Predicting the addition of an HTLC should yield the weight
+		// of the transaction once the HTLC has been added.
+		actual = txWeight(commitTx)
+		if estimate := estimateCommitTxCost(numHtlcs, true); estimate != actual {
+			t.Fatalf("expected predicted weight of %v with %v "+
+				"htlcs, got %v", actual, numHtlcs+1, estimate)
+		}
+	}
+}
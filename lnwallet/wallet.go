@@ -1,6 +1,7 @@
 package lnwallet
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"net"
@@ -13,8 +14,11 @@ import (
 	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcutil/hdkeychain"
 
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/blockchain"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
@@ -26,17 +30,6 @@ const (
 	// outside word.
 	msgBufferSize = 100
 
-	// revocationRootIndex is the top level HD key index from which secrets
-	// used to generate producer roots should be derived from.
-	revocationRootIndex = hdkeychain.HardenedKeyStart + 1
-
-	// identityKeyIndex is the top level HD key index which is used to
-	// generate/rotate identity keys.
-	//
-	// TODO(roasbeef): should instead be child to make room for future
-	// rotations, etc.
-	identityKeyIndex = hdkeychain.HardenedKeyStart + 2
-
 	commitFee = 5000
 )
 
@@ -68,10 +61,10 @@ func (e *ErrInsufficientFunds) Error() string {
 // be locked up within the channel. Upon success a ChannelReservation will be
 // created in order to track the lifetime of this pending channel. Outputs
 // selected will be 'locked', making them unavailable, for any other pending
-// reservations. Therefore, all channels in reservation limbo will be periodically
-// after a timeout period in order to avoid "exhaustion" attacks.
-//
-// TODO(roasbeef): zombie reservation sweeper goroutine.
+// reservations. Therefore, all channels in reservation limbo are expected to
+// be cancelled by the caller after a timeout period in order to avoid
+// "exhaustion" attacks. The funding manager's zombie sweeper does so for any
+// reservation which fails to progress through the funding flow in time.
 type initFundingReserveMsg struct {
 	// The ID of the remote node we would like to open a channel with.
 	nodeID *btcec.PublicKey
@@ -111,6 +104,16 @@ type initFundingReserveMsg struct {
 	// The delay on the "pay-to-self" output(s) of the commitment transaction.
 	csvDelay uint32
 
+	// coinSelectOpts controls the coin selection performed in order to
+	// fund the channel, along with the fee rate paid by the funding
+	// transaction. If nil, the wallet's defaults are used.
+	coinSelectOpts *CoinSelectOpts
+
+	// externalFunding indicates that the funding transaction will be
+	// crafted and signed by an external wallet, so no coin selection
+	// should be performed.
+	externalFunding bool
+
 	// A channel in which all errors will be sent accross. Will be nil if
 	// this initial set is succesful.
 	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
@@ -146,6 +149,10 @@ type addContributionMsg struct {
 	// TODO(roasbeef): Should also carry SPV proofs in we're in SPV mode
 	contribution *ChannelContribution
 
+	// fundingTx is the unsigned funding transaction crafted by an external
+	// wallet. It is only set for externally funded reservations.
+	fundingTx *wire.MsgTx
+
 	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
 	err chan error
 }
@@ -277,9 +284,26 @@ type LightningWallet struct {
 	// used to lookup the existence of outputs within the UTXO set.
 	ChainIO BlockChainIO
 
-	// rootKey is the root HD key derived from a WalletController private
-	// key. This rootKey is used to derive all LN specific secrets.
-	rootKey *hdkeychain.ExtendedKey
+	// FeeEstimator is the implementation that the wallet will use for
+	// the calculation of on-chain transaction fees, such as those paid
+	// by funding transactions.
+	FeeEstimator FeeEstimator
+
+	// MessageSigner signs messages using the keys of the KeyRing, falling
+	// back to the MessageSigner of the WalletController for all other
+	// keys.
+	MessageSigner MessageSigner
+
+	// KeyRing derives the keys used within our channels. Each key derived
+	// is identified by a KeyLocator recorded within the channel's state,
+	// allowing it to be re-derived from the wallet's seed alone. The
+	// secrets derived from our keys are computed using the key ring's
+	// ECDH, so its private keys may be held by a remote signer.
+	KeyRing keychain.ECDHRing
+
+	// watchOnly is true if the wallet holds no private keys, with all of
+	// its signing delegated to a remote signer.
+	watchOnly bool
 
 	// All messages to the wallet are to be sent across this channel.
 	msgChan chan interface{}
@@ -289,12 +313,12 @@ type LightningWallet struct {
 	// limbo. Once the final signatures have been exchanged, a reservation
 	// is removed from limbo. Each reservation is tracked by a unique
 	// monotonically integer. All requests concerning the channel MUST
-	// carry a valid, active funding ID.
+	// carry a valid, active funding ID. Reservations abandoned part way
+	// through the funding workflow remain in limbo until cancelled by the
+	// caller.
 	fundingLimbo  map[uint64]*ChannelReservation
 	nextFundingID uint64
 	limboMtx      sync.RWMutex
-	// TODO(roasbeef): zombie garbage collection routine to solve
-	// lost-object/starvation problem/attack.
 
 	// lockedOutPoints is a set of the currently locked outpoint. This
 	// information is kept in order to provide an easy way to unlock all
@@ -316,10 +340,14 @@ type LightningWallet struct {
 // If the wallet has never been created (according to the passed dataDir), first-time
 // setup is executed.
 //
+// The passed Signer and MessageSigner are used to sign with any key not
+// derived by the wallet's KeyRing.
+//
 // NOTE: The passed channeldb, and ChainNotifier should already be fully
 // initialized/started before being passed as a function arugment.
 func NewLightningWallet(cdb *channeldb.DB, notifier chainntnfs.ChainNotifier,
-	wallet WalletController, signer Signer, bio BlockChainIO,
+	wallet WalletController, signer Signer, msgSigner MessageSigner,
+	bio BlockChainIO, feeEstimator FeeEstimator,
 	netParams *chaincfg.Params) (*LightningWallet, error) {
 
 	// TODO(roasbeef): need a another wallet level config
@@ -338,19 +366,65 @@ func NewLightningWallet(cdb *channeldb.DB, notifier chainntnfs.ChainNotifier,
 		return nil, err
 	}
 
+	// The keys used within our channels are derived from the same root,
+	// with the number of keys derived within each family persisted in
+	// the channeldb.
+	keyRing, err := keychain.NewHDKeyRing(rootMasterKey, cdb)
+	if err != nil {
+		return nil, err
+	}
+	keyRingSigner := &keyRingSigner{
+		keyRing:   keyRing,
+		signer:    signer,
+		msgSigner: msgSigner,
+	}
+
+	return newLightningWallet(cdb, notifier, wallet, keyRing,
+		keyRingSigner, keyRingSigner, bio, feeEstimator), nil
+}
+
+// NewWatchOnlyLightningWallet creates a LightningWallet which holds no private
+// keys. The keys of the passed key ring are derived, and used to compute our
+// secrets, by a remote signer, which the passed Signer and MessageSigner must
+// also sign through. The passed WalletController is expected to be watch-only,
+// as its root key is never fetched.
+//
+// NOTE: The passed channeldb, and ChainNotifier should already be fully
+// initialized/started before being passed as a function arugment.
+func NewWatchOnlyLightningWallet(cdb *channeldb.DB,
+	notifier chainntnfs.ChainNotifier, wallet WalletController,
+	keyRing keychain.ECDHRing, signer Signer, msgSigner MessageSigner,
+	bio BlockChainIO, feeEstimator FeeEstimator) *LightningWallet {
+
+	w := newLightningWallet(cdb, notifier, wallet, keyRing, signer,
+		msgSigner, bio, feeEstimator)
+	w.watchOnly = true
+
+	return w
+}
+
+// newLightningWallet creates a LightningWallet which derives its keys using
+// the passed key ring, and signs using the passed signers.
+func newLightningWallet(cdb *channeldb.DB, notifier chainntnfs.ChainNotifier,
+	wallet WalletController, keyRing keychain.ECDHRing, signer Signer,
+	msgSigner MessageSigner, bio BlockChainIO,
+	feeEstimator FeeEstimator) *LightningWallet {
+
 	return &LightningWallet{
-		rootKey:          rootMasterKey,
+		KeyRing:          keyRing,
 		chainNotifier:    notifier,
 		Signer:           signer,
+		MessageSigner:    msgSigner,
 		WalletController: wallet,
 		ChainIO:          bio,
+		FeeEstimator:     feeEstimator,
 		ChannelDB:        cdb,
 		msgChan:          make(chan interface{}, msgBufferSize),
 		nextFundingID:    0,
 		fundingLimbo:     make(map[uint64]*ChannelReservation),
 		lockedOutPoints:  make(map[wire.OutPoint]struct{}),
 		quit:             make(chan struct{}),
-	}, nil
+	}
 }
 
 // Startup establishes a connection to the RPC source, and spins up all
@@ -390,6 +464,12 @@ func (l *LightningWallet) Shutdown() error {
 	return nil
 }
 
+// WatchOnly returns true if the wallet holds no private keys, in which case
+// the underlying WalletController is unable to sign transactions itself.
+func (l *LightningWallet) WatchOnly() bool {
+	return l.watchOnly
+}
+
 // LockedOutpoints returns a list of all currently locked outpoint.
 func (l *LightningWallet) LockedOutpoints() []*wire.OutPoint {
 	outPoints := make([]*wire.OutPoint, 0, len(l.lockedOutPoints))
@@ -423,15 +503,41 @@ func (l *LightningWallet) ActiveReservations() []*ChannelReservation {
 	return reservations
 }
 
-// GetIdentitykey returns the identity private key of the wallet.
+// IdentityKey returns the descriptor of the wallet's identity key.
 // TODO(roasbeef): should be moved elsewhere
-func (l *LightningWallet) GetIdentitykey() (*btcec.PrivateKey, error) {
-	identityKey, err := l.rootKey.Child(identityKeyIndex)
+func (l *LightningWallet) IdentityKey() (keychain.KeyDescriptor, error) {
+	return l.KeyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamilyNodeKey,
+	})
+}
+
+// DeriveOnionKey derives the descriptor of the onion key at the target index.
+// Onion keys are used in place of the identity key to process incoming
+// Sphinx packets, and are periodically rotated by advancing the index.
+func (l *LightningWallet) DeriveOnionKey(index uint32) (keychain.KeyDescriptor,
+	error) {
+
+	return l.KeyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamilyOnionKey,
+		Index:  index,
+	})
+}
+
+// revocationRoot derives the root of the revocation pre-images of a channel
+// from the point shared between the revocation root key identified by the
+// passed locator and the remote node's multi-sig key. As the shared point is
+// computed by the key ring, the root may be derived without access to the
+// private key, such as when it's held by a remote signer.
+func (l *LightningWallet) revocationRoot(keyLoc keychain.KeyLocator,
+	ourKey, theirKey *btcec.PublicKey) (*chainhash.Hash, error) {
+
+	sharedPoint, err := l.KeyRing.ECDH(keyLoc, theirKey)
 	if err != nil {
 		return nil, err
 	}
+	secret := sha256.Sum256(sharedPoint.SerializeCompressed())
 
-	return identityKey.ECPrivKey()
+	return deriveRevocationRoot(secret[:], ourKey, theirKey), nil
 }
 
 // requestHandler is the primary goroutine(s) responsible for handling, and
@@ -481,11 +587,16 @@ out:
 // and final step verifies all signatures for the inputs of the funding
 // transaction, and that the signature we records for our version of the
 // commitment transaction is valid.
+//
+// The passed coin selection options, which may be nil, control how the inputs
+// funding our side of the channel are selected. If opts.SpendAll is set, then
+// ourFundAmt is ignored, and all eligible coins are committed to the channel.
 func (l *LightningWallet) InitChannelReservation(capacity,
 	ourFundAmt btcutil.Amount, theirID *btcec.PublicKey,
 	theirAddr *net.TCPAddr, numConfs uint16,
 	csvDelay uint32, ourDustLimit btcutil.Amount,
-	pushSat btcutil.Amount) (*ChannelReservation, error) {
+	pushSat btcutil.Amount,
+	coinSelectOpts *CoinSelectOpts) (*ChannelReservation, error) {
 
 	// TODO(roasbeef): make the above into an initial config as part of the
 	// refactor to implement spec compliant funding flow
@@ -494,16 +605,49 @@ func (l *LightningWallet) InitChannelReservation(capacity,
 	respChan := make(chan *ChannelReservation, 1)
 
 	l.msgChan <- &initFundingReserveMsg{
-		capacity:      capacity,
-		numConfs:      numConfs,
-		fundingAmount: ourFundAmt,
-		csvDelay:      csvDelay,
-		ourDustLimit:  ourDustLimit,
-		pushSat:       pushSat,
-		nodeID:        theirID,
-		nodeAddr:      theirAddr,
-		err:           errChan,
-		resp:          respChan,
+		capacity:       capacity,
+		numConfs:       numConfs,
+		fundingAmount:  ourFundAmt,
+		csvDelay:       csvDelay,
+		ourDustLimit:   ourDustLimit,
+		pushSat:        pushSat,
+		nodeID:         theirID,
+		nodeAddr:       theirAddr,
+		coinSelectOpts: coinSelectOpts,
+		err:            errChan,
+		resp:           respChan,
+	}
+
+	return <-respChan, <-errChan
+}
+
+// InitExternalFundingReservation kicks off the same workflow as
+// InitChannelReservation, however the funding transaction will be crafted and
+// signed by an external wallet rather than by us. As a result, no coin
+// selection is performed, and the funding transaction must later be presented
+// via ProcessExternalContribution, with the signatures for its inputs passed
+// to CompleteReservation.
+func (l *LightningWallet) InitExternalFundingReservation(capacity,
+	ourFundAmt btcutil.Amount, theirID *btcec.PublicKey,
+	theirAddr *net.TCPAddr, numConfs uint16,
+	csvDelay uint32, ourDustLimit btcutil.Amount,
+	pushSat btcutil.Amount) (*ChannelReservation, error) {
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *ChannelReservation, 1)
+
+	l.msgChan <- &initFundingReserveMsg{
+		capacity:        capacity,
+		numConfs:        numConfs,
+		fundingAmount:   ourFundAmt,
+		csvDelay:        csvDelay,
+		ourDustLimit:    ourDustLimit,
+		pushSat:         pushSat,
+		nodeID:          theirID,
+		nodeAddr:        theirAddr,
+		externalFunding: true,
+		err:             errChan,
+		resp:            respChan,
 	}
 
 	return <-respChan, <-errChan
@@ -512,8 +656,10 @@ func (l *LightningWallet) InitChannelReservation(capacity,
 // handleFundingReserveRequest processes a message intending to create, and
 // validate a funding reservation request.
 func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg) {
+	spendAll := req.coinSelectOpts != nil && req.coinSelectOpts.SpendAll
+
 	// It isn't possible to create a channel with zero funds committed.
-	if req.fundingAmount+req.capacity == 0 {
+	if req.fundingAmount+req.capacity == 0 && !spendAll {
 		req.err <- fmt.Errorf("cannot have channel with zero " +
 			"satoshis funded")
 		req.resp <- nil
@@ -521,6 +667,66 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 	}
 
 	id := atomic.AddUint64(&l.nextFundingID, 1)
+
+	// If we're on the receiving end of a single funder channel, or the
+	// funding transaction is to be crafted by an external wallet, then we
+	// don't need to perform any coin selection. Otherwise, attempt to
+	// obtain enough coins to meet the required funding amount.
+	var (
+		selectedCoins []*wire.OutPoint
+		changeAmt     btcutil.Amount
+		reserved      bool
+	)
+
+	// If we're unable to complete the reservation, then any coins we've
+	// selected are unlocked, making them eligible for coin selection once
+	// again.
+	defer func() {
+		if !reserved && len(selectedCoins) != 0 {
+			l.unlockCoins(selectedCoins)
+		}
+	}()
+
+	if (req.fundingAmount != 0 || spendAll) && !req.externalFunding {
+		// Determine the fee rate the funding transaction should pay,
+		// either as specified by the caller, or by consulting the fee
+		// estimator.
+		feeRate, err := l.coinSelectFeeRate(req.coinSelectOpts,
+			FundingTxConfTarget)
+		if err != nil {
+			req.err <- err
+			req.resp <- nil
+			return
+		}
+
+		amt := req.fundingAmount + commitFee
+		coins, excessAmt, err := l.selectCoins(feeRate, amt,
+			p2wshOutputSize, req.coinSelectOpts)
+		if err != nil {
+			req.err <- err
+			req.resp <- nil
+			return
+		}
+		selectedCoins = coins
+
+		// If all eligible coins are to be spent, then whatever remains
+		// of their value after fees is committed to the channel.
+		// Otherwise, the excess is returned to us as change.
+		if spendAll {
+			fundingAmt := excessAmt - commitFee
+			if fundingAmt <= 0 {
+				req.err <- &ErrInsufficientFunds{commitFee, excessAmt}
+				req.resp <- nil
+				return
+			}
+
+			req.capacity += fundingAmt - req.fundingAmount
+			req.fundingAmount = fundingAmt
+		} else {
+			changeAmt = excessAmt
+		}
+	}
+
 	totalCapacity := req.capacity + commitFee
 	reservation := NewChannelReservation(totalCapacity, req.fundingAmount,
 		req.minFeeRate, l, id, req.numConfs, req.pushSat)
@@ -530,6 +736,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 	defer reservation.Unlock()
 
 	reservation.nodeAddr = req.nodeAddr
+	reservation.externalFunding = req.externalFunding
 	reservation.ourContribution.CsvDelay = req.csvDelay
 
 	reservation.partialState.NumConfsRequired = req.numConfs
@@ -539,41 +746,61 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 
 	ourContribution := reservation.ourContribution
 
-	// If we're on the receiving end of a single funder channel then we
-	// don't need to perform any coin selection. Otherwise, attempt to
-	// obtain enough coins to meet the required funding amount.
-	if req.fundingAmount != 0 {
-		// TODO(roasbeef): consult model for proper fee rate on funding
-		// tx
-		feeRate := uint64(10)
-		amt := req.fundingAmount + commitFee
-		err := l.selectCoinsAndChange(feeRate, amt, ourContribution)
+	// The selected coins are now "reserved" as inputs to the funding
+	// transaction. Empty sig scripts are used, as we'll actually sign if
+	// this reservation is queued up to be completed (the other side
+	// accepts).
+	ourContribution.Inputs = make([]*wire.TxIn, len(selectedCoins))
+	for i, coin := range selectedCoins {
+		ourContribution.Inputs[i] = wire.NewTxIn(coin, nil, nil)
+	}
+
+	// Record any change output generated as a result of the coin
+	// selection.
+	if changeAmt != 0 {
+		changeOutput, err := l.newChangeOutput(changeAmt)
 		if err != nil {
 			req.err <- err
 			req.resp <- nil
 			return
 		}
+		ourContribution.ChangeOutputs = []*wire.TxOut{changeOutput}
 	}
 
-	// Grab two fresh keys from our HD chain, one will be used for the
+	// Grab two fresh keys from our key ring, one will be used for the
 	// multi-sig funding transaction, and the other for the commitment
-	// transaction.
-	multiSigKey, err := l.NewRawKey()
+	// transaction. The commitment key is used both within the
+	// time-locked output of our commitment transaction, and the output
+	// paying to us within theirs.
+	multiSigKey, err := l.KeyRing.DeriveNextKey(keychain.KeyFamilyMultiSig)
 	if err != nil {
 		req.err <- err
 		req.resp <- nil
 		return
 	}
-	commitKey, err := l.NewRawKey()
+	commitKey, err := l.KeyRing.DeriveNextKey(keychain.KeyFamilyCommitKey)
 	if err != nil {
 		req.err <- err
 		req.resp <- nil
 		return
 	}
-	reservation.partialState.OurMultiSigKey = multiSigKey
-	ourContribution.MultiSigKey = multiSigKey
-	reservation.partialState.OurCommitKey = commitKey
-	ourContribution.CommitKey = commitKey
+	reservation.partialState.OurMultiSigKey = multiSigKey.PubKey
+	reservation.partialState.OurMultiSigKeyLoc = multiSigKey.KeyLocator
+	ourContribution.MultiSigKey = multiSigKey.PubKey
+	reservation.partialState.OurCommitKey = commitKey.PubKey
+	reservation.partialState.OurCommitKeyLoc = commitKey.KeyLocator
+	ourContribution.CommitKey = commitKey.PubKey
+
+	// We'll also derive a fresh key to serve as the root of the channel's
+	// revocation preimages.
+	revocationRoot, err := l.KeyRing.DeriveNextKey(
+		keychain.KeyFamilyRevocationRoot)
+	if err != nil {
+		req.err <- err
+		req.resp <- nil
+		return
+	}
+	reservation.partialState.RevocationRootKeyLoc = revocationRoot.KeyLocator
 
 	// Generate a fresh address to be used in the case of a cooperative
 	// channel close.
@@ -597,6 +824,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 	l.limboMtx.Lock()
 	l.fundingLimbo[id] = reservation
 	l.limboMtx.Unlock()
+	reserved = true
 
 	// Funding reservation request successfully handled. The funding inputs
 	// will be marked as unavailable until the reservation is either
@@ -660,34 +888,15 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	pendingReservation.Lock()
 	defer pendingReservation.Unlock()
 
-	// Create a blank, fresh transaction. Soon to be a complete funding
-	// transaction which will allow opening a lightning channel.
-	pendingReservation.fundingTx = wire.NewMsgTx(1)
-	fundingTx := pendingReservation.fundingTx
-
 	// Some temporary variables to cut down on the resolution verbosity.
 	pendingReservation.theirContribution = req.contribution
 	theirContribution := req.contribution
 	ourContribution := pendingReservation.ourContribution
 
-	// Add all multi-party inputs and outputs to the transaction.
-	for _, ourInput := range ourContribution.Inputs {
-		fundingTx.AddTxIn(ourInput)
-	}
-	for _, theirInput := range theirContribution.Inputs {
-		fundingTx.AddTxIn(theirInput)
-	}
-	for _, ourChangeOutput := range ourContribution.ChangeOutputs {
-		fundingTx.AddTxOut(ourChangeOutput)
-	}
-	for _, theirChangeOutput := range theirContribution.ChangeOutputs {
-		fundingTx.AddTxOut(theirChangeOutput)
-	}
-
 	ourKey := pendingReservation.partialState.OurMultiSigKey
 	theirKey := theirContribution.MultiSigKey
 
-	// Finally, add the 2-of-2 multi-sig output which will set up the lightning
+	// Generate the 2-of-2 multi-sig output which will set up the lightning
 	// channel.
 	channelCapacity := int64(pendingReservation.partialState.Capacity)
 	witnessScript, multiSigOut, err := GenFundingPkScript(ourKey.SerializeCompressed(),
@@ -698,44 +907,25 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	}
 	pendingReservation.partialState.FundingWitnessScript = witnessScript
 
-	// Sort the transaction. Since both side agree to a canonical
-	// ordering, by sorting we no longer need to send the entire
-	// transaction. Only signatures will be exchanged.
-	fundingTx.AddTxOut(multiSigOut)
-	txsort.InPlaceSort(pendingReservation.fundingTx)
-
-	// Next, sign all inputs that are ours, collecting the signatures in
-	// order of the inputs.
-	pendingReservation.ourFundingInputScripts = make([]*InputScript, 0, len(ourContribution.Inputs))
-	signDesc := SignDescriptor{
-		HashType:  txscript.SigHashAll,
-		SigHashes: txscript.NewTxSigHashes(fundingTx),
-	}
-	for i, txIn := range fundingTx.TxIn {
-		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
-		if err == ErrNotMine {
-			continue
-		} else if err != nil {
+	// If the funding transaction was crafted by an external wallet, then
+	// we only need to ensure it pays to the multi-sig output. Otherwise,
+	// we'll assemble it from both contributions, signing all our inputs.
+	var fundingTx *wire.MsgTx
+	if pendingReservation.externalFunding {
+		err = verifyExternalFundingTx(req.fundingTx, multiSigOut)
+		if err != nil {
 			req.err <- err
 			return
 		}
-
-		signDesc.Output = info
-		signDesc.InputIndex = i
-
-		inputScript, err := l.Signer.ComputeInputScript(fundingTx, &signDesc)
+		fundingTx = req.fundingTx.Copy()
+	} else {
+		fundingTx, err = l.assembleFundingTx(pendingReservation, multiSigOut)
 		if err != nil {
 			req.err <- err
 			return
 		}
-
-		txIn.SignatureScript = inputScript.ScriptSig
-		txIn.Witness = inputScript.Witness
-		pendingReservation.ourFundingInputScripts = append(
-			pendingReservation.ourFundingInputScripts,
-			inputScript,
-		)
 	}
+	pendingReservation.fundingTx = fundingTx
 
 	// Locate the index of the multi-sig outpoint in order to record it
 	// since the outputs are canonically sorted. If this is a single funder
@@ -752,16 +942,16 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	pendingReservation.partialState.RevocationStore = s
 	pendingReservation.partialState.TheirCurrentRevocation = theirContribution.RevocationKey
 
-	masterElkremRoot, err := l.deriveMasterRevocationRoot()
+		// Now that we have their commitment key, we can create the revocation
+	// key for the first version of our commitment transaction. To do so,
+	// we'll first create our root, then produce the first pre-image.
+	root, err := l.revocationRoot(
+		pendingReservation.partialState.RevocationRootKeyLoc, ourKey,
+		theirKey)
 	if err != nil {
 		req.err <- err
 		return
 	}
-
-	// Now that we have their commitment key, we can create the revocation
-	// key for the first version of our commitment transaction. To do so,
-	// we'll first create our root, then produce the first pre-image.
-	root := deriveRevocationRoot(masterElkremRoot, ourKey, theirKey)
 	producer := shachain.NewRevocationProducer(*root)
 	pendingReservation.partialState.RevocationProducer = producer
 	firstPreimage, err := producer.AtIndex(0)
@@ -838,7 +1028,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 
 	// Generate a signature for their version of the initial commitment
 	// transaction.
-	signDesc = SignDescriptor{
+	signDesc := SignDescriptor{
 		WitnessScript: witnessScript,
 		PubKey:        ourKey,
 		Output:        multiSigOut,
@@ -856,6 +1046,109 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	req.err <- nil
 }
 
+// assembleFundingTx builds the funding transaction from both parties'
+// contributions and the passed multi-sig output, then signs all of our inputs
+// to it, recording the resulting input scripts within the reservation.
+//
+// NOTE: The reservation's mutex MUST be held when calling this method.
+func (l *LightningWallet) assembleFundingTx(res *ChannelReservation,
+	multiSigOut *wire.TxOut) (*wire.MsgTx, error) {
+
+	ourContribution := res.ourContribution
+	theirContribution := res.theirContribution
+
+	// Create a blank, fresh transaction. Soon to be a complete funding
+	// transaction which will allow opening a lightning channel.
+	fundingTx := wire.NewMsgTx(1)
+
+	// Add all multi-party inputs and outputs to the transaction.
+	for _, ourInput := range ourContribution.Inputs {
+		fundingTx.AddTxIn(ourInput)
+	}
+	for _, theirInput := range theirContribution.Inputs {
+		fundingTx.AddTxIn(theirInput)
+	}
+	for _, ourChangeOutput := range ourContribution.ChangeOutputs {
+		fundingTx.AddTxOut(ourChangeOutput)
+	}
+	for _, theirChangeOutput := range theirContribution.ChangeOutputs {
+		fundingTx.AddTxOut(theirChangeOutput)
+	}
+
+	// Sort the transaction. Since both side agree to a canonical
+	// ordering, by sorting we no longer need to send the entire
+	// transaction. Only signatures will be exchanged.
+	fundingTx.AddTxOut(multiSigOut)
+	txsort.InPlaceSort(fundingTx)
+
+	// Next, sign all inputs that are ours, collecting the signatures in
+	// order of the inputs.
+	res.ourFundingInputScripts = make([]*InputScript, 0, len(ourContribution.Inputs))
+	signDesc := SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: txscript.NewTxSigHashes(fundingTx),
+	}
+	for i, txIn := range fundingTx.TxIn {
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err == ErrNotMine {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Signer.ComputeInputScript(fundingTx, &signDesc)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn.SignatureScript = inputScript.ScriptSig
+		txIn.Witness = inputScript.Witness
+		res.ourFundingInputScripts = append(
+			res.ourFundingInputScripts, inputScript,
+		)
+	}
+
+	return fundingTx, nil
+}
+
+// verifyExternalFundingTx ensures that a funding transaction crafted by an
+// external wallet pays the full capacity of the channel to its multi-sig
+// output exactly once, and carries no input scripts.
+func verifyExternalFundingTx(fundingTx *wire.MsgTx, multiSigOut *wire.TxOut) error {
+	if fundingTx == nil {
+		return fmt.Errorf("externally funded reservation requires a " +
+			"funding transaction")
+	}
+
+	var numFundingOutputs int
+	for _, txOut := range fundingTx.TxOut {
+		if !bytes.Equal(txOut.PkScript, multiSigOut.PkScript) {
+			continue
+		}
+		if txOut.Value != multiSigOut.Value {
+			return fmt.Errorf("funding output pays %v, expected %v",
+				btcutil.Amount(txOut.Value),
+				btcutil.Amount(multiSigOut.Value))
+		}
+		numFundingOutputs++
+	}
+	if numFundingOutputs != 1 {
+		return fmt.Errorf("funding transaction must contain exactly "+
+			"one funding output, found %v", numFundingOutputs)
+	}
+
+	for _, txIn := range fundingTx.TxIn {
+		if len(txIn.SignatureScript) != 0 || len(txIn.Witness) != 0 {
+			return fmt.Errorf("funding transaction must be unsigned")
+		}
+	}
+
+	return nil
+}
+
 // handleSingleContribution is called as the second step to a single funder
 // workflow to which we are the responder. It simply saves the remote peer's
 // contribution to the channel, as solely the remote peer will contribute any
@@ -892,15 +1185,15 @@ func (l *LightningWallet) handleSingleContribution(req *addSingleContributionMsg
 	}
 	pendingReservation.partialState.FundingWitnessScript = witnessScript
 
-	masterElkremRoot, err := l.deriveMasterRevocationRoot()
+		// Now that we know their commitment key, we can create the revocation
+	// key for our version of the initial commitment transaction.
+	root, err := l.revocationRoot(
+		pendingReservation.partialState.RevocationRootKeyLoc, ourKey,
+		theirKey)
 	if err != nil {
 		req.err <- err
 		return
 	}
-
-	// Now that we know their commitment key, we can create the revocation
-	// key for our version of the initial commitment transaction.
-	root := deriveRevocationRoot(masterElkremRoot, ourKey, theirKey)
 	producer := shachain.NewRevocationProducer(*root)
 	firstPreimage, err := producer.AtIndex(0)
 	if err != nil {
@@ -970,6 +1263,17 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	res.theirFundingInputScripts = msg.theirFundingInputScripts
 	inputScripts := msg.theirFundingInputScripts
 	fundingTx := res.fundingTx
+
+	// If the funding transaction was crafted by an external wallet, then
+	// none of its inputs are ours, so a script must've been provided for
+	// each of them.
+	if res.externalFunding && len(inputScripts) != len(fundingTx.TxIn) {
+		msg.err <- fmt.Errorf("externally funded transaction has %v "+
+			"inputs, but %v input scripts were provided",
+			len(fundingTx.TxIn), len(inputScripts))
+		return
+	}
+
 	sigIndex := 0
 	fundingHashCache := txscript.NewTxSigHashes(fundingTx)
 	for i, txin := range fundingTx.TxIn {
@@ -980,8 +1284,12 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 
 			// Fetch the alleged previous output along with the
 			// pkscript referenced by this input.
+			//
+			// TODO(roasbeef): obtain height hint from the remote
+			// party so light clients needn't scan the entire chain
 			prevOut := txin.PreviousOutPoint
-			output, err := l.ChainIO.GetUtxo(&prevOut.Hash, prevOut.Index)
+			output, err := l.ChainIO.GetUtxo(&prevOut.Hash,
+				prevOut.Index, 0)
 			if output == nil {
 				msg.err <- fmt.Errorf("input to funding tx does not exist: %v", err)
 				return
@@ -1045,9 +1353,27 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	delete(l.fundingLimbo, res.reservationID)
 	l.limboMtx.Unlock()
 
+	// Record the current height before broadcasting the funding
+	// transaction, as the funding output can't be spent prior to it.
+	_, bestHeight, err := l.ChainIO.GetBestBlock()
+	if err != nil {
+		msg.err <- err
+		return
+	}
+	res.partialState.FundingBroadcastHeight = uint32(bestHeight)
+
 	walletLog.Infof("Broadcasting funding tx for ChannelPoint(%v): %v",
 		res.partialState.FundingOutpoint, spew.Sdump(fundingTx))
 
+	// If the caller has taken on the responsibility of broadcasting the
+	// funding transaction, then it'll also commit the channel to disk, so
+	// our work here is done.
+	if res.deferBroadcast {
+		msg.completeChan <- res.partialState
+		msg.err <- nil
+		return
+	}
+
 	// Broacast the finalized funding transaction to the network.
 	if err := l.PublishTransaction(fundingTx); err != nil {
 		msg.err <- err
@@ -1181,6 +1507,16 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 	}
 	pendingReservation.ourCommitmentSig = sigTheirCommit
 
+	// The initiator will only broadcast the funding transaction once
+	// they've received our signature, so the current height serves as a
+	// lower bound for any spends of the funding output.
+	_, bestHeight, err := l.ChainIO.GetBestBlock()
+	if err != nil {
+		req.err <- err
+		return
+	}
+	pendingReservation.partialState.FundingBroadcastHeight = uint32(bestHeight)
+
 	// Add the complete funding transaction to the DB, in it's open bucket
 	// which will be used for the lifetime of this channel.
 	if err := pendingReservation.partialState.SyncPending(pendingReservation.nodeAddr); err != nil {
@@ -1196,82 +1532,342 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 	l.limboMtx.Unlock()
 }
 
-// selectCoinsAndChange performs coin selection in order to obtain witness
-// outputs which sum to at least 'numCoins' amount of satoshis. If coin
-// selection is successful/possible, then the selected coins are available
-// within the passed contribution's inputs. If necessary, a change address will
-// also be generated.
-// TODO(roasbeef): remove hardcoded fees and req'd confs for outputs.
-func (l *LightningWallet) selectCoinsAndChange(feeRate uint64, amt btcutil.Amount,
-	contribution *ChannelContribution) error {
+// CoinSelectOpts allows the caller to control the coin selection performed
+// when the wallet funds a transaction, along with the fee rate the transaction
+// pays. A nil *CoinSelectOpts selects coins according to the wallet's
+// defaults.
+type CoinSelectOpts struct {
+	// Outpoints, if non-empty, restricts coin selection to this set of the
+	// wallet's unspent witness outputs.
+	Outpoints []wire.OutPoint
+
+	// SatPerVByte is the fee rate, in satoshis per virtual byte, the
+	// transaction should pay. If zero, the fee rate is estimated using
+	// TargetConf.
+	SatPerVByte uint64
+
+	// TargetConf is the number of blocks within which the transaction
+	// should confirm, used to estimate its fee rate if SatPerVByte isn't
+	// set. If zero, a default target for the transaction is used.
+	TargetConf uint32
+
+	// MinConfs is the number of confirmations an output requires in order
+	// to be selected. If zero, unconfirmed outputs may be selected.
+	MinConfs int32
+
+	// SpendAll indicates that every eligible output should be spent, with
+	// their entire value, less fees, paid to the transaction's sole
+	// output rather than generating change.
+	SpendAll bool
+}
+
+// coinSelectFeeRate returns the fee rate, in sat/byte, the wallet should use
+// when performing coin selection according to the passed options. If the
+// options don't specify a fee rate, then one is estimated using either the
+// target they specify, or the passed default target.
+func (l *LightningWallet) coinSelectFeeRate(opts *CoinSelectOpts,
+	defaultConfTarget uint32) (uint64, error) {
+
+	confTarget := defaultConfTarget
+	if opts != nil {
+		if opts.SatPerVByte != 0 {
+			return opts.SatPerVByte, nil
+		}
+		if opts.TargetConf != 0 {
+			confTarget = opts.TargetConf
+		}
+	}
+
+	// Coin selection expects a rate in sat/byte, so we'll scale the
+	// estimate accordingly.
+	feePerWeight, err := l.FeeEstimator.EstimateFeePerWeight(confTarget)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(feePerWeight * blockchain.WitnessScaleFactor), nil
+}
+
+// selectCoins performs coin selection in order to obtain witness outputs
+// which sum to at least amt satoshis, plus the fees required for a
+// transaction creating outputs of outputSize bytes at the passed fee rate. The
+// selected coins are locked, preventing them from being selected again, and
+// returned along with the excess amount to be returned as change. If
+// opts.SpendAll is set, then all eligible coins are selected, and amt is
+// ignored. In this case, the returned amount is the total value of the coins
+// less fees.
+func (l *LightningWallet) selectCoins(feeRate uint64, amt btcutil.Amount,
+	outputSize int, opts *CoinSelectOpts) ([]*wire.OutPoint, btcutil.Amount,
+	error) {
+
+	if opts == nil {
+		opts = &CoinSelectOpts{MinConfs: 1}
+	}
+	minConfs := opts.MinConfs
 
 	// We hold the coin select mutex while querying for outputs, and
 	// performing coin selection in order to avoid inadvertent double
-	// spends across funding transactions.
+	// spends across transactions.
 	l.coinSelectMtx.Lock()
 	defer l.coinSelectMtx.Unlock()
 
-	// Find all unlocked unspent witness outputs with greater than 1
-	// confirmation.
-	// TODO(roasbeef): make num confs a configuration paramter
-	coins, err := l.ListUnspentWitness(1)
+	// Find all unlocked unspent witness outputs with the required number
+	// of confirmations.
+	coins, err := l.ListUnspentWitness(minConfs)
 	if err != nil {
-		return err
+		return nil, 0, err
+	}
+
+	// If the caller has chosen the coins to be spent, then we'll only
+	// select from amongst those.
+	if len(opts.Outpoints) != 0 {
+		available := make(map[wire.OutPoint]*Utxo, len(coins))
+		for _, coin := range coins {
+			available[coin.OutPoint] = coin
+		}
+
+		coins = make([]*Utxo, 0, len(opts.Outpoints))
+		for _, outpoint := range opts.Outpoints {
+			coin, ok := available[outpoint]
+			if !ok {
+				return nil, 0, fmt.Errorf("outpoint %v isn't an "+
+					"unlocked witness output with at least %v "+
+					"confirmations", outpoint, minConfs)
+			}
+			delete(available, outpoint)
+
+			coins = append(coins, coin)
+		}
 	}
 
 	// Perform coin selection over our available, unlocked unspent outputs
-	// in order to find enough coins to meet the funding amount
-	// requirements.
-	selectedCoins, changeAmt, err := coinSelect(feeRate, amt, coins)
+	// in order to find enough coins to meet the amount requirements.
+	var (
+		selectedCoins []*wire.OutPoint
+		excessAmt     btcutil.Amount
+	)
+	if opts.SpendAll {
+		selectedCoins, excessAmt, err = coinSelectSpendAll(feeRate,
+			outputSize, coins)
+	} else {
+		selectedCoins, excessAmt, err = coinSelect(feeRate, amt,
+			outputSize, coins)
+	}
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
 	// Lock the selected coins. These coins are now "reserved", this
-	// prevents concurrent funding requests from referring to and this
+	// prevents concurrent requests from referring to and this
 	// double-spending the same set of coins.
-	contribution.Inputs = make([]*wire.TxIn, len(selectedCoins))
-	for i, coin := range selectedCoins {
+	for _, coin := range selectedCoins {
 		l.lockedOutPoints[*coin] = struct{}{}
 		l.LockOutpoint(*coin)
+	}
+
+	return selectedCoins, excessAmt, nil
+}
+
+// unlockCoins unlocks coins previously locked by selectCoins, marking them
+// eligible for coin selection once again.
+func (l *LightningWallet) unlockCoins(coins []*wire.OutPoint) {
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
 
-		// Empty sig script, we'll actually sign if this reservation is
-		// queued up to be completed (the other side accepts).
-		contribution.Inputs[i] = wire.NewTxIn(coin, nil, nil)
+	for _, coin := range coins {
+		delete(l.lockedOutPoints, *coin)
+		l.UnlockOutpoint(*coin)
 	}
+}
 
-	// Record any change output(s) generated as a result of the coin
-	// selection.
-	if changeAmt != 0 {
-		changeAddr, err := l.NewAddress(WitnessPubKey, true)
-		if err != nil {
-			return err
-		}
-		changeScript, err := txscript.PayToAddrScript(changeAddr)
-		if err != nil {
-			return err
-		}
+// newChangeOutput creates an output paying the passed amount to a fresh
+// change address.
+func (l *LightningWallet) newChangeOutput(amt btcutil.Amount) (*wire.TxOut, error) {
+	changeAddr, err := l.NewAddress(WitnessPubKey, true)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, err
+	}
 
-		contribution.ChangeOutputs = make([]*wire.TxOut, 1)
-		contribution.ChangeOutputs[0] = &wire.TxOut{
-			Value:    int64(changeAmt),
-			PkScript: changeScript,
-		}
+	return &wire.TxOut{
+		Value:    int64(amt),
+		PkScript: changeScript,
+	}, nil
+}
+
+// SendOutputsWithOpts funds, signs, and broadcasts a transaction paying out
+// to the specified outputs. Unlike SendOutputs, the inputs of the transaction
+// are selected by the LightningWallet according to the passed options, which
+// may be nil. If opts.SpendAll is set, then exactly one output must be passed,
+// whose value is replaced by the total value of the selected coins, less
+// fees.
+func (l *LightningWallet) SendOutputsWithOpts(outputs []*wire.TxOut,
+	opts *CoinSelectOpts) (*wire.MsgTx, error) {
+
+	spendAll := opts != nil && opts.SpendAll
+	if spendAll && len(outputs) != 1 {
+		return nil, fmt.Errorf("exactly one output must be specified " +
+			"when spending all coins")
 	}
 
-	return nil
+	feeRate, err := l.coinSelectFeeRate(opts, SendTxConfTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		amt        btcutil.Amount
+		outputSize int
+	)
+	for _, output := range outputs {
+		amt += btcutil.Amount(output.Value)
+		outputSize += 8 + 1 + len(output.PkScript)
+	}
+
+	coins, excessAmt, err := l.selectCoins(feeRate, amt, outputSize, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := l.createSendTx(outputs, coins, excessAmt, spendAll)
+	if err != nil {
+		l.unlockCoins(coins)
+		return nil, err
+	}
+
+	if err := l.PublishTransaction(tx); err != nil {
+		l.unlockCoins(coins)
+		return nil, err
+	}
+
+	// Now that the transaction has been broadcast, the wallet considers
+	// the selected coins spent, so we no longer need to keep them locked.
+	l.unlockCoins(coins)
+
+	return tx, nil
 }
 
-// deriveMasterRevocationRoot derives the private key which serves as the master
-// producer root. This master secret is used as the secret input to a HKDF to
-// generate revocation secrets based on random, but public data.
-func (l *LightningWallet) deriveMasterRevocationRoot() (*btcec.PrivateKey, error) {
-	masterElkremRoot, err := l.rootKey.Child(revocationRootIndex)
+// FundBatchTx funds and signs, but doesn't broadcast, a transaction paying
+// out to the specified outputs, selecting its inputs according to the passed
+// options, which may be nil. The selected coins remain locked until released
+// via ReleaseTxInputs. This allows several channels to be funded by a single
+// funding transaction, which is only broadcast once every channel has been
+// agreed upon.
+func (l *LightningWallet) FundBatchTx(outputs []*wire.TxOut,
+	opts *CoinSelectOpts) (*wire.MsgTx, error) {
+
+	if opts != nil && opts.SpendAll {
+		return nil, fmt.Errorf("all coins cannot be spent by a batch " +
+			"funding transaction")
+	}
+
+	feeRate, err := l.coinSelectFeeRate(opts, FundingTxConfTarget)
 	if err != nil {
 		return nil, err
 	}
 
-	return masterElkremRoot.ECPrivKey()
+	var (
+		amt        btcutil.Amount
+		outputSize int
+	)
+	for _, output := range outputs {
+		amt += btcutil.Amount(output.Value)
+		outputSize += 8 + 1 + len(output.PkScript)
+	}
+
+	coins, excessAmt, err := l.selectCoins(feeRate, amt, outputSize, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := l.createSendTx(outputs, coins, excessAmt, false)
+	if err != nil {
+		l.unlockCoins(coins)
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ReleaseTxInputs unlocks the inputs of a transaction funded via FundBatchTx,
+// either once it has been broadcast, or if it's to be abandoned.
+func (l *LightningWallet) ReleaseTxInputs(tx *wire.MsgTx) {
+	coins := make([]*wire.OutPoint, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		coins[i] = &txIn.PreviousOutPoint
+	}
+
+	l.unlockCoins(coins)
+}
+
+// createSendTx creates a fully signed transaction spending the passed coins
+// to the passed outputs. If spendAll is true, then the value of the sole
+// output is set to excessAmt. Otherwise, excessAmt is paid to a change
+// output, unless it's below the dust limit, in which case it's added to the
+// fees.
+func (l *LightningWallet) createSendTx(outputs []*wire.TxOut,
+	coins []*wire.OutPoint, excessAmt btcutil.Amount,
+	spendAll bool) (*wire.MsgTx, error) {
+
+	tx := wire.NewMsgTx(2)
+	for _, coin := range coins {
+		tx.AddTxIn(wire.NewTxIn(coin, nil, nil))
+	}
+
+	switch {
+	case spendAll:
+		if excessAmt < DefaultDustLimit() {
+			return nil, fmt.Errorf("amount %v remaining after fees "+
+				"is below the dust limit", excessAmt)
+		}
+
+		tx.AddTxOut(wire.NewTxOut(int64(excessAmt),
+			outputs[0].PkScript))
+
+	default:
+		for _, output := range outputs {
+			tx.AddTxOut(output)
+		}
+
+		if excessAmt >= DefaultDustLimit() {
+			changeOutput, err := l.newChangeOutput(excessAmt)
+			if err != nil {
+				return nil, err
+			}
+			tx.AddTxOut(changeOutput)
+		}
+	}
+
+	txsort.InPlaceSort(tx)
+
+	// With the transaction in its final order, we can now sign each of
+	// its inputs, all of which belong to the wallet.
+	signDesc := SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: txscript.NewTxSigHashes(tx),
+	}
+	for i, txIn := range tx.TxIn {
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Signer.ComputeInputScript(tx, &signDesc)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn.SignatureScript = inputScript.ScriptSig
+		txIn.Witness = inputScript.Witness
+	}
+
+	return tx, nil
 }
 
 // deriveStateHintObfuscator derives the bytes to be used for obfuscating the
@@ -1344,35 +1940,36 @@ func selectInputs(amt btcutil.Amount, coins []*Utxo) (btcutil.Amount, []*wire.Ou
 	return satSelected, selectedUtxos, nil
 }
 
+const (
+	// txOverhead is the overhead of a transaction residing within the
+	// version number and lock time.
+	txOverhead = 8
+
+	// p2wkhSpendSize an estimate of the number of bytes it takes to spend
+	// a p2wkh output.
+	//
+	// (p2wkh witness) + txid + index + varint script size + sequence
+	// TODO(roasbeef): div by 3 due to witness size?
+	p2wkhSpendSize = (1 + 73 + 1 + 33) + 32 + 4 + 1 + 4
+
+	// p2wkhOutputSize is an estimate of the size of a regualr p2wkh
+	// output.
+	//
+	// 8 (output) + 1 (var int script) + 22 (p2wkh output)
+	p2wkhOutputSize = 8 + 1 + 22
+
+	// p2wkhOutputSize is an estimate of the p2wsh funding uotput.
+	p2wshOutputSize = 8 + 1 + 34
+)
+
 // coinSelect attemps to select a sufficient amount of coins, including a
 // change output to fund amt satoshis, adhearing to the specified fee rate. The
 // specified fee rate should be expressed in sat/byte for coin selection to
-// function properly.
-func coinSelect(feeRate uint64, amt btcutil.Amount,
+// function properly. The outputSize parameter is the total size of the
+// outputs the transaction creates, excluding change.
+func coinSelect(feeRate uint64, amt btcutil.Amount, outputSize int,
 	coins []*Utxo) ([]*wire.OutPoint, btcutil.Amount, error) {
 
-	const (
-		// txOverhead is the overhead of a transaction residing within
-		// the version number and lock time.
-		txOverhead = 8
-
-		// p2wkhSpendSize an estimate of the number of bytes it takes
-		// to spend a p2wkh output.
-		//
-		// (p2wkh witness) + txid + index + varint script size + sequence
-		// TODO(roasbeef): div by 3 due to witness size?
-		p2wkhSpendSize = (1 + 73 + 1 + 33) + 32 + 4 + 1 + 4
-
-		// p2wkhOutputSize is an estimate of the size of a regualr
-		// p2wkh output.
-		//
-		// 8 (output) + 1 (var int script) + 22 (p2wkh output)
-		p2wkhOutputSize = 8 + 1 + 22
-
-		// p2wkhOutputSize is an estimate of the p2wsh funding uotput.
-		p2wshOutputSize = 8 + 1 + 34
-	)
-
 	var estimatedSize int
 
 	amtNeeded := amt
@@ -1387,7 +1984,7 @@ func coinSelect(feeRate uint64, amt btcutil.Amount,
 		// Based on the selected coins, estimate the size of the final
 		// fully signed transaction.
 		estimatedSize = ((len(selectedUtxos) * p2wkhSpendSize) +
-			p2wshOutputSize + txOverhead)
+			outputSize + txOverhead)
 
 		// The difference bteween the selected amount and the amount
 		// requested will be used to pay fees, and generate a change
@@ -1410,3 +2007,31 @@ func coinSelect(feeRate uint64, amt btcutil.Amount,
 		return selectedUtxos, changeAmt, nil
 	}
 }
+
+// coinSelectSpendAll selects all of the passed coins, returning their total
+// value less the fee required to spend them to outputs of outputSize bytes at
+// the specified fee rate, expressed in sat/byte.
+func coinSelectSpendAll(feeRate uint64, outputSize int,
+	coins []*Utxo) ([]*wire.OutPoint, btcutil.Amount, error) {
+
+	var (
+		selectedUtxos []*wire.OutPoint
+		totalSat      btcutil.Amount
+	)
+	for _, coin := range coins {
+		selectedUtxos = append(selectedUtxos, &wire.OutPoint{
+			Hash:  coin.Hash,
+			Index: coin.Index,
+		})
+		totalSat += coin.Value
+	}
+
+	estimatedSize := ((len(selectedUtxos) * p2wkhSpendSize) +
+		outputSize + txOverhead)
+	requiredFee := btcutil.Amount(uint64(estimatedSize) * feeRate)
+	if len(selectedUtxos) == 0 || totalSat <= requiredFee {
+		return nil, 0, &ErrInsufficientFunds{requiredFee, totalSat}
+	}
+
+	return selectedUtxos, totalSat - requiredFee, nil
+}
@@ -0,0 +1,104 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// newTestCoins creates a set of unspent outputs with the passed values.
+func newTestCoins(values ...btcutil.Amount) []*Utxo {
+	coins := make([]*Utxo, len(values))
+	for i, value := range values {
+		coins[i] = &Utxo{
+			Value: value,
+			OutPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{byte(i)},
+				Index: uint32(i),
+			},
+		}
+	}
+
+	return coins
+}
+
+// TestCoinSelect tests that coin selection selects enough coins to pay for
+// both the requested amount and the fee, returning the remainder as change.
+func TestCoinSelect(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = 10
+
+	coins := newTestCoins(50000, 50000, 50000)
+
+	// A single coin is enough to pay for the outputs, so the change
+	// should be whatever remains once the fee has been paid.
+	selected, changeAmt, err := coinSelect(feeRate, 30000,
+		p2wkhOutputSize, coins)
+	if err != nil {
+		t.Fatalf("unable to select coins: %v", err)
+	}
+	if len(selected) != 1 {
+		t.Fatalf("expected 1 coin to be selected, instead got %v",
+			len(selected))
+	}
+	fee := btcutil.Amount((p2wkhSpendSize + p2wkhOutputSize +
+		txOverhead) * feeRate)
+	if changeAmt != 50000-30000-fee {
+		t.Fatalf("expected change of %v, instead got %v",
+			50000-30000-fee, changeAmt)
+	}
+
+	// Requesting more than the total value of the coins should fail.
+	_, _, err = coinSelect(feeRate, 150000, p2wkhOutputSize, coins)
+	if _, ok := err.(*ErrInsufficientFunds); !ok {
+		t.Fatalf("expected ErrInsufficientFunds, instead got %v", err)
+	}
+}
+
+// TestCoinSelectSpendAll tests that all coins are selected when spending
+// all, with their total value less fees returned.
+func TestCoinSelectSpendAll(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = 10
+
+	coins := newTestCoins(50000, 20000, 30000)
+
+	selected, amt, err := coinSelectSpendAll(feeRate, p2wkhOutputSize,
+		coins)
+	if err != nil {
+		t.Fatalf("unable to select coins: %v", err)
+	}
+	if len(selected) != len(coins) {
+		t.Fatalf("expected %v coins to be selected, instead got %v",
+			len(coins), len(selected))
+	}
+	for i, coin := range coins {
+		if *selected[i] != coin.OutPoint {
+			t.Fatalf("expected coin %v to be selected, instead "+
+				"got %v", coin.OutPoint, selected[i])
+		}
+	}
+
+	fee := btcutil.Amount((3*p2wkhSpendSize + p2wkhOutputSize +
+		txOverhead) * feeRate)
+	if amt != 100000-fee {
+		t.Fatalf("expected amount of %v, instead got %v",
+			100000-fee, amt)
+	}
+
+	// If the coins can't even pay for the fee, then an error should be
+	// returned, as should be the case if there are no coins at all.
+	_, _, err = coinSelectSpendAll(feeRate, p2wkhOutputSize,
+		newTestCoins(1000))
+	if _, ok := err.(*ErrInsufficientFunds); !ok {
+		t.Fatalf("expected ErrInsufficientFunds, instead got %v", err)
+	}
+	_, _, err = coinSelectSpendAll(feeRate, p2wkhOutputSize, nil)
+	if _, ok := err.(*ErrInsufficientFunds); !ok {
+		t.Fatalf("expected ErrInsufficientFunds, instead got %v", err)
+	}
+}
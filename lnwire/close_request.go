@@ -27,19 +27,21 @@ type CloseRequest struct {
 	// assembled closing transaction.
 	RequesterCloseSig *btcec.Signature
 
-	// Fee is the required fee-per-KB the closing transaction must have.
-	// It is recommended that a "sufficient" fee be paid in order to
-	// achieve timely channel closure.
-	// TODO(roasbeef): if initiator always pays fees, then no longer needed.
+	// Fee is the absolute fee the closing transaction pays, as proposed
+	// by the requester. It is recommended that a "sufficient" fee be paid
+	// in order to achieve timely channel closure. The fee is paid in full
+	// by the initiator of the channel.
 	Fee btcutil.Amount
 }
 
 // NewCloseRequest creates a new CloseRequest.
-func NewCloseRequest(cid ChannelID, sig *btcec.Signature) *CloseRequest {
-	// TODO(roasbeef): update once fees aren't hardcoded
+func NewCloseRequest(cid ChannelID, sig *btcec.Signature,
+	fee btcutil.Amount) *CloseRequest {
+
 	return &CloseRequest{
 		ChanID:            cid,
 		RequesterCloseSig: sig,
+		Fee:               fee,
 	}
 }
 
@@ -29,6 +29,38 @@ const (
 	// channel update or a funding request while their still syncing to the
 	// latest state of the blockchain.
 	ErrSynchronizingChain ErrorCode = 2
+
+	// ErrUnacceptableConstraints is returned by a remote peer during the
+	// funding workflow if the channel constraints we've proposed are
+	// deemed unreasonable.
+	ErrUnacceptableConstraints ErrorCode = 3
+
+	// ErrConstraintViolation is sent to a remote peer which has violated
+	// the channel constraints negotiated during funding. The channel the
+	// error references is failed once the error has been sent.
+	ErrConstraintViolation ErrorCode = 4
+
+	// ErrForceCloseRequested is sent by a remote peer which has lost the
+	// state of the referenced channel, and has restored it from a static
+	// backup. As the peer is unable to close the channel itself, it
+	// requests that we force close the channel, allowing it to sweep its
+	// balance from our commitment transaction. A request for a channel
+	// the peer has updated is rejected, as the peer hasn't lost its state.
+	ErrForceCloseRequested ErrorCode = 5
+
+	// ErrChannelRejected is returned by a remote peer which has declined
+	// to accept a requested channel, for example due to its size, or the
+	// identity of the requesting node.
+	ErrChannelRejected ErrorCode = 6
+
+	// ErrFundingTimeout is sent to a remote peer once a funding flow with
+	// it has been cancelled for failing to progress in time.
+	ErrFundingTimeout ErrorCode = 7
+
+	// ErrUnacceptableCloseFee is returned by a remote peer which has
+	// declined to complete a cooperative close, as the fee proposed for
+	// the closing transaction strays too far from its own estimate.
+	ErrUnacceptableCloseFee ErrorCode = 8
 )
 
 // ErrorData is a set of bytes associated with a particular sent error. A
@@ -82,6 +82,12 @@ func writeElement(w io.Writer, element interface{}) error {
 		if _, err := w.Write(b[:]); err != nil {
 			return err
 		}
+	case FundingFlag:
+		var b [1]byte
+		b[0] = uint8(e)
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
 	case ErrorCode:
 		var b [2]byte
 		binary.BigEndian.PutUint16(b[:], uint16(e))
@@ -107,6 +113,10 @@ func writeElement(w io.Writer, element interface{}) error {
 			return err
 		}
 	case *btcec.PublicKey:
+		if e == nil {
+			return fmt.Errorf("cannot write nil pubkey")
+		}
+
 		var b [33]byte
 		serializedPubkey := e.SerializeCompressed()
 		copy(b[:], serializedPubkey)
@@ -155,6 +165,10 @@ func writeElement(w io.Writer, element interface{}) error {
 			}
 		}
 	case *btcec.Signature:
+		if e == nil {
+			return fmt.Errorf("cannot write nil signature")
+		}
+
 		var b [64]byte
 		err := serializeSigToWire(&b, e)
 		if err != nil {
@@ -426,6 +440,12 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 		*e = binary.BigEndian.Uint16(b[:])
+	case *FundingFlag:
+		var b [1]uint8
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		*e = FundingFlag(b[0])
 	case *ErrorCode:
 		var b [2]byte
 		if _, err := io.ReadFull(r, b[:]); err != nil {
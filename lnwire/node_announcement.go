@@ -103,6 +103,21 @@ type NodeAnnouncement struct {
 	// Address includes two specification fields: 'ipv6' and 'port' on which
 	// the node is accepting incoming connections.
 	Addresses []net.Addr
+
+	// OnionKey is the public key that should be used when constructing
+	// Sphinx onion packets destined to this node. Unlike the NodeID, the
+	// onion key is periodically rotated by the node.
+	//
+	// NOTE: This is an optional trailing field, so that the announcement
+	// remains readable by nodes unaware of onion keys. If it's nil, then
+	// the NodeID should be used in its place.
+	OnionKey *btcec.PublicKey
+
+	// OnionKeySig is a signature under the NodeID of the data returned by
+	// OnionKeyDataToSign. As the OnionKey isn't covered by the primary
+	// Signature, this authenticates the OnionKey. It MUST be set if the
+	// OnionKey is set.
+	OnionKeySig *btcec.Signature
 }
 
 // A compile time check to ensure NodeAnnouncement implements the
@@ -123,7 +138,7 @@ func (a *NodeAnnouncement) Validate() error {
 //
 // This is part of the lnwire.Message interface.
 func (a *NodeAnnouncement) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		&a.Signature,
 		&a.Timestamp,
 		&a.NodeID,
@@ -132,13 +147,28 @@ func (a *NodeAnnouncement) Decode(r io.Reader, pver uint32) error {
 		&a.Addresses,
 		&a.Features,
 	)
+	if err != nil {
+		return err
+	}
+
+	// The onion key and its signature are optional trailing fields. If
+	// we've reached the end of the message, then the node hasn't
+	// advertised an onion key.
+	err = readElements(r, &a.OnionKey, &a.OnionKeySig)
+	if err == io.EOF {
+		a.OnionKey = nil
+		a.OnionKeySig = nil
+		return nil
+	}
+
+	return err
 }
 
 // Encode serializes the target NodeAnnouncement into the passed io.Writer
 // observing the protocol version specified.
 //
 func (a *NodeAnnouncement) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		a.Signature,
 		a.Timestamp,
 		a.NodeID,
@@ -147,6 +177,18 @@ func (a *NodeAnnouncement) Encode(w io.Writer, pver uint32) error {
 		a.Addresses,
 		a.Features,
 	)
+	if err != nil {
+		return err
+	}
+
+	if a.OnionKey == nil {
+		return nil
+	}
+	if a.OnionKeySig == nil {
+		return errors.New("onion key included without signature")
+	}
+
+	return writeElements(w, a.OnionKey, a.OnionKeySig)
 }
 
 // Command returns the integer uniquely identifying this message type on the
@@ -173,8 +215,10 @@ func (a *NodeAnnouncement) MaxPayloadLength(pver uint32) uint32 {
 	// Ipv4 - 4 bytes (optional)
 	// Ipv6 - 16 bytes (optional)
 	// Port - 2 bytes (optional)
+	// OnionKey - 33 bytes (optional)
+	// OnionKeySig - 64 bytes (optional)
 
-	// Base size, 140, but can be variable due to multiple addresses
+	// Base size, 173, but can be variable due to multiple addresses
 	return 8192
 }
 
@@ -197,3 +241,25 @@ func (a *NodeAnnouncement) DataToSign() ([]byte, error) {
 
 	return w.Bytes(), nil
 }
+
+// OnionKeyDataToSign returns the data which should be signed by the node in
+// order to authenticate the advertised OnionKey. The timestamp is included so
+// that an onion key from a prior announcement can't be replayed within a
+// later one.
+func (a *NodeAnnouncement) OnionKeyDataToSign() ([]byte, error) {
+	if a.OnionKey == nil {
+		return nil, errors.New("announcement has no onion key")
+	}
+
+	var w bytes.Buffer
+	err := writeElements(&w,
+		a.NodeID,
+		a.Timestamp,
+		a.OnionKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
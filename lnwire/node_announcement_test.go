@@ -8,13 +8,15 @@ import (
 
 func TestNodeAnnouncementEncodeDecode(t *testing.T) {
 	na := &NodeAnnouncement{
-		Signature: someSig,
-		Timestamp: maxUint32,
-		NodeID:    pubKey,
-		RGBColor:  someRGB,
-		Alias:     someAlias,
-		Addresses: someAddresses,
-		Features:  someFeatures,
+		Signature:   someSig,
+		Timestamp:   maxUint32,
+		NodeID:      pubKey,
+		RGBColor:    someRGB,
+		Alias:       someAlias,
+		Addresses:   someAddresses,
+		Features:    someFeatures,
+		OnionKey:    pubKey,
+		OnionKeySig: someSig,
 	}
 
 	// Next encode the NA message into an empty bytes buffer.
@@ -69,6 +71,56 @@ func TestNodeAnnoucementPayloadLength(t *testing.T) {
 	}
 }
 
+// TestNodeAnnouncementOptionalOnionKey tests that the onion key is treated as
+// an optional trailing field, so that announcements without one remain
+// compatible with nodes unaware of onion keys.
+func TestNodeAnnouncementOptionalOnionKey(t *testing.T) {
+	na := &NodeAnnouncement{
+		Signature: someSig,
+		Timestamp: maxUint32,
+		NodeID:    pubKey,
+		RGBColor:  someRGB,
+		Alias:     someAlias,
+		Addresses: someAddresses,
+		Features:  someFeatures,
+	}
+
+	// An announcement without an onion key should decode with a nil onion
+	// key, rather than failing.
+	var b bytes.Buffer
+	if err := na.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode NodeAnnouncement: %v", err)
+	}
+	na2 := &NodeAnnouncement{}
+	if err := na2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode NodeAnnouncement: %v", err)
+	}
+	if na2.OnionKey != nil || na2.OnionKeySig != nil {
+		t.Fatalf("expected no onion key, got %v", na2.OnionKey)
+	}
+
+	// The onion key must not alter the primary signed digest, otherwise
+	// nodes unaware of it would be unable to validate the announcement.
+	withoutKey, err := na.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to get data to sign: %v", err)
+	}
+	na.OnionKey = pubKey
+	withKey, err := na.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to get data to sign: %v", err)
+	}
+	if !bytes.Equal(withoutKey, withKey) {
+		t.Fatalf("onion key altered the signed digest")
+	}
+
+	// Finally, an onion key can't be encoded without its signature.
+	b.Reset()
+	if err := na.Encode(&b, 0); err == nil {
+		t.Fatalf("expected onion key without signature to be rejected")
+	}
+}
+
 func TestValidateAlias(t *testing.T) {
 	if err := someAlias.Validate(); err != nil {
 		t.Fatalf("alias was invalid: %v", err)
@@ -8,6 +8,18 @@ import (
 	"github.com/roasbeef/btcutil"
 )
 
+// FundingFlag represents the possible bit mask values for the ChannelFlags
+// field within the SingleFundingRequest message.
+type FundingFlag uint8
+
+const (
+	// FFAnnounceChannel is a FundingFlag that when set, indicates the
+	// initiator of a funding flow wishes to announce the channel to the
+	// rest of the network. If unset, then the channel is private, and
+	// won't be announced by either party.
+	FFAnnounceChannel FundingFlag = 1 << iota
+)
+
 // SingleFundingRequest is the message Alice sends to Bob if we should like
 // to create a channel with Bob where she's the sole provider of funds to the
 // channel. Single funder channels simplify the initial funding workflow, are
@@ -81,6 +93,37 @@ type SingleFundingRequest struct {
 	// of a funding workflow is requesting be required before the channel
 	// is considered fully open.
 	ConfirmationDepth uint32
+
+	// Extended indicates that the message carries the optional trailing
+	// fields below. These are only sent to peers which signal support
+	// for them via the extended-funding local feature, as older peers
+	// would reject the longer message. If unset, then each of the fields
+	// below will be zero, other than ChannelFlags which will signal that
+	// the channel is to be announced, as older initiators always
+	// announce their channels.
+	Extended bool
+
+	// MaxValueInFlight is the maximum total value of outstanding HTLCs
+	// the initiator will accept from the responder at any given time.
+	// This allows the initiator to limit their exposure to HTLCs offered
+	// by the responder.
+	MaxValueInFlight btcutil.Amount
+
+	// ChannelReserve is the minimum balance the initiator requires the
+	// responder to retain within their output on both commitment
+	// transactions. HTLCs offered by the responder which would cause
+	// their balance to dip below the reserve will be rejected.
+	ChannelReserve btcutil.Amount
+
+	// MaxAcceptedHTLCs is the maximum number of outstanding HTLCs
+	// offered by the responder the initiator will accept at any given
+	// time.
+	MaxAcceptedHTLCs uint16
+
+	// ChannelFlags is a bit-field which allows the initiator of the
+	// channel to specify further behavior surrounding the channel.
+	// Currently, the only defined flag is FFAnnounceChannel.
+	ChannelFlags FundingFlag
 }
 
 // NewSingleFundingRequest creates, and returns a new empty SingleFundingRequest.
@@ -112,7 +155,7 @@ func NewSingleFundingRequest(chanID [32]byte, chanType uint8, coinType uint64,
 //
 // This is part of the lnwire.Message interface.
 func (c *SingleFundingRequest) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		c.PendingChannelID[:],
 		&c.ChannelType,
 		&c.CoinType,
@@ -125,6 +168,27 @@ func (c *SingleFundingRequest) Decode(r io.Reader, pver uint32) error {
 		&c.DeliveryPkScript,
 		&c.DustLimit,
 		&c.ConfirmationDepth)
+	if err != nil {
+		return err
+	}
+
+	// The remaining fields are optional. If we've reached the end of the
+	// message, then the initiator doesn't support them.
+	var maxValueInFlight btcutil.Amount
+	err = readElement(r, &maxValueInFlight)
+	if err == io.EOF {
+		c.ChannelFlags = FFAnnounceChannel
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	c.Extended = true
+	c.MaxValueInFlight = maxValueInFlight
+	return readElements(r,
+		&c.ChannelReserve,
+		&c.MaxAcceptedHTLCs,
+		&c.ChannelFlags)
 }
 
 // Encode serializes the target SingleFundingRequest into the passed io.Writer
@@ -133,7 +197,7 @@ func (c *SingleFundingRequest) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *SingleFundingRequest) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		c.PendingChannelID[:],
 		c.ChannelType,
 		c.CoinType,
@@ -146,6 +210,19 @@ func (c *SingleFundingRequest) Encode(w io.Writer, pver uint32) error {
 		c.DeliveryPkScript,
 		c.DustLimit,
 		c.ConfirmationDepth)
+	if err != nil {
+		return err
+	}
+
+	if !c.Extended {
+		return nil
+	}
+
+	return writeElements(w,
+		c.MaxValueInFlight,
+		c.ChannelReserve,
+		c.MaxAcceptedHTLCs,
+		c.ChannelFlags)
 }
 
 // Command returns the uint32 code which uniquely identifies this message as a
@@ -201,6 +278,18 @@ func (c *SingleFundingRequest) MaxPayloadLength(uint32) uint32 {
 	// ConfirmationDepth - 4 bytes
 	length += 4
 
+	// MaxValueInFlight - 8 bytes (optional)
+	length += 8
+
+	// ChannelReserve - 8 bytes (optional)
+	length += 8
+
+	// MaxAcceptedHTLCs - 2 bytes (optional)
+	length += 2
+
+	// ChannelFlags - 1 byte (optional)
+	length++
+
 	return length
 }
 
@@ -240,6 +329,20 @@ func (c *SingleFundingRequest) Validate() error {
 		return fmt.Errorf("ConfirmationDepth must be non-zero")
 	}
 
+	if !c.Extended {
+		return nil
+	}
+
+	if c.MaxValueInFlight < 0 {
+		return fmt.Errorf("MaxValueInFlight cannot be negative")
+	}
+	if c.ChannelReserve < 0 {
+		return fmt.Errorf("ChannelReserve cannot be negative")
+	}
+	if c.MaxAcceptedHTLCs == 0 {
+		return fmt.Errorf("MaxAcceptedHTLCs must be non-zero")
+	}
+
 	// We're good!
 	return nil
 }
@@ -12,6 +12,11 @@ func TestSingleFundingRequestWire(t *testing.T) {
 	delivery := PkScript(bytes.Repeat([]byte{0x02}, 25))
 	sfr := NewSingleFundingRequest(revHash, 21, 22, 23, 5, 5, cdp, cdp,
 		delivery, 540, 10000, 6)
+	sfr.MaxValueInFlight = 100000
+	sfr.ChannelReserve = 1000
+	sfr.MaxAcceptedHTLCs = 30
+	sfr.ChannelFlags = FFAnnounceChannel
+	sfr.Extended = true
 
 	// Next encode the SFR message into an empty bytes buffer.
 	var b bytes.Buffer
@@ -30,4 +35,24 @@ func TestSingleFundingRequestWire(t *testing.T) {
 		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
 			sfr, sfr2)
 	}
+
+	// A message lacking the optional trailing fields, as sent to or by
+	// peers without the extended-funding feature, should decode with
+	// each of those fields left unset, other than the channel being
+	// announced.
+	legacy := *sfr
+	legacy.Extended = false
+	b.Reset()
+	if err := legacy.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode SingleFundingRequest: %v", err)
+	}
+	sfr3 := &SingleFundingRequest{}
+	if err := sfr3.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode SingleFundingRequest: %v", err)
+	}
+	if sfr3.Extended || sfr3.MaxAcceptedHTLCs != 0 ||
+		sfr3.ChannelFlags != FFAnnounceChannel {
+		t.Fatalf("legacy message decoded with optional fields: %#v",
+			sfr3)
+	}
 }
@@ -56,6 +56,30 @@ type SingleFundingResponse struct {
 	// of a funding workflow is requesting be required before the channel
 	// is considered fully open.
 	ConfirmationDepth uint32
+
+	// Extended indicates that the message carries the optional trailing
+	// fields below. These are only sent to peers which signal support
+	// for them via the extended-funding local feature, as older peers
+	// would reject the longer message. If unset, then each of the fields
+	// below will be zero.
+	Extended bool
+
+	// MaxValueInFlight is the maximum total value of outstanding HTLCs
+	// the responder will accept from the initiator at any given time.
+	// This allows the responder to limit their exposure to HTLCs offered
+	// by the initiator.
+	MaxValueInFlight btcutil.Amount
+
+	// ChannelReserve is the minimum balance the responder requires the
+	// initiator to retain within their output on both commitment
+	// transactions. HTLCs offered by the initiator which would cause
+	// their balance to dip below the reserve will be rejected.
+	ChannelReserve btcutil.Amount
+
+	// MaxAcceptedHTLCs is the maximum number of outstanding HTLCs
+	// offered by the initiator the responder will accept at any given
+	// time.
+	MaxAcceptedHTLCs uint16
 }
 
 // NewSingleFundingResponse creates, and returns a new empty
@@ -86,7 +110,7 @@ var _ Message = (*SingleFundingResponse)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *SingleFundingResponse) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		c.PendingChannelID[:],
 		&c.ChannelDerivationPoint,
 		&c.CommitmentKey,
@@ -95,6 +119,25 @@ func (c *SingleFundingResponse) Decode(r io.Reader, pver uint32) error {
 		&c.DeliveryPkScript,
 		&c.DustLimit,
 		&c.ConfirmationDepth)
+	if err != nil {
+		return err
+	}
+
+	// The remaining fields are optional. If we've reached the end of the
+	// message, then the responder doesn't support them.
+	var maxValueInFlight btcutil.Amount
+	err = readElement(r, &maxValueInFlight)
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	c.Extended = true
+	c.MaxValueInFlight = maxValueInFlight
+	return readElements(r,
+		&c.ChannelReserve,
+		&c.MaxAcceptedHTLCs)
 }
 
 // Encode serializes the target SingleFundingResponse into the passed io.Writer
@@ -103,7 +146,7 @@ func (c *SingleFundingResponse) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *SingleFundingResponse) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		c.PendingChannelID[:],
 		c.ChannelDerivationPoint,
 		c.CommitmentKey,
@@ -112,6 +155,18 @@ func (c *SingleFundingResponse) Encode(w io.Writer, pver uint32) error {
 		c.DeliveryPkScript,
 		c.DustLimit,
 		c.ConfirmationDepth)
+	if err != nil {
+		return err
+	}
+
+	if !c.Extended {
+		return nil
+	}
+
+	return writeElements(w,
+		c.MaxValueInFlight,
+		c.ChannelReserve,
+		c.MaxAcceptedHTLCs)
 }
 
 // Command returns the uint32 code which uniquely identifies this message as a
@@ -155,6 +210,15 @@ func (c *SingleFundingResponse) MaxPayloadLength(uint32) uint32 {
 	// ConfirmationDepth - 4 bytes
 	length += 4
 
+	// MaxValueInFlight - 8 bytes (optional)
+	length += 8
+
+	// ChannelReserve - 8 bytes (optional)
+	length += 8
+
+	// MaxAcceptedHTLCs - 2 bytes (optional)
+	length += 2
+
 	return length
 }
 
@@ -186,6 +250,20 @@ func (c *SingleFundingResponse) Validate() error {
 		return fmt.Errorf("ConfirmationDepth must be non-zero")
 	}
 
+	if !c.Extended {
+		return nil
+	}
+
+	if c.MaxValueInFlight < 0 {
+		return fmt.Errorf("MaxValueInFlight cannot be negative")
+	}
+	if c.ChannelReserve < 0 {
+		return fmt.Errorf("ChannelReserve cannot be negative")
+	}
+	if c.MaxAcceptedHTLCs == 0 {
+		return fmt.Errorf("MaxAcceptedHTLCs must be non-zero")
+	}
+
 	// We're good!
 	return nil
 }
@@ -11,6 +11,10 @@ func TestSingleFundingResponseWire(t *testing.T) {
 	delivery := PkScript(bytes.Repeat([]byte{0x02}, 25))
 	sfr := NewSingleFundingResponse(revHash, pubKey, pubKey, pubKey, 5,
 		delivery, 540, 4)
+	sfr.MaxValueInFlight = 100000
+	sfr.ChannelReserve = 1000
+	sfr.MaxAcceptedHTLCs = 30
+	sfr.Extended = true
 
 	// Next encode the SFR message into an empty bytes buffer.
 	var b bytes.Buffer
@@ -29,4 +33,22 @@ func TestSingleFundingResponseWire(t *testing.T) {
 		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
 			sfr, sfr2)
 	}
+
+	// A message lacking the optional trailing fields, as sent to or by
+	// peers without the extended-funding feature, should decode with
+	// each of those fields left unset.
+	legacy := *sfr
+	legacy.Extended = false
+	b.Reset()
+	if err := legacy.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode SingleFundingResponse: %v", err)
+	}
+	sfr3 := &SingleFundingResponse{}
+	if err := sfr3.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode SingleFundingResponse: %v", err)
+	}
+	if sfr3.Extended || sfr3.MaxAcceptedHTLCs != 0 {
+		t.Fatalf("legacy message decoded with optional fields: %#v",
+			sfr3)
+	}
 }
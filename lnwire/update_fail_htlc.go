@@ -37,6 +37,11 @@ const (
 	// IncorrectValue indicates that the HTLC ultimately extended to the
 	// destination did not match the value that was expected.
 	IncorrectValue FailCode = 5
+
+	// ReplayedOnion indicates that the Sphinx packet within the HTLC has
+	// already been processed as part of a prior HTLC, and was therefore
+	// rejected as a replay.
+	ReplayedOnion FailCode = 6
 )
 
 // String returns a human-readable version of the FailCode type.
@@ -62,6 +67,9 @@ func (c FailCode) String() string {
 	case IncorrectValue:
 		return "IncorrectValue: htlc value was wrong"
 
+	case ReplayedOnion:
+		return "ReplayedOnion: sphinx packet has already been processed"
+
 	default:
 		return "unknown reason"
 	}
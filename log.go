@@ -32,8 +32,11 @@ var (
 	hswcLog    = btclog.Disabled
 	utxnLog    = btclog.Disabled
 	brarLog    = btclog.Disabled
+	swprLog    = btclog.Disabled
+	bmprLog    = btclog.Disabled
 	cmgrLog    = btclog.Disabled
 	crtrLog    = btclog.Disabled
+	chbuLog    = btclog.Disabled
 )
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -50,8 +53,11 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"HSWC": hswcLog,
 	"UTXN": utxnLog,
 	"BRAR": brarLog,
+	"SWPR": swprLog,
+	"BMPR": bmprLog,
 	"CMGR": cmgrLog,
 	"CRTR": crtrLog,
+	"CHBU": chbuLog,
 }
 
 // useLogger updates the logger references for subsystemID to logger.  Invalid
@@ -103,6 +109,12 @@ func useLogger(subsystemID string, logger btclog.Logger) {
 	case "BRAR":
 		brarLog = logger
 
+	case "SWPR":
+		swprLog = logger
+
+	case "BMPR":
+		bmprLog = logger
+
 	case "CMGR":
 		cmgrLog = logger
 		connmgr.UseLogger(logger)
@@ -110,6 +122,9 @@ func useLogger(subsystemID string, logger btclog.Logger) {
 	case "CRTR":
 		crtrLog = logger
 		routing.UseLogger(crtrLog)
+
+	case "CHBU":
+		chbuLog = logger
 	}
 }
 
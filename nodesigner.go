@@ -5,20 +5,25 @@ import (
 
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/roasbeef/btcd/btcec"
-	"github.com/roasbeef/btcd/chaincfg/chainhash"
 )
 
-// nodeSigner is an implementation of the MessageSigner interface backed by the
-// identity private key of running lnd node.
+// nodeSigner is an implementation of the MessageSigner interface which signs
+// under the identity key of the running lnd node. Signing is delegated to the
+// wallet's MessageSigner, so the identity private key may be held by a remote
+// signer rather than within the node itself.
 type nodeSigner struct {
-	privKey *btcec.PrivateKey
+	pubKey *btcec.PublicKey
+	signer lnwallet.MessageSigner
 }
 
-// newNodeSigner creates a new instance of the nodeSigner backed by the target
-// private key.
-func newNodeSigner(key *btcec.PrivateKey) *nodeSigner {
+// newNodeSigner creates a new instance of the nodeSigner which signs under
+// the passed identity public key using the passed MessageSigner.
+func newNodeSigner(pubKey *btcec.PublicKey,
+	signer lnwallet.MessageSigner) *nodeSigner {
+
 	return &nodeSigner{
-		privKey: key,
+		pubKey: pubKey,
+		signer: signer,
 	}
 }
 
@@ -30,13 +35,13 @@ func (n *nodeSigner) SignMessage(pubKey *btcec.PublicKey,
 
 	// If this isn't our identity public key, then we'll exit early with an
 	// error as we can't sign with this key.
-	if !pubKey.IsEqual(n.privKey.PubKey()) {
+	if !pubKey.IsEqual(n.pubKey) {
 		return nil, fmt.Errorf("unknown public key")
 	}
 
-	// Otherwise, we'll sign the dsha256 of the target message.
-	digest := chainhash.DoubleHashB(msg)
-	sign, err := n.privKey.Sign(digest)
+	// Otherwise, we'll have the wallet sign the dsha256 of the target
+	// message.
+	sign, err := n.signer.SignMessage(pubKey, msg)
 	if err != nil {
 		return nil, fmt.Errorf("can't sign the message: %v", err)
 	}
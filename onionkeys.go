@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// onionRouter couples the descriptor of an onion key with the Sphinx router
+// which processes packets sent to it.
+//
+// As the onion private key may be held by a remote signer, the router isn't
+// backed by it. Instead, the router is backed by the unit private key, and
+// each packet is processed with its ephemeral key replaced by the ECDH point
+// shared with the onion key, as computed by the wallet's key ring. Scalar
+// multiplication by the unit key leaves the shared point unchanged, so the
+// router derives the same shared secret as it would using the onion key. The
+// packet's ephemeral key isn't covered by its HMAC, so the substitution
+// doesn't affect the packet's authentication.
+type onionRouter struct {
+	keyDesc keychain.KeyDescriptor
+	router  *sphinx.Router
+}
+
+// onionKeyManager manages the rotating onion key used to process incoming
+// Sphinx packets. Onion keys are derived from the wallet's key hierarchy,
+// allowing the manager to only persist the index of the current key. Once a
+// new key is rotated in, it's announced to the network within a new node
+// announcement. As the announcement takes time to propagate, packets
+// constructed using the prior key are accepted until the configured grace
+// period has elapsed. Rotations are performed on a schedule, or may be
+// triggered manually via the RotateOnionKey method.
+type onionKeyManager struct {
+	started uint32
+	stopped uint32
+
+	wallet *lnwallet.LightningWallet
+	db     *channeldb.DB
+
+	// rotationInterval is the interval at which the onion key is rotated.
+	// If zero, then keys are only rotated on demand.
+	rotationInterval time.Duration
+
+	// gracePeriod is the period following a rotation during which packets
+	// for the prior key are still accepted.
+	gracePeriod time.Duration
+
+	// announceKey is called with the new onion public key after each
+	// rotation in order to advertise it to the rest of the network.
+	announceKey func(*btcec.PublicKey) error
+
+	// rotateMtx serializes rotations.
+	rotateMtx sync.Mutex
+
+	// rotated is signalled after each rotation so the rotation schedule
+	// can be reset.
+	rotated chan struct{}
+
+	sync.RWMutex
+	state   *channeldb.OnionKeyState
+	current *onionRouter
+	prev    *onionRouter
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newOnionKeyManager creates a new onionKeyManager, restoring the current
+// (and prior) onion key from the state persisted within the database. If no
+// state exists yet, then the first onion key is derived and written to disk.
+func newOnionKeyManager(wallet *lnwallet.LightningWallet, db *channeldb.DB,
+	rotationInterval, gracePeriod time.Duration,
+	announceKey func(*btcec.PublicKey) error) (*onionKeyManager, error) {
+
+	m := &onionKeyManager{
+		wallet:           wallet,
+		db:               db,
+		rotationInterval: rotationInterval,
+		gracePeriod:      gracePeriod,
+		announceKey:      announceKey,
+		rotated:          make(chan struct{}, 1),
+		quit:             make(chan struct{}),
+	}
+
+	state, err := db.FetchOnionKeyState()
+	switch {
+	// If this is the first time we've started up, then we'll start with
+	// the key at the first index.
+	case err == channeldb.ErrNoOnionKeyState:
+		state = &channeldb.OnionKeyState{
+			RotatedAt: time.Now(),
+		}
+		if err := db.PutOnionKeyState(state); err != nil {
+			return nil, err
+		}
+
+	case err != nil:
+		return nil, err
+	}
+
+	m.state = state
+	m.current, err = m.deriveRouter(state.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll also restore the prior key so packets sent to it continue to
+	// be accepted if we restarted within the grace period.
+	if state.Index > 0 {
+		m.prev, err = m.deriveRouter(state.Index - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Start launches the goroutine which rotates the onion key according to the
+// configured schedule.
+func (m *onionKeyManager) Start() error {
+	if !atomic.CompareAndSwapUint32(&m.started, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Tracef("Starting onion key manager")
+
+	if m.rotationInterval == 0 {
+		return nil
+	}
+
+	m.wg.Add(1)
+	go m.rotator()
+
+	return nil
+}
+
+// Stop gracefully shuts down the onion key manager.
+func (m *onionKeyManager) Stop() error {
+	if !atomic.CompareAndSwapUint32(&m.stopped, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Infof("Onion key manager shutting down")
+
+	close(m.quit)
+	m.wg.Wait()
+
+	return nil
+}
+
+// rotator rotates the onion key each time the rotation interval has elapsed
+// since the last rotation.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *onionKeyManager) rotator() {
+	defer m.wg.Done()
+
+	for {
+		m.RLock()
+		nextRotation := m.state.RotatedAt.Add(m.rotationInterval)
+		m.RUnlock()
+
+		select {
+		case <-time.After(nextRotation.Sub(time.Now())):
+			if _, err := m.RotateOnionKey(); err != nil {
+				srvrLog.Errorf("unable to rotate onion key: %v",
+					err)
+			}
+
+		// The key was rotated on demand, so we'll re-compute the time
+		// of the next rotation.
+		case <-m.rotated:
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// CurrentKey returns the onion public key currently in use.
+func (m *onionKeyManager) CurrentKey() *btcec.PublicKey {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.current.keyDesc.PubKey
+}
+
+// RotateOnionKey derives the next onion key, persists the rotation, then
+// announces the new key to the network. The new onion public key is returned.
+func (m *onionKeyManager) RotateOnionKey() (*btcec.PublicKey, error) {
+	m.rotateMtx.Lock()
+	defer m.rotateMtx.Unlock()
+
+	m.RLock()
+	nextIndex := m.state.Index + 1
+	m.RUnlock()
+
+	nextRouter, err := m.deriveRouter(nextIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll write the rotation to disk before swapping out the key, so
+	// we'll never process a packet using a key we'd be unable to restore.
+	newState := &channeldb.OnionKeyState{
+		Index:     nextIndex,
+		RotatedAt: time.Now(),
+	}
+	if err := m.db.PutOnionKeyState(newState); err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	m.prev = m.current
+	m.current = nextRouter
+	m.state = newState
+	m.Unlock()
+
+	select {
+	case m.rotated <- struct{}{}:
+	default:
+	}
+
+	onionKey := nextRouter.keyDesc.PubKey
+	srvrLog.Infof("Rotated onion key to index=%v, onion_key=%x",
+		nextIndex, onionKey.SerializeCompressed())
+
+	if err := m.announceKey(onionKey); err != nil {
+		return nil, err
+	}
+
+	return onionKey, nil
+}
+
+// processOnionPacket processes the passed onion packet using the current
+// onion key. If that fails and we're still within the grace period of the
+// last rotation, then the prior key is tried as well. The shared secret
+// derived by the onion key used to successfully process the packet is
+// returned along with the processed packet.
+func (m *onionKeyManager) processOnionPacket(onionPkt *sphinx.OnionPacket,
+	assocData []byte) (*sphinx.ProcessedPacket, [32]byte, error) {
+
+	m.RLock()
+	current, prev := m.current, m.prev
+	inGracePeriod := prev != nil &&
+		time.Now().Before(m.state.RotatedAt.Add(m.gracePeriod))
+	m.RUnlock()
+
+	sphinxPacket, sharedSecret, err := m.processWithRouter(current,
+		onionPkt, assocData)
+	if err == nil {
+		return sphinxPacket, sharedSecret, nil
+	}
+	if !inGracePeriod {
+		return nil, [32]byte{}, err
+	}
+
+	sphinxPacket, sharedSecret, prevErr := m.processWithRouter(prev,
+		onionPkt, assocData)
+	if prevErr != nil {
+		return nil, [32]byte{}, err
+	}
+
+	return sphinxPacket, sharedSecret, nil
+}
+
+// processWithRouter processes the passed onion packet using the passed
+// router, returning the processed packet along with the Sphinx shared secret
+// derived from the router's onion key and the packet's ephemeral key.
+func (m *onionKeyManager) processWithRouter(r *onionRouter,
+	onionPkt *sphinx.OnionPacket,
+	assocData []byte) (*sphinx.ProcessedPacket, [32]byte, error) {
+
+	ephemeralKey := onionPkt.Header.EphemeralKey
+	sharedPoint, err := m.wallet.KeyRing.ECDH(r.keyDesc.KeyLocator,
+		ephemeralKey)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	sharedSecret := sha256.Sum256(sharedPoint.SerializeCompressed())
+
+	// We'll process a copy of the packet whose ephemeral key has been
+	// replaced by the shared point, leaving the original packet intact.
+	var b bytes.Buffer
+	if err := onionPkt.Encode(&b); err != nil {
+		return nil, [32]byte{}, err
+	}
+	sharedPkt := &sphinx.OnionPacket{}
+	if err := sharedPkt.Decode(&b); err != nil {
+		return nil, [32]byte{}, err
+	}
+	sharedPkt.Header.EphemeralKey = sharedPoint
+
+	sphinxPacket, err := r.router.ProcessOnionPacket(sharedPkt, assocData)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	// The router blinded the shared point in place of the packet's
+	// ephemeral key, so we'll set the ephemeral key of the packet to be
+	// forwarded to the next hop ourselves.
+	if sphinxPacket.Action == sphinx.MoreHops {
+		sphinxPacket.Packet.Header.EphemeralKey = nextEphemeralKey(
+			ephemeralKey, sharedSecret,
+		)
+	}
+
+	return sphinxPacket, sharedSecret, nil
+}
+
+// deriveRouter derives the onion key at the target index, returning it along
+// with a Sphinx router able to process packets sent to the key.
+func (m *onionKeyManager) deriveRouter(index uint32) (*onionRouter, error) {
+	keyDesc, err := m.wallet.DeriveOnionKey(index)
+	if err != nil {
+		return nil, err
+	}
+
+	unitKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x01})
+
+	return &onionRouter{
+		keyDesc: keyDesc,
+		router:  sphinx.NewRouter(unitKey, activeNetParams.Params),
+	}, nil
+}
+
+// nextEphemeralKey blinds the passed ephemeral key by the blinding factor
+// derived from it and the passed shared secret, yielding the ephemeral key
+// of the packet forwarded to the next hop as specified within BOLT #4.
+func nextEphemeralKey(ephemeralKey *btcec.PublicKey,
+	sharedSecret [32]byte) *btcec.PublicKey {
+
+	blindingFactor := sha256.Sum256(append(
+		ephemeralKey.SerializeCompressed(), sharedSecret[:]...,
+	))
+
+	x, y := btcec.S256().ScalarMult(ephemeralKey.X, ephemeralKey.Y,
+		blindingFactor[:])
+
+	return &btcec.PublicKey{
+		Curve: btcec.S256(),
+		X:     x,
+		Y:     y,
+	}
+}
@@ -7,16 +7,21 @@ import "github.com/roasbeef/btcd/chaincfg"
 var activeNetParams = testNetParams
 
 // netParams couples the p2p parameters of a network with the corresponding RPC
-// port of a daemon running on the particular network.
+// ports of the daemons running on the particular network.
 type netParams struct {
 	*chaincfg.Params
 	rpcPort string
+
+	// bitcoindRPCPort is the default RPC port of a bitcoind node on the
+	// network. It's empty if bitcoind doesn't support the network.
+	bitcoindRPCPort string
 }
 
 // testNetParams contains parameters specific to the 3rd version of the test network.
 var testNetParams = netParams{
-	Params:  &chaincfg.TestNet3Params,
-	rpcPort: "18334",
+	Params:          &chaincfg.TestNet3Params,
+	rpcPort:         "18334",
+	bitcoindRPCPort: "18332",
 }
 
 // simNetParams contains parameters specific to the simulation test network.
@@ -37,6 +37,12 @@ const (
 	// messages to be sent across the wire, requested by objects outside
 	// this struct.
 	outgoingQueueLen = 50
+
+	// maxCloseFeeMultiplier bounds the fee of a cooperative close
+	// transaction proposed by the remote peer. We'll refuse to complete
+	// the close if the fee is more than this many times greater, or
+	// smaller, than the fee we'd propose ourselves.
+	maxCloseFeeMultiplier = 3
 )
 
 // outgoinMsg packages an lnwire.Message to be sent out on the wire, along with
@@ -214,6 +220,13 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 			continue
 		}
 
+		// If we've already broadcast our commitment transaction, then
+		// the channel is being resolved on-chain by the htlc expiry
+		// watcher, so it can no longer be updated.
+		if dbChan.CommitmentBroadcast {
+			continue
+		}
+
 		lnChan, err := lnwallet.NewLightningChannel(p.server.lnwallet.Signer,
 			p.server.chainNotifier, dbChan)
 		if err != nil {
@@ -395,6 +408,12 @@ func (p *peer) readHandler() {
 	var activeChanMtx sync.Mutex
 	activeChanStreams := make(map[lnwire.ChannelID]struct{})
 
+	// updatedChans is the set of channels the remote peer has sent updates
+	// for over this connection. A peer which has lost the state of a
+	// channel is unable to update it, so a request to force close any of
+	// these channels isn't made from a data loss state, and is rejected.
+	updatedChans := make(map[lnwire.ChannelID]struct{})
+
 out:
 	for atomic.LoadInt32(&p.disconnect) == 0 {
 		nextMsg, _, err := p.readNextMessage()
@@ -451,6 +470,59 @@ out:
 			p.remoteCloseChanReqs <- msg
 
 		case *lnwire.Error:
+			// If we've violated the constraints of one of our
+			// channels, then the remote peer has failed the
+			// channel, and will no longer accept updates to it.
+			// We'll fail the channel in turn, force closing it
+			// to recover our balance.
+			if msg.Code == lnwire.ErrConstraintViolation {
+				peerLog.Errorf("ChannelID(%v) failed by peer "+
+					"%v due to constraint violation: %s",
+					msg.ChanID, p, msg.Data)
+
+				err := p.handleChannelFailure(msg.ChanID)
+				if err != nil {
+					peerLog.Errorf("unable to fail "+
+						"ChannelID(%v): %v", msg.ChanID,
+						err)
+				}
+				break
+			}
+
+			// If the remote peer has lost the state of one of our
+			// channels, then it'll request that we force close
+			// the channel so it's able to sweep its balance.
+			if msg.Code == lnwire.ErrForceCloseRequested {
+				if _, ok := updatedChans[msg.ChanID]; ok {
+					peerLog.Errorf("Rejecting force close "+
+						"of ChannelID(%v) requested by "+
+						"peer %v, which has updated "+
+						"the channel", msg.ChanID, p)
+					break
+				}
+
+				err := p.server.htlcExpiry.forceCloseRequested(
+					msg.ChanID, p.addr.IdentityKey)
+				if err != nil {
+					peerLog.Errorf("unable to force close "+
+						"ChannelID(%v) requested by "+
+						"peer %v: %v", msg.ChanID, p,
+						err)
+				}
+				break
+			}
+
+			// If the remote peer rejected the fee we proposed to
+			// cooperatively close a channel, then the close won't
+			// complete, and the channel will need to be closed
+			// once again.
+			if msg.Code == lnwire.ErrUnacceptableCloseFee {
+				peerLog.Errorf("Cooperative close of "+
+					"ChannelID(%v) rejected by peer %v: %s",
+					msg.ChanID, p, msg.Data)
+				break
+			}
+
 			p.server.fundingMgr.processFundingError(msg, p.addr)
 
 		// TODO(roasbeef): create ChanUpdater interface for the below
@@ -483,6 +555,8 @@ out:
 		}
 
 		if isChanUpdate {
+			updatedChans[targetChan] = struct{}{}
+
 			sendUpdate := func() {
 				// Dispatch the commitment update message to
 				// the proper active goroutine dedicated to
@@ -809,8 +883,13 @@ func (p *peer) executeCooperativeClose(channel *lnwallet.LightningChannel) (*cha
 	// generates a signature for the closing tx, as well as a txid of the
 	// closing tx itself, allowing us to watch the network to determine
 	// when the remote node broadcasts the fully signed closing
-	// transaction.
-	sig, txid, err := channel.InitCooperativeClose()
+	// transaction. The fee of the closing transaction is proposed by us,
+	// based on the current estimate of our fee estimator.
+	closeFee, err := p.estimateCloseFee()
+	if err != nil {
+		return nil, err
+	}
+	sig, txid, err := channel.InitCooperativeClose(closeFee)
 	if err != nil {
 		return nil, err
 	}
@@ -829,12 +908,43 @@ func (p *peer) executeCooperativeClose(channel *lnwallet.LightningChannel) (*cha
 	}
 
 	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
-	closeReq := lnwire.NewCloseRequest(chanID, closeSig)
+	closeReq := lnwire.NewCloseRequest(chanID, closeSig, closeFee)
 	p.queueMsg(closeReq, nil)
 
 	return txid, nil
 }
 
+// estimateCloseFee returns the fee we'll propose for a cooperative close
+// transaction, based on the current estimate of the server's fee estimator.
+func (p *peer) estimateCloseFee() (btcutil.Amount, error) {
+	feePerWeight, err := p.server.lnwallet.FeeEstimator.EstimateFeePerWeight(
+		lnwallet.CloseTxConfTarget)
+	if err != nil {
+		return 0, err
+	}
+
+	return feePerWeight * lnwallet.CooperativeCloseTxCost, nil
+}
+
+// validateCloseFee returns an error if the fee proposed by the remote peer for
+// a cooperative close transaction is more than maxCloseFeeMultiplier times
+// greater, or smaller, than the fee we expect it to pay.
+func validateCloseFee(proposedFee, expectedFee btcutil.Amount) error {
+	maxFee := expectedFee * maxCloseFeeMultiplier
+	minFee := expectedFee / maxCloseFeeMultiplier
+
+	switch {
+	case proposedFee > maxFee:
+		return fmt.Errorf("proposed close fee of %v exceeds maximum "+
+			"of %v", proposedFee, maxFee)
+	case proposedFee < minFee:
+		return fmt.Errorf("proposed close fee of %v is below minimum "+
+			"of %v", proposedFee, minFee)
+	}
+
+	return nil
+}
+
 // handleLocalClose kicks-off the workflow to execute a cooperative or forced
 // unilateral closure of the channel initiated by a local subsystem.
 // TODO(roasbeef): if no more active channels with peer call Remove on connMgr
@@ -951,12 +1061,34 @@ func (p *peer) handleRemoteClose(req *lnwire.CloseRequest) {
 
 	chanPoint := channel.ChannelPoint()
 
+	// Before accepting the fee they proposed, we'll ensure it's within
+	// range of our own estimate. Otherwise, the remote peer would be able
+	// to burn our balance to fees, or leave the closing transaction
+	// unable to confirm.
+	expectedFee, err := p.estimateCloseFee()
+	if err != nil {
+		peerLog.Errorf("unable to estimate close fee for "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+	if err := validateCloseFee(req.Fee, expectedFee); err != nil {
+		peerLog.Errorf("unable to complete cooperative close for "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+
+		p.queueMsg(&lnwire.Error{
+			ChanID: req.ChanID,
+			Code:   lnwire.ErrUnacceptableCloseFee,
+			Data:   []byte(err.Error()),
+		}, nil)
+		return
+	}
+
 	// Now that we have their signature for the closure transaction, we
 	// can assemble the final closure transaction, complete with our
-	// signature.
+	// signature, using the fee they proposed.
 	sig := req.RequesterCloseSig
 	closeSig := append(sig.Serialize(), byte(txscript.SigHashAll))
-	closeTx, err := channel.CompleteCooperativeClose(closeSig)
+	closeTx, err := channel.CompleteCooperativeClose(closeSig, req.Fee)
 	if err != nil {
 		peerLog.Errorf("unable to complete cooperative "+
 			"close for ChannelPoint(%v): %v",
@@ -993,6 +1125,28 @@ func (p *peer) handleRemoteClose(req *lnwire.CloseRequest) {
 func wipeChannel(p *peer, channel *lnwallet.LightningChannel) error {
 	chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
 
+	// If the channel's link has already been removed, then this channel
+	// has already been wiped.
+	if !p.removeLink(chanID) {
+		return nil
+	}
+
+	// Finally, we purge the channel's state from the database, leaving a
+	// small summary for historical records.
+	if err := channel.DeleteState(); err != nil {
+		peerLog.Errorf("Unable to delete ChannelPoint(%v) "+
+			"from db: %v", chanID, err)
+		return err
+	}
+
+	return nil
+}
+
+// removeLink removes the target channel from all indexes associated with the
+// peer and tears down its link with the htlcSwitch, without modifying the
+// channel's on-disk state. False is returned if the channel's link had
+// already been removed.
+func (p *peer) removeLink(chanID lnwire.ChannelID) bool {
 	p.activeChanMtx.Lock()
 	delete(p.activeChannels, chanID)
 	p.activeChanMtx.Unlock()
@@ -1012,7 +1166,7 @@ func wipeChannel(p *peer, channel *lnwallet.LightningChannel) error {
 	htlcWireLink, ok := p.htlcManagers[chanID]
 	if !ok {
 		p.htlcManMtx.RUnlock()
-		return nil
+		return false
 	}
 
 	close(htlcWireLink)
@@ -1026,15 +1180,7 @@ func wipeChannel(p *peer, channel *lnwallet.LightningChannel) error {
 	delete(p.htlcManagers, chanID)
 	p.htlcManMtx.RUnlock()
 
-	// Finally, we purge the channel's state from the database, leaving a
-	// small summary for historical records.
-	if err := channel.DeleteState(); err != nil {
-		peerLog.Errorf("Unable to delete ChannelPoint(%v) "+
-			"from db: %v", chanID, err)
-		return err
-	}
-
-	return nil
+	return true
 }
 
 // pendingPayment represents a pending HTLC which has yet to be settled by the
@@ -1073,6 +1219,15 @@ type commitmentState struct {
 	// channel update log, but not yet committed to latest commitment.
 	pendingBatch []*pendingPayment
 
+	// numPendingUpdates is the number of updates (adds, settles and
+	// fails) we've added to the remote party's update log since we last
+	// signed a new commitment for them. Once this reaches the configured
+	// batch size, a new commitment is signed immediately rather than
+	// waiting for the batch timer to fire. Settles and fails are never
+	// held back, as doing so would delay the release of the HTLCs'
+	// funds.
+	numPendingUpdates uint32
+
 	// clearedHTCLs is a map of outgoing HTLCs we've committed to in our
 	// chain which have not yet been settled by the upstream peer.
 	clearedHTCLs map[uint64]*pendingPayment
@@ -1081,11 +1236,6 @@ type commitmentState struct {
 	// forwarding.
 	switchChan chan<- *htlcPacket
 
-	// sphinx is an instance of the Sphinx onion Router for this node. The
-	// router will be used to process all incoming Sphinx packets embedded
-	// within HTLC add messages.
-	sphinx *sphinx.Router
-
 	// pendingCircuits tracks the remote log index of the incoming HTLCs,
 	// mapped to the processed Sphinx packet contained within the HTLC.
 	// This map is used as a staging area between when an HTLC is added to
@@ -1139,7 +1289,6 @@ func (p *peer) htlcManager(channel *lnwallet.LightningChannel,
 		htlcsToCancel:   make(map[uint64]lnwire.FailCode),
 		cancelReasons:   make(map[uint64]lnwire.FailCode),
 		pendingCircuits: make(map[uint64]*sphinx.ProcessedPacket),
-		sphinx:          p.server.sphinx,
 		switchChan:      htlcPlex,
 	}
 
@@ -1148,7 +1297,7 @@ func (p *peer) htlcManager(channel *lnwallet.LightningChannel,
 	//   * also need signals when new invoices are added by the
 	//   invoiceRegistry
 
-	batchTimer := time.NewTicker(50 * time.Millisecond)
+	batchTimer := time.NewTicker(cfg.CommitBatchInterval)
 	defer batchTimer.Stop()
 
 	logCommitTimer := time.NewTicker(100 * time.Millisecond)
@@ -1196,7 +1345,7 @@ out:
 		case <-batchTimer.C:
 			// If the current batch is empty, then we have no work
 			// here.
-			if len(state.pendingBatch) == 0 {
+			if state.numPendingUpdates == 0 {
 				continue
 			}
 
@@ -1360,17 +1509,102 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 		isSettle = true
 	}
 
-	// If this newly added update exceeds the min batch size for adds, or
-	// this is a settle request, then initiate an update.
-	// TODO(roasbeef): enforce max HTLCs in flight limit
-	if len(state.pendingBatch) >= 10 || isSettle {
+	// With the update added to the log, we'll either sign a new commitment
+	// now if this is a settle request or the batch is full, or wait for
+	// the batch timer to fire.
+	state.numPendingUpdates++
+	if isSettle {
 		if err := p.updateCommitTx(state); err != nil {
-			peerLog.Errorf("unable to update "+
-				"commitment: %v", err)
+			peerLog.Errorf("unable to update commitment: %v", err)
 			p.Disconnect()
-			return
 		}
+		return
+	}
+	if err := p.maybeUpdateCommitTx(state); err != nil {
+		peerLog.Errorf("unable to update commitment: %v", err)
+		p.Disconnect()
+	}
+}
+
+// maybeUpdateCommitTx signs a new commitment for the remote party if the
+// number of updates pending within the current batch has reached the
+// configured batch size. Otherwise, the updates will be committed to once the
+// batch timer fires.
+func (p *peer) maybeUpdateCommitTx(state *commitmentState) error {
+	if state.numPendingUpdates < cfg.CommitBatchSize {
+		return nil
 	}
+
+	return p.updateCommitTx(state)
+}
+
+// failChannel fails the link for the channel described by the passed state
+// after the remote peer sent an update we were unable to accept. If the update
+// violated the constraints negotiated during funding, then the remote peer is
+// first sent an error detailing the violation. Afterwards, the connection to
+// the peer is torn down.
+func (p *peer) failChannel(state *commitmentState, err error) {
+	switch err {
+	case lnwallet.ErrMaxHTLCNumber, lnwallet.ErrMaxPendingAmount,
+		lnwallet.ErrBelowChanReserve:
+
+		errMsg := &lnwire.Error{
+			ChanID: state.chanID,
+			Code:   lnwire.ErrConstraintViolation,
+			Data:   []byte(err.Error()),
+		}
+
+		// We'll wait until the error has been written to the wire
+		// before disconnecting, as otherwise the message may never
+		// reach the remote peer.
+		sentChan := make(chan struct{}, 1)
+		p.queueMsg(errMsg, sentChan)
+		select {
+		case <-sentChan:
+		case <-p.quit:
+		}
+	}
+
+	p.Disconnect()
+}
+
+// handleChannelFailure force closes the channel identified by the passed
+// channel ID after the remote peer failed it, due to us violating the
+// constraints negotiated during funding. Once failed, the remote peer will no
+// longer accept updates to the channel, so we broadcast our commitment
+// transaction in order to recover our balance. Channels which aren't shared
+// with the remote peer are ignored.
+func (p *peer) handleChannelFailure(chanID lnwire.ChannelID) error {
+	dbChannels, err := p.server.chanDB.FetchOpenChannels(p.addr.IdentityKey)
+	if err != nil {
+		return err
+	}
+
+	for _, dbChannel := range dbChannels {
+		chanPoint := *dbChannel.ChanID
+		if lnwire.NewChanIDFromOutPoint(&chanPoint) != chanID {
+			continue
+		}
+
+		// If our commitment transaction has already been broadcast,
+		// then the channel is being resolved on-chain.
+		if dbChannel.CommitmentBroadcast {
+			return nil
+		}
+
+		_, height, err := p.server.bio.GetBestBlock()
+		if err != nil {
+			return err
+		}
+
+		peerLog.Warnf("ChannelPoint(%v) failed by peer %v, force "+
+			"closing", chanPoint, p)
+
+		return p.server.htlcExpiry.forceClose(dbChannel, uint32(height))
+	}
+
+	return fmt.Errorf("unable to find ChannelID(%v) shared with peer %v",
+		chanID, p)
 }
 
 // handleUpstreamMsg processes wire messages related to commitment state
@@ -1397,7 +1631,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		index, err := state.channel.ReceiveHTLC(htlcPkt)
 		if err != nil {
 			peerLog.Errorf("Receiving HTLC rejected: %v", err)
-			p.Disconnect()
+			p.failChannel(state, err)
 			return
 		}
 
@@ -1411,7 +1645,9 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		// *forced* to use the same payment hash twice, thereby losing
 		// their money entirely.
 		rHash := htlcPkt.PaymentHash[:]
-		sphinxPacket, err := state.sphinx.ProcessOnionPacket(onionPkt, rHash)
+		sphinxPacket, sharedSecret, err := p.server.onionKeys.processOnionPacket(
+			onionPkt, rHash,
+		)
 		if err != nil {
 			// If we're unable to parse the Sphinx packet, then
 			// we'll cancel the HTLC after the current commitment
@@ -1421,6 +1657,30 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			return
 		}
 
+		// With the packet authenticated, we'll now consult the decayed
+		// log to ensure this onion hasn't already been processed
+		// within a prior HTLC. If it has, then this is a replay and
+		// the HTLC will be cancelled after the current commitment
+		// transition. HTLCs which are re-processed after a restart
+		// are recognized by their channel and log index, so they
+		// won't be flagged.
+		isReplay, err := p.server.decayedLog.checkAndLog(
+			sharedSecret, htlcPkt.Expiry, state.chanID, index,
+		)
+		if err != nil {
+			peerLog.Errorf("unable to check onion pkt for "+
+				"replay: %v", err)
+			state.htlcsToCancel[index] = lnwire.SphinxParseError
+			return
+		}
+		if isReplay {
+			peerLog.Warnf("rejecting replayed onion pkt in "+
+				"HTLC(index=%v, hash=%x) from ChannelPoint(%v)",
+				index, rHash, state.chanPoint)
+			state.htlcsToCancel[index] = lnwire.ReplayedOnion
+			return
+		}
+
 		switch sphinxPacket.Action {
 		// We're the designated payment destination. Therefore we
 		// attempt to see if we have an invoice locally which'll allow
@@ -1494,7 +1754,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		sig := htlcPkt.CommitSig.Serialize()
 		if err := state.channel.ReceiveNewCommitment(sig); err != nil {
 			peerLog.Errorf("unable to accept new commitment: %v", err)
-			p.Disconnect()
+			p.failChannel(state, err)
 			return
 		}
 
@@ -1591,6 +1851,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 					PaymentPreimage: preimage,
 				}
 				p.queueMsg(settleMsg, nil)
+				state.numPendingUpdates++
 
 				delete(state.htlcsToSettle, htlc.Index)
 				settledPayments[htlc.RHash] = struct{}{}
@@ -1621,6 +1882,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				Reason: []byte{byte(reason)},
 			}
 			p.queueMsg(cancelMsg, nil)
+			state.numPendingUpdates++
 			delete(state.htlcsToCancel, htlc.Index)
 
 			cancelledHtlcs[htlc.Index] = struct{}{}
@@ -1660,6 +1922,18 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		}()
 
 		if len(settledPayments) == 0 && len(cancelledHtlcs) == 0 {
+			// The revocation may have opened up our revocation
+			// window, so we'll sign a commitment covering any
+			// updates still pending within the current batch
+			// rather than waiting for the batch timer to fire.
+			if state.numPendingUpdates == 0 {
+				return
+			}
+			if err := p.updateCommitTx(state); err != nil {
+				peerLog.Errorf("unable to update commitment: %v",
+					err)
+				p.Disconnect()
+			}
 			return
 		}
 
@@ -1673,7 +1947,8 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 
 		// With all the settle updates added to the local and remote
 		// HTLC logs, initiate a state transition by updating the
-		// remote commitment chain.
+		// remote commitment chain. The commitment also covers any
+		// updates still pending within the current batch.
 		if err := p.updateCommitTx(state); err != nil {
 			peerLog.Errorf("unable to update commitment: %v", err)
 			p.Disconnect()
@@ -1726,6 +2001,7 @@ func (p *peer) updateCommitTx(state *commitmentState) error {
 	// bool to indicate were waiting for a commitment signature.
 	// TODO(roasbeef): re-slice instead to avoid GC?
 	state.pendingBatch = nil
+	state.numPendingUpdates = 0
 
 	return nil
 }
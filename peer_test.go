@@ -1 +1,38 @@
 package main
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestValidateCloseFee tests that a cooperative close fee proposed by the
+// remote peer is only accepted if it's within maxCloseFeeMultiplier of our own
+// estimate.
+func TestValidateCloseFee(t *testing.T) {
+	const expectedFee = btcutil.Amount(6000)
+
+	tests := []struct {
+		proposedFee btcutil.Amount
+		valid       bool
+	}{
+		{proposedFee: expectedFee, valid: true},
+		{proposedFee: expectedFee * maxCloseFeeMultiplier, valid: true},
+		{proposedFee: expectedFee*maxCloseFeeMultiplier + 1, valid: false},
+		{proposedFee: expectedFee / maxCloseFeeMultiplier, valid: true},
+		{proposedFee: expectedFee/maxCloseFeeMultiplier - 1, valid: false},
+		{proposedFee: 0, valid: false},
+	}
+
+	for _, test := range tests {
+		err := validateCloseFee(test.proposedFee, expectedFee)
+		if test.valid && err != nil {
+			t.Fatalf("expected fee of %v to be accepted: %v",
+				test.proposedFee, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("expected fee of %v to be rejected",
+				test.proposedFee)
+		}
+	}
+}
@@ -0,0 +1,468 @@
+// Package psbt implements the subset of the Partially Signed Bitcoin
+// Transaction format specified within BIP 174 which is required in order to
+// fund channels from an external wallet. Fields which aren't understood by
+// this package are preserved, but otherwise ignored.
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// magic is the sequence of bytes every serialized packet begins with:
+// "psbt" followed by a 0xff separator.
+var magic = [5]byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+const (
+	// globalUnsignedTxType is the key type of the unsigned transaction
+	// within the global map.
+	globalUnsignedTxType = 0x00
+
+	// inputNonWitnessUtxoType is the key type of the full transaction
+	// containing the output spent by an input.
+	inputNonWitnessUtxoType = 0x00
+
+	// inputWitnessUtxoType is the key type of the output spent by a
+	// witness input.
+	inputWitnessUtxoType = 0x01
+
+	// inputFinalScriptSigType is the key type of the final sigScript of an
+	// input.
+	inputFinalScriptSigType = 0x07
+
+	// inputFinalScriptWitnessType is the key type of the final witness of
+	// an input.
+	inputFinalScriptWitnessType = 0x08
+
+	// maxPsbtKeyValueSize is the maximum size of a key or value we'll
+	// read from a serialized packet.
+	maxPsbtKeyValueSize = 4000000
+)
+
+var (
+	// ErrInvalidMagic is returned when a serialized packet doesn't begin
+	// with the PSBT magic bytes.
+	ErrInvalidMagic = errors.New("invalid psbt magic bytes")
+
+	// ErrNoUnsignedTx is returned when a serialized packet doesn't
+	// contain an unsigned transaction.
+	ErrNoUnsignedTx = errors.New("psbt doesn't contain an unsigned " +
+		"transaction")
+
+	// ErrSignedUnsignedTx is returned when the unsigned transaction of a
+	// packet contains a sigScript or witness.
+	ErrSignedUnsignedTx = errors.New("unsigned transaction of psbt " +
+		"contains input scripts")
+
+	// ErrDuplicateKey is returned when a map within a serialized packet
+	// contains the same key twice.
+	ErrDuplicateKey = errors.New("psbt contains a duplicate key")
+
+	// ErrNotFinalized is returned when extracting the final transaction
+	// from a packet in which some inputs are yet to be finalized.
+	ErrNotFinalized = errors.New("psbt inputs aren't all finalized")
+)
+
+// Unknown is a key-value pair of a type this package doesn't understand.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// Input contains the information needed to sign, or the result of signing,
+// a single input of the unsigned transaction.
+type Input struct {
+	// NonWitnessUtxo is the transaction containing the output spent by
+	// the input.
+	NonWitnessUtxo *wire.MsgTx
+
+	// WitnessUtxo is the output spent by the input, if it's a witness
+	// input.
+	WitnessUtxo *wire.TxOut
+
+	// FinalScriptSig is the final sigScript of the input.
+	FinalScriptSig []byte
+
+	// FinalScriptWitness is the final witness of the input.
+	FinalScriptWitness wire.TxWitness
+
+	// Unknowns are the fields of the input this package doesn't
+	// understand.
+	Unknowns []*Unknown
+}
+
+// IsFinalized returns true if the input has a final sigScript or witness.
+func (i *Input) IsFinalized() bool {
+	return len(i.FinalScriptSig) != 0 || len(i.FinalScriptWitness) != 0
+}
+
+// Output contains information concerning a single output of the unsigned
+// transaction.
+type Output struct {
+	// Unknowns are the fields of the output this package doesn't
+	// understand.
+	Unknowns []*Unknown
+}
+
+// Packet is a partially signed transaction, consisting of the unsigned
+// transaction itself, along with information concerning each of its inputs
+// and outputs.
+type Packet struct {
+	// UnsignedTx is the transaction being signed. None of its inputs
+	// contain a sigScript or witness.
+	UnsignedTx *wire.MsgTx
+
+	// Inputs holds the information concerning each input of the unsigned
+	// transaction, in order.
+	Inputs []Input
+
+	// Outputs holds the information concerning each output of the
+	// unsigned transaction, in order.
+	Outputs []Output
+
+	// Unknowns are the global fields this package doesn't understand.
+	Unknowns []*Unknown
+}
+
+// New creates a new packet for the passed unsigned transaction, without any
+// information concerning its inputs and outputs.
+func New(tx *wire.MsgTx) (*Packet, error) {
+	for _, txIn := range tx.TxIn {
+		if len(txIn.SignatureScript) != 0 || len(txIn.Witness) != 0 {
+			return nil, ErrSignedUnsignedTx
+		}
+	}
+
+	return &Packet{
+		UnsignedTx: tx,
+		Inputs:     make([]Input, len(tx.TxIn)),
+		Outputs:    make([]Output, len(tx.TxOut)),
+	}, nil
+}
+
+// keyValue is a single entry within one of the maps of a serialized packet.
+type keyValue struct {
+	key   []byte
+	value []byte
+}
+
+// readMap reads a map from the passed io.Reader, up to and including the
+// separator which terminates it. An error is returned if the map contains a
+// duplicate key.
+func readMap(r io.Reader) ([]keyValue, error) {
+	var (
+		entries []keyValue
+		seen    = make(map[string]struct{})
+	)
+	for {
+		key, err := wire.ReadVarBytes(r, 0, maxPsbtKeyValueSize, "key")
+		if err != nil {
+			return nil, err
+		}
+
+		// An empty key is the separator terminating the map.
+		if len(key) == 0 {
+			return entries, nil
+		}
+
+		if _, ok := seen[string(key)]; ok {
+			return nil, ErrDuplicateKey
+		}
+		seen[string(key)] = struct{}{}
+
+		value, err := wire.ReadVarBytes(r, 0, maxPsbtKeyValueSize,
+			"value")
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, keyValue{key, value})
+	}
+}
+
+// writeMap writes the passed entries to the passed io.Writer as a map,
+// followed by the separator which terminates it.
+func writeMap(w io.Writer, entries []keyValue) error {
+	for _, entry := range entries {
+		if err := wire.WriteVarBytes(w, 0, entry.key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, entry.value); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+// Parse reads a serialized packet from the passed io.Reader.
+func Parse(r io.Reader) (*Packet, error) {
+	var m [5]byte
+	if _, err := io.ReadFull(r, m[:]); err != nil {
+		return nil, err
+	}
+	if m != magic {
+		return nil, ErrInvalidMagic
+	}
+
+	globals, err := readMap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var p *Packet
+	var unknowns []*Unknown
+	for _, entry := range globals {
+		if entry.key[0] != globalUnsignedTxType || len(entry.key) != 1 {
+			unknowns = append(unknowns, &Unknown{entry.key, entry.value})
+			continue
+		}
+
+		// As the unsigned transaction doesn't contain any witnesses, it
+		// always has the same serialization.
+		tx := wire.NewMsgTx(1)
+		if err := tx.Deserialize(bytes.NewReader(entry.value)); err != nil {
+			return nil, err
+		}
+		if p, err = New(tx); err != nil {
+			return nil, err
+		}
+	}
+	if p == nil {
+		return nil, ErrNoUnsignedTx
+	}
+	p.Unknowns = unknowns
+
+	for i := range p.Inputs {
+		entries, err := readMap(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.Inputs[i].parse(entries); err != nil {
+			return nil, fmt.Errorf("invalid input %v: %v", i, err)
+		}
+	}
+
+	for i := range p.Outputs {
+		entries, err := readMap(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			p.Outputs[i].Unknowns = append(p.Outputs[i].Unknowns,
+				&Unknown{entry.key, entry.value})
+		}
+	}
+
+	return p, nil
+}
+
+// parse populates the input from the entries of its serialized map.
+func (i *Input) parse(entries []keyValue) error {
+	for _, entry := range entries {
+		// All of the fields we understand have keys consisting solely
+		// of their type.
+		if len(entry.key) != 1 {
+			i.Unknowns = append(i.Unknowns,
+				&Unknown{entry.key, entry.value})
+			continue
+		}
+
+		r := bytes.NewReader(entry.value)
+		switch entry.key[0] {
+		case inputNonWitnessUtxoType:
+			tx := wire.NewMsgTx(1)
+			if err := tx.Deserialize(r); err != nil {
+				return err
+			}
+			i.NonWitnessUtxo = tx
+
+		case inputWitnessUtxoType:
+			var value int64
+			if err := binary.Read(r, binary.LittleEndian, &value); err != nil {
+				return err
+			}
+			pkScript, err := wire.ReadVarBytes(r, 0,
+				maxPsbtKeyValueSize, "pkScript")
+			if err != nil {
+				return err
+			}
+			i.WitnessUtxo = wire.NewTxOut(value, pkScript)
+
+		case inputFinalScriptSigType:
+			i.FinalScriptSig = entry.value
+
+		case inputFinalScriptWitnessType:
+			numItems, err := wire.ReadVarInt(r, 0)
+			if err != nil {
+				return err
+			}
+			if numItems > uint64(len(entry.value)) {
+				return fmt.Errorf("witness of %v items exceeds "+
+					"its serialized size", numItems)
+			}
+
+			witness := make(wire.TxWitness, numItems)
+			for j := range witness {
+				witness[j], err = wire.ReadVarBytes(r, 0,
+					maxPsbtKeyValueSize, "witness item")
+				if err != nil {
+					return err
+				}
+			}
+			i.FinalScriptWitness = witness
+
+		default:
+			i.Unknowns = append(i.Unknowns,
+				&Unknown{entry.key, entry.value})
+		}
+	}
+
+	return nil
+}
+
+// entries returns the entries of the input's serialized map.
+func (i *Input) entries() ([]keyValue, error) {
+	var entries []keyValue
+
+	if i.NonWitnessUtxo != nil {
+		var b bytes.Buffer
+		if err := i.NonWitnessUtxo.Serialize(&b); err != nil {
+			return nil, err
+		}
+		entries = append(entries, keyValue{
+			[]byte{inputNonWitnessUtxoType}, b.Bytes(),
+		})
+	}
+
+	if i.WitnessUtxo != nil {
+		var b bytes.Buffer
+		err := binary.Write(&b, binary.LittleEndian, i.WitnessUtxo.Value)
+		if err != nil {
+			return nil, err
+		}
+		if err := wire.WriteVarBytes(&b, 0, i.WitnessUtxo.PkScript); err != nil {
+			return nil, err
+		}
+		entries = append(entries, keyValue{
+			[]byte{inputWitnessUtxoType}, b.Bytes(),
+		})
+	}
+
+	if len(i.FinalScriptSig) != 0 {
+		entries = append(entries, keyValue{
+			[]byte{inputFinalScriptSigType}, i.FinalScriptSig,
+		})
+	}
+
+	if len(i.FinalScriptWitness) != 0 {
+		var b bytes.Buffer
+		numItems := uint64(len(i.FinalScriptWitness))
+		if err := wire.WriteVarInt(&b, 0, numItems); err != nil {
+			return nil, err
+		}
+		for _, item := range i.FinalScriptWitness {
+			if err := wire.WriteVarBytes(&b, 0, item); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, keyValue{
+			[]byte{inputFinalScriptWitnessType}, b.Bytes(),
+		})
+	}
+
+	for _, unknown := range i.Unknowns {
+		entries = append(entries, keyValue{unknown.Key, unknown.Value})
+	}
+
+	return entries, nil
+}
+
+// Serialize writes the packet to the passed io.Writer.
+func (p *Packet) Serialize(w io.Writer) error {
+	if _, err := w.Write(magic[:]); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := p.UnsignedTx.Serialize(&b); err != nil {
+		return err
+	}
+	globals := []keyValue{{[]byte{globalUnsignedTxType}, b.Bytes()}}
+	for _, unknown := range p.Unknowns {
+		globals = append(globals, keyValue{unknown.Key, unknown.Value})
+	}
+	if err := writeMap(w, globals); err != nil {
+		return err
+	}
+
+	for _, input := range p.Inputs {
+		entries, err := input.entries()
+		if err != nil {
+			return err
+		}
+		if err := writeMap(w, entries); err != nil {
+			return err
+		}
+	}
+
+	for _, output := range p.Outputs {
+		var entries []keyValue
+		for _, unknown := range output.Unknowns {
+			entries = append(entries,
+				keyValue{unknown.Key, unknown.Value})
+		}
+		if err := writeMap(w, entries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InputUtxo returns the output spent by the input at the passed index, if
+// it's known.
+func (p *Packet) InputUtxo(index int) (*wire.TxOut, error) {
+	input := p.Inputs[index]
+	switch {
+	case input.WitnessUtxo != nil:
+		return input.WitnessUtxo, nil
+
+	case input.NonWitnessUtxo != nil:
+		prevOut := p.UnsignedTx.TxIn[index].PreviousOutPoint
+		if input.NonWitnessUtxo.TxHash() != prevOut.Hash {
+			return nil, fmt.Errorf("utxo of input %v doesn't match "+
+				"its outpoint %v", index, prevOut)
+		}
+		if prevOut.Index >= uint32(len(input.NonWitnessUtxo.TxOut)) {
+			return nil, fmt.Errorf("utxo of input %v doesn't "+
+				"contain output %v", index, prevOut.Index)
+		}
+		return input.NonWitnessUtxo.TxOut[prevOut.Index], nil
+
+	default:
+		return nil, fmt.Errorf("utxo of input %v unknown", index)
+	}
+}
+
+// Extract returns the final, signed transaction once all of the packet's
+// inputs have been finalized.
+func (p *Packet) Extract() (*wire.MsgTx, error) {
+	tx := p.UnsignedTx.Copy()
+	for i, input := range p.Inputs {
+		if !input.IsFinalized() {
+			return nil, ErrNotFinalized
+		}
+
+		tx.TxIn[i].SignatureScript = input.FinalScriptSig
+		tx.TxIn[i].Witness = input.FinalScriptWitness
+	}
+
+	return tx, nil
+}
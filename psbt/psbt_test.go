@@ -0,0 +1,118 @@
+package psbt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// newTestTx creates an unsigned transaction spending a single input to a
+// single output.
+func newTestTx() *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+		Hash:  chainhash.Hash{1},
+		Index: 1,
+	}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(100000, []byte{0x00, 0x14, 0x01}))
+
+	return tx
+}
+
+// TestPacketSerialization tests that a packet survives a round trip through
+// its serialization, and that its final transaction can then be extracted.
+func TestPacketSerialization(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(newTestTx())
+	if err != nil {
+		t.Fatalf("unable to create packet: %v", err)
+	}
+	p.Inputs[0].WitnessUtxo = wire.NewTxOut(200000, []byte{0x00, 0x14})
+	p.Inputs[0].FinalScriptWitness = wire.TxWitness{{0x01}, {0x02, 0x03}}
+	p.Outputs[0].Unknowns = []*Unknown{{Key: []byte{0xfc}, Value: []byte{1}}}
+
+	var b bytes.Buffer
+	if err := p.Serialize(&b); err != nil {
+		t.Fatalf("unable to serialize packet: %v", err)
+	}
+	p2, err := Parse(&b)
+	if err != nil {
+		t.Fatalf("unable to parse packet: %v", err)
+	}
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("packet mismatch: expected %v, got %v", p, p2)
+	}
+
+	utxo, err := p2.InputUtxo(0)
+	if err != nil {
+		t.Fatalf("unable to fetch input utxo: %v", err)
+	}
+	if utxo.Value != 200000 {
+		t.Fatalf("expected utxo value of 200000, got %v", utxo.Value)
+	}
+
+	tx, err := p2.Extract()
+	if err != nil {
+		t.Fatalf("unable to extract tx: %v", err)
+	}
+	if !reflect.DeepEqual(tx.TxIn[0].Witness, p.Inputs[0].FinalScriptWitness) {
+		t.Fatalf("extracted tx has wrong witness: %v",
+			tx.TxIn[0].Witness)
+	}
+	if tx.TxHash() != p.UnsignedTx.TxHash() {
+		t.Fatalf("extracted tx has wrong txid")
+	}
+}
+
+// TestPacketInvalid tests that malformed or incomplete packets are rejected.
+func TestPacketInvalid(t *testing.T) {
+	t.Parallel()
+
+	// A packet without the correct magic bytes should be rejected.
+	_, err := Parse(bytes.NewReader([]byte{0x70, 0x73, 0x62, 0x74, 0x00}))
+	if err != ErrInvalidMagic {
+		t.Fatalf("expected ErrInvalidMagic, got %v", err)
+	}
+
+	// As should one which doesn't contain an unsigned transaction.
+	_, err = Parse(bytes.NewReader(append(magic[:], 0x00)))
+	if err != ErrNoUnsignedTx {
+		t.Fatalf("expected ErrNoUnsignedTx, got %v", err)
+	}
+
+	// A transaction which already contains input scripts can't be used to
+	// create a packet.
+	tx := newTestTx()
+	tx.TxIn[0].SignatureScript = []byte{0x01}
+	if _, err := New(tx); err != ErrSignedUnsignedTx {
+		t.Fatalf("expected ErrSignedUnsignedTx, got %v", err)
+	}
+
+	// Duplicate keys within a map should be rejected.
+	p, err := New(newTestTx())
+	if err != nil {
+		t.Fatalf("unable to create packet: %v", err)
+	}
+	p.Unknowns = []*Unknown{
+		{Key: []byte{0xfc}, Value: []byte{1}},
+		{Key: []byte{0xfc}, Value: []byte{2}},
+	}
+	var b bytes.Buffer
+	if err := p.Serialize(&b); err != nil {
+		t.Fatalf("unable to serialize packet: %v", err)
+	}
+	if _, err := Parse(&b); err != ErrDuplicateKey {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+
+	// Finally, the final transaction can't be extracted until all inputs
+	// have been finalized.
+	p.Unknowns = nil
+	if _, err := p.Extract(); err != ErrNotFinalized {
+		t.Fatalf("expected ErrNotFinalized, got %v", err)
+	}
+}
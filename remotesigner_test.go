@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/remotesigner"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// remoteSignerHarness is a node whose wallet is watch-only, delegating all
+// signing and key derivation to a signer running on a local port. The node
+// itself holds no private key material.
+type remoteSignerHarness struct {
+	t     *testing.T
+	chain *simchain.Chain
+
+	// signerWallet is the wallet backing the signer, which holds every
+	// private key of the node.
+	signerWallet *lnwallet.LightningWallet
+
+	// watchWallet is the watch-only wallet controller of the node, and
+	// wallet the LightningWallet wrapping it.
+	watchWallet *simchain.Wallet
+	wallet      *lnwallet.LightningWallet
+	cdb         *channeldb.DB
+
+	cleanUp func()
+}
+
+// newRemoteSignerHarness starts a new simulated chain, along with a signer
+// serving a wallet on top of it, then creates a node whose watch-only wallet
+// watches the addresses of the signer's wallet.
+func newRemoteSignerHarness(t *testing.T) *remoteSignerHarness {
+	chain := simchain.New(&chaincfg.RegressionNetParams)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+
+	feeEstimator := lnwallet.StaticFeeEstimator{FeeRate: sweepTestFeeRate}
+
+	signerDB, _, cleanUpSignerDB := newTestChannelDB(t)
+	signerSimWallet, err := simchain.NewWallet(chain,
+		bytes.Repeat([]byte{5}, 32))
+	if err != nil {
+		t.Fatalf("unable to create signer wallet: %v", err)
+	}
+	signerWallet, err := lnwallet.NewLightningWallet(signerDB, chain,
+		signerSimWallet, signerSimWallet, signerSimWallet,
+		signerSimWallet, feeEstimator, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("unable to create signer wallet: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	lnrpc.RegisterSignerServer(grpcServer, remotesigner.NewServer(
+		signerWallet.Signer, signerWallet.MessageSigner,
+		signerWallet.KeyRing))
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("unable to dial signer: %v", err)
+	}
+
+	// The node's wallet is only given the public address branch of the
+	// signer's wallet, and derives the rest of its keys via the signer.
+	addrBranch, err := signerSimWallet.AddrBranchPub()
+	if err != nil {
+		t.Fatalf("unable to fetch address branch: %v", err)
+	}
+	watchWallet, err := simchain.NewWatchOnlyWallet(chain, addrBranch)
+	if err != nil {
+		t.Fatalf("unable to create watch-only wallet: %v", err)
+	}
+
+	cdb, _, cleanUpDB := newTestChannelDB(t)
+	keyRing, err := remotesigner.NewKeyRing(conn, cdb)
+	if err != nil {
+		t.Fatalf("unable to create remote key ring: %v", err)
+	}
+	remoteSigner := remotesigner.New(conn, keyRing)
+	wallet := lnwallet.NewWatchOnlyLightningWallet(cdb, chain, watchWallet,
+		keyRing, remoteSigner, remoteSigner, watchWallet, feeEstimator)
+
+	return &remoteSignerHarness{
+		t:            t,
+		chain:        chain,
+		signerWallet: signerWallet,
+		watchWallet:  watchWallet,
+		wallet:       wallet,
+		cdb:          cdb,
+		cleanUp: func() {
+			conn.Close()
+			grpcServer.Stop()
+			cleanUpDB()
+			cleanUpSignerDB()
+			chain.Stop()
+		},
+	}
+}
+
+// TestRemoteSignerNodeHoldsNoKeys tests that a node using a remote signer
+// holds no private keys, yet is able to spend its on-chain funds via the
+// signer.
+func TestRemoteSignerNodeHoldsNoKeys(t *testing.T) {
+	h := newRemoteSignerHarness(t)
+	defer h.cleanUp()
+
+	if _, ok := h.wallet.KeyRing.(keychain.SecretKeyRing); ok {
+		t.Fatalf("remote key ring able to derive private keys")
+	}
+	if _, err := h.watchWallet.FetchRootKey(); err != simchain.ErrWatchOnly {
+		t.Fatalf("expected ErrWatchOnly fetching root key, got %v", err)
+	}
+
+	addr, err := h.wallet.NewAddress(lnwallet.WitnessPubKey, false)
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+	if _, err := h.watchWallet.GetPrivKey(addr); err != simchain.ErrWatchOnly {
+		t.Fatalf("expected ErrWatchOnly fetching private key, got %v",
+			err)
+	}
+
+	// The node's keys are derived by the signer, so they must match
+	// those the signer derives for itself.
+	idKeyDesc, err := h.wallet.IdentityKey()
+	if err != nil {
+		t.Fatalf("unable to derive identity key: %v", err)
+	}
+	signerIDKeyDesc, err := h.signerWallet.IdentityKey()
+	if err != nil {
+		t.Fatalf("unable to derive signer identity key: %v", err)
+	}
+	if !idKeyDesc.PubKey.IsEqual(signerIDKeyDesc.PubKey) {
+		t.Fatalf("identity key mismatch: expected %x, got %x",
+			signerIDKeyDesc.PubKey.SerializeCompressed(),
+			idKeyDesc.PubKey.SerializeCompressed())
+	}
+
+	// Fund the node's address, then spend the funds. The transaction is
+	// signed by the signer, which must first find the address among its
+	// own keys.
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create output script: %v", err)
+	}
+	_, err = h.chain.SendOutputs([]*wire.TxOut{{
+		Value:    int64(btcutil.SatoshiPerBitcoin),
+		PkScript: pkScript,
+	}}, 0)
+	if err != nil {
+		t.Fatalf("unable to fund node: %v", err)
+	}
+	if _, err := h.chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	tx, err := h.wallet.SendOutputsWithOpts([]*wire.TxOut{{
+		Value:    btcutil.SatoshiPerBitcoin / 2,
+		PkScript: []byte{0x00},
+	}}, &lnwallet.CoinSelectOpts{MinConfs: 1})
+	if err != nil {
+		t.Fatalf("unable to send outputs: %v", err)
+	}
+
+	hashCache := txscript.NewTxSigHashes(tx)
+	vm, err := txscript.NewEngine(pkScript, tx, 0,
+		txscript.StandardVerifyFlags, nil, hashCache,
+		btcutil.SatoshiPerBitcoin)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("spend signed by remote signer invalid: %v", err)
+	}
+}
+
+// TestRemoteSignerBrontideHandshake tests that a node using a remote signer
+// is able to complete the brontide handshake, with the ECDH operations using
+// its identity key performed by the signer.
+func TestRemoteSignerBrontideHandshake(t *testing.T) {
+	h := newRemoteSignerHarness(t)
+	defer h.cleanUp()
+
+	idKeyDesc, err := h.wallet.IdentityKey()
+	if err != nil {
+		t.Fatalf("unable to derive identity key: %v", err)
+	}
+	listener, err := brontide.NewListener(
+		keychain.NewPubKeyECDH(idKeyDesc, h.wallet.KeyRing),
+		"127.0.0.1:0",
+	)
+	if err != nil {
+		t.Fatalf("unable to create listener: %v", err)
+	}
+	defer listener.Close()
+
+	remotePriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	netAddr := &lnwire.NetAddress{
+		IdentityKey: idKeyDesc.PubKey,
+		Address:     listener.Addr().(*net.TCPAddr),
+	}
+
+	errChan := make(chan error, 1)
+	connChan := make(chan *brontide.Conn, 1)
+	go func() {
+		conn, err := brontide.Dial(
+			&keychain.PrivKeyECDH{PrivKey: remotePriv}, netAddr,
+		)
+		errChan <- err
+		connChan <- conn
+	}()
+
+	localConn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("unable to accept connection: %v", err)
+	}
+	defer localConn.Close()
+
+	if err := <-errChan; err != nil {
+		t.Fatalf("unable to dial node: %v", err)
+	}
+	remoteConn := <-connChan
+	defer remoteConn.Close()
+
+	msg := []byte("hello")
+	if _, err := remoteConn.Write(msg); err != nil {
+		t.Fatalf("unable to write message: %v", err)
+	}
+	readBuf := make([]byte, len(msg))
+	if _, err := localConn.Read(readBuf); err != nil {
+		t.Fatalf("unable to read message: %v", err)
+	}
+	if !bytes.Equal(readBuf, msg) {
+		t.Fatalf("messages don't match, %v vs %v", string(readBuf),
+			string(msg))
+	}
+}
+
+// TestRemoteSignerOnionPacket tests that a node using a remote signer is able
+// to process onion packets sent to its onion key, with the ECDH operation
+// using the key performed by the signer, and that the packet it forwards is
+// able to be processed by the next hop.
+func TestRemoteSignerOnionPacket(t *testing.T) {
+	h := newRemoteSignerHarness(t)
+	defer h.cleanUp()
+
+	onionKeys, err := newOnionKeyManager(h.wallet, h.cdb, 0, time.Hour,
+		func(*btcec.PublicKey) error { return nil })
+	if err != nil {
+		t.Fatalf("unable to create onion key manager: %v", err)
+	}
+
+	// The onion key must be derived by the signer.
+	onionKey := onionKeys.CurrentKey()
+	signerKeyDesc, err := h.signerWallet.DeriveOnionKey(0)
+	if err != nil {
+		t.Fatalf("unable to derive signer onion key: %v", err)
+	}
+	if !onionKey.IsEqual(signerKeyDesc.PubKey) {
+		t.Fatalf("onion key mismatch: expected %x, got %x",
+			signerKeyDesc.PubKey.SerializeCompressed(),
+			onionKey.SerializeCompressed())
+	}
+
+	nextHopPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	route := []*btcec.PublicKey{onionKey, nextHopPriv.PubKey()}
+	var hopPayloads [][]byte
+	for i := range route {
+		payload := bytes.Repeat([]byte{byte('A' + i)},
+			sphinx.HopPayloadSize)
+		hopPayloads = append(hopPayloads, payload)
+	}
+	assocData := bytes.Repeat([]byte{1}, 32)
+	onionPkt, err := sphinx.NewOnionPacket(route, sessionKey, hopPayloads,
+		assocData)
+	if err != nil {
+		t.Fatalf("unable to create onion packet: %v", err)
+	}
+
+	processed, sharedSecret, err := onionKeys.processOnionPacket(onionPkt,
+		assocData)
+	if err != nil {
+		t.Fatalf("unable to process onion packet: %v", err)
+	}
+	if processed.Action != sphinx.MoreHops {
+		t.Fatalf("expected MoreHops, got %v", processed.Action)
+	}
+
+	x, y := btcec.S256().ScalarMult(onionKey.X, onionKey.Y,
+		sessionKey.D.Bytes())
+	sharedPoint := &btcec.PublicKey{Curve: btcec.S256(), X: x, Y: y}
+	expectedSecret := sha256.Sum256(sharedPoint.SerializeCompressed())
+	if sharedSecret != expectedSecret {
+		t.Fatalf("shared secret mismatch: expected %x, got %x",
+			expectedSecret, sharedSecret)
+	}
+
+	nextHop := sphinx.NewRouter(nextHopPriv, activeNetParams.Params)
+	nextProcessed, err := nextHop.ProcessOnionPacket(processed.Packet,
+		assocData)
+	if err != nil {
+		t.Fatalf("next hop unable to process onion packet: %v", err)
+	}
+	if nextProcessed.Action != sphinx.ExitNode {
+		t.Fatalf("expected ExitNode, got %v", nextProcessed.Action)
+	}
+}
+
+// TestRemoteSignerNodeAnnouncement tests that a node using a remote signer
+// is able to sign its node announcement, along with its onion key, under its
+// identity key.
+func TestRemoteSignerNodeAnnouncement(t *testing.T) {
+	h := newRemoteSignerHarness(t)
+	defer h.cleanUp()
+
+	idKeyDesc, err := h.wallet.IdentityKey()
+	if err != nil {
+		t.Fatalf("unable to derive identity key: %v", err)
+	}
+	onionKeyDesc, err := h.wallet.DeriveOnionKey(0)
+	if err != nil {
+		t.Fatalf("unable to derive onion key: %v", err)
+	}
+	alias, err := lnwire.NewAlias("remotesigner")
+	if err != nil {
+		t.Fatalf("unable to create alias: %v", err)
+	}
+
+	signer := newNodeSigner(idKeyDesc.PubKey, h.wallet.MessageSigner)
+	nodeAnn := &lnwire.NodeAnnouncement{
+		Timestamp: uint32(time.Now().Unix()),
+		NodeID:    idKeyDesc.PubKey,
+		Alias:     alias,
+		Features:  globalFeatures,
+		OnionKey:  onionKeyDesc.PubKey,
+	}
+	nodeAnn.Signature, err = discovery.SignAnnouncement(signer,
+		idKeyDesc.PubKey, nodeAnn)
+	if err != nil {
+		t.Fatalf("unable to sign node announcement: %v", err)
+	}
+	nodeAnn.OnionKeySig, err = discovery.SignOnionKey(signer,
+		idKeyDesc.PubKey, nodeAnn)
+	if err != nil {
+		t.Fatalf("unable to sign onion key: %v", err)
+	}
+
+	data, err := nodeAnn.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to get data to sign: %v", err)
+	}
+	if !nodeAnn.Signature.Verify(chainhash.DoubleHashB(data),
+		idKeyDesc.PubKey) {
+
+		t.Fatalf("signature on node announcement is invalid")
+	}
+
+	data, err = nodeAnn.OnionKeyDataToSign()
+	if err != nil {
+		t.Fatalf("unable to get data to sign: %v", err)
+	}
+	if !nodeAnn.OnionKeySig.Verify(chainhash.DoubleHashB(data),
+		idKeyDesc.PubKey) {
+
+		t.Fatalf("signature on onion key is invalid")
+	}
+
+	// The node signer must refuse to sign under any other key.
+	if _, err := signer.SignMessage(onionKeyDesc.PubKey, data); err == nil {
+		t.Fatalf("node signer signed under a key other than the " +
+			"identity key")
+	}
+}
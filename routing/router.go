@@ -563,7 +563,8 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 		// Now that we have the funding outpoint of the channel, ensure
 		// that it hasn't yet been spent. If so, then this channel has
 		// been closed so we'll ignore it.
-		chanUtxo, err := r.cfg.Chain.GetUtxo(&fundingPoint.Hash, fundingPoint.Index)
+		chanUtxo, err := r.cfg.Chain.GetUtxo(&fundingPoint.Hash,
+			fundingPoint.Index, channelID.BlockHeight)
 		if err != nil {
 			return errors.Errorf("unable to fetch utxo for "+
 				"chan_id=%v: %v", msg.ChannelID, err)
@@ -655,7 +656,7 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 				"chan_id=%v: %v", msg.ChannelID, err)
 		}
 		if _, err := r.cfg.Chain.GetUtxo(&chanPoint.Hash,
-			chanPoint.Index); err != nil {
+			chanPoint.Index, channelID.BlockHeight); err != nil {
 			return errors.Errorf("unable to fetch utxo for "+
 				"chan_id=%v: %v", msg.ChannelID, err)
 		}
@@ -810,13 +811,21 @@ func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte, error) {
 	// in each hop.
 	nodes := make([]*btcec.PublicKey, len(route.Hops))
 	for i, hop := range route.Hops {
+		// If the node has advertised an onion key, then the packet
+		// must be constructed using it, otherwise we'll fall back to
+		// the node's identity key.
+		onionKey := hop.Channel.Node.OnionKey
+		if onionKey == nil {
+			onionKey = hop.Channel.Node.PubKey
+		}
+
 		// We create a new instance of the public key to avoid possibly
 		// mutating the curve parameters, which are unset in a higher
 		// level in order to avoid spamming the logs.
 		pub := btcec.PublicKey{
 			Curve: btcec.S256(),
-			X:     hop.Channel.Node.PubKey.X,
-			Y:     hop.Channel.Node.PubKey.Y,
+			X:     onionKey.X,
+			Y:     onionKey.Y,
 		}
 		nodes[i] = &pub
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
@@ -18,12 +19,15 @@ import (
 
 	"github.com/boltdb/bolt"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/psbt"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/roasbeef/btcd/blockchain"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -104,16 +108,81 @@ func addrPairsToOutputs(addrPairs map[string]int64) ([]*wire.TxOut, error) {
 	return outputs, nil
 }
 
+// newCoinSelectOpts creates the coin selection options specified within an
+// RPC request, returning nil if the caller hasn't specified any options. As
+// an unset min_confs is indistinguishable from zero, unconfirmed outputs are
+// only selected if spendUnconfirmed is set, otherwise outputs require at
+// least one confirmation.
+func newCoinSelectOpts(outpoints []string, satPerVByte uint64,
+	targetConf uint32, minConfs int32, spendUnconfirmed,
+	sendAll bool) (*lnwallet.CoinSelectOpts, error) {
+
+	if len(outpoints) == 0 && satPerVByte == 0 && targetConf == 0 &&
+		minConfs == 0 && !spendUnconfirmed && !sendAll {
+
+		return nil, nil
+	}
+
+	if satPerVByte != 0 && targetConf != 0 {
+		return nil, fmt.Errorf("only one of sat_per_vbyte and " +
+			"target_conf may be set")
+	}
+
+	switch {
+	case minConfs < 0:
+		return nil, fmt.Errorf("min_confs must not be negative")
+
+	case minConfs != 0 && spendUnconfirmed:
+		return nil, fmt.Errorf("min_confs cannot be set along with " +
+			"spend_unconfirmed")
+
+	case minConfs == 0 && !spendUnconfirmed:
+		minConfs = 1
+	}
+
+	opts := &lnwallet.CoinSelectOpts{
+		SatPerVByte: satPerVByte,
+		TargetConf:  targetConf,
+		MinConfs:    minConfs,
+		SpendAll:    sendAll,
+	}
+	for _, s := range outpoints {
+		outpoint, err := parseOutPoint(s)
+		if err != nil {
+			return nil, err
+		}
+		opts.Outpoints = append(opts.Outpoints, *outpoint)
+	}
+
+	return opts, nil
+}
+
 // sendCoinsOnChain makes an on-chain transaction in or to send coins to one or
 // more addresses specified in the passed payment map. The payment map maps an
-// address to a specified output value to be sent to that address.
-func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64) (*chainhash.Hash, error) {
+// address to a specified output value to be sent to that address. If coin
+// selection options are passed, then the transaction is funded according to
+// them. Otherwise, the wallet funds the transaction as it sees fit.
+func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
+	opts *lnwallet.CoinSelectOpts) (*chainhash.Hash, error) {
+
 	outputs, err := addrPairsToOutputs(paymentMap)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.server.lnwallet.SendOutputs(outputs)
+	// A watch-only wallet is unable to sign the transaction itself, so
+	// we'll have the LightningWallet fund it, signing via its Signer.
+	if opts == nil && !r.server.lnwallet.WatchOnly() {
+		return r.server.lnwallet.SendOutputs(outputs)
+	}
+
+	tx, err := r.server.lnwallet.SendOutputsWithOpts(outputs, opts)
+	if err != nil {
+		return nil, err
+	}
+	txid := tx.TxHash()
+
+	return &txid, nil
 }
 
 // SendCoins executes a request to send coins to a particular address. Unlike
@@ -121,10 +190,17 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64) (*chainhash.Ha
 func (r *rpcServer) SendCoins(ctx context.Context,
 	in *lnrpc.SendCoinsRequest) (*lnrpc.SendCoinsResponse, error) {
 
-	rpcsLog.Infof("[sendcoins] addr=%v, amt=%v", in.Addr, btcutil.Amount(in.Amount))
+	rpcsLog.Infof("[sendcoins] addr=%v, amt=%v, send_all=%v", in.Addr,
+		btcutil.Amount(in.Amount), in.SendAll)
+
+	opts, err := newCoinSelectOpts(in.Outpoints, in.SatPerVbyte,
+		in.TargetConf, in.MinConfs, in.SpendUnconfirmed, in.SendAll)
+	if err != nil {
+		return nil, err
+	}
 
 	paymentMap := map[string]int64{in.Addr: in.Amount}
-	txid, err := r.sendCoinsOnChain(paymentMap)
+	txid, err := r.sendCoinsOnChain(paymentMap, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +215,13 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 func (r *rpcServer) SendMany(ctx context.Context,
 	in *lnrpc.SendManyRequest) (*lnrpc.SendManyResponse, error) {
 
-	txid, err := r.sendCoinsOnChain(in.AddrToAmount)
+	opts, err := newCoinSelectOpts(in.Outpoints, in.SatPerVbyte,
+		in.TargetConf, in.MinConfs, in.SpendUnconfirmed, in.SendAll)
+	if err != nil {
+		return nil, err
+	}
+
+	txid, err := r.sendCoinsOnChain(in.AddrToAmount, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -149,6 +231,57 @@ func (r *rpcServer) SendMany(ctx context.Context,
 	return &lnrpc.SendManyResponse{Txid: txid.String()}, nil
 }
 
+// ListUnspent returns the wallet's unspent witness outputs whose number of
+// confirmations lies within the requested range.
+func (r *rpcServer) ListUnspent(ctx context.Context,
+	in *lnrpc.ListUnspentRequest) (*lnrpc.ListUnspentResponse, error) {
+
+	switch {
+	case in.MinConfs < 0:
+		return nil, fmt.Errorf("min_confs must not be negative")
+
+	case in.MaxConfs != 0 && in.MaxConfs < in.MinConfs:
+		return nil, fmt.Errorf("max_confs must not be below min_confs")
+	}
+
+	utxos, err := r.server.lnwallet.ListUnspentWitness(in.MinConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListUnspentResponse{
+		Utxos: make([]*lnrpc.Utxo, 0, len(utxos)),
+	}
+	for _, utxo := range utxos {
+		if in.MaxConfs != 0 && utxo.Confirmations > int64(in.MaxConfs) {
+			continue
+		}
+
+		var addr string
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(utxo.PkScript,
+			activeNetParams.Params)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) > 0 {
+			addr = addrs[0].EncodeAddress()
+		}
+
+		resp.Utxos = append(resp.Utxos, &lnrpc.Utxo{
+			Outpoint:      utxo.OutPoint.String(),
+			Address:       addr,
+			AmountSat:     int64(utxo.Value),
+			PkScript:      hex.EncodeToString(utxo.PkScript),
+			Confirmations: utxo.Confirmations,
+		})
+	}
+
+	rpcsLog.Debugf("[listunspent] min_confs=%v, max_confs=%v, utxos=%v",
+		in.MinConfs, in.MaxConfs, len(resp.Utxos))
+
+	return resp, nil
+}
+
 // NewAddress creates a new address under control of the local wallet.
 func (r *rpcServer) NewAddress(ctx context.Context,
 	in *lnrpc.NewAddressRequest) (*lnrpc.NewAddressResponse, error) {
@@ -247,31 +380,42 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
 	remoteInitialBalance := btcutil.Amount(in.PushSat)
 
-	// Ensure that the initial balance of the remote party (if pushing
-	// satoshis) does not execeed the amount the local party has requested
-	// for funding.
-	if remoteInitialBalance >= localFundingAmt {
-		return fmt.Errorf("amount pushed to remote peer for initial " +
-			"state must be below the local funding amount")
+	coinSelectOpts, err := newCoinSelectOpts(in.Outpoints, in.SatPerVbyte,
+		in.TargetConf, in.MinConfs, in.SpendUnconfirmed, in.SendAll)
+	if err != nil {
+		return err
 	}
 
-	const minChannelSize = btcutil.Amount(6000)
+	// As the funding transaction of a PSBT funded channel is crafted by an
+	// external wallet, none of our own coins are selected for it.
+	if in.PsbtFunding && coinSelectOpts != nil {
+		return fmt.Errorf("coin selection options cannot be used " +
+			"with psbt funding")
+	}
+
+	// If all eligible coins are to be committed to the channel, then the
+	// local funding amount is only known once the funding manager has
+	// performed coin selection, so the checks below are deferred to it.
+	if !in.SendAll {
+		// Ensure that the initial balance of the remote party (if
+		// pushing satoshis) does not execeed the amount the local party
+		// has requested for funding.
+		if remoteInitialBalance >= localFundingAmt {
+			return fmt.Errorf("amount pushed to remote peer for " +
+				"initial state must be below the local funding " +
+				"amount")
+		}
 
-	// Restrict the size of the channel we'll actually open. Atm, we
-	// require the amount to be above 6k satoahis s we currently hard-coded
-	// a 5k satoshi fee in several areas. As a result 6k sat is the min
-	// channnel size that allows us to safely sit above the dust threshold
-	// after fees are applied
-	// TODO(roasbeef): remove after dynamic fees are in
-	if localFundingAmt < minChannelSize {
-		return fmt.Errorf("channel is too small, the minimum channel "+
-			"size is: %v (6k sat)", minChannelSize)
+		// Restrict the size of the channel we'll actually open.
+		if localFundingAmt < minChannelSize {
+			return fmt.Errorf("channel is too small, the minimum "+
+				"channel size is: %v (6k sat)", minChannelSize)
+		}
 	}
 
 	var (
 		nodepubKey      *btcec.PublicKey
 		nodepubKeyBytes []byte
-		err             error
 	)
 
 	// TODO(roasbeef): also return channel ID?
@@ -291,7 +435,8 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
 	updateChan, errChan := r.server.OpenChannel(in.TargetPeerId,
-		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs)
+		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs,
+		coinSelectOpts, in.PsbtFunding, in.Private)
 
 	var outpoint wire.OutPoint
 out:
@@ -370,16 +515,32 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
 	remoteInitialBalance := btcutil.Amount(in.PushSat)
 
+	// A PSBT funded channel requires further interaction from the caller
+	// before the funding transaction is broadcast, so it can only be
+	// opened via the streaming call.
+	if in.PsbtFunding {
+		return nil, fmt.Errorf("psbt funding is only supported by " +
+			"OpenChannel")
+	}
+
+	coinSelectOpts, err := newCoinSelectOpts(in.Outpoints, in.SatPerVbyte,
+		in.TargetConf, in.MinConfs, in.SpendUnconfirmed, in.SendAll)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure that the initial balance of the remote party (if pushing
 	// satoshis) does not execeed the amount the local party has requested
-	// for funding.
-	if remoteInitialBalance >= localFundingAmt {
+	// for funding. If all eligible coins are to be committed to the
+	// channel, then this check is deferred to the funding manager.
+	if !in.SendAll && remoteInitialBalance >= localFundingAmt {
 		return nil, fmt.Errorf("amount pushed to remote peer for " +
 			"initial state must be below the local funding amount")
 	}
 
 	updateChan, errChan := r.server.OpenChannel(in.TargetPeerId,
-		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs)
+		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs,
+		coinSelectOpts, false, in.Private)
 
 	select {
 	// If an error occurs them immediately return the error to the client.
@@ -409,6 +570,289 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 	}
 }
 
+// BatchOpenChannel opens a channel to each of the requested peers, all funded
+// by a single funding transaction. The call returns once the funding
+// transaction has been broadcast, which only happens once every peer has
+// accepted their channel and signed our version of its commitment
+// transaction. If any channel fails, then none of them are opened.
+func (r *rpcServer) BatchOpenChannel(ctx context.Context,
+	in *lnrpc.BatchOpenChannelRequest) (*lnrpc.BatchOpenChannelResponse, error) {
+
+	rpcsLog.Tracef("[batchopenchannel] request to open %v channels",
+		len(in.Channels))
+
+	if len(in.Channels) == 0 {
+		return nil, fmt.Errorf("at least one channel must be specified")
+	}
+
+	// Creation of channels before the wallet syncs up is currently
+	// disallowed.
+	isSynced, err := r.server.lnwallet.IsSynced()
+	if err != nil {
+		return nil, err
+	}
+	if !isSynced {
+		return nil, errors.New("channels cannot be created before the " +
+			"wallet is fully synced")
+	}
+
+	coinSelectOpts, err := newCoinSelectOpts(in.Outpoints, in.SatPerVbyte,
+		in.TargetConf, in.MinConfs, in.SpendUnconfirmed, false)
+	if err != nil {
+		return nil, err
+	}
+
+	chans := make([]*batchChanDesc, len(in.Channels))
+	for i, channel := range in.Channels {
+		nodePubKey, err := btcec.ParsePubKey(channel.NodePubkey,
+			btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		localFundingAmt := btcutil.Amount(channel.LocalFundingAmount)
+		pushAmt := btcutil.Amount(channel.PushSat)
+		if pushAmt >= localFundingAmt {
+			return nil, fmt.Errorf("amount pushed to remote peer "+
+				"%x for initial state must be below the local "+
+				"funding amount", channel.NodePubkey)
+		}
+		if localFundingAmt < minChannelSize {
+			return nil, fmt.Errorf("channel to %x is too small, "+
+				"the minimum channel size is: %v (6k sat)",
+				channel.NodePubkey, minChannelSize)
+		}
+
+		chans[i] = &batchChanDesc{
+			nodeKey:  nodePubKey,
+			localAmt: localFundingAmt,
+			pushAmt:  pushAmt,
+		}
+	}
+
+	updateChans, errChans, err := r.server.BatchOpenChannel(chans,
+		in.NumConfs, coinSelectOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for each channel's first update, which is sent once the shared
+	// funding transaction has been broadcast.
+	resp := &lnrpc.BatchOpenChannelResponse{
+		PendingChannels: make([]*lnrpc.PendingUpdate, len(chans)),
+	}
+	for i := range chans {
+		select {
+		case err := <-errChans[i]:
+			rpcsLog.Errorf("unable to open batch of channels: %v",
+				err)
+			return nil, err
+
+		case fundingUpdate := <-updateChans[i]:
+			openUpdate, ok := fundingUpdate.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+			if !ok {
+				return nil, fmt.Errorf("unexpected funding "+
+					"update: %v", fundingUpdate)
+			}
+			resp.PendingChannels[i] = openUpdate.ChanPending
+
+		case <-r.quit:
+			return nil, nil
+		}
+	}
+
+	rpcsLog.Tracef("[batchopenchannel] opened %v channels", len(chans))
+
+	return resp, nil
+}
+
+// ChannelAcceptor dispatches each inbound channel request to the client,
+// which must respond with a decision on whether the channel should be
+// accepted. If a decision isn't made within the configured timeout, then the
+// default decision is used in its place. Any requests yet to be decided upon
+// once the stream is closed also fall back to the default decision.
+func (r *rpcServer) ChannelAcceptor(stream lnrpc.Lightning_ChannelAcceptorServer) error {
+	client := r.server.chanAcceptor.Subscribe()
+	defer r.server.chanAcceptor.Unsubscribe(client)
+
+	// Launch a new goroutine to read the client's decisions, so that we
+	// can continue to dispatch new requests while waiting on them.
+	done := make(chan struct{})
+	defer close(done)
+
+	errChan := make(chan error, 1)
+	responses := make(chan *lnrpc.ChannelAcceptResponse)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			select {
+			case responses <- resp:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Each request is tracked by its ID until the client responds to it.
+	// We don't use the pending channel ID, as it's chosen by the peer, so
+	// may be shared by requests from different peers.
+	pendingRequests := make(map[uint64]*chanAcceptRequest)
+	for {
+		select {
+		case req := <-client.requests:
+			msg := req.msg
+			pendingRequests[req.id] = req
+
+			err := stream.Send(&lnrpc.ChannelAcceptRequest{
+				NodePubkey:       req.peerKey.SerializeCompressed(),
+				PendingChanId:    msg.PendingChannelID[:],
+				FundingAmt:       int64(msg.FundingAmount),
+				PushAmt:          int64(msg.PushSatoshis),
+				CsvDelay:         msg.CsvDelay,
+				DustLimit:        int64(msg.DustLimit),
+				ChannelReserve:   int64(msg.ChannelReserve),
+				MaxValueInFlight: int64(msg.MaxValueInFlight),
+				MaxAcceptedHtlcs: uint32(msg.MaxAcceptedHTLCs),
+				NumConfs:         msg.ConfirmationDepth,
+				RequestId:        req.id,
+			})
+			if err != nil {
+				return err
+			}
+
+		case resp := <-responses:
+			req, ok := pendingRequests[resp.RequestId]
+			if !ok {
+				rpcsLog.Warnf("[channelacceptor] response for "+
+					"unknown request %v", resp.RequestId)
+				continue
+			}
+			delete(pendingRequests, resp.RequestId)
+
+			req.resp <- &chanAcceptResponse{
+				accept: resp.Accept,
+				reason: resp.Error,
+			}
+
+		// If a request has expired before the client decided on it,
+		// then we'll stop tracking it, as any decision is now moot.
+		case req := <-client.expired:
+			delete(pendingRequests, req.id)
+
+		case err := <-errChan:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// FundingStateStep advances the funding workflow of a PSBT funded channel
+// opened via OpenChannel. First, the unsigned PSBT funding the channel is
+// verified, at which point the responder is asked for their signature for our
+// commitment transaction. Only then should the PSBT be signed, after which
+// the signed funding transaction is presented, and broadcast.
+func (r *rpcServer) FundingStateStep(ctx context.Context,
+	in *lnrpc.FundingTransitionMsg) (*lnrpc.FundingStateStepResp, error) {
+
+	switch {
+	case in.GetPsbtVerify() != nil:
+		step := in.GetPsbtVerify()
+
+		pendingChanID, err := parsePendingChanID(step.PendingChanId)
+		if err != nil {
+			return nil, err
+		}
+		packet, err := psbt.Parse(bytes.NewReader(step.FundedPsbt))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse psbt: %v", err)
+		}
+
+		rpcsLog.Debugf("[fundingstatestep] verifying psbt for "+
+			"pending_chan_id=%x", pendingChanID)
+
+		err = r.server.fundingMgr.VerifyPsbtFunding(pendingChanID, packet)
+		if err != nil {
+			return nil, err
+		}
+
+	case in.GetPsbtFinalize() != nil:
+		step := in.GetPsbtFinalize()
+
+		pendingChanID, err := parsePendingChanID(step.PendingChanId)
+		if err != nil {
+			return nil, err
+		}
+
+		// The signed funding transaction may be presented either as a
+		// finalized PSBT, or as a raw transaction.
+		var fundingTx *wire.MsgTx
+		switch {
+		case len(step.SignedPsbt) != 0 && len(step.FinalRawTx) != 0:
+			return nil, fmt.Errorf("only one of signed_psbt and " +
+				"final_raw_tx may be set")
+
+		case len(step.SignedPsbt) != 0:
+			packet, err := psbt.Parse(bytes.NewReader(step.SignedPsbt))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse psbt: %v",
+					err)
+			}
+			fundingTx, err = packet.Extract()
+			if err != nil {
+				return nil, err
+			}
+
+		case len(step.FinalRawTx) != 0:
+			fundingTx = wire.NewMsgTx(1)
+			err := fundingTx.Deserialize(bytes.NewReader(step.FinalRawTx))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse funding "+
+					"transaction: %v", err)
+			}
+
+		default:
+			return nil, fmt.Errorf("either signed_psbt or " +
+				"final_raw_tx must be set")
+		}
+
+		rpcsLog.Debugf("[fundingstatestep] finalizing psbt for "+
+			"pending_chan_id=%x", pendingChanID)
+
+		err = r.server.fundingMgr.FinalizePsbtFunding(pendingChanID,
+			fundingTx)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown funding state step")
+	}
+
+	return &lnrpc.FundingStateStepResp{}, nil
+}
+
+// parsePendingChanID parses the raw bytes of a pending channel ID.
+func parsePendingChanID(rawID []byte) ([32]byte, error) {
+	var pendingChanID [32]byte
+	if len(rawID) != len(pendingChanID) {
+		return pendingChanID, fmt.Errorf("pending channel ID must be "+
+			"%v bytes, got %v", len(pendingChanID), len(rawID))
+	}
+	copy(pendingChanID[:], rawID)
+
+	return pendingChanID, nil
+}
+
 // CloseChannel attempts to close an active channel identified by its channel
 // point. The actions of this method can additionally be augmented to attempt
 // a force close after a timeout period in the case of an inactive peer.
@@ -650,7 +1094,7 @@ func (r *rpcServer) GetInfo(ctx context.Context,
 		return nil, err
 	}
 
-	idPub := r.server.identityPriv.PubKey().SerializeCompressed()
+	idPub := r.server.identityECDH.PubKey().SerializeCompressed()
 
 	bestHash, bestHeight, err := r.server.bio.GetBestBlock()
 	if err != nil {
@@ -776,7 +1220,10 @@ func (r *rpcServer) PendingChannels(ctx context.Context,
 	includeClose := (in.Status == lnrpc.ChannelStatus_CLOSING) || both
 	rpcsLog.Debugf("[pendingchannels] %v", in.Status)
 
-	var pendingChannels []*lnrpc.PendingChannelResponse_PendingChannel
+	var (
+		pendingChannels     []*lnrpc.PendingChannelResponse_PendingChannel
+		pendingReservations []*lnrpc.PendingReservation
+	)
 	if includeOpen {
 		pendingOpenChans, err := r.server.fundingMgr.PendingChannels()
 		if err != nil {
@@ -792,15 +1239,37 @@ func (r *rpcServer) PendingChannels(ctx context.Context,
 				LocalBalance:  int64(pendingOpen.localBalance),
 				RemoteBalance: int64(pendingOpen.remoteBalance),
 				Status:        lnrpc.ChannelStatus_OPENING,
+				ForgetHeight:  pendingOpen.forgetHeight,
 			}
 			pendingChannels = append(pendingChannels, pendingChan)
 		}
+
+		// We'll also include any reservations which have yet to reach
+		// the point of being committed to disk, along with how long
+		// until each will be cancelled as a zombie.
+		for _, res := range r.server.fundingMgr.PendingReservations() {
+			pub := res.identityPub.SerializeCompressed()
+			expiresIn := res.expiry.Sub(time.Now())
+			if expiresIn < 0 {
+				expiresIn = 0
+			}
+
+			pendingRes := &lnrpc.PendingReservation{
+				IdentityKey:   hex.EncodeToString(pub),
+				PendingChanId: res.pendingChanID[:],
+				Capacity:      int64(res.capacity),
+				Initiator:     res.initiator,
+				ExpiresIn:     int64(expiresIn / time.Second),
+			}
+			pendingReservations = append(pendingReservations, pendingRes)
+		}
 	}
 	if includeClose {
 	}
 
 	return &lnrpc.PendingChannelResponse{
-		PendingChannels: pendingChannels,
+		PendingChannels:     pendingChannels,
+		PendingReservations: pendingReservations,
 	}, nil
 }
 
@@ -852,6 +1321,7 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 			TotalSatoshisSent:     int64(dbChannel.TotalSatoshisSent),
 			TotalSatoshisReceived: int64(dbChannel.TotalSatoshisReceived),
 			NumUpdates:            dbChannel.NumUpdates,
+			Private:               dbChannel.IsPrivate,
 			PendingHtlcs:          make([]*lnrpc.HTLC, len(dbChannel.Htlcs)),
 		}
 
@@ -1183,7 +1653,7 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 	// Finally we also create an encoded payment request which allows the
 	// caller to comactly send the invoice to the payer.
 	payReqString := zpay32.Encode(&zpay32.PaymentRequest{
-		Destination: r.server.identityPriv.PubKey(),
+		Destination: r.server.identityECDH.PubKey(),
 		PaymentHash: rHash,
 		Amount:      btcutil.Amount(invoice.Value),
 	})
@@ -1245,7 +1715,7 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 		CreationDate: invoice.CreationDate.Unix(),
 		Settled:      invoice.Terms.Settled,
 		PaymentRequest: zpay32.Encode(&zpay32.PaymentRequest{
-			Destination: r.server.identityPriv.PubKey(),
+			Destination: r.server.identityECDH.PubKey(),
 			PaymentHash: sha256.Sum256(preimage[:]),
 			Amount:      invoice.Terms.Value,
 		}),
@@ -1274,7 +1744,7 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 			Settled:      dbInvoice.Terms.Settled,
 			CreationDate: dbInvoice.CreationDate.Unix(),
 			PaymentRequest: zpay32.Encode(&zpay32.PaymentRequest{
-				Destination: r.server.identityPriv.PubKey(),
+				Destination: r.server.identityECDH.PubKey(),
 				PaymentHash: sha256.Sum256(paymentPreimge),
 				Amount:      invoiceAmount,
 			}),
@@ -1926,6 +2396,262 @@ func (r *rpcServer) DebugLevel(ctx context.Context,
 	return &lnrpc.DebugLevelResponse{}, nil
 }
 
+// RotateOnionKey derives a new onion key to be used to process incoming Sphinx
+// packets, and announces it to the rest of the network. Packets constructed
+// using the prior onion key are still accepted for the duration of the
+// configured grace period.
+func (r *rpcServer) RotateOnionKey(ctx context.Context,
+	req *lnrpc.RotateOnionKeyRequest) (*lnrpc.RotateOnionKeyResponse, error) {
+
+	rpcsLog.Infof("[rotateonionkey]")
+
+	onionKey, err := r.server.onionKeys.RotateOnionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RotateOnionKeyResponse{
+		OnionKey: hex.EncodeToString(onionKey.SerializeCompressed()),
+	}, nil
+}
+
+// EstimateFee returns the fee rate a transaction must pay in order to confirm
+// within the target number of blocks, as estimated by the fee estimator used
+// to price lnd's own on-chain transactions.
+func (r *rpcServer) EstimateFee(ctx context.Context,
+	req *lnrpc.EstimateFeeRequest) (*lnrpc.EstimateFeeResponse, error) {
+
+	if req.TargetConf <= 0 {
+		return nil, fmt.Errorf("target confirmations must be " +
+			"positive")
+	}
+
+	rpcsLog.Debugf("[estimatefee] target_conf=%v", req.TargetConf)
+
+	feePerWeight, err := r.server.lnwallet.FeeEstimator.EstimateFeePerWeight(
+		uint32(req.TargetConf))
+	if err != nil {
+		return nil, err
+	}
+
+	// A virtual byte is comprised of four weight units.
+	feePerByte := feePerWeight * blockchain.WitnessScaleFactor
+
+	return &lnrpc.EstimateFeeResponse{
+		FeePerWeight: int64(feePerWeight),
+		FeePerByte:   int64(feePerByte),
+	}, nil
+}
+
+// BumpFee raises the fee of an unconfirmed transaction, either by replacing
+// it, or by broadcasting a child transaction spending one of its outputs.
+func (r *rpcServer) BumpFee(ctx context.Context,
+	req *lnrpc.BumpFeeRequest) (*lnrpc.BumpFeeResponse, error) {
+
+	var (
+		txid     *chainhash.Hash
+		outPoint *wire.OutPoint
+		err      error
+	)
+	switch {
+	case req.Txid != "" && req.Outpoint != "":
+		return nil, fmt.Errorf("only one of txid and outpoint may be " +
+			"set")
+
+	case req.Txid != "":
+		txid, err = chainhash.NewHashFromStr(req.Txid)
+		if err != nil {
+			return nil, err
+		}
+
+	case req.Outpoint != "":
+		outPoint, err = parseOutPoint(req.Outpoint)
+		if err != nil {
+			return nil, err
+		}
+		txid = &outPoint.Hash
+
+	default:
+		return nil, fmt.Errorf("either txid or outpoint must be set")
+	}
+
+	// An explicit fee rate takes precedence over the confirmation target.
+	var feePerWeight btcutil.Amount
+	switch {
+	case req.FeePerWeight > 0:
+		feePerWeight = btcutil.Amount(req.FeePerWeight)
+
+	case req.TargetConf > 0:
+		feePerWeight, err = r.server.lnwallet.FeeEstimator.EstimateFeePerWeight(
+			uint32(req.TargetConf))
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("either fee_per_weight or target_conf " +
+			"must be positive")
+	}
+
+	rpcsLog.Debugf("[bumpfee] txid=%v, outpoint=%v, fee_per_weight=%v",
+		txid, outPoint, int64(feePerWeight))
+
+	result, err := r.server.feeBumper.bumpFee(txid, outPoint, feePerWeight)
+	if err != nil {
+		rpcsLog.Errorf("unable to bump fee of tx %v: %v", txid, err)
+		return nil, err
+	}
+
+	return &lnrpc.BumpFeeResponse{
+		Txid:     result.tx.TxHash().String(),
+		Replaced: result.replaced,
+		Fee:      int64(result.fee),
+	}, nil
+}
+
+// parseOutPoint parses an outpoint of the form txid:index.
+func parseOutPoint(s string) (*wire.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("outpoint %v not of the form txid:index",
+			s)
+	}
+
+	txid, err := chainhash.NewHashFromStr(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewOutPoint(txid, uint32(index)), nil
+}
+
+// parseChannelPoint converts the passed RPC channel point, whose funding txid
+// is either set as raw bytes or as a string, into an outpoint.
+func parseChannelPoint(chanPoint *lnrpc.ChannelPoint) (*wire.OutPoint, error) {
+	if chanPoint == nil {
+		return nil, fmt.Errorf("channel point must be set")
+	}
+
+	var (
+		txid *chainhash.Hash
+		err  error
+	)
+	if chanPoint.FundingTxidStr != "" {
+		txid, err = chainhash.NewHashFromStr(chanPoint.FundingTxidStr)
+	} else {
+		txid, err = chainhash.NewHash(chanPoint.FundingTxid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewOutPoint(txid, chanPoint.OutputIndex), nil
+}
+
+// ExportChannelBackup returns the encrypted static backup of the channel
+// identified by the passed channel point.
+func (r *rpcServer) ExportChannelBackup(ctx context.Context,
+	req *lnrpc.ExportChannelBackupRequest) (*lnrpc.ChannelBackup, error) {
+
+	chanPoint, err := parseChannelPoint(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[exportchanbackup] ChannelPoint(%v)", chanPoint)
+
+	chanBackup, err := r.server.chanBackup.exportSingle(*chanPoint)
+	if err != nil {
+		rpcsLog.Errorf("unable to export backup of ChannelPoint(%v): "+
+			"%v", chanPoint, err)
+		return nil, err
+	}
+
+	return &lnrpc.ChannelBackup{
+		ChanPoint:  req.ChanPoint,
+		ChanBackup: chanBackup,
+	}, nil
+}
+
+// SubscribeChannelBackups creates a uni-directional stream (server -> client)
+// over which the encrypted static backup of all our channels is sent each time
+// a channel is opened or closed.
+func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelBackupsServer) error {
+
+	backupClient := r.server.chanBackup.subscribe()
+	defer backupClient.Cancel()
+
+	for {
+		select {
+		case snapshot := <-backupClient.Snapshots:
+			chanPoints := make([]*lnrpc.ChannelPoint,
+				len(snapshot.chanPoints))
+			for i, chanPoint := range snapshot.chanPoints {
+				chanPoints[i] = &lnrpc.ChannelPoint{
+					FundingTxid: chanPoint.Hash[:],
+					OutputIndex: chanPoint.Index,
+				}
+			}
+
+			err := updateStream.Send(&lnrpc.ChanBackupSnapshot{
+				ChanPoints:      chanPoints,
+				MultiChanBackup: snapshot.packedMulti,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// RestoreChannelBackups decrypts the passed static channel backups, then
+// begins the recovery of each channel within them. As the state of the
+// channels has been lost, each remote peer is asked to force close its
+// channel, allowing our balance to be swept back into the wallet.
+func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
+	req *lnrpc.RestoreChanBackupRequest) (*lnrpc.RestoreBackupResponse, error) {
+
+	keyRing := r.server.lnwallet.KeyRing
+
+	singles, err := chanbackup.PackedSingles(req.ChanBackups).Unpack(keyRing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unpack channel backups: %v",
+			err)
+	}
+
+	if len(req.MultiChanBackup) != 0 {
+		packedMulti := chanbackup.PackedMulti(req.MultiChanBackup)
+		multi, err := packedMulti.Unpack(keyRing)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unpack multi "+
+				"channel backup: %v", err)
+		}
+		singles = append(singles, multi.StaticBackups...)
+	}
+
+	if len(singles) == 0 {
+		return nil, fmt.Errorf("no channel backups to restore")
+	}
+
+	rpcsLog.Infof("[restorechanbackup] restoring %v channels",
+		len(singles))
+
+	if err := r.server.chanRestorer.restore(singles); err != nil {
+		rpcsLog.Errorf("unable to restore channels: %v", err)
+		return nil, err
+	}
+
+	return &lnrpc.RestoreBackupResponse{}, nil
+}
+
 // DecodePayReq takes an encoded payment request string and attempts to decode
 // it, returning a full description of the conditions encoded within the
 // payment request.
@@ -13,11 +13,12 @@ import (
 	"time"
 
 	"github.com/boltdb/bolt"
-	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -36,12 +37,13 @@ type server struct {
 	started  int32 // atomic
 	shutdown int32 // atomic
 
-	// identityPriv is the private key used to authenticate any incoming
-	// connections.
-	identityPriv *btcec.PrivateKey
+	// identityECDH performs ECDH with the identity private key in order
+	// to authenticate any incoming connections. The private key itself
+	// may be held by a remote signer.
+	identityECDH keychain.SingleKeyECDH
 
 	// nodeSigner is an implementation of the MessageSigner implementation
-	// that's backed by the identituy private key of the running lnd node.
+	// that signs under the identity key of the running lnd node.
 	nodeSigner *nodeSigner
 
 	// lightningID is the sha256 of the public key corresponding to our
@@ -62,6 +64,10 @@ type server struct {
 	fundingMgr *fundingManager
 	chanDB     *channeldb.DB
 
+	// chanAcceptor dispatches inbound channel requests to any clients of
+	// the ChannelAcceptor RPC for their approval.
+	chanAcceptor *chanAcceptor
+
 	htlcSwitch    *htlcSwitch
 	invoices      *invoiceRegistry
 	breachArbiter *breachArbiter
@@ -72,7 +78,29 @@ type server struct {
 
 	utxoNursery *utxoNursery
 
-	sphinx *sphinx.Router
+	// sweeper batches the outputs resolved by the utxoNursery and
+	// breachArbiter into sweep transactions paying into the wallet.
+	sweeper *sweeper
+
+	// feeBumper raises the fee of stuck transactions on request.
+	feeBumper *feeBumper
+
+	// onionKeys manages the rotating onion key used to process incoming
+	// Sphinx packets.
+	onionKeys *onionKeyManager
+
+	// decayedLog is a persistent log of the Sphinx shared secrets we've
+	// processed, used to reject replayed onion packets.
+	decayedLog *decayedLog
+
+	// htlcExpiry force closes channels whose outgoing HTLCs are nearing
+	// expiry without having been resolved off-chain.
+	htlcExpiry *htlcExpiryWatcher
+
+	// chanBackup maintains the static backups of our channels, while
+	// chanRestorer recovers the funds of channels restored from them.
+	chanBackup   *chanBackupManager
+	chanRestorer *chanRestorer
 
 	connMgr *connmgr.ConnManager
 
@@ -104,21 +132,27 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 	bio lnwallet.BlockChainIO, fundingSigner lnwallet.MessageSigner,
 	wallet *lnwallet.LightningWallet, chanDB *channeldb.DB) (*server, error) {
 
-	privKey, err := wallet.GetIdentitykey()
+	idKeyDesc, err := wallet.IdentityKey()
 	if err != nil {
 		return nil, err
 	}
-	privKey.Curve = btcec.S256()
+	idKeyECDH := keychain.NewPubKeyECDH(idKeyDesc, wallet.KeyRing)
 
 	listeners := make([]net.Listener, len(listenAddrs))
 	for i, addr := range listenAddrs {
-		listeners[i], err = brontide.NewListener(privKey, addr)
+		listeners[i], err = brontide.NewListener(idKeyECDH, addr)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	serializedPubKey := privKey.PubKey().SerializeCompressed()
+	// All subsystems pricing on-chain transactions share the wallet's
+	// fee estimator, and sweep their outputs through a single sweeper.
+	feeEstimator := wallet.FeeEstimator
+	sweeper := newSweeper(wallet, notifier, feeEstimator,
+		cfg.SweepBatchWindow)
+
+	serializedPubKey := idKeyDesc.PubKey.SerializeCompressed()
 	s := &server{
 		lnwallet:      wallet,
 		bio:           bio,
@@ -126,15 +160,15 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		chanDB:        chanDB,
 
 		invoices:    newInvoiceRegistry(chanDB),
-		utxoNursery: newUtxoNursery(chanDB, notifier, wallet),
+		utxoNursery: newUtxoNursery(chanDB, notifier, wallet, sweeper),
+		sweeper:     sweeper,
+		feeBumper:   newFeeBumper(wallet, notifier, chanDB),
 		htlcSwitch:  newHtlcSwitch(),
 
-		identityPriv: privKey,
-		nodeSigner:   newNodeSigner(privKey),
+		identityECDH: idKeyECDH,
+		nodeSigner:   newNodeSigner(idKeyDesc.PubKey, fundingSigner),
 
-		// TODO(roasbeef): derive proper onion key based on rotation
-		// schedule
-		sphinx:      sphinx.NewRouter(privKey, activeNetParams.Params),
+		decayedLog:  newDecayedLog(chanDB, notifier),
 		lightningID: sha256.Sum256(serializedPubKey),
 
 		persistentConnReqs: make(map[string][]*connmgr.ConnReq),
@@ -155,6 +189,13 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		quit:    make(chan struct{}),
 	}
 
+	s.onionKeys, err = newOnionKeyManager(wallet, chanDB,
+		cfg.OnionKeyRotation, cfg.OnionKeyGracePeriod,
+		s.announceOnionKey)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the debug HTLC flag is on, then we invoice a "master debug"
 	// invoice which all outgoing payments will be sent and all incoming
 	// HTLCs with the debug R-Hash immediately settled.
@@ -197,29 +238,36 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 	self := &channeldb.LightningNode{
 		LastUpdate: time.Now(),
 		Addresses:  selfAddrs,
-		PubKey:     privKey.PubKey(),
+		PubKey:     idKeyDesc.PubKey,
 		// TODO(roasbeef): make alias configurable
 		Alias:    alias.String(),
 		Features: globalFeatures,
+		OnionKey: s.onionKeys.CurrentKey(),
 	}
 
 	// Initialize graph with authenticated lightning node. We need to
 	// generate a valid signature in order for other nodes on the network
 	// to accept our announcement.
+	selfAnn := &lnwire.NodeAnnouncement{
+		Timestamp: uint32(self.LastUpdate.Unix()),
+		Addresses: self.Addresses,
+		NodeID:    self.PubKey,
+		Alias:     alias,
+		Features:  self.Features,
+		OnionKey:  self.OnionKey,
+	}
 	self.AuthSig, err = discovery.SignAnnouncement(s.nodeSigner,
-		s.identityPriv.PubKey(),
-		&lnwire.NodeAnnouncement{
-			Timestamp: uint32(self.LastUpdate.Unix()),
-			Addresses: self.Addresses,
-			NodeID:    self.PubKey,
-			Alias:     alias,
-			Features:  self.Features,
-		},
-	)
+		s.identityECDH.PubKey(), selfAnn)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate signature for "+
 			"self node announcement: %v", err)
 	}
+	self.OnionKeySig, err = discovery.SignOnionKey(s.nodeSigner,
+		s.identityECDH.PubKey(), selfAnn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate signature for "+
+			"onion key: %v", err)
+	}
 
 	if err := chanGraph.SetSourceNode(self); err != nil {
 		return nil, fmt.Errorf("can't set self node: %v", err)
@@ -258,7 +306,19 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 	}
 
 	s.rpcServer = newRPCServer(s)
-	s.breachArbiter = newBreachArbiter(wallet, chanDB, notifier, s.htlcSwitch)
+	s.chanBackup = newChanBackupManager(chanDB, wallet.KeyRing,
+		*activeNetParams.GenesisHash,
+		chanbackup.NewMultiFile(cfg.BackupFilePath),
+		func() []chanbackup.Single {
+			return s.chanRestorer.pendingSingles()
+		},
+	)
+	s.chanRestorer = newChanRestorer(s, s.chanBackup.channelsChanged)
+	s.breachArbiter = newBreachArbiter(wallet, chanDB, notifier,
+		s.htlcSwitch, sweeper, s.chanBackup.channelsChanged)
+	s.htlcExpiry = newHtlcExpiryWatcher(s, cfg.HtlcExpiryDelta)
+	s.chanAcceptor = newChanAcceptor(cfg.AcceptorTimeout,
+		cfg.AcceptorRejectDefault)
 
 	var chanIDSeed [32]byte
 	if _, err := rand.Read(chanIDSeed[:]); err != nil {
@@ -266,25 +326,27 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 	}
 
 	s.fundingMgr, err = newFundingManager(fundingConfig{
-		IDKey:    s.identityPriv.PubKey(),
-		Wallet:   wallet,
-		Notifier: s.chainNotifier,
+		IDKey:        s.identityECDH.PubKey(),
+		Wallet:       wallet,
+		FeeEstimator: feeEstimator,
+		Notifier:     s.chainNotifier,
 		SignMessage: func(pubKey *btcec.PublicKey, msg []byte) (*btcec.Signature, error) {
-			if pubKey.IsEqual(s.identityPriv.PubKey()) {
+			if pubKey.IsEqual(s.identityECDH.PubKey()) {
 				return s.nodeSigner.SignMessage(pubKey, msg)
 			}
 
 			return fundingSigner.SignMessage(pubKey, msg)
 		},
-		SendAnnouncement: func(msg lnwire.Message) error {
-			s.discoverSrv.ProcessLocalAnnouncement(msg,
-				s.identityPriv.PubKey())
-			return nil
+		SendAnnouncement: func(msg lnwire.Message) chan error {
+			return s.discoverSrv.ProcessLocalAnnouncement(msg,
+				s.identityECDH.PubKey())
 		},
-		ArbiterChan:    s.breachArbiter.newContracts,
-		SendToPeer:     s.sendToPeer,
-		FindPeer:       s.findPeer,
-		TempChanIDSeed: chanIDSeed,
+		ArbiterChan:          s.breachArbiter.newContracts,
+		UpdateChannelBackups: s.chanBackup.channelsChanged,
+		AcceptChannel:        s.chanAcceptor.Accept,
+		SendToPeer:           s.sendToPeer,
+		FindPeer:             s.findPeer,
+		TempChanIDSeed:       chanIDSeed,
 		FindChannel: func(chanID lnwire.ChannelID) (*lnwallet.LightningChannel, error) {
 			dbChannels, err := chanDB.FetchAllChannels()
 			if err != nil {
@@ -317,7 +379,7 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		RetryDuration:  time.Second * 5,
 		TargetOutbound: 100,
 		GetNewAddress:  nil,
-		Dial:           noiseDial(s.identityPriv),
+		Dial:           noiseDial(s.identityECDH),
 		OnConnection:   s.outboundPeerConnected,
 	})
 	if err != nil {
@@ -464,18 +526,39 @@ func (s *server) Start() error {
 	if err := s.htlcSwitch.Start(); err != nil {
 		return err
 	}
+	if err := s.decayedLog.Start(); err != nil {
+		return err
+	}
+	if err := s.sweeper.Start(); err != nil {
+		return err
+	}
+	if err := s.feeBumper.Start(); err != nil {
+		return err
+	}
 	if err := s.utxoNursery.Start(); err != nil {
 		return err
 	}
 	if err := s.breachArbiter.Start(); err != nil {
 		return err
 	}
+	if err := s.htlcExpiry.Start(); err != nil {
+		return err
+	}
+	if err := s.chanBackup.Start(); err != nil {
+		return err
+	}
+	if err := s.chanRestorer.Start(); err != nil {
+		return err
+	}
 	if err := s.discoverSrv.Start(); err != nil {
 		return err
 	}
 	if err := s.chanRouter.Start(); err != nil {
 		return err
 	}
+	if err := s.onionKeys.Start(); err != nil {
+		return err
+	}
 
 	s.wg.Add(1)
 	go s.queryHandler()
@@ -498,8 +581,15 @@ func (s *server) Stop() error {
 	s.fundingMgr.Stop()
 	s.chanRouter.Stop()
 	s.htlcSwitch.Stop()
+	s.decayedLog.Stop()
+	s.onionKeys.Stop()
+	s.htlcExpiry.Stop()
+	s.chanRestorer.Stop()
+	s.chanBackup.Stop()
 	s.utxoNursery.Stop()
 	s.breachArbiter.Stop()
+	s.sweeper.Stop()
+	s.feeBumper.Stop()
 	s.discoverSrv.Stop()
 	s.lnwallet.Shutdown()
 
@@ -515,6 +605,54 @@ func (s *server) WaitForShutdown() {
 	s.wg.Wait()
 }
 
+// announceOnionKey updates our node within the channel graph to advertise the
+// passed onion key, then broadcasts a freshly signed node announcement to the
+// network.
+func (s *server) announceOnionKey(onionKey *btcec.PublicKey) error {
+	self, err := s.chanDB.ChannelGraph().SourceNode()
+	if err != nil {
+		return err
+	}
+
+	alias, err := lnwire.NewAlias(self.Alias)
+	if err != nil {
+		return err
+	}
+
+	// The new announcement must have a strictly greater timestamp than
+	// our prior announcement, otherwise it'll be ignored as outdated.
+	timestamp := time.Now()
+	if timestamp.Unix() <= self.LastUpdate.Unix() {
+		timestamp = self.LastUpdate.Add(time.Second)
+	}
+
+	nodeAnn := &lnwire.NodeAnnouncement{
+		Timestamp: uint32(timestamp.Unix()),
+		Addresses: self.Addresses,
+		NodeID:    self.PubKey,
+		Alias:     alias,
+		Features:  self.Features,
+		OnionKey:  onionKey,
+	}
+	nodeAnn.Signature, err = discovery.SignAnnouncement(s.nodeSigner,
+		s.identityECDH.PubKey(), nodeAnn)
+	if err != nil {
+		return fmt.Errorf("unable to generate signature for "+
+			"node announcement: %v", err)
+	}
+	nodeAnn.OnionKeySig, err = discovery.SignOnionKey(s.nodeSigner,
+		s.identityECDH.PubKey(), nodeAnn)
+	if err != nil {
+		return fmt.Errorf("unable to generate signature for "+
+			"onion key: %v", err)
+	}
+
+	// Processing the announcement locally will update our node within
+	// the graph, and then broadcast the announcement to our peers.
+	return <-s.discoverSrv.ProcessLocalAnnouncement(nodeAnn,
+		s.identityECDH.PubKey())
+}
+
 // broadcastReq is a message sent to the server by a related subsystem when it
 // wishes to broadcast one or more messages to all connected peers. Thi
 type broadcastReq struct {
@@ -732,6 +870,14 @@ func (s *server) addPeer(p *peer) {
 	// channel router so we can synchronize our view of the channel graph
 	// with this new peer.
 	go s.discoverSrv.SynchronizeNode(p.addr.IdentityKey)
+
+	// If we're restoring any channels with this peer, then we'll request
+	// that it force close them.
+	go s.chanRestorer.peerConnected(p.addr.IdentityKey)
+
+	// Any channels with this peer which have confirmed without the peer
+	// having been sent FundingLocked can now complete their funding flow.
+	go s.fundingMgr.peerConnected(p.addr.IdentityKey)
 }
 
 // removePeer removes the passed peer from the server's state of all active
@@ -795,6 +941,25 @@ type openChanReq struct {
 
 	numConfs uint32
 
+	// coinSelectOpts controls the coin selection performed in order to
+	// fund the channel, along with the fee rate paid by the funding
+	// transaction. If nil, the wallet's defaults are used.
+	coinSelectOpts *lnwallet.CoinSelectOpts
+
+	// psbtFunding indicates that the funding transaction is to be crafted
+	// and signed by an external wallet, and presented to the funding
+	// manager as a PSBT.
+	psbtFunding bool
+
+	// private indicates that the channel shouldn't be announced to the
+	// rest of the network.
+	private bool
+
+	// batch is the batch of channels this channel is to be opened as part
+	// of, sharing a single funding transaction. It's nil if the channel
+	// isn't part of a batch.
+	batch *fundingBatch
+
 	updates chan *lnrpc.OpenStatusUpdate
 	err     chan error
 }
@@ -962,7 +1127,7 @@ func (s *server) handleConnectPeer(msg *connectPeerMsg) {
 			// Attempt to connect to the remote node. If the we
 			// can't make the connection, or the crypto negotiation
 			// breaks down, then return an error to the caller.
-			conn, err := brontide.Dial(s.identityPriv, addr)
+			conn, err := brontide.Dial(s.identityECDH, addr)
 			if err != nil {
 				msg.err <- err
 				return
@@ -1032,10 +1197,14 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 }
 
 // OpenChannel sends a request to the server to open a channel to the specified
-// peer identified by ID with the passed channel funding paramters.
+// peer identified by ID with the passed channel funding paramters. If
+// psbtFunding is true, then the funding transaction is to be crafted by an
+// external wallet, and presented to the funding manager as a PSBT. If private
+// is true, then the channel won't be announced to the rest of the network.
 func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
-	localAmt, pushAmt btcutil.Amount,
-	numConfs uint32) (chan *lnrpc.OpenStatusUpdate, chan error) {
+	localAmt, pushAmt btcutil.Amount, numConfs uint32,
+	coinSelectOpts *lnwallet.CoinSelectOpts, psbtFunding,
+	private bool) (chan *lnrpc.OpenStatusUpdate, chan error) {
 
 	errChan := make(chan error, 1)
 	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
@@ -1046,6 +1215,9 @@ func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
 		localFundingAmt: localAmt,
 		pushAmt:         pushAmt,
 		numConfs:        numConfs,
+		coinSelectOpts:  coinSelectOpts,
+		psbtFunding:     psbtFunding,
+		private:         private,
 		updates:         updateChan,
 		err:             errChan,
 	}
@@ -1055,6 +1227,63 @@ func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
 	return updateChan, errChan
 }
 
+// batchChanDesc describes a single channel to be opened as part of a batch.
+type batchChanDesc struct {
+	nodeKey  *btcec.PublicKey
+	localAmt btcutil.Amount
+	pushAmt  btcutil.Amount
+}
+
+// BatchOpenChannel requests that the funding manager open each of the
+// described channels, all funded by a single funding transaction whose inputs
+// are selected according to the passed options, which may be nil. An update
+// and error channel is returned for each channel, in order. If any channel
+// fails, then they all do.
+func (s *server) BatchOpenChannel(chans []*batchChanDesc, numConfs uint32,
+	coinSelectOpts *lnwallet.CoinSelectOpts) ([]chan *lnrpc.OpenStatusUpdate,
+	[]chan error, error) {
+
+	// Every peer must be connected before we kick off any of the funding
+	// workflows, as otherwise the batch would fail part way through.
+	peers := make([]*peer, len(chans))
+	s.peersMtx.RLock()
+	for i, desc := range chans {
+		pubKeyBytes := desc.nodeKey.SerializeCompressed()
+		peer, ok := s.peersByPub[string(pubKeyBytes)]
+		if !ok {
+			s.peersMtx.RUnlock()
+			return nil, nil, fmt.Errorf("unable to find peer "+
+				"nodeID(%x)", pubKeyBytes)
+		}
+		peers[i] = peer
+	}
+	s.peersMtx.RUnlock()
+
+	batch := newFundingBatch(len(chans), coinSelectOpts)
+
+	updateChans := make([]chan *lnrpc.OpenStatusUpdate, len(chans))
+	errChans := make([]chan error, len(chans))
+	for i, desc := range chans {
+		updateChans[i] = make(chan *lnrpc.OpenStatusUpdate, 1)
+		errChans[i] = make(chan error, 1)
+
+		req := &openChanReq{
+			targetPubkey:    desc.nodeKey,
+			localFundingAmt: desc.localAmt,
+			pushAmt:         desc.pushAmt,
+			numConfs:        numConfs,
+			psbtFunding:     true,
+			batch:           batch,
+			updates:         updateChans[i],
+			err:             errChans[i],
+		}
+
+		go s.fundingMgr.initFundingWorkflow(peers[i].addr, req)
+	}
+
+	return updateChans, errChans, nil
+}
+
 // Peers returns a slice of all active peers.
 func (s *server) Peers() []*peer {
 	resp := make(chan []*peer, 1)
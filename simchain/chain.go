@@ -0,0 +1,615 @@
+package simchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// reorgSafetyLimit is the number of blocks after which a confirmed or
+	// spent notification is no longer re-armed by a re-org.
+	reorgSafetyLimit = 100
+
+	// blockInterval is the amount of time between the timestamps of
+	// consecutive simulated blocks.
+	blockInterval = time.Minute * 10
+)
+
+var (
+	// ErrChainNotifierShuttingDown is returned when attempting to
+	// register for a notification once the chain has been stopped.
+	ErrChainNotifierShuttingDown = errors.New("simchain: chain has been " +
+		"stopped")
+
+	// ErrOutputSpent is returned by the GetUtxo method if the target
+	// output for lookup has already been spent.
+	ErrOutputSpent = errors.New("target output has been spent")
+
+	// forkCounter is used to give each forked chain a unique ID, which is
+	// committed to within the coinbase of the blocks it mines so they
+	// differ from those mined at the same height by other chains.
+	forkCounter uint32 // To be used atomically.
+
+	// fundingCounter is used to create a unique outpoint for each output
+	// funded by SendOutputs.
+	fundingCounter uint64 // To be used atomically.
+)
+
+// txLocation is the location of a transaction within the main chain.
+type txLocation struct {
+	tx     *wire.MsgTx
+	height int32
+	index  uint32
+}
+
+// spendLocation is the location of the input spending an output within the
+// main chain, or the mempool.
+type spendLocation struct {
+	tx         *wire.MsgTx
+	txid       chainhash.Hash
+	inputIndex uint32
+
+	// height is the height of the block containing the spending
+	// transaction, or zero if it's within the mempool.
+	height int32
+}
+
+// Chain is a simulated blockchain held entirely in memory. It implements the
+// chainntnfs.ChainNotifier and lnwallet.BlockChainIO interfaces, along with the
+// transaction publishing side of the lnwallet.WalletController interface,
+// allowing the subsystems built upon them to be tested without a full node.
+// A full lnwallet.WalletController whose outputs live within the chain can be
+// created with NewWallet.
+//
+// Blocks are only ever mined by calling Generate, which includes every
+// transaction within the mempool, so tests have full control over the state
+// of the chain. Re-orgs are triggered by mining a longer chain on a Fork, then
+// Join'ing it back. The chain performs no script or proof-of-work validation
+// whatsoever, so arbitrary spends can be injected by publishing them.
+type Chain struct {
+	stopped int32 // To be used atomically.
+
+	netParams *chaincfg.Params
+
+	// forkID is committed to within the coinbase of each block mined by
+	// the chain.
+	forkID uint32
+
+	// blocks is the main chain, indexed by height.
+	blocks []*wire.MsgBlock
+
+	// blockIndex maps the hash of each block within the main chain to
+	// its height.
+	blockIndex map[chainhash.Hash]int32
+
+	// txIndex and spendIndex index the transactions included within the
+	// main chain, and the inputs they spend.
+	txIndex    map[chainhash.Hash]*txLocation
+	spendIndex map[wire.OutPoint]*spendLocation
+
+	// mempool holds the transactions which will be included within the
+	// next block, in the order they were published. mempoolSpends
+	// indexes the inputs they spend.
+	mempool       []*wire.MsgTx
+	mempoolSpends map[wire.OutPoint]*spendLocation
+
+	clientCounter uint64
+
+	confNotifications  map[chainhash.Hash][]*confirmationsNotification
+	spendNotifications map[wire.OutPoint]map[uint64]*spendNotification
+	blockEpochClients  map[uint64]*blockEpochRegistration
+
+	// reorgDepth is the number of blocks that have been disconnected from
+	// the main chain since a block was last connected.
+	reorgDepth int32
+
+	// wallets are the wallets created on top of the chain, which are
+	// notified of each transaction entering the mempool or main chain.
+	wallets []*Wallet
+
+	mtx sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Ensure Chain implements the interfaces it simulates at compile time.
+var _ chainntnfs.ChainNotifier = (*Chain)(nil)
+var _ lnwallet.BlockChainIO = (*Chain)(nil)
+
+// New creates a new simulated chain consisting of only the genesis block of
+// the passed network.
+func New(netParams *chaincfg.Params) *Chain {
+	c := &Chain{
+		netParams:          netParams,
+		blockIndex:         make(map[chainhash.Hash]int32),
+		txIndex:            make(map[chainhash.Hash]*txLocation),
+		spendIndex:         make(map[wire.OutPoint]*spendLocation),
+		mempoolSpends:      make(map[wire.OutPoint]*spendLocation),
+		confNotifications:  make(map[chainhash.Hash][]*confirmationsNotification),
+		spendNotifications: make(map[wire.OutPoint]map[uint64]*spendNotification),
+		blockEpochClients:  make(map[uint64]*blockEpochRegistration),
+		quit:               make(chan struct{}),
+	}
+
+	c.connectBlock(netParams.GenesisBlock)
+
+	return c
+}
+
+// bestHeight returns the height of the tip of the main chain.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) bestHeight() int32 {
+	return int32(len(c.blocks) - 1)
+}
+
+// Generate mines numBlocks blocks on top of the main chain, the first of which
+// includes all transactions within the mempool. The hashes of the new blocks
+// are returned.
+func (c *Chain) Generate(numBlocks uint32) ([]*chainhash.Hash, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	blockHashes := make([]*chainhash.Hash, 0, numBlocks)
+	for i := uint32(0); i < numBlocks; i++ {
+		block, err := c.mineBlock()
+		if err != nil {
+			return nil, err
+		}
+		c.connectBlock(block)
+
+		blockHash := block.BlockHash()
+		blockHashes = append(blockHashes, &blockHash)
+	}
+
+	return blockHashes, nil
+}
+
+// mineBlock creates a new block on top of the main chain which includes all
+// transactions within the mempool.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) mineBlock() (*wire.MsgBlock, error) {
+	height := c.bestHeight() + 1
+	prevBlock := c.blocks[height-1]
+
+	// The coinbase commits to the height of the block, along with the ID
+	// of the chain, ensuring each block we mine is unique.
+	coinbaseScript, err := txscript.NewScriptBuilder().
+		AddInt64(int64(height)).
+		AddInt64(int64(c.forkID)).
+		Script()
+	if err != nil {
+		return nil, err
+	}
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Index: wire.MaxPrevOutIndex,
+		},
+		SignatureScript: coinbaseScript,
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(&wire.TxOut{
+		Value:    blockchain.CalcBlockSubsidy(height, c.netParams),
+		PkScript: []byte{txscript.OP_TRUE},
+	})
+
+	txns := append([]*wire.MsgTx{coinbase}, c.mempool...)
+
+	utilTxns := make([]*btcutil.Tx, 0, len(txns))
+	for _, tx := range txns {
+		utilTxns = append(utilTxns, btcutil.NewTx(tx))
+	}
+	merkles := blockchain.BuildMerkleTreeStore(utilTxns, false)
+
+	return &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  prevBlock.BlockHash(),
+			MerkleRoot: *merkles[len(merkles)-1],
+			Timestamp:  prevBlock.Header.Timestamp.Add(blockInterval),
+			Bits:       c.netParams.PowLimitBits,
+		},
+		Transactions: txns,
+	}, nil
+}
+
+// connectBlock extends the main chain with the passed block, dispatching any
+// notifications it triggers.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) connectBlock(block *wire.MsgBlock) {
+	height := int32(len(c.blocks))
+	blockHash := block.BlockHash()
+
+	c.blocks = append(c.blocks, block)
+	c.blockIndex[blockHash] = height
+
+	included := make(map[chainhash.Hash]struct{})
+	for i, tx := range block.Transactions {
+		txid := tx.TxHash()
+		included[txid] = struct{}{}
+
+		c.txIndex[txid] = &txLocation{
+			tx:     tx,
+			height: height,
+			index:  uint32(i),
+		}
+
+		if blockchain.IsCoinBaseTx(tx) {
+			continue
+		}
+		for j, txIn := range tx.TxIn {
+			prevOut := txIn.PreviousOutPoint
+			c.spendIndex[prevOut] = &spendLocation{
+				tx:         tx,
+				txid:       txid,
+				inputIndex: uint32(j),
+				height:     height,
+			}
+			delete(c.mempoolSpends, prevOut)
+		}
+	}
+
+	// Any transactions within the block are removed from the mempool.
+	mempool := c.mempool[:0]
+	for _, tx := range c.mempool {
+		if _, ok := included[tx.TxHash()]; !ok {
+			mempool = append(mempool, tx)
+		}
+	}
+	c.mempool = mempool
+
+	c.reorgDepth = 0
+
+	chainntnfs.Log.Debugf("Simulated block connected: height=%v, sha=%v",
+		height, blockHash)
+
+	c.notifyBlockEpochs(height, &blockHash)
+	for i, tx := range block.Transactions {
+		c.checkSpendTrigger(tx, height)
+
+		txid := tx.TxHash()
+		for _, ntfn := range c.confNotifications[txid] {
+			ntfn.confHeight = height
+			ntfn.txIndex = uint32(i)
+		}
+	}
+	c.notifyConfs()
+	c.pruneReorgWindow()
+
+	for _, w := range c.wallets {
+		for _, tx := range block.Transactions {
+			w.notifyTx(&chainTx{
+				tx:        tx,
+				blockHash: &blockHash,
+				height:    height,
+				timestamp: block.Header.Timestamp.Unix(),
+			})
+		}
+	}
+}
+
+// disconnectBlock removes the tip of the main chain, re-arming any
+// notifications triggered by it. The transactions included within the block
+// are returned, so they can be added back to the mempool.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) disconnectBlock() []*wire.MsgTx {
+	height := c.bestHeight()
+	block := c.blocks[height]
+	blockHash := block.BlockHash()
+
+	c.blocks[height] = nil
+	c.blocks = c.blocks[:height]
+	delete(c.blockIndex, blockHash)
+
+	for _, tx := range block.Transactions {
+		delete(c.txIndex, tx.TxHash())
+
+		if blockchain.IsCoinBaseTx(tx) {
+			continue
+		}
+		for _, txIn := range tx.TxIn {
+			delete(c.spendIndex, txIn.PreviousOutPoint)
+		}
+	}
+
+	c.reorgDepth++
+
+	chainntnfs.Log.Debugf("Simulated block disconnected: height=%v, "+
+		"sha=%v, reorg_depth=%v", height, blockHash, c.reorgDepth)
+
+	c.handleBlockDisconnected(height)
+
+	return block.Transactions[1:]
+}
+
+// acceptTransaction adds the passed transaction to the mempool, dispatching
+// any spend notifications it triggers. The transaction is rejected if it
+// double spends a transaction within the main chain or mempool.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) acceptTransaction(tx *wire.MsgTx) error {
+	txid := tx.TxHash()
+	if _, ok := c.txIndex[txid]; ok {
+		return fmt.Errorf("transaction %v already confirmed", txid)
+	}
+	for _, memTx := range c.mempool {
+		if memTx.TxHash() == txid {
+			return fmt.Errorf("transaction %v already in mempool",
+				txid)
+		}
+	}
+
+	for _, txIn := range tx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if _, ok := c.spendIndex[prevOut]; ok {
+			return fmt.Errorf("transaction %v double spends "+
+				"confirmed output %v", txid, prevOut)
+		}
+		if _, ok := c.mempoolSpends[prevOut]; ok {
+			return fmt.Errorf("transaction %v double spends "+
+				"output %v within mempool", txid, prevOut)
+		}
+	}
+
+	for i, txIn := range tx.TxIn {
+		c.mempoolSpends[txIn.PreviousOutPoint] = &spendLocation{
+			tx:         tx,
+			txid:       txid,
+			inputIndex: uint32(i),
+		}
+	}
+	c.mempool = append(c.mempool, tx)
+
+	c.checkSpendTrigger(tx, 0)
+
+	for _, w := range c.wallets {
+		w.notifyTx(&chainTx{tx: tx})
+	}
+
+	return nil
+}
+
+// PublishTransaction adds the passed transaction to the mempool, to be
+// included within the next block mined.
+//
+// This method is a part of the lnwallet.WalletController interface.
+func (c *Chain) PublishTransaction(tx *wire.MsgTx) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.acceptTransaction(tx)
+}
+
+// SendRawTransaction adds the passed transaction to the mempool, returning its
+// txid. The allowHighFees parameter is ignored, and is only present to mirror
+// the RPC client of a full node.
+func (c *Chain) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	if err := c.PublishTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	txid := tx.TxHash()
+	return &txid, nil
+}
+
+// SendOutputs publishes a new transaction creating the passed outputs. The
+// transaction is funded by a unique input from outside of the simulated chain,
+// so the feeRate is ignored.
+func (c *Chain) SendOutputs(outputs []*wire.TxOut,
+	feeRate btcutil.Amount) (*chainhash.Hash, error) {
+
+	var fundingID [8]byte
+	binary.BigEndian.PutUint64(fundingID[:],
+		atomic.AddUint64(&fundingCounter, 1))
+
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash: chainhash.DoubleHashH(fundingID[:]),
+		},
+	})
+	for _, output := range outputs {
+		tx.AddTxOut(output)
+	}
+
+	return c.SendRawTransaction(tx, true)
+}
+
+// GetRawTransaction returns the transaction identified by the passed txid,
+// which may either be within the main chain, or the mempool.
+func (c *Chain) GetRawTransaction(txid *chainhash.Hash) (*btcutil.Tx, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if loc, ok := c.txIndex[*txid]; ok {
+		return btcutil.NewTx(loc.tx), nil
+	}
+	for _, tx := range c.mempool {
+		if tx.TxHash() == *txid {
+			return btcutil.NewTx(tx), nil
+		}
+	}
+
+	return nil, fmt.Errorf("transaction %v not found", txid)
+}
+
+// Fork returns a copy of the chain, sharing the blocks of the main chain up to
+// the current tip, but with an empty mempool and no registered notifications.
+// Blocks mined on the fork are unique to it, so Join'ing a fork which has
+// become longer than the chain triggers a re-org.
+func (c *Chain) Fork() *Chain {
+	c.mtx.Lock()
+	blocks := make([]*wire.MsgBlock, len(c.blocks))
+	copy(blocks, c.blocks)
+	c.mtx.Unlock()
+
+	fork := New(c.netParams)
+	fork.forkID = atomic.AddUint32(&forkCounter, 1)
+	for _, block := range blocks[1:] {
+		fork.connectBlock(block)
+	}
+
+	return fork
+}
+
+// Join re-orgs the chain onto the main chain of the passed fork if it's
+// longer. The transactions within any disconnected blocks that aren't included
+// within the fork are added back to the mempool.
+func (c *Chain) Join(fork *Chain) error {
+	fork.mtx.Lock()
+	forkBlocks := make([]*wire.MsgBlock, len(fork.blocks))
+	copy(forkBlocks, fork.blocks)
+	fork.mtx.Unlock()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if len(forkBlocks) <= len(c.blocks) {
+		return nil
+	}
+
+	// Locate the last block the two chains have in common.
+	forkPoint := c.bestHeight()
+	for forkPoint >= 0 &&
+		c.blocks[forkPoint].BlockHash() != forkBlocks[forkPoint].BlockHash() {
+
+		forkPoint--
+	}
+	if forkPoint < 0 {
+		return fmt.Errorf("fork doesn't share the chain's genesis " +
+			"block")
+	}
+
+	// Disconnect each block after the fork point, collecting the
+	// transactions they included in the order they were originally
+	// confirmed.
+	var disconnected []*wire.MsgTx
+	for c.bestHeight() > forkPoint {
+		disconnected = append(c.disconnectBlock(), disconnected...)
+	}
+
+	// The mempool is re-built once the fork's blocks have been
+	// connected, so we'll clear it for now.
+	mempool := c.mempool
+	c.mempool = nil
+	c.mempoolSpends = make(map[wire.OutPoint]*spendLocation)
+
+	for _, block := range forkBlocks[forkPoint+1:] {
+		c.connectBlock(block)
+	}
+
+	// Finally, return the disconnected transactions to the mempool,
+	// followed by the transactions that were already there. Those that
+	// were included within the fork, or are now double spends, are
+	// dropped.
+	for _, tx := range append(disconnected, mempool...) {
+		if err := c.acceptTransaction(tx); err != nil {
+			chainntnfs.Log.Debugf("Dropping transaction after "+
+				"re-org: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// GetBestBlock returns the current height and hash of the best known block
+// within the main chain.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *Chain) GetBestBlock() (*chainhash.Hash, int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	height := c.bestHeight()
+	blockHash := c.blocks[height].BlockHash()
+
+	return &blockHash, height, nil
+}
+
+// GetUtxo returns the original output referenced by the passed outpoint. As
+// the chain is fully indexed, the heightHint is ignored.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *Chain) GetUtxo(txid *chainhash.Hash, index uint32,
+	heightHint uint32) (*wire.TxOut, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	loc, ok := c.txIndex[*txid]
+	if !ok || index >= uint32(len(loc.tx.TxOut)) {
+		return nil, fmt.Errorf("output %v:%v not found", txid, index)
+	}
+
+	outpoint := wire.OutPoint{Hash: *txid, Index: index}
+	if _, ok := c.spendIndex[outpoint]; ok {
+		return nil, ErrOutputSpent
+	}
+
+	return loc.tx.TxOut[index], nil
+}
+
+// GetTransaction returns the full transaction identified by the passed
+// transaction ID.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *Chain) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	tx, err := c.GetRawTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.MsgTx(), nil
+}
+
+// GetBlockHash returns the hash of the block in the main chain at the given
+// height.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *Chain) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if blockHeight < 0 || blockHeight > int64(c.bestHeight()) {
+		return nil, fmt.Errorf("no block at height %v", blockHeight)
+	}
+
+	blockHash := c.blocks[blockHeight].BlockHash()
+	return &blockHash, nil
+}
+
+// GetBlock returns the block in the main chain identified by the given hash.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *Chain) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	height, ok := c.blockIndex[*blockHash]
+	if !ok {
+		return nil, fmt.Errorf("block %v not found within main chain",
+			blockHash)
+	}
+
+	return c.blocks[height], nil
+}
@@ -0,0 +1,160 @@
+package simchain
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// createSpendTx returns a transaction spending the passed outpoint.
+func createSpendTx(outpoint wire.OutPoint) *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: outpoint,
+	})
+	tx.AddTxOut(&wire.TxOut{
+		Value:    1e8,
+		PkScript: []byte{txscript.OP_TRUE},
+	})
+
+	return tx
+}
+
+// TestChainIO ensures that the simulated chain properly tracks the
+// transactions and outputs within its blocks, and rejects double spends.
+func TestChainIO(t *testing.T) {
+	c := New(&chaincfg.RegressionNetParams)
+	defer c.Stop()
+
+	txid, err := c.SendOutputs([]*wire.TxOut{{
+		Value:    2e8,
+		PkScript: []byte{txscript.OP_TRUE},
+	}}, 0)
+	if err != nil {
+		t.Fatalf("unable to send outputs: %v", err)
+	}
+
+	// Until the transaction is mined, its output shouldn't be found.
+	if _, err := c.GetUtxo(txid, 0, 0); err == nil {
+		t.Fatalf("unconfirmed output found")
+	}
+
+	blockHashes, err := c.Generate(1)
+	if err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	bestHash, bestHeight, err := c.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to get best block: %v", err)
+	}
+	if bestHeight != 1 || *bestHash != *blockHashes[0] {
+		t.Fatalf("incorrect best block: expected %v at height 1, "+
+			"got %v at height %v", blockHashes[0], bestHash,
+			bestHeight)
+	}
+
+	block, err := c.GetBlock(bestHash)
+	if err != nil {
+		t.Fatalf("unable to get block: %v", err)
+	}
+	if len(block.Transactions) != 2 || block.Transactions[1].TxHash() != *txid {
+		t.Fatalf("block doesn't include transaction %v", txid)
+	}
+
+	txOut, err := c.GetUtxo(txid, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to get utxo: %v", err)
+	}
+	if txOut.Value != 2e8 {
+		t.Fatalf("incorrect output value: expected %v, got %v", 2e8,
+			txOut.Value)
+	}
+
+	// Spending the output should be accepted once, but a second,
+	// conflicting spend should be rejected.
+	outpoint := wire.OutPoint{Hash: *txid}
+	if err := c.PublishTransaction(createSpendTx(outpoint)); err != nil {
+		t.Fatalf("unable to publish spend: %v", err)
+	}
+	doubleSpend := createSpendTx(outpoint)
+	doubleSpend.TxOut[0].Value = 5e7
+	if err := c.PublishTransaction(doubleSpend); err == nil {
+		t.Fatalf("double spend accepted")
+	}
+
+	if _, err := c.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	if _, err := c.GetUtxo(txid, 0, 0); err != ErrOutputSpent {
+		t.Fatalf("expected ErrOutputSpent, got %v", err)
+	}
+}
+
+// TestChainReorg ensures that joining a longer fork re-orgs the chain onto it,
+// returning the transactions within the disconnected blocks to the mempool.
+func TestChainReorg(t *testing.T) {
+	c := New(&chaincfg.RegressionNetParams)
+	defer c.Stop()
+
+	if _, err := c.Generate(5); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	fork := c.Fork()
+
+	txid, err := c.SendOutputs([]*wire.TxOut{{
+		Value:    2e8,
+		PkScript: []byte{txscript.OP_TRUE},
+	}}, 0)
+	if err != nil {
+		t.Fatalf("unable to send outputs: %v", err)
+	}
+	if _, err := c.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+
+	// Joining a fork which is no longer than the chain should have no
+	// effect.
+	if _, err := fork.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	if err := c.Join(fork); err != nil {
+		t.Fatalf("unable to join fork: %v", err)
+	}
+	if _, err := c.GetUtxo(txid, 0, 0); err != nil {
+		t.Fatalf("output disconnected without a re-org: %v", err)
+	}
+
+	// Once the fork is longer, joining it should disconnect the block
+	// confirming the transaction.
+	if _, err := fork.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	if err := c.Join(fork); err != nil {
+		t.Fatalf("unable to join fork: %v", err)
+	}
+
+	forkHash, forkHeight, _ := fork.GetBestBlock()
+	bestHash, bestHeight, _ := c.GetBestBlock()
+	if bestHeight != forkHeight || *bestHash != *forkHash {
+		t.Fatalf("chain didn't re-org onto fork: expected %v at "+
+			"height %v, got %v at height %v", forkHash, forkHeight,
+			bestHash, bestHeight)
+	}
+	if _, err := c.GetUtxo(txid, 0, 0); err == nil {
+		t.Fatalf("output found after being re-org'd out")
+	}
+
+	// The transaction should have been returned to the mempool, so it
+	// should be confirmed once again within the next block.
+	if _, err := c.GetRawTransaction(txid); err != nil {
+		t.Fatalf("transaction not returned to mempool: %v", err)
+	}
+	if _, err := c.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	if _, err := c.GetUtxo(txid, 0, 0); err != nil {
+		t.Fatalf("unable to get utxo: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package simchain
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg"
+)
+
+const (
+	// notifierType uniquely identifies the ChainNotifier backed by the
+	// simulated chain.
+	notifierType = "simchain"
+
+	// walletType uniquely identifies the WalletController backed by the
+	// simulated chain.
+	walletType = "simchain"
+)
+
+// createNewNotifier creates a new simulated chain, returning it as an
+// instance of the ChainNotifier interface. Callers wishing to drive the chain
+// can recover the *Chain with a type assertion.
+func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("incorrect number of arguments to .New(...), "+
+			"expected 1, instead passed %v", len(args))
+	}
+
+	netParams, ok := args[0].(*chaincfg.Params)
+	if !ok {
+		return nil, fmt.Errorf("first argument to simchain.New is " +
+			"incorrect, expected a *chaincfg.Params")
+	}
+
+	return New(netParams), nil
+}
+
+// createNewWallet creates a new wallet on top of an existing simulated chain,
+// returning it as an instance of the WalletController interface.
+func createNewWallet(args ...interface{}) (lnwallet.WalletController, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("incorrect number of arguments to .New(...), "+
+			"expected 2, instead passed %v", len(args))
+	}
+
+	chain, ok := args[0].(*Chain)
+	if !ok {
+		return nil, fmt.Errorf("first argument to simchain.NewWallet is " +
+			"incorrect, expected a *simchain.Chain")
+	}
+
+	seed, ok := args[1].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("second argument to simchain.NewWallet " +
+			"is incorrect, expected a []byte")
+	}
+
+	return NewWallet(chain, seed)
+}
+
+// init registers drivers for the simulated chain's implementations of the
+// chainntnfs.ChainNotifier and lnwallet.WalletController interfaces.
+func init() {
+	// Register the driver.
+	notifier := &chainntnfs.NotifierDriver{
+		NotifierType: notifierType,
+		New:          createNewNotifier,
+	}
+
+	if err := chainntnfs.RegisterNotifier(notifier); err != nil {
+		panic(fmt.Sprintf("failed to register notifier driver '%s': %v",
+			notifierType, err))
+	}
+
+	wallet := &lnwallet.WalletDriver{
+		WalletType: walletType,
+		New:        createNewWallet,
+	}
+
+	if err := lnwallet.RegisterWallet(wallet); err != nil {
+		panic(fmt.Sprintf("failed to register wallet driver '%s': %v",
+			walletType, err))
+	}
+}
@@ -0,0 +1,544 @@
+package simchain
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// confirmationsNotification represents a client's intent to receive a
+// notification once the target txid reaches numConfirmations confirmations.
+type confirmationsNotification struct {
+	txid             chainhash.Hash
+	numConfirmations uint32
+
+	// confHeight is the height of the block within the main chain which
+	// included the transaction, or zero if it's yet to be included.
+	// txIndex is the index of the transaction within that block.
+	confHeight int32
+	txIndex    uint32
+
+	// dispatched is true once the confirmation has been sent to the
+	// client.
+	dispatched bool
+
+	finConf      chan *chainntnfs.TxConfirmation
+	negativeConf chan int32
+}
+
+// spendNotification couples a target outpoint along with the channel used for
+// notifications once a spend of the outpoint has been detected.
+type spendNotification struct {
+	targetOutpoint *wire.OutPoint
+
+	spendChan chan *chainntnfs.SpendDetail
+
+	// reorgChan is sent upon if the spending transaction is disconnected
+	// from the main chain after the spend has been dispatched.
+	reorgChan chan struct{}
+
+	spendID uint64
+
+	// dispatched is true once a spend has been sent to the client.
+	// spendHeight is then the height of the block which included the
+	// spending transaction, or zero if it's within the mempool.
+	dispatched  bool
+	spendHeight int32
+}
+
+// blockEpochRegistration represents a client's intent to receive a
+// notification with each newly connected block.
+type blockEpochRegistration struct {
+	epochChan chan *chainntnfs.BlockEpoch
+
+	// epochQueue holds the epochs yet to be delivered to the client. The
+	// queue is unbounded, so mining blocks never blocks on a slow
+	// client, and epochs are always delivered in order.
+	epochQueue  []*chainntnfs.BlockEpoch
+	epochSignal chan struct{}
+	epochMtx    sync.Mutex
+
+	cancel chan struct{}
+}
+
+// Start is a no-op, as the simulated chain is ready to dispatch notifications
+// as soon as it's created.
+//
+// This method is a part of the chainntnfs.ChainNotifier interface.
+func (c *Chain) Start() error {
+	return nil
+}
+
+// Stop shuts down the simulated chain's notification dispatch. Once stopped,
+// all pending client notifications are cancelled by closing their channels.
+//
+// This method is a part of the chainntnfs.ChainNotifier interface.
+func (c *Chain) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&c.stopped, 1) != 1 {
+		return nil
+	}
+
+	// The quit channel is closed while holding the mutex, ensuring no
+	// new clients are registered once we begin waiting on the epoch
+	// dispatchers to exit.
+	c.mtx.Lock()
+	close(c.quit)
+	c.mtx.Unlock()
+
+	c.wg.Wait()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Notify all pending clients of our shutdown by closing the related
+	// notification channels. The block epoch channels have already been
+	// closed by their dispatchers.
+	for _, spendClients := range c.spendNotifications {
+		for _, spendClient := range spendClients {
+			if !spendClient.dispatched {
+				close(spendClient.spendChan)
+			}
+		}
+	}
+	for _, confClients := range c.confNotifications {
+		for _, confClient := range confClients {
+			if !confClient.dispatched {
+				close(confClient.finConf)
+				close(confClient.negativeConf)
+			}
+		}
+	}
+
+	// With the channels closed, the chain can no longer dispatch any
+	// notifications, though it can continue to be mined.
+	c.confNotifications = make(map[chainhash.Hash][]*confirmationsNotification)
+	c.spendNotifications = make(map[wire.OutPoint]map[uint64]*spendNotification)
+	c.blockEpochClients = make(map[uint64]*blockEpochRegistration)
+
+	return nil
+}
+
+// notifyBlockEpochs notifies all registered block epoch clients of the newly
+// connected block to the main chain.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) notifyBlockEpochs(newHeight int32, newSha *chainhash.Hash) {
+	epoch := &chainntnfs.BlockEpoch{
+		Height: newHeight,
+		Hash:   newSha,
+	}
+
+	for _, client := range c.blockEpochClients {
+		client.epochMtx.Lock()
+		client.epochQueue = append(client.epochQueue, epoch)
+		client.epochMtx.Unlock()
+
+		select {
+		case client.epochSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// epochDispatcher delivers the queued block epochs of the passed client in
+// order, until either the client cancels its registration, or the chain is
+// stopped. The client's epoch channel is closed on exit.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *Chain) epochDispatcher(client *blockEpochRegistration) {
+	defer c.wg.Done()
+	defer close(client.epochChan)
+
+	for {
+		select {
+		case <-client.epochSignal:
+		case <-client.cancel:
+			return
+		case <-c.quit:
+			return
+		}
+
+		for {
+			client.epochMtx.Lock()
+			if len(client.epochQueue) == 0 {
+				client.epochMtx.Unlock()
+				break
+			}
+			epoch := client.epochQueue[0]
+			client.epochQueue[0] = nil // Set to nil to prevent GC leak.
+			client.epochQueue = client.epochQueue[1:]
+			client.epochMtx.Unlock()
+
+			select {
+			case client.epochChan <- epoch:
+			case <-client.cancel:
+				return
+			case <-c.quit:
+				return
+			}
+		}
+	}
+}
+
+// notifyConfs dispatches the confirmation notifications whose transaction has
+// reached the targeted number of confirmations within the main chain.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) notifyConfs() {
+	for _, confClients := range c.confNotifications {
+		for _, confClient := range confClients {
+			c.checkConfirmationTrigger(confClient)
+		}
+	}
+}
+
+// checkConfirmationTrigger dispatches the passed confirmation notification if
+// its transaction has reached the targeted number of confirmations.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) checkConfirmationTrigger(ntfn *confirmationsNotification) {
+	if ntfn.dispatched || ntfn.confHeight == 0 {
+		return
+	}
+
+	numConfs := c.bestHeight() - ntfn.confHeight + 1
+	if uint32(numConfs) < ntfn.numConfirmations {
+		return
+	}
+
+	chainntnfs.Log.Infof("Dispatching %v conf notification, sha=%v, "+
+		"height=%v", ntfn.numConfirmations, ntfn.txid, ntfn.confHeight)
+
+	blockHash := c.blocks[ntfn.confHeight].BlockHash()
+	ntfn.finConf <- &chainntnfs.TxConfirmation{
+		BlockHash:   &blockHash,
+		BlockHeight: uint32(ntfn.confHeight),
+		TxIndex:     ntfn.txIndex,
+	}
+	ntfn.dispatched = true
+}
+
+// checkSpendTrigger dispatches any spend notifications registered for the
+// outputs spent by the passed transaction. A spendHeight of zero indicates
+// that the transaction has only been seen within the mempool.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) checkSpendTrigger(spendingTx *wire.MsgTx, spendHeight int32) {
+	spenderSha := spendingTx.TxHash()
+
+	for i, txIn := range spendingTx.TxIn {
+		for _, ntfn := range c.spendNotifications[txIn.PreviousOutPoint] {
+			// If the spend was already dispatched after seeing the
+			// transaction within the mempool, then we only need
+			// to note its height so it can be re-armed if the
+			// block is later disconnected.
+			if ntfn.dispatched {
+				if ntfn.spendHeight == 0 {
+					ntfn.spendHeight = spendHeight
+				}
+				continue
+			}
+
+			c.dispatchSpend(ntfn, spendingTx, &spenderSha,
+				uint32(i), spendHeight)
+		}
+	}
+}
+
+// dispatchSpend sends the details of the passed spend to the client.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) dispatchSpend(ntfn *spendNotification, spendingTx *wire.MsgTx,
+	spenderSha *chainhash.Hash, inputIndex uint32, spendHeight int32) {
+
+	// If the notification was re-armed after a re-org, then a stale spend
+	// may still be buffered, so we'll drain it before dispatching the new
+	// spend.
+	select {
+	case <-ntfn.spendChan:
+	default:
+	}
+
+	chainntnfs.Log.Infof("Dispatching spend notification for "+
+		"outpoint=%v", ntfn.targetOutpoint)
+
+	ntfn.spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:     ntfn.targetOutpoint,
+		SpenderTxHash:     spenderSha,
+		SpendingTx:        spendingTx,
+		SpenderInputIndex: inputIndex,
+		SpendingHeight:    spendHeight,
+	}
+	ntfn.dispatched = true
+	ntfn.spendHeight = spendHeight
+}
+
+// handleBlockDisconnected re-arms all confirmation and spend notifications for
+// transactions which were included within the block disconnected at the
+// passed height. Each confirmation client is sent the current depth of the
+// re-org over the NegativeConf channel, and each spend client is signalled
+// over the Reorg channel.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) handleBlockDisconnected(height int32) {
+	for _, confClients := range c.confNotifications {
+		for _, ntfn := range confClients {
+			if ntfn.confHeight != height {
+				continue
+			}
+
+			chainntnfs.Log.Infof("Re-arming confirmation "+
+				"notification for txid=%v", ntfn.txid)
+
+			// If the client hasn't yet read a confirmation which
+			// has now become stale, then we'll drain it.
+			select {
+			case <-ntfn.finConf:
+			default:
+			}
+
+			// If the client has yet to read a prior re-org depth,
+			// then it'll learn of the re-org regardless, so the
+			// send is allowed to fail.
+			select {
+			case ntfn.negativeConf <- c.reorgDepth:
+			default:
+			}
+
+			ntfn.confHeight = 0
+			ntfn.dispatched = false
+		}
+	}
+
+	for op, spendClients := range c.spendNotifications {
+		for _, ntfn := range spendClients {
+			if !ntfn.dispatched || ntfn.spendHeight != height {
+				continue
+			}
+
+			chainntnfs.Log.Infof("Re-arming spend notification "+
+				"for outpoint=%v", op)
+
+			select {
+			case <-ntfn.spendChan:
+			default:
+			}
+
+			select {
+			case ntfn.reorgChan <- struct{}{}:
+			default:
+			}
+
+			ntfn.dispatched = false
+			ntfn.spendHeight = 0
+		}
+	}
+}
+
+// pruneReorgWindow stops tracking the dispatched notifications for
+// transactions included within blocks that have fallen out of the re-org
+// window.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) pruneReorgWindow() {
+	pruneHeight := c.bestHeight() - reorgSafetyLimit
+
+	for txid, confClients := range c.confNotifications {
+		remaining := confClients[:0]
+		for _, ntfn := range confClients {
+			if ntfn.dispatched && ntfn.confHeight <= pruneHeight {
+				continue
+			}
+			remaining = append(remaining, ntfn)
+		}
+
+		if len(remaining) == 0 {
+			delete(c.confNotifications, txid)
+			continue
+		}
+		c.confNotifications[txid] = remaining
+	}
+
+	for op, spendClients := range c.spendNotifications {
+		for spendID, ntfn := range spendClients {
+			if ntfn.dispatched && ntfn.spendHeight != 0 &&
+				ntfn.spendHeight <= pruneHeight {
+
+				delete(spendClients, spendID)
+			}
+		}
+
+		if len(spendClients) == 0 {
+			delete(c.spendNotifications, op)
+		}
+	}
+}
+
+// RegisterConfirmationsNtfn registers a notification which will be triggered
+// once the txid reaches numConfs number of confirmations. If the transaction
+// has already been included within the main chain, the confirmations it has
+// already attained are taken into account.
+//
+// This method is a part of the chainntnfs.ChainNotifier interface.
+func (c *Chain) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	select {
+	case <-c.quit:
+		return nil, ErrChainNotifierShuttingDown
+	default:
+	}
+
+	chainntnfs.Log.Infof("New confirmations subscription: txid=%v, "+
+		"numconfs=%v", txid, numConfs)
+
+	ntfn := &confirmationsNotification{
+		txid:             *txid,
+		numConfirmations: numConfs,
+		finConf:          make(chan *chainntnfs.TxConfirmation, 1),
+		negativeConf:     make(chan int32, 1),
+	}
+	if loc, ok := c.txIndex[*txid]; ok {
+		ntfn.confHeight = loc.height
+		ntfn.txIndex = loc.index
+	}
+
+	c.confNotifications[*txid] = append(c.confNotifications[*txid], ntfn)
+	c.checkConfirmationTrigger(ntfn)
+
+	return &chainntnfs.ConfirmationEvent{
+		Confirmed:    ntfn.finConf,
+		NegativeConf: ntfn.negativeConf,
+	}, nil
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the target
+// outpoint has been spent by a transaction within the mempool or main chain.
+// If the outpoint has already been spent within the mempool, or within the
+// main chain at or after heightHint, the notification is dispatched
+// immediately.
+//
+// This method is a part of the chainntnfs.ChainNotifier interface.
+func (c *Chain) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	select {
+	case <-c.quit:
+		return nil, ErrChainNotifierShuttingDown
+	default:
+	}
+
+	chainntnfs.Log.Infof("New spend subscription: utxo=%v", outpoint)
+
+	c.clientCounter++
+	ntfn := &spendNotification{
+		targetOutpoint: outpoint,
+		spendChan:      make(chan *chainntnfs.SpendDetail, 1),
+		reorgChan:      make(chan struct{}, 1),
+		spendID:        c.clientCounter,
+	}
+
+	op := *outpoint
+	if _, ok := c.spendNotifications[op]; !ok {
+		c.spendNotifications[op] = make(map[uint64]*spendNotification)
+	}
+	c.spendNotifications[op][ntfn.spendID] = ntfn
+
+	// If the outpoint has already been spent, then we'll dispatch the
+	// notification immediately. As with a full node scanning the chain,
+	// spends before the height hint aren't detected.
+	spend, ok := c.spendIndex[op]
+	if ok && spend.height < int32(heightHint) {
+		ok = false
+	}
+	if !ok {
+		spend, ok = c.mempoolSpends[op]
+	}
+	if ok {
+		c.dispatchSpend(ntfn, spend.tx, &spend.txid, spend.inputIndex,
+			spend.height)
+	}
+
+	return &chainntnfs.SpendEvent{
+		Spend: ntfn.spendChan,
+		Reorg: ntfn.reorgChan,
+		Cancel: func() {
+			c.mtx.Lock()
+			defer c.mtx.Unlock()
+
+			chainntnfs.Log.Infof("Cancelling spend notification "+
+				"for out_point=%v, spend_id=%v", op,
+				ntfn.spendID)
+
+			// Before we attempt to close the spendChan, ensure
+			// that the notification hasn't already been
+			// cancelled, or dispatched.
+			spendClients := c.spendNotifications[op]
+			if _, ok := spendClients[ntfn.spendID]; !ok {
+				return
+			}
+			delete(spendClients, ntfn.spendID)
+
+			if !ntfn.dispatched {
+				close(ntfn.spendChan)
+			}
+		},
+	}, nil
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive notifications, of each new block connected to the main
+// chain.
+//
+// This method is a part of the chainntnfs.ChainNotifier interface.
+func (c *Chain) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	select {
+	case <-c.quit:
+		return nil, ErrChainNotifierShuttingDown
+	default:
+	}
+
+	chainntnfs.Log.Infof("New block epoch subscription")
+
+	c.clientCounter++
+	epochID := c.clientCounter
+	registration := &blockEpochRegistration{
+		epochChan:   make(chan *chainntnfs.BlockEpoch, 20),
+		epochSignal: make(chan struct{}, 1),
+		cancel:      make(chan struct{}),
+	}
+	c.blockEpochClients[epochID] = registration
+
+	c.wg.Add(1)
+	go c.epochDispatcher(registration)
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: registration.epochChan,
+		Cancel: func() {
+			c.mtx.Lock()
+			defer c.mtx.Unlock()
+
+			chainntnfs.Log.Infof("Cancelling epoch notification, "+
+				"epoch_id=%v", epochID)
+
+			if _, ok := c.blockEpochClients[epochID]; !ok {
+				return
+			}
+			delete(c.blockEpochClients, epochID)
+			close(registration.cancel)
+		},
+	}, nil
+}
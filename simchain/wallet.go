@@ -0,0 +1,916 @@
+package simchain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+const (
+	// sendOutputsFee is the fee paid by each transaction created by the
+	// wallet's SendOutputs method. As the simulated chain enforces no fee
+	// policy, a fixed fee is used regardless of the transaction's size.
+	sendOutputsFee = btcutil.Amount(10000)
+
+	// signerLookahead is the number of keys beyond the last derived which
+	// are searched for an unknown key the wallet is asked to sign with.
+	signerLookahead = 100
+)
+
+var (
+	// ErrInsufficientFunds is returned by SendOutputs if the wallet's
+	// confirmed, unlocked outputs are unable to pay for the passed
+	// outputs.
+	ErrInsufficientFunds = errors.New("simchain: insufficient funds")
+
+	// ErrWatchOnly is returned when a watch-only wallet is asked for, or
+	// to sign with, a private key.
+	ErrWatchOnly = errors.New("simchain: private keys unavailable to " +
+		"watch-only wallet")
+)
+
+// chainTx is a transaction within either the main chain or the mempool.
+type chainTx struct {
+	tx *wire.MsgTx
+
+	// blockHash, height and timestamp locate the transaction within the
+	// main chain. The blockHash is nil, and the height zero, for
+	// transactions within the mempool.
+	blockHash *chainhash.Hash
+	height    int32
+	timestamp int64
+}
+
+// txSubscription is a transaction subscription returned by the wallet's
+// SubscribeTransactions method.
+type txSubscription struct {
+	id uint64
+
+	w *Wallet
+
+	confirmed   chan *lnwallet.TransactionDetail
+	unconfirmed chan *lnwallet.TransactionDetail
+
+	quit chan struct{}
+}
+
+// ConfirmedTransactions returns a channel which will be sent on as new
+// relevant transactions are confirmed.
+//
+// This is part of the lnwallet.TransactionSubscription interface.
+func (s *txSubscription) ConfirmedTransactions() chan *lnwallet.TransactionDetail {
+	return s.confirmed
+}
+
+// UnconfirmedTransactions returns a channel which will be sent on as new
+// relevant transactions are seen within the mempool.
+//
+// This is part of the lnwallet.TransactionSubscription interface.
+func (s *txSubscription) UnconfirmedTransactions() chan *lnwallet.TransactionDetail {
+	return s.unconfirmed
+}
+
+// Cancel finalizes the subscription, cleaning up any resources allocated.
+//
+// This is part of the lnwallet.TransactionSubscription interface.
+func (s *txSubscription) Cancel() {
+	s.w.mtx.Lock()
+	defer s.w.mtx.Unlock()
+
+	if _, ok := s.w.subscriptions[s.id]; !ok {
+		return
+	}
+	delete(s.w.subscriptions, s.id)
+	close(s.quit)
+}
+
+// Wallet is a wallet whose outputs live within a simulated Chain. It
+// implements the lnwallet.WalletController, lnwallet.Signer,
+// lnwallet.MessageSigner and lnwallet.BlockChainIO interfaces, allowing a
+// LightningWallet to be driven entirely in memory alongside the Chain.
+//
+// All keys controlled by the wallet are derived from its HD seed, and it's
+// only capable of creating and spending p2wkh outputs. Signatures produced by
+// the wallet are fully valid, though the Chain itself doesn't check them.
+//
+// A watch-only wallet derives the same addresses from the public address
+// branch of another wallet, but holds no private keys, so it's unable to
+// sign. Its outputs are instead spent via a Signer backed by the other
+// wallet.
+type Wallet struct {
+	chain *Chain
+
+	// rootKey is returned by FetchRootKey, and addrBranch is the branch
+	// from which the wallet's address and raw keys are derived. The
+	// rootKey is nil, and the addrBranch public, for a watch-only wallet.
+	rootKey    *btcec.PrivateKey
+	addrBranch *hdkeychain.ExtendedKey
+
+	// The fields below are protected by the mutex.
+	//
+	// NOTE: The wallet's mutex may be acquired while the chain's mutex is
+	// held, so the chain MUST NOT be called into with it held.
+	mtx sync.Mutex
+
+	nextKeyIndex uint32
+
+	// pubKeys and pkScripts index each private key controlled by the
+	// wallet by its compressed public key and its p2wkh output script.
+	// The private keys of a watch-only wallet are nil.
+	pubKeys   map[string]*btcec.PrivateKey
+	pkScripts map[string]*btcec.PrivateKey
+
+	lockedOutpoints map[wire.OutPoint]struct{}
+
+	subscriptionCounter uint64
+	subscriptions       map[uint64]*txSubscription
+}
+
+// Ensure Wallet implements the interfaces it simulates at compile time.
+var _ lnwallet.WalletController = (*Wallet)(nil)
+var _ lnwallet.Signer = (*Wallet)(nil)
+var _ lnwallet.MessageSigner = (*Wallet)(nil)
+var _ lnwallet.BlockChainIO = (*Wallet)(nil)
+
+// NewWallet creates a new wallet on top of the passed chain, deriving its keys
+// from the passed HD seed.
+func NewWallet(chain *Chain, seed []byte) (*Wallet, error) {
+	masterKey, err := hdkeychain.NewMaster(seed, chain.netParams)
+	if err != nil {
+		return nil, err
+	}
+
+	rootExtKey, err := masterKey.Child(hdkeychain.HardenedKeyStart)
+	if err != nil {
+		return nil, err
+	}
+	rootKey, err := rootExtKey.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+
+	addrBranch, err := masterKey.Child(hdkeychain.HardenedKeyStart + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWallet(chain, rootKey, addrBranch), nil
+}
+
+// NewWatchOnlyWallet creates a new watch-only wallet on top of the passed
+// chain, deriving its addresses from the passed public address branch, such
+// as that returned by the AddrBranchPub method of another wallet.
+func NewWatchOnlyWallet(chain *Chain,
+	addrBranch *hdkeychain.ExtendedKey) (*Wallet, error) {
+
+	if addrBranch.IsPrivate() {
+		return nil, fmt.Errorf("simchain: watch-only wallet requires a " +
+			"public address branch")
+	}
+
+	return newWallet(chain, nil, addrBranch), nil
+}
+
+// newWallet creates a new wallet on top of the passed chain, registering it
+// to be notified of the chain's transactions.
+func newWallet(chain *Chain, rootKey *btcec.PrivateKey,
+	addrBranch *hdkeychain.ExtendedKey) *Wallet {
+
+	w := &Wallet{
+		chain:           chain,
+		rootKey:         rootKey,
+		addrBranch:      addrBranch,
+		pubKeys:         make(map[string]*btcec.PrivateKey),
+		pkScripts:       make(map[string]*btcec.PrivateKey),
+		lockedOutpoints: make(map[wire.OutPoint]struct{}),
+		subscriptions:   make(map[uint64]*txSubscription),
+	}
+
+	chain.mtx.Lock()
+	chain.wallets = append(chain.wallets, w)
+	chain.mtx.Unlock()
+
+	return w
+}
+
+// AddrBranchPub returns the public key of the branch from which the wallet's
+// addresses are derived, allowing a watch-only wallet to be created for it.
+func (w *Wallet) AddrBranchPub() (*hdkeychain.ExtendedKey, error) {
+	return w.addrBranch.Neuter()
+}
+
+// deriveNextKey derives the next unused key of the wallet, returning its
+// public key along with its p2wkh address.
+func (w *Wallet) deriveNextKey() (*btcec.PublicKey, btcutil.Address, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return w.deriveNextKeyLocked()
+}
+
+// deriveNextKeyLocked derives the next unused key of the wallet, indexing its
+// private key, if available, by its public key and output script.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method.
+func (w *Wallet) deriveNextKeyLocked() (*btcec.PublicKey, btcutil.Address,
+	error) {
+
+	extKey, err := w.addrBranch.Child(w.nextKeyIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubKey, err := extKey.ECPubKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A watch-only wallet indexes its keys without their private keys.
+	var privKey *btcec.PrivateKey
+	if extKey.IsPrivate() {
+		privKey, err = extKey.ECPrivKey()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	serializedKey := pubKey.SerializeCompressed()
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(serializedKey), w.chain.netParams,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.nextKeyIndex++
+	w.pubKeys[string(serializedKey)] = privKey
+	w.pkScripts[string(pkScript)] = privKey
+
+	return pubKey, addr, nil
+}
+
+// lookupPrivKey returns the private key indexed by the passed public key, or
+// output script if byScript is set, and whether it was found. If the key isn't
+// yet known, then up to signerLookahead keys beyond the last derived are
+// derived in search of it. This allows the wallet to sign for a watch-only
+// wallet created from its address branch, whose keys may have been derived
+// beyond its own. ErrWatchOnly is returned if the key was found but the
+// wallet is watch-only.
+func (w *Wallet) lookupPrivKey(key []byte,
+	byScript bool) (*btcec.PrivateKey, bool, error) {
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	index := w.pubKeys
+	if byScript {
+		index = w.pkScripts
+	}
+
+	privKey, ok := index[string(key)]
+	for i := 0; !ok && w.rootKey != nil && i < signerLookahead; i++ {
+		if _, _, err := w.deriveNextKeyLocked(); err != nil {
+			return nil, false, err
+		}
+		privKey, ok = index[string(key)]
+	}
+
+	switch {
+	case !ok:
+		return nil, false, nil
+	case privKey == nil:
+		return nil, true, ErrWatchOnly
+	}
+
+	return privKey, true, nil
+}
+
+// isMine returns true if the passed output script pays to the wallet.
+func (w *Wallet) isMine(pkScript []byte) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	_, ok := w.pkScripts[string(pkScript)]
+	return ok
+}
+
+// isLocked returns true if the passed outpoint has been locked.
+func (w *Wallet) isLocked(op wire.OutPoint) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	_, ok := w.lockedOutpoints[op]
+	return ok
+}
+
+// chainTxns returns every transaction within the main chain, followed by
+// every transaction within the mempool, along with the set of outputs they
+// spend and the current height of the chain.
+func (c *Chain) chainTxns() ([]*chainTx, map[wire.OutPoint]struct{}, int32) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var txns []*chainTx
+	for height, block := range c.blocks {
+		blockHash := block.BlockHash()
+		for _, tx := range block.Transactions {
+			txns = append(txns, &chainTx{
+				tx:        tx,
+				blockHash: &blockHash,
+				height:    int32(height),
+				timestamp: block.Header.Timestamp.Unix(),
+			})
+		}
+	}
+	for _, tx := range c.mempool {
+		txns = append(txns, &chainTx{tx: tx})
+	}
+
+	spent := make(map[wire.OutPoint]struct{})
+	for op := range c.spendIndex {
+		spent[op] = struct{}{}
+	}
+	for op := range c.mempoolSpends {
+		spent[op] = struct{}{}
+	}
+
+	return txns, spent, c.bestHeight()
+}
+
+// unspentOutputs returns every output controlled by the wallet which hasn't
+// been spent within the main chain or the mempool.
+func (w *Wallet) unspentOutputs() []*lnwallet.Utxo {
+	txns, spent, bestHeight := w.chain.chainTxns()
+
+	var utxos []*lnwallet.Utxo
+	for _, chainTx := range txns {
+		txid := chainTx.tx.TxHash()
+		for i, txOut := range chainTx.tx.TxOut {
+			op := wire.OutPoint{Hash: txid, Index: uint32(i)}
+			if _, ok := spent[op]; ok || !w.isMine(txOut.PkScript) {
+				continue
+			}
+
+			var numConfs int64
+			if chainTx.blockHash != nil {
+				numConfs = int64(bestHeight-chainTx.height) + 1
+			}
+
+			utxos = append(utxos, &lnwallet.Utxo{
+				Value:         btcutil.Amount(txOut.Value),
+				OutPoint:      op,
+				PkScript:      txOut.PkScript,
+				Confirmations: numConfs,
+			})
+		}
+	}
+
+	return utxos
+}
+
+// txDetail returns the details of the passed transaction from the point of
+// view of the wallet, or nil if the transaction neither pays to nor spends
+// from the wallet. The prevOutput closure is used to look up the outputs
+// spent by the transaction.
+func (w *Wallet) txDetail(chainTx *chainTx, bestHeight int32,
+	prevOutput func(wire.OutPoint) *wire.TxOut) *lnwallet.TransactionDetail {
+
+	tx := chainTx.tx
+
+	var credit, debit, totalIn, totalOut int64
+	knownInputs := true
+	if !blockchain.IsCoinBaseTx(tx) {
+		for _, txIn := range tx.TxIn {
+			prevOut := prevOutput(txIn.PreviousOutPoint)
+			if prevOut == nil {
+				knownInputs = false
+				continue
+			}
+
+			totalIn += prevOut.Value
+			if w.isMine(prevOut.PkScript) {
+				debit += prevOut.Value
+			}
+		}
+	}
+	for _, txOut := range tx.TxOut {
+		totalOut += txOut.Value
+		if w.isMine(txOut.PkScript) {
+			credit += txOut.Value
+		}
+	}
+
+	if credit == 0 && debit == 0 {
+		return nil
+	}
+
+	detail := &lnwallet.TransactionDetail{
+		Hash:  tx.TxHash(),
+		Value: btcutil.Amount(credit - debit),
+	}
+	if debit != 0 && knownInputs {
+		detail.TotalFees = totalIn - totalOut
+	}
+	if chainTx.blockHash != nil {
+		detail.NumConfirmations = bestHeight - chainTx.height + 1
+		detail.BlockHash = chainTx.blockHash
+		detail.BlockHeight = chainTx.height
+		detail.Timestamp = chainTx.timestamp
+	}
+
+	return detail
+}
+
+// notifyTx dispatches the details of the passed transaction to each of the
+// wallet's transaction subscriptions if it's relevant to the wallet. The
+// transaction has either just been added to the mempool, or has just been
+// confirmed by the tip of the main chain.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (w *Wallet) notifyTx(chainTx *chainTx) {
+	bestHeight := w.chain.bestHeight()
+	detail := w.txDetail(chainTx, bestHeight, w.chain.lookupOutput)
+	if detail == nil {
+		return
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for _, sub := range w.subscriptions {
+		ntfnChan := sub.unconfirmed
+		if detail.BlockHash != nil {
+			ntfnChan = sub.confirmed
+		}
+
+		// The notification is delivered asynchronously, as the
+		// chain's mutex is held.
+		go func(sub *txSubscription) {
+			select {
+			case ntfnChan <- detail:
+			case <-sub.quit:
+			}
+		}(sub)
+	}
+}
+
+// lookupOutput returns the output referenced by the passed outpoint if its
+// transaction is within the main chain or the mempool, and nil otherwise.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) lookupOutput(op wire.OutPoint) *wire.TxOut {
+	var tx *wire.MsgTx
+	if loc, ok := c.txIndex[op.Hash]; ok {
+		tx = loc.tx
+	} else {
+		for _, memTx := range c.mempool {
+			if memTx.TxHash() == op.Hash {
+				tx = memTx
+				break
+			}
+		}
+	}
+
+	if tx == nil || op.Index >= uint32(len(tx.TxOut)) {
+		return nil
+	}
+
+	return tx.TxOut[op.Index]
+}
+
+// FetchInputInfo returns the original output referenced by the passed
+// outpoint if it's controlled by the wallet, and ErrNotMine otherwise.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) FetchInputInfo(prevOut *wire.OutPoint) (*wire.TxOut, error) {
+	w.chain.mtx.Lock()
+	output := w.chain.lookupOutput(*prevOut)
+	w.chain.mtx.Unlock()
+
+	if output == nil || !w.isMine(output.PkScript) {
+		return nil, lnwallet.ErrNotMine
+	}
+
+	return output, nil
+}
+
+// ConfirmedBalance returns the sum of all the wallet's unspent outputs that
+// have at least confs confirmations. If confs is zero, then unconfirmed
+// outputs are included as well. As all of the wallet's outputs are p2wkh, the
+// witness parameter has no effect.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) ConfirmedBalance(confs int32,
+	witness bool) (btcutil.Amount, error) {
+
+	var balance btcutil.Amount
+	for _, utxo := range w.unspentOutputs() {
+		if utxo.Confirmations >= int64(confs) {
+			balance += utxo.Value
+		}
+	}
+
+	return balance, nil
+}
+
+// NewAddress returns a new p2wkh address controlled by the wallet. No other
+// address types are supported.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) NewAddress(addrType lnwallet.AddressType,
+	change bool) (btcutil.Address, error) {
+
+	if addrType != lnwallet.WitnessPubKey {
+		return nil, fmt.Errorf("simchain: unsupported address type: %v",
+			addrType)
+	}
+
+	_, addr, err := w.deriveNextKey()
+	return addr, err
+}
+
+// GetPrivKey returns the private key controlling the passed p2wkh address.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) GetPrivKey(a btcutil.Address) (*btcec.PrivateKey, error) {
+	pkScript, err := txscript.PayToAddrScript(a)
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, ok, err := w.lookupPrivKey(pkScript, true)
+	switch {
+	case err != nil:
+		return nil, err
+	case !ok:
+		return nil, fmt.Errorf("address %v not found", a)
+	}
+
+	return privKey, nil
+}
+
+// NewRawKey returns a new public key controlled by the wallet.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) NewRawKey() (*btcec.PublicKey, error) {
+	pubKey, _, err := w.deriveNextKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKey, nil
+}
+
+// FetchRootKey returns the root private key of the wallet, which is derived
+// from its HD seed independently of its address keys. ErrWatchOnly is returned
+// if the wallet is watch-only.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) FetchRootKey() (*btcec.PrivateKey, error) {
+	if w.rootKey == nil {
+		return nil, ErrWatchOnly
+	}
+
+	return w.rootKey, nil
+}
+
+// SendOutputs funds, signs and publishes a transaction creating the passed
+// outputs, using the wallet's confirmed and unlocked outputs. Any change is
+// sent to a new address of the wallet. ErrWatchOnly is returned if the wallet
+// is watch-only.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) SendOutputs(outputs []*wire.TxOut) (*chainhash.Hash, error) {
+	if w.rootKey == nil {
+		return nil, ErrWatchOnly
+	}
+
+	tx := wire.NewMsgTx(2)
+	required := sendOutputsFee
+	for _, output := range outputs {
+		tx.AddTxOut(output)
+		required += btcutil.Amount(output.Value)
+	}
+
+	var (
+		selected []*lnwallet.Utxo
+		total    btcutil.Amount
+	)
+	for _, utxo := range w.unspentOutputs() {
+		if total >= required {
+			break
+		}
+		if utxo.Confirmations < 1 || w.isLocked(utxo.OutPoint) {
+			continue
+		}
+
+		selected = append(selected, utxo)
+		total += utxo.Value
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: utxo.OutPoint})
+	}
+	if total < required {
+		return nil, ErrInsufficientFunds
+	}
+
+	if change := total - required; change > 0 {
+		changeAddr, err := w.NewAddress(lnwallet.WitnessPubKey, true)
+		if err != nil {
+			return nil, err
+		}
+		changeScript, err := txscript.PayToAddrScript(changeAddr)
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(change), changeScript))
+	}
+
+	sigHashes := txscript.NewTxSigHashes(tx)
+	for i, utxo := range selected {
+		inputScript, err := w.ComputeInputScript(tx, &lnwallet.SignDescriptor{
+			Output: &wire.TxOut{
+				Value:    int64(utxo.Value),
+				PkScript: utxo.PkScript,
+			},
+			HashType:   txscript.SigHashAll,
+			SigHashes:  sigHashes,
+			InputIndex: i,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tx.TxIn[i].Witness = inputScript.Witness
+	}
+
+	if err := w.chain.PublishTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	txid := tx.TxHash()
+	return &txid, nil
+}
+
+// ListUnspentWitness returns the wallet's unspent and unlocked outputs with
+// at least confirms confirmations. If confirms is zero or negative, then
+// unconfirmed outputs are included as well.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) ListUnspentWitness(confirms int32) ([]*lnwallet.Utxo, error) {
+	var utxos []*lnwallet.Utxo
+	for _, utxo := range w.unspentOutputs() {
+		if utxo.Confirmations < int64(confirms) ||
+			w.isLocked(utxo.OutPoint) {
+
+			continue
+		}
+
+		utxos = append(utxos, utxo)
+	}
+
+	return utxos, nil
+}
+
+// ListTransactionDetails returns the details of every transaction within the
+// main chain and the mempool which pays to, or spends from the wallet.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) ListTransactionDetails() ([]*lnwallet.TransactionDetail, error) {
+	txns, _, bestHeight := w.chain.chainTxns()
+
+	outputs := make(map[wire.OutPoint]*wire.TxOut)
+	for _, chainTx := range txns {
+		txid := chainTx.tx.TxHash()
+		for i, txOut := range chainTx.tx.TxOut {
+			outputs[wire.OutPoint{Hash: txid, Index: uint32(i)}] = txOut
+		}
+	}
+	prevOutput := func(op wire.OutPoint) *wire.TxOut {
+		return outputs[op]
+	}
+
+	var details []*lnwallet.TransactionDetail
+	for _, chainTx := range txns {
+		detail := w.txDetail(chainTx, bestHeight, prevOutput)
+		if detail != nil {
+			details = append(details, detail)
+		}
+	}
+
+	return details, nil
+}
+
+// LockOutpoint marks the passed outpoint as locked, excluding it from coin
+// selection and ListUnspentWitness.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) LockOutpoint(o wire.OutPoint) {
+	w.mtx.Lock()
+	w.lockedOutpoints[o] = struct{}{}
+	w.mtx.Unlock()
+}
+
+// UnlockOutpoint unlocks a previously locked outpoint.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) UnlockOutpoint(o wire.OutPoint) {
+	w.mtx.Lock()
+	delete(w.lockedOutpoints, o)
+	w.mtx.Unlock()
+}
+
+// PublishTransaction adds the passed transaction to the mempool of the
+// chain.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) PublishTransaction(tx *wire.MsgTx) error {
+	return w.chain.PublishTransaction(tx)
+}
+
+// SubscribeTransactions returns a subscription which is notified of each
+// transaction relevant to the wallet as it enters the mempool, and once it's
+// confirmed.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) SubscribeTransactions() (lnwallet.TransactionSubscription, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.subscriptionCounter++
+	sub := &txSubscription{
+		id:          w.subscriptionCounter,
+		w:           w,
+		confirmed:   make(chan *lnwallet.TransactionDetail),
+		unconfirmed: make(chan *lnwallet.TransactionDetail),
+		quit:        make(chan struct{}),
+	}
+	w.subscriptions[sub.id] = sub
+
+	return sub, nil
+}
+
+// IsSynced always returns true, as the wallet reads directly from the chain.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) IsSynced() (bool, error) {
+	return true, nil
+}
+
+// Start is a no-op, as the wallet requires no background goroutines.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) Start() error {
+	return nil
+}
+
+// Stop cancels any active transaction subscriptions.
+//
+// This is a part of the lnwallet.WalletController interface.
+func (w *Wallet) Stop() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for id, sub := range w.subscriptions {
+		delete(w.subscriptions, id)
+		close(sub.quit)
+	}
+
+	return nil
+}
+
+// fetchPrivKey returns the private key corresponding to the passed public
+// key, if it's controlled by the wallet.
+func (w *Wallet) fetchPrivKey(pub *btcec.PublicKey) (*btcec.PrivateKey, error) {
+	privKey, ok, err := w.lookupPrivKey(pub.SerializeCompressed(), false)
+	switch {
+	case err != nil:
+		return nil, err
+	case !ok:
+		return nil, fmt.Errorf("key %x not found",
+			pub.SerializeCompressed())
+	}
+
+	return privKey, nil
+}
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor.
+//
+// This is a part of the lnwallet.Signer interface.
+func (w *Wallet) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	privKey, err := w.fetchPrivKey(signDesc.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// If a tweak is specified, then we'll need to use this tweak to derive
+	// the final private key to be used for signing this output.
+	if signDesc.PrivateTweak != nil {
+		privKey = lnwallet.DeriveRevocationPrivKey(privKey,
+			signDesc.PrivateTweak)
+	}
+
+	sig, err := txscript.RawTxInWitnessSignature(tx, signDesc.SigHashes,
+		signDesc.InputIndex, signDesc.Output.Value,
+		signDesc.WitnessScript, txscript.SigHashAll, privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chop off the sighash flag at the end of the signature.
+	return sig[:len(sig)-1], nil
+}
+
+// ComputeInputScript generates the witness spending the p2wkh output of the
+// wallet described by the passed SignDescriptor.
+//
+// This is a part of the lnwallet.Signer interface.
+func (w *Wallet) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	pkScript := signDesc.Output.PkScript
+
+	privKey, ok, err := w.lookupPrivKey(pkScript, true)
+	switch {
+	case err != nil:
+		return nil, err
+	case !ok:
+		return nil, lnwallet.ErrNotMine
+	}
+
+	witness, err := txscript.WitnessScript(tx, signDesc.SigHashes,
+		signDesc.InputIndex, signDesc.Output.Value, pkScript,
+		txscript.SigHashAll, privKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwallet.InputScript{Witness: witness}, nil
+}
+
+// SignMessage signs the double SHA-256 of the passed message with the private
+// key corresponding to the passed public key.
+//
+// This is a part of the lnwallet.MessageSigner interface.
+func (w *Wallet) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	privKey, err := w.fetchPrivKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey.Sign(chainhash.DoubleHashB(msg))
+}
+
+// GetBestBlock returns the current height and hash of the best known block
+// within the main chain.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (w *Wallet) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return w.chain.GetBestBlock()
+}
+
+// GetUtxo returns the original output referenced by the passed outpoint.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (w *Wallet) GetUtxo(txid *chainhash.Hash, index uint32,
+	heightHint uint32) (*wire.TxOut, error) {
+
+	return w.chain.GetUtxo(txid, index, heightHint)
+}
+
+// GetTransaction returns the full transaction identified by the passed
+// transaction ID.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (w *Wallet) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	return w.chain.GetTransaction(txid)
+}
+
+// GetBlockHash returns the hash of the block in the main chain at the given
+// height.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (w *Wallet) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	return w.chain.GetBlockHash(blockHeight)
+}
+
+// GetBlock returns the block in the main chain identified by the given hash.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (w *Wallet) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return w.chain.GetBlock(blockHash)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/simchain"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// simWalletHarness bundles a wallet with the simulated chain backing it, for
+// use by the tests of subsystems which spend on-chain funds.
+type simWalletHarness struct {
+	t      *testing.T
+	chain  *simchain.Chain
+	wallet *simchain.Wallet
+
+	// lnWallet wraps the wallet, for subsystems which expect a
+	// LightningWallet.
+	lnWallet *lnwallet.LightningWallet
+}
+
+// newSimWalletHarness starts a new simulated chain, and creates a wallet on
+// top of it whose keys are derived from a seed filled with the passed byte.
+func newSimWalletHarness(t *testing.T, seed byte) *simWalletHarness {
+	chain := simchain.New(&chaincfg.RegressionNetParams)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+
+	wallet, err := simchain.NewWallet(chain, bytes.Repeat([]byte{seed}, 32))
+	if err != nil {
+		chain.Stop()
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+
+	return &simWalletHarness{
+		t:      t,
+		chain:  chain,
+		wallet: wallet,
+		lnWallet: &lnwallet.LightningWallet{
+			WalletController: wallet,
+			Signer:           wallet,
+			ChainIO:          wallet,
+		},
+	}
+}
+
+// stop shuts down the simulated chain.
+func (h *simWalletHarness) stop() {
+	h.chain.Stop()
+}
+
+// generate mines the passed number of blocks on the simulated chain.
+func (h *simWalletHarness) generate(numBlocks uint32) {
+	if _, err := h.chain.Generate(numBlocks); err != nil {
+		h.t.Fatalf("unable to generate blocks: %v", err)
+	}
+}
+
+// fundWallet publishes a transaction creating a p2wkh output of the passed
+// amount paying to the wallet, returning the output along with its outpoint.
+// The transaction remains within the mempool until a block is generated.
+func (h *simWalletHarness) fundWallet(
+	amt btcutil.Amount) (*wire.TxOut, wire.OutPoint) {
+
+	addr, err := h.wallet.NewAddress(lnwallet.WitnessPubKey, false)
+	if err != nil {
+		h.t.Fatalf("unable to create address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		h.t.Fatalf("unable to create pkScript: %v", err)
+	}
+
+	output := &wire.TxOut{Value: int64(amt), PkScript: pkScript}
+	txid, err := h.chain.SendOutputs([]*wire.TxOut{output}, 0)
+	if err != nil {
+		h.t.Fatalf("unable to fund wallet: %v", err)
+	}
+
+	return output, wire.OutPoint{Hash: *txid}
+}
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// ErrSweeperShuttingDown is returned when an input is handed to the
+	// sweeper after it has begun to shut down.
+	ErrSweeperShuttingDown = errors.New("sweeper shutting down")
+)
+
+// unsweepableError is returned when a sweep transaction can't be created for
+// a set of inputs, for example as they're unable to pay its fee, or a witness
+// can't be generated for one of them. Unlike a rejected broadcast, retrying
+// the sweep of the same inputs won't succeed.
+type unsweepableError struct {
+	err error
+}
+
+// Error returns a human readable description of the error.
+func (e *unsweepableError) Error() string {
+	return fmt.Sprintf("unable to create sweep tx: %v", e.err)
+}
+
+// sweepInput describes an output which we're able to spend, and should be
+// swept back into the wallet. Sources of sweepInputs, such as the utxoNursery
+// and breachArbiter, describe how the output is spent using the same
+// witnessGenerator abstraction used for kidOutputs, allowing the sweeper to
+// remain oblivious of the scripts it's spending.
+type sweepInput struct {
+	outPoint wire.OutPoint
+	amt      btcutil.Amount
+
+	// witnessFunc generates the final witness which spends the output
+	// within the sweep transaction.
+	witnessFunc witnessGenerator
+
+	// witnessSize is an upper bound on the size of the witness generated
+	// by witnessFunc, used to estimate the fee of the sweep transaction.
+	witnessSize int
+
+	// sequence is the sequence number the output must be spent with in
+	// order to satisfy any relative time-lock encumbering it.
+	sequence uint32
+
+	// lockTime is the absolute block height before which the output
+	// can't be swept. This is zero for outputs without an absolute
+	// time-lock.
+	lockTime uint32
+
+	// confTarget is the number of blocks within which the sweep
+	// transaction spending the output should confirm.
+	confTarget uint32
+
+	// heightHint is the earliest height at which the output may have been
+	// spent, used when registering for its spend notification.
+	heightHint uint32
+}
+
+// sweepResult is sent to the source of a sweepInput once the output it
+// describes has been spent, or the sweeper has given up on sweeping it.
+type sweepResult struct {
+	// spendingTx is the transaction which spent the output.
+	spendingTx *wire.MsgTx
+
+	// swept is true if the spending transaction was created by the
+	// sweeper. Otherwise, the output was spent elsewhere, such as by the
+	// remote party.
+	swept bool
+
+	// err is set if the sweeper was unable to sweep the output, for
+	// example as the output is unable to pay for its own spend, in which
+	// case the output is no longer watched. If set, spendingTx is nil.
+	err error
+}
+
+// pendingInput is a sweepInput which the sweeper is waiting to see spent.
+type pendingInput struct {
+	*sweepInput
+
+	// resultChan is sent upon once the input has been spent.
+	resultChan chan *sweepResult
+
+	// spendEvent is the spend notification registered for the input.
+	spendEvent *chainntnfs.SpendEvent
+
+	// sweepTx is the most recent sweep transaction broadcast which spends
+	// the input. If nil, the input still needs to be swept.
+	sweepTx *wire.MsgTx
+}
+
+// sweeper is a subsystem which sweeps outputs we're able to spend back into
+// the wallet, on behalf of the other subsystems resolving on-chain contracts.
+// Inputs handed to the sweeper within the same batch window are batched
+// together, creating a single sweep transaction for each fee rate targeted
+// by the inputs. The sweeper watches each input until it's been spent:
+// transactions yet to confirm are rebroadcast with each new block, and if an
+// input is spent by a transaction other than our own, the remaining inputs
+// of the invalidated sweep transaction are swept once again.
+type sweeper struct {
+	started uint32
+	stopped uint32
+
+	wallet       *lnwallet.LightningWallet
+	notifier     chainntnfs.ChainNotifier
+	feeEstimator lnwallet.FeeEstimator
+
+	// batchWindow is the duration for which inputs are collected before
+	// they're swept.
+	batchWindow time.Duration
+
+	// newInputs is used to hand new inputs to the sweeper's main loop.
+	newInputs chan *pendingInput
+
+	// spends is sent upon by the goroutines watching each input once it
+	// has been spent.
+	spends chan *chainntnfs.SpendDetail
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newSweeper creates a new instance of the sweeper, which will price its
+// sweep transactions using the passed fee estimator.
+func newSweeper(wallet *lnwallet.LightningWallet,
+	notifier chainntnfs.ChainNotifier, feeEstimator lnwallet.FeeEstimator,
+	batchWindow time.Duration) *sweeper {
+
+	return &sweeper{
+		wallet:       wallet,
+		notifier:     notifier,
+		feeEstimator: feeEstimator,
+		batchWindow:  batchWindow,
+		newInputs:    make(chan *pendingInput),
+		spends:       make(chan *chainntnfs.SpendDetail),
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start launches the sweeper's main loop.
+func (s *sweeper) Start() error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return nil
+	}
+
+	swprLog.Tracef("Starting sweeper")
+
+	_, bestHeight, err := s.wallet.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	epochClient, err := s.notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go s.collector(epochClient, uint32(bestHeight))
+
+	return nil
+}
+
+// Stop gracefully shuts down the sweeper.
+func (s *sweeper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&s.stopped, 0, 1) {
+		return nil
+	}
+
+	swprLog.Infof("Sweeper shutting down")
+
+	close(s.quit)
+	s.wg.Wait()
+
+	return nil
+}
+
+// sweepInput hands an input to the sweeper, which will sweep it into the
+// wallet once the current batch window closes. The returned channel is sent
+// upon once the input has been spent, either by our sweep transaction, or
+// elsewhere.
+func (s *sweeper) sweepInput(input *sweepInput) (<-chan *sweepResult, error) {
+	spendEvent, err := s.notifier.RegisterSpendNtfn(&input.outPoint,
+		input.heightHint)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &pendingInput{
+		sweepInput: input,
+		resultChan: make(chan *sweepResult, 1),
+		spendEvent: spendEvent,
+	}
+
+	select {
+	case s.newInputs <- pending:
+	case <-s.quit:
+		spendEvent.Cancel()
+		return nil, ErrSweeperShuttingDown
+	}
+
+	return pending.resultChan, nil
+}
+
+// waitForSpend forwards the spend of an input to the sweeper's main loop.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *sweeper) waitForSpend(spendEvent *chainntnfs.SpendEvent) {
+	defer s.wg.Done()
+
+	select {
+	case spend, ok := <-spendEvent.Spend:
+		// If the spend channel has been closed, then the notifier is
+		// shutting down so we exit.
+		if !ok {
+			return
+		}
+
+		select {
+		case s.spends <- spend:
+		case <-s.quit:
+		}
+
+	case <-s.quit:
+	}
+}
+
+// collector is the sweeper's main loop. It collects new inputs, sweeping
+// them once the batch window closes, and tracks their spends. With each new
+// block, any sweep transactions yet to confirm are rebroadcast, and the
+// inputs which we were previously unable to sweep are retried.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *sweeper) collector(epochClient *chainntnfs.BlockEpochEvent,
+	bestHeight uint32) {
+
+	defer s.wg.Done()
+	defer epochClient.Cancel()
+
+	pending := make(map[wire.OutPoint]*pendingInput)
+	defer func() {
+		for _, input := range pending {
+			input.spendEvent.Cancel()
+		}
+	}()
+
+	// batchTimer fires once the current batch window closes. It's nil if
+	// there are no inputs waiting to be swept.
+	var batchTimer <-chan time.Time
+	scheduleSweep := func() {
+		if batchTimer == nil {
+			batchTimer = time.After(s.batchWindow)
+		}
+	}
+
+	for {
+		select {
+		case input := <-s.newInputs:
+			if _, ok := pending[input.outPoint]; ok {
+				swprLog.Warnf("Input %v is already being swept",
+					input.outPoint)
+				input.spendEvent.Cancel()
+				continue
+			}
+
+			swprLog.Debugf("Received input %v of %v to sweep "+
+				"within %v blocks", input.outPoint, input.amt,
+				input.confTarget)
+
+			pending[input.outPoint] = input
+			scheduleSweep()
+
+			s.wg.Add(1)
+			go s.waitForSpend(input.spendEvent)
+
+		case <-batchTimer:
+			batchTimer = nil
+			for _, input := range s.sweep(pending, bestHeight) {
+				delete(pending, input.outPoint)
+			}
+
+		case spend := <-s.spends:
+			input, ok := pending[*spend.SpentOutPoint]
+			if !ok {
+				continue
+			}
+			delete(pending, input.outPoint)
+
+			swept := input.sweepTx != nil &&
+				input.sweepTx.TxHash() == *spend.SpenderTxHash
+
+			swprLog.Infof("Input %v spent by txid=%v, swept=%v",
+				input.outPoint, spend.SpenderTxHash, swept)
+
+			input.resultChan <- &sweepResult{
+				spendingTx: spend.SpendingTx,
+				swept:      swept,
+			}
+
+			// If the input was spent by a transaction other than
+			// our sweep, then our sweep transaction is invalid.
+			// The remaining inputs it spent will need to be swept
+			// once again.
+			if swept || input.sweepTx == nil {
+				continue
+			}
+			conflictedTxid := input.sweepTx.TxHash()
+			for _, other := range pending {
+				if other.sweepTx == nil {
+					continue
+				}
+				if other.sweepTx.TxHash() == conflictedTxid {
+					other.sweepTx = nil
+				}
+			}
+			scheduleSweep()
+
+		case epoch, ok := <-epochClient.Epochs:
+			// If the epoch channel has been closed, then the
+			// notifier is shutting down so we exit.
+			if !ok {
+				return
+			}
+			bestHeight = uint32(epoch.Height)
+
+			s.rebroadcast(pending)
+
+			// Any inputs we were previously unable to sweep, such
+			// as those with an absolute time-lock which has yet to
+			// expire, or those whose sweep transaction was
+			// rejected, are retried.
+			for _, input := range pending {
+				if input.sweepTx == nil {
+					scheduleSweep()
+					break
+				}
+			}
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// sweep creates and broadcasts sweep transactions for all pending inputs
+// which aren't yet spent by one of our transactions. The inputs are grouped
+// by the fee rate estimated for their confirmation target, with a single
+// sweep transaction broadcast for each fee rate. If the sweep transaction of
+// a group can't be created or broadcast, then each of its inputs is swept on
+// its own, ensuring a single unsweepable input doesn't hold back the rest.
+// The inputs for which no sweep transaction can be created, even on their
+// own, are notified of the failure and returned, so they can be removed from
+// the pending set.
+func (s *sweeper) sweep(pending map[wire.OutPoint]*pendingInput,
+	bestHeight uint32) []*pendingInput {
+
+	feeRates := make(map[uint32]btcutil.Amount)
+	buckets := make(map[btcutil.Amount]pendingInputs)
+	for _, input := range pending {
+		// Inputs which have already been swept, or can't yet be
+		// included within a final transaction, are skipped.
+		if input.sweepTx != nil || input.lockTime > bestHeight {
+			continue
+		}
+
+		feeRate, ok := feeRates[input.confTarget]
+		if !ok {
+			var err error
+			feeRate, err = s.feeEstimator.EstimateFeePerWeight(
+				input.confTarget)
+			if err != nil {
+				swprLog.Errorf("unable to estimate fee for "+
+					"input %v: %v", input.outPoint, err)
+				continue
+			}
+			feeRates[input.confTarget] = feeRate
+		}
+
+		buckets[feeRate] = append(buckets[feeRate], input)
+	}
+
+	var failed []*pendingInput
+	for feeRate, bucket := range buckets {
+		// Order the inputs of the bucket by their outpoint, so the
+		// sweep transaction created is deterministic.
+		sort.Sort(bucket)
+
+		err := s.sweepBatch(bucket, feeRate)
+		if err == nil {
+			continue
+		}
+		swprLog.Errorf("unable to sweep batch of %v inputs: %v",
+			len(bucket), err)
+
+		// If the batch contains several inputs, then we don't know
+		// which of them is at fault, so each is swept on its own.
+		if len(bucket) > 1 {
+			for _, input := range bucket {
+				err := s.sweepBatch(pendingInputs{input}, feeRate)
+				if s.handleSweepError(input, err) {
+					failed = append(failed, input)
+				}
+			}
+			continue
+		}
+
+		if s.handleSweepError(bucket[0], err) {
+			failed = append(failed, bucket[0])
+		}
+	}
+
+	return failed
+}
+
+// sweepBatch creates and broadcasts a single sweep transaction spending each
+// of the passed inputs at the passed fee rate.
+func (s *sweeper) sweepBatch(batch pendingInputs,
+	feeRate btcutil.Amount) error {
+
+	inputs := make([]*sweepInput, 0, len(batch))
+	for _, input := range batch {
+		inputs = append(inputs, input.sweepInput)
+	}
+
+	sweepTx, err := createSweepTx(s.wallet, feeRate, inputs)
+	if err != nil {
+		return &unsweepableError{err}
+	}
+
+	swprLog.Infof("Sweeping %v inputs at %v sat/weight with sweep "+
+		"tx (txid=%v): %v", len(inputs), int64(feeRate),
+		sweepTx.TxHash(), newLogClosure(func() string {
+			return spew.Sdump(sweepTx)
+		}))
+
+	if err := s.wallet.PublishTransaction(sweepTx); err != nil {
+		return fmt.Errorf("unable to broadcast sweep tx %v: %v",
+			sweepTx.TxHash(), err)
+	}
+
+	for _, input := range batch {
+		input.sweepTx = sweepTx
+	}
+
+	return nil
+}
+
+// handleSweepError handles the error encountered while sweeping the passed
+// input on its own, returning true if the sweeper has given up on the input.
+// If no sweep transaction can be created for the input, then it'll never be
+// swept, so its source is notified of the failure, and its spend is no
+// longer watched. Otherwise, the sweep was rejected, possibly as the input
+// has been spent elsewhere, so it's left to be retried with the next block.
+func (s *sweeper) handleSweepError(input *pendingInput, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if _, ok := err.(*unsweepableError); !ok {
+		swprLog.Errorf("Unable to sweep input %v, retrying with the "+
+			"next block: %v", input.outPoint, err)
+		return false
+	}
+
+	swprLog.Errorf("Unable to sweep input %v: %v", input.outPoint, err)
+
+	input.spendEvent.Cancel()
+	input.resultChan <- &sweepResult{err: err}
+
+	return true
+}
+
+// rebroadcast broadcasts each sweep transaction spending a pending input once
+// again, ensuring that transactions evicted from the mempool will still
+// confirm.
+func (s *sweeper) rebroadcast(pending map[wire.OutPoint]*pendingInput) {
+	sweepTxs := make(map[chainhash.Hash]*wire.MsgTx)
+	for _, input := range pending {
+		if input.sweepTx != nil {
+			sweepTxs[input.sweepTx.TxHash()] = input.sweepTx
+		}
+	}
+
+	for txid, sweepTx := range sweepTxs {
+		if err := s.wallet.PublishTransaction(sweepTx); err != nil {
+			swprLog.Debugf("unable to rebroadcast sweep tx %v: %v",
+				txid, err)
+		}
+	}
+}
+
+// pendingInputs is a slice of pendingInputs which implements the
+// sort.Interface, ordering the inputs by txid, then by output index.
+type pendingInputs []*pendingInput
+
+// Len returns the number of inputs within the slice.
+func (p pendingInputs) Len() int {
+	return len(p)
+}
+
+// Less returns true if the input at index i sorts before the input at index
+// j.
+func (p pendingInputs) Less(i, j int) bool {
+	a, b := p[i].outPoint, p[j].outPoint
+	cmp := bytes.Compare(a.Hash[:], b.Hash[:])
+	if cmp != 0 {
+		return cmp < 0
+	}
+
+	return a.Index < b.Index
+}
+
+// Swap swaps the inputs at indexes i and j.
+func (p pendingInputs) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// createSweepTx creates a final sweeping transaction with all witnesses in
+// place for all inputs. The created transaction has a single output sending
+// all the funds back to the source wallet, less the fee for the transaction
+// at the passed fee rate.
+func createSweepTx(wallet *lnwallet.LightningWallet,
+	feePerWeight btcutil.Amount, inputs []*sweepInput) (*wire.MsgTx, error) {
+
+	pkScript, err := newSweepPkScript(wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalSum btcutil.Amount
+	witnessSizes := make([]int, 0, len(inputs))
+	for _, input := range inputs {
+		totalSum += input.amt
+		witnessSizes = append(witnessSizes, input.witnessSize)
+	}
+
+	// Using the size of each input's witness, we'll estimate the weight
+	// of the sweep transaction, and from it the fee it should pay.
+	txWeight := lnwallet.EstimateSweepTxCost(witnessSizes...)
+	txFee := feePerWeight * btcutil.Amount(txWeight)
+	if totalSum <= txFee {
+		return nil, fmt.Errorf("inputs of %v are unable to pay sweep "+
+			"tx fee of %v", totalSum, txFee)
+	}
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: pkScript,
+		Value:    int64(totalSum - txFee),
+	})
+	for _, input := range inputs {
+		sweepTx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: input.outPoint,
+			Sequence:         input.sequence,
+		})
+
+		// The lock time of the sweep transaction must satisfy the
+		// absolute time-lock of every input being swept.
+		if input.lockTime > sweepTx.LockTime {
+			sweepTx.LockTime = input.lockTime
+		}
+	}
+
+	// With all the inputs in place, use each input's unique witness
+	// function to generate the final witness required for spending.
+	hashCache := txscript.NewTxSigHashes(sweepTx)
+	for i, txIn := range sweepTx.TxIn {
+		witness, err := inputs[i].witnessFunc(sweepTx, hashCache, i)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn.Witness = witness
+	}
+
+	return sweepTx, nil
+}
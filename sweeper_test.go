@@ -0,0 +1,385 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// sweepTestFeeRate is the fee rate, in satoshis per weight unit, used
+	// by the sweeper within each test.
+	sweepTestFeeRate = 50
+
+	// sweepTestTimeout is the duration a test waits for an input to be
+	// spent before failing.
+	sweepTestTimeout = 5 * time.Second
+)
+
+// sweeperTestContext bundles a sweeper with the simulated chain and wallet
+// backing it.
+type sweeperTestContext struct {
+	*simWalletHarness
+
+	sweeper *sweeper
+}
+
+// newSweeperTestContext creates a sweeper backed by a simulated chain, which
+// collects inputs for the passed batch window before sweeping them.
+func newSweeperTestContext(t *testing.T,
+	batchWindow time.Duration) *sweeperTestContext {
+
+	harness := newSimWalletHarness(t, 2)
+
+	sweeper := newSweeper(harness.lnWallet, harness.chain,
+		lnwallet.StaticFeeEstimator{FeeRate: sweepTestFeeRate},
+		batchWindow)
+	if err := sweeper.Start(); err != nil {
+		t.Fatalf("unable to start sweeper: %v", err)
+	}
+
+	return &sweeperTestContext{
+		simWalletHarness: harness,
+		sweeper:          sweeper,
+	}
+}
+
+// stop shuts down the sweeper and the simulated chain.
+func (ctx *sweeperTestContext) stop() {
+	ctx.sweeper.Stop()
+	ctx.simWalletHarness.stop()
+}
+
+// newInput publishes a transaction creating a p2wkh output of the passed
+// amount paying to the wallet, returning a sweepInput which spends it along
+// with the output itself.
+func (ctx *sweeperTestContext) newInput(amt btcutil.Amount,
+	lockTime uint32) (*sweepInput, *wire.TxOut) {
+
+	output, outPoint := ctx.fundWallet(amt)
+
+	signDesc := lnwallet.SignDescriptor{
+		Output:   output,
+		HashType: txscript.SigHashAll,
+	}
+	witnessFunc := func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
+		inputIndex int) ([][]byte, error) {
+
+		desc := signDesc
+		desc.SigHashes = hc
+		desc.InputIndex = inputIndex
+
+		inputScript, err := ctx.wallet.ComputeInputScript(tx, &desc)
+		if err != nil {
+			return nil, err
+		}
+
+		return inputScript.Witness, nil
+	}
+
+	return &sweepInput{
+		outPoint:    outPoint,
+		amt:         amt,
+		witnessFunc: witnessFunc,
+		witnessSize: lnwallet.P2WKHWitnessSize,
+		lockTime:    lockTime,
+		confTarget:  6,
+	}, output
+}
+
+// sweep hands the passed input to the sweeper, returning the channel its
+// result is delivered over.
+func (ctx *sweeperTestContext) sweep(input *sweepInput) <-chan *sweepResult {
+	resultChan, err := ctx.sweeper.sweepInput(input)
+	if err != nil {
+		ctx.t.Fatalf("unable to sweep input: %v", err)
+	}
+
+	return resultChan
+}
+
+// assertResult waits for the result of an input, failing if it isn't
+// delivered in time, or doesn't match the expected value of swept.
+func (ctx *sweeperTestContext) assertResult(resultChan <-chan *sweepResult,
+	swept bool) *sweepResult {
+
+	select {
+	case result := <-resultChan:
+		if result.err != nil {
+			ctx.t.Fatalf("unable to sweep input: %v", result.err)
+		}
+		if result.swept != swept {
+			ctx.t.Fatalf("expected swept=%v, got %v", swept,
+				result.swept)
+		}
+		return result
+	case <-time.After(sweepTestTimeout):
+		ctx.t.Fatalf("input never spent")
+	}
+
+	return nil
+}
+
+// assertNoResult asserts that the input hasn't been spent.
+func (ctx *sweeperTestContext) assertNoResult(resultChan <-chan *sweepResult) {
+	select {
+	case result := <-resultChan:
+		if result.err != nil {
+			ctx.t.Fatalf("unable to sweep input: %v", result.err)
+		}
+		ctx.t.Fatalf("input unexpectedly spent by tx %v",
+			result.spendingTx.TxHash())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// assertFailed asserts that the sweeper gives up on sweeping the input.
+func (ctx *sweeperTestContext) assertFailed(resultChan <-chan *sweepResult) {
+	select {
+	case result := <-resultChan:
+		if result.err == nil {
+			ctx.t.Fatalf("expected sweep to fail, input spent by "+
+				"tx %v", result.spendingTx.TxHash())
+		}
+	case <-time.After(sweepTestTimeout):
+		ctx.t.Fatalf("sweep failure never reported")
+	}
+}
+
+// assertValidSpend asserts that the input at the passed index of the sweep
+// transaction validly spends the passed output.
+func assertValidSpend(t *testing.T, sweepTx *wire.MsgTx, inputIndex int,
+	output *wire.TxOut) {
+
+	vm, err := txscript.NewEngine(output.PkScript, sweepTx, inputIndex,
+		txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(sweepTx), output.Value)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("sweep tx input %v invalid: %v", inputIndex, err)
+	}
+}
+
+// TestSweeperBatchesInputs tests that inputs handed to the sweeper within the
+// same batch window are swept by a single valid transaction paying to the
+// wallet, and that each source is notified of the sweep.
+func TestSweeperBatchesInputs(t *testing.T) {
+	ctx := newSweeperTestContext(t, 50*time.Millisecond)
+	defer ctx.stop()
+
+	const numInputs = 3
+
+	var (
+		totalAmt    btcutil.Amount
+		outputs     = make(map[wire.OutPoint]*wire.TxOut)
+		resultChans []<-chan *sweepResult
+	)
+	for i := 0; i < numInputs; i++ {
+		amt := btcutil.Amount(i+1) * btcutil.SatoshiPerBitcoin
+		input, output := ctx.newInput(amt, 0)
+		outputs[input.outPoint] = output
+		totalAmt += amt
+
+		resultChans = append(resultChans, ctx.sweep(input))
+	}
+
+	sweepTx := ctx.assertResult(resultChans[0], true).spendingTx
+	for _, resultChan := range resultChans[1:] {
+		result := ctx.assertResult(resultChan, true)
+		if result.spendingTx.TxHash() != sweepTx.TxHash() {
+			t.Fatalf("inputs swept by different txns: %v vs %v",
+				sweepTx.TxHash(), result.spendingTx.TxHash())
+		}
+	}
+
+	if len(sweepTx.TxIn) != numInputs {
+		t.Fatalf("expected sweep tx with %v inputs, got %v", numInputs,
+			len(sweepTx.TxIn))
+	}
+	for i, txIn := range sweepTx.TxIn {
+		output, ok := outputs[txIn.PreviousOutPoint]
+		if !ok {
+			t.Fatalf("sweep tx spends unknown input %v",
+				txIn.PreviousOutPoint)
+		}
+		assertValidSpend(t, sweepTx, i, output)
+	}
+
+	// The sweep transaction should pay the total of the inputs, less the
+	// fee estimated for its weight, to the wallet.
+	if len(sweepTx.TxOut) != 1 {
+		t.Fatalf("expected sweep tx with 1 output, got %v",
+			len(sweepTx.TxOut))
+	}
+	witnessSizes := make([]int, numInputs)
+	for i := range witnessSizes {
+		witnessSizes[i] = lnwallet.P2WKHWitnessSize
+	}
+	txFee := sweepTestFeeRate *
+		btcutil.Amount(lnwallet.EstimateSweepTxCost(witnessSizes...))
+	if btcutil.Amount(sweepTx.TxOut[0].Value) != totalAmt-txFee {
+		t.Fatalf("expected sweep tx to pay %v, got %v", totalAmt-txFee,
+			btcutil.Amount(sweepTx.TxOut[0].Value))
+	}
+
+	sweepOutpoint := wire.OutPoint{Hash: sweepTx.TxHash()}
+	if _, err := ctx.wallet.FetchInputInfo(&sweepOutpoint); err != nil {
+		t.Fatalf("sweep tx doesn't pay to the wallet: %v", err)
+	}
+}
+
+// TestSweeperForeignSpend tests that an input spent by a transaction other
+// than one created by the sweeper is reported as such, and that the remaining
+// inputs are still swept.
+func TestSweeperForeignSpend(t *testing.T) {
+	ctx := newSweeperTestContext(t, 50*time.Millisecond)
+	defer ctx.stop()
+
+	foreignInput, _ := ctx.newInput(btcutil.SatoshiPerBitcoin, 0)
+	input, output := ctx.newInput(btcutil.SatoshiPerBitcoin, 0)
+
+	// Before the sweeper has a chance to sweep the first input, it'll be
+	// spent by another party.
+	foreignTx := wire.NewMsgTx(2)
+	foreignTx.AddTxIn(&wire.TxIn{PreviousOutPoint: foreignInput.outPoint})
+	foreignTx.AddTxOut(&wire.TxOut{Value: 1000})
+	if err := ctx.chain.PublishTransaction(foreignTx); err != nil {
+		t.Fatalf("unable to publish foreign tx: %v", err)
+	}
+
+	foreignResult := ctx.sweep(foreignInput)
+	resultChan := ctx.sweep(input)
+
+	result := ctx.assertResult(foreignResult, false)
+	if result.spendingTx.TxHash() != foreignTx.TxHash() {
+		t.Fatalf("expected spend by foreign tx %v, got %v",
+			foreignTx.TxHash(), result.spendingTx.TxHash())
+	}
+
+	// If the sweeper attempted to sweep both inputs together, then the
+	// sweep transaction will have been rejected as a double spend, and
+	// the remaining input swept on its own.
+	ctx.generate(1)
+
+	result = ctx.assertResult(resultChan, true)
+	sweepTx := result.spendingTx
+	if len(sweepTx.TxIn) != 1 {
+		t.Fatalf("expected sweep tx with 1 input, got %v",
+			len(sweepTx.TxIn))
+	}
+	if sweepTx.TxIn[0].PreviousOutPoint != input.outPoint {
+		t.Fatalf("expected sweep of %v, got %v", input.outPoint,
+			sweepTx.TxIn[0].PreviousOutPoint)
+	}
+	assertValidSpend(t, sweepTx, 0, output)
+}
+
+// TestSweeperIsolatesUnsweepableInput tests that an input which can't be
+// swept doesn't prevent the other inputs of its batch from being swept, and
+// that its source is notified of the failure.
+func TestSweeperIsolatesUnsweepableInput(t *testing.T) {
+	ctx := newSweeperTestContext(t, 50*time.Millisecond)
+	defer ctx.stop()
+
+	// The witness of the unsweepable input can't be generated, so no
+	// sweep transaction spending it can be created.
+	badInput, _ := ctx.newInput(btcutil.SatoshiPerBitcoin, 0)
+	badInput.witnessFunc = func(*wire.MsgTx, *txscript.TxSigHashes,
+		int) ([][]byte, error) {
+
+		return nil, errors.New("unable to generate witness")
+	}
+
+	input1, output1 := ctx.newInput(btcutil.SatoshiPerBitcoin, 0)
+	input2, output2 := ctx.newInput(btcutil.SatoshiPerBitcoin, 0)
+
+	badResult := ctx.sweep(badInput)
+	resultChan1 := ctx.sweep(input1)
+	resultChan2 := ctx.sweep(input2)
+
+	// The remaining inputs should be swept without waiting for the next
+	// block, each on its own.
+	ctx.assertFailed(badResult)
+	sweepTx1 := ctx.assertResult(resultChan1, true).spendingTx
+	sweepTx2 := ctx.assertResult(resultChan2, true).spendingTx
+	for _, sweepTx := range []*wire.MsgTx{sweepTx1, sweepTx2} {
+		if len(sweepTx.TxIn) != 1 {
+			t.Fatalf("expected sweep tx with 1 input, got %v",
+				len(sweepTx.TxIn))
+		}
+	}
+	assertValidSpend(t, sweepTx1, 0, output1)
+	assertValidSpend(t, sweepTx2, 0, output2)
+
+	// Having given up on the unsweepable input, the sweeper shouldn't
+	// retry it.
+	ctx.generate(1)
+	ctx.assertNoResult(badResult)
+}
+
+// TestSweeperAbsoluteLockTime tests that an input encumbered by an absolute
+// time-lock isn't swept until the lock time has been reached, and is then
+// swept by a transaction with a lock time satisfying it.
+func TestSweeperAbsoluteLockTime(t *testing.T) {
+	ctx := newSweeperTestContext(t, 10*time.Millisecond)
+	defer ctx.stop()
+
+	_, bestHeight, err := ctx.chain.GetBestBlock()
+	if err != nil {
+		t.Fatalf("unable to fetch best block: %v", err)
+	}
+	lockTime := uint32(bestHeight) + 2
+
+	input, output := ctx.newInput(btcutil.SatoshiPerBitcoin, lockTime)
+	resultChan := ctx.sweep(input)
+	ctx.assertNoResult(resultChan)
+
+	ctx.generate(1)
+	ctx.assertNoResult(resultChan)
+
+	ctx.generate(1)
+	sweepTx := ctx.assertResult(resultChan, true).spendingTx
+	if sweepTx.LockTime != lockTime {
+		t.Fatalf("expected sweep tx lock time of %v, got %v", lockTime,
+			sweepTx.LockTime)
+	}
+	assertValidSpend(t, sweepTx, 0, output)
+}
+
+// TestSweeperRejectsAfterStop tests that inputs handed to the sweeper after
+// it has been stopped are rejected.
+func TestSweeperRejectsAfterStop(t *testing.T) {
+	ctx := newSweeperTestContext(t, 10*time.Millisecond)
+	defer ctx.chain.Stop()
+
+	input, _ := ctx.newInput(btcutil.SatoshiPerBitcoin, 0)
+	if err := ctx.sweeper.Stop(); err != nil {
+		t.Fatalf("unable to stop sweeper: %v", err)
+	}
+
+	_, err := ctx.sweeper.sweepInput(input)
+	if err != ErrSweeperShuttingDown {
+		t.Fatalf("expected ErrSweeperShuttingDown, got %v", err)
+	}
+}
+
+// TestCreateSweepTxInsufficientFunds tests that a sweep transaction isn't
+// created for inputs unable to pay its fee.
+func TestCreateSweepTxInsufficientFunds(t *testing.T) {
+	ctx := newSweeperTestContext(t, time.Second)
+	defer ctx.stop()
+
+	input, _ := ctx.newInput(1000, 0)
+	_, err := createSweepTx(ctx.sweeper.wallet, sweepTestFeeRate,
+		[]*sweepInput{input})
+	if err == nil {
+		t.Fatalf("expected sweep tx creation to fail")
+	}
+}
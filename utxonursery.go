@@ -4,12 +4,12 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 	"sync/atomic"
 
 	"github.com/boltdb/bolt"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -31,12 +31,12 @@ var (
 	// have received an initial confirmation, but which aren't yet
 	// spendable because they require additional confirmations enforced by
 	// Check Sequence Verify. Once required additional confirmations have
-	// been reported, a sweep transaction will be created to move the funds
-	// out of these outputs. After a further six confirmations have been
-	// reported, the outputs will be deleted from this bucket. The purpose
-	// of this additional wait time is to ensure that a block
-	// reorganization doesn't result in the sweep transaction getting
-	// re-organized out of the chain.
+	// been reported, the outputs are handed to the sweeper to be moved
+	// into the wallet. Once the transaction spending an output has
+	// received graduationConfs confirmations, the output will be deleted
+	// from this bucket. The purpose of this additional wait time is to
+	// ensure that a block reorganization doesn't result in the sweep
+	// transaction getting re-organized out of the chain.
 	kindergartenBucket = []byte("kdg")
 
 	// lastGraduatedHeightKey is used to persist the last block height that
@@ -48,6 +48,13 @@ var (
 	byteOrder = binary.BigEndian
 )
 
+const (
+	// graduationConfs is the number of confirmations the transaction
+	// spending a kindergarten output must receive before the output is
+	// deleted from the kindergarten bucket.
+	graduationConfs = 6
+)
+
 // witnessType determines how an output's witness will be generated. The
 // default commitmentTimeLock type will generate a witness that will allow
 // spending of a time-locked transaction enforced by CheckSequenceVerify.
@@ -55,6 +62,11 @@ type witnessType uint16
 
 const (
 	commitmentTimeLock witnessType = 0
+
+	// htlcOfferedTimeout is the witness type of an outgoing HTLC output on
+	// our commitment transaction which we're able to reclaim after both
+	// the HTLC's absolute timeout and our relative delay have passed.
+	htlcOfferedTimeout witnessType = 1
 )
 
 // witnessGenerator represents a function which is able to generate the final
@@ -65,8 +77,7 @@ type witnessGenerator func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
 	inputIndex int) ([][]byte, error)
 
 // generateFunc will return the witnessGenerator function that a kidOutput uses
-// to generate the witness for a sweep transaction. Currently there is only one
-// witnessType but this will be expanded.
+// to generate the witness for a sweep transaction.
 func (wt witnessType) generateFunc(signer *lnwallet.Signer,
 	descriptor *lnwallet.SignDescriptor) witnessGenerator {
 
@@ -81,11 +92,42 @@ func (wt witnessType) generateFunc(signer *lnwallet.Signer,
 
 			return lnwallet.CommitSpendTimeout(*signer, desc, tx)
 		}
+	case htlcOfferedTimeout:
+		return func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
+			inputIndex int) ([][]byte, error) {
+
+			desc := descriptor
+			desc.SigHashes = hc
+			desc.InputIndex = inputIndex
+
+			return lnwallet.HtlcSpendTimeout(*signer, desc, tx)
+		}
 	}
 
 	return nil
 }
 
+// hasAbsoluteMaturity returns true if outputs of this witness type are
+// encumbered by an absolute time-lock, in addition to a relative one.
+func (wt witnessType) hasAbsoluteMaturity() bool {
+	return wt == htlcOfferedTimeout
+}
+
+// witnessSize returns an upper bound on the size of the witness generated
+// for an output of this witness type, used to estimate the fee of the sweep
+// transaction spending it. An error is returned for unknown witness types, as
+// the fee of a transaction sweeping them can't be estimated.
+func (wt witnessType) witnessSize() (int, error) {
+	switch wt {
+	case commitmentTimeLock:
+		return lnwallet.ToLocalWitnessSize, nil
+	case htlcOfferedTimeout:
+		return lnwallet.OfferedHtlcTimeoutWitnessSize, nil
+	}
+
+	return 0, fmt.Errorf("unknown witness type: %v", wt)
+}
+
 // utxoNursery is a system dedicated to incubating time-locked outputs created
 // by the broadcast of a commitment transaction either by us, or the remote
 // peer. The nursery accepts outputs and "incubates" them until they've reached
@@ -99,6 +141,7 @@ type utxoNursery struct {
 
 	notifier chainntnfs.ChainNotifier
 	wallet   *lnwallet.LightningWallet
+	sweeper  *sweeper
 
 	db *channeldb.DB
 
@@ -111,13 +154,15 @@ type utxoNursery struct {
 }
 
 // newUtxoNursery creates a new instance of the utxoNursery from a
-// ChainNotifier and LightningWallet instance.
+// ChainNotifier and LightningWallet instance. Mature outputs are handed to
+// the sweeper, which sweeps them into the wallet.
 func newUtxoNursery(db *channeldb.DB, notifier chainntnfs.ChainNotifier,
-	wallet *lnwallet.LightningWallet) *utxoNursery {
+	wallet *lnwallet.LightningWallet, sweeper *sweeper) *utxoNursery {
 
 	return &utxoNursery{
 		notifier: notifier,
 		wallet:   wallet,
+		sweeper:  sweeper,
 		requests: make(chan *incubationRequest),
 		db:       db,
 		quit:     make(chan struct{}),
@@ -177,7 +222,7 @@ func (u *utxoNursery) reloadPreschool() error {
 
 			utxnLog.Infof("Preschool outpoint %v re-registered for confirmation "+
 				"notification.", psclOutput.outPoint)
-			go psclOutput.waitForPromotion(u.db, confChan)
+			go psclOutput.waitForPromotion(u.db, confChan, u.quit)
 			return nil
 		})
 	})
@@ -207,6 +252,20 @@ func (u *utxoNursery) catchUpKindergarten() error {
 		return err
 	}
 
+	// Outputs which graduated before we were shut down are only deleted
+	// once the transaction spending them is confirmed, so we'll hand any
+	// that remain back to the sweeper to see them through.
+	if lastGraduatedHeight != 0 {
+		graduatedOutputs, err := fetchGraduatedOutputs(u.db, u.wallet,
+			lastGraduatedHeight)
+		if err != nil {
+			return err
+		}
+		if err := u.sweepKindergartenOutputs(graduatedOutputs); err != nil {
+			return err
+		}
+	}
+
 	// Get the most recently mined block
 	_, bestHeight, err := u.wallet.ChainIO.GetBestBlock()
 	if err != nil {
@@ -269,6 +328,11 @@ type kidOutput struct {
 	blocksToMaturity uint32
 	confHeight       uint32
 
+	// absoluteMaturity is the absolute block height before which the
+	// output can't be swept. This is zero for outputs which are only
+	// encumbered by a relative time-lock.
+	absoluteMaturity uint32
+
 	signDescriptor *lnwallet.SignDescriptor
 	witnessType    witnessType
 }
@@ -302,6 +366,23 @@ func (u *utxoNursery) incubateOutputs(closeSummary *lnwallet.ForceCloseSummary)
 		incReq.outputs = append(incReq.outputs, selfOutput)
 	}
 
+	// Each of our outgoing HTLCs on the commitment transaction can be
+	// swept back into the wallet once both the HTLC's absolute timeout
+	// and our relative delay have passed.
+	for _, htlcRes := range closeSummary.HtlcResolutions {
+		signDesc := htlcRes.SweepSignDesc
+		htlcOutput := &kidOutput{
+			amt:              btcutil.Amount(signDesc.Output.Value),
+			outPoint:         htlcRes.ClaimOutpoint,
+			blocksToMaturity: htlcRes.MaturityDelay,
+			absoluteMaturity: htlcRes.Expiry,
+			signDescriptor:   &signDesc,
+			witnessType:      htlcOfferedTimeout,
+		}
+
+		incReq.outputs = append(incReq.outputs, htlcOutput)
+	}
+
 	// If there are no outputs to incubate, there is nothing to send to the
 	// request channel.
 	if len(incReq.outputs) != 0 {
@@ -358,7 +439,7 @@ out:
 				// the output from the preschool bucket to the
 				// kindergarten bucket once the channel close
 				// transaction has been confirmed.
-				go output.waitForPromotion(u.db, confChan)
+				go output.waitForPromotion(u.db, confChan, u.quit)
 			}
 		case epoch, ok := <-newBlockChan.Epochs:
 			// If the epoch channel has been closed, then the
@@ -426,13 +507,77 @@ func (k *kidOutput) enterPreschool(db *channeldb.DB) error {
 // block. Once the transaction has been confirmed (as reported by the Chain
 // Notifier), waitForPromotion will delete the output from the "preschool"
 // database bucket and atomically add it to the "kindergarten" database bucket.
-// This is the second step in the output incubation process.
-func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.ConfirmationEvent) {
-	txConfirmation, ok := <-confChan.Confirmed
-	if !ok {
-		utxnLog.Errorf("notification chan "+
-			"closed, can't advance output %v", k.outPoint)
-		return
+// This is the second step in the output incubation process. If the
+// transaction is later re-org'd out of the main chain, the output is moved
+// back into the "preschool" bucket until the transaction is re-confirmed.
+func (k *kidOutput) waitForPromotion(db *channeldb.DB,
+	confChan *chainntnfs.ConfirmationEvent, quit <-chan struct{}) {
+
+	for {
+		if !k.waitForConfirmation(db, confChan, quit) {
+			return
+		}
+
+		// Even once promoted, the transaction which created the output
+		// may still be re-org'd out of the main chain. In that case,
+		// we move the output back into the preschool bucket, and wait
+		// for the transaction to be re-confirmed.
+		select {
+		case depth, ok := <-confChan.NegativeConf:
+			if !ok {
+				return
+			}
+
+			utxnLog.Warnf("Transaction creating outpoint %v re-org'd "+
+				"out of the main chain (depth=%v), moving back to "+
+				"preschool", k.outPoint, depth)
+
+			if err := k.demoteToPreschool(db); err != nil {
+				utxnLog.Errorf("unable to move kid output from "+
+					"kindergarten bucket to preschool bucket: %v",
+					err)
+				return
+			}
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// waitForConfirmation waits for the transaction creating the kidOutput to be
+// confirmed, then moves the output from the preschool bucket into the
+// kindergarten bucket. False is returned if the output wasn't promoted.
+func (k *kidOutput) waitForConfirmation(db *channeldb.DB,
+	confChan *chainntnfs.ConfirmationEvent, quit <-chan struct{}) bool {
+
+	var txConfirmation *chainntnfs.TxConfirmation
+	for txConfirmation == nil {
+		select {
+		case conf, ok := <-confChan.Confirmed:
+			if !ok {
+				utxnLog.Errorf("notification chan "+
+					"closed, can't advance output %v", k.outPoint)
+				return false
+			}
+			txConfirmation = conf
+
+		// If the transaction is re-org'd out before we've been
+		// notified of its confirmation, then the notification will be
+		// re-armed, so we continue to wait.
+		case <-confChan.NegativeConf:
+			continue
+
+		case <-quit:
+			return false
+		}
+	}
+
+	// Any re-org signalled prior to the confirmation we've just received
+	// is now stale, so we'll drain it.
+	select {
+	case <-confChan.NegativeConf:
+	default:
 	}
 
 	utxnLog.Infof("Outpoint %v confirmed in block %v moving to kindergarten",
@@ -465,7 +610,7 @@ func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.Conf
 			return err
 		}
 
-		maturityHeight := k.confHeight + k.blocksToMaturity
+		maturityHeight := k.maturityHeight()
 
 		heightBytes := make([]byte, 4)
 		byteOrder.PutUint32(heightBytes, maturityHeight)
@@ -494,7 +639,104 @@ func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.Conf
 	if err != nil {
 		utxnLog.Errorf("unable to move kid output from preschool bucket "+
 			"to kindergarten bucket: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// maturityHeight returns the block height at which the kidOutput can be
+// swept into the wallet. An output which is also encumbered by an absolute
+// time-lock can't mature before that height has been reached.
+func (k *kidOutput) maturityHeight() uint32 {
+	maturityHeight := k.confHeight + k.blocksToMaturity
+	if k.absoluteMaturity > maturityHeight {
+		maturityHeight = k.absoluteMaturity
 	}
+
+	return maturityHeight
+}
+
+// demoteToPreschool moves a kidOutput from the kindergarten bucket back into
+// the preschool bucket after the transaction which created it has been
+// re-org'd out of the main chain.
+func (k *kidOutput) demoteToPreschool(db *channeldb.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		if err := k.removeFromKindergarten(tx); err != nil {
+			return err
+		}
+
+		psclBucket, err := tx.CreateBucketIfNotExists(preschoolBucket)
+		if err != nil {
+			return err
+		}
+
+		k.confHeight = 0
+
+		var outpointBytes bytes.Buffer
+		if err := writeOutpoint(&outpointBytes, &k.outPoint); err != nil {
+			return err
+		}
+
+		var kidBytes bytes.Buffer
+		if err := serializeKidOutput(&kidBytes, k); err != nil {
+			return err
+		}
+
+		if err := psclBucket.Put(outpointBytes.Bytes(), kidBytes.Bytes()); err != nil {
+			return err
+		}
+
+		utxnLog.Infof("Outpoint %v now in preschool, waiting for "+
+			"confirmation", k.outPoint)
+
+		return nil
+	})
+}
+
+// removeFromKindergarten removes the kidOutput from the list of outputs
+// maturing at its maturity height within the kindergarten bucket.
+func (k *kidOutput) removeFromKindergarten(tx *bolt.Tx) error {
+	kgtnBucket := tx.Bucket(kindergartenBucket)
+	if kgtnBucket == nil {
+		return errors.New("unable to open kindergarten bucket")
+	}
+
+	heightBytes := make([]byte, 4)
+	byteOrder.PutUint32(heightBytes, k.maturityHeight())
+
+	results := kgtnBucket.Get(heightBytes)
+	if results == nil {
+		return errors.New("output not found in kindergarten bucket")
+	}
+	kgtnOutputs, err := deserializeKidList(bytes.NewReader(results))
+	if err != nil {
+		return err
+	}
+
+	// Re-serialize the outputs maturing at the same height, excluding the
+	// output being removed.
+	var (
+		found        bool
+		otherOutputs bytes.Buffer
+	)
+	for _, kgtnOutput := range kgtnOutputs {
+		if kgtnOutput.outPoint == k.outPoint {
+			found = true
+			continue
+		}
+		if err := serializeKidOutput(&otherOutputs, kgtnOutput); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return errors.New("output not found in kindergarten bucket")
+	}
+
+	if otherOutputs.Len() == 0 {
+		return kgtnBucket.Delete(heightBytes)
+	}
+	return kgtnBucket.Put(heightBytes, otherOutputs.Bytes())
 }
 
 // graduateKindergarten handles the steps invoked with moving funds from a
@@ -512,18 +754,10 @@ func (u *utxoNursery) graduateKindergarten(blockHeight uint32) error {
 		return err
 	}
 
-	// If we're able to graduate any outputs, then create a single
-	// transaction which sweeps them all into the wallet.
-	if len(kgtnOutputs) > 0 {
-		if err := sweepGraduatingOutputs(u.wallet, kgtnOutputs); err != nil {
-			return err
-		}
-	}
-
-	// Using a re-org safety margin of 6-blocks, delete any outputs which
-	// have graduated 6 blocks ago.
-	deleteHeight := blockHeight - 6
-	if err := deleteGraduatedOutputs(u.db, deleteHeight); err != nil {
+	// If we're able to graduate any outputs, then hand them off to the
+	// sweeper, which will batch them into a transaction sweeping them
+	// into the wallet.
+	if err := u.sweepKindergartenOutputs(kgtnOutputs); err != nil {
 		return err
 	}
 
@@ -532,6 +766,99 @@ func (u *utxoNursery) graduateKindergarten(blockHeight uint32) error {
 	return putLastHeightGraduated(u.db, blockHeight)
 }
 
+// sweepKindergartenOutputs hands the passed mature outputs to the sweeper,
+// launching a goroutine for each which deletes it from the kindergarten bucket
+// once the transaction spending it has been confirmed.
+func (u *utxoNursery) sweepKindergartenOutputs(kgtnOutputs []*kidOutput) error {
+	for _, kgtnOutput := range kgtnOutputs {
+		witnessSize, err := kgtnOutput.witnessType.witnessSize()
+		if err != nil {
+			return err
+		}
+
+		resultChan, err := u.sweeper.sweepInput(&sweepInput{
+			outPoint:    kgtnOutput.outPoint,
+			amt:         kgtnOutput.amt,
+			witnessFunc: kgtnOutput.witnessFunc,
+			witnessSize: witnessSize,
+			sequence:    kgtnOutput.blocksToMaturity,
+			lockTime:    kgtnOutput.absoluteMaturity,
+			confTarget:  lnwallet.SweepTxConfTarget,
+			heightHint:  kgtnOutput.confHeight,
+		})
+		if err != nil {
+			return err
+		}
+
+		u.wg.Add(1)
+		go u.waitForGraduation(kgtnOutput, resultChan)
+	}
+
+	return nil
+}
+
+// waitForGraduation waits for the sweeper to report the spend of the passed
+// kindergarten output, then deletes the output from the kindergarten bucket
+// once the spending transaction has received graduationConfs confirmations.
+// This is the final step in the output incubation process.
+//
+// NOTE: This MUST be run as a goroutine.
+func (u *utxoNursery) waitForGraduation(k *kidOutput,
+	resultChan <-chan *sweepResult) {
+
+	defer u.wg.Done()
+
+	var result *sweepResult
+	select {
+	case result = <-resultChan:
+	case <-u.quit:
+		return
+	}
+
+	// If the output can't be swept, then it's left within the
+	// kindergarten bucket.
+	if result.err != nil {
+		utxnLog.Errorf("unable to sweep kindergarten output %v: %v",
+			k.outPoint, result.err)
+		return
+	}
+
+	spendTxid := result.spendingTx.TxHash()
+	if !result.swept {
+		utxnLog.Warnf("Kindergarten output %v was spent by foreign "+
+			"tx %v", k.outPoint, spendTxid)
+	}
+
+	confChan, err := u.notifier.RegisterConfirmationsNtfn(&spendTxid,
+		graduationConfs)
+	if err != nil {
+		utxnLog.Errorf("unable to register for confirmation of tx %v "+
+			"spending %v: %v", spendTxid, k.outPoint, err)
+		return
+	}
+
+	select {
+	case _, ok := <-confChan.Confirmed:
+		if !ok {
+			return
+		}
+	case <-u.quit:
+		return
+	}
+
+	err = u.db.Update(func(tx *bolt.Tx) error {
+		return k.removeFromKindergarten(tx)
+	})
+	if err != nil {
+		utxnLog.Errorf("unable to delete graduated output %v: %v",
+			k.outPoint, err)
+		return
+	}
+
+	utxnLog.Infof("Output %v graduated, spent by tx %v with %v "+
+		"confirmations", k.outPoint, spendTxid, graduationConfs)
+}
+
 // fetchGraduatingOutputs checks the "kindergarten" database bucket whenever a
 // new block is received in order to determine if commitment transaction
 // outputs have become newly spendable. If fetchGraduatingOutputs finds outputs
@@ -575,11 +902,7 @@ func fetchGraduatingOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
 	// function based on its witness type. This varies if the output is on
 	// our commitment transaction or theirs, and also if it's an HTLC
 	// output or not.
-	for _, kgtnOutput := range kgtnOutputs {
-		kgtnOutput.witnessFunc = kgtnOutput.witnessType.generateFunc(
-			&wallet.Signer, kgtnOutput.signDescriptor,
-		)
-	}
+	setWitnessFuncs(wallet, kgtnOutputs)
 
 	utxnLog.Infof("New block: height=%v, sweeping %v mature outputs",
 		blockHeight, len(kgtnOutputs))
@@ -587,120 +910,55 @@ func fetchGraduatingOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
 	return kgtnOutputs, nil
 }
 
-// sweepGraduatingOutputs generates and broadcasts the transaction that
-// transfers control of funds from a channel commitment transaction to the
-// user's wallet.
-func sweepGraduatingOutputs(wallet *lnwallet.LightningWallet, kgtnOutputs []*kidOutput) error {
-	// Create a transaction which sweeps all the newly mature outputs into
-	// a output controlled by the wallet.
-	// TODO(roasbeef): can be more intelligent about buffering outputs to
-	// be more efficient on-chain.
-	sweepTx, err := createSweepTx(wallet, kgtnOutputs)
-	if err != nil {
-		// TODO(roasbeef): retry logic?
-		utxnLog.Errorf("unable to create sweep tx: %v", err)
-		return err
-	}
-
-	utxnLog.Infof("Sweeping %v time-locked outputs "+
-		"with sweep tx (txid=%v): %v", len(kgtnOutputs),
-		sweepTx.TxHash(),
-		newLogClosure(func() string {
-			return spew.Sdump(sweepTx)
-		}))
-
-	// With the sweep transaction fully signed, broadcast the transaction
-	// to the network. Additionally, we can stop tracking these outputs as
-	// they've just been swept.
-	if err := wallet.PublishTransaction(sweepTx); err != nil {
-		utxnLog.Errorf("unable to broadcast sweep tx: %v, %v",
-			err, spew.Sdump(sweepTx))
-		return err
-	}
-
-	return nil
-}
+// fetchGraduatedOutputs returns all outputs within the kindergarten bucket
+// which matured at or before the passed height. These outputs have already
+// been handed to the sweeper, but the transaction spending them has yet to
+// be sufficiently confirmed.
+func fetchGraduatedOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
+	lastGraduatedHeight uint32) ([]*kidOutput, error) {
 
-// createSweepTx creates a final sweeping transaction with all witnesses in
-// place for all inputs. The created transaction has a single output sending
-// all the funds back to the source wallet.
-func createSweepTx(wallet *lnwallet.LightningWallet,
-	matureOutputs []*kidOutput) (*wire.MsgTx, error) {
-
-	pkScript, err := newSweepPkScript(wallet)
-	if err != nil {
-		return nil, err
-	}
-
-	var totalSum btcutil.Amount
-	for _, o := range matureOutputs {
-		totalSum += o.amt
-	}
-
-	sweepTx := wire.NewMsgTx(2)
-	sweepTx.AddTxOut(&wire.TxOut{
-		PkScript: pkScript,
-		Value:    int64(totalSum - 5000),
-	})
-	for _, utxo := range matureOutputs {
-		sweepTx.AddTxIn(&wire.TxIn{
-			PreviousOutPoint: utxo.outPoint,
-			// TODO(roasbeef): assumes pure block delays
-			Sequence: utxo.blocksToMaturity,
-		})
-	}
-
-	// TODO(roasbeef): insert fee calculation
-	//  * remove hardcoded fee above
-
-	// With all the inputs in place, use each output's unique witness
-	// function to generate the final witness required for spending.
-	hashCache := txscript.NewTxSigHashes(sweepTx)
-	for i, txIn := range sweepTx.TxIn {
-		witness, err := matureOutputs[i].witnessFunc(sweepTx, hashCache, i)
-		if err != nil {
-			return nil, err
-		}
-
-		txIn.Witness = witness
-	}
-
-	return sweepTx, nil
-}
-
-// deleteGraduatedOutputs removes outputs from the kindergarten database bucket
-// when six blockchain confirmations have passed since the outputs were swept.
-// We wait for six confirmations to ensure that the outputs will be swept if a
-// chain reorganization occurs. This is the final step in the output incubation
-// process.
-func deleteGraduatedOutputs(db *channeldb.DB, deleteHeight uint32) error {
-	return db.Update(func(tx *bolt.Tx) error {
+	var kgtnOutputs []*kidOutput
+	err := db.View(func(tx *bolt.Tx) error {
 		kgtnBucket := tx.Bucket(kindergartenBucket)
 		if kgtnBucket == nil {
 			return nil
 		}
 
-		heightBytes := make([]byte, 4)
-		byteOrder.PutUint32(heightBytes, deleteHeight)
-		results := kgtnBucket.Get(heightBytes)
-		if results == nil {
-			return nil
-		}
+		return kgtnBucket.ForEach(func(k, v []byte) error {
+			// Only the keys of the kindergarten bucket which are
+			// block heights hold outputs.
+			if len(k) != 4 {
+				return nil
+			}
+			if byteOrder.Uint32(k) > lastGraduatedHeight {
+				return nil
+			}
 
-		sweptOutputs, err := deserializeKidList(bytes.NewBuffer(results))
-		if err != nil {
-			return err
-		}
+			outputs, err := deserializeKidList(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			kgtnOutputs = append(kgtnOutputs, outputs...)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if err := kgtnBucket.Delete(heightBytes); err != nil {
-			return err
-		}
+	setWitnessFuncs(wallet, kgtnOutputs)
 
-		utxnLog.Infof("Deleting %v swept outputs from kindergarten bucket "+
-			"at block height: %v", len(sweptOutputs), deleteHeight)
+	return kgtnOutputs, nil
+}
 
-		return nil
-	})
+// setWitnessFuncs generates the witness function of each of the passed
+// outputs based on its witness type.
+func setWitnessFuncs(wallet *lnwallet.LightningWallet, kgtnOutputs []*kidOutput) {
+	for _, kgtnOutput := range kgtnOutputs {
+		kgtnOutput.witnessFunc = kgtnOutput.witnessType.generateFunc(
+			&wallet.Signer, kgtnOutput.signDescriptor,
+		)
+	}
 }
 
 // putLastHeightGraduated persists the most recently processed blockheight
@@ -755,7 +1013,7 @@ func deserializeKidList(r io.Reader) ([]*kidOutput, error) {
 // serializeKidOutput converts a KidOutput struct into a form
 // suitable for on-disk database storage. Note that the signDescriptor
 // struct field is included so that the output's witness can be generated
-// by createSweepTx() when the output becomes spendable.
+// by the sweeper when the output becomes spendable.
 func serializeKidOutput(w io.Writer, kid *kidOutput) error {
 	var scratch [8]byte
 	byteOrder.PutUint64(scratch[:], uint64(kid.amt))
@@ -782,6 +1040,17 @@ func serializeKidOutput(w io.Writer, kid *kidOutput) error {
 		return err
 	}
 
+	// The absolute maturity is only written for witness types which are
+	// encumbered by an absolute time-lock. This keeps the serialization
+	// of all other outputs identical to that used before the field was
+	// introduced, so existing records continue to decode.
+	if kid.witnessType.hasAbsoluteMaturity() {
+		byteOrder.PutUint32(scratch[:4], kid.absoluteMaturity)
+		if _, err := w.Write(scratch[:4]); err != nil {
+			return err
+		}
+	}
+
 	serializedPubKey := kid.signDescriptor.PubKey.SerializeCompressed()
 	if err := wire.WriteVarBytes(w, 0, serializedPubKey); err != nil {
 		return err
@@ -837,6 +1106,13 @@ func deserializeKidOutput(r io.Reader) (*kidOutput, error) {
 	}
 	kid.witnessType = witnessType(byteOrder.Uint16(scratch[:2]))
 
+	if kid.witnessType.hasAbsoluteMaturity() {
+		if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+			return nil, err
+		}
+		kid.absoluteMaturity = byteOrder.Uint32(scratch[:4])
+	}
+
 	kid.signDescriptor = &lnwallet.SignDescriptor{}
 
 	descKeyBytes, err := wire.ReadVarBytes(r, 0, 34, "descKeyBytes")
@@ -856,7 +1132,7 @@ func deserializeKidOutput(r io.Reader) (*kidOutput, error) {
 	}
 	kid.signDescriptor.PrivateTweak = descPrivateTweak
 
-	descWitnessScript, err := wire.ReadVarBytes(r, 0, 100, "witnessScript")
+	descWitnessScript, err := wire.ReadVarBytes(r, 0, 500, "witnessScript")
 	if err != nil {
 		return nil, err
 	}
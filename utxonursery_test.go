@@ -2,12 +2,21 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/simchain"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
@@ -184,6 +193,8 @@ var (
 			outPoint:         outPoints[2],
 			blocksToMaturity: uint32(12),
 			confHeight:       uint32(34241),
+			absoluteMaturity: uint32(34300),
+			witnessType:      htlcOfferedTimeout,
 		},
 	}
 )
@@ -243,3 +254,301 @@ func TestSerializeKidOutput(t *testing.T) {
 		t.Fatalf("kidOutputs don't match %+v vs %+v", kid, deserializedKid)
 	}
 }
+
+// TestSerializeKidOutputLegacy tests that outputs which aren't encumbered by an
+// absolute time-lock are serialized without an absolute maturity, matching the
+// format of records written before the field was introduced.
+func TestSerializeKidOutputLegacy(t *testing.T) {
+	descriptor := signDescriptors[2]
+	pk, err := btcec.ParsePubKey(keys[2], btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse pub key: %v", keys[2])
+	}
+	descriptor.PubKey = pk
+
+	htlcKid := kidOutputs[2]
+	htlcKid.signDescriptor = &descriptor
+	commitKid := htlcKid
+	commitKid.witnessType = commitmentTimeLock
+
+	var htlcBytes, commitBytes bytes.Buffer
+	if err := serializeKidOutput(&htlcBytes, &htlcKid); err != nil {
+		t.Fatalf("unable to serialize kid output: %v", err)
+	}
+	if err := serializeKidOutput(&commitBytes, &commitKid); err != nil {
+		t.Fatalf("unable to serialize kid output: %v", err)
+	}
+
+	// Only the output encumbered by an absolute time-lock should carry the
+	// extra four bytes of its absolute maturity.
+	if htlcBytes.Len() != commitBytes.Len()+4 {
+		t.Fatalf("expected htlc output to be 4 bytes larger, got %v vs %v",
+			htlcBytes.Len(), commitBytes.Len())
+	}
+
+	deserializedKid, err := deserializeKidOutput(&commitBytes)
+	if err != nil {
+		t.Fatalf("unable to deserialize kid output: %v", err)
+	}
+	if deserializedKid.absoluteMaturity != 0 {
+		t.Fatalf("expected no absolute maturity, got %v",
+			deserializedKid.absoluteMaturity)
+	}
+}
+
+// toLocalScript returns the witness script of a to-local output on our
+// commitment transaction, which we're able to sweep after csvDelay blocks.
+func toLocalScript(csvDelay uint32, selfKey,
+	revokeKey *btcec.PublicKey) ([]byte, error) {
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_IF)
+	builder.AddData(revokeKey.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_ELSE)
+	builder.AddData(selfKey.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+	builder.AddInt64(int64(csvDelay))
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_ENDIF)
+
+	return builder.Script()
+}
+
+// inKindergarten returns true if the passed outpoint is held within the
+// nursery's kindergarten bucket.
+func inKindergarten(db *channeldb.DB, op wire.OutPoint) (bool, error) {
+	var found bool
+	err := db.View(func(tx *bolt.Tx) error {
+		kgtnBucket := tx.Bucket(kindergartenBucket)
+		if kgtnBucket == nil {
+			return nil
+		}
+
+		return kgtnBucket.ForEach(func(k, v []byte) error {
+			if bytes.Equal(k, lastGraduatedHeightKey) {
+				return nil
+			}
+
+			kids, err := deserializeKidList(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			for _, kid := range kids {
+				if kid.outPoint == op {
+					found = true
+				}
+			}
+			return nil
+		})
+	})
+
+	return found, err
+}
+
+// TestNurserySweepsMatureOutput tests that an output handed to the nursery is
+// promoted once the commitment transaction creating it confirms, and is swept
+// back into the wallet by the sweeper once its relative time-lock expires.
+// The nursery is driven by a simulated chain, so no full node is required.
+func TestNurserySweepsMatureOutput(t *testing.T) {
+	const (
+		csvDelay  = 5
+		outputAmt = btcutil.SatoshiPerBitcoin
+	)
+
+	chain := simchain.New(&chaincfg.RegressionNetParams)
+	if err := chain.Start(); err != nil {
+		t.Fatalf("unable to start simulated chain: %v", err)
+	}
+	defer chain.Stop()
+
+	simWallet, err := simchain.NewWallet(chain, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+	wallet := &lnwallet.LightningWallet{
+		WalletController: simWallet,
+		Signer:           simWallet,
+		ChainIO:          simWallet,
+	}
+
+	tempDir, err := ioutil.TempDir("", "nursery")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer cdb.Close()
+
+	sweeper := newSweeper(wallet, chain,
+		lnwallet.StaticFeeEstimator{FeeRate: 50}, 10*time.Millisecond)
+	if err := sweeper.Start(); err != nil {
+		t.Fatalf("unable to start sweeper: %v", err)
+	}
+	defer sweeper.Stop()
+
+	nursery := newUtxoNursery(cdb, chain, wallet, sweeper)
+	if err := nursery.Start(); err != nil {
+		t.Fatalf("unable to start nursery: %v", err)
+	}
+	defer nursery.Stop()
+
+	// We'll publish a commitment transaction with a to-local output
+	// spendable by the wallet once csvDelay blocks have passed.
+	selfKey, err := simWallet.NewRawKey()
+	if err != nil {
+		t.Fatalf("unable to create self key: %v", err)
+	}
+	revokeKey, err := simWallet.NewRawKey()
+	if err != nil {
+		t.Fatalf("unable to create revocation key: %v", err)
+	}
+	witnessScript, err := toLocalScript(csvDelay, selfKey, revokeKey)
+	if err != nil {
+		t.Fatalf("unable to create to-local script: %v", err)
+	}
+	scriptHash := sha256.Sum256(witnessScript)
+	pkScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(scriptHash[:]).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to create p2wsh script: %v", err)
+	}
+
+	output := &wire.TxOut{Value: outputAmt, PkScript: pkScript}
+	commitTxid, err := chain.SendOutputs([]*wire.TxOut{output}, 0)
+	if err != nil {
+		t.Fatalf("unable to publish commitment tx: %v", err)
+	}
+	selfOutpoint := wire.OutPoint{Hash: *commitTxid}
+
+	spendEvent, err := chain.RegisterSpendNtfn(&selfOutpoint, 0)
+	if err != nil {
+		t.Fatalf("unable to register for spend: %v", err)
+	}
+
+	nursery.incubateOutputs(&lnwallet.ForceCloseSummary{
+		SelfOutpoint:       selfOutpoint,
+		SelfOutputMaturity: csvDelay,
+		SelfOutputSignDesc: &lnwallet.SignDescriptor{
+			PubKey:        selfKey,
+			WitnessScript: witnessScript,
+			Output:        output,
+			HashType:      txscript.SigHashAll,
+		},
+	})
+
+	// Once the commitment transaction confirms, the output should be
+	// promoted to the kindergarten bucket.
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	timeout := time.After(5 * time.Second)
+	for {
+		promoted, err := inKindergarten(cdb, selfOutpoint)
+		if err != nil {
+			t.Fatalf("unable to read kindergarten: %v", err)
+		}
+		if promoted {
+			break
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("output never promoted to kindergarten")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// The output shouldn't be swept until its time-lock has expired.
+	if _, err := chain.Generate(csvDelay - 1); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	select {
+	case <-spendEvent.Spend:
+		t.Fatalf("output swept before maturity")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Once the output matures, the sweeper should broadcast a valid
+	// transaction sweeping it into the wallet.
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	var spend *chainntnfs.SpendDetail
+	select {
+	case spend = <-spendEvent.Spend:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("output never swept")
+	}
+
+	sweepTx := spend.SpendingTx
+	vm, err := txscript.NewEngine(pkScript, sweepTx,
+		int(spend.SpenderInputIndex), txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(sweepTx), outputAmt)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("sweep tx spend invalid: %v", err)
+	}
+
+	sweepOutpoint := wire.OutPoint{Hash: sweepTx.TxHash()}
+	if _, err := simWallet.FetchInputInfo(&sweepOutpoint); err != nil {
+		t.Fatalf("sweep tx doesn't pay to the wallet: %v", err)
+	}
+
+	// With the sweep transaction confirmed, its output should count
+	// towards the wallet's balance.
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	balance, err := simWallet.ConfirmedBalance(1, true)
+	if err != nil {
+		t.Fatalf("unable to fetch balance: %v", err)
+	}
+	if balance != btcutil.Amount(sweepTx.TxOut[0].Value) {
+		t.Fatalf("expected balance of %v, got %v",
+			btcutil.Amount(sweepTx.TxOut[0].Value), balance)
+	}
+
+	// The output should remain within the kindergarten bucket until the
+	// sweep transaction has received graduationConfs confirmations.
+	if _, err := chain.Generate(graduationConfs - 2); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	found, err := inKindergarten(cdb, selfOutpoint)
+	if err != nil {
+		t.Fatalf("unable to read kindergarten: %v", err)
+	}
+	if !found {
+		t.Fatalf("output deleted before sweep tx was sufficiently " +
+			"confirmed")
+	}
+
+	if _, err := chain.Generate(1); err != nil {
+		t.Fatalf("unable to generate block: %v", err)
+	}
+	timeout = time.After(5 * time.Second)
+	for {
+		found, err := inKindergarten(cdb, selfOutpoint)
+		if err != nil {
+			t.Fatalf("unable to read kindergarten: %v", err)
+		}
+		if !found {
+			break
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("output never deleted from kindergarten")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
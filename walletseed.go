@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lightningnetwork/lnd/cipherseed"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+)
+
+// initWalletSeed determines the seed the btcwallet described by the passed
+// config should be created from, if it doesn't yet exist. If the user has
+// asked to restore the wallet, then the mnemonic of an existing seed is read
+// from stdin, and the wallet will scan the chain for its funds once started.
+// Otherwise, a fresh seed is generated and its mnemonic displayed to the
+// user. This is the only time the mnemonic is ever displayed.
+func initWalletSeed(cfg *config, walletConfig *btcwallet.Config) error {
+	walletExists, err := btcwallet.WalletExists(walletConfig)
+	if err != nil {
+		return err
+	}
+	if walletExists {
+		return nil
+	}
+
+	// A watch-only wallet must derive the same addresses as the remote
+	// signer, so it can only be created from the signer's seed.
+	if walletConfig.WatchOnly && !cfg.Btcwallet.Restore {
+		return fmt.Errorf("a wallet used with a remote signer must be " +
+			"restored from the signer's seed")
+	}
+
+	seedPass := []byte(cfg.Btcwallet.SeedPass)
+
+	var seed *cipherseed.CipherSeed
+	if cfg.Btcwallet.Restore {
+		seed, err = readMnemonicSeed(os.Stdin, seedPass)
+		if err != nil {
+			return err
+		}
+
+		ltndLog.Infof("Restoring wallet from seed created on %v",
+			seed.BirthdayTime().Format("2006-01-02"))
+
+		walletConfig.RecoveryWindow = cfg.Btcwallet.RecoveryWindow
+	} else {
+		seed, err = cipherseed.New(cipherseed.InternalVersion, nil,
+			time.Now())
+		if err != nil {
+			return err
+		}
+
+		mnemonic, err := seed.ToMnemonic(seedPass)
+		if err != nil {
+			return err
+		}
+		printMnemonic(mnemonic)
+	}
+
+	walletConfig.HdSeed = seed.Entropy[:]
+	walletConfig.Birthday = seed.BirthdayTime()
+
+	return nil
+}
+
+// readMnemonicSeed prompts the user for the mnemonic of their seed, then
+// reads it from the passed io.Reader and deciphers it using the passed
+// passphrase.
+func readMnemonicSeed(r io.Reader,
+	seedPass []byte) (*cipherseed.CipherSeed, error) {
+
+	fmt.Printf("Input your %v-word mnemonic separated by spaces: ",
+		cipherseed.NumMnemonicWords)
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	mnemonic, err := cipherseed.ParseMnemonic(line)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, err := mnemonic.ToCipherSeed(seedPass)
+	if err != nil {
+		return nil, fmt.Errorf("unable to restore seed: %v", err)
+	}
+
+	return seed, nil
+}
+
+// printMnemonic displays the mnemonic of a newly created seed to the user.
+func printMnemonic(mnemonic cipherseed.Mnemonic) {
+	fmt.Println("!!!YOU MUST WRITE DOWN THIS SEED TO BE ABLE TO RESTORE " +
+		"THE WALLET!!!")
+	fmt.Println()
+	fmt.Println("---------------BEGIN LND CIPHER SEED---------------")
+
+	const wordsPerLine = 4
+	for i := 0; i < len(mnemonic); i += wordsPerLine {
+		for j := i; j < i+wordsPerLine; j++ {
+			fmt.Printf("%2d. %-10s", j+1, mnemonic[j])
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("---------------END LND CIPHER SEED-----------------")
+	fmt.Println()
+	fmt.Println("!!!YOU MUST WRITE DOWN THIS SEED TO BE ABLE TO RESTORE " +
+		"THE WALLET!!!")
+}
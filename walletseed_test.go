@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/cipherseed"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/roasbeef/btcd/chaincfg"
+)
+
+var (
+	testSeedEntropy = [cipherseed.EntropySize]byte{
+		0x81, 0xb6, 0x37, 0xd8, 0x63, 0x59, 0xe6, 0x96,
+		0x0d, 0xe7, 0x95, 0xe4, 0x1e, 0x0b, 0x4c, 0xfd,
+	}
+
+	testSeedPass = []byte("test")
+)
+
+// testMnemonic returns a new seed with the test entropy, along with its
+// mnemonic as entered by the user.
+func testMnemonic(t *testing.T) (*cipherseed.CipherSeed, string) {
+	seed, err := cipherseed.New(cipherseed.InternalVersion,
+		&testSeedEntropy, time.Now())
+	if err != nil {
+		t.Fatalf("unable to create seed: %v", err)
+	}
+	mnemonic, err := seed.ToMnemonic(testSeedPass)
+	if err != nil {
+		t.Fatalf("unable to create mnemonic: %v", err)
+	}
+
+	return seed, strings.Join(mnemonic[:], " ") + "\n"
+}
+
+// TestReadMnemonicSeed tests that a seed is restored from its mnemonic only
+// if it's entered in full with the correct passphrase.
+func TestReadMnemonicSeed(t *testing.T) {
+	seed, mnemonic := testMnemonic(t)
+
+	restored, err := readMnemonicSeed(strings.NewReader(mnemonic),
+		testSeedPass)
+	if err != nil {
+		t.Fatalf("unable to read mnemonic: %v", err)
+	}
+	if restored.Entropy != seed.Entropy {
+		t.Fatalf("expected entropy %x, got %x", seed.Entropy,
+			restored.Entropy)
+	}
+	if restored.Birthday != seed.Birthday {
+		t.Fatalf("expected birthday %v, got %v", seed.Birthday,
+			restored.Birthday)
+	}
+
+	// Without a trailing newline, the mnemonic should still be read.
+	_, err = readMnemonicSeed(
+		strings.NewReader(strings.TrimSpace(mnemonic)), testSeedPass,
+	)
+	if err != nil {
+		t.Fatalf("unable to read mnemonic without newline: %v", err)
+	}
+
+	_, err = readMnemonicSeed(strings.NewReader(mnemonic), []byte("wrong"))
+	if err == nil {
+		t.Fatalf("expected mnemonic with wrong passphrase to be rejected")
+	}
+
+	words := strings.Fields(mnemonic)
+	truncated := strings.Join(words[:len(words)-1], " ") + "\n"
+	_, err = readMnemonicSeed(strings.NewReader(truncated), testSeedPass)
+	if err == nil {
+		t.Fatalf("expected truncated mnemonic to be rejected")
+	}
+}
+
+// newTestWalletSeedConfigs returns the configs used to initialize the seed of
+// a wallet which doesn't yet exist.
+func newTestWalletSeedConfigs(t *testing.T, dataDir string,
+	restore bool) (*config, *btcwallet.Config) {
+
+	cfg := &config{
+		Btcwallet: &btcwalletConfig{
+			SeedPass:       string(testSeedPass),
+			Restore:        restore,
+			RecoveryWindow: defaultRecoveryWindow,
+		},
+	}
+	walletConfig := &btcwallet.Config{
+		DataDir:   dataDir,
+		NetParams: &chaincfg.RegressionNetParams,
+	}
+
+	return cfg, walletConfig
+}
+
+// TestInitWalletSeedCreate tests that a fresh seed is generated for a new
+// wallet, which isn't scanned for funds.
+func TestInitWalletSeedCreate(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "walletseed")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	cfg, walletConfig := newTestWalletSeedConfigs(t, dataDir, false)
+	if err := initWalletSeed(cfg, walletConfig); err != nil {
+		t.Fatalf("unable to init wallet seed: %v", err)
+	}
+
+	if len(walletConfig.HdSeed) != cipherseed.EntropySize {
+		t.Fatalf("expected seed of %v bytes, got %v",
+			cipherseed.EntropySize, len(walletConfig.HdSeed))
+	}
+	if bytes.Equal(walletConfig.HdSeed, make([]byte, cipherseed.EntropySize)) {
+		t.Fatalf("seed generated without entropy")
+	}
+	if walletConfig.RecoveryWindow != 0 {
+		t.Fatalf("new wallet shouldn't be recovered")
+	}
+	if time.Since(walletConfig.Birthday) > 24*time.Hour {
+		t.Fatalf("expected birthday of today, got %v",
+			walletConfig.Birthday)
+	}
+}
+
+// TestInitWalletSeedRestore tests that the seed of a restored wallet is read
+// from stdin, and that the wallet is scanned for its funds from the seed's
+// birthday.
+func TestInitWalletSeedRestore(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "walletseed")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	seed, mnemonic := testMnemonic(t)
+
+	// The mnemonic is read from stdin, so we'll replace it with a pipe
+	// the mnemonic is written to.
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unable to create pipe: %v", err)
+	}
+	defer stdinReader.Close()
+	if _, err := stdinWriter.WriteString(mnemonic); err != nil {
+		t.Fatalf("unable to write mnemonic: %v", err)
+	}
+	stdinWriter.Close()
+
+	stdin := os.Stdin
+	os.Stdin = stdinReader
+	defer func() {
+		os.Stdin = stdin
+	}()
+
+	cfg, walletConfig := newTestWalletSeedConfigs(t, dataDir, true)
+	if err := initWalletSeed(cfg, walletConfig); err != nil {
+		t.Fatalf("unable to init wallet seed: %v", err)
+	}
+
+	if !bytes.Equal(walletConfig.HdSeed, seed.Entropy[:]) {
+		t.Fatalf("expected seed %x, got %x", seed.Entropy[:],
+			walletConfig.HdSeed)
+	}
+	if walletConfig.RecoveryWindow != defaultRecoveryWindow {
+		t.Fatalf("expected recovery window of %v, got %v",
+			defaultRecoveryWindow, walletConfig.RecoveryWindow)
+	}
+	if !walletConfig.Birthday.Equal(seed.BirthdayTime()) {
+		t.Fatalf("expected birthday of %v, got %v",
+			seed.BirthdayTime(), walletConfig.Birthday)
+	}
+}